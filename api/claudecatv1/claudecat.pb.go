@@ -0,0 +1,1314 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.29.3
+// source: claudecat/v1/claudecat.proto
+
+// Package claudecat.v1 exposes claudecat's usage data over gRPC, so other tools can use
+// a running claudecat as their usage data backend.
+
+package claudecatv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// UsageEntry is one assistant message as recorded in the Claude usage logs.
+type UsageEntry struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp           *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Model               string                 `protobuf:"bytes,2,opt,name=model,proto3" json:"model,omitempty"`
+	InputTokens         int64                  `protobuf:"varint,3,opt,name=input_tokens,json=inputTokens,proto3" json:"input_tokens,omitempty"`
+	OutputTokens        int64                  `protobuf:"varint,4,opt,name=output_tokens,json=outputTokens,proto3" json:"output_tokens,omitempty"`
+	CacheCreationTokens int64                  `protobuf:"varint,5,opt,name=cache_creation_tokens,json=cacheCreationTokens,proto3" json:"cache_creation_tokens,omitempty"`
+	CacheReadTokens     int64                  `protobuf:"varint,6,opt,name=cache_read_tokens,json=cacheReadTokens,proto3" json:"cache_read_tokens,omitempty"`
+	ThinkingTokens      int64                  `protobuf:"varint,7,opt,name=thinking_tokens,json=thinkingTokens,proto3" json:"thinking_tokens,omitempty"`
+	TotalTokens         int64                  `protobuf:"varint,8,opt,name=total_tokens,json=totalTokens,proto3" json:"total_tokens,omitempty"`
+	CostUsd             float64                `protobuf:"fixed64,9,opt,name=cost_usd,json=costUsd,proto3" json:"cost_usd,omitempty"`
+	CostSource          string                 `protobuf:"bytes,10,opt,name=cost_source,json=costSource,proto3" json:"cost_source,omitempty"`
+	CacheSavingsUsd     float64                `protobuf:"fixed64,11,opt,name=cache_savings_usd,json=cacheSavingsUsd,proto3" json:"cache_savings_usd,omitempty"`
+	MessageId           string                 `protobuf:"bytes,12,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	RequestId           string                 `protobuf:"bytes,13,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	SessionId           string                 `protobuf:"bytes,14,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	Project             string                 `protobuf:"bytes,15,opt,name=project,proto3" json:"project,omitempty"`
+	Anomalies           []string               `protobuf:"bytes,16,rep,name=anomalies,proto3" json:"anomalies,omitempty"`
+	Suspect             bool                   `protobuf:"varint,17,opt,name=suspect,proto3" json:"suspect,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *UsageEntry) Reset() {
+	*x = UsageEntry{}
+	mi := &file_claudecat_v1_claudecat_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UsageEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UsageEntry) ProtoMessage() {}
+
+func (x *UsageEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_claudecat_v1_claudecat_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UsageEntry.ProtoReflect.Descriptor instead.
+func (*UsageEntry) Descriptor() ([]byte, []int) {
+	return file_claudecat_v1_claudecat_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *UsageEntry) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *UsageEntry) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *UsageEntry) GetInputTokens() int64 {
+	if x != nil {
+		return x.InputTokens
+	}
+	return 0
+}
+
+func (x *UsageEntry) GetOutputTokens() int64 {
+	if x != nil {
+		return x.OutputTokens
+	}
+	return 0
+}
+
+func (x *UsageEntry) GetCacheCreationTokens() int64 {
+	if x != nil {
+		return x.CacheCreationTokens
+	}
+	return 0
+}
+
+func (x *UsageEntry) GetCacheReadTokens() int64 {
+	if x != nil {
+		return x.CacheReadTokens
+	}
+	return 0
+}
+
+func (x *UsageEntry) GetThinkingTokens() int64 {
+	if x != nil {
+		return x.ThinkingTokens
+	}
+	return 0
+}
+
+func (x *UsageEntry) GetTotalTokens() int64 {
+	if x != nil {
+		return x.TotalTokens
+	}
+	return 0
+}
+
+func (x *UsageEntry) GetCostUsd() float64 {
+	if x != nil {
+		return x.CostUsd
+	}
+	return 0
+}
+
+func (x *UsageEntry) GetCostSource() string {
+	if x != nil {
+		return x.CostSource
+	}
+	return ""
+}
+
+func (x *UsageEntry) GetCacheSavingsUsd() float64 {
+	if x != nil {
+		return x.CacheSavingsUsd
+	}
+	return 0
+}
+
+func (x *UsageEntry) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *UsageEntry) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *UsageEntry) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *UsageEntry) GetProject() string {
+	if x != nil {
+		return x.Project
+	}
+	return ""
+}
+
+func (x *UsageEntry) GetAnomalies() []string {
+	if x != nil {
+		return x.Anomalies
+	}
+	return nil
+}
+
+func (x *UsageEntry) GetSuspect() bool {
+	if x != nil {
+		return x.Suspect
+	}
+	return false
+}
+
+// TokenCounts aggregates the token counts of several entries.
+type TokenCounts struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	InputTokens         int64                  `protobuf:"varint,1,opt,name=input_tokens,json=inputTokens,proto3" json:"input_tokens,omitempty"`
+	OutputTokens        int64                  `protobuf:"varint,2,opt,name=output_tokens,json=outputTokens,proto3" json:"output_tokens,omitempty"`
+	CacheCreationTokens int64                  `protobuf:"varint,3,opt,name=cache_creation_tokens,json=cacheCreationTokens,proto3" json:"cache_creation_tokens,omitempty"`
+	CacheReadTokens     int64                  `protobuf:"varint,4,opt,name=cache_read_tokens,json=cacheReadTokens,proto3" json:"cache_read_tokens,omitempty"`
+	ThinkingTokens      int64                  `protobuf:"varint,5,opt,name=thinking_tokens,json=thinkingTokens,proto3" json:"thinking_tokens,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *TokenCounts) Reset() {
+	*x = TokenCounts{}
+	mi := &file_claudecat_v1_claudecat_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TokenCounts) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TokenCounts) ProtoMessage() {}
+
+func (x *TokenCounts) ProtoReflect() protoreflect.Message {
+	mi := &file_claudecat_v1_claudecat_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TokenCounts.ProtoReflect.Descriptor instead.
+func (*TokenCounts) Descriptor() ([]byte, []int) {
+	return file_claudecat_v1_claudecat_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *TokenCounts) GetInputTokens() int64 {
+	if x != nil {
+		return x.InputTokens
+	}
+	return 0
+}
+
+func (x *TokenCounts) GetOutputTokens() int64 {
+	if x != nil {
+		return x.OutputTokens
+	}
+	return 0
+}
+
+func (x *TokenCounts) GetCacheCreationTokens() int64 {
+	if x != nil {
+		return x.CacheCreationTokens
+	}
+	return 0
+}
+
+func (x *TokenCounts) GetCacheReadTokens() int64 {
+	if x != nil {
+		return x.CacheReadTokens
+	}
+	return 0
+}
+
+func (x *TokenCounts) GetThinkingTokens() int64 {
+	if x != nil {
+		return x.ThinkingTokens
+	}
+	return 0
+}
+
+// BurnRate is the rate tokens and cost are used at.
+type BurnRate struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	TokensPerMinute float64                `protobuf:"fixed64,1,opt,name=tokens_per_minute,json=tokensPerMinute,proto3" json:"tokens_per_minute,omitempty"`
+	CostPerHour     float64                `protobuf:"fixed64,2,opt,name=cost_per_hour,json=costPerHour,proto3" json:"cost_per_hour,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *BurnRate) Reset() {
+	*x = BurnRate{}
+	mi := &file_claudecat_v1_claudecat_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BurnRate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BurnRate) ProtoMessage() {}
+
+func (x *BurnRate) ProtoReflect() protoreflect.Message {
+	mi := &file_claudecat_v1_claudecat_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BurnRate.ProtoReflect.Descriptor instead.
+func (*BurnRate) Descriptor() ([]byte, []int) {
+	return file_claudecat_v1_claudecat_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *BurnRate) GetTokensPerMinute() float64 {
+	if x != nil {
+		return x.TokensPerMinute
+	}
+	return 0
+}
+
+func (x *BurnRate) GetCostPerHour() float64 {
+	if x != nil {
+		return x.CostPerHour
+	}
+	return 0
+}
+
+// UsageProjection projects the usage at the end of a session block.
+type UsageProjection struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	ProjectedTotalTokens int64                  `protobuf:"varint,1,opt,name=projected_total_tokens,json=projectedTotalTokens,proto3" json:"projected_total_tokens,omitempty"`
+	ProjectedTotalCost   float64                `protobuf:"fixed64,2,opt,name=projected_total_cost,json=projectedTotalCost,proto3" json:"projected_total_cost,omitempty"`
+	RemainingMinutes     float64                `protobuf:"fixed64,3,opt,name=remaining_minutes,json=remainingMinutes,proto3" json:"remaining_minutes,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *UsageProjection) Reset() {
+	*x = UsageProjection{}
+	mi := &file_claudecat_v1_claudecat_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UsageProjection) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UsageProjection) ProtoMessage() {}
+
+func (x *UsageProjection) ProtoReflect() protoreflect.Message {
+	mi := &file_claudecat_v1_claudecat_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UsageProjection.ProtoReflect.Descriptor instead.
+func (*UsageProjection) Descriptor() ([]byte, []int) {
+	return file_claudecat_v1_claudecat_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *UsageProjection) GetProjectedTotalTokens() int64 {
+	if x != nil {
+		return x.ProjectedTotalTokens
+	}
+	return 0
+}
+
+func (x *UsageProjection) GetProjectedTotalCost() float64 {
+	if x != nil {
+		return x.ProjectedTotalCost
+	}
+	return 0
+}
+
+func (x *UsageProjection) GetRemainingMinutes() float64 {
+	if x != nil {
+		return x.RemainingMinutes
+	}
+	return 0
+}
+
+// SessionBlock is a session window of usage, or a gap between two sessions.
+type SessionBlock struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	StartTime *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime   *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	// Unset while the block is active.
+	ActualEndTime     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=actual_end_time,json=actualEndTime,proto3" json:"actual_end_time,omitempty"`
+	IsActive          bool                   `protobuf:"varint,5,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
+	IsGap             bool                   `protobuf:"varint,6,opt,name=is_gap,json=isGap,proto3" json:"is_gap,omitempty"`
+	TokenCounts       *TokenCounts           `protobuf:"bytes,7,opt,name=token_counts,json=tokenCounts,proto3" json:"token_counts,omitempty"`
+	CostUsd           float64                `protobuf:"fixed64,8,opt,name=cost_usd,json=costUsd,proto3" json:"cost_usd,omitempty"`
+	Models            []string               `protobuf:"bytes,9,rep,name=models,proto3" json:"models,omitempty"`
+	SentMessagesCount int64                  `protobuf:"varint,10,opt,name=sent_messages_count,json=sentMessagesCount,proto3" json:"sent_messages_count,omitempty"`
+	BurnRate          *BurnRate              `protobuf:"bytes,11,opt,name=burn_rate,json=burnRate,proto3" json:"burn_rate,omitempty"`
+	Projection        *UsageProjection       `protobuf:"bytes,12,opt,name=projection,proto3" json:"projection,omitempty"`
+	// Only filled when requested.
+	Entries       []*UsageEntry `protobuf:"bytes,13,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SessionBlock) Reset() {
+	*x = SessionBlock{}
+	mi := &file_claudecat_v1_claudecat_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionBlock) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionBlock) ProtoMessage() {}
+
+func (x *SessionBlock) ProtoReflect() protoreflect.Message {
+	mi := &file_claudecat_v1_claudecat_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionBlock.ProtoReflect.Descriptor instead.
+func (*SessionBlock) Descriptor() ([]byte, []int) {
+	return file_claudecat_v1_claudecat_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SessionBlock) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *SessionBlock) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *SessionBlock) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+func (x *SessionBlock) GetActualEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ActualEndTime
+	}
+	return nil
+}
+
+func (x *SessionBlock) GetIsActive() bool {
+	if x != nil {
+		return x.IsActive
+	}
+	return false
+}
+
+func (x *SessionBlock) GetIsGap() bool {
+	if x != nil {
+		return x.IsGap
+	}
+	return false
+}
+
+func (x *SessionBlock) GetTokenCounts() *TokenCounts {
+	if x != nil {
+		return x.TokenCounts
+	}
+	return nil
+}
+
+func (x *SessionBlock) GetCostUsd() float64 {
+	if x != nil {
+		return x.CostUsd
+	}
+	return 0
+}
+
+func (x *SessionBlock) GetModels() []string {
+	if x != nil {
+		return x.Models
+	}
+	return nil
+}
+
+func (x *SessionBlock) GetSentMessagesCount() int64 {
+	if x != nil {
+		return x.SentMessagesCount
+	}
+	return 0
+}
+
+func (x *SessionBlock) GetBurnRate() *BurnRate {
+	if x != nil {
+		return x.BurnRate
+	}
+	return nil
+}
+
+func (x *SessionBlock) GetProjection() *UsageProjection {
+	if x != nil {
+		return x.Projection
+	}
+	return nil
+}
+
+func (x *SessionBlock) GetEntries() []*UsageEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+// ModelMetrics is the usage of one model in the current session.
+type ModelMetrics struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TokenCounts   *TokenCounts           `protobuf:"bytes,1,opt,name=token_counts,json=tokenCounts,proto3" json:"token_counts,omitempty"`
+	TotalTokens   int64                  `protobuf:"varint,2,opt,name=total_tokens,json=totalTokens,proto3" json:"total_tokens,omitempty"`
+	Cost          float64                `protobuf:"fixed64,3,opt,name=cost,proto3" json:"cost,omitempty"`
+	Percentage    float64                `protobuf:"fixed64,4,opt,name=percentage,proto3" json:"percentage,omitempty"`
+	LastUsed      *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=last_used,json=lastUsed,proto3" json:"last_used,omitempty"`
+	EntryCount    int64                  `protobuf:"varint,6,opt,name=entry_count,json=entryCount,proto3" json:"entry_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ModelMetrics) Reset() {
+	*x = ModelMetrics{}
+	mi := &file_claudecat_v1_claudecat_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ModelMetrics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ModelMetrics) ProtoMessage() {}
+
+func (x *ModelMetrics) ProtoReflect() protoreflect.Message {
+	mi := &file_claudecat_v1_claudecat_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ModelMetrics.ProtoReflect.Descriptor instead.
+func (*ModelMetrics) Descriptor() ([]byte, []int) {
+	return file_claudecat_v1_claudecat_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ModelMetrics) GetTokenCounts() *TokenCounts {
+	if x != nil {
+		return x.TokenCounts
+	}
+	return nil
+}
+
+func (x *ModelMetrics) GetTotalTokens() int64 {
+	if x != nil {
+		return x.TotalTokens
+	}
+	return 0
+}
+
+func (x *ModelMetrics) GetCost() float64 {
+	if x != nil {
+		return x.Cost
+	}
+	return 0
+}
+
+func (x *ModelMetrics) GetPercentage() float64 {
+	if x != nil {
+		return x.Percentage
+	}
+	return 0
+}
+
+func (x *ModelMetrics) GetLastUsed() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastUsed
+	}
+	return nil
+}
+
+func (x *ModelMetrics) GetEntryCount() int64 {
+	if x != nil {
+		return x.EntryCount
+	}
+	return 0
+}
+
+// RealtimeMetrics are the live metrics of the current session.
+type RealtimeMetrics struct {
+	state             protoimpl.MessageState   `protogen:"open.v1"`
+	SessionStart      *timestamppb.Timestamp   `protobuf:"bytes,1,opt,name=session_start,json=sessionStart,proto3" json:"session_start,omitempty"`
+	SessionEnd        *timestamppb.Timestamp   `protobuf:"bytes,2,opt,name=session_end,json=sessionEnd,proto3" json:"session_end,omitempty"`
+	SessionProgress   float64                  `protobuf:"fixed64,3,opt,name=session_progress,json=sessionProgress,proto3" json:"session_progress,omitempty"`
+	TimeRemaining     *durationpb.Duration     `protobuf:"bytes,4,opt,name=time_remaining,json=timeRemaining,proto3" json:"time_remaining,omitempty"`
+	IsActive          bool                     `protobuf:"varint,5,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
+	CurrentTokens     int64                    `protobuf:"varint,6,opt,name=current_tokens,json=currentTokens,proto3" json:"current_tokens,omitempty"`
+	CurrentCost       float64                  `protobuf:"fixed64,7,opt,name=current_cost,json=currentCost,proto3" json:"current_cost,omitempty"`
+	BurnRate          *BurnRate                `protobuf:"bytes,8,opt,name=burn_rate,json=burnRate,proto3" json:"burn_rate,omitempty"`
+	Projection        *UsageProjection         `protobuf:"bytes,9,opt,name=projection,proto3" json:"projection,omitempty"`
+	ModelDistribution map[string]*ModelMetrics `protobuf:"bytes,10,rep,name=model_distribution,json=modelDistribution,proto3" json:"model_distribution,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	TokensPerMinute   float64                  `protobuf:"fixed64,11,opt,name=tokens_per_minute,json=tokensPerMinute,proto3" json:"tokens_per_minute,omitempty"`
+	TokensPerHour     float64                  `protobuf:"fixed64,12,opt,name=tokens_per_hour,json=tokensPerHour,proto3" json:"tokens_per_hour,omitempty"`
+	CostPerMinute     float64                  `protobuf:"fixed64,13,opt,name=cost_per_minute,json=costPerMinute,proto3" json:"cost_per_minute,omitempty"`
+	CostPerHour       float64                  `protobuf:"fixed64,14,opt,name=cost_per_hour,json=costPerHour,proto3" json:"cost_per_hour,omitempty"`
+	ConfidenceLevel   float64                  `protobuf:"fixed64,15,opt,name=confidence_level,json=confidenceLevel,proto3" json:"confidence_level,omitempty"`
+	HealthStatus      string                   `protobuf:"bytes,16,opt,name=health_status,json=healthStatus,proto3" json:"health_status,omitempty"`
+	LastUpdated       *timestamppb.Timestamp   `protobuf:"bytes,17,opt,name=last_updated,json=lastUpdated,proto3" json:"last_updated,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *RealtimeMetrics) Reset() {
+	*x = RealtimeMetrics{}
+	mi := &file_claudecat_v1_claudecat_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RealtimeMetrics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RealtimeMetrics) ProtoMessage() {}
+
+func (x *RealtimeMetrics) ProtoReflect() protoreflect.Message {
+	mi := &file_claudecat_v1_claudecat_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RealtimeMetrics.ProtoReflect.Descriptor instead.
+func (*RealtimeMetrics) Descriptor() ([]byte, []int) {
+	return file_claudecat_v1_claudecat_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *RealtimeMetrics) GetSessionStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SessionStart
+	}
+	return nil
+}
+
+func (x *RealtimeMetrics) GetSessionEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SessionEnd
+	}
+	return nil
+}
+
+func (x *RealtimeMetrics) GetSessionProgress() float64 {
+	if x != nil {
+		return x.SessionProgress
+	}
+	return 0
+}
+
+func (x *RealtimeMetrics) GetTimeRemaining() *durationpb.Duration {
+	if x != nil {
+		return x.TimeRemaining
+	}
+	return nil
+}
+
+func (x *RealtimeMetrics) GetIsActive() bool {
+	if x != nil {
+		return x.IsActive
+	}
+	return false
+}
+
+func (x *RealtimeMetrics) GetCurrentTokens() int64 {
+	if x != nil {
+		return x.CurrentTokens
+	}
+	return 0
+}
+
+func (x *RealtimeMetrics) GetCurrentCost() float64 {
+	if x != nil {
+		return x.CurrentCost
+	}
+	return 0
+}
+
+func (x *RealtimeMetrics) GetBurnRate() *BurnRate {
+	if x != nil {
+		return x.BurnRate
+	}
+	return nil
+}
+
+func (x *RealtimeMetrics) GetProjection() *UsageProjection {
+	if x != nil {
+		return x.Projection
+	}
+	return nil
+}
+
+func (x *RealtimeMetrics) GetModelDistribution() map[string]*ModelMetrics {
+	if x != nil {
+		return x.ModelDistribution
+	}
+	return nil
+}
+
+func (x *RealtimeMetrics) GetTokensPerMinute() float64 {
+	if x != nil {
+		return x.TokensPerMinute
+	}
+	return 0
+}
+
+func (x *RealtimeMetrics) GetTokensPerHour() float64 {
+	if x != nil {
+		return x.TokensPerHour
+	}
+	return 0
+}
+
+func (x *RealtimeMetrics) GetCostPerMinute() float64 {
+	if x != nil {
+		return x.CostPerMinute
+	}
+	return 0
+}
+
+func (x *RealtimeMetrics) GetCostPerHour() float64 {
+	if x != nil {
+		return x.CostPerHour
+	}
+	return 0
+}
+
+func (x *RealtimeMetrics) GetConfidenceLevel() float64 {
+	if x != nil {
+		return x.ConfidenceLevel
+	}
+	return 0
+}
+
+func (x *RealtimeMetrics) GetHealthStatus() string {
+	if x != nil {
+		return x.HealthStatus
+	}
+	return ""
+}
+
+func (x *RealtimeMetrics) GetLastUpdated() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastUpdated
+	}
+	return nil
+}
+
+type GetMetricsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMetricsRequest) Reset() {
+	*x = GetMetricsRequest{}
+	mi := &file_claudecat_v1_claudecat_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMetricsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMetricsRequest) ProtoMessage() {}
+
+func (x *GetMetricsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_claudecat_v1_claudecat_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMetricsRequest.ProtoReflect.Descriptor instead.
+func (*GetMetricsRequest) Descriptor() ([]byte, []int) {
+	return file_claudecat_v1_claudecat_proto_rawDescGZIP(), []int{7}
+}
+
+type GetMetricsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Metrics       *RealtimeMetrics       `protobuf:"bytes,1,opt,name=metrics,proto3" json:"metrics,omitempty"`
+	TokenLimit    int64                  `protobuf:"varint,2,opt,name=token_limit,json=tokenLimit,proto3" json:"token_limit,omitempty"`
+	SessionId     string                 `protobuf:"bytes,3,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	SessionCount  int64                  `protobuf:"varint,4,opt,name=session_count,json=sessionCount,proto3" json:"session_count,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMetricsResponse) Reset() {
+	*x = GetMetricsResponse{}
+	mi := &file_claudecat_v1_claudecat_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMetricsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMetricsResponse) ProtoMessage() {}
+
+func (x *GetMetricsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_claudecat_v1_claudecat_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMetricsResponse.ProtoReflect.Descriptor instead.
+func (*GetMetricsResponse) Descriptor() ([]byte, []int) {
+	return file_claudecat_v1_claudecat_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetMetricsResponse) GetMetrics() *RealtimeMetrics {
+	if x != nil {
+		return x.Metrics
+	}
+	return nil
+}
+
+func (x *GetMetricsResponse) GetTokenLimit() int64 {
+	if x != nil {
+		return x.TokenLimit
+	}
+	return 0
+}
+
+func (x *GetMetricsResponse) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *GetMetricsResponse) GetSessionCount() int64 {
+	if x != nil {
+		return x.SessionCount
+	}
+	return 0
+}
+
+func (x *GetMetricsResponse) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type ListBlocksRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Only the most recent blocks; 0 returns all.
+	Limit          int32 `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	IncludeGaps    bool  `protobuf:"varint,2,opt,name=include_gaps,json=includeGaps,proto3" json:"include_gaps,omitempty"`
+	IncludeEntries bool  `protobuf:"varint,3,opt,name=include_entries,json=includeEntries,proto3" json:"include_entries,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ListBlocksRequest) Reset() {
+	*x = ListBlocksRequest{}
+	mi := &file_claudecat_v1_claudecat_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBlocksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBlocksRequest) ProtoMessage() {}
+
+func (x *ListBlocksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_claudecat_v1_claudecat_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBlocksRequest.ProtoReflect.Descriptor instead.
+func (*ListBlocksRequest) Descriptor() ([]byte, []int) {
+	return file_claudecat_v1_claudecat_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListBlocksRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListBlocksRequest) GetIncludeGaps() bool {
+	if x != nil {
+		return x.IncludeGaps
+	}
+	return false
+}
+
+func (x *ListBlocksRequest) GetIncludeEntries() bool {
+	if x != nil {
+		return x.IncludeEntries
+	}
+	return false
+}
+
+type ListBlocksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Blocks        []*SessionBlock        `protobuf:"bytes,1,rep,name=blocks,proto3" json:"blocks,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBlocksResponse) Reset() {
+	*x = ListBlocksResponse{}
+	mi := &file_claudecat_v1_claudecat_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBlocksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBlocksResponse) ProtoMessage() {}
+
+func (x *ListBlocksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_claudecat_v1_claudecat_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBlocksResponse.ProtoReflect.Descriptor instead.
+func (*ListBlocksResponse) Descriptor() ([]byte, []int) {
+	return file_claudecat_v1_claudecat_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListBlocksResponse) GetBlocks() []*SessionBlock {
+	if x != nil {
+		return x.Blocks
+	}
+	return nil
+}
+
+func (x *ListBlocksResponse) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type GetActiveSessionRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	IncludeEntries bool                   `protobuf:"varint,1,opt,name=include_entries,json=includeEntries,proto3" json:"include_entries,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetActiveSessionRequest) Reset() {
+	*x = GetActiveSessionRequest{}
+	mi := &file_claudecat_v1_claudecat_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetActiveSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetActiveSessionRequest) ProtoMessage() {}
+
+func (x *GetActiveSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_claudecat_v1_claudecat_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetActiveSessionRequest.ProtoReflect.Descriptor instead.
+func (*GetActiveSessionRequest) Descriptor() ([]byte, []int) {
+	return file_claudecat_v1_claudecat_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetActiveSessionRequest) GetIncludeEntries() bool {
+	if x != nil {
+		return x.IncludeEntries
+	}
+	return false
+}
+
+type WatchMetricsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchMetricsRequest) Reset() {
+	*x = WatchMetricsRequest{}
+	mi := &file_claudecat_v1_claudecat_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchMetricsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchMetricsRequest) ProtoMessage() {}
+
+func (x *WatchMetricsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_claudecat_v1_claudecat_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchMetricsRequest.ProtoReflect.Descriptor instead.
+func (*WatchMetricsRequest) Descriptor() ([]byte, []int) {
+	return file_claudecat_v1_claudecat_proto_rawDescGZIP(), []int{12}
+}
+
+var File_claudecat_v1_claudecat_proto protoreflect.FileDescriptor
+
+const file_claudecat_v1_claudecat_proto_rawDesc = "" +
+	"\n" +
+	"\x1cclaudecat/v1/claudecat.proto\x12\fclaudecat.v1\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xe7\x04\n" +
+	"\n" +
+	"UsageEntry\x128\n" +
+	"\ttimestamp\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x14\n" +
+	"\x05model\x18\x02 \x01(\tR\x05model\x12!\n" +
+	"\finput_tokens\x18\x03 \x01(\x03R\vinputTokens\x12#\n" +
+	"\routput_tokens\x18\x04 \x01(\x03R\foutputTokens\x122\n" +
+	"\x15cache_creation_tokens\x18\x05 \x01(\x03R\x13cacheCreationTokens\x12*\n" +
+	"\x11cache_read_tokens\x18\x06 \x01(\x03R\x0fcacheReadTokens\x12'\n" +
+	"\x0fthinking_tokens\x18\a \x01(\x03R\x0ethinkingTokens\x12!\n" +
+	"\ftotal_tokens\x18\b \x01(\x03R\vtotalTokens\x12\x19\n" +
+	"\bcost_usd\x18\t \x01(\x01R\acostUsd\x12\x1f\n" +
+	"\vcost_source\x18\n" +
+	" \x01(\tR\n" +
+	"costSource\x12*\n" +
+	"\x11cache_savings_usd\x18\v \x01(\x01R\x0fcacheSavingsUsd\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\f \x01(\tR\tmessageId\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\r \x01(\tR\trequestId\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x0e \x01(\tR\tsessionId\x12\x18\n" +
+	"\aproject\x18\x0f \x01(\tR\aproject\x12\x1c\n" +
+	"\tanomalies\x18\x10 \x03(\tR\tanomalies\x12\x18\n" +
+	"\asuspect\x18\x11 \x01(\bR\asuspect\"\xde\x01\n" +
+	"\vTokenCounts\x12!\n" +
+	"\finput_tokens\x18\x01 \x01(\x03R\vinputTokens\x12#\n" +
+	"\routput_tokens\x18\x02 \x01(\x03R\foutputTokens\x122\n" +
+	"\x15cache_creation_tokens\x18\x03 \x01(\x03R\x13cacheCreationTokens\x12*\n" +
+	"\x11cache_read_tokens\x18\x04 \x01(\x03R\x0fcacheReadTokens\x12'\n" +
+	"\x0fthinking_tokens\x18\x05 \x01(\x03R\x0ethinkingTokens\"Z\n" +
+	"\bBurnRate\x12*\n" +
+	"\x11tokens_per_minute\x18\x01 \x01(\x01R\x0ftokensPerMinute\x12\"\n" +
+	"\rcost_per_hour\x18\x02 \x01(\x01R\vcostPerHour\"\xa6\x01\n" +
+	"\x0fUsageProjection\x124\n" +
+	"\x16projected_total_tokens\x18\x01 \x01(\x03R\x14projectedTotalTokens\x120\n" +
+	"\x14projected_total_cost\x18\x02 \x01(\x01R\x12projectedTotalCost\x12+\n" +
+	"\x11remaining_minutes\x18\x03 \x01(\x01R\x10remainingMinutes\"\xd1\x04\n" +
+	"\fSessionBlock\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x129\n" +
+	"\n" +
+	"start_time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tstartTime\x125\n" +
+	"\bend_time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\aendTime\x12B\n" +
+	"\x0factual_end_time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\ractualEndTime\x12\x1b\n" +
+	"\tis_active\x18\x05 \x01(\bR\bisActive\x12\x15\n" +
+	"\x06is_gap\x18\x06 \x01(\bR\x05isGap\x12<\n" +
+	"\ftoken_counts\x18\a \x01(\v2\x19.claudecat.v1.TokenCountsR\vtokenCounts\x12\x19\n" +
+	"\bcost_usd\x18\b \x01(\x01R\acostUsd\x12\x16\n" +
+	"\x06models\x18\t \x03(\tR\x06models\x12.\n" +
+	"\x13sent_messages_count\x18\n" +
+	" \x01(\x03R\x11sentMessagesCount\x123\n" +
+	"\tburn_rate\x18\v \x01(\v2\x16.claudecat.v1.BurnRateR\bburnRate\x12=\n" +
+	"\n" +
+	"projection\x18\f \x01(\v2\x1d.claudecat.v1.UsageProjectionR\n" +
+	"projection\x122\n" +
+	"\aentries\x18\r \x03(\v2\x18.claudecat.v1.UsageEntryR\aentries\"\xfd\x01\n" +
+	"\fModelMetrics\x12<\n" +
+	"\ftoken_counts\x18\x01 \x01(\v2\x19.claudecat.v1.TokenCountsR\vtokenCounts\x12!\n" +
+	"\ftotal_tokens\x18\x02 \x01(\x03R\vtotalTokens\x12\x12\n" +
+	"\x04cost\x18\x03 \x01(\x01R\x04cost\x12\x1e\n" +
+	"\n" +
+	"percentage\x18\x04 \x01(\x01R\n" +
+	"percentage\x127\n" +
+	"\tlast_used\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\blastUsed\x12\x1f\n" +
+	"\ventry_count\x18\x06 \x01(\x03R\n" +
+	"entryCount\"\xcd\a\n" +
+	"\x0fRealtimeMetrics\x12?\n" +
+	"\rsession_start\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\fsessionStart\x12;\n" +
+	"\vsession_end\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"sessionEnd\x12)\n" +
+	"\x10session_progress\x18\x03 \x01(\x01R\x0fsessionProgress\x12@\n" +
+	"\x0etime_remaining\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\rtimeRemaining\x12\x1b\n" +
+	"\tis_active\x18\x05 \x01(\bR\bisActive\x12%\n" +
+	"\x0ecurrent_tokens\x18\x06 \x01(\x03R\rcurrentTokens\x12!\n" +
+	"\fcurrent_cost\x18\a \x01(\x01R\vcurrentCost\x123\n" +
+	"\tburn_rate\x18\b \x01(\v2\x16.claudecat.v1.BurnRateR\bburnRate\x12=\n" +
+	"\n" +
+	"projection\x18\t \x01(\v2\x1d.claudecat.v1.UsageProjectionR\n" +
+	"projection\x12c\n" +
+	"\x12model_distribution\x18\n" +
+	" \x03(\v24.claudecat.v1.RealtimeMetrics.ModelDistributionEntryR\x11modelDistribution\x12*\n" +
+	"\x11tokens_per_minute\x18\v \x01(\x01R\x0ftokensPerMinute\x12&\n" +
+	"\x0ftokens_per_hour\x18\f \x01(\x01R\rtokensPerHour\x12&\n" +
+	"\x0fcost_per_minute\x18\r \x01(\x01R\rcostPerMinute\x12\"\n" +
+	"\rcost_per_hour\x18\x0e \x01(\x01R\vcostPerHour\x12)\n" +
+	"\x10confidence_level\x18\x0f \x01(\x01R\x0fconfidenceLevel\x12#\n" +
+	"\rhealth_status\x18\x10 \x01(\tR\fhealthStatus\x12=\n" +
+	"\flast_updated\x18\x11 \x01(\v2\x1a.google.protobuf.TimestampR\vlastUpdated\x1a`\n" +
+	"\x16ModelDistributionEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x120\n" +
+	"\x05value\x18\x02 \x01(\v2\x1a.claudecat.v1.ModelMetricsR\x05value:\x028\x01\"\x13\n" +
+	"\x11GetMetricsRequest\"\xed\x01\n" +
+	"\x12GetMetricsResponse\x127\n" +
+	"\ametrics\x18\x01 \x01(\v2\x1d.claudecat.v1.RealtimeMetricsR\ametrics\x12\x1f\n" +
+	"\vtoken_limit\x18\x02 \x01(\x03R\n" +
+	"tokenLimit\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x03 \x01(\tR\tsessionId\x12#\n" +
+	"\rsession_count\x18\x04 \x01(\x03R\fsessionCount\x129\n" +
+	"\n" +
+	"updated_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"u\n" +
+	"\x11ListBlocksRequest\x12\x14\n" +
+	"\x05limit\x18\x01 \x01(\x05R\x05limit\x12!\n" +
+	"\finclude_gaps\x18\x02 \x01(\bR\vincludeGaps\x12'\n" +
+	"\x0finclude_entries\x18\x03 \x01(\bR\x0eincludeEntries\"\x83\x01\n" +
+	"\x12ListBlocksResponse\x122\n" +
+	"\x06blocks\x18\x01 \x03(\v2\x1a.claudecat.v1.SessionBlockR\x06blocks\x129\n" +
+	"\n" +
+	"updated_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"B\n" +
+	"\x17GetActiveSessionRequest\x12'\n" +
+	"\x0finclude_entries\x18\x01 \x01(\bR\x0eincludeEntries\"\x15\n" +
+	"\x13WatchMetricsRequest2\xde\x02\n" +
+	"\fUsageService\x12O\n" +
+	"\n" +
+	"GetMetrics\x12\x1f.claudecat.v1.GetMetricsRequest\x1a .claudecat.v1.GetMetricsResponse\x12O\n" +
+	"\n" +
+	"ListBlocks\x12\x1f.claudecat.v1.ListBlocksRequest\x1a .claudecat.v1.ListBlocksResponse\x12U\n" +
+	"\x10GetActiveSession\x12%.claudecat.v1.GetActiveSessionRequest\x1a\x1a.claudecat.v1.SessionBlock\x12U\n" +
+	"\fWatchMetrics\x12!.claudecat.v1.WatchMetricsRequest\x1a .claudecat.v1.GetMetricsResponse0\x01B9Z7github.com/penwyp/claudecat/api/claudecatv1;claudecatv1b\x06proto3"
+
+var (
+	file_claudecat_v1_claudecat_proto_rawDescOnce sync.Once
+	file_claudecat_v1_claudecat_proto_rawDescData []byte
+)
+
+func file_claudecat_v1_claudecat_proto_rawDescGZIP() []byte {
+	file_claudecat_v1_claudecat_proto_rawDescOnce.Do(func() {
+		file_claudecat_v1_claudecat_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_claudecat_v1_claudecat_proto_rawDesc), len(file_claudecat_v1_claudecat_proto_rawDesc)))
+	})
+	return file_claudecat_v1_claudecat_proto_rawDescData
+}
+
+var file_claudecat_v1_claudecat_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_claudecat_v1_claudecat_proto_goTypes = []any{
+	(*UsageEntry)(nil),              // 0: claudecat.v1.UsageEntry
+	(*TokenCounts)(nil),             // 1: claudecat.v1.TokenCounts
+	(*BurnRate)(nil),                // 2: claudecat.v1.BurnRate
+	(*UsageProjection)(nil),         // 3: claudecat.v1.UsageProjection
+	(*SessionBlock)(nil),            // 4: claudecat.v1.SessionBlock
+	(*ModelMetrics)(nil),            // 5: claudecat.v1.ModelMetrics
+	(*RealtimeMetrics)(nil),         // 6: claudecat.v1.RealtimeMetrics
+	(*GetMetricsRequest)(nil),       // 7: claudecat.v1.GetMetricsRequest
+	(*GetMetricsResponse)(nil),      // 8: claudecat.v1.GetMetricsResponse
+	(*ListBlocksRequest)(nil),       // 9: claudecat.v1.ListBlocksRequest
+	(*ListBlocksResponse)(nil),      // 10: claudecat.v1.ListBlocksResponse
+	(*GetActiveSessionRequest)(nil), // 11: claudecat.v1.GetActiveSessionRequest
+	(*WatchMetricsRequest)(nil),     // 12: claudecat.v1.WatchMetricsRequest
+	nil,                             // 13: claudecat.v1.RealtimeMetrics.ModelDistributionEntry
+	(*timestamppb.Timestamp)(nil),   // 14: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),     // 15: google.protobuf.Duration
+}
+var file_claudecat_v1_claudecat_proto_depIdxs = []int32{
+	14, // 0: claudecat.v1.UsageEntry.timestamp:type_name -> google.protobuf.Timestamp
+	14, // 1: claudecat.v1.SessionBlock.start_time:type_name -> google.protobuf.Timestamp
+	14, // 2: claudecat.v1.SessionBlock.end_time:type_name -> google.protobuf.Timestamp
+	14, // 3: claudecat.v1.SessionBlock.actual_end_time:type_name -> google.protobuf.Timestamp
+	1,  // 4: claudecat.v1.SessionBlock.token_counts:type_name -> claudecat.v1.TokenCounts
+	2,  // 5: claudecat.v1.SessionBlock.burn_rate:type_name -> claudecat.v1.BurnRate
+	3,  // 6: claudecat.v1.SessionBlock.projection:type_name -> claudecat.v1.UsageProjection
+	0,  // 7: claudecat.v1.SessionBlock.entries:type_name -> claudecat.v1.UsageEntry
+	1,  // 8: claudecat.v1.ModelMetrics.token_counts:type_name -> claudecat.v1.TokenCounts
+	14, // 9: claudecat.v1.ModelMetrics.last_used:type_name -> google.protobuf.Timestamp
+	14, // 10: claudecat.v1.RealtimeMetrics.session_start:type_name -> google.protobuf.Timestamp
+	14, // 11: claudecat.v1.RealtimeMetrics.session_end:type_name -> google.protobuf.Timestamp
+	15, // 12: claudecat.v1.RealtimeMetrics.time_remaining:type_name -> google.protobuf.Duration
+	2,  // 13: claudecat.v1.RealtimeMetrics.burn_rate:type_name -> claudecat.v1.BurnRate
+	3,  // 14: claudecat.v1.RealtimeMetrics.projection:type_name -> claudecat.v1.UsageProjection
+	13, // 15: claudecat.v1.RealtimeMetrics.model_distribution:type_name -> claudecat.v1.RealtimeMetrics.ModelDistributionEntry
+	14, // 16: claudecat.v1.RealtimeMetrics.last_updated:type_name -> google.protobuf.Timestamp
+	6,  // 17: claudecat.v1.GetMetricsResponse.metrics:type_name -> claudecat.v1.RealtimeMetrics
+	14, // 18: claudecat.v1.GetMetricsResponse.updated_at:type_name -> google.protobuf.Timestamp
+	4,  // 19: claudecat.v1.ListBlocksResponse.blocks:type_name -> claudecat.v1.SessionBlock
+	14, // 20: claudecat.v1.ListBlocksResponse.updated_at:type_name -> google.protobuf.Timestamp
+	5,  // 21: claudecat.v1.RealtimeMetrics.ModelDistributionEntry.value:type_name -> claudecat.v1.ModelMetrics
+	7,  // 22: claudecat.v1.UsageService.GetMetrics:input_type -> claudecat.v1.GetMetricsRequest
+	9,  // 23: claudecat.v1.UsageService.ListBlocks:input_type -> claudecat.v1.ListBlocksRequest
+	11, // 24: claudecat.v1.UsageService.GetActiveSession:input_type -> claudecat.v1.GetActiveSessionRequest
+	12, // 25: claudecat.v1.UsageService.WatchMetrics:input_type -> claudecat.v1.WatchMetricsRequest
+	8,  // 26: claudecat.v1.UsageService.GetMetrics:output_type -> claudecat.v1.GetMetricsResponse
+	10, // 27: claudecat.v1.UsageService.ListBlocks:output_type -> claudecat.v1.ListBlocksResponse
+	4,  // 28: claudecat.v1.UsageService.GetActiveSession:output_type -> claudecat.v1.SessionBlock
+	8,  // 29: claudecat.v1.UsageService.WatchMetrics:output_type -> claudecat.v1.GetMetricsResponse
+	26, // [26:30] is the sub-list for method output_type
+	22, // [22:26] is the sub-list for method input_type
+	22, // [22:22] is the sub-list for extension type_name
+	22, // [22:22] is the sub-list for extension extendee
+	0,  // [0:22] is the sub-list for field type_name
+}
+
+func init() { file_claudecat_v1_claudecat_proto_init() }
+func file_claudecat_v1_claudecat_proto_init() {
+	if File_claudecat_v1_claudecat_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_claudecat_v1_claudecat_proto_rawDesc), len(file_claudecat_v1_claudecat_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   14,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_claudecat_v1_claudecat_proto_goTypes,
+		DependencyIndexes: file_claudecat_v1_claudecat_proto_depIdxs,
+		MessageInfos:      file_claudecat_v1_claudecat_proto_msgTypes,
+	}.Build()
+	File_claudecat_v1_claudecat_proto = out.File
+	file_claudecat_v1_claudecat_proto_goTypes = nil
+	file_claudecat_v1_claudecat_proto_depIdxs = nil
+}
@@ -0,0 +1,252 @@
+// Package billing pulls what Anthropic reports for an organization from the Admin API, to
+// validate locally computed usage and costs against actual billing.
+package billing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/penwyp/claudecat/models"
+)
+
+const (
+	// AdminKeyEnv is the environment variable holding the Admin API key, an sk-ant-admin key
+	// created in the Console by an organization admin
+	AdminKeyEnv = "ANTHROPIC_ADMIN_KEY"
+
+	// DefaultAdminAPIURL is the base URL of the Anthropic API
+	DefaultAdminAPIURL = "https://api.anthropic.com"
+
+	adminAPIVersion = "2023-06-01"
+
+	// adminPageSize is the most daily buckets the usage and cost reports return per page
+	adminPageSize = 31
+)
+
+// DayModelUsage is the usage of one model on one UTC day
+type DayModelUsage struct {
+	Date                string  `json:"date"` // YYYY-MM-DD in UTC, the day boundaries of the Admin API
+	Model               string  `json:"model"`
+	InputTokens         int     `json:"input_tokens"`
+	OutputTokens        int     `json:"output_tokens"`
+	CacheCreationTokens int     `json:"cache_creation_tokens"`
+	CacheReadTokens     int     `json:"cache_read_tokens"`
+	CostUSD             float64 `json:"cost_usd"`
+}
+
+// TotalTokens returns the tokens of all types
+func (u DayModelUsage) TotalTokens() int {
+	return u.InputTokens + u.OutputTokens + u.CacheCreationTokens + u.CacheReadTokens
+}
+
+// AdminClient reads the usage and cost reports of the Anthropic Admin API
+type AdminClient struct {
+	baseURL string
+	apiKey  string
+	client  *http.Client
+}
+
+// NewAdminClient creates a client authenticating with an Admin API key. An empty baseURL
+// uses DefaultAdminAPIURL.
+func NewAdminClient(apiKey, baseURL string) (*AdminClient, error) {
+	apiKey = strings.TrimSpace(apiKey)
+	if apiKey == "" {
+		return nil, fmt.Errorf("no Admin API key (set %s)", AdminKeyEnv)
+	}
+	if baseURL == "" {
+		baseURL = DefaultAdminAPIURL
+	}
+	parsed, err := url.Parse(baseURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid Admin API URL: %q", baseURL)
+	}
+
+	return &AdminClient{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		apiKey:  apiKey,
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}, nil
+}
+
+// Usage returns the organization's usage and cost per model and UTC day from the start of
+// from's day to the end of to's day, sorted by date and model. Models are normalized like
+// local entries so both sides line up.
+func (c *AdminClient) Usage(ctx context.Context, from, to time.Time) ([]DayModelUsage, error) {
+	start := startOfDay(from)
+	end := startOfDay(to).AddDate(0, 0, 1)
+	if !end.After(start) {
+		return nil, fmt.Errorf("the range ends before it starts")
+	}
+
+	byKey := make(map[[2]string]*DayModelUsage)
+	day := func(date, model string) *DayModelUsage {
+		key := [2]string{date, models.NormalizeModelName(model)}
+		usage, ok := byKey[key]
+		if !ok {
+			usage = &DayModelUsage{Date: key[0], Model: key[1]}
+			byKey[key] = usage
+		}
+		return usage
+	}
+
+	err := c.fetchPages(ctx, "/v1/organizations/usage_report/messages", start, end, "model", func(date string, raw json.RawMessage) error {
+		var result usageResult
+		if err := json.Unmarshal(raw, &result); err != nil {
+			return fmt.Errorf("invalid usage report: %w", err)
+		}
+		if result.Model == "" {
+			return nil
+		}
+		usage := day(date, result.Model)
+		usage.InputTokens += result.UncachedInputTokens
+		usage.OutputTokens += result.OutputTokens
+		usage.CacheCreationTokens += result.CacheCreation.Ephemeral5mInputTokens + result.CacheCreation.Ephemeral1hInputTokens
+		usage.CacheReadTokens += result.CacheReadInputTokens
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.fetchPages(ctx, "/v1/organizations/cost_report", start, end, "description", func(date string, raw json.RawMessage) error {
+		var result costResult
+		if err := json.Unmarshal(raw, &result); err != nil {
+			return fmt.Errorf("invalid cost report: %w", err)
+		}
+		// Costs that aren't tied to a model, like web search, have no local counterpart
+		if result.Model == "" || !strings.EqualFold(result.Currency, "USD") {
+			return nil
+		}
+		cents, err := strconv.ParseFloat(result.Amount, 64)
+		if err != nil {
+			return fmt.Errorf("invalid cost amount %q: %w", result.Amount, err)
+		}
+		day(date, result.Model).CostUSD += cents / 100
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return sortedUsage(byKey), nil
+}
+
+// usageResult is a result of a bucket of the messages usage report
+type usageResult struct {
+	Model                string `json:"model"`
+	UncachedInputTokens  int    `json:"uncached_input_tokens"`
+	CacheReadInputTokens int    `json:"cache_read_input_tokens"`
+	OutputTokens         int    `json:"output_tokens"`
+	CacheCreation        struct {
+		Ephemeral5mInputTokens int `json:"ephemeral_5m_input_tokens"`
+		Ephemeral1hInputTokens int `json:"ephemeral_1h_input_tokens"`
+	} `json:"cache_creation"`
+}
+
+// costResult is a result of a bucket of the cost report. Amounts are decimal strings in
+// the lowest unit of the currency, i.e. cents.
+type costResult struct {
+	Model    string `json:"model"`
+	Currency string `json:"currency"`
+	Amount   string `json:"amount"`
+}
+
+// reportPage is one page of a usage or cost report
+type reportPage struct {
+	Data []struct {
+		StartingAt time.Time         `json:"starting_at"`
+		Results    []json.RawMessage `json:"results"`
+	} `json:"data"`
+	HasMore  bool   `json:"has_more"`
+	NextPage string `json:"next_page"`
+}
+
+// fetchPages requests every page of a report of daily buckets and passes each result to
+// visit along with the date of its bucket
+func (c *AdminClient) fetchPages(ctx context.Context, path string, start, end time.Time, groupBy string, visit func(date string, result json.RawMessage) error) error {
+	query := url.Values{}
+	query.Set("starting_at", start.Format(time.RFC3339))
+	query.Set("ending_at", end.Format(time.RFC3339))
+	query.Set("bucket_width", "1d")
+	query.Set("limit", strconv.Itoa(adminPageSize))
+	query.Add("group_by[]", groupBy)
+
+	for {
+		page, err := c.get(ctx, path, query)
+		if err != nil {
+			return err
+		}
+		for _, bucket := range page.Data {
+			date := bucket.StartingAt.UTC().Format("2006-01-02")
+			for _, result := range bucket.Results {
+				if err := visit(date, result); err != nil {
+					return err
+				}
+			}
+		}
+		if !page.HasMore || page.NextPage == "" {
+			return nil
+		}
+		query.Set("page", page.NextPage)
+	}
+}
+
+// get requests one page of a report
+func (c *AdminClient) get(ctx context.Context, path string, query url.Values) (*reportPage, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Admin API request: %w", err)
+	}
+	req.Header.Set("x-api-key", c.apiKey)
+	req.Header.Set("anthropic-version", adminAPIVersion)
+	req.Header.Set("User-Agent", "claudecat")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the Admin API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("Admin API returned status %d: %s", resp.StatusCode, bytes.TrimSpace(detail))
+	}
+
+	var page reportPage
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return nil, fmt.Errorf("failed to decode Admin API response: %w", err)
+	}
+	return &page, nil
+}
+
+// startOfDay returns the start of t's UTC day
+func startOfDay(t time.Time) time.Time {
+	year, month, day := t.UTC().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// sortedUsage flattens usage by date and model into a list sorted the same way
+func sortedUsage(byKey map[[2]string]*DayModelUsage) []DayModelUsage {
+	result := make([]DayModelUsage, 0, len(byKey))
+	for _, usage := range byKey {
+		result = append(result, *usage)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Date != result[j].Date {
+			return result[i].Date < result[j].Date
+		}
+		return result[i].Model < result[j].Model
+	})
+	return result
+}
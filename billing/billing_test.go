@@ -0,0 +1,124 @@
+package billing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/penwyp/claudecat/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const usagePage1 = `{"data":[{"starting_at":"2025-06-01T00:00:00Z","ending_at":"2025-06-02T00:00:00Z","results":[
+  {"model":"claude-sonnet-4-20250514","uncached_input_tokens":1000,"cache_read_input_tokens":4000,"output_tokens":500,
+   "cache_creation":{"ephemeral_5m_input_tokens":200,"ephemeral_1h_input_tokens":100}}]}],
+ "has_more":true,"next_page":"page-2"}`
+
+const usagePage2 = `{"data":[{"starting_at":"2025-06-02T00:00:00Z","ending_at":"2025-06-03T00:00:00Z","results":[
+  {"model":"claude-opus-4-20250514","uncached_input_tokens":10,"output_tokens":20,"cache_creation":{}}]}],
+ "has_more":false,"next_page":null}`
+
+const costPage = `{"data":[{"starting_at":"2025-06-01T00:00:00Z","ending_at":"2025-06-02T00:00:00Z","results":[
+  {"model":"claude-sonnet-4-20250514","currency":"USD","amount":"150.5","token_type":"output_tokens"},
+  {"model":"claude-sonnet-4-20250514","currency":"USD","amount":"49.5","token_type":"uncached_input_tokens"},
+  {"model":null,"currency":"USD","amount":"1000","cost_type":"web_search"}]}],
+ "has_more":false,"next_page":null}`
+
+func TestAdminClient_Usage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "sk-ant-admin-test", r.Header.Get("x-api-key"))
+		assert.Equal(t, adminAPIVersion, r.Header.Get("anthropic-version"))
+		assert.Equal(t, "2025-06-01T00:00:00Z", r.URL.Query().Get("starting_at"))
+		assert.Equal(t, "2025-06-03T00:00:00Z", r.URL.Query().Get("ending_at"), "the last day is included")
+		assert.Equal(t, "1d", r.URL.Query().Get("bucket_width"))
+
+		switch r.URL.Path {
+		case "/v1/organizations/usage_report/messages":
+			if r.URL.Query().Get("page") == "page-2" {
+				w.Write([]byte(usagePage2))
+				return
+			}
+			w.Write([]byte(usagePage1))
+		case "/v1/organizations/cost_report":
+			w.Write([]byte(costPage))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewAdminClient("sk-ant-admin-test", server.URL)
+	require.NoError(t, err)
+	usage, err := client.Usage(context.Background(),
+		time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 6, 2, 12, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Len(t, usage, 2)
+
+	sonnet := usage[0]
+	assert.Equal(t, "2025-06-01", sonnet.Date)
+	assert.Equal(t, "claude-sonnet-4-20250514", sonnet.Model)
+	assert.Equal(t, 1000, sonnet.InputTokens)
+	assert.Equal(t, 300, sonnet.CacheCreationTokens)
+	assert.Equal(t, 4000, sonnet.CacheReadTokens)
+	assert.Equal(t, 5800, sonnet.TotalTokens())
+	assert.InDelta(t, 2.00, sonnet.CostUSD, 1e-9, "amounts are in cents")
+
+	assert.Equal(t, "2025-06-02", usage[1].Date, "later pages are fetched")
+	assert.Equal(t, 30, usage[1].TotalTokens())
+}
+
+func TestAdminClient_Errors(t *testing.T) {
+	_, err := NewAdminClient(" ", "")
+	assert.Error(t, err)
+	_, err = NewAdminClient("key", "ftp://example.com")
+	assert.Error(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":{"type":"authentication_error"}}`, http.StatusUnauthorized)
+	}))
+	defer server.Close()
+	client, err := NewAdminClient("key", server.URL)
+	require.NoError(t, err)
+	day := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	_, err = client.Usage(context.Background(), day, day)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "401")
+	assert.Contains(t, err.Error(), "authentication_error")
+}
+
+func TestReconcile(t *testing.T) {
+	day := time.Date(2025, 6, 1, 23, 0, 0, 0, time.UTC)
+	entries := []models.UsageEntry{
+		{Timestamp: day, Model: "claude-sonnet-4-20250514", Source: models.SourceClaude, InputTokens: 1000, OutputTokens: 400, ThinkingTokens: 100, CacheCreationTokens: 300, CacheReadTokens: 4000, CostUSD: 2.01},
+		{Timestamp: day, Model: "anthropic.claude-sonnet-4-20250514-v1:0", Source: models.SourceBedrock, InputTokens: 5000, CostUSD: 1},
+		{Timestamp: day, Model: "gpt-5", Source: models.SourceCodex, InputTokens: 5000, CostUSD: 1},
+		{Timestamp: day.Add(2 * time.Hour), Model: "claude-opus-4-20250514", InputTokens: 100, CostUSD: 0.5},
+		{Timestamp: day.AddDate(0, 0, 5), Model: "claude-opus-4-20250514", InputTokens: 100, CostUSD: 0.5},
+	}
+	local := AggregateLocal(entries, day, day.AddDate(0, 0, 1))
+	require.Len(t, local, 2, "other providers and days out of range are left out")
+	assert.Equal(t, 500, local[0].OutputTokens, "thinking is billed as output")
+
+	billed := []DayModelUsage{
+		{Date: "2025-06-01", Model: "claude-sonnet-4-20250514", InputTokens: 1000, OutputTokens: 500, CacheCreationTokens: 300, CacheReadTokens: 4000, CostUSD: 2.00},
+		{Date: "2025-06-02", Model: "claude-opus-4-20250514", InputTokens: 100, CostUSD: 0.6},
+		{Date: "2025-06-02", Model: "claude-haiku-4-5", InputTokens: 100, CostUSD: 0.1},
+	}
+	days := Reconcile(local, billed, 1)
+	require.Len(t, days, 3)
+
+	assert.Equal(t, "claude-sonnet-4-20250514", days[0].Model)
+	assert.True(t, days[0].Match, "within tolerance")
+
+	assert.Equal(t, "claude-haiku-4-5", days[1].Model)
+	assert.False(t, days[1].Match)
+	assert.Contains(t, days[1].LikelyCause, "billed only")
+
+	assert.Equal(t, "claude-opus-4-20250514", days[2].Model)
+	assert.Equal(t, 0, days[2].TokenDiff)
+	assert.InDelta(t, -0.1, days[2].CostDiff, 1e-9)
+	assert.Contains(t, days[2].LikelyCause, "pricing")
+}
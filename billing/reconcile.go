@@ -0,0 +1,127 @@
+package billing
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/penwyp/claudecat/models"
+)
+
+// Discrepancy compares the local usage of one model on one UTC day with what was billed
+type Discrepancy struct {
+	Date         string  `json:"date"`
+	Model        string  `json:"model"`
+	LocalTokens  int     `json:"local_tokens"`
+	BilledTokens int     `json:"billed_tokens"`
+	TokenDiff    int     `json:"token_diff"`
+	LocalCost    float64 `json:"local_cost"`
+	BilledCost   float64 `json:"billed_cost"`
+	CostDiff     float64 `json:"cost_diff"`
+	Match        bool    `json:"match"`
+	LikelyCause  string  `json:"likely_cause,omitempty"`
+}
+
+// AggregateLocal sums local entries per model and UTC day within [from, to]. Only usage
+// billed by Anthropic directly is counted: Bedrock, Vertex AI and other assistants are
+// billed elsewhere.
+func AggregateLocal(entries []models.UsageEntry, from, to time.Time) []DayModelUsage {
+	first, last := startOfDay(from).Format("2006-01-02"), startOfDay(to).Format("2006-01-02")
+	byKey := make(map[[2]string]*DayModelUsage)
+	for _, entry := range entries {
+		if entry.Source != "" && entry.Source != models.SourceClaude {
+			continue
+		}
+		model := models.NormalizeModelName(entry.Model)
+		if model == "" || model == "<synthetic>" {
+			continue
+		}
+		date := entry.Timestamp.UTC().Format("2006-01-02")
+		if date < first || date > last {
+			continue
+		}
+
+		key := [2]string{date, model}
+		usage, ok := byKey[key]
+		if !ok {
+			usage = &DayModelUsage{Date: date, Model: model}
+			byKey[key] = usage
+		}
+		usage.InputTokens += entry.InputTokens
+		// Thinking is billed as output
+		usage.OutputTokens += entry.OutputTokens + entry.ThinkingTokens
+		usage.CacheCreationTokens += entry.CacheCreationTokens
+		usage.CacheReadTokens += entry.CacheReadTokens
+		usage.CostUSD += entry.CostUSD
+	}
+	return sortedUsage(byKey)
+}
+
+// Reconcile compares local and billed usage per model and day. A pair matches when both
+// its tokens and its cost are within tolerancePercent of each other.
+func Reconcile(local, billed []DayModelUsage, tolerancePercent float64) []Discrepancy {
+	byKey := make(map[[2]string]*Discrepancy)
+	row := func(usage DayModelUsage) *Discrepancy {
+		key := [2]string{usage.Date, usage.Model}
+		d, ok := byKey[key]
+		if !ok {
+			d = &Discrepancy{Date: usage.Date, Model: usage.Model}
+			byKey[key] = d
+		}
+		return d
+	}
+	for _, usage := range local {
+		d := row(usage)
+		d.LocalTokens += usage.TotalTokens()
+		d.LocalCost += usage.CostUSD
+	}
+	for _, usage := range billed {
+		d := row(usage)
+		d.BilledTokens += usage.TotalTokens()
+		d.BilledCost += usage.CostUSD
+	}
+
+	result := make([]Discrepancy, 0, len(byKey))
+	for _, d := range byKey {
+		d.TokenDiff = d.LocalTokens - d.BilledTokens
+		d.CostDiff = d.LocalCost - d.BilledCost
+		tokensMatch := WithinTolerance(float64(d.LocalTokens), float64(d.BilledTokens), tolerancePercent)
+		d.Match = tokensMatch && WithinTolerance(d.LocalCost, d.BilledCost, tolerancePercent)
+		if !d.Match {
+			d.LikelyCause = likelyCause(*d, tokensMatch)
+		}
+		result = append(result, *d)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Date != result[j].Date {
+			return result[i].Date < result[j].Date
+		}
+		return result[i].Model < result[j].Model
+	})
+	return result
+}
+
+// likelyCause guesses why local and billed usage differ
+func likelyCause(d Discrepancy, tokensMatch bool) string {
+	switch {
+	case tokensMatch:
+		return "pricing: tokens match but cost differs (pricing source or discounts)"
+	case d.LocalTokens == 0:
+		return "billed only: usage from other machines, users or API clients"
+	case d.BilledTokens == 0:
+		return "local only: usage covered by a subscription or another organization"
+	case d.TokenDiff < 0:
+		return "more billed than logged: other machines, users or API clients share the organization"
+	default:
+		return "more logged than billed: duplicate entries or usage covered by a subscription"
+	}
+}
+
+// WithinTolerance reports whether a and b differ by at most tolerancePercent of the larger value
+func WithinTolerance(a, b, tolerancePercent float64) bool {
+	largest := math.Max(math.Abs(a), math.Abs(b))
+	if largest == 0 {
+		return true
+	}
+	return math.Abs(a-b)/largest*100 <= tolerancePercent
+}
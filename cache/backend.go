@@ -0,0 +1,185 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/penwyp/claudecat/config"
+)
+
+// Summary cache backends
+const (
+	BackendFile   = "file"   // One JSON file per summary under the cache directory
+	BackendBolt   = "bbolt"  // Embedded bbolt database in the cache directory
+	BackendSQLite = "sqlite" // SQLite database in the cache directory
+	BackendRedis  = "redis"  // Redis server, shareable between machines
+)
+
+// SummaryStore persists file summaries. Every backend implements it, so loaders and the
+// monitor don't need to know where summaries live.
+type SummaryStore interface {
+	GetFileSummary(absolutePath string) (*FileSummary, error)
+	SetFileSummary(summary *FileSummary) error
+	HasFileSummary(absolutePath string) bool
+	InvalidateFileSummary(absolutePath string) error
+	BatchSet(summaries []*FileSummary) error
+	Clear() error
+	Close() error
+
+	// Scan calls fn for every stored summary, including ones that can't be decoded
+	Scan(fn func(stored StoredSummary) error) error
+	// Remove deletes the stored summary with the given key, as reported by Scan
+	Remove(key string) error
+	// StorageSize returns the bytes used by the stored summaries
+	StorageSize() (int64, error)
+	// Compact reclaims space left behind by deleted summaries
+	Compact() error
+	// FindByIdentity returns a stored summary of a file with the given identity, whatever
+	// its path, so renamed files are recognized
+	FindByIdentity(identity string) (*FileSummary, error)
+}
+
+// StoredSummary is a summary as found in a backend by Scan
+type StoredSummary struct {
+	Key     string       // Backend-specific key, accepted by Remove
+	Summary *FileSummary // Nil when the stored data is corrupt
+	Err     error        // Why the stored data couldn't be decoded
+}
+
+// BackendConfig selects and configures a summary cache backend
+type BackendConfig struct {
+	Backend   string        // One of the Backend* constants; empty means BackendFile
+	Dir       string        // Cache directory used by the file, bbolt and sqlite backends
+	RedisURL  string        // Connection URL for the redis backend, e.g. redis://localhost:6379/0
+	RedisTTL  time.Duration // Expiry of summaries stored in redis; zero keeps them forever
+	KeyPrefix string        // Prefix of redis keys; empty uses DefaultRedisKeyPrefix
+	ReadOnly  bool          // Open without creating or changing anything, for one-shot commands
+}
+
+// NewBackendConfig returns the backend configuration of the cache settings, with the
+// cache directory dir, already expanded
+func NewBackendConfig(settings config.CacheConfig, dir string, readOnly bool) BackendConfig {
+	return BackendConfig{
+		Backend:   settings.Backend,
+		Dir:       dir,
+		RedisURL:  settings.RedisURL,
+		RedisTTL:  settings.RedisTTL,
+		KeyPrefix: settings.RedisKeyPrefix,
+		ReadOnly:  readOnly,
+	}
+}
+
+// ErrReadOnly is returned when a store opened read-only is asked to change the cache
+var ErrReadOnly = errors.New("summary cache is open read-only")
+
+// OpenSummaryStore opens the summary cache backend selected by cfg
+func OpenSummaryStore(cfg BackendConfig) (SummaryStore, error) {
+	switch cfg.Backend {
+	case "", BackendFile:
+		return newFileBasedSummaryCache(cfg.Dir, cfg.ReadOnly)
+	case BackendBolt:
+		return newBoltSummaryCache(cfg.Dir, cfg.ReadOnly)
+	case BackendSQLite:
+		return newSQLiteSummaryCache(cfg.Dir, cfg.ReadOnly)
+	case BackendRedis:
+		return newRedisSummaryCache(cfg.RedisURL, cfg.KeyPrefix, cfg.RedisTTL, cfg.ReadOnly)
+	default:
+		return nil, fmt.Errorf("unknown cache backend: %s", cfg.Backend)
+	}
+}
+
+// errSummaryNotFound is returned by backends when no summary is stored for a path
+func errSummaryNotFound(absolutePath string) error {
+	return fmt.Errorf("file summary not found: %s", absolutePath)
+}
+
+// errIdentityNotFound is returned by backends when no summary has an identity
+func errIdentityNotFound(identity string) error {
+	return fmt.Errorf("no file summary with identity %s", identity)
+}
+
+// summarizesLaterState reports whether stored describes a later state of its file than
+// summary. The monitor and one-off commands share the cache and may summarize a file at
+// different times, so a write never replaces a summary of a later state with one of an
+// earlier state, whatever order the writes land in.
+func summarizesLaterState(stored, summary *FileSummary) bool {
+	if stored.ModTime.Equal(summary.ModTime) {
+		return stored.FileSize > summary.FileSize
+	}
+	return stored.ModTime.After(summary.ModTime)
+}
+
+// findByIdentity looks up a summary by identity by scanning every stored summary, for
+// backends that don't index identities. Lookups only happen for files new to the cache.
+func findByIdentity(scan func(fn func(stored StoredSummary) error) error, identity string) (*FileSummary, error) {
+	var found *FileSummary
+	errFound := errors.New("found")
+	err := scan(func(stored StoredSummary) error {
+		if stored.Summary != nil && stored.Summary.Identity == identity {
+			found = stored.Summary
+			return errFound
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errFound) {
+		return nil, err
+	}
+	if found == nil {
+		return nil, errIdentityNotFound(identity)
+	}
+	return found, nil
+}
+
+// encodeSummary encodes a summary for storage, together with a checksum of its content
+func encodeSummary(summary *FileSummary) ([]byte, error) {
+	integrity, err := summaryIntegrity(summary)
+	if err != nil {
+		return nil, err
+	}
+	signed := *summary
+	signed.Integrity = integrity
+	data, err := json.Marshal(&signed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal summary: %w", err)
+	}
+	return data, nil
+}
+
+// decodeSummary decodes a stored summary, rejecting data that doesn't identify its file or
+// doesn't match its checksum. Summaries stored before checksums existed are accepted.
+func decodeSummary(data []byte) (*FileSummary, error) {
+	var summary FileSummary
+	if err := json.Unmarshal(data, &summary); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal summary: %w", err)
+	}
+	if summary.AbsolutePath == "" {
+		return nil, fmt.Errorf("summary has no file path")
+	}
+	if summary.Integrity != "" {
+		integrity, err := summaryIntegrity(&summary)
+		if err != nil {
+			return nil, err
+		}
+		if integrity != summary.Integrity {
+			return nil, fmt.Errorf("summary checksum mismatch for %s", summary.AbsolutePath)
+		}
+	}
+	return &summary, nil
+}
+
+// summaryIntegrity returns the SHA-256 checksum of a summary's content, excluding the
+// checksum itself
+func summaryIntegrity(summary *FileSummary) (string, error) {
+	unsigned := *summary
+	unsigned.Integrity = ""
+	data, err := json.Marshal(&unsigned)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal summary: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
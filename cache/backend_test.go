@@ -0,0 +1,197 @@
+package cache
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummaryStoreBackends(t *testing.T) {
+	backends := []BackendConfig{
+		{Backend: BackendFile},
+		{Backend: BackendBolt},
+		{Backend: BackendSQLite},
+	}
+	// Redis needs a running server, e.g. CLAUDECAT_TEST_REDIS_URL=redis://localhost:6379/15
+	if url := os.Getenv("CLAUDECAT_TEST_REDIS_URL"); url != "" {
+		backends = append(backends, BackendConfig{Backend: BackendRedis, RedisURL: url, KeyPrefix: "claudecat:test:"})
+	}
+
+	for _, cfg := range backends {
+		t.Run(cfg.Backend, func(t *testing.T) {
+			cfg.Dir = t.TempDir()
+			store, err := OpenSummaryStore(cfg)
+			require.NoError(t, err)
+			require.NoError(t, store.Clear())
+
+			modTime := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+			first := &FileSummary{Path: "a.jsonl", AbsolutePath: "/data/a.jsonl", ModTime: modTime, FileSize: 100, EntryCount: 2, TotalTokens: 300}
+			second := &FileSummary{Path: "b.jsonl", AbsolutePath: "/data/b.jsonl", ModTime: modTime, FileSize: 50, EntryCount: 1, TotalTokens: 150, Identity: "b-head"}
+
+			_, err = store.GetFileSummary(first.AbsolutePath)
+			assert.Error(t, err)
+			assert.False(t, store.HasFileSummary(first.AbsolutePath))
+
+			require.NoError(t, store.SetFileSummary(first))
+			require.NoError(t, store.BatchSet([]*FileSummary{second}))
+
+			got, err := store.GetFileSummary(first.AbsolutePath)
+			require.NoError(t, err)
+			assert.Equal(t, 300, got.TotalTokens)
+			assert.True(t, got.ModTime.Equal(modTime))
+			assert.True(t, store.HasFileSummary(second.AbsolutePath))
+
+			found, err := store.FindByIdentity("b-head")
+			require.NoError(t, err)
+			assert.Equal(t, second.AbsolutePath, found.AbsolutePath)
+			_, err = store.FindByIdentity("unknown")
+			assert.Error(t, err)
+
+			require.NoError(t, store.InvalidateFileSummary(first.AbsolutePath))
+			assert.False(t, store.HasFileSummary(first.AbsolutePath))
+			assert.True(t, store.HasFileSummary(second.AbsolutePath))
+
+			// Summaries survive reopening the backend
+			require.NoError(t, store.Close())
+			store, err = OpenSummaryStore(cfg)
+			require.NoError(t, err)
+			got, err = store.GetFileSummary(second.AbsolutePath)
+			require.NoError(t, err)
+			assert.Equal(t, 150, got.TotalTokens)
+
+			require.NoError(t, store.Clear())
+			assert.False(t, store.HasFileSummary(second.AbsolutePath))
+			require.NoError(t, store.Close())
+		})
+	}
+}
+
+func TestSummaryStoreBackends_SharedBetweenProcesses(t *testing.T) {
+	for _, backend := range []string{BackendFile, BackendBolt, BackendSQLite} {
+		t.Run(backend, func(t *testing.T) {
+			cfg := BackendConfig{Backend: backend, Dir: t.TempDir()}
+			monitor, err := OpenSummaryStore(cfg)
+			require.NoError(t, err)
+			defer monitor.Close()
+			command, err := OpenSummaryStore(cfg)
+			require.NoError(t, err)
+			defer command.Close()
+
+			modTime := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+			earlier := &FileSummary{AbsolutePath: "/data/a.jsonl", ModTime: modTime, FileSize: 100, TotalTokens: 300}
+			later := &FileSummary{AbsolutePath: "/data/a.jsonl", ModTime: modTime.Add(time.Minute), FileSize: 150, TotalTokens: 450}
+
+			// A summary of an earlier state of the file that lands last doesn't replace the later one
+			require.NoError(t, command.SetFileSummary(later))
+			require.NoError(t, monitor.SetFileSummary(earlier))
+			got, err := monitor.GetFileSummary(later.AbsolutePath)
+			require.NoError(t, err)
+			assert.Equal(t, 450, got.TotalTokens)
+
+			// Read-only stores see the summaries and refuse to change them
+			reader, err := OpenSummaryStore(BackendConfig{Backend: backend, Dir: cfg.Dir, ReadOnly: true})
+			require.NoError(t, err)
+			defer reader.Close()
+			got, err = reader.GetFileSummary(later.AbsolutePath)
+			require.NoError(t, err)
+			assert.Equal(t, 450, got.TotalTokens)
+			assert.ErrorIs(t, reader.SetFileSummary(earlier), ErrReadOnly)
+			assert.ErrorIs(t, reader.InvalidateFileSummary(later.AbsolutePath), ErrReadOnly)
+			assert.ErrorIs(t, reader.Clear(), ErrReadOnly)
+			assert.True(t, monitor.HasFileSummary(later.AbsolutePath))
+		})
+	}
+}
+
+func TestOpenSummaryStore_ReadOnlyCreatesNothing(t *testing.T) {
+	for _, backend := range []string{BackendFile, BackendBolt} {
+		t.Run(backend, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "cache")
+			store, err := OpenSummaryStore(BackendConfig{Backend: backend, Dir: dir, ReadOnly: true})
+			require.NoError(t, err)
+			assert.False(t, store.HasFileSummary("/data/a.jsonl"))
+			size, err := store.StorageSize()
+			require.NoError(t, err)
+			assert.Zero(t, size)
+			require.NoError(t, store.Close())
+			assert.NoDirExists(t, dir)
+		})
+	}
+
+	_, err := OpenSummaryStore(BackendConfig{Backend: BackendSQLite, Dir: t.TempDir(), ReadOnly: true})
+	assert.Error(t, err, "a read-only SQLite cache needs an existing database")
+}
+
+func TestSQLiteSummaryCache_IndexesIdentitiesOfEarlierDatabases(t *testing.T) {
+	dir := t.TempDir()
+	summary := &FileSummary{Path: "b.jsonl", AbsolutePath: "/data/b.jsonl", ModTime: time.Now(), FileSize: 50, Identity: "b-head"}
+	data, err := encodeSummary(summary)
+	require.NoError(t, err)
+
+	// A database as written before identities were indexed
+	db, err := sql.Open("sqlite3", filepath.Join(dir, SQLiteDBFileName))
+	require.NoError(t, err)
+	_, err = db.Exec(`CREATE TABLE summaries (absolute_path TEXT PRIMARY KEY, data BLOB NOT NULL)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO summaries (absolute_path, data) VALUES (?, ?)`, summary.AbsolutePath, data)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	// Read-only, the database can't be changed, so the summaries are searched
+	readOnly, err := newSQLiteSummaryCache(dir, true)
+	require.NoError(t, err)
+	assert.False(t, readOnly.indexed)
+	found, err := readOnly.FindByIdentity("b-head")
+	require.NoError(t, err)
+	assert.Equal(t, summary.AbsolutePath, found.AbsolutePath)
+	require.NoError(t, readOnly.Close())
+
+	store, err := newSQLiteSummaryCache(dir, false)
+	require.NoError(t, err)
+	defer store.Close()
+	assert.True(t, store.indexed)
+	found, err = store.FindByIdentity("b-head")
+	require.NoError(t, err, "existing summaries are indexed")
+	assert.Equal(t, summary.AbsolutePath, found.AbsolutePath)
+	_, err = store.FindByIdentity("")
+	assert.Error(t, err)
+}
+
+func TestOpenSummaryStore_UnknownBackend(t *testing.T) {
+	_, err := OpenSummaryStore(BackendConfig{Backend: "memcached", Dir: t.TempDir()})
+	assert.Error(t, err)
+
+	_, err = OpenSummaryStore(BackendConfig{Backend: BackendRedis})
+	assert.Error(t, err)
+}
+
+func TestSummaryIntegrity(t *testing.T) {
+	summary := &FileSummary{AbsolutePath: "/data/a.jsonl", ModTime: time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC),
+		TotalTokens: 300, TotalCost: 0.1, ModelStats: map[string]ModelStat{"claude-opus-4": {EntryCount: 2}}}
+
+	data, err := encodeSummary(summary)
+	require.NoError(t, err)
+	assert.Empty(t, summary.Integrity, "the caller's summary is left alone")
+
+	decoded, err := decodeSummary(data)
+	require.NoError(t, err)
+	assert.Equal(t, 300, decoded.TotalTokens)
+	assert.NotEmpty(t, decoded.Integrity)
+
+	// Content changed after it was written fails the checksum
+	tampered := strings.Replace(string(data), `"total_tokens":300`, `"total_tokens":301`, 1)
+	require.NotEqual(t, string(data), tampered)
+	_, err = decodeSummary([]byte(tampered))
+	assert.ErrorContains(t, err, "checksum mismatch")
+
+	// Summaries written before checksums existed are still read
+	decoded, err = decodeSummary([]byte(`{"absolute_path": "/data/a.jsonl", "total_tokens": 5}`))
+	require.NoError(t, err)
+	assert.Equal(t, 5, decoded.TotalTokens)
+}
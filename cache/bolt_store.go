@@ -0,0 +1,323 @@
+package cache
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/penwyp/claudecat/logging"
+	bolt "go.etcd.io/bbolt"
+)
+
+// BoltDBFileName is the name of the bbolt summary database inside the cache directory
+const BoltDBFileName = "summaries.db"
+
+var boltSummariesBucket = []byte("summaries")
+
+// boltLockTimeout bounds how long an operation waits for another claudecat process
+// that has the database open
+const boltLockTimeout = 2 * time.Second
+
+// BoltSummaryCache stores file summaries in a single bbolt database. Summaries are preloaded
+// into memory like the file-based cache, and the database is only opened while reading or
+// writing so several claudecat processes can share it.
+type BoltSummaryCache struct {
+	path     string
+	memCache map[string]*FileSummary
+	readOnly bool
+	mu       sync.RWMutex
+}
+
+// NewBoltSummaryCache opens (or creates) the bbolt summary database in cacheDir
+func NewBoltSummaryCache(cacheDir string) (*BoltSummaryCache, error) {
+	return newBoltSummaryCache(cacheDir, false)
+}
+
+// newBoltSummaryCache opens the bbolt summary database in cacheDir. A read-only cache
+// takes a shared lock while preloading, so it doesn't wait for other readers, and starts
+// empty when the database doesn't exist yet.
+func newBoltSummaryCache(cacheDir string, readOnly bool) (*BoltSummaryCache, error) {
+	if !readOnly {
+		if err := os.MkdirAll(cacheDir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create cache directory: %w", err)
+		}
+	}
+
+	c := &BoltSummaryCache{
+		path:     filepath.Join(cacheDir, BoltDBFileName),
+		memCache: make(map[string]*FileSummary),
+		readOnly: readOnly,
+	}
+
+	preload := func(bucket *bolt.Bucket) error {
+		return bucket.ForEach(func(key, value []byte) error {
+			summary, err := decodeSummary(value)
+			if err != nil {
+				logging.LogDebugf("Skipping unreadable summary for %s: %v", key, err)
+				return nil
+			}
+			c.memCache[summary.AbsolutePath] = summary
+			return nil
+		})
+	}
+	var err error
+	if readOnly {
+		err = c.view(preload)
+	} else {
+		err = c.update(preload)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	logging.LogInfof("Initialized bbolt cache at %s with %d preloaded summaries", c.path, len(c.memCache))
+	return c, nil
+}
+
+// GetFileSummary retrieves a file summary from cache
+func (c *BoltSummaryCache) GetFileSummary(absolutePath string) (*FileSummary, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	summary, exists := c.memCache[absolutePath]
+	if !exists {
+		return nil, errSummaryNotFound(absolutePath)
+	}
+	return summary, nil
+}
+
+// SetFileSummary stores a file summary in cache
+func (c *BoltSummaryCache) SetFileSummary(summary *FileSummary) error {
+	return c.BatchSet([]*FileSummary{summary})
+}
+
+// BatchSet stores several summaries in one transaction
+func (c *BoltSummaryCache) BatchSet(summaries []*FileSummary) error {
+	if len(summaries) == 0 {
+		return nil
+	}
+
+	if c.readOnly {
+		return ErrReadOnly
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// The transaction holds the database lock, so no other process can store a summary
+	// between checking for a newer one and writing
+	kept := make([]*FileSummary, 0, len(summaries))
+	err := c.update(func(bucket *bolt.Bucket) error {
+		kept = kept[:0]
+		for _, summary := range summaries {
+			if existing := bucket.Get([]byte(summary.AbsolutePath)); existing != nil {
+				if stored, err := decodeSummary(existing); err == nil && summarizesLaterState(stored, summary) {
+					kept = append(kept, stored)
+					continue
+				}
+			}
+			data, err := encodeSummary(summary)
+			if err != nil {
+				return err
+			}
+			if err := bucket.Put([]byte(summary.AbsolutePath), data); err != nil {
+				return fmt.Errorf("failed to store summary for %s: %w", summary.AbsolutePath, err)
+			}
+			kept = append(kept, summary)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, summary := range kept {
+		c.memCache[summary.AbsolutePath] = summary
+	}
+	return nil
+}
+
+// HasFileSummary checks if a file summary exists in cache
+func (c *BoltSummaryCache) HasFileSummary(absolutePath string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	_, exists := c.memCache[absolutePath]
+	return exists
+}
+
+// InvalidateFileSummary removes a file summary from cache
+func (c *BoltSummaryCache) InvalidateFileSummary(absolutePath string) error {
+	if c.readOnly {
+		return ErrReadOnly
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.memCache, absolutePath)
+	return c.update(func(bucket *bolt.Bucket) error {
+		if err := bucket.Delete([]byte(absolutePath)); err != nil {
+			return fmt.Errorf("failed to delete summary: %w", err)
+		}
+		return nil
+	})
+}
+
+// Clear removes all summaries from cache
+func (c *BoltSummaryCache) Clear() error {
+	if c.readOnly {
+		return ErrReadOnly
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.memCache = make(map[string]*FileSummary)
+	err := c.updateTx(func(tx *bolt.Tx) error {
+		if err := tx.DeleteBucket(boltSummariesBucket); err != nil && err != bolt.ErrBucketNotFound {
+			return fmt.Errorf("failed to clear summaries: %w", err)
+		}
+		_, err := tx.CreateBucket(boltSummariesBucket)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	logging.LogInfof("Cache cleared")
+	return nil
+}
+
+// Scan calls fn for every summary in the database
+func (c *BoltSummaryCache) Scan(fn func(stored StoredSummary) error) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.view(func(bucket *bolt.Bucket) error {
+		return bucket.ForEach(func(key, value []byte) error {
+			stored := StoredSummary{Key: string(key)}
+			stored.Summary, stored.Err = decodeSummary(value)
+			return fn(stored)
+		})
+	})
+}
+
+// FindByIdentity returns a preloaded summary with the given identity
+func (c *BoltSummaryCache) FindByIdentity(identity string) (*FileSummary, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, summary := range c.memCache {
+		if summary.Identity == identity {
+			return summary, nil
+		}
+	}
+	return nil, errIdentityNotFound(identity)
+}
+
+// Remove deletes the summary stored under key, the file path it summarizes
+func (c *BoltSummaryCache) Remove(key string) error {
+	return c.InvalidateFileSummary(key)
+}
+
+// StorageSize returns the size of the database file
+func (c *BoltSummaryCache) StorageSize() (int64, error) {
+	info, err := os.Stat(c.path)
+	if c.readOnly && os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to stat summary database: %w", err)
+	}
+	return info.Size(), nil
+}
+
+// Compact rewrites the database into a new file, since bbolt never shrinks its file when
+// summaries are deleted
+func (c *BoltSummaryCache) Compact() error {
+	if c.readOnly {
+		return ErrReadOnly
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	src, err := bolt.Open(c.path, 0644, &bolt.Options{Timeout: boltLockTimeout})
+	if err != nil {
+		return fmt.Errorf("failed to open summary database %s: %w", c.path, err)
+	}
+	defer src.Close()
+
+	tmpPath := c.path + ".compact"
+	dst, err := bolt.Open(tmpPath, 0644, &bolt.Options{Timeout: boltLockTimeout})
+	if err != nil {
+		return fmt.Errorf("failed to create compacted database: %w", err)
+	}
+	if err := bolt.Compact(dst, src, 0); err != nil {
+		dst.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to compact summary database: %w", err)
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write compacted database: %w", err)
+	}
+
+	// Replace the file while still holding the lock on the original
+	if err := os.Rename(tmpPath, c.path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to replace summary database: %w", err)
+	}
+	return nil
+}
+
+// Close is a no-op since the database is only open during operations
+func (c *BoltSummaryCache) Close() error {
+	return nil
+}
+
+// view runs fn against the summaries bucket in a read-only transaction. It holds a shared
+// lock, so readers don't wait for each other; a database or bucket that doesn't exist yet
+// is treated as empty.
+func (c *BoltSummaryCache) view(fn func(bucket *bolt.Bucket) error) error {
+	if _, err := os.Stat(c.path); os.IsNotExist(err) {
+		return nil
+	}
+	db, err := bolt.Open(c.path, 0644, &bolt.Options{Timeout: boltLockTimeout, ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("failed to open summary database %s: %w", c.path, err)
+	}
+	defer db.Close()
+
+	return db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(boltSummariesBucket)
+		if bucket == nil {
+			return nil
+		}
+		return fn(bucket)
+	})
+}
+
+// update runs fn against the summaries bucket in a read-write transaction
+func (c *BoltSummaryCache) update(fn func(bucket *bolt.Bucket) error) error {
+	return c.updateTx(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(boltSummariesBucket)
+		if err != nil {
+			return fmt.Errorf("failed to create summaries bucket: %w", err)
+		}
+		return fn(bucket)
+	})
+}
+
+func (c *BoltSummaryCache) updateTx(fn func(tx *bolt.Tx) error) error {
+	db, err := bolt.Open(c.path, 0644, &bolt.Options{Timeout: boltLockTimeout})
+	if err != nil {
+		return fmt.Errorf("failed to open summary database %s: %w", c.path, err)
+	}
+	defer db.Close()
+
+	return db.Update(fn)
+}
@@ -0,0 +1,218 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/penwyp/claudecat/logging"
+)
+
+const (
+	// DefaultDedupRetention is how long dedup records are kept when no retention is configured
+	DefaultDedupRetention = 30 * 24 * time.Hour
+
+	dedupIndexFileName    = "dedup_index.json"
+	dedupIndexVersion     = 1
+	dedupCompactInterval  = time.Hour
+	dedupIndexPermissions = 0644
+)
+
+// DedupRecord remembers which file an entry was first counted from
+type DedupRecord struct {
+	File      string    `json:"file"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// dedupIndexData is the on-disk format of the dedup index
+type dedupIndexData struct {
+	Version     int                    `json:"version"`
+	CompactedAt time.Time              `json:"compacted_at"`
+	Records     map[string]DedupRecord `json:"records"`
+}
+
+// DedupIndex is a persistent message+request ID index that survives restarts.
+//
+// Each entry key is owned by the first file it was counted from. Other files carrying the
+// same entry skip it, even when the owning file is served from a cached summary and its
+// entries are never re-read. Records older than the retention horizon, and records whose
+// owning file no longer exists, are dropped by compaction; another copy of a dropped entry
+// is counted the next time its file is re-read.
+type DedupIndex struct {
+	path        string
+	retention   time.Duration
+	records     map[string]DedupRecord
+	dropped     map[string]bool // Keys compacted away since the last save
+	compactedAt time.Time
+	dirty       bool
+	mu          sync.Mutex
+}
+
+// DedupKey returns the index key for a message and request ID pair
+func DedupKey(messageID, requestID string) string {
+	return messageID + ":" + requestID
+}
+
+// OpenDedupIndex loads the dedup index from cacheDir, starting empty if it doesn't exist or
+// can't be read. A retention of zero or less uses DefaultDedupRetention.
+func OpenDedupIndex(cacheDir string, retention time.Duration) (*DedupIndex, error) {
+	if retention <= 0 {
+		retention = DefaultDedupRetention
+	}
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	index := &DedupIndex{
+		path:      filepath.Join(cacheDir, dedupIndexFileName),
+		retention: retention,
+		records:   make(map[string]DedupRecord),
+		dropped:   make(map[string]bool),
+	}
+
+	data, err := os.ReadFile(index.path)
+	switch {
+	case os.IsNotExist(err):
+		return index, nil
+	case err != nil:
+		logging.LogWarnf("Failed to read dedup index %s, starting empty: %v", index.path, err)
+		return index, nil
+	}
+
+	var stored dedupIndexData
+	if err := json.Unmarshal(data, &stored); err != nil || stored.Version != dedupIndexVersion {
+		logging.LogWarnf("Ignoring unreadable dedup index %s (version %d): %v", index.path, stored.Version, err)
+		return index, nil
+	}
+	if stored.Records != nil {
+		index.records = stored.Records
+	}
+	index.compactedAt = stored.CompactedAt
+
+	logging.LogDebugf("Loaded dedup index with %d records from %s", len(index.records), index.path)
+	return index, nil
+}
+
+// Claim reports whether file may count the entry with the given key. The first file to
+// claim a key owns it; later claims from the same file succeed, claims from others fail.
+func (d *DedupIndex) Claim(key, file string, timestamp time.Time) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if record, ok := d.records[key]; ok {
+		return record.File == file
+	}
+	d.records[key] = DedupRecord{File: file, Timestamp: timestamp}
+	d.dirty = true
+	return true
+}
+
+// Compact drops records older than the retention horizon and records whose owning file no
+// longer exists. It returns the number of records removed.
+func (d *DedupIndex) Compact(now time.Time) int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	cutoff := now.Add(-d.retention)
+	fileExists := make(map[string]bool)
+	removed := 0
+	for key, record := range d.records {
+		exists, checked := fileExists[record.File]
+		if !checked {
+			_, err := os.Stat(record.File)
+			exists = err == nil
+			fileExists[record.File] = exists
+		}
+		if !exists || record.Timestamp.Before(cutoff) {
+			delete(d.records, key)
+			d.dropped[key] = true
+			removed++
+		}
+	}
+
+	d.compactedAt = now
+	d.dirty = true
+	if removed > 0 {
+		logging.LogDebugf("Compacted dedup index: removed %d records, %d remaining", removed, len(d.records))
+	}
+	return removed
+}
+
+// CompactIfDue compacts the index when it hasn't been compacted in the last hour
+func (d *DedupIndex) CompactIfDue(now time.Time) int {
+	d.mu.Lock()
+	due := now.Sub(d.compactedAt) >= dedupCompactInterval
+	d.mu.Unlock()
+
+	if !due {
+		return 0
+	}
+	return d.Compact(now)
+}
+
+// Len returns the number of records in the index
+func (d *DedupIndex) Len() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.records)
+}
+
+// Save writes the index to disk if it changed since it was loaded or last saved. Records
+// saved by other processes in the meantime are kept; where two processes claimed the same
+// key, the claim saved first wins.
+func (d *DedupIndex) Save() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if !d.dirty {
+		return nil
+	}
+
+	// Another process sharing the cache may have saved claims since this index was loaded
+	d.mergeSaved()
+
+	data, err := json.Marshal(dedupIndexData{
+		Version:     dedupIndexVersion,
+		CompactedAt: d.compactedAt,
+		Records:     d.records,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal dedup index: %w", err)
+	}
+
+	// A crash never leaves a truncated index
+	if err := writeFileAtomic(d.path, data, dedupIndexPermissions); err != nil {
+		return fmt.Errorf("failed to save dedup index: %w", err)
+	}
+
+	d.dirty = false
+	d.dropped = make(map[string]bool)
+	return nil
+}
+
+// mergeSaved adds the records of the index on disk that this one doesn't have, skipping
+// ones past the retention horizon or compacted away here. The caller holds d.mu.
+func (d *DedupIndex) mergeSaved() {
+	data, err := os.ReadFile(d.path)
+	if err != nil {
+		return
+	}
+	var stored dedupIndexData
+	if err := json.Unmarshal(data, &stored); err != nil || stored.Version != dedupIndexVersion {
+		return
+	}
+
+	cutoff := time.Now().Add(-d.retention)
+	for key, record := range stored.Records {
+		if record.Timestamp.Before(cutoff) || d.dropped[key] {
+			continue
+		}
+		d.records[key] = record
+	}
+	if stored.CompactedAt.After(d.compactedAt) {
+		d.compactedAt = stored.CompactedAt
+	}
+}
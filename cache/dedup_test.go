@@ -0,0 +1,60 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDedupIndex_Compact(t *testing.T) {
+	dir := t.TempDir()
+	owner := filepath.Join(dir, "owner.jsonl")
+	require.NoError(t, os.WriteFile(owner, nil, 0644))
+
+	index, err := OpenDedupIndex(dir, 24*time.Hour)
+	require.NoError(t, err)
+
+	now := time.Now()
+	assert.True(t, index.Claim("recent", owner, now))
+	assert.True(t, index.Claim("old", owner, now.Add(-48*time.Hour)))
+	assert.True(t, index.Claim("orphan", filepath.Join(dir, "deleted.jsonl"), now))
+	assert.True(t, index.Claim("recent", owner, now), "the owner can claim its entry again")
+	assert.False(t, index.Claim("recent", filepath.Join(dir, "other.jsonl"), now))
+
+	assert.Equal(t, 2, index.Compact(now))
+	require.NoError(t, index.Save())
+
+	reopened, err := OpenDedupIndex(dir, 24*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 1, reopened.Len())
+	assert.Equal(t, 0, reopened.CompactIfDue(now.Add(time.Minute)), "compaction is throttled")
+	assert.False(t, reopened.Claim("recent", filepath.Join(dir, "other.jsonl"), now))
+}
+
+func TestDedupIndex_SaveKeepsOtherProcessesClaims(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+
+	monitor, err := OpenDedupIndex(dir, 24*time.Hour)
+	require.NoError(t, err)
+	command, err := OpenDedupIndex(dir, 24*time.Hour)
+	require.NoError(t, err)
+
+	assert.True(t, command.Claim("shared", "/data/a.jsonl", now))
+	assert.True(t, command.Claim("command-only", "/data/a.jsonl", now))
+	require.NoError(t, command.Save())
+
+	assert.True(t, monitor.Claim("shared", "/data/b.jsonl", now))
+	assert.True(t, monitor.Claim("monitor-only", "/data/b.jsonl", now))
+	require.NoError(t, monitor.Save())
+
+	reopened, err := OpenDedupIndex(dir, 24*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 3, reopened.Len())
+	assert.True(t, reopened.Claim("shared", "/data/a.jsonl", now), "the claim saved first wins")
+	assert.True(t, reopened.Claim("monitor-only", "/data/b.jsonl", now))
+}
@@ -2,7 +2,6 @@ package cache
 
 import (
 	"crypto/md5"
-	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -14,10 +13,15 @@ import (
 	"github.com/penwyp/claudecat/logging"
 )
 
+// staleTempFileAge is how old a temporary file must be before it counts as left behind by an
+// interrupted write rather than belonging to a write in progress
+const staleTempFileAge = time.Minute
+
 // FileBasedSummaryCache provides a file-based cache for file summaries with memory preloading
 type FileBasedSummaryCache struct {
 	baseDir  string
 	memCache map[string]*FileSummary // Memory cache for fast access
+	readOnly bool
 	mu       sync.RWMutex
 	stats    FileBasedCacheStats
 }
@@ -34,15 +38,24 @@ type FileBasedCacheStats struct {
 
 // NewFileBasedSummaryCache creates a new file-based summary cache
 func NewFileBasedSummaryCache(persistPath string) (*FileBasedSummaryCache, error) {
+	return newFileBasedSummaryCache(persistPath, false)
+}
+
+// newFileBasedSummaryCache opens the file-based summary cache. A read-only cache neither
+// creates its directory nor cleans up after interrupted writes.
+func newFileBasedSummaryCache(persistPath string, readOnly bool) (*FileBasedSummaryCache, error) {
 	// Create base directory if it doesn't exist
 	summariesDir := filepath.Join(persistPath, "summaries")
-	if err := os.MkdirAll(summariesDir, 0755); err != nil {
-		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	if !readOnly {
+		if err := os.MkdirAll(summariesDir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create cache directory: %w", err)
+		}
 	}
 
 	cache := &FileBasedSummaryCache{
 		baseDir:  summariesDir,
 		memCache: make(map[string]*FileSummary),
+		readOnly: readOnly,
 	}
 
 	// Preload existing summaries into memory
@@ -65,6 +78,17 @@ func (c *FileBasedSummaryCache) preloadSummaries() error {
 			return nil // Skip files with errors
 		}
 
+		// Temporary files of writes interrupted by a crash are never renamed into place
+		if !info.IsDir() && strings.HasSuffix(path, ".tmp") {
+			if c.readOnly || time.Since(info.ModTime()) <= staleTempFileAge {
+				return nil
+			}
+			if err := os.Remove(path); err == nil {
+				logging.LogDebugf("Removed temporary file of an interrupted write: %s", path)
+			}
+			return nil
+		}
+
 		if info.IsDir() || !strings.HasSuffix(path, ".json") {
 			return nil
 		}
@@ -76,14 +100,14 @@ func (c *FileBasedSummaryCache) preloadSummaries() error {
 			return nil // Skip this file
 		}
 
-		var summary FileSummary
-		if err := json.Unmarshal(data, &summary); err != nil {
-			logging.LogDebugf("Failed to unmarshal cache file %s: %v", path, err)
+		summary, err := decodeSummary(data)
+		if err != nil {
+			logging.LogDebugf("Skipping unreadable cache file %s: %v", path, err)
 			return nil // Skip this file
 		}
 
 		// Add to memory cache
-		c.memCache[summary.AbsolutePath] = &summary
+		c.memCache[summary.AbsolutePath] = summary
 		count++
 
 		if count%100 == 0 {
@@ -153,29 +177,44 @@ func (c *FileBasedSummaryCache) GetFileSummary(absolutePath string) (*FileSummar
 		return nil, fmt.Errorf("failed to read cache file: %w", err)
 	}
 
-	var summary FileSummary
-	if err := json.Unmarshal(data, &summary); err != nil {
+	summary, err := decodeSummary(data)
+	if err != nil {
 		c.stats.Errors++
-		return nil, fmt.Errorf("failed to unmarshal summary: %w", err)
+		return nil, err
 	}
 
 	// Add to memory cache
-	c.memCache[absolutePath] = &summary
+	c.memCache[absolutePath] = summary
 	c.stats.Hits++
 
-	return &summary, nil
+	return summary, nil
 }
 
 // SetFileSummary stores a file summary in cache
 func (c *FileBasedSummaryCache) SetFileSummary(summary *FileSummary) error {
+	if c.readOnly {
+		return ErrReadOnly
+	}
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	// Another process may have stored a summary of a later state of the file since this
+	// cache was preloaded. The check and the rename below aren't atomic across processes, but
+	// a summary that loses the race only costs a re-parse, as loaders check it against the file.
+	cacheFile := c.getCacheFilePath(summary.AbsolutePath)
+	if data, err := os.ReadFile(cacheFile); err == nil {
+		if stored, err := decodeSummary(data); err == nil && summarizesLaterState(stored, summary) {
+			logging.LogDebugf("Keeping the newer cached summary of %s", summary.AbsolutePath)
+			c.memCache[summary.AbsolutePath] = stored
+			return nil
+		}
+	}
+
 	// Update memory cache
 	c.memCache[summary.AbsolutePath] = summary
 
 	// Write to disk
-	cacheFile := c.getCacheFilePath(summary.AbsolutePath)
 	cacheDir := filepath.Dir(cacheFile)
 
 	// Create subdirectory if needed
@@ -184,26 +223,17 @@ func (c *FileBasedSummaryCache) SetFileSummary(summary *FileSummary) error {
 		return fmt.Errorf("failed to create cache subdirectory: %w", err)
 	}
 
-	data, err := json.MarshalIndent(summary, "", "  ")
+	data, err := encodeSummary(summary)
 	if err != nil {
 		c.stats.Errors++
-		return fmt.Errorf("failed to marshal summary: %w", err)
+		return err
 	}
 
-	// Write to temporary file first
-	tmpFile := cacheFile + ".tmp"
-	if err := os.WriteFile(tmpFile, data, 0644); err != nil {
+	if err := writeFileAtomic(cacheFile, data, 0644); err != nil {
 		c.stats.Errors++
 		return fmt.Errorf("failed to write cache file: %w", err)
 	}
 
-	// Atomic rename
-	if err := os.Rename(tmpFile, cacheFile); err != nil {
-		c.stats.Errors++
-		os.Remove(tmpFile) // Clean up
-		return fmt.Errorf("failed to rename cache file: %w", err)
-	}
-
 	c.stats.Writes++
 	return nil
 }
@@ -219,6 +249,10 @@ func (c *FileBasedSummaryCache) HasFileSummary(absolutePath string) bool {
 
 // InvalidateFileSummary removes a file summary from cache
 func (c *FileBasedSummaryCache) InvalidateFileSummary(absolutePath string) error {
+	if c.readOnly {
+		return ErrReadOnly
+	}
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
@@ -238,6 +272,19 @@ func (c *FileBasedSummaryCache) InvalidateFileSummary(absolutePath string) error
 	return nil
 }
 
+// FindByIdentity returns a preloaded summary with the given identity
+func (c *FileBasedSummaryCache) FindByIdentity(identity string) (*FileSummary, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, summary := range c.memCache {
+		if summary.Identity == identity {
+			return summary, nil
+		}
+	}
+	return nil, errIdentityNotFound(identity)
+}
+
 // IsFileChanged checks if a file has changed based on modTime and size
 func (c *FileBasedSummaryCache) IsFileChanged(filePath string, stat os.FileInfo) bool {
 	summary, err := c.GetFileSummary(filePath)
@@ -261,6 +308,10 @@ func (c *FileBasedSummaryCache) BatchSet(summaries []*FileSummary) error {
 
 // Clear removes all summaries from cache
 func (c *FileBasedSummaryCache) Clear() error {
+	if c.readOnly {
+		return ErrReadOnly
+	}
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
@@ -342,3 +393,100 @@ func (c *FileBasedSummaryCache) Close() error {
 	// Nothing to close for file-based cache
 	return nil
 }
+
+// Scan calls fn for every summary file on disk
+func (c *FileBasedSummaryCache) Scan(fn func(stored StoredSummary) error) error {
+	return filepath.Walk(c.baseDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || !strings.HasSuffix(path, ".json") {
+			return nil
+		}
+
+		stored := StoredSummary{Key: path}
+		if data, err := os.ReadFile(path); err != nil {
+			stored.Err = fmt.Errorf("failed to read cache file: %w", err)
+		} else {
+			stored.Summary, stored.Err = decodeSummary(data)
+		}
+		return fn(stored)
+	})
+}
+
+// Remove deletes the summary file at key, a path returned by Scan
+func (c *FileBasedSummaryCache) Remove(key string) error {
+	if c.readOnly {
+		return ErrReadOnly
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for absolutePath := range c.memCache {
+		if c.getCacheFilePath(absolutePath) == key {
+			delete(c.memCache, absolutePath)
+		}
+	}
+	if err := os.Remove(key); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete cache file: %w", err)
+	}
+	c.stats.Deletes++
+	return nil
+}
+
+// StorageSize returns the total size of the summary files
+func (c *FileBasedSummaryCache) StorageSize() (int64, error) {
+	var size int64
+	err := filepath.Walk(c.baseDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			// A read-only cache may not have created its directory yet
+			if path == c.baseDir && os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if !info.IsDir() {
+			size += info.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to walk cache directory: %w", err)
+	}
+	return size, nil
+}
+
+// Compact removes temporary files left by interrupted writes and empty subdirectories
+func (c *FileBasedSummaryCache) Compact() error {
+	if c.readOnly {
+		return ErrReadOnly
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entries, err := os.ReadDir(c.baseDir)
+	if err != nil {
+		return fmt.Errorf("failed to read cache directory: %w", err)
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		subDir := filepath.Join(c.baseDir, entry.Name())
+		files, err := os.ReadDir(subDir)
+		if err != nil {
+			continue
+		}
+		remaining := len(files)
+		for _, file := range files {
+			if strings.HasSuffix(file.Name(), ".tmp") {
+				if err := os.Remove(filepath.Join(subDir, file.Name())); err == nil {
+					remaining--
+				}
+			}
+		}
+		if remaining == 0 {
+			os.Remove(subDir)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package cache
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/penwyp/claudecat/logging"
+)
+
+// SummaryStatus classifies a stored summary against the file it summarizes
+type SummaryStatus string
+
+const (
+	SummaryUpToDate SummaryStatus = "up_to_date" // File unchanged since it was summarized
+	SummaryOutdated SummaryStatus = "outdated"   // File modified since it was summarized
+	SummaryDeleted  SummaryStatus = "deleted"    // File no longer exists
+	SummaryCorrupt  SummaryStatus = "corrupt"    // Stored data can't be decoded
+)
+
+// StoreReport describes the contents of a summary store
+type StoreReport struct {
+	Summaries    int   `json:"summaries"` // All stored summaries, including corrupt ones
+	UpToDate     int   `json:"up_to_date"`
+	Outdated     int   `json:"outdated"`
+	Deleted      int   `json:"deleted"`
+	Corrupt      int   `json:"corrupt"`
+	StorageBytes int64 `json:"storage_bytes"`
+
+	// Coverage of the usage files passed to InspectStore
+	UsageFiles      int     `json:"usage_files"`
+	CoveredFiles    int     `json:"covered_files"`     // Usage files with an up-to-date summary
+	ExpectedHitRate float64 `json:"expected_hit_rate"` // Share of usage files the next load reads from cache
+}
+
+// InspectStore classifies every stored summary and measures how many of usageFiles the
+// next load can serve from the cache
+func InspectStore(store SummaryStore, usageFiles []string) (*StoreReport, error) {
+	report := &StoreReport{}
+	upToDate := make(map[string]bool)
+
+	err := store.Scan(func(stored StoredSummary) error {
+		report.Summaries++
+		switch classifySummary(stored) {
+		case SummaryUpToDate:
+			report.UpToDate++
+			upToDate[stored.Summary.AbsolutePath] = true
+		case SummaryOutdated:
+			report.Outdated++
+		case SummaryDeleted:
+			report.Deleted++
+		case SummaryCorrupt:
+			report.Corrupt++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan summaries: %w", err)
+	}
+
+	if report.StorageBytes, err = store.StorageSize(); err != nil {
+		return nil, err
+	}
+
+	report.UsageFiles = len(usageFiles)
+	for _, file := range usageFiles {
+		if absPath, err := filepath.Abs(file); err == nil && upToDate[absPath] {
+			report.CoveredFiles++
+		}
+	}
+	if report.UsageFiles > 0 {
+		report.ExpectedHitRate = float64(report.CoveredFiles) / float64(report.UsageFiles)
+	}
+	return report, nil
+}
+
+// PruneStore removes the summaries whose status is one of statuses and returns how many
+// were removed. Removed summaries of existing files are rebuilt by the next load.
+func PruneStore(store SummaryStore, statuses ...SummaryStatus) (int, error) {
+	prune := make(map[SummaryStatus]bool, len(statuses))
+	for _, status := range statuses {
+		prune[status] = true
+	}
+
+	// Collect keys first; backends may hold locks while scanning
+	var keys []string
+	err := store.Scan(func(stored StoredSummary) error {
+		if prune[classifySummary(stored)] {
+			keys = append(keys, stored.Key)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to scan summaries: %w", err)
+	}
+
+	for i, key := range keys {
+		if err := store.Remove(key); err != nil {
+			return i, err
+		}
+	}
+	return len(keys), nil
+}
+
+// RecoverStore removes corrupt summaries, such as ones cut short by a crash or failing
+// their checksum, so the next load rebuilds them from the logs. It returns how many were
+// removed.
+func RecoverStore(store SummaryStore) (int, error) {
+	removed, err := PruneStore(store, SummaryCorrupt)
+	if removed > 0 {
+		logging.LogWarnf("Removed %d corrupt summaries from the cache, they are rebuilt from the logs", removed)
+	}
+	if err != nil {
+		return removed, fmt.Errorf("failed to recover summary cache: %w", err)
+	}
+	return removed, nil
+}
+
+// classifySummary compares a stored summary with the file on disk
+func classifySummary(stored StoredSummary) SummaryStatus {
+	if stored.Err != nil || stored.Summary == nil {
+		return SummaryCorrupt
+	}
+
+	info, err := os.Stat(stored.Summary.AbsolutePath)
+	if err != nil {
+		return SummaryDeleted
+	}
+	if stored.Summary.IsExpired(info.ModTime(), info.Size()) {
+		return SummaryOutdated
+	}
+	return SummaryUpToDate
+}
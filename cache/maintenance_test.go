@@ -0,0 +1,112 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInspectAndPruneStore(t *testing.T) {
+	for _, backend := range []string{BackendFile, BackendBolt, BackendSQLite} {
+		t.Run(backend, func(t *testing.T) {
+			dataDir := t.TempDir()
+			current := filepath.Join(dataDir, "current.jsonl")
+			changed := filepath.Join(dataDir, "changed.jsonl")
+			uncached := filepath.Join(dataDir, "uncached.jsonl")
+			for _, path := range []string{current, changed, uncached} {
+				require.NoError(t, os.WriteFile(path, []byte("{}\n"), 0644))
+			}
+			info, err := os.Stat(current)
+			require.NoError(t, err)
+
+			store, err := OpenSummaryStore(BackendConfig{Backend: backend, Dir: t.TempDir()})
+			require.NoError(t, err)
+			defer store.Close()
+
+			require.NoError(t, store.BatchSet([]*FileSummary{
+				{AbsolutePath: current, ModTime: info.ModTime(), FileSize: info.Size()},
+				{AbsolutePath: changed, ModTime: info.ModTime().Add(-time.Hour), FileSize: info.Size()},
+				{AbsolutePath: filepath.Join(dataDir, "deleted.jsonl"), ModTime: info.ModTime(), FileSize: 3},
+			}))
+
+			report, err := InspectStore(store, []string{current, changed, uncached})
+			require.NoError(t, err)
+			assert.Equal(t, 3, report.Summaries)
+			assert.Equal(t, 1, report.UpToDate)
+			assert.Equal(t, 1, report.Outdated)
+			assert.Equal(t, 1, report.Deleted)
+			assert.Equal(t, 0, report.Corrupt)
+			assert.Equal(t, 3, report.UsageFiles)
+			assert.Equal(t, 1, report.CoveredFiles)
+			assert.InDelta(t, 1.0/3, report.ExpectedHitRate, 0.001)
+			assert.Positive(t, report.StorageBytes)
+
+			removed, err := PruneStore(store, SummaryDeleted, SummaryOutdated)
+			require.NoError(t, err)
+			assert.Equal(t, 2, removed)
+			assert.True(t, store.HasFileSummary(current))
+			assert.False(t, store.HasFileSummary(changed))
+
+			require.NoError(t, store.Compact())
+			report, err = InspectStore(store, nil)
+			require.NoError(t, err)
+			assert.Equal(t, 1, report.Summaries)
+			assert.Equal(t, 1, report.UpToDate)
+		})
+	}
+}
+
+func TestPruneStore_CorruptFileSummary(t *testing.T) {
+	cacheDir := t.TempDir()
+	store, err := NewFileBasedSummaryCache(cacheDir)
+	require.NoError(t, err)
+
+	corrupt := filepath.Join(cacheDir, "summaries", "ab", "abcdef.json")
+	require.NoError(t, os.MkdirAll(filepath.Dir(corrupt), 0755))
+	require.NoError(t, os.WriteFile(corrupt, []byte("{not json"), 0644))
+
+	report, err := InspectStore(store, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Corrupt)
+
+	removed, err := PruneStore(store, SummaryCorrupt)
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+	assert.NoFileExists(t, corrupt)
+}
+
+func TestRecoverStore_FileSummary(t *testing.T) {
+	cacheDir := t.TempDir()
+	store, err := NewFileBasedSummaryCache(cacheDir)
+	require.NoError(t, err)
+	require.NoError(t, store.BatchSet([]*FileSummary{
+		{AbsolutePath: "/data/intact.jsonl", TotalTokens: 100},
+		{AbsolutePath: "/data/damaged.jsonl", TotalTokens: 200},
+	}))
+
+	// Damage one summary and leave a temporary file behind, as a crash mid-write could
+	damaged := store.getCacheFilePath("/data/damaged.jsonl")
+	data, err := os.ReadFile(damaged)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(damaged, []byte(strings.Replace(string(data), "200", "900", 1)), 0644))
+	leftover := damaged + ".123.tmp"
+	require.NoError(t, os.WriteFile(leftover, data[:len(data)/2], 0644))
+	old := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(leftover, old, old))
+
+	store, err = NewFileBasedSummaryCache(cacheDir)
+	require.NoError(t, err)
+	assert.NoFileExists(t, leftover)
+	assert.False(t, store.HasFileSummary("/data/damaged.jsonl"), "damaged summary is not served")
+
+	removed, err := RecoverStore(store)
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+	assert.NoFileExists(t, damaged)
+	assert.True(t, store.HasFileSummary("/data/intact.jsonl"))
+}
@@ -0,0 +1,357 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/penwyp/claudecat/logging"
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultRedisKeyPrefix prefixes the keys of summaries stored in redis
+const DefaultRedisKeyPrefix = "claudecat:summary:"
+
+// redisTimeout bounds each redis operation so an unreachable server can't stall loading
+const redisTimeout = 2 * time.Second
+
+// redisIdentityIndex is the key, after the key prefix, of the hash mapping file identities
+// to the paths whose summaries have them. Absolute paths never start with '#', so it
+// can't be a summary's key.
+const redisIdentityIndex = "#identities"
+
+// redisWriteAttempts is how often a write is tried while other clients keep changing the
+// summaries it stores
+const redisWriteAttempts = 3
+
+// RedisSummaryCache stores file summaries in redis, keyed by absolute path, so machines
+// that mount the same conversation logs can share one summary cache
+type RedisSummaryCache struct {
+	client   *redis.Client
+	prefix   string
+	ttl      time.Duration
+	readOnly bool
+}
+
+// NewRedisSummaryCache connects to the redis server at url. An empty prefix uses
+// DefaultRedisKeyPrefix and a ttl of zero keeps summaries until they are invalidated.
+func NewRedisSummaryCache(url, prefix string, ttl time.Duration) (*RedisSummaryCache, error) {
+	return newRedisSummaryCache(url, prefix, ttl, false)
+}
+
+// newRedisSummaryCache connects to the redis server at url, refusing writes when readOnly
+func newRedisSummaryCache(url, prefix string, ttl time.Duration, readOnly bool) (*RedisSummaryCache, error) {
+	if url == "" {
+		return nil, fmt.Errorf("redis cache backend requires a redis URL")
+	}
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redis URL: %w", err)
+	}
+	if prefix == "" {
+		prefix = DefaultRedisKeyPrefix
+	}
+
+	client := redis.NewClient(opts)
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to connect to redis at %s: %w", opts.Addr, err)
+	}
+
+	c := &RedisSummaryCache{client: client, prefix: prefix, ttl: ttl, readOnly: readOnly}
+	if !readOnly {
+		if err := c.indexIdentities(); err != nil {
+			client.Close()
+			return nil, err
+		}
+	}
+
+	logging.LogInfof("Initialized redis cache at %s with key prefix %q", opts.Addr, prefix)
+	return c, nil
+}
+
+// indexIdentities builds the identity index from the stored summaries when there is none,
+// as with summaries stored by earlier versions
+func (c *RedisSummaryCache) indexIdentities() error {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+	count, err := c.client.Exists(ctx, c.identityKey()).Result()
+	if err != nil {
+		return fmt.Errorf("failed to read identity index: %w", err)
+	}
+	if count > 0 {
+		return nil
+	}
+
+	index := make(map[string]interface{})
+	err = c.Scan(func(stored StoredSummary) error {
+		if stored.Summary != nil && stored.Summary.Identity != "" {
+			index[stored.Summary.Identity] = stored.Summary.AbsolutePath
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(index) == 0 {
+		return nil
+	}
+	if err := c.client.HSet(context.Background(), c.identityKey(), index).Err(); err != nil {
+		return fmt.Errorf("failed to store identity index: %w", err)
+	}
+	return nil
+}
+
+// GetFileSummary retrieves a file summary from cache
+func (c *RedisSummaryCache) GetFileSummary(absolutePath string) (*FileSummary, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	data, err := c.client.Get(ctx, c.key(absolutePath)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, errSummaryNotFound(absolutePath)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read summary: %w", err)
+	}
+
+	return decodeSummary(data)
+}
+
+// SetFileSummary stores a file summary in cache
+func (c *RedisSummaryCache) SetFileSummary(summary *FileSummary) error {
+	return c.BatchSet([]*FileSummary{summary})
+}
+
+// BatchSet stores several summaries in one pipeline
+func (c *RedisSummaryCache) BatchSet(summaries []*FileSummary) error {
+	if len(summaries) == 0 {
+		return nil
+	}
+
+	if c.readOnly {
+		return ErrReadOnly
+	}
+
+	keys := make([]string, len(summaries))
+	encoded := make([][]byte, len(summaries))
+	for i, summary := range summaries {
+		data, err := encodeSummary(summary)
+		if err != nil {
+			return err
+		}
+		keys[i] = c.key(summary.AbsolutePath)
+		encoded[i] = data
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	// Watching the keys makes the write fail, and be retried, when another client stores
+	// one of them between checking for newer summaries and writing
+	store := func(tx *redis.Tx) error {
+		existing, err := tx.MGet(ctx, keys...).Result()
+		if err != nil {
+			return err
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			for i, summary := range summaries {
+				if data, ok := existing[i].(string); ok {
+					if stored, err := decodeSummary([]byte(data)); err == nil && summarizesLaterState(stored, summary) {
+						continue
+					}
+				}
+				pipe.Set(ctx, keys[i], encoded[i], c.ttl)
+				if summary.Identity != "" {
+					pipe.HSet(ctx, c.identityKey(), summary.Identity, summary.AbsolutePath)
+				}
+			}
+			return nil
+		})
+		return err
+	}
+
+	var err error
+	for attempt := 0; attempt < redisWriteAttempts; attempt++ {
+		if err = c.client.Watch(ctx, store, keys...); !errors.Is(err, redis.TxFailedErr) {
+			break
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to store summaries: %w", err)
+	}
+	return nil
+}
+
+// HasFileSummary checks if a file summary exists in cache
+func (c *RedisSummaryCache) HasFileSummary(absolutePath string) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	count, err := c.client.Exists(ctx, c.key(absolutePath)).Result()
+	return err == nil && count > 0
+}
+
+// InvalidateFileSummary removes a file summary from cache
+func (c *RedisSummaryCache) InvalidateFileSummary(absolutePath string) error {
+	if c.readOnly {
+		return ErrReadOnly
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	if err := c.client.Del(ctx, c.key(absolutePath)).Err(); err != nil {
+		return fmt.Errorf("failed to delete summary: %w", err)
+	}
+	return nil
+}
+
+// Clear removes every summary under the key prefix
+func (c *RedisSummaryCache) Clear() error {
+	if c.readOnly {
+		return ErrReadOnly
+	}
+
+	ctx := context.Background()
+	iter := c.client.Scan(ctx, 0, c.prefix+"*", 500).Iterator()
+
+	var keys []string
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+		if len(keys) == 500 {
+			if err := c.client.Unlink(ctx, keys...).Err(); err != nil {
+				return fmt.Errorf("failed to clear summaries: %w", err)
+			}
+			keys = keys[:0]
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return fmt.Errorf("failed to list summaries: %w", err)
+	}
+	if len(keys) > 0 {
+		if err := c.client.Unlink(ctx, keys...).Err(); err != nil {
+			return fmt.Errorf("failed to clear summaries: %w", err)
+		}
+	}
+
+	logging.LogInfof("Cache cleared")
+	return nil
+}
+
+// Scan calls fn for every summary under the key prefix
+func (c *RedisSummaryCache) Scan(fn func(stored StoredSummary) error) error {
+	ctx := context.Background()
+	iter := c.client.Scan(ctx, 0, c.prefix+"*", 500).Iterator()
+	for iter.Next(ctx) {
+		key := iter.Val()
+		if key == c.identityKey() {
+			continue
+		}
+		stored := StoredSummary{Key: key}
+		data, err := c.client.Get(ctx, key).Bytes()
+		if errors.Is(err, redis.Nil) {
+			continue // Expired or removed while scanning
+		}
+		if err != nil {
+			stored.Err = fmt.Errorf("failed to read summary: %w", err)
+		} else {
+			stored.Summary, stored.Err = decodeSummary(data)
+		}
+		if err := fn(stored); err != nil {
+			return err
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return fmt.Errorf("failed to list summaries: %w", err)
+	}
+	return nil
+}
+
+// FindByIdentity returns a stored summary with the given identity, looked up in the
+// identity index
+func (c *RedisSummaryCache) FindByIdentity(identity string) (*FileSummary, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	path, err := c.client.HGet(ctx, c.identityKey(), identity).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, errIdentityNotFound(identity)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read identity index: %w", err)
+	}
+
+	data, err := c.client.Get(ctx, c.key(path)).Bytes()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, fmt.Errorf("failed to read summary: %w", err)
+	}
+	if err == nil {
+		if summary, err := decodeSummary(data); err == nil && summary.Identity == identity {
+			return summary, nil
+		}
+	}
+
+	// The summary expired, was removed or now describes another file
+	if !c.readOnly {
+		c.client.HDel(ctx, c.identityKey(), identity)
+	}
+	return nil, errIdentityNotFound(identity)
+}
+
+// Remove deletes the redis key returned by Scan
+func (c *RedisSummaryCache) Remove(key string) error {
+	if c.readOnly {
+		return ErrReadOnly
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	if err := c.client.Del(ctx, key).Err(); err != nil {
+		return fmt.Errorf("failed to delete summary: %w", err)
+	}
+	return nil
+}
+
+// StorageSize returns the combined length of the stored summaries
+func (c *RedisSummaryCache) StorageSize() (int64, error) {
+	ctx := context.Background()
+	var size int64
+	iter := c.client.Scan(ctx, 0, c.prefix+"*", 500).Iterator()
+	for iter.Next(ctx) {
+		if iter.Val() == c.identityKey() {
+			continue
+		}
+		length, err := c.client.StrLen(ctx, iter.Val()).Result()
+		if err != nil {
+			return 0, fmt.Errorf("failed to measure summary: %w", err)
+		}
+		size += length
+	}
+	if err := iter.Err(); err != nil {
+		return 0, fmt.Errorf("failed to list summaries: %w", err)
+	}
+	return size, nil
+}
+
+// Compact is a no-op; redis frees memory as keys are deleted
+func (c *RedisSummaryCache) Compact() error {
+	return nil
+}
+
+// Close closes the redis connection pool
+func (c *RedisSummaryCache) Close() error {
+	return c.client.Close()
+}
+
+func (c *RedisSummaryCache) key(absolutePath string) string {
+	return c.prefix + absolutePath
+}
+
+func (c *RedisSummaryCache) identityKey() string {
+	return c.prefix + redisIdentityIndex
+}
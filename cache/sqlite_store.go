@@ -0,0 +1,306 @@
+package cache
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/penwyp/claudecat/logging"
+
+	// Registers the sqlite3 driver; binaries built without cgo report an error when opening
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// SQLiteDBFileName is the name of the SQLite summary database inside the cache directory
+const SQLiteDBFileName = "summaries.sqlite"
+
+// SQLiteSummaryCache stores file summaries in a SQLite database. Lookups go straight to the
+// database, so memory use doesn't grow with the number of cached files.
+type SQLiteSummaryCache struct {
+	path     string
+	db       *sql.DB
+	readOnly bool
+	indexed  bool // The table has the identity column, missing from databases of earlier versions opened read-only
+}
+
+// NewSQLiteSummaryCache opens (or creates) the SQLite summary database in cacheDir
+func NewSQLiteSummaryCache(cacheDir string) (*SQLiteSummaryCache, error) {
+	return newSQLiteSummaryCache(cacheDir, false)
+}
+
+// newSQLiteSummaryCache opens the SQLite summary database in cacheDir. A read-only cache
+// requires the database to exist already.
+func newSQLiteSummaryCache(cacheDir string, readOnly bool) (*SQLiteSummaryCache, error) {
+	path := filepath.Join(cacheDir, SQLiteDBFileName)
+	if readOnly {
+		if _, err := os.Stat(path); err != nil {
+			return nil, fmt.Errorf("failed to open summary database %s: %w", path, err)
+		}
+		db, err := sql.Open("sqlite3", "file:"+path+"?mode=ro&_busy_timeout=2000")
+		if err != nil {
+			return nil, fmt.Errorf("failed to open summary database %s: %w", path, err)
+		}
+		c := &SQLiteSummaryCache{path: path, db: db, readOnly: true}
+		if c.indexed, err = c.hasIdentityColumn(); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("failed to read summary database %s: %w", path, err)
+		}
+		logging.LogInfof("Opened SQLite cache at %s read-only", path)
+		return c, nil
+	}
+
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	// WAL lets the monitor and one-off commands read while the other writes. Transactions
+	// take the write lock up front, so a newer summary can't be stored between a write's
+	// check for one and the write itself.
+	db, err := sql.Open("sqlite3", "file:"+path+"?_journal_mode=WAL&_busy_timeout=2000&_txlock=immediate")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open summary database %s: %w", path, err)
+	}
+
+	_, err = db.Exec(`CREATE TABLE IF NOT EXISTS summaries (
+		absolute_path TEXT PRIMARY KEY,
+		data BLOB NOT NULL,
+		identity TEXT NOT NULL DEFAULT ''
+	)`)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create summaries table in %s: %w", path, err)
+	}
+
+	c := &SQLiteSummaryCache{path: path, db: db, indexed: true}
+	if err := c.addIdentityColumn(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to index summaries in %s: %w", path, err)
+	}
+
+	logging.LogInfof("Initialized SQLite cache at %s", path)
+	return c, nil
+}
+
+// hasIdentityColumn reports whether the summaries table has the identity column
+func (c *SQLiteSummaryCache) hasIdentityColumn() (bool, error) {
+	var count int
+	err := c.db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('summaries') WHERE name = 'identity'`).Scan(&count)
+	return count > 0, err
+}
+
+// addIdentityColumn adds the indexed identity column that renamed files are looked up by
+// to a table of an earlier version, filling it in from the stored summaries
+func (c *SQLiteSummaryCache) addIdentityColumn() error {
+	exists, err := c.hasIdentityColumn()
+	if err != nil {
+		return err
+	}
+	if !exists {
+		if _, err := c.db.Exec(`ALTER TABLE summaries ADD COLUMN identity TEXT NOT NULL DEFAULT ''`); err != nil {
+			return err
+		}
+		err := c.Scan(func(stored StoredSummary) error {
+			if stored.Summary == nil || stored.Summary.Identity == "" {
+				return nil
+			}
+			_, err := c.db.Exec(`UPDATE summaries SET identity = ? WHERE absolute_path = ?`, stored.Summary.Identity, stored.Key)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+	_, err = c.db.Exec(`CREATE INDEX IF NOT EXISTS summaries_identity ON summaries (identity)`)
+	return err
+}
+
+// GetFileSummary retrieves a file summary from cache
+func (c *SQLiteSummaryCache) GetFileSummary(absolutePath string) (*FileSummary, error) {
+	var data []byte
+	err := c.db.QueryRow(`SELECT data FROM summaries WHERE absolute_path = ?`, absolutePath).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, errSummaryNotFound(absolutePath)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read summary: %w", err)
+	}
+
+	return decodeSummary(data)
+}
+
+// SetFileSummary stores a file summary in cache
+func (c *SQLiteSummaryCache) SetFileSummary(summary *FileSummary) error {
+	return c.BatchSet([]*FileSummary{summary})
+}
+
+// BatchSet stores several summaries in one transaction
+func (c *SQLiteSummaryCache) BatchSet(summaries []*FileSummary) error {
+	if len(summaries) == 0 {
+		return nil
+	}
+	if c.readOnly {
+		return ErrReadOnly
+	}
+
+	tx, err := c.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query, err := tx.Prepare(`SELECT data FROM summaries WHERE absolute_path = ?`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare query: %w", err)
+	}
+	defer query.Close()
+	stmt, err := tx.Prepare(`INSERT OR REPLACE INTO summaries (absolute_path, data, identity) VALUES (?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, summary := range summaries {
+		var existing []byte
+		if err := query.QueryRow(summary.AbsolutePath).Scan(&existing); err == nil {
+			if stored, err := decodeSummary(existing); err == nil && summarizesLaterState(stored, summary) {
+				continue
+			}
+		}
+		data, err := encodeSummary(summary)
+		if err != nil {
+			return err
+		}
+		if _, err := stmt.Exec(summary.AbsolutePath, data, summary.Identity); err != nil {
+			return fmt.Errorf("failed to store summary for %s: %w", summary.AbsolutePath, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit summaries: %w", err)
+	}
+	return nil
+}
+
+// HasFileSummary checks if a file summary exists in cache
+func (c *SQLiteSummaryCache) HasFileSummary(absolutePath string) bool {
+	var exists int
+	err := c.db.QueryRow(`SELECT 1 FROM summaries WHERE absolute_path = ?`, absolutePath).Scan(&exists)
+	return err == nil
+}
+
+// InvalidateFileSummary removes a file summary from cache
+func (c *SQLiteSummaryCache) InvalidateFileSummary(absolutePath string) error {
+	if c.readOnly {
+		return ErrReadOnly
+	}
+
+	if _, err := c.db.Exec(`DELETE FROM summaries WHERE absolute_path = ?`, absolutePath); err != nil {
+		return fmt.Errorf("failed to delete summary: %w", err)
+	}
+	return nil
+}
+
+// Clear removes all summaries from cache
+func (c *SQLiteSummaryCache) Clear() error {
+	if c.readOnly {
+		return ErrReadOnly
+	}
+
+	if _, err := c.db.Exec(`DELETE FROM summaries`); err != nil {
+		return fmt.Errorf("failed to clear summaries: %w", err)
+	}
+	logging.LogInfof("Cache cleared")
+	return nil
+}
+
+// Scan calls fn for every summary in the database
+func (c *SQLiteSummaryCache) Scan(fn func(stored StoredSummary) error) error {
+	rows, err := c.db.Query(`SELECT absolute_path, data FROM summaries`)
+	if err != nil {
+		return fmt.Errorf("failed to list summaries: %w", err)
+	}
+	defer rows.Close()
+
+	// Collect first so fn can modify the table
+	var all []StoredSummary
+	for rows.Next() {
+		var key string
+		var data []byte
+		if err := rows.Scan(&key, &data); err != nil {
+			return fmt.Errorf("failed to read summary row: %w", err)
+		}
+		stored := StoredSummary{Key: key}
+		stored.Summary, stored.Err = decodeSummary(data)
+		all = append(all, stored)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to list summaries: %w", err)
+	}
+	rows.Close()
+
+	for _, stored := range all {
+		if err := fn(stored); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FindByIdentity returns a stored summary with the given identity
+func (c *SQLiteSummaryCache) FindByIdentity(identity string) (*FileSummary, error) {
+	if !c.indexed {
+		return findByIdentity(c.Scan, identity)
+	}
+	if identity == "" {
+		return nil, errIdentityNotFound(identity)
+	}
+
+	var data []byte
+	err := c.db.QueryRow(`SELECT data FROM summaries WHERE identity = ? LIMIT 1`, identity).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, errIdentityNotFound(identity)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read summary: %w", err)
+	}
+	return decodeSummary(data)
+}
+
+// Remove deletes the summary stored under key, the file path it summarizes
+func (c *SQLiteSummaryCache) Remove(key string) error {
+	return c.InvalidateFileSummary(key)
+}
+
+// StorageSize returns the size of the database file and its write-ahead log
+func (c *SQLiteSummaryCache) StorageSize() (int64, error) {
+	info, err := os.Stat(c.path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to stat summary database: %w", err)
+	}
+	size := info.Size()
+	if wal, err := os.Stat(c.path + "-wal"); err == nil {
+		size += wal.Size()
+	}
+	return size, nil
+}
+
+// Compact vacuums the database and then truncates the write-ahead log the vacuum went through
+func (c *SQLiteSummaryCache) Compact() error {
+	if c.readOnly {
+		return ErrReadOnly
+	}
+
+	if _, err := c.db.Exec(`VACUUM`); err != nil {
+		return fmt.Errorf("failed to vacuum summary database: %w", err)
+	}
+	if _, err := c.db.Exec(`PRAGMA wal_checkpoint(TRUNCATE)`); err != nil {
+		return fmt.Errorf("failed to checkpoint summary database: %w", err)
+	}
+	return nil
+}
+
+// Close closes the database
+func (c *SQLiteSummaryCache) Close() error {
+	return c.db.Close()
+}
@@ -8,6 +8,8 @@ import (
 type FileSummary struct {
 	Path                   string                     `json:"path"`
 	AbsolutePath           string                     `json:"absolute_path"`
+	Project                string                     `json:"project,omitempty"` // Project of the entries, when the log records it rather than its path
+	Source                 string                     `json:"source,omitempty"`  // Assistant or provider the entries came from
 	ModTime                time.Time                  `json:"mod_time"`
 	FileSize               int64                      `json:"file_size"`
 	EntryCount             int                        `json:"entry_count"`
@@ -19,6 +21,12 @@ type FileSummary struct {
 	ProcessedAt            time.Time                  `json:"processed_at"`
 	Checksum               string                     `json:"checksum"`
 	HasNoAssistantMessages bool                       `json:"has_no_assistant_messages"` // True if file has no assistant messages
+	CostMode               string                     `json:"cost_mode,omitempty"`       // Cost mode the costs were computed with
+	ValidationKey          string                     `json:"validation_key,omitempty"`  // Entry validation settings the summary was built with
+	SuspectEntries         int                        `json:"suspect_entries,omitempty"` // Entries left out of the summary as implausible
+	Integrity              string                     `json:"integrity,omitempty"`       // Checksum of the stored summary, set by the backend
+	Identity               string                     `json:"identity,omitempty"`        // Hash of the file's first bytes and size, unchanged by renames
+	DuplicateOf            string                     `json:"duplicate_of,omitempty"`    // File this one is a copy of, whose summary counts the entries
 }
 
 // TemporalBucket represents aggregated usage data for a specific time period
@@ -39,6 +47,7 @@ type ModelStat struct {
 	OutputTokens        int     `json:"output_tokens"`
 	CacheCreationTokens int     `json:"cache_creation_tokens"`
 	CacheReadTokens     int     `json:"cache_read_tokens"`
+	ThinkingTokens      int     `json:"thinking_tokens"`
 }
 
 // IsExpired checks if the summary is expired based on file modification time or size
@@ -0,0 +1,155 @@
+package calculations
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/penwyp/claudecat/models"
+)
+
+const (
+	// anomalyBaselineDays is how many previous days the usual burn rate for an hour of day
+	// is averaged over
+	anomalyBaselineDays = 7
+
+	// anomalyMinBaselineDays is how many of those days must have usage in the hour before a
+	// spike is judged, so a first session at an unusual hour isn't flagged
+	anomalyMinBaselineDays = 2
+
+	// anomalyMinTokensPerMinute is the burn rate below which nothing is flagged, however
+	// quiet the hour usually is
+	anomalyMinTokensPerMinute = 1000
+)
+
+// Anomaly reports a burn rate far above the usual rate for the hour of day
+type Anomaly struct {
+	DetectedAt      time.Time `json:"detected_at"`
+	BlockID         string    `json:"block_id,omitempty"`
+	TokensPerMinute float64   `json:"tokens_per_minute"` // Over the detector's window
+	CostPerHour     float64   `json:"cost_per_hour"`     // Over the detector's window
+	BaselineRate    float64   `json:"baseline_rate"`     // Average tokens/min in the same hour on active days of the last week
+	BaselineDays    int       `json:"baseline_days"`     // Days with usage in the hour
+	Factor          float64   `json:"factor"`            // TokensPerMinute / BaselineRate
+	Hour            int       `json:"hour"`              // Hour of day, in the detector's location
+}
+
+// Message returns a one-line human readable description of the anomaly
+func (a Anomaly) Message() string {
+	return fmt.Sprintf("Burn rate %.0f tokens/min ($%.2f/h) is %.1fx the usual %.0f tokens/min for %02d:00",
+		a.TokensPerMinute, a.CostPerHour, a.Factor, a.BaselineRate, a.Hour)
+}
+
+// AnomalyDetector flags burn rate spikes, such as a runaway agent or an accidental
+// large-context loop, by comparing the recent burn rate with the trailing average for the
+// same hour of day. Each anomaly is reported at most once per clock hour.
+type AnomalyDetector struct {
+	factor   float64
+	window   time.Duration
+	location *time.Location
+
+	mu       sync.Mutex
+	reported time.Time // Hour of the last anomaly reported
+}
+
+// NewAnomalyDetector creates a detector that flags a burn rate over window reaching factor
+// times the usual rate. Hours of day follow loc, a nil loc uses the local zone.
+func NewAnomalyDetector(factor float64, window time.Duration, loc *time.Location) *AnomalyDetector {
+	if window <= 0 {
+		window = 15 * time.Minute
+	}
+	if loc == nil {
+		loc = time.Local
+	}
+	return &AnomalyDetector{factor: factor, window: window, location: loc}
+}
+
+// Check returns the anomaly of the blocks at now, or nil when there is none or one was
+// already reported this hour
+func (d *AnomalyDetector) Check(blocks []models.SessionBlock, now time.Time) *Anomaly {
+	anomaly := d.Detect(blocks, now)
+	if anomaly == nil {
+		return nil
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	hour := now.Truncate(time.Hour)
+	if hour.Equal(d.reported) {
+		return nil
+	}
+	d.reported = hour
+	return anomaly
+}
+
+// Detect returns the anomaly of the blocks at now, or nil when the burn rate over the
+// detector's window is normal for the hour of day or there is too little history to tell
+func (d *AnomalyDetector) Detect(blocks []models.SessionBlock, now time.Time) *Anomaly {
+	if d.factor <= 0 {
+		return nil
+	}
+
+	local := now.In(d.location)
+	hourStart := time.Date(local.Year(), local.Month(), local.Day(), local.Hour(), 0, 0, 0, d.location)
+	recentStart := now.Add(-d.window)
+
+	var recentTokens int
+	var recentCost float64
+	var dayTokens [anomalyBaselineDays]int
+	blockID := ""
+	for i := range blocks {
+		block := &blocks[i]
+		if block.IsGap {
+			continue
+		}
+		for j := range block.Entries {
+			entry := &block.Entries[j]
+			tokens := entry.CalculateTotalTokens()
+			if entry.Timestamp.After(recentStart) && !entry.Timestamp.After(now) {
+				recentTokens += tokens
+				recentCost += entry.CostUSD
+				blockID = block.ID
+				continue
+			}
+			for day := range dayTokens {
+				start := hourStart.AddDate(0, 0, -(day + 1))
+				if !entry.Timestamp.Before(start) && entry.Timestamp.Before(start.Add(time.Hour)) {
+					dayTokens[day] += tokens
+					break
+				}
+			}
+		}
+	}
+
+	minutes := d.window.Minutes()
+	rate := float64(recentTokens) / minutes
+	if rate < anomalyMinTokensPerMinute {
+		return nil
+	}
+
+	activeDays, baselineTokens := 0, 0
+	for _, tokens := range dayTokens {
+		if tokens > 0 {
+			activeDays++
+			baselineTokens += tokens
+		}
+	}
+	if activeDays < anomalyMinBaselineDays {
+		return nil
+	}
+	baseline := float64(baselineTokens) / float64(activeDays) / 60
+	if rate < d.factor*baseline {
+		return nil
+	}
+
+	return &Anomaly{
+		DetectedAt:      now,
+		BlockID:         blockID,
+		TokensPerMinute: rate,
+		CostPerHour:     recentCost / minutes * 60,
+		BaselineRate:    baseline,
+		BaselineDays:    activeDays,
+		Factor:          rate / baseline,
+		Hour:            local.Hour(),
+	}
+}
@@ -0,0 +1,83 @@
+package calculations
+
+import (
+	"testing"
+	"time"
+
+	"github.com/penwyp/claudecat/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// anomalyBlock returns a block with one entry of tokens at each of times
+func anomalyBlock(id string, tokens int, times ...time.Time) models.SessionBlock {
+	block := models.SessionBlock{ID: id}
+	for _, t := range times {
+		block.Entries = append(block.Entries, models.UsageEntry{Timestamp: t, InputTokens: tokens, CostUSD: 0.01})
+	}
+	return block
+}
+
+func TestAnomalyDetector_Detect(t *testing.T) {
+	now := time.Date(2025, 6, 10, 14, 30, 0, 0, time.UTC)
+	// Usually 60,000 tokens between 14:00 and 15:00, i.e. 1,000 tokens/min
+	history := anomalyBlock("history", 60000,
+		now.AddDate(0, 0, -1).Add(-10*time.Minute),
+		now.AddDate(0, 0, -2).Add(20*time.Minute),
+		now.AddDate(0, 0, -3),
+	)
+	// Outside the hour of day, so not part of the baseline
+	other := anomalyBlock("other", 1000000, now.AddDate(0, 0, -1).Add(-2*time.Hour))
+
+	detector := NewAnomalyDetector(3, 15*time.Minute, time.UTC)
+
+	// 15 minutes at 2,000 tokens/min is twice the usual rate
+	normal := anomalyBlock("active", 10000, now.Add(-12*time.Minute), now.Add(-8*time.Minute), now.Add(-1*time.Minute))
+	assert.Nil(t, detector.Detect([]models.SessionBlock{history, other, normal}, now))
+
+	// 15 minutes at 4,000 tokens/min is four times the usual rate
+	spike := anomalyBlock("active", 20000, now.Add(-12*time.Minute), now.Add(-8*time.Minute), now.Add(-1*time.Minute))
+	anomaly := detector.Detect([]models.SessionBlock{history, other, spike}, now)
+	require.NotNil(t, anomaly)
+	assert.Equal(t, "active", anomaly.BlockID)
+	assert.Equal(t, 4000.0, anomaly.TokensPerMinute)
+	assert.Equal(t, 1000.0, anomaly.BaselineRate)
+	assert.Equal(t, 3, anomaly.BaselineDays)
+	assert.Equal(t, 4.0, anomaly.Factor)
+	assert.Equal(t, 14, anomaly.Hour)
+	assert.InDelta(t, 0.12, anomaly.CostPerHour, 1e-9)
+	assert.Equal(t, "Burn rate 4000 tokens/min ($0.12/h) is 4.0x the usual 1000 tokens/min for 14:00", anomaly.Message())
+}
+
+func TestAnomalyDetector_NeedsHistory(t *testing.T) {
+	now := time.Date(2025, 6, 10, 14, 30, 0, 0, time.UTC)
+	spike := anomalyBlock("active", 100000, now.Add(-time.Minute))
+	detector := NewAnomalyDetector(3, 15*time.Minute, time.UTC)
+
+	// One previous day with usage in the hour isn't a baseline
+	history := anomalyBlock("history", 600, now.AddDate(0, 0, -1))
+	assert.Nil(t, detector.Detect([]models.SessionBlock{history, spike}, now))
+
+	// Too slow to matter, however quiet the hour usually is
+	history = anomalyBlock("history", 60, now.AddDate(0, 0, -1), now.AddDate(0, 0, -2))
+	slow := anomalyBlock("active", 6000, now.Add(-time.Minute))
+	assert.Nil(t, detector.Detect([]models.SessionBlock{history, slow}, now))
+	assert.NotNil(t, detector.Detect([]models.SessionBlock{history, spike}, now))
+}
+
+func TestAnomalyDetector_CheckReportsOncePerHour(t *testing.T) {
+	now := time.Date(2025, 6, 10, 14, 30, 0, 0, time.UTC)
+	history := anomalyBlock("history", 600, now.AddDate(0, 0, -1), now.AddDate(0, 0, -2))
+	blocks := func(now time.Time) []models.SessionBlock {
+		return []models.SessionBlock{history, anomalyBlock("active", 100000, now.Add(-time.Minute))}
+	}
+	detector := NewAnomalyDetector(3, 15*time.Minute, time.UTC)
+
+	require.NotNil(t, detector.Check(blocks(now), now))
+	assert.Nil(t, detector.Check(blocks(now.Add(10*time.Minute)), now.Add(10*time.Minute)))
+
+	// The next hour has no baseline of its own
+	next := now.Add(time.Hour)
+	history = anomalyBlock("history", 600, next.AddDate(0, 0, -1), next.AddDate(0, 0, -2))
+	assert.NotNil(t, detector.Check(blocks(next), next))
+}
@@ -0,0 +1,294 @@
+package calculations
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/penwyp/claudecat/config"
+	"github.com/penwyp/claudecat/models"
+	"github.com/penwyp/claudecat/timeutil"
+)
+
+// BudgetPeriod is the calendar period a budget resets on. Monthly budgets follow the billing
+// cycle when a billing day is configured.
+type BudgetPeriod string
+
+const (
+	BudgetDaily   BudgetPeriod = "daily"
+	BudgetWeekly  BudgetPeriod = "weekly"
+	BudgetMonthly BudgetPeriod = "monthly"
+)
+
+// BudgetMetric identifies which limit of a budget an alert refers to
+type BudgetMetric string
+
+const (
+	BudgetMetricCost   BudgetMetric = "cost"
+	BudgetMetricTokens BudgetMetric = "tokens"
+)
+
+// DefaultBudgetThresholds are the usage fractions that trigger alerts when none are configured
+var DefaultBudgetThresholds = []float64{0.75, 0.90, 1.00}
+
+// Bounds returns the start and end of the period containing now, in now's location.
+// Weeks start on Monday.
+func (p BudgetPeriod) Bounds(now time.Time) (time.Time, time.Time) {
+	switch p {
+	case BudgetWeekly:
+		return timeutil.WeekBounds(now, now.Location())
+	case BudgetMonthly:
+		return timeutil.BillingCycleBounds(now, now.Location(), models.BillingDay())
+	default:
+		return timeutil.DayBounds(now, now.Location())
+	}
+}
+
+// MaxLength returns the longest the period can be, which is how much history a budget needs
+func (p BudgetPeriod) MaxLength() time.Duration {
+	switch p {
+	case BudgetWeekly:
+		return 7 * 24 * time.Hour
+	case BudgetMonthly:
+		return 31 * 24 * time.Hour
+	default:
+		return 25 * time.Hour // Daylight saving days can be an hour longer
+	}
+}
+
+// Budget is a cost and/or token limit over a calendar period. A zero limit is disabled.
+type Budget struct {
+	Name       string       `json:"name"`
+	Period     BudgetPeriod `json:"period"`
+	CostLimit  float64      `json:"cost_limit,omitempty"`
+	TokenLimit int          `json:"token_limit,omitempty"`
+}
+
+// BudgetStatus is a budget's usage in its current period
+type BudgetStatus struct {
+	Budget       Budget    `json:"budget"`
+	PeriodStart  time.Time `json:"period_start"`
+	PeriodEnd    time.Time `json:"period_end"`
+	Cost         float64   `json:"cost"`
+	Tokens       int       `json:"tokens"`
+	CostPercent  float64   `json:"cost_percent,omitempty"`
+	TokenPercent float64   `json:"token_percent,omitempty"`
+
+	// Usage at the end of the period if it continues at the average rate so far
+	ProjectedCost   float64 `json:"projected_cost,omitempty"`
+	ProjectedTokens int     `json:"projected_tokens,omitempty"`
+}
+
+// BudgetAlert is fired the first time a budget crosses a threshold within its period
+type BudgetAlert struct {
+	Status    BudgetStatus `json:"status"`
+	Metric    BudgetMetric `json:"metric"`
+	Threshold float64      `json:"threshold"` // Fraction of the limit that was crossed, e.g. 0.9
+	Percent   float64      `json:"percent"`   // Actual usage as a percentage of the limit
+}
+
+// Exceeded reports whether the budget's limit has been reached
+func (a BudgetAlert) Exceeded() bool {
+	return a.Threshold >= 1
+}
+
+// Message returns a one-line human readable description of the alert
+func (a BudgetAlert) Message() string {
+	budget := a.Status.Budget
+	var used, limit string
+	if a.Metric == BudgetMetricCost {
+		used, limit = fmt.Sprintf("$%.2f", a.Status.Cost), fmt.Sprintf("$%.2f", budget.CostLimit)
+	} else {
+		used, limit = fmt.Sprintf("%d tokens", a.Status.Tokens), fmt.Sprintf("%d tokens", budget.TokenLimit)
+	}
+
+	verb := "reached"
+	if a.Exceeded() {
+		verb = "exceeded"
+	}
+	return fmt.Sprintf("%s %s budget %s %.0f%%: %s of %s (%.1f%%)",
+		budget.Name, budget.Period, verb, a.Threshold*100, used, limit, a.Percent)
+}
+
+// BudgetTracker computes budget usage from session blocks and detects threshold crossings.
+// Each threshold alerts at most once per budget, metric and period; when several thresholds
+// are crossed at once only the highest is reported.
+type BudgetTracker struct {
+	budgets    []Budget
+	thresholds []float64
+	location   *time.Location
+
+	mu      sync.Mutex
+	alerted map[string]float64 // budget/metric/period start -> highest threshold alerted
+}
+
+// NewBudgetTracker creates a tracker for budgets whose periods follow loc.
+// Empty thresholds use DefaultBudgetThresholds and a nil loc uses the local zone.
+func NewBudgetTracker(budgets []Budget, thresholds []float64, loc *time.Location) *BudgetTracker {
+	if len(thresholds) == 0 {
+		thresholds = DefaultBudgetThresholds
+	}
+	sorted := append([]float64(nil), thresholds...)
+	sort.Float64s(sorted)
+
+	if loc == nil {
+		loc = time.Local
+	}
+
+	return &BudgetTracker{
+		budgets:    budgets,
+		thresholds: sorted,
+		location:   loc,
+		alerted:    make(map[string]float64),
+	}
+}
+
+// NewBudgetTrackerFromConfig creates a tracker for the configured budgets, or nil when none are configured
+func NewBudgetTrackerFromConfig(cfg config.BudgetsConfig, loc *time.Location) *BudgetTracker {
+	if len(cfg.Items) == 0 {
+		return nil
+	}
+
+	budgets := make([]Budget, 0, len(cfg.Items))
+	for i, item := range cfg.Items {
+		name := item.Name
+		if name == "" {
+			name = fmt.Sprintf("budget-%d", i+1)
+		}
+		budgets = append(budgets, Budget{
+			Name:       name,
+			Period:     BudgetPeriod(item.Period),
+			CostLimit:  item.CostUSD,
+			TokenLimit: item.Tokens,
+		})
+	}
+	return NewBudgetTracker(budgets, cfg.Thresholds, loc)
+}
+
+// Lookback returns how much history is needed to cover every budget's current period
+func (t *BudgetTracker) Lookback() time.Duration {
+	var lookback time.Duration
+	for _, budget := range t.budgets {
+		if length := budget.Period.MaxLength(); length > lookback {
+			lookback = length
+		}
+	}
+	return lookback
+}
+
+// Evaluate returns the current status of every budget and the alerts for thresholds
+// crossed since the previous evaluation
+func (t *BudgetTracker) Evaluate(blocks []models.SessionBlock, now time.Time) ([]BudgetStatus, []BudgetAlert) {
+	now = now.In(t.location)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	statuses := make([]BudgetStatus, 0, len(t.budgets))
+	var alerts []BudgetAlert
+	for _, budget := range t.budgets {
+		status := t.status(budget, blocks, now)
+		statuses = append(statuses, status)
+
+		if budget.CostLimit > 0 {
+			if alert, ok := t.checkThreshold(status, BudgetMetricCost, status.CostPercent); ok {
+				alerts = append(alerts, alert)
+			}
+		}
+		if budget.TokenLimit > 0 {
+			if alert, ok := t.checkThreshold(status, BudgetMetricTokens, status.TokenPercent); ok {
+				alerts = append(alerts, alert)
+			}
+		}
+	}
+
+	t.pruneAlerted(now)
+	return statuses, alerts
+}
+
+// status sums the usage of every entry inside the budget's current period
+func (t *BudgetTracker) status(budget Budget, blocks []models.SessionBlock, now time.Time) BudgetStatus {
+	start, end := budget.Period.Bounds(now)
+	status := BudgetStatus{
+		Budget:      budget,
+		PeriodStart: start,
+		PeriodEnd:   end,
+	}
+
+	for _, block := range blocks {
+		if block.IsGap || block.EndTime.Before(start) || !block.StartTime.Before(end) {
+			continue
+		}
+		for _, entry := range block.Entries {
+			if entry.Timestamp.Before(start) || !entry.Timestamp.Before(end) {
+				continue
+			}
+			status.Cost += entry.CostUSD
+			tokens := entry.TotalTokens
+			if tokens == 0 {
+				tokens = entry.CalculateTotalTokens()
+			}
+			status.Tokens += tokens
+		}
+	}
+
+	if elapsed := now.Sub(start); elapsed > 0 {
+		scale := float64(end.Sub(start)) / float64(elapsed)
+		status.ProjectedCost = status.Cost * scale
+		status.ProjectedTokens = int(float64(status.Tokens) * scale)
+	}
+
+	if budget.CostLimit > 0 {
+		status.CostPercent = status.Cost / budget.CostLimit * 100
+	}
+	if budget.TokenLimit > 0 {
+		status.TokenPercent = float64(status.Tokens) / float64(budget.TokenLimit) * 100
+	}
+	return status
+}
+
+// checkThreshold returns an alert for the highest threshold crossed that hasn't been alerted yet
+func (t *BudgetTracker) checkThreshold(status BudgetStatus, metric BudgetMetric, percent float64) (BudgetAlert, bool) {
+	crossed := 0.0
+	for _, threshold := range t.thresholds {
+		if percent >= threshold*100 {
+			crossed = threshold
+		}
+	}
+	if crossed == 0 {
+		return BudgetAlert{}, false
+	}
+
+	key := alertKey(status.Budget.Name, metric, status.PeriodStart)
+	if crossed <= t.alerted[key] {
+		return BudgetAlert{}, false
+	}
+	t.alerted[key] = crossed
+
+	return BudgetAlert{
+		Status:    status,
+		Metric:    metric,
+		Threshold: crossed,
+		Percent:   percent,
+	}, true
+}
+
+// pruneAlerted forgets alerts from periods that have ended
+func (t *BudgetTracker) pruneAlerted(now time.Time) {
+	current := make(map[string]bool, len(t.budgets)*2)
+	for _, budget := range t.budgets {
+		start, _ := budget.Period.Bounds(now)
+		current[alertKey(budget.Name, BudgetMetricCost, start)] = true
+		current[alertKey(budget.Name, BudgetMetricTokens, start)] = true
+	}
+	for key := range t.alerted {
+		if !current[key] {
+			delete(t.alerted, key)
+		}
+	}
+}
+
+func alertKey(name string, metric BudgetMetric, periodStart time.Time) string {
+	return fmt.Sprintf("%s/%s/%d", name, metric, periodStart.Unix())
+}
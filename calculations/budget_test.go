@@ -0,0 +1,92 @@
+package calculations
+
+import (
+	"testing"
+	"time"
+
+	"github.com/penwyp/claudecat/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func budgetBlock(entries ...models.UsageEntry) models.SessionBlock {
+	return models.SessionBlock{
+		StartTime: entries[0].Timestamp.Truncate(time.Hour),
+		EndTime:   entries[0].Timestamp.Truncate(time.Hour).Add(5 * time.Hour),
+		Entries:   entries,
+	}
+}
+
+func TestBudgetPeriod_Bounds(t *testing.T) {
+	now := time.Date(2025, 6, 19, 15, 30, 0, 0, time.UTC) // Thursday
+
+	start, end := BudgetDaily.Bounds(now)
+	assert.Equal(t, time.Date(2025, 6, 19, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2025, 6, 20, 0, 0, 0, 0, time.UTC), end)
+
+	start, end = BudgetWeekly.Bounds(now)
+	assert.Equal(t, time.Date(2025, 6, 16, 0, 0, 0, 0, time.UTC), start, "weeks start on Monday")
+	assert.Equal(t, time.Date(2025, 6, 23, 0, 0, 0, 0, time.UTC), end)
+
+	sunday := time.Date(2025, 6, 22, 23, 0, 0, 0, time.UTC)
+	start, _ = BudgetWeekly.Bounds(sunday)
+	assert.Equal(t, time.Date(2025, 6, 16, 0, 0, 0, 0, time.UTC), start)
+
+	start, end = BudgetMonthly.Bounds(now)
+	assert.Equal(t, time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC), end)
+
+	models.SetBillingDay(17)
+	defer models.SetBillingDay(0)
+	start, end = BudgetMonthly.Bounds(now)
+	assert.Equal(t, time.Date(2025, 6, 17, 0, 0, 0, 0, time.UTC), start, "monthly budgets follow the billing cycle")
+	assert.Equal(t, time.Date(2025, 7, 17, 0, 0, 0, 0, time.UTC), end)
+}
+
+func TestBudgetTracker_Evaluate(t *testing.T) {
+	now := time.Date(2025, 6, 19, 15, 0, 0, 0, time.UTC)
+	tracker := NewBudgetTracker([]Budget{
+		{Name: "daily", Period: BudgetDaily, CostLimit: 10},
+		{Name: "weekly", Period: BudgetWeekly, TokenLimit: 1000},
+	}, []float64{0.75, 0.9, 1}, time.UTC)
+
+	yesterday := models.UsageEntry{Timestamp: now.Add(-20 * time.Hour), CostUSD: 50, TotalTokens: 100}
+	today := models.UsageEntry{Timestamp: now.Add(-time.Hour), CostUSD: 8, TotalTokens: 500}
+	blocks := []models.SessionBlock{budgetBlock(yesterday), budgetBlock(today)}
+
+	statuses, alerts := tracker.Evaluate(blocks, now)
+	require.Len(t, statuses, 2)
+	assert.InDelta(t, 8.0, statuses[0].Cost, 1e-9, "yesterday's usage is outside the daily period")
+	assert.InDelta(t, 80.0, statuses[0].CostPercent, 1e-9)
+	assert.InDelta(t, 8.0*24/15, statuses[0].ProjectedCost, 1e-9, "15 hours into the day")
+	assert.Equal(t, 600, statuses[1].Tokens)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, "daily", alerts[0].Status.Budget.Name)
+	assert.Equal(t, BudgetMetricCost, alerts[0].Metric)
+	assert.Equal(t, 0.75, alerts[0].Threshold)
+	assert.False(t, alerts[0].Exceeded())
+
+	// The same threshold doesn't alert twice
+	_, alerts = tracker.Evaluate(blocks, now)
+	assert.Empty(t, alerts)
+
+	// Jumping past several thresholds only reports the highest
+	more := models.UsageEntry{Timestamp: now.Add(-30 * time.Minute), CostUSD: 3, TotalTokens: 500}
+	blocks[1].Entries = append(blocks[1].Entries, more)
+	_, alerts = tracker.Evaluate(blocks, now)
+	require.Len(t, alerts, 2)
+	assert.Equal(t, 1.0, alerts[0].Threshold)
+	assert.True(t, alerts[0].Exceeded())
+	assert.Contains(t, alerts[0].Message(), "daily daily budget exceeded 100%")
+	assert.Equal(t, BudgetMetricTokens, alerts[1].Metric)
+	assert.Equal(t, 1.0, alerts[1].Threshold)
+
+	// A new day resets the daily budget's alerts
+	tomorrow := now.Add(24 * time.Hour)
+	next := models.UsageEntry{Timestamp: tomorrow.Add(-time.Hour), CostUSD: 8}
+	blocks = append(blocks, budgetBlock(next))
+	_, alerts = tracker.Evaluate(blocks, tomorrow)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, "daily", alerts[0].Status.Budget.Name)
+	assert.Equal(t, 0.75, alerts[0].Threshold)
+}
@@ -71,6 +71,47 @@ func (brc *BurnRateCalculator) ProjectBlockUsage(block models.SessionBlock) *mod
 	}
 }
 
+// ProjectDepletion projects when tokenLimit is reached at tokensPerMinute, given the tokens
+// already used and the time the limit resets. It returns nil without a positive limit.
+func (brc *BurnRateCalculator) ProjectDepletion(tokensUsed, tokenLimit int, tokensPerMinute float64, resetTime, now time.Time) *models.TokenDepletion {
+	if tokenLimit <= 0 {
+		return nil
+	}
+	if tokensPerMinute < 0 {
+		tokensPerMinute = 0
+	}
+
+	depletion := &models.TokenDepletion{
+		TokenLimit:      tokenLimit,
+		TokensUsed:      tokensUsed,
+		TokensRemaining: tokenLimit - tokensUsed,
+		TokensPerMinute: tokensPerMinute,
+		ResetTime:       resetTime,
+		ProjectedTokens: tokensUsed,
+	}
+
+	if minutesToReset := resetTime.Sub(now).Minutes(); minutesToReset > 0 {
+		depletion.ProjectedTokens += int(tokensPerMinute * minutesToReset)
+	}
+	depletion.ProjectedPercent = float64(depletion.ProjectedTokens) / float64(tokenLimit) * 100
+
+	switch {
+	case depletion.TokensRemaining <= 0:
+		depletion.TokensRemaining = 0
+		depletion.Exhausted = true
+		depletion.DepletionTime = &now
+		depletion.RunsOutBeforeReset = true
+	case tokensPerMinute > 0:
+		minutes := float64(depletion.TokensRemaining) / tokensPerMinute
+		depletion.TimeToDepletion = time.Duration(minutes * float64(time.Minute))
+		depletionTime := now.Add(depletion.TimeToDepletion)
+		depletion.DepletionTime = &depletionTime
+		depletion.RunsOutBeforeReset = depletionTime.Before(resetTime)
+	}
+
+	return depletion
+}
+
 // CalculateHourlyBurnRate calculates burn rate based on all sessions in the last hour
 // This matches Claude-Code-Usage-Monitor's approach of calculating tokens/min from last hour
 func (brc *BurnRateCalculator) CalculateHourlyBurnRate(blocks []models.SessionBlock, currentTime time.Time) float64 {
@@ -0,0 +1,57 @@
+package calculations
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBurnRateCalculator_ProjectDepletion(t *testing.T) {
+	brc := NewBurnRateCalculator()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	reset := now.Add(2 * time.Hour)
+
+	assert.Nil(t, brc.ProjectDepletion(1000, 0, 100, reset, now), "no limit")
+
+	t.Run("runs out before reset", func(t *testing.T) {
+		depletion := brc.ProjectDepletion(40_000, 100_000, 1000, reset, now)
+		require.NotNil(t, depletion)
+		assert.Equal(t, 60_000, depletion.TokensRemaining)
+		assert.Equal(t, time.Hour, depletion.TimeToDepletion)
+		require.NotNil(t, depletion.DepletionTime)
+		assert.Equal(t, now.Add(time.Hour), *depletion.DepletionTime)
+		assert.True(t, depletion.RunsOutBeforeReset)
+		assert.False(t, depletion.Exhausted)
+		assert.Equal(t, 160_000, depletion.ProjectedTokens)
+		assert.InDelta(t, 160, depletion.ProjectedPercent, 1e-9)
+	})
+
+	t.Run("lasts until reset", func(t *testing.T) {
+		depletion := brc.ProjectDepletion(40_000, 100_000, 100, reset, now)
+		require.NotNil(t, depletion)
+		require.NotNil(t, depletion.DepletionTime)
+		assert.Equal(t, now.Add(10*time.Hour), *depletion.DepletionTime)
+		assert.False(t, depletion.RunsOutBeforeReset)
+		assert.Equal(t, 52_000, depletion.ProjectedTokens)
+	})
+
+	t.Run("idle", func(t *testing.T) {
+		depletion := brc.ProjectDepletion(40_000, 100_000, 0, reset, now)
+		require.NotNil(t, depletion)
+		assert.Nil(t, depletion.DepletionTime)
+		assert.False(t, depletion.RunsOutBeforeReset)
+		assert.InDelta(t, 40, depletion.ProjectedPercent, 1e-9)
+	})
+
+	t.Run("exhausted", func(t *testing.T) {
+		depletion := brc.ProjectDepletion(120_000, 100_000, 500, reset, now)
+		require.NotNil(t, depletion)
+		assert.True(t, depletion.Exhausted)
+		assert.Zero(t, depletion.TokensRemaining)
+		require.NotNil(t, depletion.DepletionTime)
+		assert.Equal(t, now, *depletion.DepletionTime)
+		assert.True(t, depletion.RunsOutBeforeReset)
+	})
+}
@@ -22,11 +22,13 @@ type CostResult struct {
 	OutputCost          float64 `json:"output_cost"`
 	CacheCreationCost   float64 `json:"cache_creation_cost"`
 	CacheReadCost       float64 `json:"cache_read_cost"`
+	ThinkingCost        float64 `json:"thinking_cost"`
 	TotalCost           float64 `json:"total_cost"`
 	InputTokens         int     `json:"input_tokens"`
 	OutputTokens        int     `json:"output_tokens"`
 	CacheCreationTokens int     `json:"cache_creation_tokens"`
 	CacheReadTokens     int     `json:"cache_read_tokens"`
+	ThinkingTokens      int     `json:"thinking_tokens"`
 	TotalTokens         int     `json:"total_tokens"`
 }
 
@@ -95,6 +97,7 @@ func (c *CostCalculator) Calculate(entry models.UsageEntry) (CostResult, error)
 		OutputTokens:        entry.OutputTokens,
 		CacheCreationTokens: entry.CacheCreationTokens,
 		CacheReadTokens:     entry.CacheReadTokens,
+		ThinkingTokens:      entry.ThinkingTokens,
 		TotalTokens:         entry.TotalTokens,
 	}
 
@@ -103,9 +106,10 @@ func (c *CostCalculator) Calculate(entry models.UsageEntry) (CostResult, error)
 	result.OutputCost = c.calculateTokenCost(entry.OutputTokens, pricing.Output)
 	result.CacheCreationCost = c.calculateTokenCost(entry.CacheCreationTokens, pricing.CacheCreation)
 	result.CacheReadCost = c.calculateTokenCost(entry.CacheReadTokens, pricing.CacheRead)
+	result.ThinkingCost = c.calculateTokenCost(entry.ThinkingTokens, pricing.ThinkingRate())
 
 	result.TotalCost = result.InputCost + result.OutputCost +
-		result.CacheCreationCost + result.CacheReadCost
+		result.CacheCreationCost + result.CacheReadCost + result.ThinkingCost
 
 	// Round to 6 decimal places for financial precision
 	result.TotalCost = c.roundCost(result.TotalCost)
@@ -113,6 +117,7 @@ func (c *CostCalculator) Calculate(entry models.UsageEntry) (CostResult, error)
 	result.OutputCost = c.roundCost(result.OutputCost)
 	result.CacheCreationCost = c.roundCost(result.CacheCreationCost)
 	result.CacheReadCost = c.roundCost(result.CacheReadCost)
+	result.ThinkingCost = c.roundCost(result.ThinkingCost)
 
 	return result, nil
 }
@@ -148,11 +153,13 @@ func (c *CostCalculator) CalculateBatch(entries []models.UsageEntry) (BatchCostR
 			modelResult.OutputTokens += entryResult.OutputTokens
 			modelResult.CacheCreationTokens += entryResult.CacheCreationTokens
 			modelResult.CacheReadTokens += entryResult.CacheReadTokens
+			modelResult.ThinkingTokens += entryResult.ThinkingTokens
 			modelResult.TotalTokens += entryResult.TotalTokens
 			modelResult.InputCost += entryResult.InputCost
 			modelResult.OutputCost += entryResult.OutputCost
 			modelResult.CacheCreationCost += entryResult.CacheCreationCost
 			modelResult.CacheReadCost += entryResult.CacheReadCost
+			modelResult.ThinkingCost += entryResult.ThinkingCost
 			modelResult.TotalCost += entryResult.TotalCost
 		} else {
 			copyResult := entryResult
@@ -187,6 +194,7 @@ func (c *CostCalculator) CalculateWithCurrency(entry models.UsageEntry, currency
 	result.OutputCost *= rate
 	result.CacheCreationCost *= rate
 	result.CacheReadCost *= rate
+	result.ThinkingCost *= rate
 	result.TotalCost *= rate
 
 	// Round after conversion
@@ -194,6 +202,7 @@ func (c *CostCalculator) CalculateWithCurrency(entry models.UsageEntry, currency
 	result.OutputCost = c.roundCost(result.OutputCost)
 	result.CacheCreationCost = c.roundCost(result.CacheCreationCost)
 	result.CacheReadCost = c.roundCost(result.CacheReadCost)
+	result.ThinkingCost = c.roundCost(result.ThinkingCost)
 	result.TotalCost = c.roundCost(result.TotalCost)
 
 	return result, nil
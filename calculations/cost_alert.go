@@ -0,0 +1,70 @@
+package calculations
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/penwyp/claudecat/config"
+	"github.com/penwyp/claudecat/models"
+)
+
+// SessionCostAlert reports that the cost of the active session block reached a cost ceiling
+type SessionCostAlert struct {
+	BlockID    string    `json:"block_id"`
+	BlockEnd   time.Time `json:"block_end"`
+	Cost       float64   `json:"cost"`
+	Ceiling    float64   `json:"ceiling"`     // Highest ceiling reached
+	MaxCeiling float64   `json:"max_ceiling"` // Highest ceiling configured
+}
+
+// Exceeded reports whether the highest configured ceiling has been reached
+func (a SessionCostAlert) Exceeded() bool {
+	return a.Ceiling >= a.MaxCeiling
+}
+
+// Message returns a one-line human readable description of the alert
+func (a SessionCostAlert) Message() string {
+	if a.Exceeded() {
+		return fmt.Sprintf("Session cost $%.2f exceeded the $%.2f ceiling", a.Cost, a.Ceiling)
+	}
+	return fmt.Sprintf("Session cost $%.2f passed $%.2f (ceiling $%.2f)", a.Cost, a.Ceiling, a.MaxCeiling)
+}
+
+// SessionCostCeilings returns the cost ceilings of the configured plan, falling back to the
+// subscription-wide cost_alerts, sorted ascending
+func SessionCostCeilings(cfg config.SubscriptionConfig) []float64 {
+	ceilings := NewPlanCatalogFromConfig(cfg).Limits(cfg.Plan).CostAlerts
+	if len(ceilings) == 0 {
+		ceilings = cfg.CostAlerts
+	}
+	sorted := append([]float64(nil), ceilings...)
+	sort.Float64s(sorted)
+	return sorted
+}
+
+// CheckSessionCost returns an alert for the highest of the ascending ceilings the block's
+// cost has reached, or nil when it reached none
+func CheckSessionCost(block models.SessionBlock, ceilings []float64) *SessionCostAlert {
+	if block.IsGap || len(ceilings) == 0 {
+		return nil
+	}
+
+	reached := 0.0
+	for _, ceiling := range ceilings {
+		if ceiling > 0 && block.CostUSD >= ceiling {
+			reached = ceiling
+		}
+	}
+	if reached == 0 {
+		return nil
+	}
+
+	return &SessionCostAlert{
+		BlockID:    block.ID,
+		BlockEnd:   block.EndTime,
+		Cost:       block.CostUSD,
+		Ceiling:    reached,
+		MaxCeiling: ceilings[len(ceilings)-1],
+	}
+}
@@ -0,0 +1,44 @@
+package calculations
+
+import (
+	"testing"
+	"time"
+
+	"github.com/penwyp/claudecat/config"
+	"github.com/penwyp/claudecat/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionCostCeilings(t *testing.T) {
+	sub := config.SubscriptionConfig{
+		Plan:       "max5",
+		CostAlerts: []float64{20, 10},
+		Plans:      map[string]config.PlanLimitsConfig{"max20": {CostAlerts: []float64{50}}},
+	}
+	assert.Equal(t, []float64{10, 20}, SessionCostCeilings(sub), "plans without cost alerts use the subscription's")
+
+	sub.Plan = "max20"
+	assert.Equal(t, []float64{50}, SessionCostCeilings(sub))
+
+	assert.Empty(t, SessionCostCeilings(config.SubscriptionConfig{Plan: "pro"}))
+}
+
+func TestCheckSessionCost(t *testing.T) {
+	block := models.SessionBlock{ID: "block", EndTime: time.Now(), CostUSD: 12.5}
+
+	alert := CheckSessionCost(block, []float64{5, 10, 20})
+	require.NotNil(t, alert)
+	assert.Equal(t, 10.0, alert.Ceiling)
+	assert.Equal(t, 20.0, alert.MaxCeiling)
+	assert.False(t, alert.Exceeded())
+	assert.Equal(t, "Session cost $12.50 passed $10.00 (ceiling $20.00)", alert.Message())
+
+	alert = CheckSessionCost(block, []float64{5, 10})
+	require.NotNil(t, alert)
+	assert.True(t, alert.Exceeded())
+	assert.Equal(t, "Session cost $12.50 exceeded the $10.00 ceiling", alert.Message())
+
+	assert.Nil(t, CheckSessionCost(block, []float64{20}))
+	assert.Nil(t, CheckSessionCost(block, nil))
+}
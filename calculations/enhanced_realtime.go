@@ -3,6 +3,7 @@ package calculations
 import (
 	"context"
 	"math"
+	"sort"
 	"sync"
 	"time"
 
@@ -29,9 +30,21 @@ type EnhancedRealtimeMetrics struct {
 	// Usage projections (aligned with Claude Monitor's UsageProjection)
 	Projection *models.UsageProjection `json:"projection,omitempty"`
 
+	// When the token limit runs out at the current burn rate; nil without a token limit
+	Depletion *models.TokenDepletion `json:"depletion,omitempty"`
+
+	// Highest cost ceiling the active session reached; nil when it reached none
+	CostAlert *SessionCostAlert `json:"cost_alert,omitempty"`
+
 	// Model distribution (enhanced to match Claude Monitor format)
 	ModelDistribution map[string]EnhancedModelMetrics `json:"model_distribution"`
 
+	// Project distribution, keyed by the project name derived from each log file's directory
+	ProjectDistribution map[string]EnhancedProjectMetrics `json:"project_distribution"`
+
+	// Usage of the active session per source, most expensive first
+	SourceDistribution []SourceUsage `json:"source_distribution,omitempty"`
+
 	// Time-based rates
 	TokensPerMinute float64 `json:"tokens_per_minute"`
 	TokensPerHour   float64 `json:"tokens_per_hour"`
@@ -59,12 +72,28 @@ type EnhancedModelMetrics struct {
 	BurnRate    *models.BurnRate   `json:"burn_rate,omitempty"`
 }
 
+// UnknownProject is the project name used for entries whose project couldn't be determined
+const UnknownProject = "unknown"
+
+// EnhancedProjectMetrics provides per-project usage statistics for the active session
+type EnhancedProjectMetrics struct {
+	TokenCounts    models.TokenCounts `json:"token_counts"`
+	TotalTokens    int                `json:"total_tokens"`
+	Cost           float64            `json:"cost"`
+	Percentage     float64            `json:"percentage"`      // Share of the session's tokens
+	CostPercentage float64            `json:"cost_percentage"` // Share of the session's cost
+	LastUsed       time.Time          `json:"last_used"`
+	EntryCount     int                `json:"entry_count"`
+}
+
 // EnhancedMetricsCalculator provides real-time metrics calculation aligned with Claude Monitor
 type EnhancedMetricsCalculator struct {
 	mu            sync.RWMutex
 	burnRateCalc  *BurnRateCalculator
 	config        *config.Config
 	sessionBlocks []models.SessionBlock
+	tokenLimit    int
+	costCeilings  []float64 // Ascending session costs in USD that raise a cost alert
 
 	// Cache management
 	cacheEnabled   bool
@@ -84,8 +113,14 @@ type EnhancedMetricsCalculator struct {
 func NewEnhancedMetricsCalculator(cfg *config.Config) *EnhancedMetricsCalculator {
 	ctx, cancel := context.WithCancel(context.Background())
 
+	var costCeilings []float64
+	if cfg != nil {
+		costCeilings = SessionCostCeilings(cfg.Subscription)
+	}
+
 	return &EnhancedMetricsCalculator{
 		burnRateCalc:     NewBurnRateCalculator(),
+		costCeilings:     costCeilings,
 		config:           cfg,
 		sessionBlocks:    make([]models.SessionBlock, 0),
 		cacheEnabled:     true,
@@ -108,6 +143,27 @@ func (emc *EnhancedMetricsCalculator) UpdateSessionBlocks(blocks []models.Sessio
 	emc.cachedMetrics = nil
 }
 
+// SetTokenLimit sets the session token limit used to project depletion; 0 disables the projection
+func (emc *EnhancedMetricsCalculator) SetTokenLimit(tokenLimit int) {
+	emc.mu.Lock()
+	defer emc.mu.Unlock()
+
+	if emc.tokenLimit != tokenLimit {
+		emc.tokenLimit = tokenLimit
+		emc.cachedMetrics = nil
+	}
+}
+
+// SetCostCeilings sets the session costs in USD that raise a cost alert; empty disables them
+func (emc *EnhancedMetricsCalculator) SetCostCeilings(ceilings []float64) {
+	emc.mu.Lock()
+	defer emc.mu.Unlock()
+
+	emc.costCeilings = append([]float64(nil), ceilings...)
+	sort.Float64s(emc.costCeilings)
+	emc.cachedMetrics = nil
+}
+
 // Calculate computes comprehensive real-time metrics
 func (emc *EnhancedMetricsCalculator) Calculate() *EnhancedRealtimeMetrics {
 	emc.mu.Lock()
@@ -131,9 +187,10 @@ func (emc *EnhancedMetricsCalculator) Calculate() *EnhancedRealtimeMetrics {
 	}
 
 	metrics := &EnhancedRealtimeMetrics{
-		LastUpdated:       now,
-		ModelDistribution: make(map[string]EnhancedModelMetrics),
-		DataPoints:        len(emc.sessionBlocks),
+		LastUpdated:         now,
+		ModelDistribution:   make(map[string]EnhancedModelMetrics),
+		ProjectDistribution: make(map[string]EnhancedProjectMetrics),
+		DataPoints:          len(emc.sessionBlocks),
 	}
 
 	if activeBlock != nil {
@@ -142,8 +199,12 @@ func (emc *EnhancedMetricsCalculator) Calculate() *EnhancedRealtimeMetrics {
 		emc.calculateInactiveMetrics(metrics, now)
 	}
 
-	// Calculate model distribution
+	// Calculate model, project and source distribution
 	emc.calculateModelDistribution(metrics, activeBlock)
+	emc.calculateProjectDistribution(metrics, activeBlock)
+	if activeBlock != nil {
+		metrics.SourceDistribution = AggregateBySource(activeBlock.Entries)
+	}
 
 	// Calculate confidence level
 	emc.calculateConfidenceLevel(metrics)
@@ -155,6 +216,14 @@ func (emc *EnhancedMetricsCalculator) Calculate() *EnhancedRealtimeMetrics {
 	if activeBlock != nil {
 		metrics.BurnRate = emc.burnRateCalc.CalculateBurnRate(*activeBlock)
 		metrics.Projection = emc.burnRateCalc.ProjectBlockUsage(*activeBlock)
+
+		var tokensPerMinute float64
+		if metrics.BurnRate != nil {
+			tokensPerMinute = metrics.BurnRate.TokensPerMinute
+		}
+		metrics.Depletion = emc.burnRateCalc.ProjectDepletion(
+			metrics.CurrentTokens, emc.tokenLimit, tokensPerMinute, activeBlock.EndTime, now)
+		metrics.CostAlert = CheckSessionCost(*activeBlock, emc.costCeilings)
 	}
 
 	// Calculate processing time
@@ -252,6 +321,7 @@ func (emc *EnhancedMetricsCalculator) calculateModelDistribution(
 				OutputTokens:        getIntFromMap(stats, "output_tokens"),
 				CacheCreationTokens: getIntFromMap(stats, "cache_creation_tokens"),
 				CacheReadTokens:     getIntFromMap(stats, "cache_read_tokens"),
+				ThinkingTokens:      getIntFromMap(stats, "thinking_tokens"),
 			},
 			Cost:       getFloatFromMap(stats, "cost_usd"),
 			EntryCount: getIntFromMap(stats, "entries_count"),
@@ -271,6 +341,57 @@ func (emc *EnhancedMetricsCalculator) calculateModelDistribution(
 	}
 }
 
+// calculateProjectDistribution calculates per-project usage statistics for the active session
+func (emc *EnhancedMetricsCalculator) calculateProjectDistribution(
+	metrics *EnhancedRealtimeMetrics,
+	activeBlock *models.SessionBlock,
+) {
+	if activeBlock == nil {
+		return
+	}
+
+	var totalTokens int
+	var totalCost float64
+	for _, entry := range activeBlock.Entries {
+		project := entry.Project
+		if project == "" {
+			project = UnknownProject
+		}
+
+		projectMetrics := metrics.ProjectDistribution[project]
+		projectMetrics.TokenCounts.InputTokens += entry.InputTokens
+		projectMetrics.TokenCounts.OutputTokens += entry.OutputTokens
+		projectMetrics.TokenCounts.CacheCreationTokens += entry.CacheCreationTokens
+		projectMetrics.TokenCounts.CacheReadTokens += entry.CacheReadTokens
+		projectMetrics.TokenCounts.ThinkingTokens += entry.ThinkingTokens
+		projectMetrics.Cost += entry.CostUSD
+		projectMetrics.EntryCount++
+		if entry.Timestamp.After(projectMetrics.LastUsed) {
+			projectMetrics.LastUsed = entry.Timestamp
+		}
+		metrics.ProjectDistribution[project] = projectMetrics
+
+		totalCost += entry.CostUSD
+	}
+
+	for project, projectMetrics := range metrics.ProjectDistribution {
+		projectMetrics.TotalTokens = projectMetrics.TokenCounts.TotalTokens()
+		totalTokens += projectMetrics.TotalTokens
+		metrics.ProjectDistribution[project] = projectMetrics
+	}
+
+	// Calculate percentages
+	for project, projectMetrics := range metrics.ProjectDistribution {
+		if totalTokens > 0 {
+			projectMetrics.Percentage = float64(projectMetrics.TotalTokens) / float64(totalTokens) * 100
+		}
+		if totalCost > 0 {
+			projectMetrics.CostPercentage = projectMetrics.Cost / totalCost * 100
+		}
+		metrics.ProjectDistribution[project] = projectMetrics
+	}
+}
+
 // calculateConfidenceLevel calculates confidence in the metrics
 func (emc *EnhancedMetricsCalculator) calculateConfidenceLevel(metrics *EnhancedRealtimeMetrics) {
 	dataPoints := float64(metrics.DataPoints)
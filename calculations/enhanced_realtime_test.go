@@ -0,0 +1,76 @@
+package calculations
+
+import (
+	"testing"
+	"time"
+
+	"github.com/penwyp/claudecat/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnhancedMetricsCalculator_ProjectDistribution(t *testing.T) {
+	start := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	block := &models.SessionBlock{
+		StartTime: start,
+		EndTime:   start.Add(5 * time.Hour),
+		Entries: []models.UsageEntry{
+			{Timestamp: start.Add(time.Minute), Project: "api", InputTokens: 100, OutputTokens: 200, CostUSD: 0.3},
+			{Timestamp: start.Add(2 * time.Minute), Project: "web", InputTokens: 50, OutputTokens: 50, CostUSD: 0.1},
+			{Timestamp: start.Add(3 * time.Minute), Project: "api", InputTokens: 100, CacheReadTokens: 100, CostUSD: 0.2},
+			{Timestamp: start.Add(4 * time.Minute), InputTokens: 100},
+		},
+	}
+
+	metrics := &EnhancedRealtimeMetrics{ProjectDistribution: make(map[string]EnhancedProjectMetrics)}
+	(&EnhancedMetricsCalculator{}).calculateProjectDistribution(metrics, block)
+
+	require.Len(t, metrics.ProjectDistribution, 3)
+
+	api := metrics.ProjectDistribution["api"]
+	assert.Equal(t, 500, api.TotalTokens)
+	assert.Equal(t, 200, api.TokenCounts.InputTokens)
+	assert.Equal(t, 100, api.TokenCounts.CacheReadTokens)
+	assert.Equal(t, 2, api.EntryCount)
+	assert.InDelta(t, 0.5, api.Cost, 1e-9)
+	assert.InDelta(t, 71.429, api.Percentage, 1e-3)
+	assert.InDelta(t, 83.333, api.CostPercentage, 1e-3)
+	assert.Equal(t, start.Add(3*time.Minute), api.LastUsed)
+
+	web := metrics.ProjectDistribution["web"]
+	assert.Equal(t, 100, web.TotalTokens)
+	assert.InDelta(t, 14.286, web.Percentage, 1e-3)
+
+	unknown := metrics.ProjectDistribution[UnknownProject]
+	assert.Equal(t, 100, unknown.TotalTokens)
+	assert.Zero(t, unknown.CostPercentage)
+}
+
+func TestEnhancedMetricsCalculator_ProjectDistributionNoActiveBlock(t *testing.T) {
+	metrics := &EnhancedRealtimeMetrics{ProjectDistribution: make(map[string]EnhancedProjectMetrics)}
+	(&EnhancedMetricsCalculator{}).calculateProjectDistribution(metrics, nil)
+	assert.Empty(t, metrics.ProjectDistribution)
+}
+
+func TestEnhancedMetricsCalculator_CostAlert(t *testing.T) {
+	now := time.Now()
+	block := models.SessionBlock{
+		ID:        "active",
+		StartTime: now.Add(-time.Hour),
+		EndTime:   now.Add(4 * time.Hour),
+		IsActive:  true,
+		CostUSD:   15,
+		Entries:   []models.UsageEntry{{Timestamp: now.Add(-time.Minute), InputTokens: 100, CostUSD: 15}},
+	}
+
+	emc := NewEnhancedMetricsCalculator(nil)
+	emc.UpdateSessionBlocks([]models.SessionBlock{block})
+	assert.Nil(t, emc.Calculate().CostAlert, "no ceilings configured")
+
+	emc.SetCostCeilings([]float64{20, 10})
+	alert := emc.Calculate().CostAlert
+	require.NotNil(t, alert)
+	assert.Equal(t, "active", alert.BlockID)
+	assert.Equal(t, 10.0, alert.Ceiling)
+	assert.Equal(t, 20.0, alert.MaxCeiling)
+}
@@ -1,6 +1,7 @@
 package calculations
 
 import (
+	"fmt"
 	"sort"
 	"sync"
 	"time"
@@ -26,6 +27,37 @@ func DefaultP90Config() P90Config {
 	}
 }
 
+// LimitSource describes which past sessions a P90 token limit was derived from
+type LimitSource string
+
+const (
+	LimitSourceLimitMessages LimitSource = "limit_messages" // Tokens used when Claude reported a usage limit
+	LimitSourceNearLimit     LimitSource = "near_limit"     // Sessions close to a known plan limit
+	LimitSourceAllSessions   LimitSource = "all_sessions"   // Every completed session
+	LimitSourceDefault       LimitSource = "default"        // No usable history
+)
+
+// P90Estimate is a token limit derived from the user's own session history
+type P90Estimate struct {
+	Limit   int         `json:"limit"`
+	Source  LimitSource `json:"source"`
+	Samples int         `json:"samples"` // Number of sessions the limit was derived from
+}
+
+// Description returns a short human readable explanation of the estimate
+func (e P90Estimate) Description() string {
+	switch e.Source {
+	case LimitSourceLimitMessages:
+		return fmt.Sprintf("P90 of %d limit hits", e.Samples)
+	case LimitSourceNearLimit:
+		return fmt.Sprintf("P90 of %d near-limit sessions", e.Samples)
+	case LimitSourceAllSessions:
+		return fmt.Sprintf("P90 of %d sessions", e.Samples)
+	default:
+		return "default"
+	}
+}
+
 // P90Calculator calculates P90 token limits from historical session data
 type P90Calculator struct {
 	config  P90Config
@@ -35,7 +67,7 @@ type P90Calculator struct {
 
 // p90Cache stores cached P90 calculations
 type p90Cache struct {
-	value      int
+	estimate   P90Estimate
 	expireTime time.Time
 }
 
@@ -54,69 +86,126 @@ func NewP90CalculatorWithConfig(config P90Config) *P90Calculator {
 
 // CalculateP90Limit calculates the P90 token limit from session blocks
 func (p *P90Calculator) CalculateP90Limit(blocks []models.SessionBlock, useCache bool) int {
+	return p.EstimateLimit(blocks, useCache).Limit
+}
+
+// EstimateLimit derives a token limit from session blocks and reports what it was based on
+func (p *P90Calculator) EstimateLimit(blocks []models.SessionBlock, useCache bool) P90Estimate {
 	if len(blocks) == 0 {
-		return p.config.DefaultMinLimit
+		return P90Estimate{Limit: p.config.DefaultMinLimit, Source: LimitSourceDefault}
 	}
 
 	// Check cache if enabled
 	if useCache {
 		p.cacheMu.RLock()
 		if p.cache != nil && time.Now().Before(p.cache.expireTime) {
-			cachedValue := p.cache.value
+			cached := p.cache.estimate
 			p.cacheMu.RUnlock()
-			return cachedValue
+			return cached
 		}
 		p.cacheMu.RUnlock()
 	}
 
 	// Calculate P90
-	p90Value := p.calculateP90FromBlocks(blocks)
+	estimate := p.calculateP90FromBlocks(blocks)
 
 	// Update cache
 	if useCache {
 		p.cacheMu.Lock()
 		p.cache = &p90Cache{
-			value:      p90Value,
+			estimate:   estimate,
 			expireTime: time.Now().Add(time.Duration(p.config.CacheTTLSeconds) * time.Second),
 		}
 		p.cacheMu.Unlock()
 	}
 
-	return p90Value
+	return estimate
 }
 
-// calculateP90FromBlocks performs the actual P90 calculation
-func (p *P90Calculator) calculateP90FromBlocks(blocks []models.SessionBlock) int {
-	// First try to get sessions that hit limits
+// calculateP90FromBlocks performs the actual P90 calculation. Sessions where Claude reported
+// a usage limit are the strongest evidence of the real limit, so they are used as-is; the
+// weaker fallbacks are raised to the default minimum.
+func (p *P90Calculator) calculateP90FromBlocks(blocks []models.SessionBlock) P90Estimate {
+	if hits := p.extractLimitMessageSessions(blocks); len(hits) > 0 {
+		return P90Estimate{Limit: percentile90(hits), Source: LimitSourceLimitMessages, Samples: len(hits)}
+	}
+
+	// Then sessions that came close to a known plan limit
+	source := LimitSourceNearLimit
 	limitSessions := p.extractLimitSessions(blocks)
 
 	// If no limit sessions, use all completed sessions
 	if len(limitSessions) == 0 {
+		source = LimitSourceAllSessions
 		limitSessions = p.extractAllCompletedSessions(blocks)
 	}
 
 	// If still no sessions, return default
 	if len(limitSessions) == 0 {
-		return p.config.DefaultMinLimit
+		return P90Estimate{Limit: p.config.DefaultMinLimit, Source: LimitSourceDefault}
 	}
 
-	// Sort sessions by token count
-	sort.Ints(limitSessions)
-
-	// Calculate P90 (90th percentile)
-	p90Index := int(float64(len(limitSessions)) * 0.9)
-	if p90Index >= len(limitSessions) {
-		p90Index = len(limitSessions) - 1
-	}
-
-	p90Value := limitSessions[p90Index]
+	p90Value := percentile90(limitSessions)
 
 	// Ensure minimum value
 	if p90Value < p.config.DefaultMinLimit {
 		p90Value = p.config.DefaultMinLimit
 	}
 
-	return p90Value
+	return P90Estimate{Limit: p90Value, Source: source, Samples: len(limitSessions)}
+}
+
+// percentile90 returns the 90th percentile of values, sorting them in place
+func percentile90(values []int) int {
+	sort.Ints(values)
+
+	p90Index := int(float64(len(values)) * 0.9)
+	if p90Index >= len(values) {
+		p90Index = len(values) - 1
+	}
+	return values[p90Index]
+}
+
+// extractLimitMessageSessions returns the tokens each session had used when Claude first
+// reported a usage limit in it. Opus limits only cap one model, so they are ignored.
+func (p *P90Calculator) extractLimitMessageSessions(blocks []models.SessionBlock) []int {
+	var sessions []int
+
+	for _, block := range blocks {
+		if block.IsGap {
+			continue
+		}
+
+		var hitAt time.Time
+		for _, limit := range block.LimitMessages {
+			if limit.Type == "opus_limit" {
+				continue
+			}
+			if hitAt.IsZero() || limit.Timestamp.Before(hitAt) {
+				hitAt = limit.Timestamp
+			}
+		}
+		if hitAt.IsZero() {
+			continue
+		}
+
+		tokens := 0
+		for _, entry := range block.Entries {
+			if entry.Timestamp.After(hitAt) {
+				break
+			}
+			entryTokens := entry.TotalTokens
+			if entryTokens == 0 {
+				entryTokens = entry.CalculateTotalTokens()
+			}
+			tokens += entryTokens
+		}
+		if tokens > 0 {
+			sessions = append(sessions, tokens)
+		}
+	}
+
+	return sessions
 }
 
 // extractLimitSessions extracts sessions that hit token limits
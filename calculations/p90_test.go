@@ -0,0 +1,54 @@
+package calculations
+
+import (
+	"testing"
+	"time"
+
+	"github.com/penwyp/claudecat/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func p90Block(start time.Time, tokens ...int) models.SessionBlock {
+	block := models.SessionBlock{ID: start.Format(time.RFC3339), StartTime: start, EndTime: start.Add(5 * time.Hour)}
+	for i, t := range tokens {
+		block.Entries = append(block.Entries, models.UsageEntry{
+			Timestamp:   start.Add(time.Duration(i+1) * time.Minute),
+			TotalTokens: t,
+		})
+		block.TotalTokens += t
+	}
+	return block
+}
+
+func TestP90Calculator_EstimateLimit(t *testing.T) {
+	calc := NewP90Calculator()
+	start := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	estimate := calc.EstimateLimit(nil, false)
+	assert.Equal(t, LimitSourceDefault, estimate.Source)
+	assert.Equal(t, 1000000, estimate.Limit)
+
+	// Small completed sessions fall back to the default minimum
+	blocks := []models.SessionBlock{p90Block(start, 1000), p90Block(start.Add(6*time.Hour), 2000)}
+	estimate = calc.EstimateLimit(blocks, false)
+	assert.Equal(t, LimitSourceAllSessions, estimate.Source)
+	assert.Equal(t, 2, estimate.Samples)
+	assert.Equal(t, 1000000, estimate.Limit)
+
+	// Reported limit hits win and use the tokens spent up to the first hit, without a minimum
+	hit := p90Block(start.Add(12*time.Hour), 40000, 50000, 30000)
+	hit.LimitMessages = []models.LimitMessage{
+		{Type: "system_limit", Timestamp: hit.Entries[1].Timestamp},
+		{Type: "system_limit", Timestamp: hit.Entries[2].Timestamp},
+	}
+	opus := p90Block(start.Add(18*time.Hour), 10000)
+	opus.LimitMessages = []models.LimitMessage{{Type: "opus_limit", Timestamp: opus.Entries[0].Timestamp}}
+	blocks = append(blocks, hit, opus)
+
+	estimate = calc.EstimateLimit(blocks, false)
+	assert.Equal(t, LimitSourceLimitMessages, estimate.Source)
+	assert.Equal(t, 1, estimate.Samples, "opus limits are ignored")
+	assert.Equal(t, 90000, estimate.Limit)
+	assert.Equal(t, "P90 of 1 limit hits", estimate.Description())
+	assert.Equal(t, 90000, calc.CalculateP90Limit(blocks, false))
+}
@@ -0,0 +1,64 @@
+package calculations
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/penwyp/claudecat/models"
+)
+
+// ObservedLimit is the session token limit stated by Claude's own limit messages, which is
+// more reliable than the configured plan or an estimate from past sessions
+type ObservedLimit struct {
+	TokenLimit int       `json:"token_limit"`
+	Plan       string    `json:"plan,omitempty"` // Plan with that limit; empty when no plan matches
+	ObservedAt time.Time `json:"observed_at"`    // When the latest message stating it was written
+}
+
+// DetectObservedLimit returns the token limit stated by the latest limit message that
+// states one, along with the plan of the catalog having that limit
+func DetectObservedLimit(blocks []models.SessionBlock, catalog *models.PlanCatalog) (ObservedLimit, bool) {
+	var observed ObservedLimit
+	for _, block := range blocks {
+		for _, message := range block.LimitMessages {
+			ceiling, ok := message.TokenCeiling()
+			if !ok || message.Timestamp.Before(observed.ObservedAt) {
+				continue
+			}
+			observed = ObservedLimit{TokenLimit: ceiling, ObservedAt: message.Timestamp}
+		}
+	}
+	if observed.TokenLimit == 0 {
+		return observed, false
+	}
+	if catalog != nil {
+		observed.Plan, _ = catalog.PlanForTokenLimit(observed.TokenLimit)
+	}
+	return observed, true
+}
+
+// ConflictsWith reports whether the observed limit contradicts the token limit of the
+// configured plan. The custom plan derives its limit from past sessions, so nothing
+// contradicts it.
+func (o ObservedLimit) ConflictsWith(plan string, catalog *models.PlanCatalog) bool {
+	if strings.EqualFold(plan, models.PlanCustom) || catalog == nil {
+		return false
+	}
+	if o.Plan != "" {
+		return !strings.EqualFold(o.Plan, plan)
+	}
+	planLimit := catalog.Limits(plan).TokenLimit
+	return planLimit <= 0 || math.Abs(float64(planLimit-o.TokenLimit))/float64(planLimit) > 0.01
+}
+
+// Warning describes the conflict between the observed limit and the configured plan
+func (o ObservedLimit) Warning(plan string) string {
+	if o.Plan != "" {
+		return fmt.Sprintf("limit messages report a session limit of %d tokens, which is the %s plan, but the configured plan is %s",
+			o.TokenLimit, o.Plan, plan)
+	}
+	return fmt.Sprintf("limit messages report a session limit of %d tokens, which doesn't match the configured %s plan",
+		o.TokenLimit, plan)
+}
@@ -0,0 +1,21 @@
+package calculations
+
+import (
+	"github.com/penwyp/claudecat/config"
+	"github.com/penwyp/claudecat/models"
+)
+
+// NewPlanCatalogFromConfig returns the built-in plans with the configured limits applied
+func NewPlanCatalogFromConfig(cfg config.SubscriptionConfig) *models.PlanCatalog {
+	overrides := make(map[string]models.PlanLimits, len(cfg.Plans))
+	for name, plan := range cfg.Plans {
+		overrides[name] = models.PlanLimits{
+			Name:         plan.Name,
+			TokenLimit:   plan.TokenLimit,
+			CostLimit:    plan.CostLimit,
+			MessageLimit: plan.MessageLimit,
+			CostAlerts:   plan.CostAlerts,
+		}
+	}
+	return models.NewPlanCatalog(overrides)
+}
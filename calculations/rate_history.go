@@ -0,0 +1,107 @@
+package calculations
+
+import (
+	"sync"
+	"time"
+)
+
+// RateSample is the burn rate and cost rate seen during one bucket of a RateHistory
+type RateSample struct {
+	Time            time.Time // Start of the bucket
+	TokensPerMinute float64
+	CostPerMinute   float64
+}
+
+// RateHistory keeps the burn rate and cost rate of a recent window, one sample per bucket,
+// so the console can show whether usage is speeding up. Recording again within a bucket
+// replaces its sample.
+type RateHistory struct {
+	window  time.Duration
+	bucket  time.Duration
+	mu      sync.Mutex
+	samples []RateSample
+}
+
+// NewRateHistory creates a history covering window with one sample per bucket
+func NewRateHistory(window, bucket time.Duration) *RateHistory {
+	if bucket <= 0 {
+		bucket = time.Minute
+	}
+	if window < bucket {
+		window = bucket
+	}
+	return &RateHistory{window: window, bucket: bucket}
+}
+
+// Record stores the rates observed at t and drops samples that have left the window
+func (h *RateHistory) Record(t time.Time, tokensPerMinute, costPerMinute float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	sample := RateSample{Time: t.Truncate(h.bucket), TokensPerMinute: tokensPerMinute, CostPerMinute: costPerMinute}
+	if n := len(h.samples); n > 0 && !sample.Time.After(h.samples[n-1].Time) {
+		// Same bucket, or a clock that went backwards
+		h.samples[n-1] = sample
+	} else {
+		h.samples = append(h.samples, sample)
+	}
+
+	cutoff := sample.Time.Add(-h.window)
+	drop := 0
+	for drop < len(h.samples) && !h.samples[drop].Time.After(cutoff) {
+		drop++
+	}
+	h.samples = append(h.samples[:0], h.samples[drop:]...)
+}
+
+// Samples returns the samples in the window, oldest first
+func (h *RateHistory) Samples() []RateSample {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]RateSample(nil), h.samples...)
+}
+
+// TokenRates returns the tokens/min of the samples, oldest first
+func (h *RateHistory) TokenRates() []float64 {
+	samples := h.Samples()
+	rates := make([]float64, len(samples))
+	for i, sample := range samples {
+		rates[i] = sample.TokensPerMinute
+	}
+	return rates
+}
+
+// CostRates returns the $/min of the samples, oldest first
+func (h *RateHistory) CostRates() []float64 {
+	samples := h.Samples()
+	rates := make([]float64, len(samples))
+	for i, sample := range samples {
+		rates[i] = sample.CostPerMinute
+	}
+	return rates
+}
+
+// Trend compares the average of the newer half of values with the older half. It returns
+// the relative change, e.g. 0.5 when the burn rate grew by half, and 0 with fewer than
+// two values or an idle older half.
+func Trend(values []float64) float64 {
+	if len(values) < 2 {
+		return 0
+	}
+
+	half := len(values) / 2
+	older := mean(values[:half])
+	newer := mean(values[len(values)-half:])
+	if older == 0 {
+		return 0
+	}
+	return (newer - older) / older
+}
+
+func mean(values []float64) float64 {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
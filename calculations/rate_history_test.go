@@ -0,0 +1,36 @@
+package calculations
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateHistory_Record(t *testing.T) {
+	history := NewRateHistory(5*time.Minute, time.Minute)
+	start := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	history.Record(start, 10, 0.1)
+	history.Record(start.Add(30*time.Second), 20, 0.2) // Same bucket replaces
+	assert.Equal(t, []float64{20}, history.TokenRates())
+
+	for i := 1; i <= 6; i++ {
+		history.Record(start.Add(time.Duration(i)*time.Minute), float64(i), 0)
+	}
+
+	// The first two buckets left the window
+	samples := history.Samples()
+	assert.Len(t, samples, 5)
+	assert.Equal(t, start.Add(2*time.Minute), samples[0].Time)
+	assert.Equal(t, []float64{2, 3, 4, 5, 6}, history.TokenRates())
+	assert.Equal(t, []float64{0, 0, 0, 0, 0}, history.CostRates())
+}
+
+func TestTrend(t *testing.T) {
+	assert.Equal(t, 0.0, Trend(nil))
+	assert.Equal(t, 0.0, Trend([]float64{5}))
+	assert.Equal(t, 0.0, Trend([]float64{0, 0, 10, 10}))
+	assert.Equal(t, 1.0, Trend([]float64{10, 10, 20, 20}))
+	assert.Equal(t, -0.5, Trend([]float64{20, 99, 10}))
+}
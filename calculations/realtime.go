@@ -34,6 +34,12 @@ type RealtimeMetrics struct {
 	// 模型分布
 	ModelDistribution map[string]ModelMetrics `json:"model_distribution"`
 
+	// 项目分布
+	ProjectDistribution map[string]ProjectMetrics `json:"project_distribution"`
+
+	// 来源分布
+	SourceDistribution []SourceUsage `json:"source_distribution,omitempty"`
+
 	// 新增性能指标
 	PerformanceMetrics PerformanceMetrics `json:"performance_metrics"`
 	EfficiencyMetrics  EfficiencyMetrics  `json:"efficiency_metrics"`
@@ -93,6 +99,14 @@ type ModelMetrics struct {
 	LastUsed   time.Time `json:"last_used"`
 }
 
+// ProjectMetrics 项目使用指标
+type ProjectMetrics struct {
+	TokenCount int       `json:"token_count"`
+	Cost       float64   `json:"cost"`
+	Percentage float64   `json:"percentage"`
+	LastUsed   time.Time `json:"last_used"`
+}
+
 // MetricsCalculator 指标计算引擎
 type MetricsCalculator struct {
 	mu             sync.RWMutex
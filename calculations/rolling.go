@@ -0,0 +1,224 @@
+package calculations
+
+import (
+	"sync"
+	"time"
+
+	"github.com/penwyp/claudecat/models"
+)
+
+// Rolling usage windows, the spans many users budget their subscription over
+const (
+	RollingWeek  = 7 * 24 * time.Hour
+	RollingMonth = 30 * 24 * time.Hour
+)
+
+// RollingHistoryRefresh is how often the history before the blocks is loaded again. The
+// blocks passed to Update must reach back RollingWeek and RollingHistoryRefresh, so the
+// usage between the history and the blocks is never missed.
+const RollingHistoryRefresh = 12 * time.Hour
+
+// UsageTotal is the tokens and cost of a span of usage
+type UsageTotal struct {
+	Tokens int     `json:"tokens"`
+	Cost   float64 `json:"cost"`
+}
+
+func (t *UsageTotal) add(other UsageTotal, sign int) {
+	t.Tokens += sign * other.Tokens
+	t.Cost += float64(sign) * other.Cost
+}
+
+// RollingTotals is the usage of the last 7 and 30 days, to the hour
+type RollingTotals struct {
+	Last7Days  UsageTotal `json:"last_7_days"`
+	Last30Days UsageTotal `json:"last_30_days"`
+	Partial    bool       `json:"partial,omitempty"` // Last30Days only covers the blocks, as no history is set yet
+}
+
+// rollingBucket is the usage of an hour
+type rollingBucket struct {
+	usage   UsageTotal
+	entries int
+}
+
+func (b *rollingBucket) add(other rollingBucket, sign int) {
+	b.usage.add(other.usage, sign)
+	b.entries += sign * other.entries
+}
+
+// rollingBlock is what a block added to the hourly buckets, so it can be taken out again
+// when the block changes or goes away
+type rollingBlock struct {
+	entries int
+	cost    float64
+	hours   map[time.Time]rollingBucket
+}
+
+// RollingWindows keeps the totals of the rolling 7 and 30 day windows up to date as blocks
+// are refreshed. Usage is summed into hourly buckets per block, and an update only
+// re-sums the blocks that changed since the previous one, usually just the active block,
+// rather than 30 days of entries. Usage older than the blocks comes from the history,
+// loaded once every RollingHistoryRefresh.
+type RollingWindows struct {
+	mu         sync.Mutex
+	blocks     map[string]*rollingBlock    // By block ID
+	buckets    map[time.Time]rollingBucket // Usage of the blocks from historyEnd, by UTC hour
+	history    map[time.Time]UsageTotal    // Usage before historyEnd, by UTC hour
+	historyEnd time.Time                   // Zero until a history is set
+	historyAt  time.Time                   // When the history was set
+}
+
+// NewRollingWindows creates rolling windows with no usage
+func NewRollingWindows() *RollingWindows {
+	return &RollingWindows{
+		blocks:  make(map[string]*rollingBlock),
+		buckets: make(map[time.Time]rollingBucket),
+		history: make(map[time.Time]UsageTotal),
+	}
+}
+
+// HistoryDue reports whether the history should be loaded, as none is set or it was set
+// more than RollingHistoryRefresh before now
+func (r *RollingWindows) HistoryDue(now time.Time) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.historyEnd.IsZero() || now.Sub(r.historyAt) >= RollingHistoryRefresh
+}
+
+// SetHistory sets the usage before the blocks: entries, as loaded at now for the last
+// RollingMonth, up to RollingWeek before now. Later usage comes from the blocks.
+func (r *RollingWindows) SetHistory(entries []models.UsageEntry, now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.historyEnd = now.Add(-RollingWeek).UTC().Truncate(time.Hour)
+	r.historyAt = now
+	r.history = make(map[time.Time]UsageTotal)
+	for i := range entries {
+		hour := entries[i].Timestamp.UTC().Truncate(time.Hour)
+		if !hour.Before(r.historyEnd) {
+			continue
+		}
+		usage := r.history[hour]
+		usage.add(UsageTotal{Tokens: entryTokens(&entries[i]), Cost: entries[i].CostUSD}, 1)
+		r.history[hour] = usage
+	}
+
+	// The blocks are summed again from the new end of the history
+	r.blocks = make(map[string]*rollingBlock)
+	r.buckets = make(map[time.Time]rollingBucket)
+}
+
+// Update brings the windows up to date with blocks, the current session blocks, and
+// returns the totals at now
+func (r *RollingWindows) Update(blocks []models.SessionBlock, now time.Time) RollingTotals {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	monthStart := now.Add(-RollingMonth).Truncate(time.Hour)
+	current := make(map[string]bool, len(blocks))
+	for i := range blocks {
+		block := &blocks[i]
+		if block.IsGap || block.EndTime.Before(monthStart) || block.EndTime.Before(r.historyEnd) {
+			continue
+		}
+		current[block.ID] = true
+
+		known, ok := r.blocks[block.ID]
+		if ok && known.entries == len(block.Entries) && known.cost == block.CostUSD {
+			continue
+		}
+		if ok {
+			r.remove(known)
+		}
+		r.blocks[block.ID] = r.add(block)
+	}
+
+	// Blocks that are gone, or fell out of the longest window, no longer count
+	for id, known := range r.blocks {
+		if !current[id] {
+			r.remove(known)
+			delete(r.blocks, id)
+		}
+	}
+	for hour := range r.buckets {
+		if hour.Before(monthStart) {
+			delete(r.buckets, hour)
+		}
+	}
+	for hour := range r.history {
+		if hour.Before(monthStart) {
+			delete(r.history, hour)
+		}
+	}
+
+	weekStart := now.Add(-RollingWeek).Truncate(time.Hour)
+	totals := RollingTotals{Partial: r.historyEnd.IsZero()}
+	count := func(hour time.Time, usage UsageTotal) {
+		if hour.After(now) {
+			return
+		}
+		totals.Last30Days.add(usage, 1)
+		if !hour.Before(weekStart) {
+			totals.Last7Days.add(usage, 1)
+		}
+	}
+	for hour, usage := range r.history {
+		count(hour, usage)
+	}
+	for hour, bucket := range r.buckets {
+		count(hour, bucket.usage)
+	}
+	return totals
+}
+
+// entryTokens returns the tokens of entry, calculating them when the total isn't set
+func entryTokens(entry *models.UsageEntry) int {
+	if entry.TotalTokens != 0 {
+		return entry.TotalTokens
+	}
+	return entry.CalculateTotalTokens()
+}
+
+// add sums block's entries from the end of the history into the hourly buckets and returns
+// what it added
+func (r *RollingWindows) add(block *models.SessionBlock) *rollingBlock {
+	added := &rollingBlock{
+		entries: len(block.Entries),
+		cost:    block.CostUSD,
+		hours:   make(map[time.Time]rollingBucket),
+	}
+	for i := range block.Entries {
+		entry := &block.Entries[i]
+		hour := entry.Timestamp.UTC().Truncate(time.Hour)
+		if hour.Before(r.historyEnd) {
+			continue // Counted in the history
+		}
+		bucket := added.hours[hour]
+		bucket.add(rollingBucket{usage: UsageTotal{Tokens: entryTokens(entry), Cost: entry.CostUSD}, entries: 1}, 1)
+		added.hours[hour] = bucket
+	}
+	for hour, usage := range added.hours {
+		bucket := r.buckets[hour]
+		bucket.add(usage, 1)
+		r.buckets[hour] = bucket
+	}
+	return added
+}
+
+// remove takes what a block added out of the hourly buckets
+func (r *RollingWindows) remove(block *rollingBlock) {
+	for hour, usage := range block.hours {
+		bucket, ok := r.buckets[hour]
+		if !ok {
+			continue
+		}
+		bucket.add(usage, -1)
+		if bucket.entries <= 0 {
+			delete(r.buckets, hour)
+			continue
+		}
+		r.buckets[hour] = bucket
+	}
+}
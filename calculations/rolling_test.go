@@ -0,0 +1,87 @@
+package calculations
+
+import (
+	"testing"
+	"time"
+
+	"github.com/penwyp/claudecat/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// newRollingBlock returns a block with one entry of 1,000 tokens costing $0.10 at each of times
+func newRollingBlock(id string, times ...time.Time) models.SessionBlock {
+	block := models.SessionBlock{ID: id}
+	for _, t := range times {
+		block.Entries = append(block.Entries, models.UsageEntry{Timestamp: t, TotalTokens: 1000, CostUSD: 0.1})
+		block.CostUSD += 0.1
+	}
+	if len(times) > 0 {
+		block.EndTime = times[len(times)-1].Add(time.Hour)
+	}
+	return block
+}
+
+func TestRollingWindows_Update(t *testing.T) {
+	now := time.Date(2025, 6, 30, 14, 30, 0, 0, time.UTC)
+	old := newRollingBlock("old", now.AddDate(0, 0, -40))
+	month := newRollingBlock("month", now.AddDate(0, 0, -20), now.AddDate(0, 0, -20).Add(time.Minute))
+	week := newRollingBlock("week", now.AddDate(0, 0, -3))
+	active := newRollingBlock("active", now.Add(-time.Hour), now.Add(-time.Minute))
+
+	windows := NewRollingWindows()
+	totals := windows.Update([]models.SessionBlock{old, month, week, active, {IsGap: true}}, now)
+	assert.Equal(t, 3000, totals.Last7Days.Tokens)
+	assert.InDelta(t, 0.3, totals.Last7Days.Cost, 1e-9)
+	assert.Equal(t, 5000, totals.Last30Days.Tokens)
+	assert.InDelta(t, 0.5, totals.Last30Days.Cost, 1e-9)
+	assert.True(t, totals.Partial, "no history is set")
+
+	// New usage in the active block is added once, whatever it replaces
+	active = newRollingBlock("active", now.Add(-time.Hour), now.Add(-time.Minute), now)
+	totals = windows.Update([]models.SessionBlock{old, month, week, active}, now)
+	assert.Equal(t, 4000, totals.Last7Days.Tokens)
+	assert.Equal(t, 6000, totals.Last30Days.Tokens)
+
+	// A week later the week block has left the 7-day window, and blocks no longer loaded count no more
+	later := now.AddDate(0, 0, 7)
+	totals = windows.Update([]models.SessionBlock{month, week}, later)
+	assert.Equal(t, 0, totals.Last7Days.Tokens)
+	assert.Equal(t, 3000, totals.Last30Days.Tokens)
+
+	totals = windows.Update([]models.SessionBlock{month, week}, later.AddDate(0, 0, 21))
+	assert.Equal(t, UsageTotal{}, totals.Last30Days)
+	assert.Empty(t, windows.buckets)
+}
+
+func TestRollingWindows_History(t *testing.T) {
+	now := time.Date(2025, 6, 30, 14, 30, 0, 0, time.UTC)
+	week := newRollingBlock("week", now.AddDate(0, 0, -3))
+	active := newRollingBlock("active", now.Add(-time.Hour))
+	// The blocks only reach back 8 days; a 30-day load also holds their entries
+	history := append(newRollingBlock("", now.AddDate(0, 0, -40), now.AddDate(0, 0, -20), now.AddDate(0, 0, -10)).Entries,
+		week.Entries...)
+	history = append(history, active.Entries...)
+
+	windows := NewRollingWindows()
+	assert.True(t, windows.HistoryDue(now))
+	windows.Update([]models.SessionBlock{week, active}, now)
+	windows.SetHistory(history, now)
+	assert.False(t, windows.HistoryDue(now.Add(time.Hour)))
+	assert.True(t, windows.HistoryDue(now.Add(RollingHistoryRefresh)))
+
+	totals := windows.Update([]models.SessionBlock{week, active}, now)
+	assert.False(t, totals.Partial)
+	assert.Equal(t, 2000, totals.Last7Days.Tokens)
+	assert.Equal(t, 4000, totals.Last30Days.Tokens, "entries in both the history and the blocks count once")
+
+	// The active block grows without reloading the history
+	active = newRollingBlock("active", now.Add(-time.Hour), now)
+	totals = windows.Update([]models.SessionBlock{week, active}, now)
+	assert.Equal(t, 3000, totals.Last7Days.Tokens)
+	assert.Equal(t, 5000, totals.Last30Days.Tokens)
+
+	// History leaves the 30-day window as time passes
+	totals = windows.Update([]models.SessionBlock{week, active}, now.AddDate(0, 0, 11))
+	assert.Equal(t, 4000, totals.Last30Days.Tokens)
+	assert.Len(t, windows.history, 1)
+}
@@ -0,0 +1,66 @@
+package calculations
+
+import (
+	"sort"
+	"time"
+
+	"github.com/penwyp/claudecat/models"
+)
+
+// UnknownSource is the source used for entries that weren't tagged with one
+const UnknownSource = "unknown"
+
+// SourceUsage is the usage of one source, such as Claude Code, Bedrock or Codex CLI
+type SourceUsage struct {
+	Source         string             `json:"source"`
+	TokenCounts    models.TokenCounts `json:"token_counts"`
+	TotalTokens    int                `json:"total_tokens"`
+	Cost           float64            `json:"cost"`
+	CostPercentage float64            `json:"cost_percentage"` // Share of the total cost
+	EntryCount     int                `json:"entry_count"`
+	LastUsed       time.Time          `json:"last_used"`
+}
+
+// AggregateBySource sums the usage of entries per source, most expensive first
+func AggregateBySource(entries []models.UsageEntry) []SourceUsage {
+	bySource := make(map[string]*SourceUsage)
+	var totalCost float64
+	for _, entry := range entries {
+		source := entry.Source
+		if source == "" {
+			source = UnknownSource
+		}
+		usage, ok := bySource[source]
+		if !ok {
+			usage = &SourceUsage{Source: source}
+			bySource[source] = usage
+		}
+		usage.TokenCounts.InputTokens += entry.InputTokens
+		usage.TokenCounts.OutputTokens += entry.OutputTokens
+		usage.TokenCounts.CacheCreationTokens += entry.CacheCreationTokens
+		usage.TokenCounts.CacheReadTokens += entry.CacheReadTokens
+		usage.TokenCounts.ThinkingTokens += entry.ThinkingTokens
+		usage.Cost += entry.CostUSD
+		usage.EntryCount++
+		if entry.Timestamp.After(usage.LastUsed) {
+			usage.LastUsed = entry.Timestamp
+		}
+		totalCost += entry.CostUSD
+	}
+
+	sources := make([]SourceUsage, 0, len(bySource))
+	for _, usage := range bySource {
+		usage.TotalTokens = usage.TokenCounts.TotalTokens()
+		if totalCost > 0 {
+			usage.CostPercentage = usage.Cost / totalCost * 100
+		}
+		sources = append(sources, *usage)
+	}
+	sort.Slice(sources, func(i, j int) bool {
+		if sources[i].Cost != sources[j].Cost {
+			return sources[i].Cost > sources[j].Cost
+		}
+		return sources[i].Source < sources[j].Source
+	})
+	return sources
+}
@@ -19,6 +19,7 @@ type PeriodStats struct {
 	OutputTokens        int                   `json:"output_tokens"`
 	CacheCreationTokens int                   `json:"cache_creation_tokens"`
 	CacheReadTokens     int                   `json:"cache_read_tokens"`
+	ThinkingTokens      int                   `json:"thinking_tokens"`
 	TotalTokens         int                   `json:"total_tokens"`
 	TotalCost           float64               `json:"total_cost"`
 	EntryCount          int                   `json:"entry_count"`
@@ -34,6 +35,7 @@ type ModelStats struct {
 	OutputTokens        int     `json:"output_tokens"`
 	CacheCreationTokens int     `json:"cache_creation_tokens"`
 	CacheReadTokens     int     `json:"cache_read_tokens"`
+	ThinkingTokens      int     `json:"thinking_tokens"`
 	TotalTokens         int     `json:"total_tokens"`
 	TotalCost           float64 `json:"total_cost"`
 	EntryCount          int     `json:"entry_count"`
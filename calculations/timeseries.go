@@ -0,0 +1,166 @@
+package calculations
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/penwyp/claudecat/models"
+)
+
+// TimeSeriesInterval is the width of the buckets of a TimeSeries
+type TimeSeriesInterval string
+
+const (
+	IntervalHourly TimeSeriesInterval = "hourly"
+	IntervalDaily  TimeSeriesInterval = "daily"
+)
+
+// ParseTimeSeriesInterval parses an interval name, ignoring case
+func ParseTimeSeriesInterval(name string) (TimeSeriesInterval, error) {
+	switch interval := TimeSeriesInterval(strings.ToLower(name)); interval {
+	case IntervalHourly, IntervalDaily:
+		return interval, nil
+	default:
+		return "", fmt.Errorf("invalid interval: %s (valid options: %s, %s)", name, IntervalHourly, IntervalDaily)
+	}
+}
+
+// ModelTrendPoint is the usage of one model within a time series bucket
+type ModelTrendPoint struct {
+	Model       string             `json:"model"`
+	TokenCounts models.TokenCounts `json:"token_counts"`
+	TotalTokens int                `json:"total_tokens"`
+	CostUSD     float64            `json:"cost_usd"`
+	Entries     int                `json:"entries"`
+	CostShare   float64            `json:"cost_share"` // Fraction of the bucket's cost, 0 to 1
+}
+
+// TimeSeriesBucket is the usage of one hour or day, per model
+type TimeSeriesBucket struct {
+	Start       time.Time         `json:"start"`
+	TotalTokens int               `json:"total_tokens"`
+	CostUSD     float64           `json:"cost_usd"`
+	Entries     int               `json:"entries"`
+	Models      []ModelTrendPoint `json:"models"` // Most expensive first; empty for idle buckets
+}
+
+// TimeSeries is the per-model usage of consecutive buckets, idle buckets included, so
+// shifts between models can be charted over time
+type TimeSeries struct {
+	Interval TimeSeriesInterval `json:"interval"`
+	Models   []string           `json:"models"` // Every model in the series, most expensive first
+	Buckets  []TimeSeriesBucket `json:"buckets"`
+}
+
+// TimeSeriesAggregator buckets usage entries into hours or days of a time zone
+type TimeSeriesAggregator struct {
+	interval TimeSeriesInterval
+	location *time.Location
+}
+
+// NewTimeSeriesAggregator creates an aggregator with buckets aligned to the given time
+// zone, or to UTC when location is nil
+func NewTimeSeriesAggregator(interval TimeSeriesInterval, location *time.Location) *TimeSeriesAggregator {
+	if interval != IntervalHourly {
+		interval = IntervalDaily
+	}
+	if location == nil {
+		location = time.UTC
+	}
+	return &TimeSeriesAggregator{interval: interval, location: location}
+}
+
+// Aggregate buckets entries from the first entry's bucket through the last entry's
+func (a *TimeSeriesAggregator) Aggregate(entries []models.UsageEntry) TimeSeries {
+	series := TimeSeries{Interval: a.interval, Models: []string{}, Buckets: []TimeSeriesBucket{}}
+	if len(entries) == 0 {
+		return series
+	}
+
+	perBucket := make(map[int64]map[string]*ModelTrendPoint) // By bucket start in Unix seconds
+	modelCosts := make(map[string]float64)
+	var first, last time.Time
+	for _, entry := range entries {
+		start := a.bucketStart(entry.Timestamp)
+		if first.IsZero() || start.Before(first) {
+			first = start
+		}
+		if start.After(last) {
+			last = start
+		}
+
+		points, ok := perBucket[start.Unix()]
+		if !ok {
+			points = make(map[string]*ModelTrendPoint)
+			perBucket[start.Unix()] = points
+		}
+		point, ok := points[entry.Model]
+		if !ok {
+			point = &ModelTrendPoint{Model: entry.Model}
+			points[entry.Model] = point
+		}
+		point.TokenCounts.InputTokens += entry.InputTokens
+		point.TokenCounts.OutputTokens += entry.OutputTokens
+		point.TokenCounts.CacheCreationTokens += entry.CacheCreationTokens
+		point.TokenCounts.CacheReadTokens += entry.CacheReadTokens
+		point.TokenCounts.ThinkingTokens += entry.ThinkingTokens
+		point.TotalTokens += entry.TotalTokens
+		point.CostUSD += entry.CostUSD
+		point.Entries++
+		modelCosts[entry.Model] += entry.CostUSD
+	}
+
+	for start := first; !start.After(last); start = a.next(start) {
+		bucket := TimeSeriesBucket{Start: start, Models: []ModelTrendPoint{}}
+		for _, point := range perBucket[start.Unix()] {
+			bucket.TotalTokens += point.TotalTokens
+			bucket.CostUSD += point.CostUSD
+			bucket.Entries += point.Entries
+			bucket.Models = append(bucket.Models, *point)
+		}
+		for i := range bucket.Models {
+			if bucket.CostUSD > 0 {
+				bucket.Models[i].CostShare = bucket.Models[i].CostUSD / bucket.CostUSD
+			}
+		}
+		sort.Slice(bucket.Models, func(i, j int) bool {
+			if bucket.Models[i].CostUSD != bucket.Models[j].CostUSD {
+				return bucket.Models[i].CostUSD > bucket.Models[j].CostUSD
+			}
+			return bucket.Models[i].Model < bucket.Models[j].Model
+		})
+		series.Buckets = append(series.Buckets, bucket)
+	}
+
+	for model := range modelCosts {
+		series.Models = append(series.Models, model)
+	}
+	sort.Slice(series.Models, func(i, j int) bool {
+		ci, cj := modelCosts[series.Models[i]], modelCosts[series.Models[j]]
+		if ci != cj {
+			return ci > cj
+		}
+		return series.Models[i] < series.Models[j]
+	})
+	return series
+}
+
+// bucketStart returns the start of the hour or day containing t
+func (a *TimeSeriesAggregator) bucketStart(t time.Time) time.Time {
+	t = t.In(a.location)
+	if a.interval == IntervalHourly {
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, a.location)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, a.location)
+}
+
+// next returns the start of the bucket following the one starting at start, stepping by
+// calendar day so daylight saving changes keep days aligned to midnight
+func (a *TimeSeriesAggregator) next(start time.Time) time.Time {
+	if a.interval == IntervalHourly {
+		return start.Add(time.Hour)
+	}
+	return start.AddDate(0, 0, 1)
+}
@@ -0,0 +1,75 @@
+package calculations
+
+import (
+	"testing"
+	"time"
+
+	"github.com/penwyp/claudecat/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeSeriesAggregator_Daily(t *testing.T) {
+	day := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	entries := []models.UsageEntry{
+		{Timestamp: day.Add(9 * time.Hour), Model: "claude-sonnet-4-20250514", InputTokens: 100, TotalTokens: 100, CostUSD: 1},
+		{Timestamp: day.Add(10 * time.Hour), Model: "claude-opus-4-20250514", OutputTokens: 300, TotalTokens: 300, CostUSD: 3},
+		{Timestamp: day.Add(50 * time.Hour), Model: "claude-opus-4-20250514", TotalTokens: 50, CostUSD: 2},
+	}
+
+	series := NewTimeSeriesAggregator(IntervalDaily, time.UTC).Aggregate(entries)
+	assert.Equal(t, IntervalDaily, series.Interval)
+	assert.Equal(t, []string{"claude-opus-4-20250514", "claude-sonnet-4-20250514"}, series.Models)
+	require.Len(t, series.Buckets, 3, "the idle day in between is included")
+
+	first := series.Buckets[0]
+	assert.Equal(t, day, first.Start)
+	assert.Equal(t, 400, first.TotalTokens)
+	assert.Equal(t, 4.0, first.CostUSD)
+	require.Len(t, first.Models, 2)
+	assert.Equal(t, "claude-opus-4-20250514", first.Models[0].Model)
+	assert.Equal(t, 300, first.Models[0].TokenCounts.OutputTokens)
+	assert.InDelta(t, 0.75, first.Models[0].CostShare, 1e-9)
+	assert.InDelta(t, 0.25, first.Models[1].CostShare, 1e-9)
+
+	assert.Empty(t, series.Buckets[1].Models)
+	assert.Equal(t, 0, series.Buckets[1].Entries)
+	assert.Equal(t, 1, series.Buckets[2].Entries)
+}
+
+func TestTimeSeriesAggregator_HourlyInLocation(t *testing.T) {
+	loc := time.FixedZone("UTC+8", 8*60*60)
+	start := time.Date(2025, 6, 1, 23, 30, 0, 0, time.UTC) // 07:30 on June 2 in UTC+8
+	entries := []models.UsageEntry{
+		{Timestamp: start, Model: "claude-sonnet-4-20250514", CostUSD: 1},
+		{Timestamp: start.Add(20 * time.Minute), Model: "claude-sonnet-4-20250514", CostUSD: 1},
+		{Timestamp: start.Add(2 * time.Hour), Model: "claude-sonnet-4-20250514", CostUSD: 1},
+	}
+
+	series := NewTimeSeriesAggregator(IntervalHourly, loc).Aggregate(entries)
+	require.Len(t, series.Buckets, 3)
+	assert.Equal(t, "2025-06-02 07:00", series.Buckets[0].Start.In(loc).Format("2006-01-02 15:04"))
+	assert.Equal(t, 2, series.Buckets[0].Entries, "23:50 UTC is 07:50, the same hour as the first entry")
+	assert.Equal(t, 0, series.Buckets[1].Entries)
+	assert.Equal(t, 1, series.Buckets[2].Entries)
+
+	// Daily buckets follow local midnight
+	daily := NewTimeSeriesAggregator(IntervalDaily, loc).Aggregate(entries)
+	require.Len(t, daily.Buckets, 1)
+	assert.Equal(t, 3, daily.Buckets[0].Entries)
+}
+
+func TestTimeSeriesAggregator_Empty(t *testing.T) {
+	series := NewTimeSeriesAggregator(IntervalHourly, nil).Aggregate(nil)
+	assert.Empty(t, series.Buckets)
+	assert.Empty(t, series.Models)
+}
+
+func TestParseTimeSeriesInterval(t *testing.T) {
+	interval, err := ParseTimeSeriesInterval("Hourly")
+	require.NoError(t, err)
+	assert.Equal(t, IntervalHourly, interval)
+
+	_, err = ParseTimeSeriesInterval("weekly")
+	assert.Error(t, err)
+}
@@ -4,19 +4,19 @@ import (
 	"encoding/csv"
 	"fmt"
 	"os"
-	"path"
-	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/bytedance/sonic"
-	"github.com/penwyp/claudecat/cache"
 	"github.com/penwyp/claudecat/config"
+	"github.com/penwyp/claudecat/fileio"
 	"github.com/penwyp/claudecat/internal"
 	"github.com/penwyp/claudecat/logging"
 	"github.com/penwyp/claudecat/models"
+	"github.com/penwyp/claudecat/pkg/claudecat"
+	"github.com/penwyp/claudecat/timeutil"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -32,6 +32,9 @@ var (
 	analyzeBreakdown           bool
 	analyzeReset               bool
 	analyzeEnableDeduplication bool
+
+	// analyzeLocation is the timezone used for hour/day/week/month boundaries
+	analyzeLocation = time.Local
 )
 
 var analyzeCmd = &cobra.Command{
@@ -70,21 +73,19 @@ Examples:
 		}
 
 		// Initialize global logger for usage_loader cache logging
-		logging.InitLogger(cfg.App.LogLevel, cfg.App.LogFile, cfg.Debug.Enabled)
+		if err := initLogging(cfg); err != nil {
+			return err
+		}
 
 		// Reset cache if requested
 		if analyzeReset {
-			// Use file-based cache for clearing
-			cacheDir := cfg.Cache.Dir
-			if cacheDir[:2] == "~/" {
-				homeDir, _ := os.UserHomeDir()
-				cacheDir = filepath.Join(homeDir, cacheDir[2:])
-			}
-			fileCache, err := cache.NewFileBasedSummaryCache(cacheDir)
+			summaryStore, err := claudecat.OpenSummaryStore(cfg)
 			if err != nil {
 				return fmt.Errorf("failed to open cache: %w", err)
 			}
-			if err := fileCache.Clear(); err != nil {
+			err = summaryStore.Clear()
+			summaryStore.Close()
+			if err != nil {
 				return fmt.Errorf("failed to clear cache: %w", err)
 			}
 			logging.GetLogger().Info("Cache cleared successfully")
@@ -103,6 +104,7 @@ Examples:
 		}
 
 		// Apply filtering and grouping
+		analyzeLocation = resolveLocation(cfg)
 		results = applyFilters(results)
 		results = applyGrouping(results)
 		results = applySorting(results)
@@ -123,7 +125,7 @@ func init() {
 	analyzeCmd.Flags().StringVar(&analyzeTo, "to", "", "end date (YYYY-MM-DD or YYYY-MM-DD HH:MM:SS)")
 
 	// Grouping flags
-	analyzeCmd.Flags().StringVar(&analyzeGroupBy, "group-by", "", "group by field (model, project, day, week, month)")
+	analyzeCmd.Flags().StringVar(&analyzeGroupBy, "group-by", "", "group by field (model, project, source, day, week, month)")
 
 	// Sorting and limiting flags
 	analyzeCmd.Flags().StringVar(&analyzeSortBy, "sort-by", "timestamp", "sort by field (timestamp, cost, tokens, model)")
@@ -160,10 +162,8 @@ func applyAnalyzeFlags(cfg *config.Config, args []string) error {
 		cfg.Data.Paths = args
 	}
 
-	homeDir, _ := os.UserHomeDir()
 	if len(cfg.Data.Paths) == 0 {
-		p := path.Join(homeDir, ".claude", "projects")
-		cfg.Data.Paths = []string{p}
+		cfg.Data.Paths = fileio.DefaultDataPaths()
 	}
 
 	// Use format as alias for output if provided
@@ -203,8 +203,10 @@ func applyAnalyzeFlags(cfg *config.Config, args []string) error {
 		}
 	}
 
-	// Pricing flags are now handled globally in root.go applyRunFlags()
-	// No need to process them here as they're applied via persistent flags
+	// Apply the global pricing, cost mode and validation flags
+	if err := applyDataFlags(cfg); err != nil {
+		return err
+	}
 
 	// Apply deduplication if set
 	if analyzeEnableDeduplication {
@@ -270,6 +272,8 @@ func applyGrouping(results []models.AnalysisResult) []models.AnalysisResult {
 	// Regular grouping logic
 	groups := make(map[string][]models.AnalysisResult)
 
+	periods := make(map[string]models.PeriodBounds)
+
 	for _, result := range results {
 		var key string
 		switch analyzeGroupBy {
@@ -280,15 +284,15 @@ func applyGrouping(results []models.AnalysisResult) []models.AnalysisResult {
 			if key == "" {
 				key = "unknown"
 			}
-		case "day":
-			key = result.Timestamp.Format("2006-01-02")
-		case "hour":
-			key = result.Timestamp.Format("2006-01-02 15:00")
-		case "week":
-			year, week := result.Timestamp.ISOWeek()
-			key = fmt.Sprintf("%d-W%02d", year, week)
-		case "month":
-			key = result.Timestamp.Format("2006-01")
+		case "source":
+			key = result.Source
+			if key == "" {
+				key = "unknown"
+			}
+		case "hour", "day", "week", "month":
+			bounds, _ := models.PeriodFor(result.Timestamp, analyzeGroupBy, analyzeLocation)
+			key = bounds.Key
+			periods[key] = bounds
 		case "session":
 			key = result.SessionID
 		default:
@@ -312,6 +316,10 @@ func applyGrouping(results []models.AnalysisResult) []models.AnalysisResult {
 			Timestamp: groupResults[0].Timestamp,
 			SessionID: groupResults[0].SessionID,
 			Project:   groupResults[0].Project,
+			Source:    groupResults[0].Source,
+		}
+		if bounds, ok := periods[groupKey]; ok {
+			agg.Period = &bounds
 		}
 
 		// Aggregate values and collect unique models
@@ -321,6 +329,7 @@ func applyGrouping(results []models.AnalysisResult) []models.AnalysisResult {
 			agg.OutputTokens += result.OutputTokens
 			agg.CacheCreationTokens += result.CacheCreationTokens
 			agg.CacheReadTokens += result.CacheReadTokens
+			agg.ThinkingTokens += result.ThinkingTokens
 			agg.TotalTokens += result.TotalTokens
 			agg.CostUSD += result.CostUSD
 			if result.Model != "" {
@@ -356,18 +365,8 @@ func applyBreakdownGrouping(results []models.AnalysisResult) []models.AnalysisRe
 
 	// First pass: group by time period and model
 	for _, result := range results {
-		var timeKey string
-		switch analyzeGroupBy {
-		case "hour":
-			timeKey = result.Timestamp.Format("2006-01-02 15:00")
-		case "day":
-			timeKey = result.Timestamp.Format("2006-01-02")
-		case "week":
-			year, week := result.Timestamp.ISOWeek()
-			timeKey = fmt.Sprintf("%d-W%02d", year, week)
-		case "month":
-			timeKey = result.Timestamp.Format("2006-01")
-		}
+		bounds, _ := models.PeriodFor(result.Timestamp, analyzeGroupBy, analyzeLocation)
+		timeKey := bounds.Key
 
 		if groups[timeKey] == nil {
 			groups[timeKey] = &modelData{
@@ -376,6 +375,7 @@ func applyBreakdownGrouping(results []models.AnalysisResult) []models.AnalysisRe
 					GroupKey:  timeKey,
 					Model:     "TOTAL",
 					Timestamp: result.Timestamp,
+					Period:    &bounds,
 				},
 			}
 		}
@@ -386,6 +386,7 @@ func applyBreakdownGrouping(results []models.AnalysisResult) []models.AnalysisRe
 				GroupKey:  timeKey,
 				Model:     result.Model,
 				Timestamp: result.Timestamp,
+				Period:    &bounds,
 			}
 		}
 
@@ -394,6 +395,7 @@ func applyBreakdownGrouping(results []models.AnalysisResult) []models.AnalysisRe
 		modelResult.OutputTokens += result.OutputTokens
 		modelResult.CacheCreationTokens += result.CacheCreationTokens
 		modelResult.CacheReadTokens += result.CacheReadTokens
+		modelResult.ThinkingTokens += result.ThinkingTokens
 		modelResult.TotalTokens += result.TotalTokens
 		modelResult.CostUSD += result.CostUSD
 		modelResult.Count++
@@ -404,6 +406,7 @@ func applyBreakdownGrouping(results []models.AnalysisResult) []models.AnalysisRe
 		totalResult.OutputTokens += result.OutputTokens
 		totalResult.CacheCreationTokens += result.CacheCreationTokens
 		totalResult.CacheReadTokens += result.CacheReadTokens
+		totalResult.ThinkingTokens += result.ThinkingTokens
 		totalResult.TotalTokens += result.TotalTokens
 		totalResult.CostUSD += result.CostUSD
 		totalResult.Count++
@@ -510,6 +513,8 @@ func outputTableWithoutBreakdown(results []models.AnalysisResult) error {
 		groupColumnHeader = "Project"
 	case "model":
 		groupColumnHeader = "Model"
+	case "source":
+		groupColumnHeader = "Source"
 	case "session":
 		groupColumnHeader = "Session"
 	case "hour", "day", "week", "month":
@@ -519,15 +524,15 @@ func outputTableWithoutBreakdown(results []models.AnalysisResult) error {
 	}
 
 	// Create table headers
-	headers := []string{groupColumnHeader, "Input", "Output", "Cache Create", "Cache Read", "Total Tokens", "Cost (USD)"}
-	if analyzeGroupBy != "model" && analyzeGroupBy != "project" {
+	headers := []string{groupColumnHeader, "Input", "Output", "Cache Create", "Cache Read", "Thinking", "Total Tokens", "Cost (USD)"}
+	if analyzeGroupBy != "model" && analyzeGroupBy != "project" && analyzeGroupBy != "source" {
 		// Add Models column for time-based groupings
-		headers = []string{groupColumnHeader, "Models", "Input", "Output", "Cache Create", "Cache Read", "Total Tokens", "Cost (USD)"}
+		headers = []string{groupColumnHeader, "Models", "Input", "Output", "Cache Create", "Cache Read", "Thinking", "Total Tokens", "Cost (USD)"}
 	}
 	table := newTableFormatter(headers)
 
 	// For all groupings, we can use the aggregated results directly
-	if analyzeGroupBy != "model" && analyzeGroupBy != "project" && analyzeGroupBy != "session" {
+	if analyzeGroupBy != "model" && analyzeGroupBy != "project" && analyzeGroupBy != "source" && analyzeGroupBy != "session" {
 		// Time-based groupings - add Models column
 		// Sort results by group key
 		sort.Slice(results, func(i, j int) bool {
@@ -543,6 +548,7 @@ func outputTableWithoutBreakdown(results []models.AnalysisResult) error {
 				formatWithCommas(result.OutputTokens),
 				formatWithCommas(result.CacheCreationTokens),
 				formatWithCommas(result.CacheReadTokens),
+				formatWithCommas(result.ThinkingTokens),
 				formatWithCommas(result.TotalTokens),
 				formatCost(result.CostUSD),
 			}
@@ -552,7 +558,7 @@ func outputTableWithoutBreakdown(results []models.AnalysisResult) error {
 		// Add summary row
 		addSummaryRowWithModels(table, results)
 	} else {
-		// For non-time-based groupings (model, project, session)
+		// For non-time-based groupings (model, project, source, session)
 		// Sort results by group key
 		sort.Slice(results, func(i, j int) bool {
 			return results[i].GroupKey < results[j].GroupKey
@@ -566,6 +572,7 @@ func outputTableWithoutBreakdown(results []models.AnalysisResult) error {
 				formatWithCommas(result.OutputTokens),
 				formatWithCommas(result.CacheCreationTokens),
 				formatWithCommas(result.CacheReadTokens),
+				formatWithCommas(result.ThinkingTokens),
 				formatWithCommas(result.TotalTokens),
 				formatCost(result.CostUSD),
 			}
@@ -589,7 +596,7 @@ func outputTableWithBreakdown(results []models.AnalysisResult) error {
 		if analyzeGroupBy == "day" {
 			dateKey = result.GroupKey
 		} else {
-			dateKey = result.Timestamp.Format("2006-01-02")
+			dateKey = timeutil.DateKey(result.Timestamp, analyzeLocation)
 		}
 
 		if dateGroups[dateKey] == nil {
@@ -615,6 +622,7 @@ func outputTableWithBreakdown(results []models.AnalysisResult) error {
 		stat.outputTokens += result.OutputTokens
 		stat.cacheCreationTokens += result.CacheCreationTokens
 		stat.cacheReadTokens += result.CacheReadTokens
+		stat.thinkingTokens += result.ThinkingTokens
 		stat.totalTokens += result.TotalTokens
 		stat.costUSD += result.CostUSD
 
@@ -623,12 +631,13 @@ func outputTableWithBreakdown(results []models.AnalysisResult) error {
 		group.totalOutputTokens += result.OutputTokens
 		group.totalCacheCreationTokens += result.CacheCreationTokens
 		group.totalCacheReadTokens += result.CacheReadTokens
+		group.totalThinkingTokens += result.ThinkingTokens
 		group.totalTotalTokens += result.TotalTokens
 		group.totalCostUSD += result.CostUSD
 	}
 
 	// Create table
-	headers := []string{"Date", "Models", "Input", "Output", "Cache Create", "Cache Read", "Total Tokens", "Cost (USD)"}
+	headers := []string{"Date", "Models", "Input", "Output", "Cache Create", "Cache Read", "Thinking", "Total Tokens", "Cost (USD)"}
 	table := newTableFormatter(headers)
 
 	// Sort dates
@@ -663,6 +672,7 @@ func outputTableWithBreakdown(results []models.AnalysisResult) error {
 			formatWithCommas(group.totalOutputTokens),
 			formatWithCommas(group.totalCacheCreationTokens),
 			formatWithCommas(group.totalCacheReadTokens),
+			formatWithCommas(group.totalThinkingTokens),
 			formatWithCommas(group.totalTotalTokens),
 			formatCost(group.totalCostUSD),
 		}
@@ -678,6 +688,7 @@ func outputTableWithBreakdown(results []models.AnalysisResult) error {
 				formatWithCommas(stat.outputTokens),
 				formatWithCommas(stat.cacheCreationTokens),
 				formatWithCommas(stat.cacheReadTokens),
+				formatWithCommas(stat.thinkingTokens),
 				formatWithCommas(stat.totalTokens),
 				formatCost(stat.costUSD),
 			}
@@ -706,6 +717,7 @@ type dateGroup struct {
 	outputTokens        int
 	cacheCreationTokens int
 	cacheReadTokens     int
+	thinkingTokens      int
 	totalTokens         int
 	costUSD             float64
 }
@@ -715,6 +727,7 @@ type modelStat struct {
 	outputTokens        int
 	cacheCreationTokens int
 	cacheReadTokens     int
+	thinkingTokens      int
 	totalTokens         int
 	costUSD             float64
 }
@@ -726,6 +739,7 @@ type dateGroupWithModels struct {
 	totalOutputTokens        int
 	totalCacheCreationTokens int
 	totalCacheReadTokens     int
+	totalThinkingTokens      int
 	totalTotalTokens         int
 	totalCostUSD             float64
 }
@@ -750,10 +764,10 @@ func outputCSV(results []models.AnalysisResult) error {
 	// Header
 	if analyzeGroupBy != "" {
 		_ = writer.Write([]string{"Group", "Model", "Entries", "Input Tokens", "Output Tokens",
-			"Cache Creation", "Cache Read", "Total Tokens", "Cost USD"})
+			"Cache Creation", "Cache Read", "Thinking Tokens", "Total Tokens", "Cost USD"})
 	} else {
 		_ = writer.Write([]string{"Timestamp", "Model", "Session", "Input Tokens", "Output Tokens",
-			"Cache Creation", "Cache Read", "Total Tokens", "Cost USD"})
+			"Cache Creation", "Cache Read", "Thinking Tokens", "Total Tokens", "Cost USD"})
 	}
 
 	// Data rows
@@ -767,6 +781,7 @@ func outputCSV(results []models.AnalysisResult) error {
 				strconv.Itoa(result.OutputTokens),
 				strconv.Itoa(result.CacheCreationTokens),
 				strconv.Itoa(result.CacheReadTokens),
+				strconv.Itoa(result.ThinkingTokens),
 				strconv.Itoa(result.TotalTokens),
 				fmt.Sprintf("%.4f", result.CostUSD),
 			})
@@ -779,6 +794,7 @@ func outputCSV(results []models.AnalysisResult) error {
 				strconv.Itoa(result.OutputTokens),
 				strconv.Itoa(result.CacheCreationTokens),
 				strconv.Itoa(result.CacheReadTokens),
+				strconv.Itoa(result.ThinkingTokens),
 				strconv.Itoa(result.TotalTokens),
 				fmt.Sprintf("%.4f", result.CostUSD),
 			})
@@ -796,7 +812,7 @@ func outputSummary(results []models.AnalysisResult) error {
 
 	// Calculate totals
 	var totalEntries int
-	var totalInputTokens, totalOutputTokens, totalCacheCreation, totalCacheRead, totalTokens int
+	var totalInputTokens, totalOutputTokens, totalCacheCreation, totalCacheRead, totalThinking, totalTokens int
 	var totalCost float64
 	modelCounts := make(map[string]int)
 	modelStats := make(map[string]struct {
@@ -804,6 +820,7 @@ func outputSummary(results []models.AnalysisResult) error {
 		OutputTokens        int
 		CacheCreationTokens int
 		CacheReadTokens     int
+		ThinkingTokens      int
 		TotalTokens         int
 		Cost                float64
 	})
@@ -818,6 +835,7 @@ func outputSummary(results []models.AnalysisResult) error {
 		totalOutputTokens += result.OutputTokens
 		totalCacheCreation += result.CacheCreationTokens
 		totalCacheRead += result.CacheReadTokens
+		totalThinking += result.ThinkingTokens
 		totalTokens += result.TotalTokens
 		totalCost += result.CostUSD
 		modelCounts[result.Model]++
@@ -828,6 +846,7 @@ func outputSummary(results []models.AnalysisResult) error {
 		stat.OutputTokens += result.OutputTokens
 		stat.CacheCreationTokens += result.CacheCreationTokens
 		stat.CacheReadTokens += result.CacheReadTokens
+		stat.ThinkingTokens += result.ThinkingTokens
 		stat.TotalTokens += result.TotalTokens
 		stat.Cost += result.CostUSD
 		modelStats[result.Model] = stat
@@ -845,6 +864,7 @@ func outputSummary(results []models.AnalysisResult) error {
 	fmt.Printf("  Output Tokens: %d\n", totalOutputTokens)
 	fmt.Printf("  Cache Creation: %d\n", totalCacheCreation)
 	fmt.Printf("  Cache Read: %d\n", totalCacheRead)
+	fmt.Printf("  Thinking Tokens: %d\n", totalThinking)
 	fmt.Printf("  Total Tokens: %d\n", totalTokens)
 	fmt.Printf("\nCost: $%.4f\n\n", totalCost)
 
@@ -866,6 +886,7 @@ func outputSummary(results []models.AnalysisResult) error {
 				OutputTokens        int
 				CacheCreationTokens int
 				CacheReadTokens     int
+				ThinkingTokens      int
 				TotalTokens         int
 				Cost                float64
 			}
@@ -886,6 +907,7 @@ func outputSummary(results []models.AnalysisResult) error {
 			fmt.Printf("  Output Tokens: %d\n", b.stats.OutputTokens)
 			fmt.Printf("  Cache Creation: %d\n", b.stats.CacheCreationTokens)
 			fmt.Printf("  Cache Read: %d\n", b.stats.CacheReadTokens)
+			fmt.Printf("  Thinking Tokens: %d\n", b.stats.ThinkingTokens)
 			fmt.Printf("  Total Tokens: %d\n", b.stats.TotalTokens)
 			fmt.Printf("  Cost: $%.4f (%.1f%%)\n", b.stats.Cost, (b.stats.Cost/totalCost)*100)
 		}
@@ -1072,6 +1094,7 @@ func (tf *tableFormatter) isNumericColumn(colIndex int) bool {
 	return strings.Contains(header, "input") ||
 		strings.Contains(header, "output") ||
 		strings.Contains(header, "cache") ||
+		strings.Contains(header, "thinking") ||
 		strings.Contains(header, "tokens") ||
 		strings.Contains(header, "cost")
 }
@@ -1174,7 +1197,7 @@ func getModelPriority(model string) int {
 
 // addSummaryRow adds a summary row to the table for non-breakdown mode
 func addSummaryRow(table *tableFormatter, dateGroups map[string]*dateGroup) {
-	var totalInput, totalOutput, totalCacheCreation, totalCacheRead, totalTokens int
+	var totalInput, totalOutput, totalCacheCreation, totalCacheRead, totalThinking, totalTokens int
 	var totalCost float64
 	var allModels = make(map[string]bool)
 
@@ -1183,6 +1206,7 @@ func addSummaryRow(table *tableFormatter, dateGroups map[string]*dateGroup) {
 		totalOutput += group.outputTokens
 		totalCacheCreation += group.cacheCreationTokens
 		totalCacheRead += group.cacheReadTokens
+		totalThinking += group.thinkingTokens
 		totalTokens += group.totalTokens
 		totalCost += group.costUSD
 
@@ -1209,6 +1233,7 @@ func addSummaryRow(table *tableFormatter, dateGroups map[string]*dateGroup) {
 		formatWithCommas(totalOutput),
 		formatWithCommas(totalCacheCreation),
 		formatWithCommas(totalCacheRead),
+		formatWithCommas(totalThinking),
 		formatWithCommas(totalTokens),
 		formatCost(totalCost),
 	}
@@ -1217,7 +1242,7 @@ func addSummaryRow(table *tableFormatter, dateGroups map[string]*dateGroup) {
 
 // addSummaryRowSimple adds a summary row for non-time-based groupings
 func addSummaryRowSimple(table *tableFormatter, results []models.AnalysisResult) {
-	var totalInput, totalOutput, totalCacheCreation, totalCacheRead, totalTokens int
+	var totalInput, totalOutput, totalCacheCreation, totalCacheRead, totalThinking, totalTokens int
 	var totalCost float64
 
 	for _, result := range results {
@@ -1225,6 +1250,7 @@ func addSummaryRowSimple(table *tableFormatter, results []models.AnalysisResult)
 		totalOutput += result.OutputTokens
 		totalCacheCreation += result.CacheCreationTokens
 		totalCacheRead += result.CacheReadTokens
+		totalThinking += result.ThinkingTokens
 		totalTokens += result.TotalTokens
 		totalCost += result.CostUSD
 	}
@@ -1239,6 +1265,7 @@ func addSummaryRowSimple(table *tableFormatter, results []models.AnalysisResult)
 		formatWithCommas(totalOutput),
 		formatWithCommas(totalCacheCreation),
 		formatWithCommas(totalCacheRead),
+		formatWithCommas(totalThinking),
 		formatWithCommas(totalTokens),
 		formatCost(totalCost),
 	}
@@ -1247,7 +1274,7 @@ func addSummaryRowSimple(table *tableFormatter, results []models.AnalysisResult)
 
 // addSummaryRowWithModels adds a summary row for time-based groupings with models column
 func addSummaryRowWithModels(table *tableFormatter, results []models.AnalysisResult) {
-	var totalInput, totalOutput, totalCacheCreation, totalCacheRead, totalTokens int
+	var totalInput, totalOutput, totalCacheCreation, totalCacheRead, totalThinking, totalTokens int
 	var totalCost float64
 	allModels := make(map[string]bool)
 
@@ -1256,6 +1283,7 @@ func addSummaryRowWithModels(table *tableFormatter, results []models.AnalysisRes
 		totalOutput += result.OutputTokens
 		totalCacheCreation += result.CacheCreationTokens
 		totalCacheRead += result.CacheReadTokens
+		totalThinking += result.ThinkingTokens
 		totalTokens += result.TotalTokens
 		totalCost += result.CostUSD
 		
@@ -1286,6 +1314,7 @@ func addSummaryRowWithModels(table *tableFormatter, results []models.AnalysisRes
 		formatWithCommas(totalOutput),
 		formatWithCommas(totalCacheCreation),
 		formatWithCommas(totalCacheRead),
+		formatWithCommas(totalThinking),
 		formatWithCommas(totalTokens),
 		formatCost(totalCost),
 	}
@@ -1294,7 +1323,7 @@ func addSummaryRowWithModels(table *tableFormatter, results []models.AnalysisRes
 
 // addSummaryRowBreakdown adds a summary row to the table for breakdown mode
 func addSummaryRowBreakdown(table *tableFormatter, dateGroups map[string]*dateGroupWithModels) {
-	var totalInput, totalOutput, totalCacheCreation, totalCacheRead, totalTokens int
+	var totalInput, totalOutput, totalCacheCreation, totalCacheRead, totalThinking, totalTokens int
 	var totalCost float64
 
 	for _, group := range dateGroups {
@@ -1302,6 +1331,7 @@ func addSummaryRowBreakdown(table *tableFormatter, dateGroups map[string]*dateGr
 		totalOutput += group.totalOutputTokens
 		totalCacheCreation += group.totalCacheCreationTokens
 		totalCacheRead += group.totalCacheReadTokens
+		totalThinking += group.totalThinkingTokens
 		totalTokens += group.totalTotalTokens
 		totalCost += group.totalCostUSD
 	}
@@ -1317,6 +1347,7 @@ func addSummaryRowBreakdown(table *tableFormatter, dateGroups map[string]*dateGr
 		formatWithCommas(totalOutput),
 		formatWithCommas(totalCacheCreation),
 		formatWithCommas(totalCacheRead),
+		formatWithCommas(totalThinking),
 		formatWithCommas(totalTokens),
 		formatCost(totalCost),
 	}
@@ -0,0 +1,305 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/penwyp/claudecat/cache"
+	"github.com/penwyp/claudecat/config"
+	"github.com/penwyp/claudecat/fileio"
+	"github.com/penwyp/claudecat/logging"
+	"github.com/penwyp/claudecat/models"
+	"github.com/penwyp/claudecat/models/pricing"
+	"github.com/penwyp/claudecat/pkg/claudecat"
+	"github.com/spf13/cobra"
+)
+
+var (
+	benchRuns   int
+	benchOutput string
+)
+
+var benchCmd = &cobra.Command{
+	Use:   "bench [flags] [path...]",
+	Short: "Time each stage of loading your usage data",
+	Long: `Time the stages of a refresh against your actual data and print a breakdown, to
+find out why refreshes are slow:
+
+  discover      finding the JSONL files in the data paths
+  parse         reading and parsing every file, without the summary cache
+  cache write   loading into an empty summary cache, which parses and stores every file
+  cache read    loading again, served from the summaries just written
+  transform     grouping the entries into session blocks
+
+The cache stages use a scratch cache of the configured backend, so your cache is left
+untouched. With --runs each stage is repeated and the median reported.
+
+Examples:
+  claudecat bench
+  claudecat bench --runs 3
+  claudecat bench ~/work/claude-data -o json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		validOutputs := []string{"table", "json"}
+		if !containsFold(validOutputs, benchOutput) {
+			return fmt.Errorf("invalid output format: %s (valid options: %s)",
+				benchOutput, strings.Join(validOutputs, ", "))
+		}
+		if benchRuns < 1 {
+			return fmt.Errorf("--runs must be at least 1")
+		}
+
+		cfg, err := loadSessionCommandConfig(cmd)
+		if err != nil {
+			return err
+		}
+		if len(args) > 0 {
+			cfg.Data.Paths = args
+		}
+
+		report, err := runBenchmark(cmd.Context(), cfg, benchRuns)
+		if err != nil {
+			return err
+		}
+		if strings.EqualFold(benchOutput, "json") {
+			return writeJSON(report)
+		}
+		outputBenchReport(report)
+		return nil
+	},
+}
+
+func init() {
+	benchCmd.Flags().IntVar(&benchRuns, "runs", 1, "times to repeat each stage; the median is reported")
+	benchCmd.Flags().StringVarP(&benchOutput, "output", "o", "table", "output format (table, json)")
+
+	rootCmd.AddCommand(benchCmd)
+}
+
+// benchStage is the timing of one stage
+type benchStage struct {
+	Stage    string        `json:"stage"`
+	Duration time.Duration `json:"duration_ns"`
+	Items    int           `json:"items"`           // Files or entries the stage handled
+	Unit     string        `json:"unit"`            // What Items counts
+	Bytes    int64         `json:"bytes,omitempty"` // Data read, for throughput
+}
+
+// benchReport is the result of claudecat bench
+type benchReport struct {
+	Paths        []string     `json:"paths"`
+	Files        int          `json:"files"`
+	Bytes        int64        `json:"bytes"`
+	Entries      int          `json:"entries"`
+	Blocks       int          `json:"blocks"`
+	CacheBackend string       `json:"cache_backend"`
+	Runs         int          `json:"runs"`
+	Stages       []benchStage `json:"stages"`
+}
+
+// runBenchmark times each stage of loading the data paths of cfg runs times
+func runBenchmark(ctx context.Context, cfg *config.Config, runs int) (*benchReport, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	var paths []string
+	for _, path := range claudecat.DataPaths(cfg) {
+		if _, err := os.Stat(path); err == nil {
+			paths = append(paths, path)
+		}
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no data paths found (configure data.paths or use --paths)")
+	}
+
+	report := &benchReport{Paths: paths, CacheBackend: cacheBackendName(cfg), Runs: runs}
+
+	// Discovery
+	var files []string
+	discover, err := timeRuns(runs, func() error {
+		var err error
+		files, err = fileio.DiscoverFilesInPaths(paths)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find usage files: %w", err)
+	}
+	report.Files = len(files)
+	for _, state := range fileio.StatFiles(files) {
+		report.Bytes += state.Size
+	}
+	report.Stages = append(report.Stages, benchStage{Stage: "discover", Duration: discover, Items: len(files), Unit: "files"})
+	if len(files) == 0 {
+		return report, nil
+	}
+
+	pricingProvider, err := pricing.CreatePricingProvider(&cfg.Data, claudecat.CacheDir(cfg))
+	if err != nil {
+		logging.LogWarnf("Failed to create pricing provider: %v", err)
+		pricingProvider = pricing.NewDefaultProvider()
+	}
+	opts := fileio.LoadUsageEntriesOptions{
+		Files:           files,
+		Mode:            claudecat.CostMode(cfg),
+		PricingProvider: pricingProvider,
+		Validator:       claudecat.EntryValidator(cfg),
+		Concurrency:     claudecat.Concurrency(cfg),
+		MemoryBudget:    cfg.Performance.MaxMemory,
+	}
+	load := func(store fileio.CacheStore) (*fileio.LoadUsageEntriesResult, error) {
+		opts := opts
+		opts.CacheStore = store
+		return fileio.LoadUsageEntriesContext(ctx, opts)
+	}
+
+	// Parsing without the cache
+	var entries []models.UsageEntry
+	parse, err := timeRuns(runs, func() error {
+		result, err := load(nil)
+		if err == nil {
+			entries = result.Entries
+		}
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load usage entries: %w", err)
+	}
+	report.Entries = len(entries)
+	report.Stages = append(report.Stages, benchStage{Stage: "parse", Duration: parse, Items: len(entries), Unit: "entries", Bytes: report.Bytes})
+
+	// Cache write and read, in a scratch cache that each write run starts empty
+	write, read, err := benchCache(cfg, runs, load)
+	if err != nil {
+		return nil, err
+	}
+	report.Stages = append(report.Stages,
+		benchStage{Stage: "cache write", Duration: write, Items: len(files), Unit: "files", Bytes: report.Bytes},
+		benchStage{Stage: "cache read", Duration: read, Items: len(files), Unit: "files"},
+	)
+
+	// Transformation, on a fresh copy each run since it sorts in place
+	analyzer := newSessionAnalyzer(cfg)
+	transform, _ := timeRuns(runs, func() error {
+		blocks := analyzer.TransformToBlocksContext(ctx, append([]models.UsageEntry(nil), entries...))
+		report.Blocks = len(blocks)
+		return nil
+	})
+	report.Stages = append(report.Stages, benchStage{Stage: "transform", Duration: transform, Items: len(entries), Unit: "entries"})
+	return report, nil
+}
+
+// benchCache times loading into an empty scratch cache and loading again from it
+func benchCache(cfg *config.Config, runs int, load func(fileio.CacheStore) (*fileio.LoadUsageEntriesResult, error)) (time.Duration, time.Duration, error) {
+	dir, err := os.MkdirTemp("", "claudecat-bench-")
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to create scratch cache: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	// A scratch directory, or key prefix, leaves the configured cache alone
+	backend := claudecat.SummaryBackend(cfg, false)
+	backend.Dir = dir
+	backend.KeyPrefix = fmt.Sprintf("claudecat:bench:%d:", os.Getpid())
+	store, err := cache.OpenSummaryStore(backend)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to open scratch %s cache: %w", cacheBackendName(cfg), err)
+	}
+	defer func() {
+		if err := store.Clear(); err != nil {
+			logging.LogWarnf("Failed to clear scratch cache: %v", err)
+		}
+		store.Close()
+	}()
+
+	var writes, reads []time.Duration
+	for i := 0; i < runs; i++ {
+		if err := store.Clear(); err != nil {
+			return 0, 0, fmt.Errorf("failed to clear scratch cache: %w", err)
+		}
+		write, err := timeRuns(1, func() error { _, err := load(store); return err })
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to load into the cache: %w", err)
+		}
+		read, err := timeRuns(1, func() error { _, err := load(store); return err })
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to load from the cache: %w", err)
+		}
+		writes = append(writes, write)
+		reads = append(reads, read)
+	}
+	return medianDuration(writes), medianDuration(reads), nil
+}
+
+// timeRuns calls fn runs times and returns the median duration
+func timeRuns(runs int, fn func() error) (time.Duration, error) {
+	durations := make([]time.Duration, 0, runs)
+	for i := 0; i < runs; i++ {
+		start := time.Now()
+		if err := fn(); err != nil {
+			return 0, err
+		}
+		durations = append(durations, time.Since(start))
+	}
+	return medianDuration(durations), nil
+}
+
+// medianDuration returns the median of durations, which it sorts
+func medianDuration(durations []time.Duration) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	return durations[len(durations)/2]
+}
+
+func outputBenchReport(report *benchReport) {
+	fmt.Printf("Data:    %s in %s files, %s entries, %s blocks\n",
+		formatBytes(report.Bytes), formatWithCommas(report.Files), formatWithCommas(report.Entries), formatWithCommas(report.Blocks))
+	fmt.Printf("Paths:   %s\n", strings.Join(report.Paths, ", "))
+	fmt.Printf("Cache:   %s (scratch)\n", report.CacheBackend)
+	if report.Runs > 1 {
+		fmt.Printf("Runs:    %d, median shown\n", report.Runs)
+	}
+	fmt.Println()
+
+	var total time.Duration
+	for _, stage := range report.Stages {
+		total += stage.Duration
+	}
+	fmt.Printf("%-12s %10s %6s  %s\n", "STAGE", "TIME", "SHARE", "THROUGHPUT")
+	for _, stage := range report.Stages {
+		share := 0.0
+		if total > 0 {
+			share = float64(stage.Duration) / float64(total) * 100
+		}
+		fmt.Printf("%-12s %10s %5.1f%%  %s\n", stage.Stage, formatBenchDuration(stage.Duration), share, benchThroughput(stage))
+	}
+}
+
+// benchThroughput describes how fast a stage handled its items and data
+func benchThroughput(stage benchStage) string {
+	seconds := stage.Duration.Seconds()
+	if seconds <= 0 || stage.Items == 0 {
+		return "-"
+	}
+	throughput := fmt.Sprintf("%s %s/s", formatWithCommas(int(float64(stage.Items)/seconds)), stage.Unit)
+	if stage.Bytes > 0 {
+		throughput += fmt.Sprintf(", %s/s", formatBytes(int64(float64(stage.Bytes)/seconds)))
+	}
+	return throughput
+}
+
+// formatBenchDuration rounds a duration to a readable precision
+func formatBenchDuration(d time.Duration) string {
+	switch {
+	case d >= time.Second:
+		return d.Round(time.Millisecond).String()
+	case d >= time.Millisecond:
+		return d.Round(10 * time.Microsecond).String()
+	default:
+		return d.Round(time.Microsecond).String()
+	}
+}
@@ -0,0 +1,262 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/penwyp/claudecat/cache"
+	"github.com/penwyp/claudecat/config"
+	"github.com/penwyp/claudecat/fileio"
+	"github.com/penwyp/claudecat/pkg/claudecat"
+	"github.com/spf13/cobra"
+)
+
+var cacheOutput string
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Inspect and maintain the file summary cache",
+	Long: `Inspect and maintain the cache of per-file usage summaries that lets claudecat
+skip re-parsing conversation logs that haven't changed.
+
+Examples:
+  claudecat cache stats     # Size, contents and expected hit rate
+  claudecat cache compact   # Drop summaries of deleted files and reclaim space
+  claudecat cache verify    # Rebuild corrupt and outdated summaries
+  claudecat cache clear     # Remove every summary`,
+}
+
+var cacheStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show cache size, contents and expected hit rate",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, store, err := openCacheCommandStore(cmd, true)
+		if err != nil {
+			return err
+		}
+		defer store.Close()
+
+		report, err := inspectSummaryCache(cfg, store)
+		if err != nil {
+			return err
+		}
+
+		if cacheOutput == "json" {
+			return writeJSON(struct {
+				Backend string `json:"backend"`
+				*cache.StoreReport
+			}{cacheBackendName(cfg), report})
+		}
+		outputCacheReport(cfg, report)
+		return nil
+	},
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove every cached summary",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		_, store, err := openCacheCommandStore(cmd, false)
+		if err != nil {
+			return err
+		}
+		defer store.Close()
+
+		if err := store.Clear(); err != nil {
+			return fmt.Errorf("failed to clear cache: %w", err)
+		}
+		fmt.Println("Cache cleared.")
+		return nil
+	},
+}
+
+var cacheCompactCmd = &cobra.Command{
+	Use:   "compact",
+	Short: "Remove summaries of deleted files and reclaim space",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		_, store, err := openCacheCommandStore(cmd, false)
+		if err != nil {
+			return err
+		}
+		defer store.Close()
+
+		before, err := store.StorageSize()
+		if err != nil {
+			return err
+		}
+		removed, err := cache.PruneStore(store, cache.SummaryDeleted)
+		if err != nil {
+			return fmt.Errorf("failed to prune cache: %w", err)
+		}
+		if err := store.Compact(); err != nil {
+			return fmt.Errorf("failed to compact cache: %w", err)
+		}
+		after, err := store.StorageSize()
+		if err != nil {
+			return err
+		}
+
+		if cacheOutput == "json" {
+			return writeJSON(map[string]interface{}{
+				"removed":              removed,
+				"storage_bytes_before": before,
+				"storage_bytes_after":  after,
+			})
+		}
+		fmt.Printf("Removed %d summaries of deleted files.\n", removed)
+		fmt.Printf("Storage: %s -> %s\n", formatBytes(before), formatBytes(after))
+		return nil
+	},
+}
+
+var cacheVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Check every summary and rebuild corrupt or outdated ones",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, store, err := openCacheCommandStore(cmd, false)
+		if err != nil {
+			return err
+		}
+
+		before, err := inspectSummaryCache(cfg, store)
+		if err != nil {
+			store.Close()
+			return err
+		}
+		removed, err := cache.PruneStore(store, cache.SummaryCorrupt, cache.SummaryOutdated)
+		if err != nil {
+			store.Close()
+			return fmt.Errorf("failed to remove invalid summaries: %w", err)
+		}
+		store.Close()
+
+		// Loading through the cache writes fresh summaries for every file without one
+		loadAllUsageEntries(cfg, false, true)
+
+		store, err = claudecat.OpenSummaryStore(cfg)
+		if err != nil {
+			return fmt.Errorf("failed to reopen cache: %w", err)
+		}
+		defer store.Close()
+		after, err := inspectSummaryCache(cfg, store)
+		if err != nil {
+			return err
+		}
+
+		if cacheOutput == "json" {
+			return writeJSON(map[string]interface{}{
+				"corrupt":  before.Corrupt,
+				"outdated": before.Outdated,
+				"removed":  removed,
+				"after":    after,
+			})
+		}
+		fmt.Printf("Found %d corrupt and %d outdated summaries; removed %d and rebuilt from the logs.\n",
+			before.Corrupt, before.Outdated, removed)
+		fmt.Println()
+		outputCacheReport(cfg, after)
+		return nil
+	},
+}
+
+func init() {
+	cacheCmd.PersistentFlags().StringVarP(&cacheOutput, "output", "o", "table", "output format (table, json)")
+	cacheCmd.PersistentFlags().StringSliceVarP(&runPaths, "paths", "p", nil, "data paths to scan (can be specified multiple times)")
+
+	cacheCmd.AddCommand(cacheStatsCmd)
+	cacheCmd.AddCommand(cacheClearCmd)
+	cacheCmd.AddCommand(cacheCompactCmd)
+	cacheCmd.AddCommand(cacheVerifyCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
+
+// openCacheCommandStore loads configuration, validates shared flags and opens the summary
+// cache, read-only for commands that only inspect it
+func openCacheCommandStore(cmd *cobra.Command, readOnly bool) (*config.Config, cache.SummaryStore, error) {
+	cfg, err := loadSessionCommandConfig(cmd)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	validOutputs := []string{"table", "json"}
+	if !containsFold(validOutputs, cacheOutput) {
+		return nil, nil, fmt.Errorf("invalid output format: %s (valid options: %s)",
+			cacheOutput, strings.Join(validOutputs, ", "))
+	}
+	cacheOutput = strings.ToLower(cacheOutput)
+
+	openStore := claudecat.OpenSummaryStore
+	if readOnly {
+		openStore = claudecat.OpenReadOnlySummaryStore
+	}
+	store, err := openStore(cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open %s cache: %w", cacheBackendName(cfg), err)
+	}
+	return cfg, store, nil
+}
+
+// inspectSummaryCache reports on the cache against the usage files currently on disk
+func inspectSummaryCache(cfg *config.Config, store cache.SummaryStore) (*cache.StoreReport, error) {
+	var paths []string
+	for _, path := range claudecat.DataPaths(cfg) {
+		if _, err := os.Stat(path); err == nil {
+			paths = append(paths, path)
+		}
+	}
+
+	files, err := fileio.DiscoverFilesInPaths(paths)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find usage files: %w", err)
+	}
+	report, err := cache.InspectStore(store, files)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect cache: %w", err)
+	}
+	return report, nil
+}
+
+func cacheBackendName(cfg *config.Config) string {
+	if cfg.Cache.Backend == "" {
+		return cache.BackendFile
+	}
+	return cfg.Cache.Backend
+}
+
+func outputCacheReport(cfg *config.Config, report *cache.StoreReport) {
+	location := claudecat.CacheDir(cfg)
+	switch cacheBackendName(cfg) {
+	case cache.BackendBolt:
+		location = filepath.Join(location, cache.BoltDBFileName)
+	case cache.BackendSQLite:
+		location = filepath.Join(location, cache.SQLiteDBFileName)
+	case cache.BackendRedis:
+		location = cfg.Cache.RedisURL
+	}
+
+	fmt.Printf("Backend:     %s (%s)\n", cacheBackendName(cfg), location)
+	fmt.Printf("Storage:     %s\n", formatBytes(report.StorageBytes))
+	fmt.Printf("Summaries:   %s\n", formatWithCommas(report.Summaries))
+	fmt.Printf("  Up to date: %s\n", formatWithCommas(report.UpToDate))
+	fmt.Printf("  Outdated:   %s\n", formatWithCommas(report.Outdated))
+	fmt.Printf("  Deleted:    %s\n", formatWithCommas(report.Deleted))
+	fmt.Printf("  Corrupt:    %s\n", formatWithCommas(report.Corrupt))
+	fmt.Printf("Usage files: %s (%s cached)\n", formatWithCommas(report.UsageFiles), formatWithCommas(report.CoveredFiles))
+	fmt.Printf("Hit rate:    %.1f%% expected on the next load\n", report.ExpectedHitRate*100)
+}
+
+// formatBytes formats a byte count with a binary unit
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
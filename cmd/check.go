@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/penwyp/claudecat/config"
+	"github.com/penwyp/claudecat/fileio"
+	"github.com/penwyp/claudecat/models"
+	"github.com/penwyp/claudecat/pkg/claudecat"
+	"github.com/spf13/cobra"
+)
+
+// checkExitExceeded is the exit code of the check command when a threshold was exceeded;
+// errors exit with 1
+const checkExitExceeded = 2
+
+var (
+	checkMaxCostToday     float64
+	checkMaxTokensToday   int
+	checkMaxCostSession   float64
+	checkMaxTokensSession int
+	checkMaxCostMonth     float64
+	checkMaxTokensMonth   int
+	checkProjects         []string
+	checkTags             []string
+)
+
+var checkCmd = &cobra.Command{
+	Use:   "check [flags] [path...]",
+	Short: "Exit non-zero when usage exceeds budget thresholds, for CI and hooks",
+	Long: `Evaluate usage against the given thresholds once, print a JSON verdict and exit
+with a status CI pipelines and pre-commit hooks can gate on:
+
+  0  every threshold holds
+  1  the check couldn't run, e.g. invalid flags
+  2  at least one threshold was exceeded
+
+Today and this month are in the configured timezone; with subscription.billing_day
+set, the month is the current billing cycle. The session is the active 5-hour session
+block, and its thresholds hold while no session is active. A threshold is exceeded
+when usage is above it. Logs last written before the earliest period checked are
+skipped unread, so checks stay fast however long the history grows.
+
+Examples:
+  claudecat check --max-cost-today 50
+  claudecat check --max-cost-today 50 --max-tokens-session 2000000
+  claudecat check --max-cost-month 500 --tag platform      # One team's monthly budget
+  claudecat check --max-cost-today 20 --project myrepo || exit 1`,
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadSessionCommandConfig(cmd)
+		if err != nil {
+			return err
+		}
+		if len(args) > 0 {
+			cfg.Data.Paths = args
+		}
+
+		if checkMaxCostToday < 0 || checkMaxTokensToday < 0 || checkMaxCostSession < 0 ||
+			checkMaxTokensSession < 0 || checkMaxCostMonth < 0 || checkMaxTokensMonth < 0 {
+			return fmt.Errorf("thresholds must not be negative")
+		}
+		if checkMaxCostToday == 0 && checkMaxTokensToday == 0 && checkMaxCostSession == 0 &&
+			checkMaxTokensSession == 0 && checkMaxCostMonth == 0 && checkMaxTokensMonth == 0 {
+			return fmt.Errorf("no thresholds given: set at least one --max-* flag")
+		}
+
+		verdict, err := runCheck(cmd.Context(), cfg, time.Now())
+		if err != nil {
+			return err
+		}
+		if err := writeJSON(verdict); err != nil {
+			return err
+		}
+		if verdict.Status == checkStatusFail {
+			return &ExitError{Code: checkExitExceeded}
+		}
+		return nil
+	},
+}
+
+func init() {
+	checkCmd.Flags().Float64Var(&checkMaxCostToday, "max-cost-today", 0, "maximum cost in USD today")
+	checkCmd.Flags().IntVar(&checkMaxTokensToday, "max-tokens-today", 0, "maximum tokens today")
+	checkCmd.Flags().Float64Var(&checkMaxCostSession, "max-cost-session", 0, "maximum cost in USD of the active session block")
+	checkCmd.Flags().IntVar(&checkMaxTokensSession, "max-tokens-session", 0, "maximum tokens of the active session block")
+	checkCmd.Flags().Float64Var(&checkMaxCostMonth, "max-cost-month", 0, "maximum cost in USD this month or billing cycle")
+	checkCmd.Flags().IntVar(&checkMaxTokensMonth, "max-tokens-month", 0, "maximum tokens this month or billing cycle")
+	checkCmd.Flags().StringSliceVar(&checkProjects, "project", nil, "only count these projects (substring match, can be specified multiple times)")
+	checkCmd.Flags().StringSliceVar(&checkTags, "tag", nil, "only count these cost allocation tags, or untagged (can be specified multiple times)")
+
+	rootCmd.AddCommand(checkCmd)
+}
+
+const (
+	checkStatusPass = "pass"
+	checkStatusFail = "fail"
+)
+
+// checkVerdict is the JSON output of the check command
+type checkVerdict struct {
+	Status    string           `json:"status"` // pass or fail
+	CheckedAt time.Time        `json:"checked_at"`
+	Checks    []thresholdCheck `json:"checks"`
+}
+
+// thresholdCheck is the outcome of one threshold
+type thresholdCheck struct {
+	Name     string     `json:"name"` // e.g. cost_today or tokens_session
+	Limit    float64    `json:"limit"`
+	Value    float64    `json:"value"`
+	Exceeded bool       `json:"exceeded"`
+	From     *time.Time `json:"from,omitempty"` // Start of the period counted, unset when no session is active
+	To       *time.Time `json:"to,omitempty"`   // End of the period counted, exclusive
+}
+
+// runCheck loads the usage the thresholds cover and evaluates them at now
+func runCheck(ctx context.Context, cfg *config.Config, now time.Time) (checkVerdict, error) {
+	location := resolveLocation(cfg)
+	today, err := models.PeriodFor(now, models.PeriodDay, location)
+	if err != nil {
+		return checkVerdict{}, err
+	}
+	month, err := models.PeriodFor(now, models.PeriodMonth, location)
+	if err != nil {
+		return checkVerdict{}, err
+	}
+
+	// Only load as far back as the earliest period checked; the active session may have
+	// started a session window before today
+	from := today.Start.Add(-cfg.Session.WindowDuration)
+	if checkMaxCostMonth > 0 || checkMaxTokensMonth > 0 {
+		from = month.Start
+	}
+	// Cached summaries only keep hourly timestamps, which can move entries across the day
+	// and session boundaries a gate enforces, so the logs in the window are always parsed
+	usage, err := claudecat.Load(ctx, cfg, claudecat.LoadOptions{HoursBack: int(now.Sub(from).Hours()) + 1, Quick: true})
+	if err != nil {
+		return checkVerdict{}, err
+	}
+	usage.Entries = fileio.EntryFilter{Projects: checkProjects, Tags: checkTags}.Apply(usage.Entries)
+
+	verdict := checkVerdict{Status: checkStatusPass, CheckedAt: now}
+	add := func(name string, limit, value float64, start, end time.Time) {
+		check := thresholdCheck{Name: name, Limit: limit, Value: value, Exceeded: value > limit}
+		if !start.IsZero() {
+			check.From, check.To = &start, &end
+		}
+		if check.Exceeded {
+			verdict.Status = checkStatusFail
+		}
+		verdict.Checks = append(verdict.Checks, check)
+	}
+	addPeriod := func(period string, entries []models.UsageEntry, start, end time.Time, maxCost float64, maxTokens int) {
+		var cost float64
+		var tokens int
+		for _, entry := range entries {
+			cost += entry.CostUSD
+			tokens += entry.TotalTokens
+		}
+		if maxCost > 0 {
+			add("cost_"+period, maxCost, cost, start, end)
+		}
+		if maxTokens > 0 {
+			add("tokens_"+period, float64(maxTokens), float64(tokens), start, end)
+		}
+	}
+
+	if checkMaxCostToday > 0 || checkMaxTokensToday > 0 {
+		entries := fileio.EntryFilter{Since: today.Start, Until: today.End}.Apply(usage.Entries)
+		addPeriod("today", entries, today.Start, today.End, checkMaxCostToday, checkMaxTokensToday)
+	}
+	if checkMaxCostSession > 0 || checkMaxTokensSession > 0 {
+		// Without an active session nothing counts against the session thresholds
+		var entries []models.UsageEntry
+		var start, end time.Time
+		if block := claudecat.Analyze(cfg, usage).ActiveBlock(); block != nil {
+			entries, start, end = block.Entries, block.StartTime, block.EndTime
+		}
+		addPeriod("session", entries, start, end, checkMaxCostSession, checkMaxTokensSession)
+	}
+	if checkMaxCostMonth > 0 || checkMaxTokensMonth > 0 {
+		entries := fileio.EntryFilter{Since: month.Start, Until: month.End}.Apply(usage.Entries)
+		addPeriod("month", entries, month.Start, month.End, checkMaxCostMonth, checkMaxTokensMonth)
+	}
+	return verdict, nil
+}
@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/bytedance/sonic"
+	"github.com/penwyp/claudecat/config"
+	"github.com/penwyp/claudecat/pkg/claudecat"
+	"github.com/spf13/cobra"
+)
+
+var (
+	configOutput    string
+	configEffective bool
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Validate and show the configuration",
+	Long: `Check configuration files before running claudecat, and show the settings it
+runs with. Settings are merged from the defaults, the config files, CLAUDECAT_
+environment variables and command line flags, each overriding the ones before.
+
+Every setting that is not a map or a list of structured values can be set from the
+environment: the key in upper case with dots replaced by underscores, e.g.
+CLAUDECAT_UI_THEME=light or CLAUDECAT_DATA_PATHS=/data/a,/data/b (lists are comma
+separated). CLAUDECAT_PATHS, CLAUDECAT_PLAN, CLAUDECAT_REFRESH_RATE,
+CLAUDECAT_TIMEZONE, CLAUDECAT_LOG_LEVEL and CLAUDECAT_DEBUG are short forms of the
+common ones. Unlike in config files, environment variables set to false or 0 take
+effect too. The CLAWCAT_ variables of earlier versions still apply where no CLAUDECAT_
+one is set, but are deprecated.
+
+Examples:
+  claudecat config validate                    # Check the config files in use
+  claudecat config validate ./claudecat.yaml   # Check a single file
+  claudecat config show                        # Settings changed from the defaults
+  claudecat config show --effective            # Every setting and where it came from`,
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate [file...]",
+	Short: "Check config files for syntax errors, unknown keys and invalid values",
+	Long: `Check config files for syntax errors, keys that are not settings (usually
+typos, which are otherwise ignored), values that don't parse, like invalid
+durations, and settings that fail validation, like unknown plan names.
+
+Without arguments the config files claudecat would load are checked, together
+with the environment and flags. The command fails when a problem is found.`,
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		files := args
+		if len(files) == 0 {
+			for _, path := range config.ConfigPaths() {
+				if _, err := os.Stat(os.ExpandEnv(path)); err == nil {
+					files = append(files, path)
+				}
+			}
+		}
+
+		valid := true
+		for _, path := range files {
+			cfg, err := config.CheckFile(path)
+			if err == nil {
+				err = config.NewStandardValidator().Validate(new(config.DefaultMerger).Merge(config.DefaultConfig(), cfg))
+			}
+			if err != nil {
+				valid = false
+				fmt.Printf("✗ %s\n%s\n", os.ExpandEnv(path), indentLines(err.Error(), "    "))
+				continue
+			}
+			fmt.Printf("✓ %s\n", os.ExpandEnv(path))
+		}
+
+		// The files are merged with the environment and flags into what actually runs
+		if len(args) == 0 {
+			if err := validateEffectiveConfig(cmd); err != nil {
+				valid = false
+				fmt.Printf("✗ effective configuration\n%s\n", indentLines(err.Error(), "    "))
+			} else {
+				fmt.Println("✓ effective configuration")
+			}
+		}
+
+		if !valid {
+			return fmt.Errorf("configuration is invalid")
+		}
+		return nil
+	},
+}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the settings claudecat runs with and where they came from",
+	Long: `Show the merged configuration: the defaults overridden by config files,
+environment variables and flags, with the source of each value. By default only
+settings that differ from the defaults are listed; --effective lists them all.
+Passwords and request headers are redacted.`,
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		validOutputs := []string{"table", "json"}
+		if !containsFold(validOutputs, configOutput) {
+			return fmt.Errorf("invalid output format: %s (valid options: %s)",
+				configOutput, strings.Join(validOutputs, ", "))
+		}
+
+		_, settings, err := loadConfigSettings(cmd)
+		if err != nil {
+			return err
+		}
+
+		shown := make([]config.Setting, 0, len(settings))
+		for _, setting := range settings {
+			if !configEffective && setting.Source == config.SourceDefault {
+				continue
+			}
+			if isSecretSetting(setting.Key) && !reflect.ValueOf(setting.Value).IsZero() {
+				setting.Value = "<redacted>"
+			}
+			shown = append(shown, setting)
+		}
+
+		if strings.EqualFold(configOutput, "json") {
+			return writeJSON(shown)
+		}
+		if len(shown) == 0 {
+			fmt.Println("All settings have their default values (use --effective to list them).")
+			return nil
+		}
+		table := newTableFormatter([]string{"Setting", "Value", "Source"})
+		for _, setting := range shown {
+			table.addRow([]string{setting.Key, formatSettingValue(setting.Value), setting.Source})
+		}
+		fmt.Println(table.render())
+		return nil
+	},
+}
+
+func init() {
+	configShowCmd.Flags().BoolVar(&configEffective, "effective", false, "list every setting, including defaults")
+	configShowCmd.Flags().StringVarP(&configOutput, "output", "o", "table", "output format (table, json)")
+
+	configCmd.AddCommand(configValidateCmd)
+	configCmd.AddCommand(configShowCmd)
+	rootCmd.AddCommand(configCmd)
+}
+
+// loadConfigSettings loads the configuration with the source of each setting, crediting
+// command line flags with what they change
+func loadConfigSettings(cmd *cobra.Command) (*config.Config, []config.Setting, error) {
+	cfg, settings, err := newConfigLoader(cmd).LoadWithSources()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if err := applyRunFlags(cfg); err != nil {
+		return nil, nil, fmt.Errorf("failed to apply command flags: %w", err)
+	}
+	config.Attribute(settings, cfg, "flags")
+	return cfg, settings, nil
+}
+
+// validateEffectiveConfig validates the configuration merged from every source
+func validateEffectiveConfig(cmd *cobra.Command) error {
+	cfg, _, err := loadConfigSettings(cmd)
+	if err != nil {
+		return err
+	}
+	if err := config.NewStandardValidator().Validate(cfg); err != nil {
+		return err
+	}
+	return claudecat.ApplySettings(cfg)
+}
+
+// isSecretSetting reports whether a setting may hold credentials
+func isSecretSetting(key string) bool {
+	return strings.HasSuffix(key, "password") || strings.HasSuffix(key, "headers")
+}
+
+// formatSettingValue renders a setting value for the table: lists and maps as JSON
+func formatSettingValue(value interface{}) string {
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Slice, reflect.Map:
+		if reflect.ValueOf(value).Len() == 0 {
+			return ""
+		}
+		data, err := sonic.Marshal(value)
+		if err == nil {
+			return string(data)
+		}
+	}
+	return fmt.Sprint(value)
+}
+
+// indentLines prefixes every line of text
+func indentLines(text, prefix string) string {
+	return prefix + strings.ReplaceAll(text, "\n", "\n"+prefix)
+}
@@ -0,0 +1,376 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/penwyp/claudecat/config"
+	"github.com/penwyp/claudecat/fileio"
+	"github.com/penwyp/claudecat/logging"
+	"github.com/penwyp/claudecat/models"
+	"github.com/penwyp/claudecat/pkg/claudecat"
+	"github.com/spf13/cobra"
+)
+
+var (
+	conversationsSortBy string
+	conversationsLimit  int
+	conversationsOutput string
+)
+
+// conversationLabelWidth is the widest title or prompt shown in the conversations table
+const conversationLabelWidth = 60
+
+var conversationsCmd = &cobra.Command{
+	Use:   "conversations",
+	Short: "Find which conversations used the most tokens",
+	Long: `Break usage down per conversation. Claude Code keeps one log per conversation;
+each is listed with its title, or its first prompt when Claude Code hasn't titled
+it, so the conversation that burned through a budget can be found. Titles and
+prompts are left out when privacy.redact_content is set.
+
+Examples:
+  claudecat conversations list                       # 20 most expensive conversations
+  claudecat conversations list --since 7d            # ... of the last week
+  claudecat conversations list --sort-by last        # Most recently active first
+  claudecat conversations list --project myrepo --limit 0
+  claudecat conversations show 3f2a                  # Details for one conversation
+  claudecat conversations list -o json               # Machine-readable output`,
+}
+
+var conversationsListCmd = &cobra.Command{
+	Use:   "list [flags]",
+	Short: "List conversations by cost",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		validSorts := []string{"cost", "tokens", "entries", "first", "last"}
+		if !containsFold(validSorts, conversationsSortBy) {
+			return fmt.Errorf("invalid sort field: %s (valid options: %s)",
+				conversationsSortBy, strings.Join(validSorts, ", "))
+		}
+		conversationsSortBy = strings.ToLower(conversationsSortBy)
+
+		filter, err := entryFilterFromFlags("", "")
+		if err != nil {
+			return err
+		}
+		conversations, err := loadConversations(cmd, filter)
+		if err != nil {
+			return err
+		}
+
+		sortConversations(conversations, conversationsSortBy)
+		if conversationsLimit > 0 && len(conversations) > conversationsLimit {
+			conversations = conversations[:conversationsLimit]
+		}
+
+		if conversationsOutput == "json" {
+			if conversations == nil {
+				conversations = []conversationStats{}
+			}
+			return writeJSON(conversations)
+		}
+		outputConversationList(conversations)
+		return nil
+	},
+}
+
+var conversationsShowCmd = &cobra.Command{
+	Use:   "show <session-id>",
+	Short: "Show details for a single conversation",
+	Long:  "Show details for a single conversation. The ID may be shortened to any prefix that matches only one conversation.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		conversations, err := loadConversations(cmd, fileio.EntryFilter{})
+		if err != nil {
+			return err
+		}
+
+		conversation, err := findConversation(conversations, args[0])
+		if err != nil {
+			return err
+		}
+
+		if conversationsOutput == "json" {
+			return writeJSON(conversation)
+		}
+		outputConversationDetail(conversation)
+		return nil
+	},
+}
+
+func init() {
+	conversationsCmd.PersistentFlags().StringVarP(&conversationsOutput, "output", "o", "table", "output format (table, json)")
+	conversationsCmd.PersistentFlags().StringSliceVarP(&runPaths, "paths", "p", nil, "data paths to scan (can be specified multiple times)")
+
+	conversationsListCmd.Flags().StringVar(&conversationsSortBy, "sort-by", "cost", "sort by field (cost, tokens, entries, first, last)")
+	conversationsListCmd.Flags().IntVar(&conversationsLimit, "limit", 20, "number of conversations to show (0 = all)")
+	addEntryFilterFlags(conversationsListCmd)
+
+	conversationsCmd.AddCommand(conversationsListCmd)
+	conversationsCmd.AddCommand(conversationsShowCmd)
+	rootCmd.AddCommand(conversationsCmd)
+}
+
+// conversationModelUsage is the usage of one model within a conversation
+type conversationModelUsage struct {
+	Entries             int     `json:"entries"`
+	InputTokens         int     `json:"input_tokens"`
+	OutputTokens        int     `json:"output_tokens"`
+	CacheCreationTokens int     `json:"cache_creation_tokens"`
+	CacheReadTokens     int     `json:"cache_read_tokens"`
+	ThinkingTokens      int     `json:"thinking_tokens"`
+	TotalTokens         int     `json:"total_tokens"`
+	CostUSD             float64 `json:"cost_usd"`
+}
+
+// conversationStats contains aggregated usage for a single conversation
+type conversationStats struct {
+	SessionID     string                             `json:"session_id"`
+	Title         string                             `json:"title,omitempty"`
+	FirstPrompt   string                             `json:"first_prompt,omitempty"`
+	Project       string                             `json:"project"`
+	Source        string                             `json:"source,omitempty"`
+	Path          string                             `json:"path,omitempty"`
+	FirstActivity time.Time                          `json:"first_activity"`
+	LastActivity  time.Time                          `json:"last_activity"`
+	Entries       int                                `json:"entries"`
+	TotalTokens   int                                `json:"total_tokens"`
+	CostUSD       float64                            `json:"cost_usd"`
+	PerModel      map[string]*conversationModelUsage `json:"per_model"`
+}
+
+// label returns the title of the conversation, its first prompt when it has none, or "-"
+func (c conversationStats) label() string {
+	switch {
+	case c.Title != "":
+		return c.Title
+	case c.FirstPrompt != "":
+		return c.FirstPrompt
+	default:
+		return "-"
+	}
+}
+
+// loadConversations loads the entries that pass filter and aggregates them per conversation,
+// with the titles and first prompts of their logs
+func loadConversations(cmd *cobra.Command, filter fileio.EntryFilter) ([]conversationStats, error) {
+	cfg, err := loadSessionCommandConfig(cmd)
+	if err != nil {
+		return nil, err
+	}
+	validOutputs := []string{"table", "json"}
+	if !containsFold(validOutputs, conversationsOutput) {
+		return nil, fmt.Errorf("invalid output format: %s (valid options: %s)",
+			conversationsOutput, strings.Join(validOutputs, ", "))
+	}
+	conversationsOutput = strings.ToLower(conversationsOutput)
+
+	// Cached summaries don't keep the session of each entry
+	entries, _ := loadAllUsageEntries(cfg, false, false)
+	conversations := buildConversationStats(filter.Apply(entries))
+	if !cfg.Privacy.RedactContent {
+		attachConversationInfo(cfg, conversations)
+	}
+	return conversations, nil
+}
+
+// buildConversationStats aggregates entries per session ID. Entries without one are left out.
+func buildConversationStats(entries []models.UsageEntry) []conversationStats {
+	bySession := make(map[string]*conversationStats)
+	for i := range entries {
+		entry := &entries[i]
+		if entry.SessionID == "" {
+			continue
+		}
+
+		stats, exists := bySession[entry.SessionID]
+		if !exists {
+			stats = &conversationStats{
+				SessionID:     entry.SessionID,
+				Project:       entry.Project,
+				Source:        entry.Source,
+				FirstActivity: entry.Timestamp,
+				LastActivity:  entry.Timestamp,
+				PerModel:      make(map[string]*conversationModelUsage),
+			}
+			bySession[entry.SessionID] = stats
+		}
+
+		tokens := entry.CalculateTotalTokens()
+		stats.Entries++
+		stats.TotalTokens += tokens
+		stats.CostUSD += entry.CostUSD
+		if entry.Timestamp.Before(stats.FirstActivity) {
+			stats.FirstActivity = entry.Timestamp
+		}
+		if entry.Timestamp.After(stats.LastActivity) {
+			stats.LastActivity = entry.Timestamp
+		}
+
+		model := models.NormalizeModelName(entry.Model)
+		usage, exists := stats.PerModel[model]
+		if !exists {
+			usage = &conversationModelUsage{}
+			stats.PerModel[model] = usage
+		}
+		usage.Entries++
+		usage.InputTokens += entry.InputTokens
+		usage.OutputTokens += entry.OutputTokens
+		usage.CacheCreationTokens += entry.CacheCreationTokens
+		usage.CacheReadTokens += entry.CacheReadTokens
+		usage.ThinkingTokens += entry.ThinkingTokens
+		usage.TotalTokens += tokens
+		usage.CostUSD += entry.CostUSD
+	}
+
+	result := make([]conversationStats, 0, len(bySession))
+	for _, stats := range bySession {
+		result = append(result, *stats)
+	}
+	return result
+}
+
+// attachConversationInfo adds the titles, first prompts and log paths of the conversations
+func attachConversationInfo(cfg *config.Config, conversations []conversationStats) {
+	if len(conversations) == 0 {
+		return
+	}
+	infos, err := fileio.ReadConversationInfos(claudecat.DataPaths(cfg))
+	if err != nil {
+		logging.LogWarnf("Failed to read conversation titles: %v", err)
+		return
+	}
+	for i := range conversations {
+		if info, ok := infos[conversations[i].SessionID]; ok {
+			conversations[i].Title = info.Title
+			conversations[i].FirstPrompt = info.FirstPrompt
+			conversations[i].Path = info.Path
+		}
+	}
+}
+
+// sortConversations sorts in place, most expensive, largest or most recent first
+func sortConversations(conversations []conversationStats, sortBy string) {
+	sort.Slice(conversations, func(i, j int) bool {
+		a, b := conversations[i], conversations[j]
+		switch sortBy {
+		case "tokens":
+			if a.TotalTokens != b.TotalTokens {
+				return a.TotalTokens > b.TotalTokens
+			}
+		case "entries":
+			if a.Entries != b.Entries {
+				return a.Entries > b.Entries
+			}
+		case "first":
+			if !a.FirstActivity.Equal(b.FirstActivity) {
+				return a.FirstActivity.After(b.FirstActivity)
+			}
+		case "last":
+			if !a.LastActivity.Equal(b.LastActivity) {
+				return a.LastActivity.After(b.LastActivity)
+			}
+		default:
+			if a.CostUSD != b.CostUSD {
+				return a.CostUSD > b.CostUSD
+			}
+		}
+		return a.SessionID < b.SessionID
+	})
+}
+
+// findConversation returns the conversation whose session ID is id or starts with it
+func findConversation(conversations []conversationStats, id string) (*conversationStats, error) {
+	var matches []*conversationStats
+	for i := range conversations {
+		if conversations[i].SessionID == id {
+			return &conversations[i], nil
+		}
+		if strings.HasPrefix(conversations[i].SessionID, id) {
+			matches = append(matches, &conversations[i])
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no conversation matches %q", id)
+	case 1:
+		return matches[0], nil
+	default:
+		return nil, fmt.Errorf("%q matches %d conversations, use a longer prefix", id, len(matches))
+	}
+}
+
+func outputConversationList(conversations []conversationStats) {
+	if len(conversations) == 0 {
+		fmt.Println("No conversations found.")
+		return
+	}
+
+	table := newTableFormatter([]string{"Conversation", "Last Active", "Project", "Entries", "Total Tokens", "Cost (USD)", "Title / First Prompt"})
+	for _, conversation := range conversations {
+		table.addRow([]string{
+			conversation.SessionID[:min(len(conversation.SessionID), 8)],
+			conversation.LastActivity.Local().Format("2006-01-02 15:04"),
+			conversation.Project,
+			formatWithCommas(conversation.Entries),
+			formatWithCommas(conversation.TotalTokens),
+			formatCost(conversation.CostUSD),
+			truncateLabel(conversation.label(), conversationLabelWidth),
+		})
+	}
+	fmt.Println(table.render())
+}
+
+func outputConversationDetail(conversation *conversationStats) {
+	fmt.Printf("Conversation: %s\n", conversation.SessionID)
+	if conversation.Title != "" {
+		fmt.Printf("Title:        %s\n", conversation.Title)
+	}
+	if conversation.FirstPrompt != "" {
+		fmt.Printf("First prompt: %s\n", truncateLabel(conversation.FirstPrompt, 200))
+	}
+	fmt.Printf("Project:      %s\n", conversation.Project)
+	if conversation.Path != "" {
+		fmt.Printf("Log:          %s\n", conversation.Path)
+	}
+	fmt.Printf("Active:       %s - %s\n",
+		conversation.FirstActivity.Local().Format("2006-01-02 15:04"),
+		conversation.LastActivity.Local().Format("2006-01-02 15:04"))
+	fmt.Printf("Entries:      %s\n", formatWithCommas(conversation.Entries))
+	fmt.Printf("Tokens:       %s\n", formatWithCommas(conversation.TotalTokens))
+	fmt.Printf("Cost:         %s\n", formatCost(conversation.CostUSD))
+
+	modelNames := make([]string, 0, len(conversation.PerModel))
+	for model := range conversation.PerModel {
+		modelNames = append(modelNames, model)
+	}
+	sortModelsByPreference(modelNames)
+
+	fmt.Println()
+	table := newTableFormatter([]string{"Model", "Entries", "Input", "Output", "Cache Create", "Cache Read", "Thinking", "Total Tokens", "Cost (USD)"})
+	for _, model := range modelNames {
+		usage := conversation.PerModel[model]
+		table.addRow([]string{
+			model,
+			formatWithCommas(usage.Entries),
+			formatWithCommas(usage.InputTokens),
+			formatWithCommas(usage.OutputTokens),
+			formatWithCommas(usage.CacheCreationTokens),
+			formatWithCommas(usage.CacheReadTokens),
+			formatWithCommas(usage.ThinkingTokens),
+			formatWithCommas(usage.TotalTokens),
+			formatCost(usage.CostUSD),
+		})
+	}
+	fmt.Println(table.render())
+}
+
+// truncateLabel shortens s to at most width runes
+func truncateLabel(s string, width int) string {
+	if runes := []rune(s); len(runes) > width {
+		return string(runes[:width-3]) + "..."
+	}
+	return s
+}
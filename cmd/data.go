@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/penwyp/claudecat/config"
+	"github.com/penwyp/claudecat/logging"
+	"github.com/penwyp/claudecat/models"
+	"github.com/penwyp/claudecat/pkg/claudecat"
+	"github.com/penwyp/claudecat/sessions"
+)
+
+// newSessionAnalyzer creates a session analyzer using the configured session window
+func newSessionAnalyzer(cfg *config.Config) *sessions.SessionAnalyzer {
+	return sessions.NewSessionAnalyzerWithDuration(cfg.Session.WindowDuration)
+}
+
+// newBlockAnalyzer creates a session analyzer for block reports and exports, which also
+// splits blocks as session.split_blocks selects. Limit resets are found in limitRecords.
+func newBlockAnalyzer(cfg *config.Config, limitRecords []map[string]interface{}) *sessions.SessionAnalyzer {
+	analyzer := newSessionAnalyzer(cfg)
+	segmentation, err := sessions.ParseSegmentation(cfg.Session.SplitBlocks)
+	if err != nil {
+		logging.LogWarnf("%v, not splitting blocks", err)
+	}
+	analyzer.SetSegmentation(segmentation, analyzer.DetectLimits(limitRecords))
+	return analyzer
+}
+
+// splitsAtLimitResets reports whether block reports need the raw limit records
+func splitsAtLimitResets(cfg *config.Config) bool {
+	segmentation, _ := sessions.ParseSegmentation(cfg.Session.SplitBlocks)
+	return segmentation.LimitResets
+}
+
+// resolveLocation returns the timezone used for calendar boundaries: the UI timezone,
+// then the app timezone, then the system zone. Invalid names fall back to the system zone.
+func resolveLocation(cfg *config.Config) *time.Location {
+	loc, err := cfg.Location()
+	if err != nil {
+		logging.LogWarnf("%v, using local time", err)
+	}
+	return loc
+}
+
+// loadAllUsageEntries loads usage entries from every configured data path.
+// When includeLimits is set the raw limit message records are returned as well; no other
+// raw records are kept. The summary cache is only used when useCache is set and limit
+// records are not requested, since cached files carry neither raw records nor exact
+// per-message timestamps.
+func loadAllUsageEntries(cfg *config.Config, includeLimits, useCache bool) ([]models.UsageEntry, []map[string]interface{}) {
+	usage, err := claudecat.Load(context.Background(), cfg, claudecat.LoadOptions{
+		IncludeLimits: includeLimits,
+		UseCache:      useCache,
+	})
+	if err != nil {
+		logging.LogErrorf("Failed to load usage entries: %v", err)
+		return nil, nil
+	}
+	return usage.Entries, usage.LimitRecords
+}
+
+// loadUsageEntriesSince loads the usage entries logged since the given time with a quick
+// load, which skips logs last modified earlier and leaves the caches unchanged. A zero
+// since loads every entry like loadAllUsageEntries.
+func loadUsageEntriesSince(cfg *config.Config, since time.Time) []models.UsageEntry {
+	if since.IsZero() {
+		entries, _ := loadAllUsageEntries(cfg, false, false)
+		return entries
+	}
+	usage, err := claudecat.Load(context.Background(), cfg, claudecat.LoadOptions{
+		HoursBack: int(math.Ceil(time.Since(since).Hours())) + 1,
+		Quick:     true,
+	})
+	if err != nil {
+		logging.LogErrorf("Failed to load usage entries: %v", err)
+		return nil
+	}
+	return usage.Entries
+}
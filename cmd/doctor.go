@@ -0,0 +1,596 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/penwyp/claudecat/config"
+	"github.com/penwyp/claudecat/fileio"
+	"github.com/penwyp/claudecat/instance"
+	"github.com/penwyp/claudecat/models/pricing"
+	"github.com/penwyp/claudecat/orchestrator"
+	"github.com/penwyp/claudecat/pkg/claudecat"
+	"github.com/spf13/cobra"
+)
+
+var (
+	doctorURL        string
+	doctorTimeout    time.Duration
+	doctorOutput     string
+	doctorSample     int
+	doctorSkipWatch  bool
+	doctorQuarantine string
+)
+
+// doctorProbeModel is looked up to check that pricing works
+const doctorProbeModel = "claude-sonnet-4-20250514"
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor [flags] [path...]",
+	Short: "Diagnose configuration, data, cache and pricing problems",
+	Long: `Check everything claudecat depends on and print how to fix what is wrong:
+
+  config     config files parse and the settings are valid
+  data       data paths exist and are readable
+  logs       a sample of the most recent JSONL files parses
+  parse      every JSONL file is parsed and the lines that are not valid JSON counted
+  cache      the summary cache opens and holds no corrupt entries
+  instance   which claudecat monitor, if any, is using the cache
+  pricing    prices can be looked up, and fetched when pricing_source is litellm
+  monitor    a monitor started locally loads the data within --timeout
+
+With --url only the health of a running "claudecat serve" instance is fetched from its
+/healthz endpoint. The command fails when a check fails, so it can be used as a probe.
+With --quarantine the invalid lines are copied to a file, one JSON record each with the
+source file, line number, parse error and the original content, for inspection.
+
+Examples:
+  claudecat doctor
+  claudecat doctor --sample 20 --skip-monitor
+  claudecat doctor --url http://127.0.0.1:8080
+  claudecat doctor --quarantine bad-lines.jsonl
+  claudecat doctor -o json`,
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		validOutputs := []string{"table", "json"}
+		if !containsFold(validOutputs, doctorOutput) {
+			return fmt.Errorf("invalid output format: %s (valid options: %s)",
+				doctorOutput, strings.Join(validOutputs, ", "))
+		}
+		jsonOutput := strings.EqualFold(doctorOutput, "json")
+
+		if doctorURL != "" {
+			health, err := fetchRemoteHealth(doctorURL, doctorTimeout)
+			if err != nil {
+				return err
+			}
+			if jsonOutput {
+				if err := writeJSON(health); err != nil {
+					return err
+				}
+			} else {
+				outputHealth(health)
+			}
+			if health.Status == orchestrator.HealthUnhealthy {
+				return fmt.Errorf("monitoring is unhealthy")
+			}
+			return nil
+		}
+
+		report := runDiagnostics(cmd, args)
+		if jsonOutput {
+			if err := writeJSON(report); err != nil {
+				return err
+			}
+		} else {
+			outputDoctorReport(report)
+		}
+
+		if report.Status == diagnosticFail {
+			return fmt.Errorf("doctor found problems")
+		}
+		return nil
+	},
+}
+
+func init() {
+	doctorCmd.Flags().StringVar(&doctorURL, "url", "", "only check a running claudecat serve instance at this address")
+	doctorCmd.Flags().DurationVar(&doctorTimeout, "timeout", 30*time.Second, "how long to wait for the data to load, prices to download or the server to answer")
+	doctorCmd.Flags().StringVarP(&doctorOutput, "output", "o", "table", "output format (table, json)")
+	doctorCmd.Flags().IntVar(&doctorSample, "sample", 5, "number of recent JSONL files to parse")
+	doctorCmd.Flags().BoolVar(&doctorSkipWatch, "skip-monitor", false, "skip starting a monitor")
+	doctorCmd.Flags().StringVar(&doctorQuarantine, "quarantine", "", "copy lines that are not valid JSON to this file")
+
+	rootCmd.AddCommand(doctorCmd)
+}
+
+// diagnosticStatus is the outcome of a diagnostic, ordered from best to worst
+type diagnosticStatus string
+
+const (
+	diagnosticOK   diagnosticStatus = "ok"
+	diagnosticWarn diagnosticStatus = "warn"
+	diagnosticFail diagnosticStatus = "fail"
+)
+
+// severity orders statuses so the worst diagnostic determines the overall status
+func (s diagnosticStatus) severity() int {
+	switch s {
+	case diagnosticOK:
+		return 0
+	case diagnosticWarn:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// diagnostic is the result of a single doctor check
+type diagnostic struct {
+	Check   string           `json:"check"`
+	Status  diagnosticStatus `json:"status"`
+	Message string           `json:"message"`
+	Fix     string           `json:"fix,omitempty"` // What to do about a warning or failure
+}
+
+// doctorReport is the outcome of every doctor check
+type doctorReport struct {
+	Status      diagnosticStatus     `json:"status"`
+	Diagnostics []diagnostic         `json:"diagnostics"`
+	Health      *orchestrator.Health `json:"health,omitempty"`
+}
+
+// add records a diagnostic, keeping the worst status
+func (r *doctorReport) add(d diagnostic) {
+	r.Diagnostics = append(r.Diagnostics, d)
+	if d.Status.severity() > r.Status.severity() {
+		r.Status = d.Status
+	}
+}
+
+// runDiagnostics runs every local check
+func runDiagnostics(cmd *cobra.Command, args []string) doctorReport {
+	report := doctorReport{Status: diagnosticOK}
+
+	cfg := diagnoseConfig(cmd, &report)
+	if len(args) > 0 {
+		cfg.Data.Paths = args
+	}
+	if err := initLogging(cfg); err != nil {
+		report.add(diagnostic{Check: "config", Status: diagnosticWarn, Message: err.Error(),
+			Fix: "Check app.log_file points to a writable location"})
+	}
+
+	files := diagnoseDataPaths(cfg, &report)
+	diagnoseLogs(files, doctorSample, &report)
+	diagnoseParsing(files, doctorQuarantine, &report)
+	diagnoseCache(cfg, &report)
+	diagnoseInstance(cfg, &report)
+	diagnosePricing(cfg, doctorTimeout, &report)
+
+	if !doctorSkipWatch {
+		health, err := checkLocalHealth(cfg, doctorTimeout)
+		if err != nil {
+			report.add(diagnostic{Check: "monitor", Status: diagnosticFail, Message: err.Error()})
+		} else {
+			report.Health = &health
+			report.add(healthDiagnostic(health))
+		}
+	}
+	return report
+}
+
+// diagnoseConfig reports unreadable config files and invalid settings, and returns the
+// configuration the other checks use: as loaded, even when invalid, or the defaults
+func diagnoseConfig(cmd *cobra.Command, report *doctorReport) *config.Config {
+	var found []string
+	for _, path := range config.ConfigPaths() {
+		expanded := os.ExpandEnv(path)
+		if _, err := os.Stat(expanded); err != nil {
+			continue
+		}
+		if _, err := config.NewFileSource(path).Load(); err != nil {
+			report.add(diagnostic{Check: "config", Status: diagnosticFail, Message: err.Error(),
+				Fix: fmt.Sprintf("Fix the syntax of %s; its settings are ignored until then", expanded)})
+			continue
+		}
+		found = append(found, expanded)
+	}
+
+	cfg, err := newConfigLoader(cmd).LoadWithDefaults()
+	if err != nil {
+		report.add(diagnostic{Check: "config", Status: diagnosticFail, Message: err.Error()})
+		cfg = config.DefaultConfig()
+	}
+	if err := applyRunFlags(cfg); err != nil {
+		report.add(diagnostic{Check: "config", Status: diagnosticFail, Message: err.Error(),
+			Fix: "Correct the command line flags"})
+	}
+
+	if err := config.NewStandardValidator().Validate(cfg); err != nil {
+		report.add(diagnostic{Check: "config", Status: diagnosticFail, Message: err.Error(),
+			Fix: "Correct the listed settings; other commands refuse to start until then"})
+	} else if err := claudecat.ApplySettings(cfg); err != nil {
+		report.add(diagnostic{Check: "config", Status: diagnosticFail, Message: err.Error(),
+			Fix: "Correct the listed pattern in data.model_aliases, data.cost_allocation, data.include or data.exclude"})
+	} else if len(found) == 0 && report.Status == diagnosticOK {
+		report.add(diagnostic{Check: "config", Status: diagnosticOK, Message: "no config file found, using defaults"})
+	} else if len(found) > 0 {
+		report.add(diagnostic{Check: "config", Status: diagnosticOK, Message: "valid: " + strings.Join(found, ", ")})
+	}
+	return cfg
+}
+
+// diagnoseDataPaths checks every data path exists and is readable, and returns the usage
+// files found in them
+func diagnoseDataPaths(cfg *config.Config, report *doctorReport) []string {
+	configured := len(cfg.Data.Paths) > 0
+	var files []string
+	for _, path := range claudecat.DataPaths(cfg) {
+		info, err := os.Stat(path)
+		switch {
+		case os.IsNotExist(err) && configured:
+			report.add(diagnostic{Check: "data", Status: diagnosticFail, Message: fmt.Sprintf("%s does not exist", path),
+				Fix: "Correct data.paths or --paths"})
+			continue
+		case os.IsNotExist(err):
+			report.add(diagnostic{Check: "data", Status: diagnosticFail,
+				Message: fmt.Sprintf("no Claude Code data found (looked in %s)", strings.Join(fileio.CandidateDataPaths(), ", ")),
+				Fix:     fmt.Sprintf("Use Claude Code once, or point claudecat at its data with --paths or %s", fileio.ClaudeConfigDirEnv)})
+			continue
+		case err != nil:
+			report.add(diagnostic{Check: "data", Status: diagnosticFail, Message: err.Error(),
+				Fix: fmt.Sprintf("Grant read access to %s", path)})
+			continue
+		}
+
+		if info.IsDir() {
+			if _, err := os.ReadDir(path); err != nil {
+				report.add(diagnostic{Check: "data", Status: diagnosticFail, Message: err.Error(),
+					Fix: fmt.Sprintf("Grant read access with: chmod -R u+rX %s", path)})
+				continue
+			}
+		}
+
+		found, err := fileio.DiscoverFilesInPaths([]string{path})
+		if err != nil {
+			report.add(diagnostic{Check: "data", Status: diagnosticFail, Message: err.Error(),
+				Fix: fmt.Sprintf("Grant read access with: chmod -R u+rX %s", path)})
+			continue
+		}
+		if len(found) == 0 {
+			report.add(diagnostic{Check: "data", Status: diagnosticWarn, Message: fmt.Sprintf("%s holds no JSONL files yet", path),
+				Fix: "Usage appears once Claude Code has been used; otherwise check the path"})
+			continue
+		}
+		report.add(diagnostic{Check: "data", Status: diagnosticOK, Message: fmt.Sprintf("%s: %s files", path, formatWithCommas(len(found)))})
+		files = append(files, found...)
+	}
+	return files
+}
+
+// diagnoseLogs parses the most recently modified files in full
+func diagnoseLogs(files []string, sample int, report *doctorReport) {
+	if len(files) == 0 || sample <= 0 {
+		return
+	}
+
+	modified := make(map[string]time.Time, len(files))
+	for _, file := range files {
+		if info, err := os.Stat(file); err == nil {
+			modified[file] = info.ModTime()
+		}
+	}
+	recent := append([]string(nil), files...)
+	sort.SliceStable(recent, func(i, j int) bool { return modified[recent[i]].After(modified[recent[j]]) })
+	if len(recent) > sample {
+		recent = recent[:sample]
+	}
+
+	usage := 0
+	problems := 0
+	for _, file := range recent {
+		inspection, err := fileio.InspectUsageFile(file)
+		if err != nil {
+			problems++
+			report.add(diagnostic{Check: "logs", Status: diagnosticFail, Message: fmt.Sprintf("%s: %v", file, err),
+				Fix: fmt.Sprintf("Grant read access with: chmod u+r %s", file)})
+			continue
+		}
+		usage += inspection.UsageEntries
+		if inspection.InvalidLines > 0 {
+			problems++
+			report.add(diagnostic{Check: "logs", Status: diagnosticWarn,
+				Message: fmt.Sprintf("%s: %d of %d lines are not valid JSON (first at line %d: %s)",
+					file, inspection.InvalidLines, inspection.Lines, inspection.FirstInvalidLine, inspection.FirstError),
+				Fix: "Invalid lines are skipped; if many lines are affected the file is corrupt and can be moved out of the data path"})
+		}
+	}
+
+	switch {
+	case problems > 0:
+	case usage == 0:
+		report.add(diagnostic{Check: "logs", Status: diagnosticWarn,
+			Message: fmt.Sprintf("the %d most recent files hold no usage records", len(recent)),
+			Fix:     "Check the data path points at Claude Code's projects directory"})
+	default:
+		report.add(diagnostic{Check: "logs", Status: diagnosticOK,
+			Message: fmt.Sprintf("%d recent files parse cleanly (%s usage records)", len(recent), formatWithCommas(usage))})
+	}
+}
+
+// diagnoseParsing parses every usage file, reporting how many lines are not valid JSON and
+// copying them to the quarantine file when one is given
+func diagnoseParsing(files []string, quarantinePath string, report *doctorReport) {
+	if len(files) == 0 {
+		return
+	}
+
+	var out io.Writer
+	if quarantinePath != "" {
+		f, err := os.Create(quarantinePath)
+		if err != nil {
+			report.add(diagnostic{Check: "parse", Status: diagnosticFail, Message: fmt.Sprintf("failed to create quarantine file: %v", err),
+				Fix: "Choose a writable path for --quarantine"})
+			return
+		}
+		defer f.Close()
+		out = f
+	}
+	quarantine := fileio.NewQuarantine(out)
+
+	result, err := fileio.LoadUsageEntries(fileio.LoadUsageEntriesOptions{Files: files, Quarantine: quarantine})
+	if err != nil {
+		report.add(diagnostic{Check: "parse", Status: diagnosticFail, Message: err.Error()})
+		return
+	}
+	if err := quarantine.Err(); err != nil {
+		report.add(diagnostic{Check: "parse", Status: diagnosticWarn, Message: err.Error(),
+			Fix: "Check there is space left for the quarantine file"})
+	}
+
+	metadata := result.Metadata
+	if metadata.InvalidLines == 0 {
+		report.add(diagnostic{Check: "parse", Status: diagnosticOK,
+			Message: fmt.Sprintf("all %s files parse cleanly", formatWithCommas(len(files)))})
+		return
+	}
+
+	var worst []string
+	for i, file := range metadata.ParseErrors {
+		if i == 3 {
+			worst = append(worst, fmt.Sprintf("%d more", len(metadata.ParseErrors)-i))
+			break
+		}
+		worst = append(worst, fmt.Sprintf("%s: %d", file.Path, file.InvalidLines))
+	}
+	fix := "Invalid lines are skipped; copy them out for inspection with --quarantine FILE"
+	if quarantinePath != "" {
+		fix = fmt.Sprintf("The invalid lines were copied to %s", quarantinePath)
+	}
+	report.add(diagnostic{Check: "parse", Status: diagnosticWarn,
+		Message: fmt.Sprintf("%s invalid lines in %d of %s files (%s)", formatWithCommas(metadata.InvalidLines),
+			len(metadata.ParseErrors), formatWithCommas(len(files)), strings.Join(worst, ", ")),
+		Fix: fix})
+}
+
+// diagnoseCache checks the summary cache opens and reports corrupt summaries
+func diagnoseCache(cfg *config.Config, report *doctorReport) {
+	backend := cacheBackendName(cfg)
+	store, err := claudecat.OpenSummaryStore(cfg)
+	if err != nil {
+		fix := fmt.Sprintf("Check that %s is writable", claudecat.CacheDir(cfg))
+		if backend == "redis" {
+			fix = "Check cache.redis_url and that the Redis server is reachable"
+		}
+		report.add(diagnostic{Check: "cache", Status: diagnosticFail,
+			Message: fmt.Sprintf("failed to open %s cache: %v", backend, err), Fix: fix})
+		return
+	}
+	defer store.Close()
+
+	inspection, err := inspectSummaryCache(cfg, store)
+	if err != nil {
+		report.add(diagnostic{Check: "cache", Status: diagnosticFail, Message: err.Error(),
+			Fix: "Reset the cache with: claudecat cache clear"})
+		return
+	}
+	if inspection.Corrupt > 0 {
+		report.add(diagnostic{Check: "cache", Status: diagnosticWarn,
+			Message: fmt.Sprintf("%d of %d %s summaries are corrupt", inspection.Corrupt, inspection.Summaries, backend),
+			Fix:     "Rebuild them with: claudecat cache verify"})
+		return
+	}
+	report.add(diagnostic{Check: "cache", Status: diagnosticOK,
+		Message: fmt.Sprintf("%s: %s summaries, %.1f%% expected hit rate",
+			backend, formatWithCommas(inspection.Summaries), inspection.ExpectedHitRate*100)})
+}
+
+// diagnoseInstance reports the monitor holding the single-instance lock of the cache
+func diagnoseInstance(cfg *config.Config, report *doctorReport) {
+	holder, err := instance.Holder(claudecat.CacheDir(cfg))
+	switch {
+	case err != nil:
+		report.add(diagnostic{Check: "instance", Status: diagnosticWarn, Message: err.Error(),
+			Fix: fmt.Sprintf("Remove %s if no claudecat monitor is running", filepath.Join(claudecat.CacheDir(cfg), instance.FileName))})
+	case holder != nil:
+		report.add(diagnostic{Check: "instance", Status: diagnosticOK, Message: "monitor running: " + holder.String()})
+	default:
+		report.add(diagnostic{Check: "instance", Status: diagnosticOK, Message: "no monitor running"})
+	}
+}
+
+// diagnosePricing looks up a model's prices, fetching current prices first when they come
+// from LiteLLM
+func diagnosePricing(cfg *config.Config, timeout time.Duration, report *doctorReport) {
+	provider, err := pricing.CreatePricingProvider(&cfg.Data, claudecat.CacheDir(cfg))
+	if err != nil {
+		report.add(diagnostic{Check: "pricing", Status: diagnosticFail, Message: err.Error(),
+			Fix: "Set data.pricing_source to default or litellm"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	source := provider.GetProviderName()
+	if source == "litellm" && !cfg.Data.PricingOfflineMode {
+		if err := provider.RefreshPricing(ctx); err != nil {
+			report.add(diagnostic{Check: "pricing", Status: diagnosticWarn,
+				Message: fmt.Sprintf("failed to fetch current LiteLLM prices, using cached or bundled ones: %v", err),
+				Fix:     "Check network access to GitHub, or set data.pricing_offline_mode to stop fetching"})
+		}
+	}
+
+	if _, err := provider.GetPricing(ctx, doctorProbeModel); err != nil {
+		report.add(diagnostic{Check: "pricing", Status: diagnosticFail,
+			Message: fmt.Sprintf("no price for %s: %v", doctorProbeModel, err),
+			Fix:     "Set data.pricing_source to default to use the built-in prices"})
+		return
+	}
+	all, _ := provider.GetAllPricings(ctx)
+	report.add(diagnostic{Check: "pricing", Status: diagnosticOK,
+		Message: fmt.Sprintf("%s: %d models priced", source, len(all))})
+}
+
+// healthDiagnostic summarizes the monitoring health as a diagnostic
+func healthDiagnostic(health orchestrator.Health) diagnostic {
+	d := diagnostic{Check: "monitor", Status: diagnosticOK, Message: "monitoring is healthy"}
+	var issues []string
+	for _, check := range health.Checks {
+		if check.Status != orchestrator.HealthOK {
+			issues = append(issues, fmt.Sprintf("%s: %s", check.Name, check.Message))
+		}
+	}
+	switch health.Status {
+	case orchestrator.HealthUnhealthy:
+		d.Status, d.Message = diagnosticFail, strings.Join(issues, "; ")
+		d.Fix = "See the failures above; run with --debug for the full log"
+	case orchestrator.HealthDegraded:
+		d.Status, d.Message = diagnosticWarn, strings.Join(issues, "; ")
+	}
+	return d
+}
+
+// checkLocalHealth starts a monitor, waits for its first load and returns its health
+func checkLocalHealth(cfg *config.Config, timeout time.Duration) (orchestrator.Health, error) {
+	updateInterval := cfg.UI.RefreshRate
+	if updateInterval <= 0 {
+		updateInterval = 10 * time.Second
+	}
+
+	monitor := orchestrator.NewMonitoringOrchestrator(updateInterval, claudecat.DataPaths(cfg), cfg)
+	if err := monitor.Start(); err != nil {
+		return orchestrator.Health{}, fmt.Errorf("failed to start monitoring: %w", err)
+	}
+	defer monitor.Stop()
+
+	monitor.WaitForInitialData(timeout)
+	return monitor.GetHealth(), nil
+}
+
+// fetchRemoteHealth fetches the health reported by a claudecat serve instance
+func fetchRemoteHealth(baseURL string, timeout time.Duration) (orchestrator.Health, error) {
+	url := strings.TrimSuffix(baseURL, "/")
+	if !strings.HasSuffix(url, "/healthz") {
+		url += "/healthz"
+	}
+	if !strings.Contains(url, "://") {
+		url = "http://" + url
+	}
+
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Get(url)
+	if err != nil {
+		return orchestrator.Health{}, fmt.Errorf("failed to reach %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return orchestrator.Health{}, fmt.Errorf("failed to read health from %s: %w", url, err)
+	}
+
+	// Unhealthy instances answer 503 with the health report
+	var health orchestrator.Health
+	if err := sonic.Unmarshal(body, &health); err != nil || health.Status == "" {
+		return orchestrator.Health{}, fmt.Errorf("unexpected response from %s: %s", url, resp.Status)
+	}
+	return health, nil
+}
+
+// outputDoctorReport prints the diagnostics followed by the steps fixing the problems found
+func outputDoctorReport(report doctorReport) {
+	fmt.Printf("Status: %s\n\n", strings.ToUpper(string(report.Status)))
+
+	table := newTableFormatter([]string{"Check", "Status", "Details"})
+	var fixes []string
+	for _, d := range report.Diagnostics {
+		table.addRow([]string{d.Check, string(d.Status), d.Message})
+		if d.Status != diagnosticOK && d.Fix != "" && !containsString(fixes, d.Fix) {
+			fixes = append(fixes, d.Fix)
+		}
+	}
+	fmt.Println(table.render())
+
+	if len(fixes) > 0 {
+		fmt.Println("\nTo fix:")
+		for i, fix := range fixes {
+			fmt.Printf("  %d. %s\n", i+1, fix)
+		}
+	}
+}
+
+// outputHealth prints the health checks followed by the monitoring details
+func outputHealth(health orchestrator.Health) {
+	fmt.Printf("Status: %s\n\n", strings.ToUpper(string(health.Status)))
+
+	table := newTableFormatter([]string{"Check", "Status", "Details"})
+	for _, check := range health.Checks {
+		table.addRow([]string{check.Name, string(check.Status), check.Message})
+	}
+	fmt.Println(table.render())
+
+	fmt.Printf("\nData paths:     %s\n", strings.Join(health.DataPaths, ", "))
+	if health.LastSuccessfulFetch != nil {
+		fmt.Printf("Last fetch:     %s\n", health.LastSuccessfulFetch.Format(time.RFC3339))
+	} else {
+		fmt.Println("Last fetch:     never")
+	}
+	if health.CacheAgeSeconds >= 0 {
+		fmt.Printf("Cached data:    %s old\n", (time.Duration(health.CacheAgeSeconds) * time.Second).Round(time.Second))
+	} else {
+		fmt.Println("Cached data:    none")
+	}
+	fmt.Printf("Summary cache:  %s\n", enabledString(health.SummaryCache))
+	fmt.Printf("Fetch errors:   %d total, %d in a row\n", health.FetchErrors, health.ConsecutiveErrors)
+	if health.LastError != "" {
+		fmt.Printf("Last error:     %s\n", health.LastError)
+	}
+}
+
+// enabledString describes a boolean setting
+func enabledString(enabled bool) string {
+	if enabled {
+		return "enabled"
+	}
+	return "disabled"
+}
+
+// containsString reports whether values contains value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/penwyp/claudecat/config"
+	"github.com/penwyp/claudecat/events"
+	"github.com/penwyp/claudecat/logging"
+	"github.com/penwyp/claudecat/pkg/claudecat"
+	"github.com/penwyp/claudecat/sessions"
+	"github.com/spf13/cobra"
+)
+
+var (
+	eventsSince     string
+	eventsUntil     string
+	eventsTypes     []string
+	eventsSession   string
+	eventsLimit     int
+	eventsNoRefresh bool
+	eventsOutput    string
+)
+
+// eventMessageWidth is the widest message shown in the events table
+const eventMessageWidth = 80
+
+var eventsCmd = &cobra.Command{
+	Use:   "events [flags]",
+	Short: "Show the log of session, limit, budget and error events",
+	Long: `Show the event log: sessions starting and ending, usage spikes, usage limits hit,
+budgets breached and refreshes failing, oldest first.
+
+Session and limit events are derived from the conversation logs, so they are
+recorded whenever this command runs as well as while monitoring. Usage spikes,
+budget breaches and errors are only recorded while monitoring. The log is an
+append-only JSONL file in the cache directory.
+
+Event types: session_start, session_end, anomaly, limit_hit, budget_breach, error
+
+Examples:
+  claudecat events --since 7d                 # Last week
+  claudecat events --type limit_hit --since 30d
+  claudecat events --since 2025-06-10 --until 2025-06-11
+  claudecat events --session <id>             # One session, as listed by claudecat sessions
+  claudecat events --output json              # JSON output`,
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadSessionCommandConfig(cmd)
+		if err != nil {
+			return err
+		}
+
+		validOutputs := []string{"table", "json"}
+		if !containsFold(validOutputs, eventsOutput) {
+			return fmt.Errorf("invalid output format: %s (valid options: %s)",
+				eventsOutput, strings.Join(validOutputs, ", "))
+		}
+		eventsOutput = strings.ToLower(eventsOutput)
+
+		query := events.Query{SessionID: eventsSession, Limit: eventsLimit}
+		for _, value := range eventsTypes {
+			eventType, err := parseEventType(value)
+			if err != nil {
+				return err
+			}
+			query.Types = append(query.Types, eventType)
+		}
+		now := time.Now()
+		if eventsSince != "" {
+			if query.Since, err = parseFilterTime(eventsSince, now); err != nil {
+				return fmt.Errorf("invalid since: %w", err)
+			}
+		}
+		if eventsUntil != "" {
+			if query.Until, err = parseFilterTime(eventsUntil, now); err != nil {
+				return fmt.Errorf("invalid until: %w", err)
+			}
+		}
+
+		log, err := events.Open(claudecat.CacheDir(cfg))
+		if err != nil {
+			return err
+		}
+		if !eventsNoRefresh {
+			refreshEventLog(cfg, log)
+		}
+
+		result, err := log.Query(query)
+		if err != nil {
+			return err
+		}
+
+		if eventsOutput == "json" {
+			if result == nil {
+				result = []events.Event{}
+			}
+			return writeJSON(result)
+		}
+		outputEventsTable(result, resolveLocation(cfg))
+		return nil
+	},
+}
+
+func init() {
+	eventsCmd.Flags().StringVar(&eventsSince, "since", "", "start date or age (YYYY-MM-DD, YYYY-MM-DD HH:MM:SS, or e.g. 7d, 2w, 12h)")
+	eventsCmd.Flags().StringVar(&eventsUntil, "until", "", "end date or age, exclusive (same formats as --since)")
+	eventsCmd.Flags().StringSliceVar(&eventsTypes, "type", nil, "only show these event types (can be specified multiple times)")
+	eventsCmd.Flags().StringVar(&eventsSession, "session", "", "only show the events of this session")
+	eventsCmd.Flags().IntVarP(&eventsLimit, "limit", "n", 0, "only show the latest n events")
+	eventsCmd.Flags().BoolVar(&eventsNoRefresh, "no-refresh", false, "don't record new events from the conversation logs first")
+	eventsCmd.Flags().StringVarP(&eventsOutput, "output", "o", "table", "output format (table, json)")
+	eventsCmd.Flags().StringSliceVarP(&runPaths, "paths", "p", nil, "data paths to scan (can be specified multiple times)")
+
+	rootCmd.AddCommand(eventsCmd)
+}
+
+// parseEventType parses an event type name
+func parseEventType(value string) (events.Type, error) {
+	for _, eventType := range events.Types {
+		if strings.EqualFold(value, string(eventType)) {
+			return eventType, nil
+		}
+	}
+	names := make([]string, len(events.Types))
+	for i, eventType := range events.Types {
+		names[i] = string(eventType)
+	}
+	return "", fmt.Errorf("invalid event type: %s (valid options: %s)", value, strings.Join(names, ", "))
+}
+
+// refreshEventLog records the session and limit events of all available usage data that
+// aren't in the event log yet
+func refreshEventLog(cfg *config.Config, log *events.Log) {
+	// Raw limit records are needed for limit detection
+	entries, limitRecords := loadAllUsageEntries(cfg, true, false)
+	if len(entries) == 0 {
+		return
+	}
+
+	analyzer := newSessionAnalyzer(cfg)
+	blocks := analyzer.TransformToBlocks(entries)
+	sessions.AttachLimits(blocks, analyzer.DetectLimits(limitRecords))
+	if _, err := log.Record(events.FromBlocks(blocks)); err != nil {
+		logging.LogWarnf("Failed to update event log: %v", err)
+	}
+}
+
+func outputEventsTable(result []events.Event, loc *time.Location) {
+	if len(result) == 0 {
+		fmt.Println("No events recorded.")
+		return
+	}
+
+	table := newTableFormatter([]string{"Time", "Event", "Session", "Message"})
+	for _, event := range result {
+		sessionID := event.SessionID
+		if sessionID == "" {
+			sessionID = "-"
+		}
+		message, _, _ := strings.Cut(event.Message, "\n")
+		table.addRow([]string{
+			event.Time.In(loc).Format("2006-01-02 15:04:05"),
+			string(event.Type),
+			sessionID,
+			truncateLabel(message, eventMessageWidth),
+		})
+	}
+	fmt.Println(table.render())
+}
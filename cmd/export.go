@@ -0,0 +1,228 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/penwyp/claudecat/config"
+	"github.com/penwyp/claudecat/fileio"
+	"github.com/penwyp/claudecat/models"
+	"github.com/penwyp/claudecat/output"
+	"github.com/penwyp/claudecat/sessions"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportFormat      string
+	exportType        string
+	exportFile        string
+	exportFrom        string
+	exportTo          string
+	exportIncludeGaps bool
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export [flags] [path...]",
+	Short: "Export usage entries or session blocks for spreadsheets and BI tools",
+	Long: `Export raw usage entries (one row per request) or aggregated 5-hour session
+blocks (one row per block) with a header row. Blocks are split further as
+session.split_blocks selects, like the blocks report.
+
+Parquet exports are zstd-compressed columnar files for loading into DuckDB,
+BigQuery, Snowflake and other warehouses. Columns match the CSV header; timestamps
+are UTC with millisecond precision and block models are a list of strings. The
+schema version is stored in the file metadata under claudecat.schema_version.
+
+SQLite exports write entries, blocks and limit events into one database whatever
+the --type, with model and project names in lookup tables, indices on timestamp,
+model and project, and a usage view joining names back for ad-hoc SQL.
+
+Examples:
+  claudecat export --format csv > usage.csv                 # Every usage entry
+  claudecat export --type blocks --file blocks.csv          # Session blocks
+  claudecat export --from 2025-06-01 --to 2025-07-01 --file june.csv
+  claudecat export --type blocks --format json              # Blocks as JSON
+  claudecat export --format parquet --file usage.parquet    # Every usage entry for a warehouse
+  claudecat export --format sqlite --file usage.sqlite      # Entries, blocks and limits for SQL
+  claudecat export --model opus --project myrepo --since 7d # Opus requests on one repo last week`,
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadSessionCommandConfig(cmd)
+		if err != nil {
+			return err
+		}
+		if len(args) > 0 {
+			cfg.Data.Paths = args
+		}
+
+		validFormats := []string{"csv", "json", "parquet", "sqlite"}
+		if !containsFold(validFormats, exportFormat) {
+			return fmt.Errorf("invalid export format: %s (valid options: %s)",
+				exportFormat, strings.Join(validFormats, ", "))
+		}
+		exportFormat = strings.ToLower(exportFormat)
+		if exportFormat == "parquet" && (exportFile == "" || exportFile == "-") && isTerminal(os.Stdout) {
+			return fmt.Errorf("parquet is a binary format: use --file or redirect the output")
+		}
+		if exportFormat == "sqlite" && (exportFile == "" || exportFile == "-") {
+			return fmt.Errorf("sqlite exports need a database path: use --file")
+		}
+
+		validTypes := []string{"entries", "blocks"}
+		if !containsFold(validTypes, exportType) {
+			return fmt.Errorf("invalid export type: %s (valid options: %s)",
+				exportType, strings.Join(validTypes, ", "))
+		}
+		exportType = strings.ToLower(exportType)
+
+		filter, err := entryFilterFromFlags(exportFrom, exportTo)
+		if err != nil {
+			return err
+		}
+
+		// Bypass the summary cache: exports need every entry with its exact timestamp and IDs
+		includeLimits := exportFormat == "sqlite" || (exportType == "blocks" && splitsAtLimitResets(cfg))
+		entries, limitRecords := loadAllUsageEntries(cfg, includeLimits, false)
+		if exportFormat == "sqlite" {
+			return exportSQLite(cfg, entries, limitRecords, filter)
+		}
+
+		w := io.Writer(os.Stdout)
+		if exportFile != "" && exportFile != "-" {
+			if dir := filepath.Dir(exportFile); dir != "." {
+				if err := os.MkdirAll(dir, 0755); err != nil {
+					return fmt.Errorf("failed to create directory: %w", err)
+				}
+			}
+			file, err := os.Create(exportFile)
+			if err != nil {
+				return fmt.Errorf("failed to create export file: %w", err)
+			}
+			defer file.Close()
+			w = file
+		}
+
+		var rows int
+		if exportType == "blocks" {
+			blocks := filterBlocks(newBlockAnalyzer(cfg, limitRecords), entries, filter, exportIncludeGaps)
+			rows = len(blocks)
+			err = writeExportBlocks(w, blocks)
+		} else {
+			entries = filter.Apply(entries)
+			rows = len(entries)
+			err = writeExportEntries(w, entries)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to export %s: %w", exportType, err)
+		}
+
+		if exportFile != "" && exportFile != "-" {
+			fmt.Fprintf(os.Stderr, "Exported %d %s to %s\n", rows, exportType, exportFile)
+		}
+		return nil
+	},
+}
+
+func init() {
+	exportCmd.Flags().StringVar(&exportFormat, "format", "csv", "export format (csv, json, parquet, sqlite)")
+	exportCmd.Flags().StringVar(&exportType, "type", "entries", "what to export (entries, blocks); sqlite exports both")
+	exportCmd.Flags().StringVarP(&exportFile, "file", "f", "", "write to this file instead of stdout")
+	exportCmd.Flags().StringVar(&exportFrom, "from", "", "start date (YYYY-MM-DD or YYYY-MM-DD HH:MM:SS)")
+	exportCmd.Flags().StringVar(&exportTo, "to", "", "end date (YYYY-MM-DD or YYYY-MM-DD HH:MM:SS)")
+	exportCmd.Flags().BoolVar(&exportIncludeGaps, "include-gaps", false, "include idle gap blocks when exporting blocks")
+	addEntryFilterFlags(exportCmd)
+
+	rootCmd.AddCommand(exportCmd)
+}
+
+// exportSQLite writes the filtered entries, the blocks built from them and the limits hit
+// within the filter's time range to a SQLite database at exportFile
+func exportSQLite(cfg *config.Config, entries []models.UsageEntry, limitRecords []map[string]interface{}, filter fileio.EntryFilter) error {
+	if dir := filepath.Dir(exportFile); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create directory: %w", err)
+		}
+	}
+
+	analyzer := newBlockAnalyzer(cfg, limitRecords)
+	blocks := filterBlocks(analyzer, entries, filter, exportIncludeGaps)
+	entries = filter.Apply(entries)
+	limits := filterLimitMessages(analyzer.DetectLimits(limitRecords), filter.Since, filter.Until)
+	if err := output.WriteSQLiteExport(exportFile, entries, blocks, limits); err != nil {
+		return fmt.Errorf("failed to export to SQLite: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "Exported %d entries, %d blocks and %d limits to %s\n",
+		len(entries), len(blocks), len(limits), exportFile)
+	return nil
+}
+
+// filterExportEntries keeps entries within the optional [from, to) range
+func filterExportEntries(entries []models.UsageEntry, from, to time.Time) []models.UsageEntry {
+	return fileio.EntryFilter{Since: from, Until: to}.Apply(entries)
+}
+
+// filterBlocks builds session blocks from the entries of the filter's projects and models,
+// keeping blocks starting within its time range
+func filterBlocks(analyzer *sessions.SessionAnalyzer, entries []models.UsageEntry, filter fileio.EntryFilter, includeGaps bool) []models.SessionBlock {
+	blocks := analyzer.TransformToBlocks(filter.WithoutTimeRange().Apply(entries))
+	return filterExportBlocks(blocks, filter.Since, filter.Until, includeGaps)
+}
+
+// filterExportBlocks keeps blocks starting within the optional [from, to) range, dropping gaps unless requested
+func filterExportBlocks(blocks []models.SessionBlock, from, to time.Time, includeGaps bool) []models.SessionBlock {
+	filtered := make([]models.SessionBlock, 0, len(blocks))
+	for _, block := range blocks {
+		if block.IsGap && !includeGaps {
+			continue
+		}
+		if !from.IsZero() && block.StartTime.Before(from) {
+			continue
+		}
+		if !to.IsZero() && !block.StartTime.Before(to) {
+			continue
+		}
+		filtered = append(filtered, block)
+	}
+	return filtered
+}
+
+func writeExportEntries(w io.Writer, entries []models.UsageEntry) error {
+	switch exportFormat {
+	case "json":
+		return writeExportJSON(w, entries)
+	case "parquet":
+		return output.WriteUsageEntriesParquet(w, entries)
+	}
+	return output.WriteUsageEntriesCSV(w, entries)
+}
+
+func writeExportBlocks(w io.Writer, blocks []models.SessionBlock) error {
+	switch exportFormat {
+	case "json":
+		return writeExportJSON(w, blocks)
+	case "parquet":
+		return output.WriteSessionBlocksParquet(w, blocks)
+	}
+	return output.WriteSessionBlocksCSV(w, blocks)
+}
+
+func writeExportJSON(w io.Writer, v interface{}) error {
+	data, err := sonic.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// isTerminal reports whether f is an interactive terminal rather than a file or pipe
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
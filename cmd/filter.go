@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/penwyp/claudecat/fileio"
+	"github.com/spf13/cobra"
+)
+
+// Entry filter flags shared by the report and export commands
+var (
+	filterProjects []string
+	filterModels   []string
+	filterSources  []string
+	filterTags     []string
+	filterSince    string
+	filterUntil    string
+)
+
+// addEntryFilterFlags registers the entry filter flags on cmd
+func addEntryFilterFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(&filterProjects, "project", nil, "only include these projects (substring match, can be specified multiple times)")
+	cmd.Flags().StringSliceVar(&filterModels, "model", nil, "only include these models or families, e.g. opus (can be specified multiple times)")
+	cmd.Flags().StringSliceVar(&filterSources, "source", nil, "only include these sources: claude, bedrock, vertex, codex, gemini (can be specified multiple times)")
+	cmd.Flags().StringSliceVar(&filterTags, "tag", nil, "only include these cost allocation tags, or untagged (can be specified multiple times)")
+	cmd.Flags().StringVar(&filterSince, "since", "", "start date or age (YYYY-MM-DD, YYYY-MM-DD HH:MM:SS, or e.g. 7d, 2w, 12h)")
+	cmd.Flags().StringVar(&filterUntil, "until", "", "end date or age, exclusive (same formats as --since)")
+}
+
+// entryFilterFromFlags builds the entry filter from the filter flags and a command's
+// --from and --to values, which are alternatives to --since and --until
+func entryFilterFromFlags(fromStr, toStr string) (fileio.EntryFilter, error) {
+	filter := fileio.EntryFilter{Projects: filterProjects, Models: filterModels, Sources: filterSources, Tags: filterTags}
+
+	if fromStr != "" && filterSince != "" {
+		return filter, fmt.Errorf("--from and --since cannot be combined")
+	}
+	if toStr != "" && filterUntil != "" {
+		return filter, fmt.Errorf("--to and --until cannot be combined")
+	}
+
+	from, to, err := parseTimeRange(fromStr, toStr)
+	if err != nil {
+		return filter, err
+	}
+	filter.Since, filter.Until = from, to
+
+	now := time.Now()
+	if filterSince != "" {
+		if filter.Since, err = parseFilterTime(filterSince, now); err != nil {
+			return filter, fmt.Errorf("invalid since: %w", err)
+		}
+	}
+	if filterUntil != "" {
+		if filter.Until, err = parseFilterTime(filterUntil, now); err != nil {
+			return filter, fmt.Errorf("invalid until: %w", err)
+		}
+	}
+
+	if !filter.Since.IsZero() && !filter.Until.IsZero() && !filter.Since.Before(filter.Until) {
+		return filter, fmt.Errorf("start %s is not before end %s",
+			filter.Since.Format(time.RFC3339), filter.Until.Format(time.RFC3339))
+	}
+	return filter, nil
+}
+
+// parseFilterTime parses a date, or an age such as 7d, 2w or 12h counted back from now
+func parseFilterTime(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if age, err := parseAge(value); err == nil {
+		return now.Add(-age), nil
+	}
+	return parseTimeString(value)
+}
+
+// parseAge parses a duration, additionally accepting days (d) and weeks (w)
+func parseAge(value string) (time.Duration, error) {
+	units := map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour}
+	for suffix, unit := range units {
+		if n, ok := strings.CutSuffix(value, suffix); ok {
+			count, err := strconv.Atoi(n)
+			if err != nil || count < 0 {
+				return 0, fmt.Errorf("invalid age: %s", value)
+			}
+			return time.Duration(count) * unit, nil
+		}
+	}
+
+	age, err := time.ParseDuration(value)
+	if err != nil || age < 0 {
+		return 0, fmt.Errorf("invalid age: %s", value)
+	}
+	return age, nil
+}
@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/penwyp/claudecat/config"
+	"github.com/penwyp/claudecat/history"
+	"github.com/penwyp/claudecat/logging"
+	"github.com/penwyp/claudecat/pkg/claudecat"
+	"github.com/penwyp/claudecat/sessions"
+	"github.com/spf13/cobra"
+)
+
+var (
+	historyWeeks     int
+	historyDaily     bool
+	historyNoRefresh bool
+	historyOutput    string
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history [flags]",
+	Short: "Show week-over-week usage trends",
+	Long: `Show weekly token usage, cost, sessions and peak burn rate with the change from
+the previous week, read from the local trend database.
+
+The trend database keeps daily summaries and periodic snapshots of the active
+session, recorded while monitoring and whenever this command runs, so trends
+remain available after the underlying conversation logs have been rotated away.
+
+Examples:
+  claudecat history                # Last 8 weeks
+  claudecat history --weeks 26     # Last 26 weeks
+  claudecat history --daily        # Include a row per day
+  claudecat history --no-refresh   # Only use what is already recorded
+  claudecat history --output json  # JSON output`,
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadSessionCommandConfig(cmd)
+		if err != nil {
+			return err
+		}
+
+		validOutputs := []string{"table", "json"}
+		if !containsFold(validOutputs, historyOutput) {
+			return fmt.Errorf("invalid output format: %s (valid options: %s)",
+				historyOutput, strings.Join(validOutputs, ", "))
+		}
+		historyOutput = strings.ToLower(historyOutput)
+		if historyWeeks <= 0 {
+			return fmt.Errorf("invalid number of weeks: %d", historyWeeks)
+		}
+
+		store, err := history.Open(claudecat.CacheDir(cfg), cfg.History.Retention, resolveLocation(cfg))
+		if err != nil {
+			return fmt.Errorf("failed to open trend history: %w", err)
+		}
+
+		if !historyNoRefresh {
+			refreshTrendHistory(cfg, store)
+		}
+
+		// Start one extra week back so the first week shown has a change
+		now := time.Now().In(store.Location())
+		from := history.WeekStart(now).AddDate(0, 0, -7*historyWeeks)
+		days, err := store.Daily(from, now)
+		if err != nil {
+			return fmt.Errorf("failed to read trend history: %w", err)
+		}
+
+		weeks, err := history.WeeklyTrends(days, store.Location())
+		if err != nil {
+			return err
+		}
+		if len(weeks) > historyWeeks {
+			weeks = weeks[len(weeks)-historyWeeks:]
+		}
+
+		if historyOutput == "json" {
+			return outputHistoryJSON(weeks, days, historyDaily)
+		}
+		outputHistoryTable(weeks, days, historyDaily)
+		return nil
+	},
+}
+
+func init() {
+	historyCmd.Flags().IntVar(&historyWeeks, "weeks", 8, "number of weeks to show")
+	historyCmd.Flags().BoolVar(&historyDaily, "daily", false, "also show a row per day")
+	historyCmd.Flags().BoolVar(&historyNoRefresh, "no-refresh", false, "don't update the trend database from the conversation logs first")
+	historyCmd.Flags().StringVarP(&historyOutput, "output", "o", "table", "output format (table, json)")
+	historyCmd.Flags().StringSliceVarP(&runPaths, "paths", "p", nil, "data paths to scan (can be specified multiple times)")
+
+	rootCmd.AddCommand(historyCmd)
+}
+
+// refreshTrendHistory merges daily summaries of all available usage data into the trend database
+func refreshTrendHistory(cfg *config.Config, store *history.Store) {
+	entries, _ := loadAllUsageEntries(cfg, false, true)
+	if len(entries) == 0 {
+		return
+	}
+
+	blocks := newSessionAnalyzer(cfg).TransformToBlocks(entries)
+	if err := store.MergeDaily(store.SummarizeDays(blocks, time.Now())); err != nil {
+		logging.LogWarnf("Failed to update trend history: %v", err)
+	}
+}
+
+func outputHistoryJSON(weeks []history.WeeklyTrend, days []history.DailySummary, daily bool) error {
+	if weeks == nil {
+		weeks = []history.WeeklyTrend{}
+	}
+	result := map[string]interface{}{"weekly": weeks}
+	if daily {
+		if days == nil {
+			days = []history.DailySummary{}
+		}
+		result["daily"] = days
+	}
+	return writeJSON(result)
+}
+
+func outputHistoryTable(weeks []history.WeeklyTrend, days []history.DailySummary, daily bool) {
+	if len(weeks) == 0 {
+		fmt.Println("No usage history recorded.")
+		return
+	}
+
+	table := newTableFormatter([]string{"Week", "Starting", "Active Days", "Sessions", "Tokens", "Change", "Cost (USD)", "Change", "Peak Burn Rate", "Top Model"})
+	for _, week := range weeks {
+		table.addRow([]string{
+			week.Week,
+			week.WeekStart.Format("2006-01-02"),
+			strconv.Itoa(week.ActiveDays),
+			strconv.Itoa(week.Sessions),
+			formatWithCommas(week.TotalTokens),
+			formatChange(week.TokenChange),
+			formatCost(week.CostUSD),
+			formatChange(week.CostChange),
+			formatBurnRate(week.PeakTokensPerMinute),
+			week.TopModel,
+		})
+	}
+	fmt.Println(table.render())
+
+	if !daily || len(days) == 0 {
+		return
+	}
+
+	fmt.Println()
+	fmt.Println("Daily usage:")
+	dailyTable := newTableFormatter([]string{"Date", "Sessions", "Entries", "Tokens", "Cost (USD)", "Peak Burn Rate"})
+	for _, day := range days {
+		dailyTable.addRow([]string{
+			day.Date,
+			strconv.Itoa(day.Sessions),
+			formatWithCommas(day.EntryCount),
+			formatWithCommas(day.TotalTokens),
+			formatCost(day.CostUSD),
+			formatBurnRate(day.PeakTokensPerMinute),
+		})
+	}
+	fmt.Println(dailyTable.render())
+}
+
+// formatChange renders a percentage change with its sign, or "-" when there is nothing to compare
+func formatChange(change *float64) string {
+	if change == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%+.1f%%", *change)
+}
+
+// formatBurnRate renders a tokens-per-minute rate, or "-" when none was recorded
+func formatBurnRate(tokensPerMinute float64) string {
+	if tokensPerMinute <= 0 {
+		return "-"
+	}
+	return formatWithCommas(int(tokensPerMinute+0.5)) + "/min"
+}
+
+// refreshSessionHistory loads all usage data, rebuilds session blocks with their limit
+// events and merges them into the persisted session history
+func refreshSessionHistory(cfg *config.Config) (*sessions.HistoryStore, error) {
+	store, err := sessions.NewHistoryStore(claudecat.CacheDir(cfg))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open session history: %w", err)
+	}
+
+	// Raw limit records are needed for limit detection
+	entries, limitRecords := loadAllUsageEntries(cfg, true, false)
+	if len(entries) == 0 {
+		return store, nil
+	}
+
+	analyzer := newSessionAnalyzer(cfg)
+	blocks := analyzer.TransformToBlocks(entries)
+	limits := analyzer.DetectLimits(limitRecords)
+
+	store.Record(blocks, limits)
+	if err := store.Save(); err != nil {
+		logging.LogWarnf("Failed to save session history: %v", err)
+	}
+
+	return store, nil
+}
@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/penwyp/claudecat/models"
+	"github.com/penwyp/claudecat/sessions"
+	"github.com/penwyp/claudecat/timeutil"
+	"github.com/spf13/cobra"
+)
+
+var (
+	limitsFrom   string
+	limitsTo     string
+	limitsOutput string
+)
+
+var limitsCmd = &cobra.Command{
+	Use:   "limits [flags]",
+	Short: "Show history of detected usage limit hits",
+	Long: `List every detected limit event with its type, timestamp, session, tokens used
+at the time of the hit and the resulting downtime, followed by a weekly summary.
+
+Limit events are kept in the persisted session history, so hits remain visible
+even after the underlying conversation logs have been rotated away.
+
+Examples:
+  claudecat limits                                  # All recorded limit hits
+  claudecat limits --from 2025-01-01 --to 2025-02-01 # Hits within a date range
+  claudecat limits --output json                    # JSON output`,
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadSessionCommandConfig(cmd)
+		if err != nil {
+			return err
+		}
+
+		validOutputs := []string{"table", "json"}
+		if !containsFold(validOutputs, limitsOutput) {
+			return fmt.Errorf("invalid output format: %s (valid options: %s)",
+				limitsOutput, strings.Join(validOutputs, ", "))
+		}
+		limitsOutput = strings.ToLower(limitsOutput)
+
+		from, to, err := parseTimeRange(limitsFrom, limitsTo)
+		if err != nil {
+			return err
+		}
+
+		store, err := refreshSessionHistory(cfg)
+		if err != nil {
+			return err
+		}
+
+		events := store.LimitEvents(from, to)
+		loc := resolveLocation(cfg)
+		summary := summarizeLimitsByWeek(events, loc)
+
+		if limitsOutput == "json" {
+			return outputLimitsJSON(events, summary)
+		}
+		outputLimitsTable(events, summary, loc)
+		return nil
+	},
+}
+
+func init() {
+	limitsCmd.Flags().StringVar(&limitsFrom, "from", "", "start date (YYYY-MM-DD or YYYY-MM-DD HH:MM:SS)")
+	limitsCmd.Flags().StringVar(&limitsTo, "to", "", "end date (YYYY-MM-DD or YYYY-MM-DD HH:MM:SS)")
+	limitsCmd.Flags().StringVarP(&limitsOutput, "output", "o", "table", "output format (table, json)")
+	limitsCmd.Flags().StringSliceVarP(&runPaths, "paths", "p", nil, "data paths to scan (can be specified multiple times)")
+
+	rootCmd.AddCommand(limitsCmd)
+}
+
+// weeklyLimitSummary counts limit hits within a single ISO week
+type weeklyLimitSummary struct {
+	Week          string         `json:"week"`
+	WeekStart     time.Time      `json:"week_start"`
+	Hits          int            `json:"hits"`
+	TotalDowntime time.Duration  `json:"total_downtime"`
+	ByType        map[string]int `json:"by_type"`
+}
+
+// summarizeLimitsByWeek groups limit events by ISO week in loc
+func summarizeLimitsByWeek(events []sessions.LimitEvent, loc *time.Location) []weeklyLimitSummary {
+	byWeek := make(map[string]*weeklyLimitSummary)
+	for _, event := range events {
+		week, _ := models.PeriodFor(event.Timestamp, models.PeriodWeek, loc)
+
+		summary, exists := byWeek[week.Key]
+		if !exists {
+			summary = &weeklyLimitSummary{
+				Week:      week.Key,
+				WeekStart: week.Start,
+				ByType:    make(map[string]int),
+			}
+			byWeek[week.Key] = summary
+		}
+
+		summary.Hits++
+		summary.TotalDowntime += event.Downtime
+		summary.ByType[event.Type]++
+	}
+
+	result := make([]weeklyLimitSummary, 0, len(byWeek))
+	for _, summary := range byWeek {
+		result = append(result, *summary)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].WeekStart.Before(result[j].WeekStart)
+	})
+
+	return result
+}
+
+func outputLimitsJSON(events []sessions.LimitEvent, summary []weeklyLimitSummary) error {
+	if events == nil {
+		events = []sessions.LimitEvent{}
+	}
+
+	return writeJSON(map[string]interface{}{
+		"events": events,
+		"weekly": summary,
+	})
+}
+
+func outputLimitsTable(events []sessions.LimitEvent, summary []weeklyLimitSummary, loc *time.Location) {
+	if len(events) == 0 {
+		fmt.Println("No limit events recorded.")
+		return
+	}
+
+	table := newTableFormatter([]string{"Time", "Type", "Session", "Tokens at Hit", "Downtime"})
+	for _, event := range events {
+		table.addRow([]string{
+			event.Timestamp.In(loc).Format("2006-01-02 15:04"),
+			event.Type,
+			event.SessionID,
+			formatWithCommas(event.TokensAtHit),
+			formatDowntime(event.Downtime),
+		})
+	}
+	fmt.Println(table.render())
+
+	fmt.Println()
+	fmt.Println("Hits per week:")
+	weekly := newTableFormatter([]string{"Week", "Starting", "Hits", "Total Downtime"})
+	for _, week := range summary {
+		weekly.addRow([]string{
+			week.Week,
+			week.WeekStart.Format(timeutil.DateLayout),
+			strconv.Itoa(week.Hits),
+			formatDowntime(week.TotalDowntime),
+		})
+	}
+	fmt.Println(weekly.render())
+}
+
+// formatDowntime renders a duration as hours and minutes
+func formatDowntime(d time.Duration) string {
+	d = d.Round(time.Minute)
+	hours := int(d.Hours())
+	minutes := int(d.Minutes()) % 60
+	if hours > 0 {
+		return fmt.Sprintf("%dh %02dm", hours, minutes)
+	}
+	return fmt.Sprintf("%dm", minutes)
+}
+
+// parseTimeRange parses optional --from/--to values
+func parseTimeRange(fromStr, toStr string) (time.Time, time.Time, error) {
+	var from, to time.Time
+	var err error
+
+	if fromStr != "" {
+		if from, err = parseTimeString(fromStr); err != nil {
+			return from, to, fmt.Errorf("invalid from date: %w", err)
+		}
+	}
+	if toStr != "" {
+		if to, err = parseTimeString(toStr); err != nil {
+			return from, to, fmt.Errorf("invalid to date: %w", err)
+		}
+	}
+
+	return from, to, nil
+}
+
+// containsFold reports whether value matches any option, ignoring case
+func containsFold(options []string, value string) bool {
+	for _, option := range options {
+		if strings.EqualFold(option, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/penwyp/claudecat/history"
+	"github.com/penwyp/claudecat/mcp"
+	"github.com/penwyp/claudecat/orchestrator"
+	"github.com/penwyp/claudecat/pkg/claudecat"
+	"github.com/spf13/cobra"
+)
+
+var mcpCmd = &cobra.Command{
+	Use:   "mcp [flags] [path...]",
+	Short: "Run a Model Context Protocol server on stdio",
+	Long: `Monitor usage in the background and answer Model Context Protocol requests on
+stdin/stdout, so Claude Code and other MCP clients can ask about your usage from
+inside a conversation.
+
+Tools:
+  get_current_session_usage   Tokens, cost, burn rate and projections of the current session
+  get_daily_report            Usage of one day (argument "date", YYYY-MM-DD, default today)
+
+Logs are written to stderr or the configured log file, never to stdout.
+
+Examples:
+  claude mcp add claudecat -- claudecat mcp     # Register with Claude Code
+  claudecat mcp ~/.claude/projects              # Monitor a specific data path`,
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadSessionCommandConfig(cmd)
+		if err != nil {
+			return err
+		}
+		if len(args) > 0 {
+			cfg.Data.Paths = args
+		}
+		defer initTelemetry(cfg)()
+
+		store, err := history.Open(claudecat.CacheDir(cfg), cfg.History.Retention, resolveLocation(cfg))
+		if err != nil {
+			return fmt.Errorf("failed to open trend history: %w", err)
+		}
+
+		updateInterval := cfg.UI.RefreshRate
+		if updateInterval <= 0 {
+			updateInterval = 10 * time.Second
+		}
+
+		srv := mcp.NewServer(Version, cfg, store)
+		monitor := orchestrator.NewMonitoringOrchestrator(updateInterval, claudecat.DataPaths(cfg), cfg)
+		monitor.RegisterUpdateCallback(srv.Update)
+		if err := monitor.Start(); err != nil {
+			return fmt.Errorf("failed to start monitoring: %w", err)
+		}
+		defer monitor.Stop()
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		// The client ends the session by closing stdin
+		serveErr := make(chan error, 1)
+		go func() {
+			serveErr <- srv.Serve(os.Stdin, os.Stdout)
+		}()
+
+		select {
+		case err := <-serveErr:
+			return err
+		case <-ctx.Done():
+			return nil
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(mcpCmd)
+}
@@ -0,0 +1,228 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/penwyp/claudecat/models"
+	"github.com/spf13/cobra"
+)
+
+var (
+	projectsOutput string
+	projectsSortBy string
+	projectsLimit  int
+)
+
+var projectsCmd = &cobra.Command{
+	Use:   "projects [flags] [path...]",
+	Short: "List projects with usage statistics",
+	Long: `List every discovered project with its entry count, first and last activity,
+total tokens and cost, and its share of the current session.
+
+Examples:
+  claudecat projects                         # All projects sorted by cost
+  claudecat projects --sort-by last          # Most recently active first
+  claudecat projects --output json --limit 5 # Top 5 projects as JSON`,
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadSessionCommandConfig(cmd)
+		if err != nil {
+			return err
+		}
+
+		if len(args) > 0 {
+			cfg.Data.Paths = args
+		}
+
+		validOutputs := []string{"table", "json"}
+		if !containsFold(validOutputs, projectsOutput) {
+			return fmt.Errorf("invalid output format: %s (valid options: %s)",
+				projectsOutput, strings.Join(validOutputs, ", "))
+		}
+		projectsOutput = strings.ToLower(projectsOutput)
+
+		validSorts := []string{"cost", "tokens", "entries", "first", "last", "name", "session"}
+		if !containsFold(validSorts, projectsSortBy) {
+			return fmt.Errorf("invalid sort field: %s (valid options: %s)",
+				projectsSortBy, strings.Join(validSorts, ", "))
+		}
+		projectsSortBy = strings.ToLower(projectsSortBy)
+
+		entries, _ := loadAllUsageEntries(cfg, false, true)
+
+		// Locate the active session so each project's share of it can be reported
+		var activeBlock *models.SessionBlock
+		blocks := newSessionAnalyzer(cfg).TransformToBlocks(entries)
+		for i := range blocks {
+			if blocks[i].IsActive {
+				activeBlock = &blocks[i]
+			}
+		}
+
+		stats := buildProjectStats(entries, activeBlock)
+		sortProjectStats(stats, projectsSortBy)
+		if projectsLimit > 0 && len(stats) > projectsLimit {
+			stats = stats[:projectsLimit]
+		}
+
+		if projectsOutput == "json" {
+			return writeJSON(stats)
+		}
+		outputProjectsTable(stats)
+		return nil
+	},
+}
+
+func init() {
+	projectsCmd.Flags().StringVarP(&projectsOutput, "output", "o", "table", "output format (table, json)")
+	projectsCmd.Flags().StringVar(&projectsSortBy, "sort-by", "cost", "sort by field (cost, tokens, entries, first, last, name, session)")
+	projectsCmd.Flags().IntVar(&projectsLimit, "limit", 0, "limit number of projects (0 = no limit)")
+
+	rootCmd.AddCommand(projectsCmd)
+}
+
+// projectStats contains aggregated usage for a single project
+type projectStats struct {
+	Project       string    `json:"project"`
+	Entries       int       `json:"entries"`
+	FirstActivity time.Time `json:"first_activity"`
+	LastActivity  time.Time `json:"last_activity"`
+	TotalTokens   int       `json:"total_tokens"`
+	CostUSD       float64   `json:"cost_usd"`
+	SessionTokens int       `json:"session_tokens"`
+	SessionShare  float64   `json:"session_share"` // Percentage of the active session's tokens
+}
+
+// buildProjectStats aggregates entries per project, including each project's share of the active block
+func buildProjectStats(entries []models.UsageEntry, activeBlock *models.SessionBlock) []projectStats {
+	byProject := make(map[string]*projectStats)
+	for _, entry := range entries {
+		name := entry.Project
+		if name == "" {
+			name = "unknown"
+		}
+
+		stats, exists := byProject[name]
+		if !exists {
+			stats = &projectStats{
+				Project:       name,
+				FirstActivity: entry.Timestamp,
+				LastActivity:  entry.Timestamp,
+			}
+			byProject[name] = stats
+		}
+
+		stats.Entries++
+		stats.TotalTokens += entry.TotalTokens
+		stats.CostUSD += entry.CostUSD
+		if entry.Timestamp.Before(stats.FirstActivity) {
+			stats.FirstActivity = entry.Timestamp
+		}
+		if entry.Timestamp.After(stats.LastActivity) {
+			stats.LastActivity = entry.Timestamp
+		}
+	}
+
+	if activeBlock != nil {
+		sessionTotal := 0
+		for _, entry := range activeBlock.Entries {
+			name := entry.Project
+			if name == "" {
+				name = "unknown"
+			}
+			if stats, exists := byProject[name]; exists {
+				stats.SessionTokens += entry.TotalTokens
+			}
+			sessionTotal += entry.TotalTokens
+		}
+		if sessionTotal > 0 {
+			for _, stats := range byProject {
+				stats.SessionShare = float64(stats.SessionTokens) / float64(sessionTotal) * 100
+			}
+		}
+	}
+
+	result := make([]projectStats, 0, len(byProject))
+	for _, stats := range byProject {
+		result = append(result, *stats)
+	}
+	return result
+}
+
+// sortProjectStats sorts project statistics in place; names break ties
+func sortProjectStats(stats []projectStats, sortBy string) {
+	sort.Slice(stats, func(i, j int) bool {
+		a, b := stats[i], stats[j]
+		switch sortBy {
+		case "tokens":
+			if a.TotalTokens != b.TotalTokens {
+				return a.TotalTokens > b.TotalTokens
+			}
+		case "entries":
+			if a.Entries != b.Entries {
+				return a.Entries > b.Entries
+			}
+		case "first":
+			if !a.FirstActivity.Equal(b.FirstActivity) {
+				return a.FirstActivity.Before(b.FirstActivity)
+			}
+		case "last":
+			if !a.LastActivity.Equal(b.LastActivity) {
+				return a.LastActivity.After(b.LastActivity)
+			}
+		case "session":
+			if a.SessionTokens != b.SessionTokens {
+				return a.SessionTokens > b.SessionTokens
+			}
+		case "cost":
+			if a.CostUSD != b.CostUSD {
+				return a.CostUSD > b.CostUSD
+			}
+		}
+		return a.Project < b.Project
+	})
+}
+
+func outputProjectsTable(stats []projectStats) {
+	if len(stats) == 0 {
+		fmt.Println("No projects found.")
+		return
+	}
+
+	table := newTableFormatter([]string{"Project", "Entries", "First Activity", "Last Activity", "Total Tokens", "Cost (USD)", "Session Share"})
+	totalEntries, totalTokens, totalCost := 0, 0, 0.0
+	for _, s := range stats {
+		share := "-"
+		if s.SessionTokens > 0 {
+			share = strconv.FormatFloat(s.SessionShare, 'f', 1, 64) + "%"
+		}
+		table.addRow([]string{
+			s.Project,
+			formatWithCommas(s.Entries),
+			s.FirstActivity.Local().Format("2006-01-02 15:04"),
+			s.LastActivity.Local().Format("2006-01-02 15:04"),
+			formatWithCommas(s.TotalTokens),
+			formatCost(s.CostUSD),
+			share,
+		})
+		totalEntries += s.Entries
+		totalTokens += s.TotalTokens
+		totalCost += s.CostUSD
+	}
+
+	table.addSeparatorLine()
+	table.addRow([]string{
+		"Total",
+		formatWithCommas(totalEntries),
+		"",
+		"",
+		formatWithCommas(totalTokens),
+		formatCost(totalCost),
+		"",
+	})
+	fmt.Println(table.render())
+}
@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/penwyp/claudecat/billing"
+	"github.com/spf13/cobra"
+)
+
+var (
+	reconcileFrom      string
+	reconcileTo        string
+	reconcileAdminKey  string
+	reconcileAPIURL    string
+	reconcileTolerance float64
+	reconcileOutput    string
+)
+
+// reconcileDefaultDays is how many days are reconciled when --from is not given
+const reconcileDefaultDays = 7
+
+var reconcileCmd = &cobra.Command{
+	Use:   "reconcile [flags] [path...]",
+	Short: "Reconcile local costs against Anthropic billing",
+	Long: `Pull the organization's usage and cost from the Anthropic Admin API and compare them
+with the usage and cost computed from local logs, per UTC day and model.
+
+The Admin API needs an Admin API key (sk-ant-admin...), which organization admins can
+create in the Anthropic Console. It is read from ANTHROPIC_ADMIN_KEY unless --admin-key
+is given.
+
+Only usage billed by Anthropic directly is compared: Bedrock, Vertex AI, Codex CLI and
+Gemini CLI usage is left out. The Admin API reports the whole organization, so usage of
+other machines, users and API clients shows up as billed but not logged, while usage
+covered by a Pro or Max subscription is logged but never billed.
+
+Examples:
+  claudecat reconcile                                   # The last 7 days
+  claudecat reconcile --from 2025-06-01 --to 2025-06-30
+  claudecat reconcile --tolerance 5 --output json`,
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadSessionCommandConfig(cmd)
+		if err != nil {
+			return err
+		}
+		if len(args) > 0 {
+			cfg.Data.Paths = args
+		}
+
+		validOutputs := []string{"table", "json"}
+		if !containsFold(validOutputs, reconcileOutput) {
+			return fmt.Errorf("invalid output format: %s (valid options: %s)",
+				reconcileOutput, strings.Join(validOutputs, ", "))
+		}
+		reconcileOutput = strings.ToLower(reconcileOutput)
+
+		from, to, err := parseTimeRange(reconcileFrom, reconcileTo)
+		if err != nil {
+			return err
+		}
+		if to.IsZero() {
+			to = time.Now().UTC()
+		}
+		if from.IsZero() {
+			from = to.AddDate(0, 0, -(reconcileDefaultDays - 1))
+		}
+
+		key := reconcileAdminKey
+		if key == "" {
+			key = os.Getenv(billing.AdminKeyEnv)
+		}
+		client, err := billing.NewAdminClient(key, reconcileAPIURL)
+		if err != nil {
+			return err
+		}
+		billed, err := client.Usage(context.Background(), from, to)
+		if err != nil {
+			return err
+		}
+
+		// Billing counts every request once, and needs exact timestamps
+		cfg.Data.Deduplication = true
+		entries, _ := loadAllUsageEntries(cfg, false, false)
+		local := billing.AggregateLocal(entries, from, to)
+
+		report := reconcileReport{
+			From:   from.UTC().Format("2006-01-02"),
+			To:     to.UTC().Format("2006-01-02"),
+			Local:  local,
+			Billed: billed,
+			Days:   billing.Reconcile(local, billed, reconcileTolerance),
+		}
+		if reconcileOutput == "json" {
+			return writeJSON(report)
+		}
+		outputReconcileReport(report)
+		return nil
+	},
+}
+
+func init() {
+	reconcileCmd.Flags().StringVar(&reconcileFrom, "from", "", "start date (YYYY-MM-DD, UTC)")
+	reconcileCmd.Flags().StringVar(&reconcileTo, "to", "", "end date (YYYY-MM-DD, UTC)")
+	reconcileCmd.Flags().StringVar(&reconcileAdminKey, "admin-key", "", "Admin API key (default $"+billing.AdminKeyEnv+")")
+	reconcileCmd.Flags().StringVar(&reconcileAPIURL, "api-url", billing.DefaultAdminAPIURL, "base URL of the Anthropic API")
+	reconcileCmd.Flags().Float64Var(&reconcileTolerance, "tolerance", 2.0, "allowed difference in percent before a day is flagged")
+	reconcileCmd.Flags().StringVarP(&reconcileOutput, "output", "o", "table", "output format (table, json)")
+
+	rootCmd.AddCommand(reconcileCmd)
+}
+
+// reconcileReport is the complete reconciliation output
+type reconcileReport struct {
+	From   string                  `json:"from"`
+	To     string                  `json:"to"`
+	Local  []billing.DayModelUsage `json:"local"`
+	Billed []billing.DayModelUsage `json:"billed"`
+	Days   []billing.Discrepancy   `json:"days"`
+}
+
+func outputReconcileReport(report reconcileReport) {
+	if len(report.Days) == 0 {
+		fmt.Printf("No usage between %s and %s.\n", report.From, report.To)
+		return
+	}
+
+	mismatches := 0
+	var localCost, billedCost float64
+	table := newTableFormatter([]string{"Date", "Model", "Local Tokens", "Billed Tokens", "Diff", "Local Cost", "Billed Cost", "Status"})
+	for _, day := range report.Days {
+		status := "OK"
+		if !day.Match {
+			status = "MISMATCH"
+			mismatches++
+		}
+		localCost += day.LocalCost
+		billedCost += day.BilledCost
+		table.addRow([]string{
+			day.Date,
+			day.Model,
+			formatWithCommas(day.LocalTokens),
+			formatWithCommas(day.BilledTokens),
+			formatSignedInt(day.TokenDiff),
+			formatCost(day.LocalCost),
+			formatCost(day.BilledCost),
+			status,
+		})
+	}
+	fmt.Println(table.render())
+	fmt.Printf("\nLocal cost %s, billed cost %s (%s to %s, UTC)\n",
+		formatCost(localCost), formatCost(billedCost), report.From, report.To)
+
+	if mismatches == 0 {
+		fmt.Printf("All %d days and models match.\n", len(report.Days))
+		return
+	}
+
+	fmt.Printf("%d of %d days and models differ:\n", mismatches, len(report.Days))
+	for _, day := range report.Days {
+		if !day.Match {
+			fmt.Printf("  %s  %-28s %s\n", day.Date, day.Model, day.LikelyCause)
+		}
+	}
+}
@@ -0,0 +1,397 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/penwyp/claudecat/calculations"
+	"github.com/penwyp/claudecat/models"
+	"github.com/penwyp/claudecat/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	reportFormat   string
+	reportFrom     string
+	reportTo       string
+	reportInterval string
+)
+
+// reportTypes are the kinds of report, in the order they are listed in help texts
+var reportTypes = []string{"daily", "monthly", "session", "blocks", "trend", "source", "tag"}
+
+// trendColumns is how many models get their own column in the trend table; the rest are
+// summed under Other
+const trendColumns = 3
+
+var reportCmd = &cobra.Command{
+	Use:   "report [daily|monthly|session|blocks|trend|source|tag] [path...]",
+	Short: "Report usage per day, month, session, 5-hour block, source or cost allocation tag",
+	Long: `Report token usage and cost per calendar day (the default), per month, per
+Claude Code session or per 5-hour session block. With subscription.billing_day set,
+monthly reports follow the billing cycle instead of calendar months. The trend report shows the cost of
+each model per day or hour, to see how usage shifts between models over time. The
+source report splits usage by where it came from: Claude Code against the Anthropic
+API, Bedrock or Vertex AI, Codex CLI or Gemini CLI. The tag report splits usage by the
+cost allocation tags data.cost_allocation assigns by log path or project, for chargeback
+to teams or cost centers; entries no rule matches are reported as untagged.
+With --from or --since, logs last written before the start are skipped unread, except
+by the blocks report. With session.split_blocks set, the blocks report also splits
+blocks where usage resumes after a limit (limit_reset) or moves to another model
+family (model_switch), to attribute cost more finely than whole windows.
+
+The ccusage-json format emits the same JSON as ccusage's --json reports, so
+dashboards and scripts built around ccusage work with claudecat unchanged.
+
+Examples:
+  claudecat report                                  # Usage per day
+  claudecat report monthly --format json            # Usage per month as JSON
+  claudecat report daily --format ccusage-json      # Same output as 'ccusage daily --json'
+  claudecat report blocks --from 2025-06-01         # 5-hour blocks since June 1
+  claudecat report --model opus --project myrepo --since 7d   # Opus usage on one repo last week
+  claudecat report trend --since 30d                # Cost per model per day this month
+  claudecat report trend --interval hourly --since 24h
+  claudecat report source --since 30d               # Cost per source this month
+  claudecat report tag --since 30d                  # Cost per team or cost center this month
+  claudecat report monthly --tag platform           # One team's usage per month
+  claudecat report session ~/.claude/projects       # Sessions of a specific data path`,
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		reportType := "daily"
+		if len(args) > 0 && containsFold(reportTypes, args[0]) {
+			reportType = strings.ToLower(args[0])
+			args = args[1:]
+		}
+
+		cfg, err := loadSessionCommandConfig(cmd)
+		if err != nil {
+			return err
+		}
+		if len(args) > 0 {
+			cfg.Data.Paths = args
+		}
+
+		validFormats := []string{"table", "json", "ccusage-json"}
+		if !containsFold(validFormats, reportFormat) {
+			return fmt.Errorf("invalid report format: %s (valid options: %s)",
+				reportFormat, strings.Join(validFormats, ", "))
+		}
+		reportFormat = strings.ToLower(reportFormat)
+		if (reportType == "trend" || reportType == "source" || reportType == "tag") && reportFormat == "ccusage-json" {
+			return fmt.Errorf("the %s report has no ccusage-json format", reportType)
+		}
+		if _, err := calculations.ParseTimeSeriesInterval(reportInterval); err != nil {
+			return err
+		}
+
+		filter, err := entryFilterFromFlags(reportFrom, reportTo)
+		if err != nil {
+			return err
+		}
+
+		// Bypass the summary cache: reports group entries by their exact timestamps and sessions.
+		// Blocks are laid out from the entries before them, so the blocks report loads all.
+		var entries []models.UsageEntry
+		var blocks []models.SessionBlock
+		if reportType == "blocks" {
+			var limitRecords []map[string]interface{}
+			entries, limitRecords = loadAllUsageEntries(cfg, splitsAtLimitResets(cfg), false)
+			blocks = filterBlocks(newBlockAnalyzer(cfg, limitRecords), entries, filter, true)
+		} else {
+			entries = loadUsageEntriesSince(cfg, filter.Since)
+		}
+		location := resolveLocation(cfg)
+
+		return writeReport(reportType, reportFormat, filter.Apply(entries), blocks, location)
+	},
+}
+
+func init() {
+	reportCmd.Flags().StringVar(&reportFormat, "format", "table", "output format (table, json, ccusage-json)")
+	reportCmd.Flags().StringVar(&reportFrom, "from", "", "start date (YYYY-MM-DD or YYYY-MM-DD HH:MM:SS)")
+	reportCmd.Flags().StringVar(&reportTo, "to", "", "end date (YYYY-MM-DD or YYYY-MM-DD HH:MM:SS)")
+	reportCmd.Flags().StringVar(&reportInterval, "interval", "daily", "bucket width of the trend report (daily, hourly)")
+	addEntryFilterFlags(reportCmd)
+
+	rootCmd.AddCommand(reportCmd)
+}
+
+// usageReport is the JSON form of daily, monthly and session reports
+type usageReport struct {
+	Type   string             `json:"type"`
+	Rows   []output.ReportRow `json:"rows"`
+	Totals output.ReportRow   `json:"totals"`
+}
+
+// writeReport prints a report of entries, or of blocks for the blocks report, in format
+func writeReport(reportType, format string, entries []models.UsageEntry, blocks []models.SessionBlock, location *time.Location) error {
+	if format != "table" {
+		return writeJSON(reportValue(reportType, format, entries, blocks, location))
+	}
+	switch reportType {
+	case "blocks":
+		outputBlocksReport(blocks, location)
+	case "trend":
+		outputTrendReport(trendSeries(entries, location), location)
+	default:
+		outputUsageReport(reportType, reportRows(reportType, entries, location))
+	}
+	return nil
+}
+
+// reportValue returns the JSON value of a report in the json or ccusage-json format
+func reportValue(reportType, format string, entries []models.UsageEntry, blocks []models.SessionBlock, location *time.Location) interface{} {
+	if reportType == "trend" {
+		return trendSeries(entries, location)
+	}
+	if reportType == "blocks" {
+		if format == "ccusage-json" {
+			return output.NewCCUsageBlocksReport(blocks)
+		}
+		return blocks
+	}
+
+	rows := reportRows(reportType, entries, location)
+	if format == "ccusage-json" {
+		return ccusageReport(reportType, rows, location)
+	}
+	return usageReport{Type: reportType, Rows: rows, Totals: output.ReportTotals(rows)}
+}
+
+// reportRows groups entries into the rows of a daily, monthly, session, source or tag report
+func reportRows(reportType string, entries []models.UsageEntry, location *time.Location) []output.ReportRow {
+	rows := output.GroupUsage(entries, reportKey(reportType, location))
+	switch reportType {
+	case "session":
+		sort.SliceStable(rows, func(i, j int) bool { return rows[i].LastActivity.Before(rows[j].LastActivity) })
+	case "source", "tag":
+		sort.SliceStable(rows, func(i, j int) bool { return rows[i].CostUSD > rows[j].CostUSD })
+	}
+	return rows
+}
+
+// reportKey returns the function grouping entries for a report type
+func reportKey(reportType string, location *time.Location) func(models.UsageEntry) string {
+	switch reportType {
+	case "monthly":
+		return func(entry models.UsageEntry) string {
+			bounds, _ := models.PeriodFor(entry.Timestamp, models.PeriodMonth, location)
+			return bounds.Key
+		}
+	case "session":
+		return func(entry models.UsageEntry) string {
+			if entry.SessionID == "" {
+				return "unknown"
+			}
+			return entry.SessionID
+		}
+	case "source":
+		return func(entry models.UsageEntry) string {
+			if entry.Source == "" {
+				return calculations.UnknownSource
+			}
+			return entry.Source
+		}
+	case "tag":
+		return func(entry models.UsageEntry) string {
+			if entry.Tag == "" {
+				return models.UntaggedAllocation
+			}
+			return entry.Tag
+		}
+	default:
+		return func(entry models.UsageEntry) string { return entry.Timestamp.In(location).Format("2006-01-02") }
+	}
+}
+
+// trendSeries buckets entries per model by the --interval of the report command
+func trendSeries(entries []models.UsageEntry, location *time.Location) calculations.TimeSeries {
+	interval, err := calculations.ParseTimeSeriesInterval(reportInterval)
+	if err != nil {
+		interval = calculations.IntervalDaily
+	}
+	return calculations.NewTimeSeriesAggregator(interval, location).Aggregate(entries)
+}
+
+func ccusageReport(reportType string, rows []output.ReportRow, location *time.Location) interface{} {
+	switch reportType {
+	case "monthly":
+		return output.NewCCUsageMonthlyReport(rows)
+	case "session":
+		return output.NewCCUsageSessionReport(rows, location)
+	default:
+		return output.NewCCUsageDailyReport(rows)
+	}
+}
+
+func outputUsageReport(reportType string, rows []output.ReportRow) {
+	if len(rows) == 0 {
+		fmt.Println("No usage found.")
+		return
+	}
+
+	keyHeader := map[string]string{"daily": "Date", "monthly": "Month", "session": "Session", "source": "Source", "tag": "Tag"}[reportType]
+	if reportType == "monthly" && models.BillingDay() > 1 {
+		// Billing cycles are keyed by the month they start in
+		keyHeader = fmt.Sprintf("Cycle (from day %d)", models.BillingDay())
+	}
+	table := newTableFormatter([]string{keyHeader, "Input", "Output", "Cache Create", "Cache Read", "Total Tokens", "Cost (USD)", "Models"})
+	addRow := func(key string, row output.ReportRow, modelList string) {
+		table.addRow([]string{
+			key,
+			formatWithCommas(row.TokenCounts.InputTokens),
+			formatWithCommas(row.TokenCounts.OutputTokens + row.TokenCounts.ThinkingTokens),
+			formatWithCommas(row.TokenCounts.CacheCreationTokens),
+			formatWithCommas(row.TokenCounts.CacheReadTokens),
+			formatWithCommas(row.TotalTokens),
+			formatCost(row.CostUSD),
+			modelList,
+		})
+	}
+
+	for _, row := range rows {
+		addRow(row.Key, row, formatModels(row.ModelNames()))
+	}
+	table.addSeparatorLine()
+	totals := output.ReportTotals(rows)
+	addRow("Total", totals, "")
+	fmt.Println(table.render())
+
+	if line := cacheSavingsLine(totals.CostUSD, totals.CacheSavingsUSD); line != "" {
+		fmt.Println(line)
+	}
+}
+
+// cacheSavingsLine describes how much prompt caching saved, or is empty when it saved nothing
+func cacheSavingsLine(cost, savings float64) string {
+	switch {
+	case savings > 0 && cost+savings > 0:
+		return fmt.Sprintf("Prompt caching saved %s (%.1f%% of the cost without caching)",
+			formatCost(savings), savings/(cost+savings)*100)
+	case savings < 0:
+		return fmt.Sprintf("Prompt caching cost %s more than uncached input, as cache writes were not reused", formatCost(-savings))
+	default:
+		return ""
+	}
+}
+
+func outputTrendReport(series calculations.TimeSeries, location *time.Location) {
+	if len(series.Buckets) == 0 {
+		fmt.Println("No usage found.")
+		return
+	}
+
+	columns := series.Models
+	other := len(columns) > trendColumns
+	if other {
+		columns = columns[:trendColumns]
+	}
+
+	periodHeader, layout := "Date", "2006-01-02"
+	if series.Interval == calculations.IntervalHourly {
+		periodHeader, layout = "Hour", "2006-01-02 15:00"
+	}
+	headers := append([]string{periodHeader}, columns...)
+	if other {
+		headers = append(headers, "Other")
+	}
+	table := newTableFormatter(append(headers, "Total Tokens", "Cost (USD)"))
+
+	// Cells show each model's cost and its share of the period's cost
+	cell := func(cost, total float64, used bool) string {
+		if !used {
+			return "-"
+		}
+		if total <= 0 {
+			return formatCost(cost)
+		}
+		return fmt.Sprintf("%s (%.0f%%)", formatCost(cost), cost/total*100)
+	}
+
+	totals := make(map[string]float64)
+	totalTokens, totalCost := 0, 0.0
+	for _, bucket := range series.Buckets {
+		// Idle periods are kept in the JSON series for charting but would swamp the table
+		if bucket.Entries == 0 {
+			continue
+		}
+		costs := make(map[string]float64, len(bucket.Models))
+		for _, point := range bucket.Models {
+			costs[point.Model] = point.CostUSD
+			totals[point.Model] += point.CostUSD
+		}
+
+		row := []string{bucket.Start.In(location).Format(layout)}
+		rest := bucket.CostUSD
+		for _, model := range columns {
+			cost, used := costs[model]
+			row = append(row, cell(cost, bucket.CostUSD, used))
+			rest -= cost
+		}
+		if other {
+			row = append(row, cell(rest, bucket.CostUSD, len(costs) > countUsed(costs, columns)))
+		}
+		table.addRow(append(row, formatWithCommas(bucket.TotalTokens), formatCost(bucket.CostUSD)))
+		totalTokens += bucket.TotalTokens
+		totalCost += bucket.CostUSD
+	}
+
+	table.addSeparatorLine()
+	row := []string{"Total"}
+	rest := totalCost
+	for _, model := range columns {
+		row = append(row, cell(totals[model], totalCost, true))
+		rest -= totals[model]
+	}
+	if other {
+		row = append(row, cell(rest, totalCost, true))
+	}
+	table.addRow(append(row, formatWithCommas(totalTokens), formatCost(totalCost)))
+	fmt.Println(table.render())
+}
+
+// countUsed counts the models with usage in costs
+func countUsed(costs map[string]float64, models []string) int {
+	used := 0
+	for _, model := range models {
+		if _, ok := costs[model]; ok {
+			used++
+		}
+	}
+	return used
+}
+
+func outputBlocksReport(blocks []models.SessionBlock, location *time.Location) {
+	if len(blocks) == 0 {
+		fmt.Println("No session blocks found.")
+		return
+	}
+
+	table := newTableFormatter([]string{"Start", "End", "Status", "Entries", "Total Tokens", "Cost (USD)", "Models"})
+	totalTokens, totalCost := 0, 0.0
+	for _, block := range blocks {
+		status := "completed"
+		if block.IsGap {
+			status = "gap"
+		} else if block.IsActive {
+			status = "active"
+		}
+		table.addRow([]string{
+			block.StartTime.In(location).Format("2006-01-02 15:04"),
+			block.EndTime.In(location).Format("2006-01-02 15:04"),
+			status,
+			formatWithCommas(len(block.Entries)),
+			formatWithCommas(block.TokenCounts.TotalTokens()),
+			formatCost(block.CostUSD),
+			formatModels(block.Models),
+		})
+		totalTokens += block.TokenCounts.TotalTokens()
+		totalCost += block.CostUSD
+	}
+
+	table.addSeparatorLine()
+	table.addRow([]string{"Total", "", "", "", formatWithCommas(totalTokens), formatCost(totalCost), ""})
+	fmt.Println(table.render())
+}
@@ -1,15 +1,22 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/penwyp/claudecat/config"
+	"github.com/penwyp/claudecat/instance"
 	"github.com/penwyp/claudecat/internal"
 	"github.com/penwyp/claudecat/logging"
+	"github.com/penwyp/claudecat/models"
+	"github.com/penwyp/claudecat/pkg/claudecat"
+	"github.com/penwyp/claudecat/telemetry"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -27,10 +34,17 @@ var (
 	runTheme      string
 	runWatch      bool
 	runBackground bool
+	runIdle       time.Duration
+	runTokenLimit string
+	runCostLimit  float64
+	runNoNotify   bool
+	runForce      bool
 	// pricing and deduplication flags
 	pricingSource       string
 	pricingOffline      bool
 	enableDeduplication bool
+	costMode            string
+	includeSuspect      bool
 	// Monitor view flags
 	timezone   string
 	timeFormat string
@@ -49,26 +63,21 @@ capabilities to help developers track their Claude API usage efficiently.`,
 		return initializeConfig()
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		// Load and validate configuration
-		cfg, err := loadConfiguration(cmd)
+		cfg, err := loadMonitorConfiguration(cmd)
 		if err != nil {
-			return fmt.Errorf("failed to load configuration: %w", err)
+			return err
 		}
 
-		// Override configuration with command line flags
-		if err := applyRunFlags(cfg); err != nil {
-			return fmt.Errorf("failed to apply command flags: %w", err)
+		// Initialize global logger with debug mode support
+		if err := initLogging(cfg); err != nil {
+			return err
 		}
-
-		// Apply debug flag if set from command line
-		if debug {
-			cfg.Debug.Enabled = true
-			// Set log level to debug when debug flag is enabled
-			cfg.App.LogLevel = "debug"
+		release, err := acquireInstanceLock(cfg, "claudecat")
+		if err != nil {
+			return err
 		}
-
-		// Initialize global logger with debug mode support
-		logging.InitLogger(cfg.App.LogLevel, cfg.App.LogFile, cfg.Debug.Enabled)
+		defer release()
+		defer initTelemetry(cfg)()
 
 		// Create and run enhanced application
 		app, err := internal.NewEnhancedApplication(cfg)
@@ -76,6 +85,11 @@ capabilities to help developers track their Claude API usage efficiently.`,
 			return fmt.Errorf("failed to create enhanced application: %w", err)
 		}
 
+		// Re-read the configuration files on SIGHUP; command line flags keep taking precedence
+		app.SetConfigLoader(func() (*config.Config, error) {
+			return loadMonitorConfiguration(cmd)
+		})
+
 		if verbose {
 			fmt.Fprintf(os.Stderr, "Starting claudecat console monitor...\n")
 			fmt.Fprintf(os.Stderr, "Configuration: %+v\n", cfg)
@@ -90,6 +104,24 @@ func Execute() error {
 	return rootCmd.Execute()
 }
 
+// ExitError makes claudecat exit with Code rather than 1. Err, when set, is printed like any
+// other error; commands that already reported their outcome leave it nil.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("exit status %d", e.Code)
+	}
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
 func init() {
 	cobra.OnInitialize(initConfig)
 
@@ -109,15 +141,24 @@ func init() {
 
 	// Run command flags (now default behavior)
 	rootCmd.Flags().StringSliceVarP(&runPaths, "paths", "p", nil, "data paths to monitor (can be specified multiple times)")
-	rootCmd.Flags().StringVar(&runPlan, "plan", "", "subscription plan (free, pro, team, max5, max20, custom)")
+	rootCmd.Flags().StringVar(&runPlan, "plan", "", "subscription plan (free, pro, team, max5, max20, custom, or one defined under subscription.plans; custom derives the token limit from past sessions)")
 	rootCmd.Flags().DurationVarP(&runRefresh, "refresh", "r", 0, "refresh interval (e.g., 1s, 500ms)")
 	rootCmd.Flags().StringVarP(&runTheme, "theme", "t", "", "UI theme (dark, light, high-contrast)")
 	rootCmd.Flags().BoolVarP(&runWatch, "watch", "w", false, "enable file watching for real-time updates")
 	rootCmd.Flags().BoolVar(&runBackground, "background", false, "run in background mode (minimal UI)")
+	rootCmd.Flags().DurationVar(&runIdle, "idle-timeout", 0, "in background mode, suspend refreshes after this long without data file writes (e.g., 15m)")
+	rootCmd.Flags().StringVar(&runTokenLimit, "token-limit", "", "override the plan token limit (number or p90)")
+	rootCmd.Flags().Float64Var(&runCostLimit, "cost-limit", 0, "override the plan cost limit in USD")
+	rootCmd.Flags().BoolVar(&runNoNotify, "no-notify", false, "disable desktop and other limit notifications")
+	rootCmd.Flags().BoolVar(&runForce, "force", false, "run even when another claudecat monitor is using the same cache")
+	// Alias of --cost-mode; not persistent, as verify has a --mode of its own
+	rootCmd.Flags().StringVar(&costMode, "mode", "", "alias of --cost-mode")
 
 	// Global pricing flags (moved from analyze command)
 	rootCmd.PersistentFlags().StringVar(&pricingSource, "pricing-source", "", "pricing source (default, litellm)")
 	rootCmd.PersistentFlags().BoolVar(&pricingOffline, "pricing-offline", false, "use cached pricing data for offline mode")
+	rootCmd.PersistentFlags().StringVar(&costMode, "cost-mode", "", "cost mode (auto, display, calculate)")
+	rootCmd.PersistentFlags().BoolVar(&includeSuspect, "include-suspect", false, "include entries with implausible token counts or costs in metrics")
 
 	// Pricing and deduplication flags
 	rootCmd.Flags().BoolVar(&enableDeduplication, "deduplication", false, "enable deduplication of entries across all files")
@@ -168,6 +209,9 @@ func init() {
 	if err := viper.BindPFlag("data.pricing_offline_mode", rootCmd.PersistentFlags().Lookup("pricing-offline")); err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to bind pricing-offline flag: %v\n", err)
 	}
+	if err := viper.BindPFlag("data.cost_mode", rootCmd.PersistentFlags().Lookup("cost-mode")); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to bind cost-mode flag: %v\n", err)
+	}
 
 	// Bind deduplication flag
 	if err := viper.BindPFlag("data.deduplication", rootCmd.Flags().Lookup("deduplication")); err != nil {
@@ -201,7 +245,7 @@ func initConfig() {
 	}
 
 	// Environment variable prefix
-	viper.SetEnvPrefix("CLAWCAT")
+	viper.SetEnvPrefix(config.EnvPrefix)
 	viper.AutomaticEnv()
 
 	// Set default values
@@ -261,8 +305,9 @@ func initializeConfig() error {
 	return nil
 }
 
-func loadConfiguration(cmd *cobra.Command) (*config.Config, error) {
-	// Create config loader
+// newConfigLoader creates a loader reading the config files, environment and command line
+// flags, without validation
+func newConfigLoader(cmd *cobra.Command) *config.Loader {
 	loader := config.NewLoader()
 
 	// Add default configuration paths as file sources
@@ -271,11 +316,17 @@ func loadConfiguration(cmd *cobra.Command) (*config.Config, error) {
 	}
 
 	// Add environment variable source
-	loader.AddSource(config.NewEnvSource("CLAWCAT"))
+	loader.AddSource(config.NewEnvSource(config.EnvPrefix))
 
 	// Add command line flags source
 	loader.AddSource(config.NewFlagSource(cmd.Flags()))
 
+	return loader
+}
+
+func loadConfiguration(cmd *cobra.Command) (*config.Config, error) {
+	loader := newConfigLoader(cmd)
+
 	// Add validator
 	loader.AddValidator(config.NewStandardValidator())
 
@@ -285,6 +336,83 @@ func loadConfiguration(cmd *cobra.Command) (*config.Config, error) {
 		return nil, err
 	}
 
+	// Model names are normalized everywhere entries are parsed, so these apply process-wide
+	if err := claudecat.ApplySettings(cfg); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// initLogging initializes the global logger from the application settings
+func initLogging(cfg *config.Config) error {
+	if err := logging.Init(internal.LoggingOptions(cfg)); err != nil {
+		return fmt.Errorf("failed to initialize logging: %w", err)
+	}
+	return nil
+}
+
+// acquireInstanceLock makes sure no other monitor uses the cache directory, unless --force
+// is set. The returned function releases the lock.
+func acquireInstanceLock(cfg *config.Config, command string) (func(), error) {
+	lock, err := instance.Acquire(claudecat.CacheDir(cfg), command, runForce)
+	if err != nil {
+		return nil, err
+	}
+	return func() {
+		if err := lock.Release(); err != nil {
+			logging.LogWarnf("Failed to release instance lock: %v", err)
+		}
+	}, nil
+}
+
+// telemetryShutdownTimeout bounds how long exiting commands wait for buffered telemetry to flush
+const telemetryShutdownTimeout = 5 * time.Second
+
+// initTelemetry starts exporting traces and metrics when telemetry.endpoint is configured.
+// The returned function flushes and stops the exporters; setup failures only disable telemetry.
+func initTelemetry(cfg *config.Config) func() {
+	shutdown, err := telemetry.Setup(context.Background(), telemetry.Options{
+		Endpoint:       cfg.Telemetry.Endpoint,
+		Headers:        cfg.Telemetry.Headers,
+		ServiceName:    cfg.Telemetry.ServiceName,
+		ServiceVersion: Version,
+		SampleRatio:    cfg.Telemetry.SampleRatio,
+		MetricInterval: cfg.Telemetry.MetricInterval,
+	})
+	if err != nil {
+		logging.LogWarnf("Telemetry disabled: %v", err)
+		return func() {}
+	}
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), telemetryShutdownTimeout)
+		defer cancel()
+		if err := shutdown(ctx); err != nil {
+			logging.LogWarnf("Failed to flush telemetry: %v", err)
+		}
+	}
+}
+
+// loadMonitorConfiguration loads the configuration for the monitor and applies its command line flags
+func loadMonitorConfiguration(cmd *cobra.Command) (*config.Config, error) {
+	// Load and validate configuration
+	cfg, err := loadConfiguration(cmd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	// Override configuration with command line flags
+	if err := applyRunFlags(cfg); err != nil {
+		return nil, fmt.Errorf("failed to apply command flags: %w", err)
+	}
+
+	// Apply debug flag if set from command line
+	if debug {
+		cfg.Debug.Enabled = true
+		// Set log level to debug when debug flag is enabled
+		cfg.App.LogLevel = "debug"
+	}
+
 	return cfg, nil
 }
 
@@ -303,6 +431,15 @@ func applyRunFlags(cfg *config.Config) error {
 	// Apply subscription plan if provided
 	if runPlan != "" {
 		validPlans := []string{"free", "pro", "team", "max5", "max20", "custom"}
+		// Plans defined in the configuration file are valid too
+		var definedPlans []string
+		for name := range cfg.Subscription.Plans {
+			if !containsFold(validPlans, name) {
+				definedPlans = append(definedPlans, name)
+			}
+		}
+		sort.Strings(definedPlans)
+		validPlans = append(validPlans, definedPlans...)
 		found := false
 		for _, plan := range validPlans {
 			if strings.EqualFold(runPlan, plan) {
@@ -317,6 +454,27 @@ func applyRunFlags(cfg *config.Config) error {
 		}
 	}
 
+	// Apply limit overrides if provided
+	if runTokenLimit != "" {
+		if strings.EqualFold(runTokenLimit, "p90") {
+			cfg.Subscription.TokenLimitP90 = true
+			cfg.Subscription.CustomTokenLimit = 0
+		} else {
+			limit, err := strconv.Atoi(strings.ReplaceAll(runTokenLimit, ",", ""))
+			if err != nil || limit <= 0 {
+				return fmt.Errorf("invalid token limit: %s (expected a positive number or p90)", runTokenLimit)
+			}
+			cfg.Subscription.CustomTokenLimit = limit
+			cfg.Subscription.TokenLimitP90 = false
+		}
+	}
+	if runCostLimit < 0 {
+		return fmt.Errorf("invalid cost limit: %v (must be positive)", runCostLimit)
+	}
+	if runCostLimit > 0 {
+		cfg.Subscription.CustomCostLimit = runCostLimit
+	}
+
 	// Apply refresh interval if provided
 	if runRefresh > 0 {
 		if runRefresh < 100*time.Millisecond {
@@ -354,27 +512,21 @@ func applyRunFlags(cfg *config.Config) error {
 	if runBackground {
 		cfg.UI.CompactMode = true
 	}
-
-	// Apply pricing source if provided
-	if pricingSource != "" {
-		validSources := []string{"default", "litellm"}
-		found := false
-		for _, source := range validSources {
-			if strings.EqualFold(pricingSource, source) {
-				cfg.Data.PricingSource = strings.ToLower(pricingSource)
-				found = true
-				break
-			}
-		}
-		if !found {
-			return fmt.Errorf("invalid pricing source: %s (valid options: %s)",
-				pricingSource, strings.Join(validSources, ", "))
+	if runIdle > 0 {
+		if runIdle < time.Minute {
+			return fmt.Errorf("idle timeout too small: %v (minimum: 1m)", runIdle)
 		}
+		cfg.UI.IdleTimeout = runIdle
 	}
 
-	// Apply pricing offline mode if set
-	if pricingOffline {
-		cfg.Data.PricingOfflineMode = true
+	// Disable limit notifications if requested
+	if runNoNotify {
+		cfg.Limits.Enabled = false
+	}
+
+	// Apply pricing, cost mode and validation flags
+	if err := applyDataFlags(cfg); err != nil {
+		return err
 	}
 
 	// Apply deduplication if set
@@ -406,3 +558,44 @@ func applyRunFlags(cfg *config.Config) error {
 
 	return nil
 }
+
+// applyDataFlags applies the global pricing, cost and validation flags shared by every command
+func applyDataFlags(cfg *config.Config) error {
+	// Apply pricing source if provided
+	if pricingSource != "" {
+		validSources := []string{"default", "litellm"}
+		found := false
+		for _, source := range validSources {
+			if strings.EqualFold(pricingSource, source) {
+				cfg.Data.PricingSource = strings.ToLower(pricingSource)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("invalid pricing source: %s (valid options: %s)",
+				pricingSource, strings.Join(validSources, ", "))
+		}
+	}
+
+	// Apply pricing offline mode if set
+	if pricingOffline {
+		cfg.Data.PricingOfflineMode = true
+	}
+
+	// Apply cost mode if provided
+	if costMode != "" {
+		mode, err := models.ParseCostMode(costMode)
+		if err != nil {
+			return err
+		}
+		cfg.Data.CostMode = mode.String()
+	}
+
+	// Keep suspect entries if requested
+	if includeSuspect {
+		cfg.Data.Validation.IncludeSuspect = true
+	}
+
+	return nil
+}
@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/penwyp/claudecat/logging"
+	"github.com/penwyp/claudecat/orchestrator"
+	"github.com/penwyp/claudecat/pkg/claudecat"
+	"github.com/penwyp/claudecat/server"
+	"github.com/spf13/cobra"
+)
+
+var (
+	serveHost string
+	servePort int
+	serveUI   bool
+	serveGRPC int
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve [flags] [path...]",
+	Short: "Serve live usage metrics over an HTTP JSON API and web dashboard",
+	Long: `Monitor usage in the background and expose the live metrics over HTTP, so
+external dashboards can poll claudecat instead of parsing console output. A web
+dashboard with live gauges, the hourly burn rate and the session history is
+served at the root, so usage can be kept open in a browser tab.
+
+Endpoints:
+  GET /                         Web dashboard (disable with --dashboard=false)
+  GET /api/v1/metrics           Real-time metrics for the current session
+  GET /api/v1/blocks            Session blocks (?limit=N, ?gaps=true, ?entries=true)
+  GET /api/v1/sessions/active   The active session block (404 when idle)
+  GET /api/v1/trend             Cost and tokens per model per day or hour (?interval=hourly, ?model=opus, ?project=NAME)
+  GET /api/v1/stream            Server-Sent Events: a "metrics" event with each update
+  GET /healthz                  Monitoring health checks (503 when unhealthy)
+  GET /readyz                   Whether usage data has been loaded (503 until then)
+
+With --grpc-port, the same data is also served over gRPC (service
+claudecat.v1.UsageService, defined in proto/claudecat/v1/claudecat.proto).
+
+Examples:
+  claudecat serve                       # Listen on 127.0.0.1:8080
+  claudecat serve --port 9090
+  claudecat serve --host 0.0.0.0        # Listen on all interfaces
+  claudecat serve --dashboard=false     # JSON API only
+  claudecat serve --grpc-port 9091      # Also serve gRPC on 127.0.0.1:9091`,
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadSessionCommandConfig(cmd)
+		if err != nil {
+			return err
+		}
+		if len(args) > 0 {
+			cfg.Data.Paths = args
+		}
+		defer initTelemetry(cfg)()
+
+		if servePort < 1 || servePort > 65535 {
+			return fmt.Errorf("invalid port: %d (must be between 1 and 65535)", servePort)
+		}
+		if serveGRPC < 0 || serveGRPC > 65535 || (serveGRPC != 0 && serveGRPC == servePort) {
+			return fmt.Errorf("invalid gRPC port: %d (must be between 1 and 65535 and differ from --port)", serveGRPC)
+		}
+		addr := net.JoinHostPort(serveHost, strconv.Itoa(servePort))
+
+		updateInterval := cfg.UI.RefreshRate
+		if updateInterval <= 0 {
+			updateInterval = 10 * time.Second
+		}
+
+		release, err := acquireInstanceLock(cfg, "claudecat serve")
+		if err != nil {
+			return err
+		}
+		defer release()
+
+		srv := server.NewServer(addr, cfg)
+		srv.SetDashboard(serveUI)
+		monitor := orchestrator.NewMonitoringOrchestrator(updateInterval, claudecat.DataPaths(cfg), cfg)
+		monitor.RegisterUpdateCallback(srv.Update)
+		srv.SetHealthCheck(monitor.GetHealth)
+		if err := monitor.Start(); err != nil {
+			return fmt.Errorf("failed to start monitoring: %w", err)
+		}
+		defer monitor.Stop()
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		serveErr := make(chan error, 1)
+		go func() {
+			serveErr <- srv.Serve()
+		}()
+		var grpcSrv *server.GRPCServer
+		grpcErr := make(chan error, 1)
+		if serveGRPC != 0 {
+			grpcAddr := net.JoinHostPort(serveHost, strconv.Itoa(serveGRPC))
+			grpcSrv = server.NewGRPCServer(grpcAddr, srv)
+			go func() {
+				grpcErr <- grpcSrv.Serve()
+			}()
+			fmt.Fprintf(os.Stderr, "Serving claudecat gRPC API on %s\n", grpcAddr)
+		}
+		if serveUI {
+			fmt.Fprintf(os.Stderr, "Serving claudecat dashboard and API on http://%s (Ctrl+C to stop)\n", addr)
+		} else {
+			fmt.Fprintf(os.Stderr, "Serving claudecat API on http://%s (Ctrl+C to stop)\n", addr)
+		}
+
+		select {
+		case err := <-serveErr:
+			return err
+		case err := <-grpcErr:
+			return err
+		case <-ctx.Done():
+		}
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if grpcSrv != nil {
+			if err := grpcSrv.Shutdown(shutdownCtx); err != nil {
+				logging.LogWarnf("gRPC server shutdown: %v", err)
+			}
+			if err := <-grpcErr; err != nil {
+				return err
+			}
+		}
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			logging.LogWarnf("HTTP server shutdown: %v", err)
+		}
+		return <-serveErr
+	},
+}
+
+func init() {
+	serveCmd.Flags().StringVar(&serveHost, "host", "127.0.0.1", "address to listen on")
+	serveCmd.Flags().IntVar(&servePort, "port", 8080, "port to listen on")
+	serveCmd.Flags().BoolVar(&serveUI, "dashboard", true, "serve the web dashboard at /")
+	serveCmd.Flags().IntVar(&serveGRPC, "grpc-port", 0, "also serve the gRPC API on this port (0 disables it)")
+	serveCmd.Flags().BoolVar(&runForce, "force", false, "serve even when another claudecat monitor is using the same cache")
+
+	rootCmd.AddCommand(serveCmd)
+}
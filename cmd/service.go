@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/penwyp/claudecat/config"
+	"github.com/penwyp/claudecat/service"
+	"github.com/spf13/cobra"
+)
+
+var (
+	serviceLogFile string
+	servicePaths   []string
+	servicePrint   bool
+	serviceOutput  string
+)
+
+// serviceCommandTimeout bounds each systemctl or launchctl call
+const serviceCommandTimeout = 30 * time.Second
+
+var serviceCmd = &cobra.Command{
+	Use:   "service",
+	Short: "Run claudecat in the background at login",
+	Long: `Install claudecat as a per-user background service, so monitoring is always on
+without a terminal: limit notifications, history snapshots and digests keep working.
+On Linux a systemd user unit is written to ~/.config/systemd/user/claudecat.service,
+on macOS a launch agent to ~/Library/LaunchAgents/com.penwyp.claudecat.plist.
+
+The service runs "claudecat --background" from the home directory, so it reads
+~/.config/claudecat/config.yaml. The log file and data paths are passed as
+CLAUDECAT_ environment variables.
+
+Examples:
+  claudecat service install
+  claudecat service install --paths ~/work/claude-data --log-file ~/claudecat.log
+  claudecat service install --print     # Show the unit or plist without installing
+  claudecat service status
+  claudecat service uninstall`,
+}
+
+var serviceInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Install and start the background service",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := service.NewManager()
+		if err != nil {
+			return err
+		}
+		spec, err := newServiceSpec(manager)
+		if err != nil {
+			return err
+		}
+
+		if servicePrint {
+			content, err := manager.Render(spec)
+			if err != nil {
+				return err
+			}
+			fmt.Print(content)
+			return nil
+		}
+
+		logFile := spec.Env[config.EnvVar(config.EnvPrefix, "app.log_file")]
+		if err := os.MkdirAll(filepath.Dir(logFile), 0755); err != nil {
+			return fmt.Errorf("failed to create log directory: %w", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), serviceCommandTimeout)
+		defer cancel()
+		path, err := manager.Install(ctx, spec)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Installed %s\n", path)
+		fmt.Printf("claudecat now runs in the background and at every login; logs go to %s\n", logFile)
+		return nil
+	},
+}
+
+var serviceUninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Stop and remove the background service",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := service.NewManager()
+		if err != nil {
+			return err
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), serviceCommandTimeout)
+		defer cancel()
+		path, err := manager.Uninstall(ctx)
+		if errors.Is(err, service.ErrNotInstalled) {
+			fmt.Println("Not installed.")
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Removed %s\n", path)
+		return nil
+	},
+}
+
+var serviceStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show whether the background service is installed and running",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		validOutputs := []string{"table", "json"}
+		if !containsFold(validOutputs, serviceOutput) {
+			return fmt.Errorf("invalid output format: %s (valid options: %s)",
+				serviceOutput, strings.Join(validOutputs, ", "))
+		}
+
+		manager, err := service.NewManager()
+		if err != nil {
+			return err
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), serviceCommandTimeout)
+		defer cancel()
+		status, err := manager.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		if strings.EqualFold(serviceOutput, "json") {
+			return writeJSON(status)
+		}
+		switch {
+		case !status.Installed:
+			fmt.Println("Not installed (install with: claudecat service install)")
+		case status.Running:
+			fmt.Printf("Running (%s)\n", status.Path)
+		default:
+			fmt.Printf("Installed but not running: %s (%s)\n", status.Detail, status.Path)
+		}
+		return nil
+	},
+}
+
+func init() {
+	serviceInstallCmd.Flags().StringVar(&serviceLogFile, "log-file", "", "log file of the service (default: claudecat.log in the platform's log directory)")
+	serviceInstallCmd.Flags().StringSliceVarP(&servicePaths, "paths", "p", nil, "data paths to monitor (can be specified multiple times)")
+	serviceInstallCmd.Flags().BoolVar(&servicePrint, "print", false, "print the unit or plist instead of installing it")
+	serviceStatusCmd.Flags().StringVarP(&serviceOutput, "output", "o", "table", "output format (table, json)")
+
+	serviceCmd.AddCommand(serviceInstallCmd)
+	serviceCmd.AddCommand(serviceUninstallCmd)
+	serviceCmd.AddCommand(serviceStatusCmd)
+	rootCmd.AddCommand(serviceCmd)
+}
+
+// newServiceSpec describes the background process from the install flags
+func newServiceSpec(manager *service.Manager) (service.Spec, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return service.Spec{}, fmt.Errorf("failed to find the claudecat binary: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(executable); err == nil {
+		executable = resolved
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return service.Spec{}, fmt.Errorf("failed to find home directory: %w", err)
+	}
+
+	logDir, err := manager.LogDir()
+	if err != nil {
+		return service.Spec{}, err
+	}
+	logFile := filepath.Join(logDir, "claudecat.log")
+	if serviceLogFile != "" {
+		if logFile, err = filepath.Abs(serviceLogFile); err != nil {
+			return service.Spec{}, fmt.Errorf("invalid log file: %w", err)
+		}
+	}
+
+	spec := service.Spec{
+		Executable: executable,
+		Args:       []string{"--background"},
+		Env:        map[string]string{config.EnvVar(config.EnvPrefix, "app.log_file"): logFile},
+		WorkingDir: home,
+		OutputLog:  filepath.Join(logDir, "service.log"),
+	}
+	if len(servicePaths) > 0 {
+		paths := make([]string, 0, len(servicePaths))
+		for _, path := range servicePaths {
+			abs, err := filepath.Abs(path)
+			if err != nil {
+				return service.Spec{}, fmt.Errorf("invalid data path %s: %w", path, err)
+			}
+			if _, err := os.Stat(abs); err != nil {
+				return service.Spec{}, fmt.Errorf("data path %s: %w", path, err)
+			}
+			paths = append(paths, abs)
+		}
+		spec.Env[config.EnvVar(config.EnvPrefix, "data.paths")] = strings.Join(paths, ",")
+	}
+	return spec, nil
+}
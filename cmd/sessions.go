@@ -0,0 +1,282 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bytedance/sonic"
+	"github.com/penwyp/claudecat/config"
+	"github.com/penwyp/claudecat/sessions"
+	"github.com/spf13/cobra"
+)
+
+var (
+	sessionsFrom   string
+	sessionsTo     string
+	sessionsLimit  int
+	sessionsOutput string
+)
+
+var sessionsCmd = &cobra.Command{
+	Use:   "sessions",
+	Short: "Inspect recorded usage sessions",
+	Long: `Inspect the 5-hour usage sessions kept in the session history.
+
+Examples:
+  claudecat sessions list                 # 20 most recent sessions
+  claudecat sessions list --limit 0       # All recorded sessions
+  claudecat sessions show 215f059d9386    # Details for one session
+  claudecat sessions show 215f            # IDs can be shortened to a unique prefix
+  claudecat sessions list -o json         # Machine-readable output`,
+}
+
+var sessionsListCmd = &cobra.Command{
+	Use:   "list [flags]",
+	Short: "List recent sessions",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := openSessionHistory(cmd)
+		if err != nil {
+			return err
+		}
+
+		from, to, err := parseTimeRange(sessionsFrom, sessionsTo)
+		if err != nil {
+			return err
+		}
+
+		records := store.Sessions(from, to)
+		// Keep the most recent sessions when limiting
+		if sessionsLimit > 0 && len(records) > sessionsLimit {
+			records = records[len(records)-sessionsLimit:]
+		}
+
+		if sessionsOutput == "json" {
+			if records == nil {
+				records = []sessions.HistoryRecord{}
+			}
+			return writeJSON(records)
+		}
+
+		outputSessionList(records)
+		return nil
+	},
+}
+
+var sessionsShowCmd = &cobra.Command{
+	Use:   "show <session-id>",
+	Short: "Show details for a single session",
+	Long:  "Show details for a single session. The ID may be shortened to any prefix that matches only one session.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := openSessionHistory(cmd)
+		if err != nil {
+			return err
+		}
+
+		record, err := store.Session(args[0])
+		if err != nil {
+			return err
+		}
+
+		if sessionsOutput == "json" {
+			return writeJSON(record)
+		}
+
+		outputSessionDetail(record)
+		return nil
+	},
+}
+
+func init() {
+	sessionsCmd.PersistentFlags().StringVarP(&sessionsOutput, "output", "o", "table", "output format (table, json)")
+	sessionsCmd.PersistentFlags().StringSliceVarP(&runPaths, "paths", "p", nil, "data paths to scan (can be specified multiple times)")
+
+	sessionsListCmd.Flags().StringVar(&sessionsFrom, "from", "", "start date (YYYY-MM-DD or YYYY-MM-DD HH:MM:SS)")
+	sessionsListCmd.Flags().StringVar(&sessionsTo, "to", "", "end date (YYYY-MM-DD or YYYY-MM-DD HH:MM:SS)")
+	sessionsListCmd.Flags().IntVar(&sessionsLimit, "limit", 20, "number of most recent sessions to show (0 = all)")
+
+	sessionsCmd.AddCommand(sessionsListCmd)
+	sessionsCmd.AddCommand(sessionsShowCmd)
+	rootCmd.AddCommand(sessionsCmd)
+}
+
+// openSessionHistory loads configuration, validates shared flags and refreshes the session history
+func openSessionHistory(cmd *cobra.Command) (*sessions.HistoryStore, error) {
+	cfg, err := loadSessionCommandConfig(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	validOutputs := []string{"table", "json"}
+	if !containsFold(validOutputs, sessionsOutput) {
+		return nil, fmt.Errorf("invalid output format: %s (valid options: %s)",
+			sessionsOutput, strings.Join(validOutputs, ", "))
+	}
+	sessionsOutput = strings.ToLower(sessionsOutput)
+
+	return refreshSessionHistory(cfg)
+}
+
+// loadSessionCommandConfig loads configuration and initializes logging for history-based commands
+func loadSessionCommandConfig(cmd *cobra.Command) (*config.Config, error) {
+	cfg, err := loadConfiguration(cmd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	if err := applyRunFlags(cfg); err != nil {
+		return nil, fmt.Errorf("failed to apply command flags: %w", err)
+	}
+
+	if debug {
+		cfg.Debug.Enabled = true
+		cfg.App.LogLevel = "debug"
+	}
+	if err := initLogging(cfg); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+func outputSessionList(records []sessions.HistoryRecord) {
+	if len(records) == 0 {
+		fmt.Println("No sessions recorded.")
+		return
+	}
+
+	table := newTableFormatter([]string{"Session", "Start", "End", "Duration", "Total Tokens", "Cost (USD)", "Limits", "Models"})
+	for _, record := range records {
+		end := record.EndTime
+		if record.ActualEndTime != nil {
+			end = *record.ActualEndTime
+		}
+
+		id := record.ID
+		if record.IsActive {
+			id += " *"
+		}
+
+		models := append([]string{}, record.Models...)
+		sortModelsByPreference(models)
+
+		table.addRow([]string{
+			id,
+			record.StartTime.Local().Format("2006-01-02 15:04"),
+			end.Local().Format("2006-01-02 15:04"),
+			formatDowntime(record.Duration()),
+			formatWithCommas(record.TotalTokens),
+			formatCost(record.CostUSD),
+			strconv.Itoa(len(record.Limits)),
+			formatModels(models),
+		})
+	}
+	fmt.Println(table.render())
+}
+
+func outputSessionDetail(record *sessions.HistoryRecord) {
+	fmt.Printf("Session:   %s\n", record.ID)
+	fmt.Printf("Window:    %s - %s\n",
+		record.StartTime.Local().Format("2006-01-02 15:04"),
+		record.EndTime.Local().Format("2006-01-02 15:04"))
+	if record.ActualEndTime != nil {
+		fmt.Printf("Last used: %s\n", record.ActualEndTime.Local().Format("2006-01-02 15:04"))
+	}
+	fmt.Printf("Duration:  %s\n", formatDowntime(record.Duration()))
+	fmt.Printf("Active:    %v\n", record.IsActive)
+	fmt.Printf("Entries:   %s\n", formatWithCommas(record.EntryCount))
+	fmt.Printf("Tokens:    %s\n", formatWithCommas(record.TotalTokens))
+	fmt.Printf("Cost:      %s\n", formatCost(record.CostUSD))
+
+	if len(record.PerModel) > 0 {
+		modelNames := make([]string, 0, len(record.PerModel))
+		for model := range record.PerModel {
+			modelNames = append(modelNames, model)
+		}
+		sortModelsByPreference(modelNames)
+
+		fmt.Println()
+		table := newTableFormatter([]string{"Model", "Entries", "Input", "Output", "Cache Create", "Cache Read", "Thinking", "Total Tokens", "Cost (USD)"})
+		for _, model := range modelNames {
+			usage := record.PerModel[model]
+			table.addRow([]string{
+				model,
+				formatWithCommas(usage.EntryCount),
+				formatWithCommas(usage.InputTokens),
+				formatWithCommas(usage.OutputTokens),
+				formatWithCommas(usage.CacheCreationTokens),
+				formatWithCommas(usage.CacheReadTokens),
+				formatWithCommas(usage.ThinkingTokens),
+				formatWithCommas(usage.TotalTokens),
+				formatCost(usage.CostUSD),
+			})
+		}
+		fmt.Println(table.render())
+	}
+
+	if len(record.Projects) > 0 {
+		type projectShare struct {
+			name   string
+			tokens int
+		}
+		projects := make([]projectShare, 0, len(record.Projects))
+		for name, tokens := range record.Projects {
+			projects = append(projects, projectShare{name: name, tokens: tokens})
+		}
+		sort.Slice(projects, func(i, j int) bool {
+			if projects[i].tokens != projects[j].tokens {
+				return projects[i].tokens > projects[j].tokens
+			}
+			return projects[i].name < projects[j].name
+		})
+		if len(projects) > 5 {
+			projects = projects[:5]
+		}
+
+		fmt.Println()
+		fmt.Println("Top projects:")
+		table := newTableFormatter([]string{"Project", "Total Tokens", "Share"})
+		for _, project := range projects {
+			share := 0.0
+			if record.TotalTokens > 0 {
+				share = float64(project.tokens) / float64(record.TotalTokens) * 100
+			}
+			table.addRow([]string{
+				project.name,
+				formatWithCommas(project.tokens),
+				strconv.FormatFloat(share, 'f', 1, 64) + "%",
+			})
+		}
+		fmt.Println(table.render())
+	}
+
+	fmt.Println()
+	if len(record.Limits) == 0 {
+		fmt.Println("No limits hit in this session.")
+		return
+	}
+	fmt.Println("Limits:")
+	table := newTableFormatter([]string{"Time", "Type", "Tokens at Hit", "Downtime"})
+	for _, event := range record.Limits {
+		table.addRow([]string{
+			event.Timestamp.Local().Format("2006-01-02 15:04"),
+			event.Type,
+			formatWithCommas(event.TokensAtHit),
+			formatDowntime(event.Downtime),
+		})
+	}
+	fmt.Println(table.render())
+}
+
+// writeJSON writes v to stdout as indented JSON
+func writeJSON(v interface{}) error {
+	data, err := sonic.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(append(data, '\n'))
+	return err
+}
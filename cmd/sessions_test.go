@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/penwyp/claudecat/sessions"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runCommand runs claudecat with args and returns what it wrote to stdout
+func runCommand(t *testing.T, args ...string) string {
+	t.Helper()
+
+	reader, writer, err := os.Pipe()
+	require.NoError(t, err)
+	stdout := os.Stdout
+	os.Stdout = writer
+	defer func() { os.Stdout = stdout }()
+
+	output := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(reader)
+		output <- string(data)
+	}()
+
+	rootCmd.SetArgs(args)
+	err = rootCmd.Execute()
+	require.NoError(t, writer.Close())
+	require.NoError(t, err)
+	return <-output
+}
+
+func TestSessionsCommands(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_STATE_HOME", filepath.Join(home, "state"))
+	// Keeps the log file and any claudecat.yaml of the working directory out of the test
+	t.Chdir(home)
+
+	dataDir := t.TempDir()
+	projectDir := filepath.Join(dataDir, "-Users-demo-projects-webapp")
+	require.NoError(t, os.MkdirAll(projectDir, 0755))
+	lines := []string{
+		`{"type":"assistant","timestamp":"2025-06-01T10:05:00Z","requestId":"r1","message":{"id":"m1","model":"claude-sonnet-4-20250514","usage":{"input_tokens":100,"output_tokens":50}}}`,
+		`{"type":"assistant","timestamp":"2025-06-01T10:35:00Z","requestId":"r2","message":{"id":"m2","model":"claude-sonnet-4-20250514","usage":{"input_tokens":200,"output_tokens":100}}}`,
+		`{"type":"assistant","timestamp":"2025-06-02T14:10:00Z","requestId":"r3","message":{"id":"m3","model":"claude-opus-4-20250514","usage":{"input_tokens":300,"output_tokens":150}}}`,
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, "session.jsonl"), []byte(strings.Join(lines, "\n")+"\n"), 0644))
+	// Set in a config file, repeating --paths would add the directory again on each run
+	configDir := filepath.Join(home, ".config", "claudecat")
+	require.NoError(t, os.MkdirAll(configDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "config.yaml"), []byte("data:\n  paths:\n    - "+dataDir+"\n"), 0644))
+
+	var records []sessions.HistoryRecord
+	require.NoError(t, sonic.UnmarshalString(runCommand(t, "sessions", "list", "-o", "json", "--limit", "0"), &records))
+	require.Len(t, records, 2)
+	assert.Equal(t, time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC), records[0].StartTime.UTC())
+	assert.Equal(t, 2, records[0].EntryCount)
+	assert.Equal(t, 450, records[0].TotalTokens)
+	assert.Equal(t, []string{"claude-opus-4-20250514"}, records[1].Models)
+
+	// The limit keeps the most recent sessions
+	var recent []sessions.HistoryRecord
+	require.NoError(t, sonic.UnmarshalString(runCommand(t, "sessions", "list", "-o", "json", "--limit", "1"), &recent))
+	require.Len(t, recent, 1)
+	assert.Equal(t, records[1].ID, recent[0].ID)
+
+	// A session can be shown by a prefix of its ID
+	var record sessions.HistoryRecord
+	require.NoError(t, sonic.UnmarshalString(runCommand(t, "sessions", "show", records[0].ID[:6], "-o", "json"), &record))
+	assert.Equal(t, records[0].ID, record.ID)
+	assert.Equal(t, 300, record.PerModel["claude-sonnet-4-20250514"].InputTokens)
+
+	table := runCommand(t, "sessions", "show", records[0].ID, "-o", "table")
+	assert.Contains(t, table, "Entries:   2")
+	assert.Contains(t, table, "No limits hit in this session.")
+}
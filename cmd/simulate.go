@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/penwyp/claudecat/internal"
+	"github.com/spf13/cobra"
+)
+
+var (
+	simulateDays     int
+	simulateProfile  string
+	simulateOutput   string
+	simulateProjects int
+	simulateSeed     int64
+)
+
+var simulateCmd = &cobra.Command{
+	Use:   "simulate [flags]",
+	Short: "Generate synthetic usage data",
+	Long: `Write realistic synthetic Claude Code conversation logs into a data directory.
+
+The generated files follow the same layout and record format as ~/.claude/projects,
+so they can be used to demo the monitor, test alert rules or reproduce performance
+issues without sharing real conversation logs.
+
+Examples:
+  claudecat simulate --days 30 --profile heavy       # Write to a new temp directory
+  claudecat simulate --output ./demo-data --seed 42  # Reproducible data set
+  claudecat --paths "$(claudecat simulate -q)"       # Monitor simulated data`,
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		profiles := make([]string, 0, len(internal.SimulationProfiles))
+		for name := range internal.SimulationProfiles {
+			profiles = append(profiles, name)
+		}
+		sort.Strings(profiles)
+
+		if !containsFold(profiles, simulateProfile) {
+			return fmt.Errorf("invalid profile: %s (valid options: %s)",
+				simulateProfile, strings.Join(profiles, ", "))
+		}
+
+		simulator, err := internal.NewSimulator(internal.SimulateOptions{
+			OutputDir: simulateOutput,
+			Days:      simulateDays,
+			Profile:   strings.ToLower(simulateProfile),
+			Projects:  simulateProjects,
+			Seed:      simulateSeed,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create simulator: %w", err)
+		}
+
+		result, err := simulator.Run()
+		if err != nil {
+			return fmt.Errorf("simulation failed: %w", err)
+		}
+
+		quiet, _ := cmd.Flags().GetBool("quiet")
+		if quiet {
+			fmt.Println(result.OutputDir)
+			return nil
+		}
+
+		fmt.Printf("Generated %s entries in %d files (%d limit events)\n",
+			formatWithCommas(result.Entries), result.Files, result.LimitEvents)
+		fmt.Printf("Profile:  %s (seed %d)\n", result.Profile, result.Seed)
+		fmt.Printf("Range:    %s - %s\n",
+			result.StartTime.Local().Format("2006-01-02"), result.EndTime.Local().Format("2006-01-02 15:04"))
+		fmt.Printf("Data dir: %s\n", result.OutputDir)
+		fmt.Printf("\nRun: claudecat --paths %s\n", result.OutputDir)
+		return nil
+	},
+}
+
+func init() {
+	simulateCmd.Flags().IntVar(&simulateDays, "days", 7, "number of days of history to generate")
+	simulateCmd.Flags().StringVar(&simulateProfile, "profile", "moderate", "usage profile (light, moderate, heavy)")
+	simulateCmd.Flags().StringVarP(&simulateOutput, "output", "o", "", "output directory (default: new temp directory)")
+	simulateCmd.Flags().IntVar(&simulateProjects, "projects", 3, "number of distinct projects (1-6)")
+	simulateCmd.Flags().Int64Var(&simulateSeed, "seed", 0, "random seed for reproducible output (0 = random)")
+	simulateCmd.Flags().BoolP("quiet", "q", false, "only print the output directory")
+
+	rootCmd.AddCommand(simulateCmd)
+}
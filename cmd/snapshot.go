@@ -0,0 +1,249 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/penwyp/claudecat/models"
+	"github.com/penwyp/claudecat/pkg/claudecat"
+	"github.com/penwyp/claudecat/snapshot"
+	"github.com/spf13/cobra"
+)
+
+var (
+	snapshotFile   string
+	snapshotFrom   string
+	snapshotTo     string
+	snapshotType   string
+	snapshotFormat string
+)
+
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Bundle usage data into an archive for offline analysis",
+	Long: `Bundle parsed usage entries, session blocks and the settings they were computed
+with into a single compressed archive, and run reports against such an archive
+without access to the original Claude data directory.
+
+Archives make billing discrepancies reproducible: attach one to a bug report and
+the exact numbers can be inspected on any machine.
+
+Examples:
+  claudecat snapshot create                               # Archive all usage data
+  claudecat snapshot create -f usage.tar.gz --from 2025-06-01
+  claudecat snapshot load usage.tar.gz                    # All reports from the archive
+  claudecat snapshot load usage.tar.gz --type blocks      # Only the 5-hour blocks
+  claudecat snapshot load usage.tar.gz --format ccusage-json`,
+}
+
+var snapshotCreateCmd = &cobra.Command{
+	Use:   "create [flags] [path...]",
+	Short: "Create a snapshot archive of your usage data",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadSessionCommandConfig(cmd)
+		if err != nil {
+			return err
+		}
+		if len(args) > 0 {
+			cfg.Data.Paths = args
+		}
+
+		from, to, err := parseTimeRange(snapshotFrom, snapshotTo)
+		if err != nil {
+			return err
+		}
+
+		// Bypass the summary cache so the archive holds every entry with its exact timestamp
+		entries, limitRecords := loadAllUsageEntries(cfg, true, false)
+		analyzer := newSessionAnalyzer(cfg)
+
+		// Blocks are built from all entries so their boundaries match unfiltered reports
+		archive := snapshot.Archive{
+			Entries: filterExportEntries(entries, from, to),
+			Blocks:  filterExportBlocks(analyzer.TransformToBlocks(entries), from, to, true),
+			Limits:  filterLimitMessages(analyzer.DetectLimits(limitRecords), from, to),
+		}
+
+		now := time.Now()
+		archive.Metadata = snapshot.Metadata{
+			ClaudecatVersion: Version,
+			CreatedAt:        now.UTC(),
+			DataPaths:        claudecat.DataPaths(cfg),
+			Timezone:         locationName(resolveLocation(cfg)),
+			CostMode:         claudecat.CostMode(cfg).String(),
+			PricingSource:    cfg.Data.PricingSource,
+			SessionWindow:    cfg.Session.WindowDuration,
+			Redacted:         cfg.Privacy.RedactContent,
+		}
+		if host, err := os.Hostname(); err == nil {
+			archive.Metadata.Host = host
+		}
+		if !from.IsZero() {
+			archive.Metadata.From = &from
+		}
+		if !to.IsZero() {
+			archive.Metadata.To = &to
+		}
+
+		path := snapshotFile
+		if path == "" {
+			path = fmt.Sprintf("claudecat-snapshot-%s.tar.gz", now.Format("20060102-150405"))
+		}
+		if err := snapshot.Write(path, archive); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Wrote %d entries and %d session blocks to %s\n",
+			len(archive.Entries), len(archive.Blocks), path)
+		return nil
+	},
+}
+
+var snapshotLoadCmd = &cobra.Command{
+	Use:   "load [flags] <archive>",
+	Short: "Run reports against a snapshot archive",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadSessionCommandConfig(cmd)
+		if err != nil {
+			return err
+		}
+
+		validTypes := append([]string{"all"}, reportTypes...)
+		if !containsFold(validTypes, snapshotType) {
+			return fmt.Errorf("invalid report type: %s (valid options: %s)",
+				snapshotType, strings.Join(validTypes, ", "))
+		}
+		snapshotType = strings.ToLower(snapshotType)
+
+		validFormats := []string{"table", "json", "ccusage-json"}
+		if !containsFold(validFormats, snapshotFormat) {
+			return fmt.Errorf("invalid report format: %s (valid options: %s)",
+				snapshotFormat, strings.Join(validFormats, ", "))
+		}
+		snapshotFormat = strings.ToLower(snapshotFormat)
+
+		archive, err := snapshot.Read(args[0])
+		if err != nil {
+			return err
+		}
+
+		// Group calendar reports in the zone the archive was created in, so the numbers
+		// match the creator's. Archives made with the system zone use the local settings.
+		location := resolveLocation(cfg)
+		if name := archive.Metadata.Timezone; name != "" && name != "Local" {
+			if loc, err := time.LoadLocation(name); err == nil {
+				location = loc
+			}
+		}
+
+		blocks := archive.Blocks
+		if blocks == nil && len(archive.Entries) > 0 {
+			if archive.Metadata.SessionWindow > 0 {
+				cfg.Session.WindowDuration = archive.Metadata.SessionWindow
+			}
+			blocks = newSessionAnalyzer(cfg).TransformToBlocks(archive.Entries)
+		}
+
+		types := reportTypes
+		if snapshotType != "all" {
+			types = []string{snapshotType}
+		}
+
+		if snapshotFormat != "table" {
+			if len(types) == 1 {
+				return writeJSON(reportValue(types[0], snapshotFormat, archive.Entries, blocks, location))
+			}
+			reports := map[string]interface{}{"metadata": archive.Metadata}
+			for _, reportType := range types {
+				reports[reportType] = reportValue(reportType, snapshotFormat, archive.Entries, blocks, location)
+			}
+			return writeJSON(reports)
+		}
+
+		outputSnapshotMetadata(archive.Metadata, location)
+		for _, reportType := range types {
+			fmt.Printf("\n%s%s report\n\n", strings.ToUpper(reportType[:1]), reportType[1:])
+			if err := writeReport(reportType, "table", archive.Entries, blocks, location); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	snapshotCreateCmd.Flags().StringVarP(&snapshotFile, "file", "f", "", "archive path (default: claudecat-snapshot-<time>.tar.gz)")
+	snapshotCreateCmd.Flags().StringVar(&snapshotFrom, "from", "", "start date (YYYY-MM-DD or YYYY-MM-DD HH:MM:SS)")
+	snapshotCreateCmd.Flags().StringVar(&snapshotTo, "to", "", "end date (YYYY-MM-DD or YYYY-MM-DD HH:MM:SS)")
+
+	snapshotLoadCmd.Flags().StringVar(&snapshotType, "type", "all", "report to run (all, daily, monthly, session, blocks, trend)")
+	snapshotLoadCmd.Flags().StringVar(&snapshotFormat, "format", "table", "output format (table, json, ccusage-json)")
+
+	snapshotCmd.AddCommand(snapshotCreateCmd)
+	snapshotCmd.AddCommand(snapshotLoadCmd)
+	rootCmd.AddCommand(snapshotCmd)
+}
+
+// locationName returns the IANA name of loc. The system zone is resolved through $TZ or the
+// /etc/localtime link so archives are grouped the same way on other machines; when neither
+// names it, "Local" is returned.
+func locationName(loc *time.Location) string {
+	if loc.String() != "Local" {
+		return loc.String()
+	}
+	if name := strings.TrimPrefix(os.Getenv("TZ"), ":"); name != "" {
+		if _, err := time.LoadLocation(name); err == nil {
+			return name
+		}
+	}
+	if target, err := os.Readlink("/etc/localtime"); err == nil {
+		if i := strings.Index(target, "zoneinfo/"); i >= 0 {
+			name := target[i+len("zoneinfo/"):]
+			if _, err := time.LoadLocation(name); err == nil {
+				return name
+			}
+		}
+	}
+	return "Local"
+}
+
+// filterLimitMessages keeps limit messages within the optional [from, to) range
+func filterLimitMessages(limits []models.LimitMessage, from, to time.Time) []models.LimitMessage {
+	filtered := make([]models.LimitMessage, 0, len(limits))
+	for _, limit := range limits {
+		if !from.IsZero() && limit.Timestamp.Before(from) {
+			continue
+		}
+		if !to.IsZero() && !limit.Timestamp.Before(to) {
+			continue
+		}
+		filtered = append(filtered, limit)
+	}
+	return filtered
+}
+
+func outputSnapshotMetadata(metadata snapshot.Metadata, location *time.Location) {
+	fmt.Printf("Snapshot created %s by claudecat %s", metadata.CreatedAt.In(location).Format("2006-01-02 15:04:05 MST"), metadata.ClaudecatVersion)
+	if metadata.Host != "" {
+		fmt.Printf(" on %s", metadata.Host)
+	}
+	fmt.Println()
+	fmt.Printf("Data paths:     %s\n", strings.Join(metadata.DataPaths, ", "))
+	if metadata.From != nil || metadata.To != nil {
+		from, to := "start", "now"
+		if metadata.From != nil {
+			from = metadata.From.In(location).Format("2006-01-02 15:04:05")
+		}
+		if metadata.To != nil {
+			to = metadata.To.In(location).Format("2006-01-02 15:04:05")
+		}
+		fmt.Printf("Range:          %s to %s\n", from, to)
+	}
+	fmt.Printf("Timezone:       %s\n", location)
+	fmt.Printf("Cost mode:      %s (pricing: %s)\n", metadata.CostMode, metadata.PricingSource)
+	fmt.Printf("Session window: %s\n", metadata.SessionWindow)
+	fmt.Printf("Contents:       %s entries, %s session blocks, %s limit messages\n",
+		formatWithCommas(metadata.Entries), formatWithCommas(metadata.Blocks), formatWithCommas(metadata.Limits))
+}
@@ -0,0 +1,226 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/penwyp/claudecat/calculations"
+	"github.com/penwyp/claudecat/config"
+	"github.com/penwyp/claudecat/logging"
+	"github.com/penwyp/claudecat/models"
+	"github.com/penwyp/claudecat/output"
+	"github.com/penwyp/claudecat/pkg/claudecat"
+	"github.com/spf13/cobra"
+)
+
+// statuslineStateFile keeps the last status line in the cache directory between invocations
+const statuslineStateFile = "statusline.json"
+
+var (
+	statuslineFormat string
+	statuslineColor  string
+	statuslineMaxAge time.Duration
+	statuslineBell   bool
+	statuslineBellAt float64
+	statuslineClaude bool
+)
+
+var statuslineCmd = &cobra.Command{
+	Use:   "statusline [flags] [path...]",
+	Short: "Print a one-line session summary for tmux, starship or shell prompts",
+	Long: `Print the active session's token usage, cost and time until reset as one compact
+line, such as "45% · $3.21 · 2h13m", or "idle" when no session is active.
+
+Status bars run the command every few seconds, so the last status is kept in the cache
+directory and printed as is while younger than --max-age; only then are the logs read
+again, from the summary cache and skipping logs last written before the sessions the
+status needs. The time until reset is always current.
+
+--format is a Go text/template with the fields .Active, .Percent, .Tokens, .Limit,
+.Cost, .Reset and .ResetAt. --color colors the line green, yellow from 75% and red from
+90% of the token limit, with ANSI codes for prompts or tmux styles for status bars.
+--bell rings the terminal bell on stderr once per session when usage reaches --bell-at
+percent of the token limit.
+
+With --claude-code the command serves as Claude Code's statusline command: it reads the
+JSON context Claude Code writes to stdin and adds the model, working directory and
+conversation cost, as in "[Opus] myrepo · $0.12 · 45% · 2h13m". Templates can also use
+.Model, .Dir, .SessionCost, .LinesAdded and .LinesRemoved. To set it up, add this to
+~/.claude/settings.json:
+
+  "statusLine": {"type": "command", "command": "claudecat statusline --claude-code --color ansi"}
+
+Examples:
+  claudecat statusline
+  claudecat statusline --color tmux                     # In tmux: set -g status-right '#(claudecat statusline --color tmux)'
+  claudecat statusline --format '{{.Tokens}}/{{.Limit}} {{.Reset}}'
+  claudecat statusline --color ansi --bell --bell-at 80 # In a shell prompt`,
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadSessionCommandConfig(cmd)
+		if err != nil {
+			return err
+		}
+		if len(args) > 0 {
+			cfg.Data.Paths = args
+		}
+		if statuslineBellAt <= 0 {
+			return fmt.Errorf("--bell-at must be positive")
+		}
+
+		format := statuslineFormat
+		var claudeCode *output.ClaudeCodeStatus
+		if statuslineClaude {
+			if claudeCode, err = output.ParseClaudeCodeStatus(os.Stdin); err != nil {
+				return err
+			}
+			if format == "" {
+				format = output.DefaultClaudeCodeStatusLineFormat
+			}
+		}
+		formatter, err := output.NewStatusLineFormatter(format, strings.ToLower(statuslineColor))
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		statePath := filepath.Join(claudecat.CacheDir(cfg), statuslineStateFile)
+		state, changed, err := currentStatusline(cmd.Context(), cfg, statePath, now)
+		if err != nil {
+			return err
+		}
+
+		status := state.Status
+		status.ClaudeCode = claudeCode
+		if statuslineBell && status.Active && status.Percent() >= statuslineBellAt && state.BelledSession != status.SessionID {
+			fmt.Fprint(os.Stderr, "\a")
+			state.BelledSession = status.SessionID
+			changed = true
+		}
+		if changed {
+			if err := writeStatuslineState(statePath, state); err != nil {
+				logging.LogWarnf("Failed to save status line: %v", err)
+			}
+		}
+
+		line, err := formatter.Format(status, now)
+		if err != nil {
+			return err
+		}
+		fmt.Println(line)
+		return nil
+	},
+}
+
+func init() {
+	statuslineCmd.Flags().StringVar(&statuslineFormat, "format", "", "Go text/template of the line (default: percent, cost and time until reset)")
+	statuslineCmd.Flags().StringVar(&statuslineColor, "color", output.StatusColorNone, "color by token limit usage (none, ansi, tmux)")
+	statuslineCmd.Flags().DurationVar(&statuslineMaxAge, "max-age", 10*time.Second, "reuse the last status while younger than this")
+	statuslineCmd.Flags().BoolVar(&statuslineBell, "bell", false, "ring the terminal bell once per session when usage reaches --bell-at")
+	statuslineCmd.Flags().Float64Var(&statuslineBellAt, "bell-at", 90, "percentage of the token limit that rings the bell")
+	statuslineCmd.Flags().BoolVar(&statuslineClaude, "claude-code", false, "read Claude Code's statusline JSON context from stdin")
+
+	rootCmd.AddCommand(statuslineCmd)
+}
+
+// statuslineState is what the statusline command keeps between invocations
+type statuslineState struct {
+	Paths         []string          `json:"paths"` // Data paths the status was computed from
+	Status        output.StatusLine `json:"status"`
+	BelledSession string            `json:"belled_session,omitempty"` // Session the bell last rang for
+}
+
+// currentStatusline returns the saved status when it is recent enough, or loads a new one.
+// changed reports whether the state differs from the saved one.
+func currentStatusline(ctx context.Context, cfg *config.Config, statePath string, now time.Time) (state statuslineState, changed bool, err error) {
+	paths := claudecat.DataPaths(cfg)
+	if saved, ok := readStatuslineState(statePath); ok && slices.Equal(saved.Paths, paths) {
+		state = saved
+		fresh := now.Sub(saved.Status.GeneratedAt) < statuslineMaxAge
+		if fresh && (!saved.Status.Active || now.Before(saved.Status.ResetAt)) {
+			return state, false, nil
+		}
+	}
+
+	status, err := loadStatusLine(ctx, cfg, now)
+	if err != nil {
+		return state, false, err
+	}
+	state.Paths = paths
+	state.Status = status
+	return state, true, nil
+}
+
+// loadStatusLine reads the logs, through the summary cache, for the status of the active session
+func loadStatusLine(ctx context.Context, cfg *config.Config, now time.Time) (output.StatusLine, error) {
+	// Two windows group the active session like the monitor does; P90 limits need history
+	hoursBack := int(2 * cfg.Session.WindowDuration.Hours())
+	p90 := cfg.Subscription.CustomTokenLimit == 0 &&
+		(cfg.Subscription.TokenLimitP90 || strings.EqualFold(cfg.Subscription.Plan, models.PlanCustom))
+	if p90 {
+		hoursBack = 192
+	}
+
+	usage, err := claudecat.Load(ctx, cfg, claudecat.LoadOptions{HoursBack: hoursBack, UseCache: true, Quick: true})
+	if err != nil {
+		return output.StatusLine{}, err
+	}
+	analysis := claudecat.Analyze(cfg, usage)
+
+	tokenLimit := cfg.Subscription.CustomTokenLimit
+	if tokenLimit == 0 {
+		if p90 {
+			tokenLimit = calculations.NewP90Calculator().CalculateP90Limit(analysis.Blocks, false)
+		} else {
+			tokenLimit = calculations.NewPlanCatalogFromConfig(cfg.Subscription).Limits(cfg.Subscription.Plan).TokenLimit
+		}
+	}
+	return output.NewStatusLine(analysis.ActiveBlock(), tokenLimit, now), nil
+}
+
+// readStatuslineState reads the saved state; a missing or unreadable file reports false
+func readStatuslineState(path string) (statuslineState, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return statuslineState{}, false
+	}
+	var state statuslineState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return statuslineState{}, false
+	}
+	return state, true
+}
+
+// writeStatuslineState saves state, replacing the file atomically so concurrent status bars
+// never read a partial one
+func writeStatuslineState(path string, state statuslineState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to encode status line: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/penwyp/claudecat/fileio"
+	"github.com/penwyp/claudecat/logging"
+	"github.com/penwyp/claudecat/models"
+	"github.com/penwyp/claudecat/models/pricing"
+	"github.com/penwyp/claudecat/pkg/claudecat"
+	"github.com/spf13/cobra"
+)
+
+var (
+	tailModel     string
+	tailProject   string
+	tailMinTokens int
+	tailMinCost   float64
+	tailInterval  time.Duration
+	tailFromStart bool
+	tailOutput    string
+)
+
+var tailCmd = &cobra.Command{
+	Use:   "tail [flags] [path...]",
+	Short: "Follow new usage entries as they are written",
+	Long: `Follow the data path and print each new usage entry as it lands, like tail -f
+for Claude usage.
+
+Examples:
+  claudecat tail                        # Follow the default Claude data paths
+  claudecat tail --model opus           # Only Opus requests
+  claudecat tail --project api --min-cost 0.10
+  claudecat tail --output json | jq .   # JSON lines`,
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadSessionCommandConfig(cmd)
+		if err != nil {
+			return err
+		}
+		if len(args) > 0 {
+			cfg.Data.Paths = args
+		}
+
+		validOutputs := []string{"table", "json"}
+		if !containsFold(validOutputs, tailOutput) {
+			return fmt.Errorf("invalid output format: %s (valid options: %s)",
+				tailOutput, strings.Join(validOutputs, ", "))
+		}
+		tailOutput = strings.ToLower(tailOutput)
+
+		if tailInterval < 100*time.Millisecond {
+			return fmt.Errorf("interval too small: %v (minimum: 100ms)", tailInterval)
+		}
+
+		pricingProvider, err := pricing.CreatePricingProvider(&cfg.Data, claudecat.CacheDir(cfg))
+		if err != nil {
+			logging.LogErrorf("Failed to create pricing provider: %v", err)
+			pricingProvider = pricing.NewDefaultProvider()
+		}
+
+		paths := claudecat.DataPaths(cfg)
+		tailer := fileio.NewTailer(paths, fileio.TailOptions{
+			Interval:        tailInterval,
+			FromStart:       tailFromStart,
+			Mode:            claudecat.CostMode(cfg),
+			PricingProvider: pricingProvider,
+			Validator:       claudecat.EntryValidator(cfg),
+		})
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		if tailOutput == "table" {
+			fmt.Fprintf(os.Stderr, "Following %s (Ctrl+C to stop)\n", strings.Join(paths, ", "))
+			fmt.Printf("%-19s  %-28s  %8s  %8s  %10s  %9s  %s\n",
+				"TIME", "MODEL", "INPUT", "OUTPUT", "TOTAL", "COST", "PROJECT")
+		}
+
+		return tailer.Run(ctx, func(entry models.UsageEntry) {
+			if !matchesTailFilters(entry) {
+				return
+			}
+			printTailEntry(entry)
+		})
+	},
+}
+
+func init() {
+	tailCmd.Flags().StringVar(&tailModel, "model", "", "only show entries whose model contains this text")
+	tailCmd.Flags().StringVar(&tailProject, "project", "", "only show entries whose project contains this text")
+	tailCmd.Flags().IntVar(&tailMinTokens, "min-tokens", 0, "only show entries with at least this many tokens")
+	tailCmd.Flags().Float64Var(&tailMinCost, "min-cost", 0, "only show entries costing at least this much (USD)")
+	tailCmd.Flags().DurationVar(&tailInterval, "interval", time.Second, "poll interval")
+	tailCmd.Flags().BoolVar(&tailFromStart, "from-start", false, "print existing entries before following")
+	tailCmd.Flags().StringVarP(&tailOutput, "output", "o", "table", "output format (table, json)")
+
+	rootCmd.AddCommand(tailCmd)
+}
+
+// matchesTailFilters applies the tail filter flags to an entry
+func matchesTailFilters(entry models.UsageEntry) bool {
+	if tailModel != "" && !strings.Contains(strings.ToLower(entry.Model), strings.ToLower(tailModel)) {
+		return false
+	}
+	if tailProject != "" && !strings.Contains(strings.ToLower(entry.Project), strings.ToLower(tailProject)) {
+		return false
+	}
+	if entry.TotalTokens < tailMinTokens {
+		return false
+	}
+	if entry.CostUSD < tailMinCost {
+		return false
+	}
+	return true
+}
+
+func printTailEntry(entry models.UsageEntry) {
+	if tailOutput == "json" {
+		data, err := sonic.Marshal(entry)
+		if err != nil {
+			logging.LogWarnf("Failed to marshal entry: %v", err)
+			return
+		}
+		fmt.Println(string(data))
+		return
+	}
+
+	fmt.Printf("%-19s  %-28s  %8s  %8s  %10s  %9s  %s\n",
+		entry.Timestamp.Local().Format("2006-01-02 15:04:05"),
+		entry.Model,
+		formatWithCommas(entry.InputTokens),
+		formatWithCommas(entry.OutputTokens),
+		formatWithCommas(entry.TotalTokens),
+		fmt.Sprintf("$%.4f", entry.CostUSD),
+		entry.Project,
+	)
+}
@@ -0,0 +1,266 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/penwyp/claudecat/team"
+	"github.com/spf13/cobra"
+)
+
+var (
+	teamUser   string
+	teamHost   string
+	teamDir    string
+	teamFile   string
+	teamPush   bool
+	teamShared bool
+	teamFrom   string
+	teamTo     string
+	teamBy     string
+	teamOutput string
+)
+
+var teamCmd = &cobra.Command{
+	Use:   "team",
+	Short: "Combine usage from several machines into team reports",
+	Long: `Combine the usage of several people and machines into one team report.
+
+Everyone exports a snapshot of their daily usage, either into a shared directory or
+to the redis server configured as cache.redis_url, and the team report aggregates
+all snapshots found there with a breakdown per user. Plain JSON exports from
+'claudecat export --format json' can be dropped into the directory as well; their
+file name is taken as the user.
+
+Examples:
+  claudecat team snapshot --dir /shared/claude-usage     # Export to a shared directory
+  claudecat team snapshot --push                         # Export to the shared redis server
+  claudecat team report /shared/claude-usage             # Per-user report
+  claudecat team report --shared --by day                # Daily costs per user from redis
+  claudecat team report /shared/claude-usage -o json     # Machine-readable report`,
+}
+
+var teamSnapshotCmd = &cobra.Command{
+	Use:   "snapshot [flags] [path...]",
+	Short: "Export a snapshot of your daily usage",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadSessionCommandConfig(cmd)
+		if err != nil {
+			return err
+		}
+		if len(args) > 0 {
+			cfg.Data.Paths = args
+		}
+
+		name, host := teamUser, teamHost
+		if name == "" {
+			name = currentUserName()
+		}
+		if host == "" {
+			if host, err = os.Hostname(); err != nil {
+				return fmt.Errorf("failed to determine host name, set --host: %w", err)
+			}
+		}
+
+		entries, _ := loadAllUsageEntries(cfg, false, false)
+		snapshot := team.NewSnapshot(name, host, entries, time.Now())
+
+		if teamPush {
+			store, err := team.NewRedisStore(cfg.Cache.RedisURL, "")
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+			if err := store.Push(snapshot); err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "Pushed %d days of usage for %s@%s\n", len(snapshot.Days), name, host)
+		}
+
+		path := teamFile
+		if teamDir != "" {
+			path = filepath.Join(teamDir, name+"@"+host+".json")
+		}
+		switch {
+		case path != "" && path != "-":
+			if err := team.WriteFile(path, snapshot); err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "Wrote %d days of usage for %s@%s to %s\n", len(snapshot.Days), name, host, path)
+		case !teamPush:
+			return writeJSON(snapshot)
+		}
+		return nil
+	},
+}
+
+var teamReportCmd = &cobra.Command{
+	Use:   "report [flags] [dir...]",
+	Short: "Report the combined usage of all snapshots",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadSessionCommandConfig(cmd)
+		if err != nil {
+			return err
+		}
+
+		validOutputs := []string{"table", "json"}
+		if !containsFold(validOutputs, teamOutput) {
+			return fmt.Errorf("invalid output format: %s (valid options: %s)",
+				teamOutput, strings.Join(validOutputs, ", "))
+		}
+		teamOutput = strings.ToLower(teamOutput)
+
+		validGroups := []string{"user", "day"}
+		if !containsFold(validGroups, teamBy) {
+			return fmt.Errorf("invalid grouping: %s (valid options: %s)",
+				teamBy, strings.Join(validGroups, ", "))
+		}
+		teamBy = strings.ToLower(teamBy)
+
+		for _, date := range []string{teamFrom, teamTo} {
+			if _, err := time.Parse("2006-01-02", date); date != "" && err != nil {
+				return fmt.Errorf("invalid date %q (expected YYYY-MM-DD)", date)
+			}
+		}
+
+		if len(args) == 0 && !teamShared {
+			return fmt.Errorf("no snapshots to report: pass snapshot directories or --shared")
+		}
+
+		var snapshots []team.Snapshot
+		var problems []error
+		for _, dir := range args {
+			found, skipped, err := team.LoadDir(dir)
+			if err != nil {
+				return err
+			}
+			snapshots = append(snapshots, found...)
+			problems = append(problems, skipped...)
+		}
+		if teamShared {
+			store, err := team.NewRedisStore(cfg.Cache.RedisURL, "")
+			if err != nil {
+				return err
+			}
+			found, skipped, err := store.Snapshots()
+			store.Close()
+			if err != nil {
+				return err
+			}
+			snapshots = append(snapshots, found...)
+			problems = append(problems, skipped...)
+		}
+		for _, problem := range problems {
+			fmt.Fprintf(os.Stderr, "Skipping %v\n", problem)
+		}
+
+		report := team.Aggregate(snapshots, teamFrom, teamTo)
+		if teamOutput == "json" {
+			return writeJSON(report)
+		}
+		if teamBy == "day" {
+			outputTeamDays(report)
+		} else {
+			outputTeamUsers(report)
+		}
+		return nil
+	},
+}
+
+func init() {
+	teamSnapshotCmd.Flags().StringVar(&teamUser, "user", "", "user name recorded in the snapshot (default: the login name)")
+	teamSnapshotCmd.Flags().StringVar(&teamHost, "host", "", "machine name recorded in the snapshot (default: the host name)")
+	teamSnapshotCmd.Flags().StringVar(&teamDir, "dir", "", "write the snapshot to <dir>/<user>@<host>.json")
+	teamSnapshotCmd.Flags().StringVarP(&teamFile, "file", "f", "", "write the snapshot to this file instead of stdout")
+	teamSnapshotCmd.Flags().BoolVar(&teamPush, "push", false, "store the snapshot on the redis server set as cache.redis_url")
+
+	teamReportCmd.Flags().BoolVar(&teamShared, "shared", false, "include snapshots stored on the redis server set as cache.redis_url")
+	teamReportCmd.Flags().StringVar(&teamFrom, "from", "", "first UTC day to include (YYYY-MM-DD)")
+	teamReportCmd.Flags().StringVar(&teamTo, "to", "", "last UTC day to include (YYYY-MM-DD)")
+	teamReportCmd.Flags().StringVar(&teamBy, "by", "user", "table rows (user, day)")
+	teamReportCmd.Flags().StringVarP(&teamOutput, "output", "o", "table", "output format (table, json)")
+
+	teamCmd.AddCommand(teamSnapshotCmd)
+	teamCmd.AddCommand(teamReportCmd)
+	rootCmd.AddCommand(teamCmd)
+}
+
+// currentUserName returns the login name, falling back to $USER
+func currentUserName() string {
+	if current, err := user.Current(); err == nil && current.Username != "" {
+		// Windows reports DOMAIN\name
+		return current.Username[strings.LastIndex(current.Username, `\`)+1:]
+	}
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	return "unknown"
+}
+
+func outputTeamUsers(report team.Report) {
+	if len(report.Users) == 0 {
+		fmt.Println("No usage found.")
+		return
+	}
+
+	table := newTableFormatter([]string{"User", "Machines", "Active Days", "Last Day", "Total Tokens", "Cost (USD)", "Share", "Top Model"})
+	for _, u := range report.Users {
+		topModel := ""
+		if len(u.Models) > 0 {
+			topModel = u.Models[0].Model
+		}
+		table.addRow([]string{
+			u.User,
+			strings.Join(u.Hosts, ", "),
+			formatWithCommas(u.ActiveDays),
+			u.LastDay,
+			formatWithCommas(u.TotalTokens),
+			formatCost(u.CostUSD),
+			fmt.Sprintf("%.1f%%", u.CostShare),
+			topModel,
+		})
+	}
+
+	table.addSeparatorLine()
+	table.addRow([]string{"Total", "", formatWithCommas(len(report.Days)), "", formatWithCommas(report.TotalTokens), formatCost(report.CostUSD), "", ""})
+	fmt.Println(table.render())
+}
+
+func outputTeamDays(report team.Report) {
+	if len(report.Days) == 0 {
+		fmt.Println("No usage found.")
+		return
+	}
+
+	// One cost column per user, most expensive first
+	headers := []string{"Date", "Total Tokens", "Cost (USD)"}
+	for _, u := range report.Users {
+		headers = append(headers, u.User)
+	}
+	table := newTableFormatter(headers)
+
+	for _, day := range report.Days {
+		row := []string{day.Date, formatWithCommas(day.TotalTokens), formatCost(day.CostUSD)}
+		for _, u := range report.Users {
+			cost, ok := day.Users[u.User]
+			if !ok {
+				row = append(row, "-")
+				continue
+			}
+			row = append(row, formatCost(cost))
+		}
+		table.addRow(row)
+	}
+
+	table.addSeparatorLine()
+	totals := []string{"Total", formatWithCommas(report.TotalTokens), formatCost(report.CostUSD)}
+	for _, u := range report.Users {
+		totals = append(totals, formatCost(u.CostUSD))
+	}
+	table.addRow(totals)
+	fmt.Println(table.render())
+}
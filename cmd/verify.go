@@ -0,0 +1,392 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/penwyp/claudecat/billing"
+	"github.com/penwyp/claudecat/models"
+	"github.com/spf13/cobra"
+)
+
+var (
+	verifyFrom       string
+	verifyTo         string
+	verifyCcusageCmd string
+	verifyMode       string
+	verifyTolerance  float64
+	verifyOutput     string
+)
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify [flags] [path...]",
+	Short: "Cross-check daily totals against ccusage",
+	Long: `Run a time range through claudecat's loading pipeline and, if installed, through
+ccusage, then compare per-day token and cost totals.
+
+Days whose totals differ by more than the tolerance are highlighted together with the
+most likely cause: duplicate entries (deduplication), pricing differences (cost mode or
+pricing source), or entries attributed to a neighbouring day (timezone/window anchoring).
+
+Examples:
+  claudecat verify --from 2025-06-01 --to 2025-06-30
+  claudecat verify --ccusage-cmd "npx ccusage@latest"
+  claudecat verify --mode calculate --tolerance 0.5`,
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadSessionCommandConfig(cmd)
+		if err != nil {
+			return err
+		}
+		if len(args) > 0 {
+			cfg.Data.Paths = args
+		}
+
+		validOutputs := []string{"table", "json"}
+		if !containsFold(validOutputs, verifyOutput) {
+			return fmt.Errorf("invalid output format: %s (valid options: %s)",
+				verifyOutput, strings.Join(validOutputs, ", "))
+		}
+		verifyOutput = strings.ToLower(verifyOutput)
+
+		validModes := []string{"auto", "calculate", "display"}
+		if !containsFold(validModes, verifyMode) {
+			return fmt.Errorf("invalid mode: %s (valid options: %s)",
+				verifyMode, strings.Join(validModes, ", "))
+		}
+		verifyMode = strings.ToLower(verifyMode)
+		// Compute our side with the same cost mode ccusage is asked to use
+		cfg.Data.CostMode = verifyMode
+
+		from, to, err := parseTimeRange(verifyFrom, verifyTo)
+		if err != nil {
+			return err
+		}
+
+		// Exact timestamps and message IDs are needed, so the summary cache is bypassed
+		entries, _ := loadAllUsageEntries(cfg, false, false)
+		ours := aggregateDailyTotals(entries, from, to, cfg.Data.Deduplication)
+
+		report := verifyReport{Claudecat: ours}
+		theirs, err := runCcusageDaily(verifyCcusageCmd, verifyMode, from, to)
+		if err != nil {
+			report.CcusageError = err.Error()
+		} else {
+			report.Ccusage = theirs
+			report.Days = compareDailyTotals(ours, theirs, verifyTolerance)
+		}
+
+		if verifyOutput == "json" {
+			return writeJSON(report)
+		}
+		outputVerifyReport(report)
+		return nil
+	},
+}
+
+func init() {
+	verifyCmd.Flags().StringVar(&verifyFrom, "from", "", "start date (YYYY-MM-DD)")
+	verifyCmd.Flags().StringVar(&verifyTo, "to", "", "end date (YYYY-MM-DD)")
+	verifyCmd.Flags().StringVar(&verifyCcusageCmd, "ccusage-cmd", "ccusage", "command used to invoke ccusage")
+	verifyCmd.Flags().StringVar(&verifyMode, "mode", "auto", "cost mode used on both sides (auto, calculate, display)")
+	verifyCmd.Flags().Float64Var(&verifyTolerance, "tolerance", 1.0, "allowed difference in percent before a day is flagged")
+	verifyCmd.Flags().StringVarP(&verifyOutput, "output", "o", "table", "output format (table, json)")
+
+	rootCmd.AddCommand(verifyCmd)
+}
+
+// dailyTotals contains the totals for a single local calendar day
+type dailyTotals struct {
+	Date                string  `json:"date"`
+	InputTokens         int     `json:"input_tokens"`
+	OutputTokens        int     `json:"output_tokens"`
+	CacheCreationTokens int     `json:"cache_creation_tokens"`
+	CacheReadTokens     int     `json:"cache_read_tokens"`
+	TotalTokens         int     `json:"total_tokens"`
+	CostUSD             float64 `json:"cost_usd"`
+	Entries             int     `json:"entries,omitempty"`
+	DuplicateTokens     int     `json:"duplicate_tokens,omitempty"` // Tokens from repeated message+request IDs
+}
+
+// dayComparison is the verification result for a single day
+type dayComparison struct {
+	Date        string  `json:"date"`
+	OurTokens   int     `json:"claudecat_tokens"`
+	TheirTokens int     `json:"ccusage_tokens"`
+	TokenDiff   int     `json:"token_diff"`
+	OurCost     float64 `json:"claudecat_cost"`
+	TheirCost   float64 `json:"ccusage_cost"`
+	CostDiff    float64 `json:"cost_diff"`
+	Match       bool    `json:"match"`
+	LikelyCause string  `json:"likely_cause,omitempty"`
+}
+
+// verifyReport is the complete verification output
+type verifyReport struct {
+	Claudecat    []dailyTotals   `json:"claudecat"`
+	Ccusage      []dailyTotals   `json:"ccusage,omitempty"`
+	Days         []dayComparison `json:"days,omitempty"`
+	CcusageError string          `json:"ccusage_error,omitempty"`
+}
+
+// aggregateDailyTotals groups entries by local date within [from, to].
+// When deduplicated is false, tokens of repeated message+request IDs are tracked separately.
+func aggregateDailyTotals(entries []models.UsageEntry, from, to time.Time, deduplicated bool) []dailyTotals {
+	byDate := make(map[string]*dailyTotals)
+	seen := make(map[string]bool)
+
+	for _, entry := range entries {
+		local := entry.Timestamp.Local()
+		date := local.Format("2006-01-02")
+		if !from.IsZero() && date < from.Format("2006-01-02") {
+			continue
+		}
+		if !to.IsZero() && date > to.Format("2006-01-02") {
+			continue
+		}
+
+		day, exists := byDate[date]
+		if !exists {
+			day = &dailyTotals{Date: date}
+			byDate[date] = day
+		}
+
+		day.InputTokens += entry.InputTokens
+		day.OutputTokens += entry.OutputTokens
+		day.CacheCreationTokens += entry.CacheCreationTokens
+		day.CacheReadTokens += entry.CacheReadTokens
+		day.TotalTokens += entry.TotalTokens
+		day.CostUSD += entry.CostUSD
+		day.Entries++
+
+		if !deduplicated && entry.MessageID != "" && entry.RequestID != "" {
+			key := entry.MessageID + ":" + entry.RequestID
+			if seen[key] {
+				day.DuplicateTokens += entry.TotalTokens
+			}
+			seen[key] = true
+		}
+	}
+
+	result := make([]dailyTotals, 0, len(byDate))
+	for _, day := range byDate {
+		result = append(result, *day)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Date < result[j].Date
+	})
+	return result
+}
+
+// ccusageDailyReport mirrors the JSON output of `ccusage daily --json`
+type ccusageDailyReport struct {
+	Daily []struct {
+		Date                string  `json:"date"`
+		InputTokens         int     `json:"inputTokens"`
+		OutputTokens        int     `json:"outputTokens"`
+		CacheCreationTokens int     `json:"cacheCreationTokens"`
+		CacheReadTokens     int     `json:"cacheReadTokens"`
+		TotalTokens         int     `json:"totalTokens"`
+		TotalCost           float64 `json:"totalCost"`
+		Cost                float64 `json:"cost"` // Older ccusage releases
+	} `json:"daily"`
+}
+
+// runCcusageDaily runs ccusage for the given range and parses its daily totals
+func runCcusageDaily(command, mode string, from, to time.Time) ([]dailyTotals, error) {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("empty ccusage command")
+	}
+	if _, err := exec.LookPath(fields[0]); err != nil {
+		return nil, fmt.Errorf("ccusage not found (install with `npm install -g ccusage` or set --ccusage-cmd)")
+	}
+
+	args := append(fields[1:], "daily", "--json", "--mode", mode)
+	if !from.IsZero() {
+		args = append(args, "--since", from.Format("20060102"))
+	}
+	if !to.IsZero() {
+		args = append(args, "--until", to.Format("20060102"))
+	}
+
+	var stdout, stderr bytes.Buffer
+	ccusage := exec.Command(fields[0], args...)
+	ccusage.Stdout = &stdout
+	ccusage.Stderr = &stderr
+	if err := ccusage.Run(); err != nil {
+		return nil, fmt.Errorf("ccusage failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	var report ccusageDailyReport
+	if err := sonic.Unmarshal(stdout.Bytes(), &report); err != nil {
+		return nil, fmt.Errorf("failed to parse ccusage output: %w", err)
+	}
+
+	result := make([]dailyTotals, 0, len(report.Daily))
+	for _, day := range report.Daily {
+		cost := day.TotalCost
+		if cost == 0 {
+			cost = day.Cost
+		}
+		result = append(result, dailyTotals{
+			Date:                day.Date,
+			InputTokens:         day.InputTokens,
+			OutputTokens:        day.OutputTokens,
+			CacheCreationTokens: day.CacheCreationTokens,
+			CacheReadTokens:     day.CacheReadTokens,
+			TotalTokens:         day.TotalTokens,
+			CostUSD:             cost,
+		})
+	}
+	return result, nil
+}
+
+// compareDailyTotals diffs both sets of daily totals and attaches a likely cause to mismatches
+func compareDailyTotals(ours, theirs []dailyTotals, tolerancePercent float64) []dayComparison {
+	ourByDate := make(map[string]dailyTotals)
+	theirByDate := make(map[string]dailyTotals)
+	dateSet := make(map[string]bool)
+	for _, day := range ours {
+		ourByDate[day.Date] = day
+		dateSet[day.Date] = true
+	}
+	for _, day := range theirs {
+		theirByDate[day.Date] = day
+		dateSet[day.Date] = true
+	}
+
+	dates := make([]string, 0, len(dateSet))
+	for date := range dateSet {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+
+	comparisons := make([]dayComparison, 0, len(dates))
+	for _, date := range dates {
+		our, their := ourByDate[date], theirByDate[date]
+		comparison := dayComparison{
+			Date:        date,
+			OurTokens:   our.TotalTokens,
+			TheirTokens: their.TotalTokens,
+			TokenDiff:   our.TotalTokens - their.TotalTokens,
+			OurCost:     our.CostUSD,
+			TheirCost:   their.CostUSD,
+			CostDiff:    our.CostUSD - their.CostUSD,
+		}
+		comparison.Match = billing.WithinTolerance(float64(our.TotalTokens), float64(their.TotalTokens), tolerancePercent) &&
+			billing.WithinTolerance(our.CostUSD, their.CostUSD, tolerancePercent)
+		comparisons = append(comparisons, comparison)
+	}
+
+	for i := range comparisons {
+		if !comparisons[i].Match {
+			comparisons[i].LikelyCause = diagnoseMismatch(comparisons, i, ourByDate[comparisons[i].Date], tolerancePercent)
+		}
+	}
+
+	return comparisons
+}
+
+// diagnoseMismatch guesses why a day's totals differ
+func diagnoseMismatch(days []dayComparison, i int, our dailyTotals, tolerancePercent float64) string {
+	day := days[i]
+
+	// Removing duplicated message+request IDs brings the totals in line
+	if our.DuplicateTokens > 0 &&
+		billing.WithinTolerance(float64(day.OurTokens-our.DuplicateTokens), float64(day.TheirTokens), tolerancePercent) {
+		return "deduplication: ccusage drops repeated message/request IDs (try --deduplication)"
+	}
+
+	// Same tokens, different cost
+	if billing.WithinTolerance(float64(day.OurTokens), float64(day.TheirTokens), tolerancePercent) {
+		return "pricing: tokens match but cost differs (cost mode or pricing source)"
+	}
+
+	// Tokens moved to an adjacent day
+	for _, j := range []int{i - 1, i + 1} {
+		if j < 0 || j >= len(days) || days[j].Match {
+			continue
+		}
+		if billing.WithinTolerance(float64(day.TokenDiff), float64(-days[j].TokenDiff), 5) {
+			return "window anchoring: tokens attributed to a neighbouring day (timezone or day boundary)"
+		}
+	}
+
+	switch {
+	case day.TheirTokens == 0:
+		return "missing in ccusage: data path or date range differs"
+	case day.OurTokens == 0:
+		return "missing in claudecat: data path or date range differs"
+	}
+	return "unknown"
+}
+
+func outputVerifyReport(report verifyReport) {
+	if report.CcusageError != "" {
+		fmt.Printf("Skipping comparison: %s\n\n", report.CcusageError)
+		if len(report.Claudecat) == 0 {
+			fmt.Println("No data to display.")
+			return
+		}
+		table := newTableFormatter([]string{"Date", "Entries", "Total Tokens", "Cost (USD)"})
+		for _, day := range report.Claudecat {
+			table.addRow([]string{day.Date, formatWithCommas(day.Entries), formatWithCommas(day.TotalTokens), formatCost(day.CostUSD)})
+		}
+		fmt.Println(table.render())
+		return
+	}
+
+	if len(report.Days) == 0 {
+		fmt.Println("No data to compare.")
+		return
+	}
+
+	mismatches := 0
+	table := newTableFormatter([]string{"Date", "claudecat Tokens", "ccusage Tokens", "Diff", "claudecat Cost", "ccusage Cost", "Status"})
+	for _, day := range report.Days {
+		status := "OK"
+		if !day.Match {
+			status = "MISMATCH"
+			mismatches++
+		}
+		table.addRow([]string{
+			day.Date,
+			formatWithCommas(day.OurTokens),
+			formatWithCommas(day.TheirTokens),
+			formatSignedInt(day.TokenDiff),
+			formatCost(day.OurCost),
+			formatCost(day.TheirCost),
+			status,
+		})
+	}
+	fmt.Println(table.render())
+
+	if mismatches == 0 {
+		fmt.Printf("\nAll %d days match.\n", len(report.Days))
+		return
+	}
+
+	fmt.Printf("\n%d of %d days differ:\n", mismatches, len(report.Days))
+	for _, day := range report.Days {
+		if !day.Match {
+			fmt.Printf("  %s  %s\n", day.Date, day.LikelyCause)
+		}
+	}
+}
+
+// formatSignedInt formats n with thousands separators and an explicit sign
+func formatSignedInt(n int) string {
+	if n > 0 {
+		return "+" + formatWithCommas(n)
+	}
+	if n < 0 {
+		return "-" + formatWithCommas(-n)
+	}
+	return "0"
+}
@@ -1,8 +1,15 @@
 package config
 
 import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
 	"runtime"
+	"strings"
 	"time"
+
+	"github.com/penwyp/claudecat/timeutil"
 )
 
 // Config represents the complete application configuration
@@ -25,35 +32,122 @@ type Config struct {
 	// Limits
 	Limits LimitsConfig `yaml:"limits" json:"limits"`
 
+	// Session windows
+	Session SessionConfig `yaml:"session" json:"session"`
+
+	// Budgets
+	Budgets BudgetsConfig `yaml:"budgets" json:"budgets"`
+
+	// Historical trends
+	History HistoryConfig `yaml:"history" json:"history"`
+
+	// Event log
+	Events EventsConfig `yaml:"events" json:"events"`
+
+	// Usage anomaly detection
+	Anomalies AnomalyConfig `yaml:"anomalies" json:"anomalies"`
+
+	// Privacy
+	Privacy PrivacyConfig `yaml:"privacy" json:"privacy"`
+
+	// Usage digests
+	Digest DigestConfig `yaml:"digest" json:"digest"`
+
+	// Pipeline hooks
+	Hooks []HookConfig `yaml:"hooks" json:"hooks"`
+
 	// Cache
 	Cache CacheConfig `yaml:"cache" json:"cache"`
 
+	// Telemetry
+	Telemetry TelemetryConfig `yaml:"telemetry" json:"telemetry"`
+
+	// statsd / DogStatsD metrics
+	Statsd StatsdConfig `yaml:"statsd" json:"statsd"`
+
+	// InfluxDB line protocol metrics
+	Influx InfluxConfig `yaml:"influx" json:"influx"`
+
 	// Debug
 	Debug DebugConfig `yaml:"debug" json:"debug"`
 }
 
 // AppConfig contains general application settings
 type AppConfig struct {
-	Name     string `yaml:"name" json:"name"`
-	Version  string `yaml:"version" json:"version"`
-	LogLevel string `yaml:"log_level" json:"log_level"`
-	LogFile  string `yaml:"log_file" json:"log_file"`
-	Timezone string `yaml:"timezone" json:"timezone"`
-	Verbose  bool   `yaml:"verbose" json:"verbose"`
+	Name        string            `yaml:"name" json:"name"`
+	Version     string            `yaml:"version" json:"version"`
+	LogLevel    string            `yaml:"log_level" json:"log_level"`
+	LogFile     string            `yaml:"log_file" json:"log_file"`
+	LogFormat   string            `yaml:"log_format" json:"log_format"`     // text or json
+	LogModules  map[string]string `yaml:"log_modules" json:"log_modules"`   // Levels for single packages, e.g. cache: debug
+	LogRotation LogRotationConfig `yaml:"log_rotation" json:"log_rotation"` // Rotation of the log file
+	Timezone    string            `yaml:"timezone" json:"timezone"`
+	Verbose     bool              `yaml:"verbose" json:"verbose"`
+}
+
+// LogRotationConfig controls when the log file is rotated. Zero values disable that kind of rotation.
+type LogRotationConfig struct {
+	MaxSize    int64         `yaml:"max_size" json:"max_size"`       // Rotate before the file grows past this many bytes
+	Interval   time.Duration `yaml:"interval" json:"interval"`       // Rotate when a new interval starts, e.g. 24h for daily files
+	MaxBackups int           `yaml:"max_backups" json:"max_backups"` // Rotated files to keep; 0 keeps all
 }
 
 // DataConfig contains data source and processing settings
 type DataConfig struct {
-	Paths              []string           `yaml:"paths" json:"paths"`
-	AutoDiscover       bool               `yaml:"auto_discover" json:"auto_discover"`
-	WatchInterval      time.Duration      `yaml:"watch_interval" json:"watch_interval"`
-	MaxFileSize        int64              `yaml:"max_file_size" json:"max_file_size"`
-	CacheEnabled       bool               `yaml:"cache_enabled" json:"cache_enabled"`
-	CacheSize          int                `yaml:"cache_size" json:"cache_size"`
-	SummaryCache       SummaryCacheConfig `yaml:"summary_cache" json:"summary_cache"`
-	PricingSource      string             `yaml:"pricing_source" json:"pricing_source"`             // default, litellm
-	PricingOfflineMode bool               `yaml:"pricing_offline_mode" json:"pricing_offline_mode"` // Use cached pricing
-	Deduplication      bool               `yaml:"deduplication" json:"deduplication"`               // Enable deduplication
+	Paths              []string               `yaml:"paths" json:"paths"`
+	AutoDiscover       bool                   `yaml:"auto_discover" json:"auto_discover"`
+	WatchInterval      time.Duration          `yaml:"watch_interval" json:"watch_interval"`
+	MaxFileSize        int64                  `yaml:"max_file_size" json:"max_file_size"`
+	CacheEnabled       bool                   `yaml:"cache_enabled" json:"cache_enabled"`
+	CacheSize          int                    `yaml:"cache_size" json:"cache_size"`
+	SummaryCache       SummaryCacheConfig     `yaml:"summary_cache" json:"summary_cache"`
+	PricingSource      string                 `yaml:"pricing_source" json:"pricing_source"`             // default, litellm
+	PricingOfflineMode bool                   `yaml:"pricing_offline_mode" json:"pricing_offline_mode"` // Use cached pricing
+	PricingRefresh     time.Duration          `yaml:"pricing_refresh" json:"pricing_refresh"`           // How often remote pricing is re-fetched
+	Deduplication      bool                   `yaml:"deduplication" json:"deduplication"`               // Enable deduplication
+	DedupRetention     time.Duration          `yaml:"dedup_retention" json:"dedup_retention"`           // How long the persistent dedup index remembers entries
+	CostMode           string                 `yaml:"cost_mode" json:"cost_mode"`                       // auto, display, calculate
+	Validation         ValidationConfig       `yaml:"validation" json:"validation"`                     // Implausible entry detection
+	ModelAliases       []ModelAliasConfig     `yaml:"model_aliases" json:"model_aliases"`               // Maps unknown model identifiers to known models
+	CostAllocation     []AllocationRuleConfig `yaml:"cost_allocation" json:"cost_allocation"`           // Tags entries for chargeback by log path or project
+	Include            []string               `yaml:"include" json:"include"`                           // Path patterns of the logs and directories to read; empty reads all
+	Exclude            []string               `yaml:"exclude" json:"exclude"`                           // Path patterns of logs and directories discovery skips, even when included
+}
+
+// ModelAliasConfig maps model identifiers matching Pattern, where * matches any text, to
+// Model. Aliases are tried in order before the built-in normalization, so Bedrock ARNs,
+// Vertex AI IDs or new releases can be priced as a known model.
+type ModelAliasConfig struct {
+	Pattern string `yaml:"pattern" json:"pattern"`
+	Model   string `yaml:"model" json:"model"`
+}
+
+// AllocationRuleConfig tags entries for cost allocation, e.g. with a team or cost center.
+// Path is matched against the whole path of the log file, where * matches any text and a
+// leading ~/ is the home directory; Claude Code names each project directory after its
+// working directory with separators replaced by dashes. Project is a regular expression
+// matched against the project name. Rules are tried in order and the first match wins.
+type AllocationRuleConfig struct {
+	Path    string `yaml:"path" json:"path"`
+	Project string `yaml:"project" json:"project"`
+	Tag     string `yaml:"tag" json:"tag"`
+}
+
+// ValidationConfig contains settings for detecting entries with implausible token counts or costs
+type ValidationConfig struct {
+	Action         string            `yaml:"action" json:"action"`                   // flag, clamp, off
+	IncludeSuspect bool              `yaml:"include_suspect" json:"include_suspect"` // Keep flagged entries in metrics
+	Bounds         EntryBoundsConfig `yaml:"bounds" json:"bounds"`
+}
+
+// EntryBoundsConfig contains per-entry upper bounds (0 disables a bound; negative values are always flagged)
+type EntryBoundsConfig struct {
+	MaxInputTokens         int     `yaml:"max_input_tokens" json:"max_input_tokens"`
+	MaxOutputTokens        int     `yaml:"max_output_tokens" json:"max_output_tokens"`
+	MaxCacheCreationTokens int     `yaml:"max_cache_creation_tokens" json:"max_cache_creation_tokens"`
+	MaxCacheReadTokens     int     `yaml:"max_cache_read_tokens" json:"max_cache_read_tokens"`
+	MaxThinkingTokens      int     `yaml:"max_thinking_tokens" json:"max_thinking_tokens"`
+	MaxCostUSD             float64 `yaml:"max_cost_usd" json:"max_cost_usd"`
 }
 
 // SummaryCacheConfig contains file summary caching settings
@@ -68,6 +162,12 @@ type CacheConfig struct {
 	Dir         string `yaml:"dir" json:"dir"`                     // Cache directory path
 	MaxMemory   int64  `yaml:"max_memory" json:"max_memory"`       // L1 memory cache size
 	MaxDiskSize int64  `yaml:"max_disk_size" json:"max_disk_size"` // L2 disk cache size
+
+	// Summary cache backend: file (default), bbolt, sqlite or redis
+	Backend        string        `yaml:"backend" json:"backend"`
+	RedisURL       string        `yaml:"redis_url" json:"redis_url"`               // e.g. redis://localhost:6379/0
+	RedisKeyPrefix string        `yaml:"redis_key_prefix" json:"redis_key_prefix"` // Namespace for shared servers
+	RedisTTL       time.Duration `yaml:"redis_ttl" json:"redis_ttl"`               // Zero keeps summaries until invalidated
 }
 
 // UIConfig contains user interface settings
@@ -83,24 +183,228 @@ type UIConfig struct {
 	NoColor       bool          `yaml:"no_color" json:"no_color"`
 	ViewMode      string        `yaml:"view_mode" json:"view_mode"` // "dashboard" or "monitor"
 	Timezone      string        `yaml:"timezone" json:"timezone"`   // Timezone for display
+	Fields        []string      `yaml:"fields" json:"fields"`       // Sections of the live console, in display order
+
+	// In compact (background) mode, refreshes are suspended after this long without data
+	// file writes and resume on the next write. Zero never suspends.
+	IdleTimeout time.Duration `yaml:"idle_timeout" json:"idle_timeout"`
+
+	// Data refreshes run every MinRefreshInterval while the active session receives writes
+	// and back off to MaxRefreshInterval while idle. A zero minimum uses RefreshRate; a
+	// maximum not above the minimum keeps the interval fixed.
+	MinRefreshInterval time.Duration `yaml:"min_refresh_interval" json:"min_refresh_interval"`
+	MaxRefreshInterval time.Duration `yaml:"max_refresh_interval" json:"max_refresh_interval"`
+}
+
+// ConsoleFields are the sections the live console can show, in their default order
+var ConsoleFields = []string{
+	"cost", "tokens", "messages", "cache", "time", "models", "projects", "sources",
+	"burn_rate", "cost_rate", "trends", "predictions",
+}
+
+// DefaultConsoleFields are the sections the live console shows unless ui.fields is set
+var DefaultConsoleFields = []string{
+	"cost", "tokens", "messages", "time", "models", "projects", "sources",
+	"burn_rate", "cost_rate", "trends", "predictions",
 }
 
 // PerformanceConfig contains performance tuning settings
 type PerformanceConfig struct {
-	WorkerCount int           `yaml:"worker_count" json:"worker_count"`
-	BufferSize  int           `yaml:"buffer_size" json:"buffer_size"`
-	BatchSize   int           `yaml:"batch_size" json:"batch_size"`
-	MaxMemory   int64         `yaml:"max_memory" json:"max_memory"`
-	GCInterval  time.Duration `yaml:"gc_interval" json:"gc_interval"`
+	WorkerCount          int           `yaml:"worker_count" json:"worker_count"` // Most files loaded at once
+	BufferSize           int           `yaml:"buffer_size" json:"buffer_size"`
+	BatchSize            int           `yaml:"batch_size" json:"batch_size"`
+	MaxMemory            int64         `yaml:"max_memory" json:"max_memory"` // Memory budget in bytes for loading usage data; larger histories are streamed
+	GCInterval           time.Duration `yaml:"gc_interval" json:"gc_interval"`
+	ConcurrencyThreshold int           `yaml:"concurrency_threshold" json:"concurrency_threshold"` // Load files concurrently when there are more than this many
+	Scheduling           string        `yaml:"scheduling" json:"scheduling"`                       // largest_first or in_order
+	FixedWorkers         bool          `yaml:"fixed_workers" json:"fixed_workers"`                 // Always start worker_count workers instead of tuning to the file sizes
+	Retry                RetryConfig   `yaml:"retry" json:"retry"`                                 // Retries of failed data loads and startup
+}
+
+// RetryConfig controls how failed operations are retried with exponential backoff
+type RetryConfig struct {
+	MaxAttempts int           `yaml:"max_attempts" json:"max_attempts"` // Attempts including the first
+	BaseDelay   time.Duration `yaml:"base_delay" json:"base_delay"`     // Wait before the first retry, doubled for each further one
+	MaxDelay    time.Duration `yaml:"max_delay" json:"max_delay"`       // Longest wait between attempts
+	Jitter      float64       `yaml:"jitter" json:"jitter"`             // Fraction of each wait randomized, 0 to 1
+	MaxElapsed  time.Duration `yaml:"max_elapsed" json:"max_elapsed"`   // Give up once this much time has passed, 0 for no limit
 }
 
 // SubscriptionConfig contains subscription and limit settings
 type SubscriptionConfig struct {
-	Plan             string  `yaml:"plan" json:"plan"`
-	CustomTokenLimit int     `yaml:"custom_token_limit" json:"custom_token_limit"`
-	CustomCostLimit  float64 `yaml:"custom_cost_limit" json:"custom_cost_limit"`
+	Plan             string  `yaml:"plan" json:"plan"`                             // custom derives the token limit from past sessions
+	CustomTokenLimit int     `yaml:"custom_token_limit" json:"custom_token_limit"` // Overrides the plan token limit when > 0
+	CustomCostLimit  float64 `yaml:"custom_cost_limit" json:"custom_cost_limit"`   // Overrides the plan cost limit when > 0
+	TokenLimitP90    bool    `yaml:"token_limit_p90" json:"token_limit_p90"`       // Derive the token limit from the P90 of past sessions
 	WarnThreshold    float64 `yaml:"warn_threshold" json:"warn_threshold"`
 	AlertThreshold   float64 `yaml:"alert_threshold" json:"alert_threshold"`
+
+	// Costs in USD of the active session block that trigger an alert, for every plan
+	// without its own cost_alerts
+	CostAlerts []float64 `yaml:"cost_alerts" json:"cost_alerts"`
+
+	// Day of the month the subscription renews on. Monthly reports and budgets follow billing
+	// cycles starting on that day instead of calendar months; 0 keeps calendar months.
+	BillingDay int `yaml:"billing_day" json:"billing_day"`
+
+	Plans map[string]PlanLimitsConfig `yaml:"plans" json:"plans"` // Overrides built-in plan limits or defines new plans
+}
+
+// PlanLimitsConfig overrides the per-session limits of a plan. Zero values keep the built-in limit.
+type PlanLimitsConfig struct {
+	Name         string    `yaml:"name" json:"name"`
+	TokenLimit   int       `yaml:"token_limit" json:"token_limit"`
+	CostLimit    float64   `yaml:"cost_limit" json:"cost_limit"`
+	MessageLimit int       `yaml:"message_limit" json:"message_limit"`
+	CostAlerts   []float64 `yaml:"cost_alerts" json:"cost_alerts"` // Session block costs in USD that trigger alerts
+}
+
+// SessionConfig contains billing window settings
+type SessionConfig struct {
+	WindowDuration  time.Duration `yaml:"window_duration" json:"window_duration"`     // Length of a billing session window
+	LateWriteBuffer time.Duration `yaml:"late_write_buffer" json:"late_write_buffer"` // How long after a window ends its files are still watched for late writes
+	SplitBlocks     []string      `yaml:"split_blocks" json:"split_blocks"`           // Also split blocks in reports and exports at limit_reset and/or model_switch
+}
+
+// BudgetsConfig contains calendar-period spending budgets and the usage fractions that trigger alerts
+type BudgetsConfig struct {
+	Thresholds []float64      `yaml:"thresholds" json:"thresholds"` // Fractions of a budget that trigger alerts, e.g. 0.9
+	Items      []BudgetConfig `yaml:"items" json:"items"`
+}
+
+// BudgetConfig is a cost and/or token budget over a daily, weekly or monthly period
+type BudgetConfig struct {
+	Name    string  `yaml:"name" json:"name"`
+	Period  string  `yaml:"period" json:"period"`     // daily, weekly (from Monday), monthly
+	CostUSD float64 `yaml:"cost_usd" json:"cost_usd"` // 0 disables the cost budget
+	Tokens  int     `yaml:"tokens" json:"tokens"`     // 0 disables the token budget
+}
+
+// HistoryConfig contains settings for the local trend database
+type HistoryConfig struct {
+	Disabled         bool          `yaml:"disabled" json:"disabled"`                   // Don't record snapshots while monitoring
+	SnapshotInterval time.Duration `yaml:"snapshot_interval" json:"snapshot_interval"` // How often metrics are snapshotted
+	Retention        time.Duration `yaml:"retention" json:"retention"`                 // How long snapshots are kept; daily summaries are kept forever
+}
+
+// EventsConfig contains settings for the event log of session starts and ends, limit hits,
+// budget breaches and errors
+type EventsConfig struct {
+	Disabled bool `yaml:"disabled" json:"disabled"` // Don't record events while monitoring
+}
+
+// AnomalyConfig configures warnings about unusual usage spikes, such as a runaway agent or an
+// accidental large-context loop
+type AnomalyConfig struct {
+	Disabled bool          `yaml:"disabled" json:"disabled"`
+	Factor   float64       `yaml:"factor" json:"factor"` // Warn when the burn rate exceeds this multiple of the usual rate for the hour of day
+	Window   time.Duration `yaml:"window" json:"window"` // How far back the current burn rate is measured
+}
+
+// PrivacyConfig controls what conversation content claudecat keeps and shows
+type PrivacyConfig struct {
+	RedactContent bool `yaml:"redact_content" json:"redact_content"` // Drop prompt and response text from raw records, and don't show conversation titles or prompts
+}
+
+// DigestConfig configures usage summaries posted to a Slack or Discord webhook
+type DigestConfig struct {
+	WebhookURL string            `yaml:"webhook_url" json:"webhook_url"` // Incoming webhook URL; empty disables digests
+	Headers    map[string]string `yaml:"headers" json:"headers"`         // Extra request headers
+	Format     string            `yaml:"format" json:"format"`           // slack or discord; detected from the webhook URL when empty
+	Schedule   string            `yaml:"schedule" json:"schedule"`       // daily posts the previous day, session posts each session when it ends
+	Time       string            `yaml:"time" json:"time"`               // Time of day (HH:MM) the daily digest is posted
+	TopN       int               `yaml:"top_n" json:"top_n"`             // Models and projects listed in a digest
+}
+
+// HookConfig runs a command or loads a Go plugin on monitoring pipeline events
+type HookConfig struct {
+	Name    string        `yaml:"name" json:"name"`
+	Events  []string      `yaml:"events" json:"events"`   // Any of HookEvents; empty receives every event
+	Command string        `yaml:"command" json:"command"` // Run with the event as JSON on stdin
+	Args    []string      `yaml:"args" json:"args"`
+	Plugin  string        `yaml:"plugin" json:"plugin"`   // Go plugin (.so) exporting a Hook variable; alternative to command
+	Timeout time.Duration `yaml:"timeout" json:"timeout"` // How long a command may run per event; 10s when zero
+}
+
+// HookEvents are the pipeline events hooks can receive
+var HookEvents = []string{"entries_loaded", "block_created", "session_start", "session_end", "threshold_crossed"}
+
+// Digest schedules
+const (
+	DigestDaily   = "daily"
+	DigestSession = "session"
+)
+
+// Digest formats
+const (
+	DigestSlack   = "slack"
+	DigestDiscord = "discord"
+)
+
+// ResolvedFormat returns the configured format, or the one matching the webhook's host
+func (d DigestConfig) ResolvedFormat() (string, error) {
+	if d.Format != "" {
+		return d.Format, nil
+	}
+	parsed, err := url.Parse(d.WebhookURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid webhook_url: %w", err)
+	}
+	host := strings.ToLower(parsed.Hostname())
+	switch {
+	case host == "hooks.slack.com":
+		return DigestSlack, nil
+	case host == "discord.com" || host == "discordapp.com" || strings.HasSuffix(host, ".discord.com"):
+		return DigestDiscord, nil
+	}
+	return "", fmt.Errorf("cannot detect the format of webhook %s, set format to slack or discord", parsed.Host)
+}
+
+// TimeOfDay returns the daily posting time as an offset from midnight
+func (d DigestConfig) TimeOfDay() (time.Duration, error) {
+	t, err := time.Parse("15:04", d.Time)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q (expected HH:MM)", d.Time)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// TelemetryConfig configures the export of OpenTelemetry traces and metrics
+type TelemetryConfig struct {
+	Endpoint       string            `yaml:"endpoint" json:"endpoint"`               // OTLP/HTTP collector URL such as http://localhost:4318; empty disables telemetry
+	Headers        map[string]string `yaml:"headers" json:"headers"`                 // Extra headers sent to the collector, e.g. for authentication
+	ServiceName    string            `yaml:"service_name" json:"service_name"`       // service.name resource attribute
+	SampleRatio    float64           `yaml:"sample_ratio" json:"sample_ratio"`       // Fraction of traces recorded, 0-1
+	MetricInterval time.Duration     `yaml:"metric_interval" json:"metric_interval"` // How often metrics are exported
+}
+
+// Statsd flavors
+const (
+	StatsdFlavorDogStatsD = "dogstatsd" // Tags are sent as DogStatsD tags
+	StatsdFlavorPlain     = "statsd"    // Tags are appended to metric names
+)
+
+// StatsdConfig pushes usage metrics to a statsd or DogStatsD agent on every refresh
+type StatsdConfig struct {
+	Address string            `yaml:"address" json:"address"` // host:port of the agent such as localhost:8125; empty disables statsd
+	Prefix  string            `yaml:"prefix" json:"prefix"`   // Prepended to metric names
+	Tags    map[string]string `yaml:"tags" json:"tags"`       // Added to every metric, e.g. team: platform
+	Flavor  string            `yaml:"flavor" json:"flavor"`   // dogstatsd or statsd
+}
+
+// InfluxConfig writes usage entries and session metrics in InfluxDB line protocol, to an
+// InfluxDB server or appended to a file
+type InfluxConfig struct {
+	URL       string            `yaml:"url" json:"url"`               // InfluxDB server such as http://localhost:8086; empty with no file disables the sink
+	Token     string            `yaml:"token" json:"token"`           // API token
+	Org       string            `yaml:"org" json:"org"`               // InfluxDB 2 organization
+	Bucket    string            `yaml:"bucket" json:"bucket"`         // InfluxDB 2 bucket
+	Database  string            `yaml:"database" json:"database"`     // InfluxDB 1.x database, used when bucket is empty
+	File      string            `yaml:"file" json:"file"`             // File line protocol is appended to instead of a server
+	Prefix    string            `yaml:"prefix" json:"prefix"`         // Measurement prefix: <prefix>_usage and <prefix>_session
+	Tags      map[string]string `yaml:"tags" json:"tags"`             // Added to every point, e.g. host: nas
+	BatchSize int               `yaml:"batch_size" json:"batch_size"` // Points per request
+	Retry     RetryConfig       `yaml:"retry" json:"retry"`           // Retries of failed requests
 }
 
 // DebugConfig contains debugging and profiling settings
@@ -110,11 +414,14 @@ type DebugConfig struct {
 
 // LimitsConfig contains subscription limit settings
 type LimitsConfig struct {
-	Enabled       bool               `yaml:"enabled" json:"enabled"`
-	Notifications []NotificationType `yaml:"notifications" json:"notifications"`
-	WebhookURL    string             `yaml:"webhook_url" json:"webhook_url"`
-	EmailEnabled  bool               `yaml:"email_enabled" json:"email_enabled"`
-	EmailSMTP     SMTPConfig         `yaml:"email_smtp" json:"email_smtp"`
+	Enabled         bool               `yaml:"enabled" json:"enabled"`
+	Notifications   []NotificationType `yaml:"notifications" json:"notifications"`
+	WebhookURL      string             `yaml:"webhook_url" json:"webhook_url"`
+	WebhookHeaders  map[string]string  `yaml:"webhook_headers" json:"webhook_headers"`   // Extra request headers, e.g. Authorization
+	WebhookTemplate string             `yaml:"webhook_template" json:"webhook_template"` // Go text/template for the request body; JSON event when empty
+	WarnBefore      time.Duration      `yaml:"warn_before" json:"warn_before"`           // Notify when the burn rate reaches the token limit within this long
+	EmailEnabled    bool               `yaml:"email_enabled" json:"email_enabled"`
+	EmailSMTP       SMTPConfig         `yaml:"email_smtp" json:"email_smtp"`
 }
 
 // NotificationType represents the type of notification
@@ -127,6 +434,19 @@ const (
 	NotifyEmail   NotificationType = "email"
 )
 
+// Notifies reports whether limit notifications are enabled for the given notification type
+func (l LimitsConfig) Notifies(notification NotificationType) bool {
+	if !l.Enabled {
+		return false
+	}
+	for _, enabled := range l.Notifications {
+		if enabled == notification {
+			return true
+		}
+	}
+	return false
+}
+
 // SMTPConfig contains SMTP settings for email notifications
 type SMTPConfig struct {
 	Host     string `yaml:"host" json:"host"`
@@ -156,6 +476,24 @@ func ConfigPaths() []string {
 	}
 }
 
+// DefaultLogFile returns the default log file: claudecat.log in the platform's state
+// directory, so running claudecat doesn't leave a log in the working directory
+func DefaultLogFile() string {
+	var dir string
+	if state := os.Getenv("XDG_STATE_HOME"); state != "" && runtime.GOOS != "darwin" {
+		dir = filepath.Join(state, "claudecat")
+	} else if home, err := os.UserHomeDir(); err == nil {
+		if runtime.GOOS == "darwin" {
+			dir = filepath.Join(home, "Library", "Logs", "claudecat")
+		} else {
+			dir = filepath.Join(home, ".local", "state", "claudecat")
+		}
+	} else {
+		dir = filepath.Join(os.TempDir(), "claudecat")
+	}
+	return filepath.Join(dir, "claudecat.log")
+}
+
 // Version will be set at build time
 var Version = "dev"
 
@@ -163,10 +501,15 @@ var Version = "dev"
 func DefaultConfig() *Config {
 	return &Config{
 		App: AppConfig{
-			Name:     "claudecat",
-			Version:  Version,
-			LogLevel: "info",
-			LogFile:  "claudecat.log",
+			Name:      "claudecat",
+			Version:   Version,
+			LogLevel:  "info",
+			LogFile:   DefaultLogFile(),
+			LogFormat: "text",
+			LogRotation: LogRotationConfig{
+				MaxSize:    10 * 1024 * 1024, // 10MB
+				MaxBackups: 5,
+			},
 			Timezone: "Local",
 		},
 		Data: DataConfig{
@@ -180,9 +523,23 @@ func DefaultConfig() *Config {
 				MaxSize:    10 * 1024 * 1024, // 10MB for summary cache
 				MaxEntries: 1000,             // Maximum 1000 cached summaries
 			},
-			PricingSource:      "default", // Use hardcoded pricing by default
-			PricingOfflineMode: false,     // Don't use offline mode by default
-			Deduplication:      false,     // Deduplication disabled by default
+			PricingSource:      "default",           // Use hardcoded pricing by default
+			PricingOfflineMode: false,               // Don't use offline mode by default
+			PricingRefresh:     24 * time.Hour,      // Re-fetch remote pricing once a day
+			Deduplication:      false,               // Deduplication disabled by default
+			DedupRetention:     30 * 24 * time.Hour, // Dedup index forgets entries after 30 days
+			CostMode:           "auto",              // Prefer costUSD from logs, calculate when missing
+			Validation: ValidationConfig{
+				Action: "flag",
+				Bounds: EntryBoundsConfig{
+					MaxInputTokens:         2_000_000,
+					MaxOutputTokens:        1_000_000,
+					MaxCacheCreationTokens: 2_000_000,
+					MaxCacheReadTokens:     2_000_000,
+					MaxThinkingTokens:      1_000_000,
+					MaxCostUSD:             100,
+				},
+			},
 		},
 		UI: UIConfig{
 			Theme:         "dark",
@@ -193,6 +550,9 @@ func DefaultConfig() *Config {
 			TablePageSize: 20,
 			DateFormat:    "2006-01-02",
 			TimeFormat:    "15:04:05",
+			Fields:        append([]string(nil), DefaultConsoleFields...),
+
+			MaxRefreshInterval: 2 * time.Minute,
 		},
 		Performance: PerformanceConfig{
 			WorkerCount: runtime.NumCPU(),
@@ -200,6 +560,16 @@ func DefaultConfig() *Config {
 			BatchSize:   100,
 			MaxMemory:   500 * 1024 * 1024, // 500MB
 			GCInterval:  5 * time.Minute,
+
+			ConcurrencyThreshold: 10,
+			Scheduling:           "largest_first",
+			Retry: RetryConfig{
+				MaxAttempts: 3,
+				BaseDelay:   100 * time.Millisecond,
+				MaxDelay:    30 * time.Second,
+				Jitter:      0.1,
+				MaxElapsed:  time.Minute,
+			},
 		},
 		Subscription: SubscriptionConfig{
 			Plan:           "pro",
@@ -209,11 +579,53 @@ func DefaultConfig() *Config {
 		Limits: LimitsConfig{
 			Enabled:       true,
 			Notifications: []NotificationType{NotifyDesktop},
+			WarnBefore:    30 * time.Minute,
+		},
+		Session: SessionConfig{
+			WindowDuration:  5 * time.Hour,
+			LateWriteBuffer: 30 * time.Minute,
+		},
+		Budgets: BudgetsConfig{
+			Thresholds: []float64{0.75, 0.90, 1.00},
+		},
+		History: HistoryConfig{
+			SnapshotInterval: 5 * time.Minute,
+			Retention:        365 * 24 * time.Hour,
+		},
+		Anomalies: AnomalyConfig{
+			Factor: 3,
+			Window: 15 * time.Minute,
+		},
+		Digest: DigestConfig{
+			Schedule: DigestDaily,
+			Time:     "09:00",
+			TopN:     5,
 		},
 		Cache: CacheConfig{
 			Dir:         "~/.cache/claudecat",
 			MaxMemory:   200 * 1024 * 1024,  // 200MB
 			MaxDiskSize: 1024 * 1024 * 1024, // 1GB
+			Backend:     "file",
+		},
+		Telemetry: TelemetryConfig{
+			ServiceName:    "claudecat",
+			SampleRatio:    1.0,
+			MetricInterval: 30 * time.Second,
+		},
+		Statsd: StatsdConfig{
+			Prefix: "claudecat",
+			Flavor: StatsdFlavorDogStatsD,
+		},
+		Influx: InfluxConfig{
+			Prefix:    "claude",
+			BatchSize: 5000,
+			Retry: RetryConfig{
+				MaxAttempts: 5,
+				BaseDelay:   time.Second,
+				MaxDelay:    30 * time.Second,
+				Jitter:      0.1,
+				MaxElapsed:  2 * time.Minute,
+			},
 		},
 		Debug: DebugConfig{
 			Enabled: false,
@@ -253,3 +665,13 @@ func ProductionConfig() *Config {
 	cfg.Performance.GCInterval = 10 * time.Minute
 	return cfg
 }
+
+// Location returns the timezone used for calendar boundaries: the UI timezone, then the app
+// timezone, then the system zone. An invalid name returns the system zone and an error.
+func (c *Config) Location() (*time.Location, error) {
+	name := c.UI.Timezone
+	if name == "" {
+		name = c.App.Timezone
+	}
+	return timeutil.LoadLocation(name)
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"path/filepath"
 	"runtime"
 	"testing"
 	"time"
@@ -15,6 +16,7 @@ func TestDefaultConfig(t *testing.T) {
 	assert.Equal(t, "claudecat", cfg.App.Name)
 	assert.Equal(t, "info", cfg.App.LogLevel)
 	assert.Equal(t, "Local", cfg.App.Timezone)
+	assert.True(t, filepath.IsAbs(cfg.App.LogFile), "the log doesn't follow the working directory")
 
 	// Test Data config
 	assert.True(t, cfg.Data.AutoDiscover)
@@ -80,6 +82,15 @@ func TestProductionConfig(t *testing.T) {
 	assert.Equal(t, 10*time.Minute, cfg.Performance.GCInterval)
 }
 
+func TestDefaultLogFile(t *testing.T) {
+	if runtime.GOOS == "darwin" {
+		t.Skip("macOS keeps logs in ~/Library/Logs")
+	}
+	state := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", state)
+	assert.Equal(t, filepath.Join(state, "claudecat", "claudecat.log"), DefaultLogFile())
+}
+
 func TestConfigPaths(t *testing.T) {
 	paths := ConfigPaths()
 
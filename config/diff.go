@@ -0,0 +1,84 @@
+package config
+
+import (
+	"reflect"
+)
+
+// Changes records which settings differ between two configurations. The named settings can
+// be applied to a running monitor; Other covers everything that needs a restart.
+type Changes struct {
+	RefreshRate bool // UI refresh rate or adaptive refresh bounds
+	Plan        bool // Subscription plan or limit overrides
+	DataPaths   bool // Monitored data paths
+	LogLevel    bool // Application log level or per-module levels
+	Timezone    bool // Display timezone or time format
+	Fields      bool // Sections of the live console
+	CostMode    bool // How entry costs are determined
+	Other       bool // Any other setting
+}
+
+// Diff compares the configuration in use with a newly loaded one
+func Diff(old, new *Config) Changes {
+	changes := Changes{
+		RefreshRate: old.UI.RefreshRate != new.UI.RefreshRate ||
+			old.UI.MinRefreshInterval != new.UI.MinRefreshInterval ||
+			old.UI.MaxRefreshInterval != new.UI.MaxRefreshInterval,
+		Plan:      !reflect.DeepEqual(old.Subscription, new.Subscription),
+		DataPaths: !reflect.DeepEqual(old.Data.Paths, new.Data.Paths),
+		LogLevel:  old.App.LogLevel != new.App.LogLevel || !reflect.DeepEqual(old.App.LogModules, new.App.LogModules),
+		Timezone: old.UI.Timezone != new.UI.Timezone || old.App.Timezone != new.App.Timezone ||
+			old.UI.TimeFormat != new.UI.TimeFormat,
+		CostMode: old.Data.CostMode != new.Data.CostMode,
+		Fields:   !reflect.DeepEqual(old.UI.Fields, new.UI.Fields),
+	}
+
+	// Copy the live settings over so whatever still differs needs a restart
+	rest := *new
+	rest.UI.RefreshRate = old.UI.RefreshRate
+	rest.UI.MinRefreshInterval = old.UI.MinRefreshInterval
+	rest.UI.MaxRefreshInterval = old.UI.MaxRefreshInterval
+	rest.Subscription = old.Subscription
+	rest.Data.Paths = old.Data.Paths
+	rest.App.LogLevel = old.App.LogLevel
+	rest.App.LogModules = old.App.LogModules
+	rest.UI.Timezone = old.UI.Timezone
+	rest.App.Timezone = old.App.Timezone
+	rest.UI.TimeFormat = old.UI.TimeFormat
+	rest.Data.CostMode = old.Data.CostMode
+	rest.UI.Fields = old.UI.Fields
+	changes.Other = !reflect.DeepEqual(old, &rest)
+
+	return changes
+}
+
+// Any reports whether any setting changed
+func (c Changes) Any() bool {
+	return c.RefreshRate || c.Plan || c.DataPaths || c.LogLevel || c.Timezone || c.CostMode || c.Fields || c.Other
+}
+
+// Names lists the changed live settings for log messages
+func (c Changes) Names() []string {
+	var names []string
+	if c.RefreshRate {
+		names = append(names, "refresh rate")
+	}
+	if c.Plan {
+		names = append(names, "plan")
+	}
+	if c.DataPaths {
+		names = append(names, "data paths")
+	}
+	if c.LogLevel {
+		names = append(names, "log level")
+	}
+	if c.Timezone {
+		names = append(names, "timezone")
+	}
+	if c.CostMode {
+		names = append(names, "cost mode")
+	}
+	if c.Fields {
+		names = append(names, "console fields")
+	}
+	return names
+}
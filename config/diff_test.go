@@ -0,0 +1,57 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	t.Run("unchanged", func(t *testing.T) {
+		changes := Diff(DefaultConfig(), DefaultConfig())
+		assert.False(t, changes.Any())
+		assert.Empty(t, changes.Names())
+	})
+
+	t.Run("live settings", func(t *testing.T) {
+		old := DefaultConfig()
+		new := DefaultConfig()
+		new.UI.RefreshRate = 5 * time.Second
+		new.Subscription.CustomTokenLimit = 100000
+		new.Data.Paths = []string{"/data/claude"}
+		new.App.LogLevel = "debug"
+		new.App.LogModules = map[string]string{"cache": "debug"}
+		new.UI.TimeFormat = "12h"
+		new.Data.CostMode = "calculate"
+		new.UI.Fields = []string{"tokens", "burn_rate"}
+
+		changes := Diff(old, new)
+		assert.True(t, changes.Any())
+		assert.False(t, changes.Other)
+		assert.Equal(t, []string{"refresh rate", "plan", "data paths", "log level", "timezone", "cost mode", "console fields"}, changes.Names())
+	})
+
+	t.Run("refresh bounds", func(t *testing.T) {
+		old := DefaultConfig()
+		new := DefaultConfig()
+		new.UI.MaxRefreshInterval = 5 * time.Minute
+
+		changes := Diff(old, new)
+		assert.False(t, changes.Other)
+		assert.Equal(t, []string{"refresh rate"}, changes.Names())
+	})
+
+	t.Run("restart required", func(t *testing.T) {
+		old := DefaultConfig()
+		new := DefaultConfig()
+		new.Cache.Backend = "bbolt"
+		new.App.LogFormat = "json"
+		new.UI.Timezone = "Europe/Berlin"
+
+		changes := Diff(old, new)
+		assert.True(t, changes.Other)
+		assert.True(t, changes.Timezone)
+		assert.Equal(t, []string{"timezone"}, changes.Names())
+	})
+}
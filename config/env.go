@@ -0,0 +1,114 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// EnvPrefix prefixes the environment variables that override settings
+const EnvPrefix = "CLAUDECAT"
+
+// LegacyEnvPrefix is the prefix before claudecat was renamed. Its variables still apply,
+// below those with EnvPrefix, with a warning.
+const LegacyEnvPrefix = "CLAWCAT"
+
+// legacyEnvWarned is set once the use of LegacyEnvPrefix variables has been warned about
+var legacyEnvWarned atomic.Bool
+
+// legacyEnvOutput receives the warning about LegacyEnvPrefix variables
+var legacyEnvOutput io.Writer = os.Stderr
+
+// envAliases are short environment variable names, without the prefix, for the settings
+// most often overridden in containers and CI. The full name wins when both are set.
+var envAliases = map[string]string{
+	"PATHS":        "data.paths",
+	"PLAN":         "subscription.plan",
+	"REFRESH_RATE": "ui.refresh_rate",
+	"TIMEZONE":     "ui.timezone",
+	"LOG_LEVEL":    "app.log_level",
+	"DEBUG":        "debug.enabled",
+}
+
+// EnvVar returns the environment variable that overrides a setting, e.g. CLAUDECAT_UI_THEME
+// for ui.theme with the CLAUDECAT prefix
+func EnvVar(prefix, key string) string {
+	return prefix + "_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// EnvKeys lists the settings that can be set from the environment: every setting except
+// maps and lists of structured values, like subscription.plans or hooks, which only config
+// files can express. Lists are comma separated.
+func EnvKeys() []string {
+	var keys []string
+	for _, setting := range rawSettings(DefaultConfig()) {
+		if envSettable(reflect.TypeOf(setting.Value)) {
+			keys = append(keys, setting.Key)
+		}
+	}
+	return keys
+}
+
+// envSettable reports whether a setting of type t can be parsed from a single string
+func envSettable(t reflect.Type) bool {
+	if t == reflect.TypeOf(time.Duration(0)) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Slice:
+		return t.Elem().Kind() != reflect.Slice && envSettable(t.Elem())
+	}
+	return false
+}
+
+// values returns the settings set in the environment by key. Sources with EnvPrefix also
+// read the LegacyEnvPrefix variables of settings not set otherwise.
+func (e *EnvSource) values() map[string]string {
+	values := lookupEnv(e.prefix)
+	if e.prefix != EnvPrefix {
+		return values
+	}
+
+	var legacy []string
+	for key, value := range lookupEnv(LegacyEnvPrefix) {
+		if _, ok := values[key]; !ok {
+			values[key] = value
+			legacy = append(legacy, key)
+		}
+	}
+	if len(legacy) > 0 && legacyEnvWarned.CompareAndSwap(false, true) {
+		sort.Strings(legacy)
+		fmt.Fprintf(legacyEnvOutput, "Warning: %s_* environment variables are deprecated, use %s_* instead (setting %s)\n",
+			LegacyEnvPrefix, EnvPrefix, strings.Join(legacy, ", "))
+	}
+	return values
+}
+
+// lookupEnv returns the settings set by the environment variables with prefix, by key
+func lookupEnv(prefix string) map[string]string {
+	values := make(map[string]string)
+	for _, key := range EnvKeys() {
+		if value, ok := os.LookupEnv(EnvVar(prefix, key)); ok {
+			values[key] = value
+		}
+	}
+	for alias, key := range envAliases {
+		if _, ok := values[key]; ok {
+			continue
+		}
+		if value, ok := os.LookupEnv(prefix + "_" + alias); ok {
+			values[key] = value
+		}
+	}
+	return values
+}
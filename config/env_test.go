@@ -0,0 +1,117 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvVar(t *testing.T) {
+	assert.Equal(t, "CLAUDECAT_UI_REFRESH_RATE", EnvVar(EnvPrefix, "ui.refresh_rate"))
+	assert.Equal(t, "CLAUDECAT_APP_LOG_ROTATION_MAX_SIZE", EnvVar(EnvPrefix, "app.log_rotation.max_size"))
+}
+
+func TestEnvKeys(t *testing.T) {
+	keys := EnvKeys()
+	for _, key := range []string{"data.paths", "subscription.plan", "ui.refresh_rate", "ui.timezone", "app.log_level", "subscription.cost_alerts", "limits.notifications"} {
+		assert.Contains(t, keys, key)
+	}
+	for _, key := range []string{"subscription.plans", "hooks", "data.model_aliases", "app.log_modules"} {
+		assert.NotContains(t, keys, key, "maps and lists of structured values need a config file")
+	}
+}
+
+func TestEnvSource_Load(t *testing.T) {
+	t.Setenv("CLAUDECAT_DATA_PATHS", "/data/a,/data/b")
+	t.Setenv("CLAUDECAT_SUBSCRIPTION_COST_ALERTS", "0.5,0.9")
+	t.Setenv("CLAUDECAT_UI_COMPACT_MODE", "true")
+	t.Setenv("CLAUDECAT_PERFORMANCE_WORKER_COUNT", "3")
+	t.Setenv("CLAUDECAT_LIMITS_NOTIFICATIONS", "desktop,webhook")
+	t.Setenv("CLAUDECAT_PLAN", "max20")
+	t.Setenv("CLAUDECAT_LOG_LEVEL", "warn")
+	t.Setenv("CLAUDECAT_APP_LOG_LEVEL", "debug")
+
+	source := NewEnvSource(EnvPrefix)
+	cfg, err := source.Load()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/data/a", "/data/b"}, cfg.Data.Paths)
+	assert.Equal(t, []float64{0.5, 0.9}, cfg.Subscription.CostAlerts)
+	assert.True(t, cfg.UI.CompactMode)
+	assert.Equal(t, 3, cfg.Performance.WorkerCount)
+	assert.Equal(t, []NotificationType{NotifyDesktop, NotifyWebhook}, cfg.Limits.Notifications)
+	assert.Equal(t, "max20", cfg.Subscription.Plan, "short form")
+	assert.Equal(t, "debug", cfg.App.LogLevel, "the full name wins over the short form")
+
+	assert.Equal(t, []string{"app.log_level", "data.paths", "limits.notifications", "performance.worker_count",
+		"subscription.cost_alerts", "subscription.plan", "ui.compact_mode"}, source.ExplicitKeys())
+}
+
+func TestLoader_EnvPrecedence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "claudecat.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+app:
+  log_level: info
+subscription:
+  plan: max5
+ui:
+  refresh_rate: 5s
+data:
+  deduplication: true
+`), 0644))
+	t.Setenv("CLAUDECAT_PLAN", "pro")
+	t.Setenv("CLAUDECAT_DATA_DEDUPLICATION", "false")
+	t.Setenv("CLAUDECAT_APP_LOG_LEVEL", "warn")
+
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.String("log-level", "info", "")
+	require.NoError(t, flags.Parse([]string{"--log-level", "error"}))
+
+	loader := NewLoader()
+	loader.AddSource(NewFlagSource(flags))
+	loader.AddSource(NewEnvSource(EnvPrefix))
+	loader.AddSource(NewFileSource(path))
+	cfg, err := loader.LoadWithDefaults()
+	require.NoError(t, err)
+
+	assert.Equal(t, "pro", cfg.Subscription.Plan, "the environment overrides files")
+	assert.False(t, cfg.Data.Deduplication, "false in the environment overrides files")
+	assert.Equal(t, 5*time.Second, cfg.UI.RefreshRate, "files apply where the environment is unset")
+	assert.Equal(t, "error", cfg.App.LogLevel, "flags override the environment")
+}
+
+func TestEnvSource_LegacyPrefix(t *testing.T) {
+	var warning bytes.Buffer
+	legacyEnvOutput = &warning
+	legacyEnvWarned.Store(false)
+	t.Cleanup(func() { legacyEnvOutput = os.Stderr })
+
+	t.Setenv("CLAWCAT_DEBUG", "true")
+	t.Setenv("CLAWCAT_UI_THEME", "light")
+	t.Setenv("CLAWCAT_PLAN", "max5")
+	t.Setenv("CLAUDECAT_SUBSCRIPTION_PLAN", "max20")
+
+	source := NewEnvSource(EnvPrefix)
+	cfg, err := source.Load()
+	require.NoError(t, err)
+	assert.True(t, cfg.Debug.Enabled)
+	assert.Equal(t, "light", cfg.UI.Theme)
+	assert.Equal(t, "max20", cfg.Subscription.Plan, "CLAUDECAT_ wins over CLAWCAT_")
+	assert.Equal(t, "Warning: CLAWCAT_* environment variables are deprecated, use CLAUDECAT_* instead (setting debug.enabled, ui.theme)\n",
+		warning.String())
+
+	_, err = source.Load()
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(warning.String(), "Warning"), "warned once")
+
+	// Other prefixes don't fall back
+	cfg, err = NewEnvSource("OTHER").Load()
+	require.NoError(t, err)
+	assert.False(t, cfg.Debug.Enabled)
+}
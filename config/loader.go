@@ -7,6 +7,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/go-viper/mapstructure/v2"
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 )
@@ -77,7 +78,7 @@ func (l *Loader) Load() (*Config, error) {
 		if config == nil {
 			config = cfg
 		} else {
-			config = l.merger.Merge(config, cfg)
+			config = l.mergeSource(config, source, cfg)
 		}
 	}
 
@@ -112,7 +113,7 @@ func (l *Loader) LoadWithDefaults() (*Config, error) {
 			continue
 		}
 
-		config = l.merger.Merge(config, cfg)
+		config = l.mergeSource(config, source, cfg)
 	}
 
 	// Validate final configuration
@@ -125,6 +126,30 @@ func (l *Loader) LoadWithDefaults() (*Config, error) {
 	return config, nil
 }
 
+// ExplicitSource is a Source that knows which settings it sets. They override lower
+// priority sources even when set to zero values, like false or 0, which the merger skips.
+type ExplicitSource interface {
+	Source
+	ExplicitKeys() []string
+}
+
+// mergeSource merges the configuration loaded from source over config
+func (l *Loader) mergeSource(config *Config, source Source, cfg *Config) *Config {
+	merged := l.merger.Merge(config, cfg)
+	if explicit, ok := source.(ExplicitSource); ok {
+		for _, key := range explicit.ExplicitKeys() {
+			copySetting(merged, cfg, key)
+		}
+	}
+	return merged
+}
+
+// yamlKeys makes viper match keys against the yaml tags, so multi-word keys like
+// window_duration reach their fields in both config files and environment variables
+func yamlKeys(dc *mapstructure.DecoderConfig) {
+	dc.TagName = "yaml"
+}
+
 // FileSource loads configuration from a file
 type FileSource struct {
 	path   string
@@ -167,7 +192,7 @@ func (f *FileSource) Load() (*Config, error) {
 
 	// Check if file exists
 	if _, err := os.Stat(expandedPath); os.IsNotExist(err) {
-		return nil, fmt.Errorf("configuration file not found: %s", expandedPath)
+		return nil, fmt.Errorf("%w: %s", ErrConfigNotFound, expandedPath)
 	}
 
 	v := viper.New()
@@ -178,7 +203,7 @@ func (f *FileSource) Load() (*Config, error) {
 	}
 
 	var config Config
-	if err := v.Unmarshal(&config); err != nil {
+	if err := v.Unmarshal(&config, yamlKeys); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal config from %s: %w", expandedPath, err)
 	}
 
@@ -210,70 +235,27 @@ func (e *EnvSource) Priority() int {
 // Load loads configuration from environment variables
 func (e *EnvSource) Load() (*Config, error) {
 	v := viper.New()
-	v.SetEnvPrefix(e.prefix)
-	v.AutomaticEnv()
-
-	// Replace dots and dashes with underscores for env vars
-	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_", "-", "_"))
-
-	// Set all possible config keys to enable env var reading
-	e.setAllKeys(v)
+	for key, value := range e.values() {
+		v.Set(key, value)
+	}
 
 	var config Config
-	if err := v.Unmarshal(&config); err != nil {
+	if err := v.Unmarshal(&config, yamlKeys); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal config from environment: %w", err)
 	}
 
 	return &config, nil
 }
 
-// setAllKeys sets all possible configuration keys for environment variable reading
-func (e *EnvSource) setAllKeys(v *viper.Viper) {
-	// App config
-	v.SetDefault("app.name", "")
-	v.SetDefault("app.version", "")
-	v.SetDefault("app.log_level", "")
-	v.SetDefault("app.log_file", "")
-	v.SetDefault("app.timezone", "")
-
-	// Data config
-	v.SetDefault("data.paths", []string{})
-	v.SetDefault("data.auto_discover", false)
-	v.SetDefault("data.watch_interval", "")
-	v.SetDefault("data.max_file_size", 0)
-	v.SetDefault("data.cache_enabled", false)
-	v.SetDefault("data.cache_size", 0)
-
-	// UI config
-	v.SetDefault("ui.theme", "")
-	v.SetDefault("ui.refresh_rate", "")
-	v.SetDefault("ui.compact_mode", false)
-	v.SetDefault("ui.show_spinner", false)
-	v.SetDefault("ui.chart_height", 0)
-	v.SetDefault("ui.table_page_size", 0)
-	v.SetDefault("ui.date_format", "")
-	v.SetDefault("ui.time_format", "")
-
-	// Performance config
-	v.SetDefault("performance.worker_count", 0)
-	v.SetDefault("performance.buffer_size", 0)
-	v.SetDefault("performance.batch_size", 0)
-	v.SetDefault("performance.max_memory", 0)
-	v.SetDefault("performance.gc_interval", "")
-
-	// Subscription config
-	v.SetDefault("subscription.plan", "")
-	v.SetDefault("subscription.custom_token_limit", 0)
-	v.SetDefault("subscription.custom_cost_limit", 0.0)
-	v.SetDefault("subscription.warn_threshold", 0.0)
-	v.SetDefault("subscription.alert_threshold", 0.0)
-
-	// Debug config
-	v.SetDefault("debug.enabled", false)
-	v.SetDefault("debug.profile_cpu", false)
-	v.SetDefault("debug.profile_memory", false)
-	v.SetDefault("debug.trace_file", "")
-	v.SetDefault("debug.metrics_port", 0)
+// ExplicitKeys returns the settings set in the environment
+func (e *EnvSource) ExplicitKeys() []string {
+	values := e.values()
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
 }
 
 // FlagSource loads configuration from command-line flags
@@ -358,6 +340,21 @@ func (m *DefaultMerger) Merge(base, override *Config) *Config {
 	if override.App.LogFile != "" {
 		result.App.LogFile = override.App.LogFile
 	}
+	if override.App.LogFormat != "" {
+		result.App.LogFormat = override.App.LogFormat
+	}
+	if len(override.App.LogModules) > 0 {
+		result.App.LogModules = override.App.LogModules
+	}
+	if override.App.LogRotation.MaxSize > 0 {
+		result.App.LogRotation.MaxSize = override.App.LogRotation.MaxSize
+	}
+	if override.App.LogRotation.Interval > 0 {
+		result.App.LogRotation.Interval = override.App.LogRotation.Interval
+	}
+	if override.App.LogRotation.MaxBackups > 0 {
+		result.App.LogRotation.MaxBackups = override.App.LogRotation.MaxBackups
+	}
 	if override.App.Timezone != "" {
 		result.App.Timezone = override.App.Timezone
 	}
@@ -375,6 +372,37 @@ func (m *DefaultMerger) Merge(base, override *Config) *Config {
 	if override.Data.CacheSize > 0 {
 		result.Data.CacheSize = override.Data.CacheSize
 	}
+	if override.Data.Deduplication {
+		result.Data.Deduplication = true
+	}
+	if override.Data.PricingRefresh > 0 {
+		result.Data.PricingRefresh = override.Data.PricingRefresh
+	}
+	if override.Data.DedupRetention > 0 {
+		result.Data.DedupRetention = override.Data.DedupRetention
+	}
+	if override.Data.CostMode != "" {
+		result.Data.CostMode = override.Data.CostMode
+	}
+	if override.Data.Validation.Action != "" {
+		result.Data.Validation.Action = override.Data.Validation.Action
+	}
+	if override.Data.Validation.IncludeSuspect {
+		result.Data.Validation.IncludeSuspect = true
+	}
+	mergeEntryBounds(&result.Data.Validation.Bounds, override.Data.Validation.Bounds)
+	if len(override.Data.ModelAliases) > 0 {
+		result.Data.ModelAliases = override.Data.ModelAliases
+	}
+	if len(override.Data.CostAllocation) > 0 {
+		result.Data.CostAllocation = override.Data.CostAllocation
+	}
+	if len(override.Data.Include) > 0 {
+		result.Data.Include = override.Data.Include
+	}
+	if len(override.Data.Exclude) > 0 {
+		result.Data.Exclude = override.Data.Exclude
+	}
 
 	// Merge UI config
 	if override.UI.Theme != "" {
@@ -395,6 +423,18 @@ func (m *DefaultMerger) Merge(base, override *Config) *Config {
 	if override.UI.TimeFormat != "" {
 		result.UI.TimeFormat = override.UI.TimeFormat
 	}
+	if len(override.UI.Fields) > 0 {
+		result.UI.Fields = override.UI.Fields
+	}
+	if override.UI.IdleTimeout > 0 {
+		result.UI.IdleTimeout = override.UI.IdleTimeout
+	}
+	if override.UI.MinRefreshInterval > 0 {
+		result.UI.MinRefreshInterval = override.UI.MinRefreshInterval
+	}
+	if override.UI.MaxRefreshInterval > 0 {
+		result.UI.MaxRefreshInterval = override.UI.MaxRefreshInterval
+	}
 
 	// Merge Performance config
 	if override.Performance.WorkerCount > 0 {
@@ -412,6 +452,30 @@ func (m *DefaultMerger) Merge(base, override *Config) *Config {
 	if override.Performance.GCInterval > 0 {
 		result.Performance.GCInterval = override.Performance.GCInterval
 	}
+	if override.Performance.ConcurrencyThreshold > 0 {
+		result.Performance.ConcurrencyThreshold = override.Performance.ConcurrencyThreshold
+	}
+	if override.Performance.Scheduling != "" {
+		result.Performance.Scheduling = override.Performance.Scheduling
+	}
+	if override.Performance.FixedWorkers {
+		result.Performance.FixedWorkers = true
+	}
+	if override.Performance.Retry.MaxAttempts > 0 {
+		result.Performance.Retry.MaxAttempts = override.Performance.Retry.MaxAttempts
+	}
+	if override.Performance.Retry.BaseDelay > 0 {
+		result.Performance.Retry.BaseDelay = override.Performance.Retry.BaseDelay
+	}
+	if override.Performance.Retry.MaxDelay > 0 {
+		result.Performance.Retry.MaxDelay = override.Performance.Retry.MaxDelay
+	}
+	if override.Performance.Retry.Jitter > 0 {
+		result.Performance.Retry.Jitter = override.Performance.Retry.Jitter
+	}
+	if override.Performance.Retry.MaxElapsed > 0 {
+		result.Performance.Retry.MaxElapsed = override.Performance.Retry.MaxElapsed
+	}
 
 	// Merge Subscription config
 	if override.Subscription.Plan != "" {
@@ -423,15 +487,234 @@ func (m *DefaultMerger) Merge(base, override *Config) *Config {
 	if override.Subscription.CustomCostLimit > 0 {
 		result.Subscription.CustomCostLimit = override.Subscription.CustomCostLimit
 	}
+	if override.Subscription.TokenLimitP90 {
+		result.Subscription.TokenLimitP90 = true
+	}
 	if override.Subscription.WarnThreshold > 0 {
 		result.Subscription.WarnThreshold = override.Subscription.WarnThreshold
 	}
 	if override.Subscription.AlertThreshold > 0 {
 		result.Subscription.AlertThreshold = override.Subscription.AlertThreshold
 	}
+	if len(override.Subscription.CostAlerts) > 0 {
+		result.Subscription.CostAlerts = override.Subscription.CostAlerts
+	}
+	if override.Subscription.BillingDay > 0 {
+		result.Subscription.BillingDay = override.Subscription.BillingDay
+	}
+	if len(override.Subscription.Plans) > 0 {
+		result.Subscription.Plans = override.Subscription.Plans
+	}
+
+	// Merge Session config
+	if override.Session.WindowDuration > 0 {
+		result.Session.WindowDuration = override.Session.WindowDuration
+	}
+	if override.Session.LateWriteBuffer > 0 {
+		result.Session.LateWriteBuffer = override.Session.LateWriteBuffer
+	}
+	if len(override.Session.SplitBlocks) > 0 {
+		result.Session.SplitBlocks = override.Session.SplitBlocks
+	}
+
+	// Merge Limits config
+	if len(override.Limits.Notifications) > 0 {
+		result.Limits.Notifications = override.Limits.Notifications
+	}
+	if override.Limits.WebhookURL != "" {
+		result.Limits.WebhookURL = override.Limits.WebhookURL
+	}
+	if len(override.Limits.WebhookHeaders) > 0 {
+		result.Limits.WebhookHeaders = override.Limits.WebhookHeaders
+	}
+	if override.Limits.WebhookTemplate != "" {
+		result.Limits.WebhookTemplate = override.Limits.WebhookTemplate
+	}
+	if override.Limits.WarnBefore > 0 {
+		result.Limits.WarnBefore = override.Limits.WarnBefore
+	}
+
+	// Merge Budgets config
+	if len(override.Budgets.Thresholds) > 0 {
+		result.Budgets.Thresholds = override.Budgets.Thresholds
+	}
+	if len(override.Budgets.Items) > 0 {
+		result.Budgets.Items = override.Budgets.Items
+	}
+
+	// Merge Hooks config
+	if len(override.Hooks) > 0 {
+		result.Hooks = override.Hooks
+	}
+
+	// Merge History config
+	if override.History.Disabled {
+		result.History.Disabled = true
+	}
+	if override.History.SnapshotInterval > 0 {
+		result.History.SnapshotInterval = override.History.SnapshotInterval
+	}
+	if override.History.Retention > 0 {
+		result.History.Retention = override.History.Retention
+	}
+
+	// Merge Events config
+	if override.Events.Disabled {
+		result.Events.Disabled = true
+	}
+
+	// Merge Anomalies config
+	if override.Anomalies.Disabled {
+		result.Anomalies.Disabled = true
+	}
+	if override.Anomalies.Factor > 0 {
+		result.Anomalies.Factor = override.Anomalies.Factor
+	}
+	if override.Anomalies.Window > 0 {
+		result.Anomalies.Window = override.Anomalies.Window
+	}
+
+	// Merge Privacy config
+	if override.Privacy.RedactContent {
+		result.Privacy.RedactContent = true
+	}
+
+	// Merge Digest config
+	if override.Digest.WebhookURL != "" {
+		result.Digest.WebhookURL = override.Digest.WebhookURL
+	}
+	if len(override.Digest.Headers) > 0 {
+		result.Digest.Headers = override.Digest.Headers
+	}
+	if override.Digest.Format != "" {
+		result.Digest.Format = override.Digest.Format
+	}
+	if override.Digest.Schedule != "" {
+		result.Digest.Schedule = override.Digest.Schedule
+	}
+	if override.Digest.Time != "" {
+		result.Digest.Time = override.Digest.Time
+	}
+	if override.Digest.TopN > 0 {
+		result.Digest.TopN = override.Digest.TopN
+	}
+
+	// Merge Telemetry config
+	if override.Telemetry.Endpoint != "" {
+		result.Telemetry.Endpoint = override.Telemetry.Endpoint
+	}
+	if len(override.Telemetry.Headers) > 0 {
+		result.Telemetry.Headers = override.Telemetry.Headers
+	}
+	if override.Telemetry.ServiceName != "" {
+		result.Telemetry.ServiceName = override.Telemetry.ServiceName
+	}
+	if override.Telemetry.SampleRatio > 0 {
+		result.Telemetry.SampleRatio = override.Telemetry.SampleRatio
+	}
+	if override.Telemetry.MetricInterval > 0 {
+		result.Telemetry.MetricInterval = override.Telemetry.MetricInterval
+	}
+
+	// Merge Statsd config
+	if override.Statsd.Address != "" {
+		result.Statsd.Address = override.Statsd.Address
+	}
+	if override.Statsd.Prefix != "" {
+		result.Statsd.Prefix = override.Statsd.Prefix
+	}
+	if len(override.Statsd.Tags) > 0 {
+		result.Statsd.Tags = override.Statsd.Tags
+	}
+	if override.Statsd.Flavor != "" {
+		result.Statsd.Flavor = override.Statsd.Flavor
+	}
+
+	// Merge Influx config
+	if override.Influx.URL != "" {
+		result.Influx.URL = override.Influx.URL
+	}
+	if override.Influx.Token != "" {
+		result.Influx.Token = override.Influx.Token
+	}
+	if override.Influx.Org != "" {
+		result.Influx.Org = override.Influx.Org
+	}
+	if override.Influx.Bucket != "" {
+		result.Influx.Bucket = override.Influx.Bucket
+	}
+	if override.Influx.Database != "" {
+		result.Influx.Database = override.Influx.Database
+	}
+	if override.Influx.File != "" {
+		result.Influx.File = override.Influx.File
+	}
+	if override.Influx.Prefix != "" {
+		result.Influx.Prefix = override.Influx.Prefix
+	}
+	if len(override.Influx.Tags) > 0 {
+		result.Influx.Tags = override.Influx.Tags
+	}
+	if override.Influx.BatchSize > 0 {
+		result.Influx.BatchSize = override.Influx.BatchSize
+	}
+	if override.Influx.Retry.MaxAttempts > 0 {
+		result.Influx.Retry.MaxAttempts = override.Influx.Retry.MaxAttempts
+	}
+	if override.Influx.Retry.BaseDelay > 0 {
+		result.Influx.Retry.BaseDelay = override.Influx.Retry.BaseDelay
+	}
+	if override.Influx.Retry.MaxDelay > 0 {
+		result.Influx.Retry.MaxDelay = override.Influx.Retry.MaxDelay
+	}
+	if override.Influx.Retry.Jitter > 0 {
+		result.Influx.Retry.Jitter = override.Influx.Retry.Jitter
+	}
+	if override.Influx.Retry.MaxElapsed > 0 {
+		result.Influx.Retry.MaxElapsed = override.Influx.Retry.MaxElapsed
+	}
+
+	// Merge Cache config
+	if override.Cache.Dir != "" {
+		result.Cache.Dir = override.Cache.Dir
+	}
+	if override.Cache.Backend != "" {
+		result.Cache.Backend = override.Cache.Backend
+	}
+	if override.Cache.RedisURL != "" {
+		result.Cache.RedisURL = override.Cache.RedisURL
+	}
+	if override.Cache.RedisKeyPrefix != "" {
+		result.Cache.RedisKeyPrefix = override.Cache.RedisKeyPrefix
+	}
+	if override.Cache.RedisTTL > 0 {
+		result.Cache.RedisTTL = override.Cache.RedisTTL
+	}
 
 	// Merge Debug config (boolean fields always override)
 	result.Debug = override.Debug
 
 	return &result
 }
+
+// mergeEntryBounds overrides each bound that is set in override
+func mergeEntryBounds(result *EntryBoundsConfig, override EntryBoundsConfig) {
+	if override.MaxInputTokens > 0 {
+		result.MaxInputTokens = override.MaxInputTokens
+	}
+	if override.MaxOutputTokens > 0 {
+		result.MaxOutputTokens = override.MaxOutputTokens
+	}
+	if override.MaxCacheCreationTokens > 0 {
+		result.MaxCacheCreationTokens = override.MaxCacheCreationTokens
+	}
+	if override.MaxCacheReadTokens > 0 {
+		result.MaxCacheReadTokens = override.MaxCacheReadTokens
+	}
+	if override.MaxThinkingTokens > 0 {
+		result.MaxThinkingTokens = override.MaxThinkingTokens
+	}
+	if override.MaxCostUSD > 0 {
+		result.MaxCostUSD = override.MaxCostUSD
+	}
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileSource_LoadMultiWordKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "claudecat.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+data:
+  dedup_retention: 72h
+session:
+  window_duration: 6h
+subscription:
+  custom_token_limit: 123456
+budgets:
+  thresholds: [0.5, 1.0]
+  items:
+    - name: daily
+      period: daily
+      cost_usd: 12.5
+    - name: monthly
+      period: monthly
+      tokens: 1000000
+`), 0644))
+
+	cfg, err := NewFileSource(path).Load()
+	require.NoError(t, err)
+
+	assert.Equal(t, 72*time.Hour, cfg.Data.DedupRetention)
+	assert.Equal(t, 6*time.Hour, cfg.Session.WindowDuration)
+	assert.Equal(t, 123456, cfg.Subscription.CustomTokenLimit)
+	assert.Equal(t, []float64{0.5, 1.0}, cfg.Budgets.Thresholds)
+	assert.Equal(t, []BudgetConfig{
+		{Name: "daily", Period: "daily", CostUSD: 12.5},
+		{Name: "monthly", Period: "monthly", Tokens: 1000000},
+	}, cfg.Budgets.Items)
+}
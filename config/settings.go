@@ -0,0 +1,183 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/spf13/viper"
+)
+
+// SourceDefault is the source of settings no configuration source changed
+const SourceDefault = "default"
+
+// ErrConfigNotFound is returned by a FileSource whose file does not exist
+var ErrConfigNotFound = errors.New("configuration file not found")
+
+// Setting is a single value of the configuration and the source it came from
+type Setting struct {
+	Key    string      `json:"key"`    // Dotted yaml path, e.g. subscription.plan
+	Value  interface{} `json:"value"`  // Durations are rendered as strings, e.g. 10s
+	Source string      `json:"source"` // Name of the source that set it, or SourceDefault
+}
+
+// Settings flattens a configuration into its values in declaration order. Lists and maps,
+// like data.paths or subscription.plans, are single settings.
+func Settings(cfg *Config) []Setting {
+	settings := rawSettings(cfg)
+	for i := range settings {
+		settings[i].Value = displayValue(settings[i].Value)
+	}
+	return settings
+}
+
+// rawSettings flattens a configuration keeping the values' own types
+func rawSettings(cfg *Config) []Setting {
+	var settings []Setting
+	flattenSettings(reflect.ValueOf(*cfg), "", &settings)
+	return settings
+}
+
+func flattenSettings(v reflect.Value, prefix string, settings *[]Setting) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "" || name == "-" || !field.IsExported() {
+			continue
+		}
+		key := prefix + name
+
+		value := v.Field(i)
+		if value.Kind() == reflect.Struct && value.Type() != reflect.TypeOf(time.Time{}) {
+			flattenSettings(value, key+".", settings)
+			continue
+		}
+		*settings = append(*settings, Setting{Key: key, Value: value.Interface(), Source: SourceDefault})
+	}
+}
+
+// settingField finds the field of a setting by its dotted yaml key
+func settingField(v reflect.Value, key string) (reflect.Value, bool) {
+	for _, name := range strings.Split(key, ".") {
+		if v.Kind() != reflect.Struct {
+			return reflect.Value{}, false
+		}
+		found := false
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			if tag, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ","); tag == name {
+				v, found = v.Field(i), true
+				break
+			}
+		}
+		if !found {
+			return reflect.Value{}, false
+		}
+	}
+	return v, true
+}
+
+// copySetting sets a setting of dst to its value in src
+func copySetting(dst, src *Config, key string) {
+	to, ok := settingField(reflect.ValueOf(dst).Elem(), key)
+	if !ok {
+		return
+	}
+	from, _ := settingField(reflect.ValueOf(src).Elem(), key)
+	to.Set(from)
+}
+
+// displayValue renders durations as strings
+func displayValue(value interface{}) interface{} {
+	if d, ok := value.(time.Duration); ok {
+		return d.String()
+	}
+	return value
+}
+
+// Attribute marks the settings whose value in cfg differs from the recorded one as set by
+// source, and records the new values. It tracks changes made to a configuration after it
+// was loaded, such as command line flags applied on top.
+func Attribute(settings []Setting, cfg *Config, source string) {
+	current := Settings(cfg)
+	for i := range settings {
+		if !reflect.DeepEqual(settings[i].Value, current[i].Value) {
+			settings[i].Value = current[i].Value
+			settings[i].Source = source
+		}
+	}
+}
+
+// LoadWithSources loads the configuration like LoadWithDefaults, and records for each setting
+// the last source, in priority order, that set it. Missing config files are skipped, but
+// sources that fail to load are reported instead of ignored, and the validators don't run.
+func (l *Loader) LoadWithSources() (*Config, []Setting, error) {
+	sort.Slice(l.sources, func(i, j int) bool {
+		return l.sources[i].Priority() < l.sources[j].Priority()
+	})
+
+	config := DefaultConfig()
+	settings := rawSettings(config)
+	var errs []error
+	for _, source := range l.sources {
+		cfg, err := source.Load()
+		if errors.Is(err, ErrConfigNotFound) {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", source.Name(), err))
+			continue
+		}
+
+		config = l.mergeSource(config, source, cfg)
+		// A source is credited with what it changed, and with values it sets that match
+		// what was there already
+		own := rawSettings(cfg)
+		merged := rawSettings(config)
+		for i := range settings {
+			changed := !reflect.DeepEqual(settings[i].Value, merged[i].Value)
+			restated := !reflect.ValueOf(own[i].Value).IsZero() && reflect.DeepEqual(own[i].Value, merged[i].Value)
+			if changed || restated {
+				settings[i].Source = source.Name()
+			}
+			settings[i].Value = merged[i].Value
+		}
+	}
+
+	for i := range settings {
+		settings[i].Value = displayValue(settings[i].Value)
+	}
+	return config, settings, errors.Join(errs...)
+}
+
+// CheckFile reads a config file strictly: besides syntax errors and values that don't fit
+// their setting, such as invalid durations, it reports keys that are not settings, which
+// are usually typos and otherwise silently ignored.
+func CheckFile(path string) (*Config, error) {
+	expandedPath := os.ExpandEnv(path)
+	if _, err := os.Stat(expandedPath); os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: %s", ErrConfigNotFound, expandedPath)
+	}
+
+	v := viper.New()
+	v.SetConfigFile(expandedPath)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %w", expandedPath, err)
+	}
+
+	var config Config
+	strict := func(dc *mapstructure.DecoderConfig) {
+		yamlKeys(dc)
+		dc.ErrorUnused = true
+	}
+	if err := v.Unmarshal(&config, strict); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", expandedPath, err)
+	}
+	return &config, nil
+}
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// settingByKey finds a setting, failing the test when it is missing
+func settingByKey(t *testing.T, settings []Setting, key string) Setting {
+	t.Helper()
+	for _, setting := range settings {
+		if setting.Key == key {
+			return setting
+		}
+	}
+	t.Fatalf("setting %s not found", key)
+	return Setting{}
+}
+
+func TestLoader_LoadWithSources(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "claudecat.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+subscription:
+  plan: max5
+ui:
+  refresh_rate: 5s
+  theme: dark
+`), 0644))
+	t.Setenv("CLAUDECAT_UI_REFRESH_RATE", "3s")
+
+	loader := NewLoader()
+	loader.AddSource(NewFileSource(path))
+	loader.AddSource(NewFileSource(filepath.Join(t.TempDir(), "missing.yaml")))
+	loader.AddSource(NewEnvSource("CLAUDECAT"))
+	cfg, settings, err := loader.LoadWithSources()
+	require.NoError(t, err)
+
+	assert.Equal(t, "max5", cfg.Subscription.Plan)
+	assert.Equal(t, 3*time.Second, cfg.UI.RefreshRate)
+	assert.Equal(t, Setting{Key: "subscription.plan", Value: "max5", Source: "file:" + path}, settingByKey(t, settings, "subscription.plan"))
+	assert.Equal(t, Setting{Key: "ui.refresh_rate", Value: "3s", Source: "env:CLAUDECAT"}, settingByKey(t, settings, "ui.refresh_rate"))
+	assert.Equal(t, "file:"+path, settingByKey(t, settings, "ui.theme").Source, "restating the default still counts")
+	assert.Equal(t, Setting{Key: "app.log_level", Value: "info", Source: SourceDefault}, settingByKey(t, settings, "app.log_level"))
+
+	cfg.Subscription.Plan = "pro"
+	Attribute(settings, cfg, "flags")
+	assert.Equal(t, Setting{Key: "subscription.plan", Value: "pro", Source: "flags"}, settingByKey(t, settings, "subscription.plan"))
+	assert.Equal(t, "env:CLAUDECAT", settingByKey(t, settings, "ui.refresh_rate").Source)
+}
+
+func TestLoader_LoadWithSourcesReportsBrokenSources(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "claudecat.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("ui:\n  refresh_rate: soon\n"), 0644))
+
+	loader := NewLoader()
+	loader.AddSource(NewFileSource(path))
+	cfg, _, err := loader.LoadWithSources()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "file:"+path)
+	assert.Equal(t, DefaultConfig().UI.RefreshRate, cfg.UI.RefreshRate)
+}
+
+func TestCheckFile(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		return path
+	}
+
+	cfg, err := CheckFile(write("valid.yaml", "subscription:\n  plan: max5\nsession:\n  window_duration: 5h\n"))
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Hour, cfg.Session.WindowDuration)
+
+	_, err = CheckFile(write("typo.yaml", "subscription:\n  plna: max5\n"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "plna")
+
+	_, err = CheckFile(write("duration.yaml", "ui:\n  refresh_rate: 10z\n"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "ui.refresh_rate")
+
+	_, err = CheckFile(filepath.Join(dir, "missing.yaml"))
+	assert.ErrorIs(t, err, ErrConfigNotFound)
+}
+
+func TestEnvSource_LoadWithUnsetKeys(t *testing.T) {
+	t.Setenv("CLAUDECAT_SESSION_WINDOW_DURATION", "6h")
+
+	cfg, err := NewEnvSource("CLAUDECAT").Load()
+	require.NoError(t, err)
+	assert.Equal(t, 6*time.Hour, cfg.Session.WindowDuration)
+	assert.Zero(t, cfg.UI.RefreshRate)
+}
@@ -2,10 +2,14 @@ package config
 
 import (
 	"fmt"
+	"net"
+	"net/url"
 	"os"
-	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
+
+	"github.com/penwyp/claudecat/models"
 )
 
 // ValidationRule represents a single validation rule
@@ -65,6 +69,53 @@ func (v *StandardValidator) Validate(cfg *Config) error {
 		errors = append(errors, fmt.Sprintf("subscription: %v", err))
 	}
 
+	// Validate Session config
+	if err := v.validateSession(&cfg.Session); err != nil {
+		errors = append(errors, fmt.Sprintf("session: %v", err))
+	}
+
+	// Validate Limits config
+	if err := v.validateLimits(&cfg.Limits); err != nil {
+		errors = append(errors, fmt.Sprintf("limits: %v", err))
+	}
+
+	// Validate Budgets config
+	if err := v.validateBudgets(&cfg.Budgets); err != nil {
+		errors = append(errors, fmt.Sprintf("budgets: %v", err))
+	}
+
+	if err := v.validateHistory(&cfg.History); err != nil {
+		errors = append(errors, fmt.Sprintf("history: %v", err))
+	}
+
+	if err := v.validateAnomalies(&cfg.Anomalies); err != nil {
+		errors = append(errors, fmt.Sprintf("anomalies: %v", err))
+	}
+
+	if err := v.validateCache(&cfg.Cache); err != nil {
+		errors = append(errors, fmt.Sprintf("cache: %v", err))
+	}
+
+	if err := v.validateDigest(&cfg.Digest); err != nil {
+		errors = append(errors, fmt.Sprintf("digest: %v", err))
+	}
+
+	if err := v.validateHooks(cfg.Hooks); err != nil {
+		errors = append(errors, fmt.Sprintf("hooks: %v", err))
+	}
+
+	if err := v.validateTelemetry(&cfg.Telemetry); err != nil {
+		errors = append(errors, fmt.Sprintf("telemetry: %v", err))
+	}
+
+	if err := v.validateStatsd(&cfg.Statsd); err != nil {
+		errors = append(errors, fmt.Sprintf("statsd: %v", err))
+	}
+
+	if err := v.validateInflux(&cfg.Influx); err != nil {
+		errors = append(errors, fmt.Sprintf("influx: %v", err))
+	}
+
 	if len(errors) > 0 {
 		return fmt.Errorf("validation errors: %s", strings.Join(errors, "; "))
 	}
@@ -87,13 +138,24 @@ func (v *StandardValidator) validateApp(app *AppConfig) error {
 		errors = append(errors, fmt.Sprintf("log_level: %v", err))
 	}
 
-	// Validate log file path if specified
+	if app.LogFormat != "" {
+		if err := ValidateLogFormat(app.LogFormat); err != nil {
+			errors = append(errors, fmt.Sprintf("log_format: %v", err))
+		}
+	}
+	for module, level := range app.LogModules {
+		if err := ValidateLogLevel(level); err != nil {
+			errors = append(errors, fmt.Sprintf("log_modules.%s: %v", module, err))
+		}
+	}
+	if app.LogRotation.MaxSize < 0 || app.LogRotation.Interval < 0 || app.LogRotation.MaxBackups < 0 {
+		errors = append(errors, "log_rotation: max_size, interval and max_backups must be non-negative")
+	}
+
+	// Validate log file path if specified; its directory is created when the log is opened
 	if app.LogFile != "" {
-		dir := filepath.Dir(app.LogFile)
-		if dir != "." {
-			if _, err := os.Stat(dir); os.IsNotExist(err) {
-				errors = append(errors, fmt.Sprintf("log_file: directory does not exist: %s", dir))
-			}
+		if info, err := os.Stat(app.LogFile); err == nil && info.IsDir() {
+			errors = append(errors, fmt.Sprintf("log_file: is a directory: %s", app.LogFile))
 		}
 	}
 
@@ -143,6 +205,63 @@ func (v *StandardValidator) validateData(data *DataConfig) error {
 		errors = append(errors, "cache_size: must not exceed 10GB")
 	}
 
+	// Validate pricing refresh interval
+	if data.PricingRefresh < 0 {
+		errors = append(errors, "pricing_refresh: must be non-negative")
+	} else if data.PricingRefresh > 0 && data.PricingRefresh < time.Minute {
+		errors = append(errors, "pricing_refresh: must be at least 1 minute")
+	}
+
+	// Validate dedup retention
+	if data.DedupRetention < 0 {
+		errors = append(errors, "dedup_retention: must be non-negative")
+	}
+
+	// Validate cost mode
+	if data.CostMode != "" {
+		if err := ValidateCostMode(data.CostMode); err != nil {
+			errors = append(errors, fmt.Sprintf("cost_mode: %v", err))
+		}
+	}
+
+	// Validate model aliases
+	for i, alias := range data.ModelAliases {
+		if strings.TrimSpace(alias.Pattern) == "" || strings.TrimSpace(alias.Model) == "" {
+			errors = append(errors, fmt.Sprintf("model_aliases[%d]: pattern and model are required", i))
+		}
+	}
+
+	// Validate cost allocation rules
+	for i, rule := range data.CostAllocation {
+		if _, err := models.NewAllocationRule(rule.Path, rule.Project, rule.Tag); err != nil {
+			errors = append(errors, fmt.Sprintf("cost_allocation[%d]: %v", i, err))
+		}
+	}
+
+	// Validate discovery include and exclude patterns
+	for i, pattern := range data.Include {
+		if strings.TrimSpace(pattern) == "" {
+			errors = append(errors, fmt.Sprintf("include[%d]: empty pattern", i))
+		}
+	}
+	for i, pattern := range data.Exclude {
+		if strings.TrimSpace(pattern) == "" {
+			errors = append(errors, fmt.Sprintf("exclude[%d]: empty pattern", i))
+		}
+	}
+
+	// Validate entry validation settings
+	if data.Validation.Action != "" {
+		if err := ValidateAnomalyAction(data.Validation.Action); err != nil {
+			errors = append(errors, fmt.Sprintf("validation.action: %v", err))
+		}
+	}
+	bounds := data.Validation.Bounds
+	if bounds.MaxInputTokens < 0 || bounds.MaxOutputTokens < 0 || bounds.MaxCacheCreationTokens < 0 ||
+		bounds.MaxCacheReadTokens < 0 || bounds.MaxThinkingTokens < 0 || bounds.MaxCostUSD < 0 {
+		errors = append(errors, "validation.bounds: must be non-negative")
+	}
+
 	if len(errors) > 0 {
 		return fmt.Errorf("%s", strings.Join(errors, "; "))
 	}
@@ -166,6 +285,26 @@ func (v *StandardValidator) validateUI(ui *UIConfig) error {
 		errors = append(errors, "refresh_rate: must not exceed 1 minute")
 	}
 
+	// Validate adaptive refresh bounds
+	if ui.MinRefreshInterval < 0 {
+		errors = append(errors, "min_refresh_interval: must not be negative")
+	} else if ui.MinRefreshInterval > 0 && ui.MinRefreshInterval < 100*time.Millisecond {
+		errors = append(errors, "min_refresh_interval: must be at least 100ms")
+	}
+	if ui.MaxRefreshInterval < 0 {
+		errors = append(errors, "max_refresh_interval: must not be negative")
+	}
+	if ui.MaxRefreshInterval > time.Hour {
+		errors = append(errors, "max_refresh_interval: must not exceed 1 hour")
+	}
+
+	// Validate idle timeout
+	if ui.IdleTimeout < 0 {
+		errors = append(errors, "idle_timeout: must not be negative")
+	} else if ui.IdleTimeout > 0 && ui.IdleTimeout < time.Minute {
+		errors = append(errors, "idle_timeout: must be at least 1 minute, or 0 to never suspend")
+	}
+
 	// Validate chart height
 	if ui.ChartHeight < 5 {
 		errors = append(errors, "chart_height: must be at least 5")
@@ -196,6 +335,22 @@ func (v *StandardValidator) validateUI(ui *UIConfig) error {
 		}
 	}
 
+	// Validate console fields
+	validFields := make(map[string]bool, len(ConsoleFields))
+	for _, field := range ConsoleFields {
+		validFields[field] = true
+	}
+	seen := make(map[string]bool, len(ui.Fields))
+	for _, field := range ui.Fields {
+		switch {
+		case !validFields[field]:
+			errors = append(errors, fmt.Sprintf("fields: unknown field %q (valid: %s)", field, strings.Join(ConsoleFields, ", ")))
+		case seen[field]:
+			errors = append(errors, fmt.Sprintf("fields: %q listed more than once", field))
+		}
+		seen[field] = true
+	}
+
 	if len(errors) > 0 {
 		return fmt.Errorf("%s", strings.Join(errors, "; "))
 	}
@@ -246,6 +401,29 @@ func (v *StandardValidator) validatePerformance(perf *PerformanceConfig) error {
 		errors = append(errors, "gc_interval: must not exceed 1 hour")
 	}
 
+	if perf.ConcurrencyThreshold < 0 {
+		errors = append(errors, "concurrency_threshold: must be non-negative")
+	}
+	if perf.Scheduling != "" {
+		if err := ValidateScheduling(perf.Scheduling); err != nil {
+			errors = append(errors, fmt.Sprintf("scheduling: %v", err))
+		}
+	}
+
+	retry := perf.Retry
+	if retry.MaxAttempts < 0 || retry.MaxAttempts > 100 {
+		errors = append(errors, "retry.max_attempts: must be between 0 and 100")
+	}
+	if retry.BaseDelay < 0 || retry.MaxDelay < 0 || retry.MaxElapsed < 0 {
+		errors = append(errors, "retry: delays must be non-negative")
+	}
+	if retry.MaxDelay > 0 && retry.BaseDelay > retry.MaxDelay {
+		errors = append(errors, "retry.base_delay: must not exceed max_delay")
+	}
+	if retry.Jitter < 0 || retry.Jitter > 1 {
+		errors = append(errors, "retry.jitter: must be between 0 and 1")
+	}
+
 	if len(errors) > 0 {
 		return fmt.Errorf("%s", strings.Join(errors, "; "))
 	}
@@ -256,9 +434,22 @@ func (v *StandardValidator) validatePerformance(perf *PerformanceConfig) error {
 func (v *StandardValidator) validateSubscription(sub *SubscriptionConfig) error {
 	var errors []string
 
-	// Validate plan
-	if err := ValidatePlan(sub.Plan); err != nil {
-		errors = append(errors, fmt.Sprintf("plan: %v", err))
+	// Validate plan; plans defined under plans are valid too
+	if _, defined := sub.Plans[strings.ToLower(sub.Plan)]; !defined {
+		if err := ValidatePlan(sub.Plan); err != nil {
+			errors = append(errors, fmt.Sprintf("plan: %v", err))
+		}
+	}
+	for name, limits := range sub.Plans {
+		if limits.TokenLimit < 0 || limits.CostLimit < 0 || limits.MessageLimit < 0 {
+			errors = append(errors, fmt.Sprintf("plans.%s: limits must be non-negative", name))
+		}
+		if err := validateCostAlerts(limits.CostAlerts); err != nil {
+			errors = append(errors, fmt.Sprintf("plans.%s.cost_alerts: %v", name, err))
+		}
+	}
+	if err := validateCostAlerts(sub.CostAlerts); err != nil {
+		errors = append(errors, fmt.Sprintf("cost_alerts: %v", err))
 	}
 
 	// Validate custom limits
@@ -268,6 +459,12 @@ func (v *StandardValidator) validateSubscription(sub *SubscriptionConfig) error
 	if sub.CustomCostLimit < 0 {
 		errors = append(errors, "custom_cost_limit: must be non-negative")
 	}
+	if sub.CustomTokenLimit > 0 && sub.TokenLimitP90 {
+		errors = append(errors, "custom_token_limit: cannot be combined with token_limit_p90")
+	}
+	if sub.BillingDay < 0 || sub.BillingDay > 31 {
+		errors = append(errors, "billing_day: must be between 1 and 31, or 0 for calendar months")
+	}
 
 	// Validate thresholds
 	if sub.WarnThreshold < 0 || sub.WarnThreshold > 1 {
@@ -286,22 +483,360 @@ func (v *StandardValidator) validateSubscription(sub *SubscriptionConfig) error
 	return nil
 }
 
+// validateSession validates session window configuration
+func (v *StandardValidator) validateSession(session *SessionConfig) error {
+	var errors []string
+
+	// Blocks start on whole hours, so shorter windows would overlap
+	if session.WindowDuration < time.Hour {
+		errors = append(errors, "window_duration: must be at least 1h")
+	}
+	if session.WindowDuration > 7*24*time.Hour {
+		errors = append(errors, "window_duration: must not exceed 168h")
+	}
+	if session.LateWriteBuffer < 0 {
+		errors = append(errors, "late_write_buffer: must be non-negative")
+	}
+	for i, strategy := range session.SplitBlocks {
+		switch strings.ToLower(strings.TrimSpace(strategy)) {
+		case "limit_reset", "model_switch":
+		default:
+			errors = append(errors, fmt.Sprintf("split_blocks[%d]: invalid strategy %q (valid: limit_reset, model_switch)", i, strategy))
+		}
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("%s", strings.Join(errors, "; "))
+	}
+	return nil
+}
+
+// validateLimits validates limit notification settings
+func (v *StandardValidator) validateLimits(limits *LimitsConfig) error {
+	var errors []string
+
+	for _, notification := range limits.Notifications {
+		switch notification {
+		case NotifyDesktop, NotifySound, NotifyWebhook, NotifyEmail:
+		default:
+			errors = append(errors, fmt.Sprintf("notifications: invalid type %q (valid: desktop, sound, webhook, email)", notification))
+		}
+	}
+	if limits.Notifies(NotifyWebhook) && limits.WebhookURL == "" {
+		errors = append(errors, "webhook_url: required for webhook notifications")
+	}
+	if limits.WarnBefore < 0 {
+		errors = append(errors, "warn_before: must be non-negative")
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("%s", strings.Join(errors, "; "))
+	}
+	return nil
+}
+
+// validateBudgets validates budget periods, limits and alert thresholds
+func (v *StandardValidator) validateBudgets(budgets *BudgetsConfig) error {
+	var errors []string
+
+	for _, threshold := range budgets.Thresholds {
+		if threshold <= 0 {
+			errors = append(errors, fmt.Sprintf("thresholds: %g must be positive", threshold))
+		}
+	}
+
+	names := make(map[string]bool)
+	for i, budget := range budgets.Items {
+		label := budget.Name
+		if label == "" {
+			label = fmt.Sprintf("items[%d]", i)
+		} else if names[label] {
+			errors = append(errors, fmt.Sprintf("%s: duplicate budget name", label))
+		}
+		names[label] = true
+
+		if err := ValidateBudgetPeriod(budget.Period); err != nil {
+			errors = append(errors, fmt.Sprintf("%s: %v", label, err))
+		}
+		if budget.CostUSD < 0 || budget.Tokens < 0 {
+			errors = append(errors, fmt.Sprintf("%s: limits must be non-negative", label))
+		}
+		if budget.CostUSD == 0 && budget.Tokens == 0 {
+			errors = append(errors, fmt.Sprintf("%s: set cost_usd, tokens or both", label))
+		}
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("%s", strings.Join(errors, "; "))
+	}
+	return nil
+}
+
+// validateHooks validates that each hook runs a command or loads a plugin, for known events
+func (v *StandardValidator) validateHooks(hooks []HookConfig) error {
+	var errors []string
+
+	names := make(map[string]bool)
+	for i, hook := range hooks {
+		label := hook.Name
+		if label == "" {
+			label = fmt.Sprintf("[%d]", i)
+		} else if names[label] {
+			errors = append(errors, fmt.Sprintf("%s: duplicate hook name", label))
+		}
+		names[label] = true
+
+		if (hook.Command == "") == (hook.Plugin == "") {
+			errors = append(errors, fmt.Sprintf("%s: set either command or plugin", label))
+		}
+		if hook.Timeout < 0 {
+			errors = append(errors, fmt.Sprintf("%s: timeout must be non-negative", label))
+		}
+		for _, event := range hook.Events {
+			if err := ValidateHookEvent(event); err != nil {
+				errors = append(errors, fmt.Sprintf("%s: %v", label, err))
+			}
+		}
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("%s", strings.Join(errors, "; "))
+	}
+	return nil
+}
+
+// validateHistory validates the snapshot interval and retention
+func (v *StandardValidator) validateHistory(history *HistoryConfig) error {
+	if history.SnapshotInterval < 0 || history.Retention < 0 {
+		return fmt.Errorf("snapshot_interval and retention must be non-negative")
+	}
+	if history.SnapshotInterval > 0 && history.SnapshotInterval < time.Minute {
+		return fmt.Errorf("snapshot_interval must be at least 1m")
+	}
+	if history.Retention > 0 && history.SnapshotInterval > 0 && history.Retention < history.SnapshotInterval {
+		return fmt.Errorf("retention must be at least snapshot_interval")
+	}
+	return nil
+}
+
+// validateAnomalies validates the spike factor and the burn rate window
+func (v *StandardValidator) validateAnomalies(anomalies *AnomalyConfig) error {
+	if anomalies.Disabled {
+		return nil
+	}
+	if anomalies.Factor <= 1 {
+		return fmt.Errorf("factor must be greater than 1")
+	}
+	if anomalies.Window < time.Minute || anomalies.Window > time.Hour {
+		return fmt.Errorf("window must be between 1m and 1h")
+	}
+	return nil
+}
+
+// validateDigest validates the webhook, format, schedule and posting time of usage digests
+func (v *StandardValidator) validateDigest(digest *DigestConfig) error {
+	if digest.WebhookURL == "" {
+		return nil
+	}
+	webhook, err := url.Parse(digest.WebhookURL)
+	if err != nil || (webhook.Scheme != "http" && webhook.Scheme != "https") || webhook.Host == "" {
+		return fmt.Errorf("webhook_url must be an http or https URL")
+	}
+	format, err := digest.ResolvedFormat()
+	if err != nil {
+		return err
+	}
+	if format != DigestSlack && format != DigestDiscord {
+		return fmt.Errorf("invalid format %q (valid: slack, discord)", format)
+	}
+	switch digest.Schedule {
+	case DigestDaily:
+		if _, err := digest.TimeOfDay(); err != nil {
+			return err
+		}
+	case DigestSession:
+	default:
+		return fmt.Errorf("invalid schedule %q (valid: daily, session)", digest.Schedule)
+	}
+	if digest.TopN < 0 {
+		return fmt.Errorf("top_n must be non-negative")
+	}
+	return nil
+}
+
+// validateTelemetry validates the collector endpoint, sampling ratio and export interval
+func (v *StandardValidator) validateTelemetry(telemetry *TelemetryConfig) error {
+	if telemetry.Endpoint != "" {
+		endpoint, err := url.Parse(telemetry.Endpoint)
+		if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+			return fmt.Errorf("endpoint must be an http or https URL: %s", telemetry.Endpoint)
+		}
+	}
+	if telemetry.SampleRatio < 0 || telemetry.SampleRatio > 1 {
+		return fmt.Errorf("sample_ratio must be between 0 and 1: %v", telemetry.SampleRatio)
+	}
+	if telemetry.MetricInterval < 0 {
+		return fmt.Errorf("metric_interval must be non-negative")
+	}
+	if telemetry.MetricInterval > 0 && telemetry.MetricInterval < time.Second {
+		return fmt.Errorf("metric_interval must be at least 1s")
+	}
+	return nil
+}
+
+// validateStatsd validates the agent address and flavor
+func (v *StandardValidator) validateStatsd(statsd *StatsdConfig) error {
+	if statsd.Address == "" {
+		return nil
+	}
+	host, port, err := net.SplitHostPort(statsd.Address)
+	if err != nil || host == "" {
+		return fmt.Errorf("address must be host:port: %s", statsd.Address)
+	}
+	if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+		return fmt.Errorf("invalid port in address: %s", statsd.Address)
+	}
+	if statsd.Flavor != "" && statsd.Flavor != StatsdFlavorDogStatsD && statsd.Flavor != StatsdFlavorPlain {
+		return fmt.Errorf("invalid flavor %q (valid: dogstatsd, statsd)", statsd.Flavor)
+	}
+	return nil
+}
+
+// validateInflux validates the destination, batch size and retries of the InfluxDB sink
+func (v *StandardValidator) validateInflux(influx *InfluxConfig) error {
+	if influx.URL == "" && influx.File == "" {
+		return nil
+	}
+	if influx.URL != "" && influx.File != "" {
+		return fmt.Errorf("url and file are mutually exclusive")
+	}
+	if influx.URL != "" {
+		server, err := url.Parse(influx.URL)
+		if err != nil || (server.Scheme != "http" && server.Scheme != "https") || server.Host == "" {
+			return fmt.Errorf("url must be an http or https URL: %s", influx.URL)
+		}
+		if influx.Bucket == "" && influx.Database == "" {
+			return fmt.Errorf("bucket or database is required with url")
+		}
+	}
+	if influx.BatchSize < 0 {
+		return fmt.Errorf("batch_size must be non-negative")
+	}
+	retry := influx.Retry
+	if retry.MaxAttempts < 0 || retry.MaxAttempts > 100 {
+		return fmt.Errorf("retry.max_attempts must be between 0 and 100")
+	}
+	if retry.BaseDelay < 0 || retry.MaxDelay < 0 || retry.MaxElapsed < 0 {
+		return fmt.Errorf("retry delays must be non-negative")
+	}
+	if retry.Jitter < 0 || retry.Jitter > 1 {
+		return fmt.Errorf("retry.jitter must be between 0 and 1")
+	}
+	return nil
+}
+
+// validateCache validates the summary cache backend settings
+func (v *StandardValidator) validateCache(cache *CacheConfig) error {
+	if cache.Backend != "" {
+		if err := ValidateCacheBackend(cache.Backend); err != nil {
+			return err
+		}
+	}
+	if cache.Backend == "redis" && cache.RedisURL == "" {
+		return fmt.Errorf("redis_url is required for the redis backend")
+	}
+	if cache.RedisTTL < 0 {
+		return fmt.Errorf("redis_ttl must be non-negative")
+	}
+	return nil
+}
+
 // Built-in validation functions
 
 // ValidatePlan validates subscription plan
 func ValidatePlan(plan string) error {
 	validPlans := map[string]bool{
-		"free": true,
-		"pro":  true,
-		"team": true,
+		"free":   true,
+		"pro":    true,
+		"team":   true,
+		"max5":   true,
+		"max20":  true,
+		"custom": true,
 	}
 
 	if !validPlans[plan] {
-		return fmt.Errorf("invalid plan: %s (valid: free, pro, team)", plan)
+		return fmt.Errorf("invalid plan: %s (valid: free, pro, team, max5, max20, custom, or a plan defined under plans)", plan)
+	}
+	return nil
+}
+
+// ValidateCacheBackend validates the summary cache backend
+func ValidateCacheBackend(backend string) error {
+	validBackends := map[string]bool{
+		"file":   true,
+		"bbolt":  true,
+		"sqlite": true,
+		"redis":  true,
+	}
+
+	if !validBackends[backend] {
+		return fmt.Errorf("invalid cache backend: %s (valid: file, bbolt, sqlite, redis)", backend)
+	}
+	return nil
+}
+
+// ValidateCostMode validates cost calculation mode
+func ValidateCostMode(mode string) error {
+	validModes := map[string]bool{
+		"auto":      true,
+		"display":   true,
+		"calculate": true,
+	}
+
+	if !validModes[mode] {
+		return fmt.Errorf("invalid cost mode: %s (valid: auto, display, calculate)", mode)
 	}
 	return nil
 }
 
+// ValidateAnomalyAction validates how implausible entries are handled
+func ValidateAnomalyAction(action string) error {
+	validActions := map[string]bool{
+		"flag":  true,
+		"clamp": true,
+		"off":   true,
+	}
+
+	if !validActions[action] {
+		return fmt.Errorf("invalid validation action: %s (valid: flag, clamp, off)", action)
+	}
+	return nil
+}
+
+// ValidateBudgetPeriod validates a budget period
+func ValidateBudgetPeriod(period string) error {
+	validPeriods := map[string]bool{
+		"daily":   true,
+		"weekly":  true,
+		"monthly": true,
+	}
+
+	if !validPeriods[period] {
+		return fmt.Errorf("invalid budget period: %s (valid: daily, weekly, monthly)", period)
+	}
+	return nil
+}
+
+// ValidateHookEvent validates a pipeline event a hook is registered for
+func ValidateHookEvent(event string) error {
+	for _, valid := range HookEvents {
+		if event == valid {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid hook event: %s (valid: %s)", event, strings.Join(HookEvents, ", "))
+}
+
 // ValidateTheme validates UI theme
 func ValidateTheme(theme string) error {
 	validThemes := map[string]bool{
@@ -333,6 +868,22 @@ func ValidateLogLevel(level string) error {
 	return nil
 }
 
+// ValidateScheduling validates the order files are handed to the loader workers in
+func ValidateScheduling(scheduling string) error {
+	if scheduling != "largest_first" && scheduling != "in_order" {
+		return fmt.Errorf("invalid scheduling: %s (valid: largest_first, in_order)", scheduling)
+	}
+	return nil
+}
+
+// ValidateLogFormat validates the log output format
+func ValidateLogFormat(format string) error {
+	if format != "text" && format != "json" {
+		return fmt.Errorf("invalid log format: %s (valid: text, json)", format)
+	}
+	return nil
+}
+
 // ValidatePaths validates data paths
 func ValidatePaths(paths []string) error {
 	if len(paths) == 0 {
@@ -355,3 +906,13 @@ func ValidatePaths(paths []string) error {
 
 	return nil
 }
+
+// validateCostAlerts validates session cost ceilings, which must be positive amounts in USD
+func validateCostAlerts(ceilings []float64) error {
+	for _, ceiling := range ceilings {
+		if ceiling <= 0 {
+			return fmt.Errorf("%g must be a positive amount in USD", ceiling)
+		}
+	}
+	return nil
+}
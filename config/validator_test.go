@@ -1,6 +1,7 @@
 package config
 
 import (
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -15,6 +16,9 @@ func TestValidatePlan(t *testing.T) {
 		{"free", false},
 		{"pro", false},
 		{"team", false},
+		{"max5", false},
+		{"max20", false},
+		{"custom", false},
 		{"invalid", true},
 		{"", true},
 	}
@@ -139,6 +143,56 @@ func TestStandardValidator_ValidateApp(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "structured logging",
+			app: AppConfig{
+				LogLevel:    "info",
+				LogFormat:   "json",
+				LogModules:  map[string]string{"cache": "debug", "orchestrator": "warn"},
+				LogRotation: LogRotationConfig{MaxSize: 1024, Interval: 24 * time.Hour, MaxBackups: 3},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid log format",
+			app: AppConfig{
+				LogLevel:  "info",
+				LogFormat: "xml",
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid module level",
+			app: AppConfig{
+				LogLevel:   "info",
+				LogModules: map[string]string{"cache": "verbose"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative rotation",
+			app: AppConfig{
+				LogLevel:    "info",
+				LogRotation: LogRotationConfig{MaxBackups: -1},
+			},
+			wantErr: true,
+		},
+		{
+			name: "log file in a directory yet to be created",
+			app: AppConfig{
+				LogLevel: "info",
+				LogFile:  filepath.Join(t.TempDir(), "state", "claudecat", "claudecat.log"),
+			},
+			wantErr: false,
+		},
+		{
+			name: "log file is a directory",
+			app: AppConfig{
+				LogLevel: "info",
+				LogFile:  t.TempDir(),
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -170,6 +224,87 @@ func TestStandardValidator_ValidateData(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "model aliases",
+			data: DataConfig{
+				WatchInterval: 100 * time.Millisecond,
+				MaxFileSize:   1024 * 1024,
+				CacheSize:     50,
+				ModelAliases:  []ModelAliasConfig{{Pattern: "arn:aws:bedrock:*/abc123", Model: "claude-sonnet-4-20250514"}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "model alias without target",
+			data: DataConfig{
+				WatchInterval: 100 * time.Millisecond,
+				MaxFileSize:   1024 * 1024,
+				CacheSize:     50,
+				ModelAliases:  []ModelAliasConfig{{Pattern: "my-model"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid cost allocation rules",
+			data: DataConfig{
+				WatchInterval:  100 * time.Millisecond,
+				MaxFileSize:    1024 * 1024,
+				CacheSize:      50,
+				CostAllocation: []AllocationRuleConfig{{Path: "~/.claude/projects/-Users-me-work-*", Tag: "work"}, {Project: "^api$", Tag: "platform"}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "cost allocation rule with invalid project regex",
+			data: DataConfig{
+				WatchInterval:  100 * time.Millisecond,
+				MaxFileSize:    1024 * 1024,
+				CacheSize:      50,
+				CostAllocation: []AllocationRuleConfig{{Project: "api(", Tag: "platform"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "cost allocation rule without pattern",
+			data: DataConfig{
+				WatchInterval:  100 * time.Millisecond,
+				MaxFileSize:    1024 * 1024,
+				CacheSize:      50,
+				CostAllocation: []AllocationRuleConfig{{Tag: "platform"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid include and exclude patterns",
+			data: DataConfig{
+				WatchInterval: 100 * time.Millisecond,
+				MaxFileSize:   1024 * 1024,
+				CacheSize:     50,
+				Include:       []string{"-Users-me-work-*"},
+				Exclude:       []string{"*/archive/*", "*.gz"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "empty include pattern",
+			data: DataConfig{
+				WatchInterval: 100 * time.Millisecond,
+				MaxFileSize:   1024 * 1024,
+				CacheSize:     50,
+				Include:       []string{""},
+			},
+			wantErr: true,
+		},
+		{
+			name: "empty exclude pattern",
+			data: DataConfig{
+				WatchInterval: 100 * time.Millisecond,
+				MaxFileSize:   1024 * 1024,
+				CacheSize:     50,
+				Exclude:       []string{"~/.claude/projects/-tmp-*", " "},
+			},
+			wantErr: true,
+		},
 		{
 			name: "watch interval too small",
 			data: DataConfig{
@@ -261,6 +396,72 @@ func TestStandardValidator_ValidateUI(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "custom console fields",
+			ui: UIConfig{
+				Theme:         "dark",
+				RefreshRate:   time.Second,
+				ChartHeight:   10,
+				TablePageSize: 20,
+				Fields:        []string{"tokens", "cache", "burn_rate"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "unknown console field",
+			ui: UIConfig{
+				Theme:         "dark",
+				RefreshRate:   time.Second,
+				ChartHeight:   10,
+				TablePageSize: 20,
+				Fields:        []string{"tokens", "weather"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicate console field",
+			ui: UIConfig{
+				Theme:         "dark",
+				RefreshRate:   time.Second,
+				ChartHeight:   10,
+				TablePageSize: 20,
+				Fields:        []string{"tokens", "tokens"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "idle timeout",
+			ui: UIConfig{
+				Theme:         "dark",
+				RefreshRate:   time.Second,
+				ChartHeight:   10,
+				TablePageSize: 20,
+				IdleTimeout:   15 * time.Minute,
+			},
+			wantErr: false,
+		},
+		{
+			name: "min refresh interval too short",
+			ui: UIConfig{
+				Theme:              "dark",
+				RefreshRate:        time.Second,
+				ChartHeight:        10,
+				TablePageSize:      20,
+				MinRefreshInterval: 10 * time.Millisecond,
+			},
+			wantErr: true,
+		},
+		{
+			name: "idle timeout too short",
+			ui: UIConfig{
+				Theme:         "dark",
+				RefreshRate:   time.Second,
+				ChartHeight:   10,
+				TablePageSize: 20,
+				IdleTimeout:   30 * time.Second,
+			},
+			wantErr: true,
+		},
 		{
 			name: "table page size too small",
 			ui: UIConfig{
@@ -337,6 +538,66 @@ func TestStandardValidator_ValidatePerformance(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "invalid scheduling",
+			perf: PerformanceConfig{
+				WorkerCount: 4,
+				BufferSize:  64 * 1024,
+				BatchSize:   100,
+				MaxMemory:   500 * 1024 * 1024,
+				GCInterval:  5 * time.Minute,
+				Scheduling:  "random",
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative concurrency threshold",
+			perf: PerformanceConfig{
+				WorkerCount:          4,
+				BufferSize:           64 * 1024,
+				BatchSize:            100,
+				MaxMemory:            500 * 1024 * 1024,
+				GCInterval:           5 * time.Minute,
+				ConcurrencyThreshold: -1,
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid retry policy",
+			perf: PerformanceConfig{
+				WorkerCount: 4,
+				BufferSize:  64 * 1024,
+				BatchSize:   100,
+				MaxMemory:   500 * 1024 * 1024,
+				GCInterval:  5 * time.Minute,
+				Retry:       RetryConfig{MaxAttempts: 5, BaseDelay: time.Second, MaxDelay: time.Minute, Jitter: 0.2, MaxElapsed: 2 * time.Minute},
+			},
+			wantErr: false,
+		},
+		{
+			name: "retry jitter out of range",
+			perf: PerformanceConfig{
+				WorkerCount: 4,
+				BufferSize:  64 * 1024,
+				BatchSize:   100,
+				MaxMemory:   500 * 1024 * 1024,
+				GCInterval:  5 * time.Minute,
+				Retry:       RetryConfig{Jitter: 1.5},
+			},
+			wantErr: true,
+		},
+		{
+			name: "retry base delay above max delay",
+			perf: PerformanceConfig{
+				WorkerCount: 4,
+				BufferSize:  64 * 1024,
+				BatchSize:   100,
+				MaxMemory:   500 * 1024 * 1024,
+				GCInterval:  5 * time.Minute,
+				Retry:       RetryConfig{BaseDelay: time.Minute, MaxDelay: time.Second},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -377,6 +638,47 @@ func TestStandardValidator_ValidateSubscription(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "plan defined in config",
+			sub: SubscriptionConfig{
+				Plan:           "enterprise",
+				WarnThreshold:  0.8,
+				AlertThreshold: 0.95,
+				Plans:          map[string]PlanLimitsConfig{"enterprise": {TokenLimit: 5000000}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "negative plan limit",
+			sub: SubscriptionConfig{
+				Plan:           "max5",
+				WarnThreshold:  0.8,
+				AlertThreshold: 0.95,
+				Plans:          map[string]PlanLimitsConfig{"max5": {CostLimit: -1}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "session cost alerts",
+			sub: SubscriptionConfig{
+				Plan:           "max5",
+				WarnThreshold:  0.8,
+				AlertThreshold: 0.95,
+				CostAlerts:     []float64{10},
+				Plans:          map[string]PlanLimitsConfig{"max5": {CostAlerts: []float64{20, 30}}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "non-positive plan cost alert",
+			sub: SubscriptionConfig{
+				Plan:           "max5",
+				WarnThreshold:  0.8,
+				AlertThreshold: 0.95,
+				Plans:          map[string]PlanLimitsConfig{"max5": {CostAlerts: []float64{0}}},
+			},
+			wantErr: true,
+		},
 		{
 			name: "invalid thresholds - warn >= alert",
 			sub: SubscriptionConfig{
@@ -395,6 +697,28 @@ func TestStandardValidator_ValidateSubscription(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "custom limits",
+			sub: SubscriptionConfig{
+				Plan:             "pro",
+				CustomTokenLimit: 2000000,
+				CustomCostLimit:  50,
+				WarnThreshold:    0.8,
+				AlertThreshold:   0.95,
+			},
+			wantErr: false,
+		},
+		{
+			name: "custom token limit with p90",
+			sub: SubscriptionConfig{
+				Plan:             "pro",
+				CustomTokenLimit: 2000000,
+				TokenLimitP90:    true,
+				WarnThreshold:    0.8,
+				AlertThreshold:   0.95,
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -409,17 +733,498 @@ func TestStandardValidator_ValidateSubscription(t *testing.T) {
 	}
 }
 
-func TestStandardValidator_Validate(t *testing.T) {
+func TestStandardValidator_ValidateSession(t *testing.T) {
 	validator := NewStandardValidator()
 
-	// Test with valid configuration
-	cfg := DefaultConfig()
-	err := validator.Validate(cfg)
-	assert.NoError(t, err)
+	tests := []struct {
+		name    string
+		session SessionConfig
+		wantErr bool
+	}{
+		{
+			name:    "default window",
+			session: SessionConfig{WindowDuration: 5 * time.Hour, LateWriteBuffer: 30 * time.Minute},
+			wantErr: false,
+		},
+		{
+			name:    "custom window without buffer",
+			session: SessionConfig{WindowDuration: 8 * time.Hour},
+			wantErr: false,
+		},
+		{
+			name:    "window shorter than an hour",
+			session: SessionConfig{WindowDuration: 30 * time.Minute},
+			wantErr: true,
+		},
+		{
+			name:    "window longer than a week",
+			session: SessionConfig{WindowDuration: 8 * 24 * time.Hour},
+			wantErr: true,
+		},
+		{
+			name:    "negative buffer",
+			session: SessionConfig{WindowDuration: 5 * time.Hour, LateWriteBuffer: -time.Minute},
+			wantErr: true,
+		},
+		{
+			name:    "block splits",
+			session: SessionConfig{WindowDuration: 5 * time.Hour, SplitBlocks: []string{"limit_reset", "model_switch"}},
+			wantErr: false,
+		},
+		{
+			name:    "unknown block split",
+			session: SessionConfig{WindowDuration: 5 * time.Hour, SplitBlocks: []string{"project"}},
+			wantErr: true,
+		},
+	}
 
-	// Test with invalid configuration
-	cfg.App.LogLevel = "invalid"
-	err = validator.Validate(cfg)
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "app:")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.validateSession(&tt.session)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestStandardValidator_ValidateBudgets(t *testing.T) {
+	validator := NewStandardValidator()
+
+	tests := []struct {
+		name    string
+		budgets BudgetsConfig
+		wantErr bool
+	}{
+		{
+			name:    "no budgets",
+			budgets: BudgetsConfig{Thresholds: []float64{0.75, 0.9, 1}},
+			wantErr: false,
+		},
+		{
+			name: "cost and token budgets",
+			budgets: BudgetsConfig{Items: []BudgetConfig{
+				{Name: "daily", Period: "daily", CostUSD: 10},
+				{Name: "monthly", Period: "monthly", CostUSD: 200, Tokens: 50_000_000},
+			}},
+			wantErr: false,
+		},
+		{
+			name:    "unknown period",
+			budgets: BudgetsConfig{Items: []BudgetConfig{{Period: "yearly", CostUSD: 10}}},
+			wantErr: true,
+		},
+		{
+			name:    "no limits",
+			budgets: BudgetsConfig{Items: []BudgetConfig{{Period: "weekly"}}},
+			wantErr: true,
+		},
+		{
+			name:    "negative limit",
+			budgets: BudgetsConfig{Items: []BudgetConfig{{Period: "weekly", CostUSD: -1}}},
+			wantErr: true,
+		},
+		{
+			name: "duplicate names",
+			budgets: BudgetsConfig{Items: []BudgetConfig{
+				{Name: "team", Period: "daily", CostUSD: 10},
+				{Name: "team", Period: "weekly", CostUSD: 50},
+			}},
+			wantErr: true,
+		},
+		{
+			name:    "non-positive threshold",
+			budgets: BudgetsConfig{Thresholds: []float64{0, 0.9}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.validateBudgets(&tt.budgets)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestStandardValidator_ValidateHooks(t *testing.T) {
+	validator := NewStandardValidator()
+
+	tests := []struct {
+		name    string
+		hooks   []HookConfig
+		wantErr bool
+	}{
+		{name: "none"},
+		{
+			name: "command and plugin hooks",
+			hooks: []HookConfig{
+				{Name: "log", Command: "/usr/local/bin/log-usage", Events: []string{"entries_loaded", "session_end"}},
+				{Name: "custom", Plugin: "/opt/claudecat/custom.so", Timeout: 5 * time.Second},
+			},
+		},
+		{name: "neither command nor plugin", hooks: []HookConfig{{Name: "empty"}}, wantErr: true},
+		{name: "both command and plugin", hooks: []HookConfig{{Command: "true", Plugin: "x.so"}}, wantErr: true},
+		{name: "unknown event", hooks: []HookConfig{{Command: "true", Events: []string{"entry_loaded"}}}, wantErr: true},
+		{name: "negative timeout", hooks: []HookConfig{{Command: "true", Timeout: -time.Second}}, wantErr: true},
+		{
+			name:    "duplicate names",
+			hooks:   []HookConfig{{Name: "a", Command: "true"}, {Name: "a", Command: "false"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.validateHooks(tt.hooks)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestStandardValidator_ValidateAnomalies(t *testing.T) {
+	validator := NewStandardValidator()
+
+	tests := []struct {
+		name      string
+		anomalies AnomalyConfig
+		wantErr   bool
+	}{
+		{
+			name:      "defaults",
+			anomalies: DefaultConfig().Anomalies,
+			wantErr:   false,
+		},
+		{
+			name:      "disabled",
+			anomalies: AnomalyConfig{Disabled: true},
+			wantErr:   false,
+		},
+		{
+			name:      "factor not above 1",
+			anomalies: AnomalyConfig{Factor: 1, Window: 15 * time.Minute},
+			wantErr:   true,
+		},
+		{
+			name:      "window too long",
+			anomalies: AnomalyConfig{Factor: 3, Window: 2 * time.Hour},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.validateAnomalies(&tt.anomalies)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestStandardValidator_ValidateHistory(t *testing.T) {
+	validator := NewStandardValidator()
+
+	tests := []struct {
+		name    string
+		history HistoryConfig
+		wantErr bool
+	}{
+		{
+			name:    "defaults",
+			history: DefaultConfig().History,
+			wantErr: false,
+		},
+		{
+			name:    "unset",
+			history: HistoryConfig{},
+			wantErr: false,
+		},
+		{
+			name:    "interval too short",
+			history: HistoryConfig{SnapshotInterval: 10 * time.Second},
+			wantErr: true,
+		},
+		{
+			name:    "negative retention",
+			history: HistoryConfig{Retention: -time.Hour},
+			wantErr: true,
+		},
+		{
+			name:    "retention shorter than interval",
+			history: HistoryConfig{SnapshotInterval: time.Hour, Retention: 30 * time.Minute},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.validateHistory(&tt.history)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestStandardValidator_ValidateCache(t *testing.T) {
+	validator := NewStandardValidator()
+
+	tests := []struct {
+		name    string
+		cache   CacheConfig
+		wantErr bool
+	}{
+		{
+			name:    "defaults",
+			cache:   DefaultConfig().Cache,
+			wantErr: false,
+		},
+		{
+			name:    "sqlite",
+			cache:   CacheConfig{Backend: "sqlite"},
+			wantErr: false,
+		},
+		{
+			name:    "redis with url",
+			cache:   CacheConfig{Backend: "redis", RedisURL: "redis://localhost:6379/0", RedisTTL: time.Hour},
+			wantErr: false,
+		},
+		{
+			name:    "redis without url",
+			cache:   CacheConfig{Backend: "redis"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown backend",
+			cache:   CacheConfig{Backend: "memcached"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.validateCache(&tt.cache)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestStandardValidator_Validate(t *testing.T) {
+	validator := NewStandardValidator()
+
+	// Test with valid configuration
+	cfg := DefaultConfig()
+	err := validator.Validate(cfg)
+	assert.NoError(t, err)
+
+	// Test with invalid configuration
+	cfg.App.LogLevel = "invalid"
+	err = validator.Validate(cfg)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "app:")
+}
+
+func TestStandardValidator_ValidateTelemetry(t *testing.T) {
+	validator := NewStandardValidator()
+
+	tests := []struct {
+		name      string
+		telemetry TelemetryConfig
+		wantErr   bool
+	}{
+		{
+			name:      "defaults",
+			telemetry: DefaultConfig().Telemetry,
+			wantErr:   false,
+		},
+		{
+			name:      "collector endpoint",
+			telemetry: TelemetryConfig{Endpoint: "http://localhost:4318", SampleRatio: 0.5, MetricInterval: time.Minute},
+			wantErr:   false,
+		},
+		{
+			name:      "endpoint without scheme",
+			telemetry: TelemetryConfig{Endpoint: "localhost:4318"},
+			wantErr:   true,
+		},
+		{
+			name:      "sample ratio above one",
+			telemetry: TelemetryConfig{SampleRatio: 1.5},
+			wantErr:   true,
+		},
+		{
+			name:      "interval too short",
+			telemetry: TelemetryConfig{MetricInterval: 100 * time.Millisecond},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.validateTelemetry(&tt.telemetry)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestStandardValidator_ValidateStatsd(t *testing.T) {
+	validator := NewStandardValidator()
+
+	tests := []struct {
+		name    string
+		statsd  StatsdConfig
+		wantErr bool
+	}{
+		{
+			name:    "defaults",
+			statsd:  DefaultConfig().Statsd,
+			wantErr: false,
+		},
+		{
+			name:    "dogstatsd agent",
+			statsd:  StatsdConfig{Address: "localhost:8125", Tags: map[string]string{"team": "platform"}, Flavor: StatsdFlavorDogStatsD},
+			wantErr: false,
+		},
+		{
+			name:    "address without port",
+			statsd:  StatsdConfig{Address: "localhost"},
+			wantErr: true,
+		},
+		{
+			name:    "port out of range",
+			statsd:  StatsdConfig{Address: "localhost:99999"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown flavor",
+			statsd:  StatsdConfig{Address: "localhost:8125", Flavor: "graphite"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.validateStatsd(&tt.statsd)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestStandardValidator_ValidateInflux(t *testing.T) {
+	validator := NewStandardValidator()
+
+	tests := []struct {
+		name    string
+		influx  InfluxConfig
+		wantErr bool
+	}{
+		{
+			name:    "defaults",
+			influx:  DefaultConfig().Influx,
+			wantErr: false,
+		},
+		{
+			name:    "influxdb 2 bucket",
+			influx:  InfluxConfig{URL: "http://nas:8086", Org: "home", Bucket: "claude", Token: "t"},
+			wantErr: false,
+		},
+		{
+			name:    "file",
+			influx:  InfluxConfig{File: "~/claude.lp"},
+			wantErr: false,
+		},
+		{
+			name:    "url and file",
+			influx:  InfluxConfig{URL: "http://nas:8086", Database: "claude", File: "~/claude.lp"},
+			wantErr: true,
+		},
+		{
+			name:    "url without bucket or database",
+			influx:  InfluxConfig{URL: "http://nas:8086"},
+			wantErr: true,
+		},
+		{
+			name:    "negative retry jitter",
+			influx:  InfluxConfig{File: "~/claude.lp", Retry: RetryConfig{Jitter: -1}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.validateInflux(&tt.influx)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestStandardValidator_ValidateDigest(t *testing.T) {
+	validator := NewStandardValidator()
+	withWebhook := func(webhookURL string, modify func(*DigestConfig)) DigestConfig {
+		digest := DefaultConfig().Digest
+		digest.WebhookURL = webhookURL
+		if modify != nil {
+			modify(&digest)
+		}
+		return digest
+	}
+
+	tests := []struct {
+		name    string
+		digest  DigestConfig
+		wantErr bool
+	}{
+		{"disabled by default", DefaultConfig().Digest, false},
+		{"slack detected from host", withWebhook("https://hooks.slack.com/services/T0/B0/x", nil), false},
+		{"discord detected from host", withWebhook("https://discord.com/api/webhooks/1/x", nil), false},
+		{"explicit format for other hosts", withWebhook("https://chat.example.com/hook", func(d *DigestConfig) { d.Format = DigestSlack }), false},
+		{"session schedule ignores time", withWebhook("https://hooks.slack.com/x", func(d *DigestConfig) { d.Schedule = DigestSession; d.Time = "" }), false},
+		{"undetectable format", withWebhook("https://chat.example.com/hook", nil), true},
+		{"unknown format", withWebhook("https://hooks.slack.com/x", func(d *DigestConfig) { d.Format = "teams" }), true},
+		{"webhook without scheme", withWebhook("hooks.slack.com/x", nil), true},
+		{"unknown schedule", withWebhook("https://hooks.slack.com/x", func(d *DigestConfig) { d.Schedule = "weekly" }), true},
+		{"invalid time", withWebhook("https://hooks.slack.com/x", func(d *DigestConfig) { d.Time = "9am" }), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.validateDigest(&tt.digest)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
 }
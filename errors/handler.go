@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"math"
 	"os"
 	"runtime"
 	"time"
@@ -43,7 +42,7 @@ type SystemContext struct {
 // EnhancedErrorHandler provides comprehensive error handling with retry mechanisms
 type EnhancedErrorHandler struct {
 	logger         *log.Logger
-	retryPolicy    *RetryPolicy
+	retryPolicy    RetryPolicy
 	circuitBreaker *CircuitBreaker
 	errorReporter  *ErrorReporter
 }
@@ -53,15 +52,6 @@ type ErrorReporter struct {
 	logger *log.Logger
 }
 
-// RetryPolicy defines retry behavior with exponential backoff
-type RetryPolicy struct {
-	MaxRetries    int
-	BaseDelay     time.Duration
-	MaxDelay      time.Duration
-	BackoffFactor float64
-	Jitter        bool
-}
-
 // RetryableFunc represents a function that can be retried
 type RetryableFunc func() error
 
@@ -69,14 +59,6 @@ type RetryableFunc func() error
 func NewEnhancedErrorHandler() *EnhancedErrorHandler {
 	logger := log.New(os.Stderr, "[ERROR] ", log.LstdFlags|log.Lshortfile)
 
-	retryPolicy := &RetryPolicy{
-		MaxRetries:    3,
-		BaseDelay:     100 * time.Millisecond,
-		MaxDelay:      30 * time.Second,
-		BackoffFactor: 2.0,
-		Jitter:        true,
-	}
-
 	circuitBreaker := &CircuitBreaker{
 		state:            StateClosed,
 		maxFailures:      5,
@@ -86,12 +68,17 @@ func NewEnhancedErrorHandler() *EnhancedErrorHandler {
 
 	return &EnhancedErrorHandler{
 		logger:         logger,
-		retryPolicy:    retryPolicy,
+		retryPolicy:    DefaultRetryPolicy(),
 		circuitBreaker: circuitBreaker,
 		errorReporter:  &ErrorReporter{logger: logger},
 	}
 }
 
+// SetRetryPolicy sets the policy used by RetryWithBackoff
+func (eeh *EnhancedErrorHandler) SetRetryPolicy(policy RetryPolicy) {
+	eeh.retryPolicy = policy
+}
+
 // ReportError reports an error with standardized logging and context
 func (eeh *EnhancedErrorHandler) ReportError(
 	err error,
@@ -184,56 +171,45 @@ func (eeh *EnhancedErrorHandler) RetryWithBackoff(
 	fn RetryableFunc,
 	operation string,
 ) error {
-	var lastErr error
-
-	for attempt := 0; attempt <= eeh.retryPolicy.MaxRetries; attempt++ {
+	attempts := 0
+	blocked := false
+	err := eeh.retryPolicy.Retry(ctx, func() error {
 		// Check if circuit breaker allows the call
 		if !eeh.circuitBreaker.CanCall() {
-			return fmt.Errorf("circuit breaker is open for operation: %s", operation)
-		}
-
-		// Execute the function
-		err := fn()
-		if err == nil {
-			eeh.circuitBreaker.RecordSuccess()
-			if attempt > 0 {
-				eeh.logger.Printf("Operation %s succeeded after %d retries", operation, attempt)
-			}
-			return nil
+			blocked = true
+			return Permanent(fmt.Errorf("circuit breaker is open for operation: %s", operation))
 		}
 
-		lastErr = err
-		eeh.circuitBreaker.RecordFailure()
-
-		// Don't retry if this is the last attempt
-		if attempt == eeh.retryPolicy.MaxRetries {
-			break
+		attempts++
+		if err := fn(); err != nil {
+			eeh.circuitBreaker.RecordFailure()
+			return err
 		}
-
-		// Calculate delay with exponential backoff
-		delay := eeh.calculateDelay(attempt)
-
+		eeh.circuitBreaker.RecordSuccess()
+		return nil
+	}, func(attempt int, delay time.Duration, err error) {
 		eeh.logger.Printf("Operation %s failed (attempt %d/%d), retrying in %v: %v",
-			operation, attempt+1, eeh.retryPolicy.MaxRetries+1, delay, err)
-
-		// Wait for the delay or context cancellation
-		select {
-		case <-ctx.Done():
-			return fmt.Errorf("retry cancelled: %w", ctx.Err())
-		case <-time.After(delay):
-			// Continue to next retry
+			operation, attempt, eeh.retryPolicy.MaxAttempts, delay, err)
+	})
+	if err == nil {
+		if attempts > 1 {
+			eeh.logger.Printf("Operation %s succeeded after %d retries", operation, attempts-1)
 		}
+		return nil
+	}
+	if blocked || ctx.Err() != nil {
+		return err
 	}
 
 	// Log final failure
 	eeh.ReportError(
-		lastErr,
+		err,
 		"retry_handler",
 		"retry_exhausted",
 		map[string]interface{}{
-			"operation":   operation,
-			"max_retries": eeh.retryPolicy.MaxRetries,
-			"final_error": lastErr.Error(),
+			"operation":    operation,
+			"max_attempts": eeh.retryPolicy.MaxAttempts,
+			"final_error":  err.Error(),
 		},
 		map[string]string{
 			"operation": operation,
@@ -241,33 +217,9 @@ func (eeh *EnhancedErrorHandler) RetryWithBackoff(
 		ErrorLevelError,
 	)
 
-	return fmt.Errorf("operation %s failed after %d retries: %w",
-		operation, eeh.retryPolicy.MaxRetries+1, lastErr)
-}
-
-// calculateDelay calculates the delay for the next retry attempt
-func (eeh *EnhancedErrorHandler) calculateDelay(attempt int) time.Duration {
-	// Exponential backoff: baseDelay * (backoffFactor ^ attempt)
-	delay := float64(eeh.retryPolicy.BaseDelay) *
-		math.Pow(eeh.retryPolicy.BackoffFactor, float64(attempt))
-
-	// Apply maximum delay limit
-	if delay > float64(eeh.retryPolicy.MaxDelay) {
-		delay = float64(eeh.retryPolicy.MaxDelay)
-	}
-
-	result := time.Duration(delay)
-
-	// Add jitter to prevent thundering herd
-	if eeh.retryPolicy.Jitter {
-		jitter := time.Duration(float64(result) * 0.1 * float64(2*time.Now().UnixNano()%2-1))
-		result += jitter
-	}
-
-	return result
+	return fmt.Errorf("operation %s failed after %d attempts: %w", operation, attempts, err)
 }
 
-
 // Report handles the actual error reporting
 func (er *ErrorReporter) Report(err error, ctx *ErrorContext, level ErrorLevel) {
 	logMessage := fmt.Sprintf("[%s] Error in %s: %v",
@@ -0,0 +1,110 @@
+package errors
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"github.com/penwyp/claudecat/config"
+)
+
+// RetryPolicy defines retry behavior with exponential backoff
+type RetryPolicy struct {
+	MaxAttempts   int           // Attempts including the first; values below 1 mean a single attempt
+	BaseDelay     time.Duration // Wait before the first retry
+	MaxDelay      time.Duration // Longest wait between attempts, 0 for no limit
+	BackoffFactor float64       // Growth of the wait per retry
+	Jitter        float64       // Fraction of each wait randomized, 0 to 1
+	MaxElapsed    time.Duration // Give up once this much time has passed, 0 for no limit
+}
+
+// DefaultRetryPolicy returns the policy used when none is configured
+func DefaultRetryPolicy() RetryPolicy {
+	return NewRetryPolicy(config.DefaultConfig().Performance.Retry)
+}
+
+// NewRetryPolicy creates a policy doubling the wait for each retry from the configuration
+func NewRetryPolicy(cfg config.RetryConfig) RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:   cfg.MaxAttempts,
+		BaseDelay:     cfg.BaseDelay,
+		MaxDelay:      cfg.MaxDelay,
+		BackoffFactor: 2.0,
+		Jitter:        cfg.Jitter,
+		MaxElapsed:    cfg.MaxElapsed,
+	}
+}
+
+// Delay returns the wait before the given retry, counting from 1
+func (p RetryPolicy) Delay(retry int) time.Duration {
+	factor := p.BackoffFactor
+	if factor < 1 {
+		factor = 1
+	}
+	delay := float64(p.BaseDelay) * math.Pow(factor, float64(retry-1))
+	if p.MaxDelay > 0 && delay > float64(p.MaxDelay) {
+		delay = float64(p.MaxDelay)
+	}
+
+	// Randomize to keep concurrent retries apart
+	if jitter := math.Min(math.Max(p.Jitter, 0), 1); jitter > 0 {
+		delay *= 1 + jitter*(2*rand.Float64()-1)
+	}
+	return time.Duration(delay)
+}
+
+// Retry calls fn until it succeeds, returns a permanent error, ctx is done or the policy is
+// exhausted, and returns the last error. onRetry, when not nil, is called before each wait
+// with the number of the failed attempt.
+func (p RetryPolicy) Retry(ctx context.Context, fn func() error, onRetry func(attempt int, delay time.Duration, err error)) error {
+	start := time.Now()
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+
+		var permanent *permanentError
+		if stderrors.As(err, &permanent) {
+			return permanent.err
+		}
+		if attempt >= p.MaxAttempts {
+			return err
+		}
+
+		delay := p.Delay(attempt)
+		if p.MaxElapsed > 0 && time.Since(start)+delay > p.MaxElapsed {
+			return err
+		}
+		if onRetry != nil {
+			onRetry(attempt, delay, err)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("retry cancelled: %w", ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// permanentError marks an error that retrying cannot resolve
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marks err so that Retry returns it without further attempts
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
@@ -0,0 +1,81 @@
+package errors
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second, BackoffFactor: 2}
+	assert.Equal(t, 100*time.Millisecond, policy.Delay(1))
+	assert.Equal(t, 400*time.Millisecond, policy.Delay(3))
+	assert.Equal(t, time.Second, policy.Delay(10), "capped at the max delay")
+
+	policy.Jitter = 0.5
+	for i := 0; i < 20; i++ {
+		delay := policy.Delay(1)
+		assert.GreaterOrEqual(t, delay, 50*time.Millisecond)
+		assert.LessOrEqual(t, delay, 150*time.Millisecond)
+	}
+}
+
+func TestRetryPolicy_Retry(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, BackoffFactor: 2}
+	failure := stderrors.New("unavailable")
+
+	calls := 0
+	var retried []int
+	err := policy.Retry(context.Background(), func() error {
+		calls++
+		if calls < 3 {
+			return failure
+		}
+		return nil
+	}, func(attempt int, delay time.Duration, err error) {
+		retried = append(retried, attempt)
+		assert.Equal(t, failure, err)
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, []int{1, 2}, retried)
+
+	// Exhausted
+	calls = 0
+	err = policy.Retry(context.Background(), func() error { calls++; return failure }, nil)
+	assert.Equal(t, failure, err)
+	assert.Equal(t, 3, calls)
+
+	// Permanent errors are returned unwrapped without retrying
+	calls = 0
+	err = policy.Retry(context.Background(), func() error { calls++; return Permanent(failure) }, nil)
+	assert.Equal(t, failure, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestRetryPolicy_RetryLimits(t *testing.T) {
+	failure := stderrors.New("unavailable")
+
+	// The next wait would pass the max elapsed time
+	policy := RetryPolicy{MaxAttempts: 10, BaseDelay: time.Second, BackoffFactor: 2, MaxElapsed: 500 * time.Millisecond}
+	calls := 0
+	err := policy.Retry(context.Background(), func() error { calls++; return failure }, nil)
+	assert.Equal(t, failure, err)
+	assert.Equal(t, 1, calls)
+
+	// Cancellation interrupts the wait
+	ctx, cancel := context.WithCancel(context.Background())
+	policy = RetryPolicy{MaxAttempts: 10, BaseDelay: time.Hour, BackoffFactor: 2}
+	err = policy.Retry(ctx, func() error { cancel(); return failure }, nil)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestNewRetryPolicy_Defaults(t *testing.T) {
+	policy := DefaultRetryPolicy()
+	assert.Equal(t, 3, policy.MaxAttempts)
+	assert.Equal(t, 100*time.Millisecond, policy.BaseDelay)
+	assert.Equal(t, 2.0, policy.BackoffFactor)
+}
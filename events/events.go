@@ -0,0 +1,298 @@
+// Package events keeps an append-only log of what happened while monitoring: sessions
+// starting and ending, usage spikes, usage limits hit, budgets breached and errors, so that
+// a limit hit or a cost spike can be traced back after the fact.
+package events
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/penwyp/claudecat/models"
+)
+
+// FileName is the name of the event log inside the cache directory
+const FileName = "events.jsonl"
+
+// Type is the kind of an event
+type Type string
+
+const (
+	// SessionStart is recorded when a session block receives its first entry
+	SessionStart Type = "session_start"
+	// SessionEnd is recorded when a session block is no longer active
+	SessionEnd Type = "session_end"
+	// LimitHit is recorded for each usage limit message Claude logged
+	LimitHit Type = "limit_hit"
+	// BudgetBreach is recorded when a budget's limit is reached
+	BudgetBreach Type = "budget_breach"
+	// Anomaly is recorded when the burn rate spikes far above the usual rate
+	Anomaly Type = "anomaly"
+	// Error is recorded when refreshing the usage data starts failing
+	Error Type = "error"
+)
+
+// Types are all event types, in the order of a session's life
+var Types = []Type{SessionStart, Anomaly, LimitHit, BudgetBreach, Error, SessionEnd}
+
+// Event is one line of the event log
+type Event struct {
+	Time      time.Time              `json:"time"`
+	Type      Type                   `json:"type"`
+	SessionID string                 `json:"session_id,omitempty"`
+	Message   string                 `json:"message"`
+	Data      map[string]interface{} `json:"data,omitempty"`
+}
+
+// key identifies an event, so events derived again from the same logs aren't recorded twice
+func (e Event) key() string {
+	return string(e.Type) + "|" + e.SessionID + "|" + e.Time.UTC().Format(time.RFC3339Nano)
+}
+
+// Query selects events from the log. Zero fields don't restrict the result.
+type Query struct {
+	Since     time.Time // Inclusive
+	Until     time.Time // Exclusive
+	Types     []Type
+	SessionID string
+	Limit     int // Keep only the latest events
+}
+
+// matches reports whether the query selects e
+func (q Query) matches(e Event) bool {
+	if !q.Since.IsZero() && e.Time.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && !e.Time.Before(q.Until) {
+		return false
+	}
+	if q.SessionID != "" && e.SessionID != q.SessionID {
+		return false
+	}
+	if len(q.Types) == 0 {
+		return true
+	}
+	for _, t := range q.Types {
+		if e.Type == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Log is an append-only JSONL file of events. Each append is a single write to a file opened
+// for appending, so the monitor and one-off commands can share it.
+type Log struct {
+	path string
+
+	mu   sync.Mutex
+	seen map[string]bool // Keys of the recorded events, loaded on the first Record
+}
+
+// Open opens (or creates) the event log in cacheDir
+func Open(cacheDir string) (*Log, error) {
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	path := filepath.Join(cacheDir, FileName)
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open event log: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return nil, fmt.Errorf("failed to open event log: %w", err)
+	}
+	return &Log{path: path}, nil
+}
+
+// Path returns the location of the log file
+func (l *Log) Path() string {
+	return l.path
+}
+
+// Append writes events to the end of the log
+func (l *Log) Append(events ...Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	var buf []byte
+	for _, event := range events {
+		line, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("failed to encode event: %w", err)
+		}
+		buf = append(append(buf, line...), '\n')
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open event log: %w", err)
+	}
+	if _, err := file.Write(buf); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write event log: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write event log: %w", err)
+	}
+	for _, event := range events {
+		if l.seen != nil {
+			l.seen[event.key()] = true
+		}
+	}
+	return nil
+}
+
+// Record appends the events that aren't in the log yet and returns them. Events derived from
+// the usage logs are found again on every refresh and after every restart; this records each
+// of them once.
+func (l *Log) Record(events []Event) ([]Event, error) {
+	if len(events) == 0 {
+		return nil, nil
+	}
+
+	l.mu.Lock()
+	if l.seen == nil {
+		recorded, err := l.read(Query{})
+		if err != nil {
+			l.mu.Unlock()
+			return nil, err
+		}
+		l.seen = make(map[string]bool, len(recorded))
+		for _, event := range recorded {
+			l.seen[event.key()] = true
+		}
+	}
+	var fresh []Event
+	for _, event := range events {
+		if key := event.key(); !l.seen[key] {
+			l.seen[key] = true
+			fresh = append(fresh, event)
+		}
+	}
+	l.mu.Unlock()
+
+	return fresh, l.Append(fresh...)
+}
+
+// Query returns the events the query selects, oldest first
+func (l *Log) Query(q Query) ([]Event, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.read(q)
+}
+
+// read scans the log for the events the query selects. Lines that don't decode, such as one
+// torn by a crash, are skipped.
+func (l *Log) read(q Query) ([]Event, error) {
+	file, err := os.Open(l.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open event log: %w", err)
+	}
+	defer file.Close()
+
+	var result []Event
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var event Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil || event.Type == "" {
+			continue
+		}
+		if q.matches(event) {
+			result = append(result, event)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read event log: %w", err)
+	}
+
+	// Concurrent writers may append slightly out of order
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Time.Before(result[j].Time)
+	})
+	if q.Limit > 0 && len(result) > q.Limit {
+		result = result[len(result)-q.Limit:]
+	}
+	return result, nil
+}
+
+// FromBlocks derives the session and limit events of session blocks: a start for every
+// block, an end for every block that is no longer active and a limit hit for every limit
+// message. Being derived from the usage logs, they are complete even for periods nobody
+// was monitoring.
+func FromBlocks(blocks []models.SessionBlock) []Event {
+	var result []Event
+	for _, block := range blocks {
+		if block.IsGap || len(block.Entries) == 0 {
+			continue
+		}
+
+		result = append(result, Event{
+			Time:      block.Entries[0].Timestamp,
+			Type:      SessionStart,
+			SessionID: block.ID,
+			Message:   fmt.Sprintf("Session started (%s)", block.Entries[0].Model),
+		})
+
+		for _, limit := range block.LimitMessages {
+			event := Event{
+				Time:      limit.Timestamp,
+				Type:      LimitHit,
+				SessionID: block.ID,
+				Message:   limit.Message,
+				Data: map[string]interface{}{
+					"tokens": tokensUntil(block, limit.Timestamp),
+				},
+			}
+			if ceiling, ok := limit.TokenCeiling(); ok {
+				event.Data["token_limit"] = ceiling
+			}
+			result = append(result, event)
+		}
+
+		if block.IsActive {
+			continue
+		}
+		end := block.EndTime
+		if block.ActualEndTime != nil {
+			end = *block.ActualEndTime
+		}
+		tokens := block.TokenCounts.TotalTokens()
+		result = append(result, Event{
+			Time:      end,
+			Type:      SessionEnd,
+			SessionID: block.ID,
+			Message:   fmt.Sprintf("Session ended after %d tokens, $%.2f", tokens, block.CostUSD),
+			Data: map[string]interface{}{
+				"tokens":   tokens,
+				"cost_usd": block.CostUSD,
+				"entries":  len(block.Entries),
+			},
+		})
+	}
+	return result
+}
+
+// tokensUntil returns the tokens a block used up to t
+func tokensUntil(block models.SessionBlock, t time.Time) int {
+	tokens := 0
+	for _, entry := range block.Entries {
+		if entry.Timestamp.After(t) {
+			break
+		}
+		tokens += entry.InputTokens + entry.OutputTokens + entry.CacheCreationTokens + entry.CacheReadTokens + entry.ThinkingTokens
+	}
+	return tokens
+}
@@ -0,0 +1,137 @@
+package events
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/penwyp/claudecat/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLog_AppendAndQuery(t *testing.T) {
+	log, err := Open(t.TempDir())
+	require.NoError(t, err)
+
+	base := time.Date(2025, 6, 10, 9, 0, 0, 0, time.UTC)
+	require.NoError(t, log.Append(
+		Event{Time: base, Type: SessionStart, SessionID: "a", Message: "started"},
+		Event{Time: base.Add(2 * time.Hour), Type: LimitHit, SessionID: "a", Message: "limit reached"},
+	))
+	require.NoError(t, log.Append(Event{Time: base.Add(time.Hour), Type: Error, Message: "refresh failed"}))
+
+	all, err := log.Query(Query{})
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	assert.Equal(t, Error, all[1].Type, "events are sorted by time")
+
+	limits, err := log.Query(Query{Types: []Type{LimitHit}})
+	require.NoError(t, err)
+	require.Len(t, limits, 1)
+	assert.Equal(t, "limit reached", limits[0].Message)
+
+	window, err := log.Query(Query{Since: base.Add(time.Hour), Until: base.Add(2 * time.Hour)})
+	require.NoError(t, err)
+	require.Len(t, window, 1)
+	assert.Equal(t, Error, window[0].Type)
+
+	session, err := log.Query(Query{SessionID: "a", Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, session, 1)
+	assert.Equal(t, LimitHit, session[0].Type)
+}
+
+func TestLog_SkipsTornLines(t *testing.T) {
+	dir := t.TempDir()
+	log, err := Open(dir)
+	require.NoError(t, err)
+	require.NoError(t, log.Append(Event{Time: time.Now(), Type: Error, Message: "first"}))
+
+	file, err := os.OpenFile(filepath.Join(dir, FileName), os.O_WRONLY|os.O_APPEND, 0644)
+	require.NoError(t, err)
+	_, err = file.WriteString(`{"time":"2025-06-10T09:00:00Z","ty` + "\n")
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	result, err := log.Query(Query{})
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	assert.Equal(t, "first", result[0].Message)
+}
+
+func TestLog_RecordSkipsRecordedEvents(t *testing.T) {
+	dir := t.TempDir()
+	base := time.Date(2025, 6, 10, 9, 0, 0, 0, time.UTC)
+	start := Event{Time: base, Type: SessionStart, SessionID: "a"}
+	end := Event{Time: base.Add(time.Hour), Type: SessionEnd, SessionID: "a"}
+
+	log, err := Open(dir)
+	require.NoError(t, err)
+	recorded, err := log.Record([]Event{start})
+	require.NoError(t, err)
+	assert.Len(t, recorded, 1)
+
+	// A new process sees what the previous one recorded
+	log, err = Open(dir)
+	require.NoError(t, err)
+	recorded, err = log.Record([]Event{start, end})
+	require.NoError(t, err)
+	require.Len(t, recorded, 1)
+	assert.Equal(t, SessionEnd, recorded[0].Type)
+
+	recorded, err = log.Record([]Event{start, end})
+	require.NoError(t, err)
+	assert.Empty(t, recorded)
+
+	all, err := log.Query(Query{})
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+}
+
+func TestFromBlocks(t *testing.T) {
+	base := time.Date(2025, 6, 10, 9, 0, 0, 0, time.UTC)
+	actualEnd := base.Add(3 * time.Hour)
+	blocks := []models.SessionBlock{
+		{
+			ID:        "ended",
+			StartTime: base,
+			EndTime:   base.Add(5 * time.Hour),
+			Entries: []models.UsageEntry{
+				{Timestamp: base.Add(10 * time.Minute), Model: "claude-opus-4", InputTokens: 100, OutputTokens: 50},
+				{Timestamp: base.Add(2 * time.Hour), Model: "claude-opus-4", InputTokens: 1000},
+			},
+			TokenCounts:   models.TokenCounts{InputTokens: 1100, OutputTokens: 50},
+			CostUSD:       1.5,
+			ActualEndTime: &actualEnd,
+			LimitMessages: []models.LimitMessage{
+				{Timestamp: base.Add(time.Hour), Message: "You've used 950,000 of your 1,000,000 tokens"},
+			},
+		},
+		{ID: "gap", IsGap: true, StartTime: actualEnd, EndTime: base.Add(6 * time.Hour)},
+		{
+			ID:        "active",
+			StartTime: base.Add(6 * time.Hour),
+			IsActive:  true,
+			Entries:   []models.UsageEntry{{Timestamp: base.Add(6 * time.Hour), Model: "claude-sonnet-4"}},
+		},
+	}
+
+	result := FromBlocks(blocks)
+	require.Len(t, result, 4)
+
+	assert.Equal(t, SessionStart, result[0].Type)
+	assert.Equal(t, base.Add(10*time.Minute), result[0].Time, "a session starts with its first entry")
+
+	assert.Equal(t, LimitHit, result[1].Type)
+	assert.Equal(t, 150, result[1].Data["tokens"], "only tokens used before the limit message count")
+	assert.Equal(t, 1000000, result[1].Data["token_limit"])
+
+	assert.Equal(t, SessionEnd, result[2].Type)
+	assert.Equal(t, actualEnd, result[2].Time)
+	assert.Equal(t, 1150, result[2].Data["tokens"])
+
+	assert.Equal(t, SessionStart, result[3].Type)
+	assert.Equal(t, "active", result[3].SessionID, "active sessions haven't ended")
+}
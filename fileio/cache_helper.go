@@ -40,6 +40,7 @@ func createEntriesFromSummary(summary *cache.FileSummary, cutoffTime *time.Time)
 					avgOutputTokens := modelStat.OutputTokens / modelStat.EntryCount
 					avgCacheCreationTokens := modelStat.CacheCreationTokens / modelStat.EntryCount
 					avgCacheReadTokens := modelStat.CacheReadTokens / modelStat.EntryCount
+					avgThinkingTokens := modelStat.ThinkingTokens / modelStat.EntryCount
 					avgCostUSD := modelStat.TotalCost / float64(modelStat.EntryCount)
 
 					// Handle remainders to ensure totals match exactly
@@ -47,6 +48,7 @@ func createEntriesFromSummary(summary *cache.FileSummary, cutoffTime *time.Time)
 					remainderOutputTokens := modelStat.OutputTokens % modelStat.EntryCount
 					remainderCacheCreationTokens := modelStat.CacheCreationTokens % modelStat.EntryCount
 					remainderCacheReadTokens := modelStat.CacheReadTokens % modelStat.EntryCount
+					remainderThinkingTokens := modelStat.ThinkingTokens % modelStat.EntryCount
 
 					for i := 0; i < modelStat.EntryCount; i++ {
 						// Distribute tokens evenly, with remainders in the first entries
@@ -54,6 +56,7 @@ func createEntriesFromSummary(summary *cache.FileSummary, cutoffTime *time.Time)
 						outputTokens := avgOutputTokens
 						cacheCreationTokens := avgCacheCreationTokens
 						cacheReadTokens := avgCacheReadTokens
+						thinkingTokens := avgThinkingTokens
 
 						if i < remainderInputTokens {
 							inputTokens++
@@ -67,6 +70,9 @@ func createEntriesFromSummary(summary *cache.FileSummary, cutoffTime *time.Time)
 						if i < remainderCacheReadTokens {
 							cacheReadTokens++
 						}
+						if i < remainderThinkingTokens {
+							thinkingTokens++
+						}
 
 						entry := models.UsageEntry{
 							Timestamp:           hourTime.Add(time.Duration(i) * time.Minute),
@@ -75,12 +81,16 @@ func createEntriesFromSummary(summary *cache.FileSummary, cutoffTime *time.Time)
 							OutputTokens:        outputTokens,
 							CacheCreationTokens: cacheCreationTokens,
 							CacheReadTokens:     cacheReadTokens,
-							TotalTokens:         inputTokens + outputTokens + cacheCreationTokens + cacheReadTokens,
+							ThinkingTokens:      thinkingTokens,
+							TotalTokens:         inputTokens + outputTokens + cacheCreationTokens + cacheReadTokens + thinkingTokens,
 							CostUSD:             avgCostUSD,
+							CostSource:          models.CostSourceSummary,
 						}
 
 						entry.NormalizeModel()
-						entry.Project = extractProjectFromPath(summary.Path)
+						entry.Project = summaryProject(summary)
+						entry.Source = summarySource(summary)
+						entry.Tag = models.AllocationTag(summary.AbsolutePath, entry.Project)
 						entries = append(entries, entry)
 					}
 				}
@@ -109,18 +119,21 @@ func createEntriesFromSummary(summary *cache.FileSummary, cutoffTime *time.Time)
 					avgOutputTokens := modelStat.OutputTokens / modelStat.EntryCount
 					avgCacheCreationTokens := modelStat.CacheCreationTokens / modelStat.EntryCount
 					avgCacheReadTokens := modelStat.CacheReadTokens / modelStat.EntryCount
+					avgThinkingTokens := modelStat.ThinkingTokens / modelStat.EntryCount
 					avgCostUSD := modelStat.TotalCost / float64(modelStat.EntryCount)
 
 					remainderInputTokens := modelStat.InputTokens % modelStat.EntryCount
 					remainderOutputTokens := modelStat.OutputTokens % modelStat.EntryCount
 					remainderCacheCreationTokens := modelStat.CacheCreationTokens % modelStat.EntryCount
 					remainderCacheReadTokens := modelStat.CacheReadTokens % modelStat.EntryCount
+					remainderThinkingTokens := modelStat.ThinkingTokens % modelStat.EntryCount
 
 					for i := 0; i < modelStat.EntryCount; i++ {
 						inputTokens := avgInputTokens
 						outputTokens := avgOutputTokens
 						cacheCreationTokens := avgCacheCreationTokens
 						cacheReadTokens := avgCacheReadTokens
+						thinkingTokens := avgThinkingTokens
 
 						if i < remainderInputTokens {
 							inputTokens++
@@ -134,6 +147,9 @@ func createEntriesFromSummary(summary *cache.FileSummary, cutoffTime *time.Time)
 						if i < remainderCacheReadTokens {
 							cacheReadTokens++
 						}
+						if i < remainderThinkingTokens {
+							thinkingTokens++
+						}
 
 						entry := models.UsageEntry{
 							Timestamp:           dayTime.Add(time.Duration(i) * time.Hour),
@@ -142,12 +158,16 @@ func createEntriesFromSummary(summary *cache.FileSummary, cutoffTime *time.Time)
 							OutputTokens:        outputTokens,
 							CacheCreationTokens: cacheCreationTokens,
 							CacheReadTokens:     cacheReadTokens,
-							TotalTokens:         inputTokens + outputTokens + cacheCreationTokens + cacheReadTokens,
+							ThinkingTokens:      thinkingTokens,
+							TotalTokens:         inputTokens + outputTokens + cacheCreationTokens + cacheReadTokens + thinkingTokens,
 							CostUSD:             avgCostUSD,
+							CostSource:          models.CostSourceSummary,
 						}
 
 						entry.NormalizeModel()
-						entry.Project = extractProjectFromPath(summary.Path)
+						entry.Project = summaryProject(summary)
+						entry.Source = summarySource(summary)
+						entry.Tag = models.AllocationTag(summary.AbsolutePath, entry.Project)
 						entries = append(entries, entry)
 					}
 				}
@@ -165,12 +185,16 @@ func createEntriesFromSummary(summary *cache.FileSummary, cutoffTime *time.Time)
 					OutputTokens:        modelStat.OutputTokens,
 					CacheCreationTokens: modelStat.CacheCreationTokens,
 					CacheReadTokens:     modelStat.CacheReadTokens,
-					TotalTokens:         modelStat.InputTokens + modelStat.OutputTokens + modelStat.CacheCreationTokens + modelStat.CacheReadTokens,
+					ThinkingTokens:      modelStat.ThinkingTokens,
+					TotalTokens:         modelStat.InputTokens + modelStat.OutputTokens + modelStat.CacheCreationTokens + modelStat.CacheReadTokens + modelStat.ThinkingTokens,
 					CostUSD:             modelStat.TotalCost,
+					CostSource:          models.CostSourceSummary,
 				}
 
 				entry.NormalizeModel()
-				entry.Project = extractProjectFromPath(summary.Path)
+				entry.Project = summaryProject(summary)
+				entry.Source = summarySource(summary)
+				entry.Tag = models.AllocationTag(summary.AbsolutePath, entry.Project)
 				entries = append(entries, entry)
 			}
 		}
@@ -179,6 +203,26 @@ func createEntriesFromSummary(summary *cache.FileSummary, cutoffTime *time.Time)
 	return entries
 }
 
+// summaryProject returns the project of the entries a summary was made from
+func summaryProject(summary *cache.FileSummary) string {
+	if summary.Project != "" {
+		return summary.Project
+	}
+	return extractProjectFromPath(summary.Path)
+}
+
+// summarySource returns the source of the entries a summary was made from. Summaries written
+// before entries were tagged take the source of their file.
+func summarySource(summary *cache.FileSummary) string {
+	if summary.Source != "" {
+		return summary.Source
+	}
+	if source := SourceFor(summary.Path); source != nil {
+		return source.Name()
+	}
+	return models.SourceClaude
+}
+
 // createSummaryFromEntries creates a FileSummary from processed entries
 func createSummaryFromEntries(absPath, filePath string, entries []models.UsageEntry, fileInfo os.FileInfo) *cache.FileSummary {
 	summary := &cache.FileSummary{
@@ -197,6 +241,13 @@ func createSummaryFromEntries(absPath, filePath string, entries []models.UsageEn
 	summary.Checksum = fmt.Sprintf("%x", md5.Sum([]byte(fmt.Sprintf("%s_%d_%d",
 		absPath, fileInfo.ModTime().Unix(), fileInfo.Size()))))
 
+	if len(entries) > 0 && entries[0].Project != extractProjectFromPath(filePath) {
+		summary.Project = entries[0].Project
+	}
+	if len(entries) > 0 {
+		summary.Source = entries[0].Source
+	}
+
 	// Process entries to create statistics
 	var totalCost float64
 	var totalTokens int
@@ -228,10 +279,12 @@ func createSummaryFromEntries(absPath, filePath string, entries []models.UsageEn
 		modelStat.OutputTokens += entry.OutputTokens
 		modelStat.CacheCreationTokens += entry.CacheCreationTokens
 		modelStat.CacheReadTokens += entry.CacheReadTokens
+		modelStat.ThinkingTokens += entry.ThinkingTokens
 		summary.ModelStats[entry.Model] = modelStat
 
-		// Update hourly bucket
-		hourKey := entry.Timestamp.Format("2006-01-02 15")
+		// Update hourly bucket. Keys are UTC so they round-trip through time.Parse on restore
+		// and never collide when a local wall-clock hour repeats at a DST transition.
+		hourKey := entry.Timestamp.UTC().Format("2006-01-02 15")
 		hourBucket, exists := summary.HourlyBuckets[hourKey]
 		if !exists {
 			hourBucket = &cache.TemporalBucket{
@@ -258,9 +311,10 @@ func createSummaryFromEntries(absPath, filePath string, entries []models.UsageEn
 		hourModelStat.OutputTokens += entry.OutputTokens
 		hourModelStat.CacheCreationTokens += entry.CacheCreationTokens
 		hourModelStat.CacheReadTokens += entry.CacheReadTokens
+		hourModelStat.ThinkingTokens += entry.ThinkingTokens
 
 		// Update daily bucket
-		dayKey := entry.Timestamp.Format("2006-01-02")
+		dayKey := entry.Timestamp.UTC().Format("2006-01-02")
 		dayBucket, exists := summary.DailyBuckets[dayKey]
 		if !exists {
 			dayBucket = &cache.TemporalBucket{
@@ -287,6 +341,7 @@ func createSummaryFromEntries(absPath, filePath string, entries []models.UsageEn
 		dayModelStat.OutputTokens += entry.OutputTokens
 		dayModelStat.CacheCreationTokens += entry.CacheCreationTokens
 		dayModelStat.CacheReadTokens += entry.CacheReadTokens
+		dayModelStat.ThinkingTokens += entry.ThinkingTokens
 	}
 
 	summary.TotalCost = totalCost
@@ -0,0 +1,207 @@
+package fileio
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/penwyp/claudecat/models"
+)
+
+// CodexHomeEnv is the environment variable OpenAI Codex CLI uses to relocate its home
+// directory, ~/.codex by default
+const CodexHomeEnv = "CODEX_HOME"
+
+const (
+	// codexDefaultModel prices sessions whose log doesn't record a model, which is what Codex
+	// CLI runs unless configured otherwise
+	codexDefaultModel = "gpt-5"
+
+	// codexProject is the project of sessions whose log doesn't record a working directory
+	codexProject = "codex"
+)
+
+// CodexDataPaths returns the directory Codex CLI writes its session logs to:
+// $CODEX_HOME/sessions, or ~/.codex/sessions
+func CodexDataPaths() []string {
+	home := strings.TrimSpace(os.Getenv(CodexHomeEnv))
+	if home == "" {
+		homeDir, _ := os.UserHomeDir()
+		home = filepath.Join(homeDir, ".codex")
+	}
+	return []string{filepath.Join(expandHome(home), "sessions")}
+}
+
+// IsCodexLogFile reports whether path names a Codex CLI session log. Codex CLI names them
+// rollout-<time>-<session id>.jsonl in per-day directories.
+func IsCodexLogFile(path string) bool {
+	base := strings.ToLower(filepath.Base(path))
+	return strings.HasPrefix(base, "rollout-") && IsUsageFile(base)
+}
+
+// codexSource reads Codex CLI session logs. They spread a session's model and token totals
+// over several records, so each log is read through a codexSession.
+type codexSource struct{}
+
+func (codexSource) Name() string { return models.SourceCodex }
+
+func (codexSource) DataPaths() []string { return CodexDataPaths() }
+
+func (codexSource) Owns(path string) bool { return IsCodexLogFile(path) }
+
+func (codexSource) NewExtractor(path string) UsageExtractor { return newCodexSession(path).extract }
+
+// codexTokenUsage is a token usage object of a Codex CLI token_count event. Input includes
+// the cached input and output includes the reasoning tokens.
+type codexTokenUsage struct {
+	Input       int
+	CachedInput int
+	Output      int
+	Reasoning   int
+	Total       int
+}
+
+// parseCodexTokenUsage reads a token usage object, reporting whether there was one
+func parseCodexTokenUsage(value interface{}) (codexTokenUsage, bool) {
+	usage, ok := value.(map[string]interface{})
+	if !ok {
+		return codexTokenUsage{}, false
+	}
+	count := func(field string) int {
+		if val, ok := usage[field].(float64); ok {
+			return int(val)
+		}
+		return 0
+	}
+	tokens := codexTokenUsage{
+		Input:       count("input_tokens"),
+		CachedInput: count("cached_input_tokens"),
+		Output:      count("output_tokens"),
+		Reasoning:   count("reasoning_output_tokens"),
+		Total:       count("total_tokens"),
+	}
+	if tokens.Total == 0 {
+		tokens.Total = tokens.Input + tokens.Output
+	}
+	return tokens, true
+}
+
+// sub returns the usage between an earlier running total and this one
+func (u codexTokenUsage) sub(earlier codexTokenUsage) codexTokenUsage {
+	return codexTokenUsage{
+		Input:       u.Input - earlier.Input,
+		CachedInput: u.CachedInput - earlier.CachedInput,
+		Output:      u.Output - earlier.Output,
+		Reasoning:   u.Reasoning - earlier.Reasoning,
+		Total:       u.Total - earlier.Total,
+	}
+}
+
+// codexSession follows the records of one Codex CLI session log:
+//
+//	{"timestamp":...,"type":"session_meta","payload":{"id":...,"cwd":...}}
+//	{"timestamp":...,"type":"turn_context","payload":{"model":"gpt-5-codex",...}}
+//	{"timestamp":...,"type":"event_msg","payload":{"type":"token_count","info":{"last_token_usage":{...},"total_token_usage":{...}}}}
+//
+// Each token_count event becomes one entry. Codex CLI repeats the last event when nothing
+// was used in between, so events that don't move the running total are skipped.
+type codexSession struct {
+	id      string
+	project string
+	model   string
+	total   codexTokenUsage
+}
+
+// newCodexSession starts following the log at filePath, taking the session ID from its name
+// until the log records one
+func newCodexSession(filePath string) *codexSession {
+	session := &codexSession{project: codexProject, model: codexDefaultModel}
+	name := strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath))
+	name = strings.TrimSuffix(name, jsonlExt)
+	if len(name) >= 36 && strings.Count(name[len(name)-36:], "-") == 4 {
+		session.id = name[len(name)-36:]
+	}
+	return session
+}
+
+// extract updates the session from a record and returns the usage entry of token_count events
+func (s *codexSession) extract(data map[string]interface{}) (models.UsageEntry, bool) {
+	var entry models.UsageEntry
+	payload, ok := data["payload"].(map[string]interface{})
+	if !ok {
+		return entry, false
+	}
+
+	switch data["type"] {
+	case "session_meta":
+		if id, ok := payload["id"].(string); ok && id != "" {
+			s.id = id
+		}
+		if cwd, ok := payload["cwd"].(string); ok && cwd != "" {
+			s.project = filepath.Base(filepath.FromSlash(cwd))
+		}
+		return entry, false
+	case "turn_context":
+		if model, ok := payload["model"].(string); ok && model != "" {
+			s.model = model
+		}
+		return entry, false
+	case "event_msg":
+		if payload["type"] != "token_count" {
+			return entry, false
+		}
+	default:
+		return entry, false
+	}
+
+	info, ok := payload["info"].(map[string]interface{})
+	if !ok {
+		return entry, false
+	}
+	timestampStr, _ := data["timestamp"].(string)
+	timestamp, err := time.Parse(time.RFC3339, timestampStr)
+	if err != nil {
+		return entry, false
+	}
+
+	total, hasTotal := parseCodexTokenUsage(info["total_token_usage"])
+	usage, hasLast := parseCodexTokenUsage(info["last_token_usage"])
+	if hasTotal {
+		if total == s.total {
+			return entry, false
+		}
+		if !hasLast {
+			usage = total.sub(s.total)
+		}
+		s.total = total
+	} else if !hasLast {
+		return entry, false
+	}
+
+	cached := min(max(usage.CachedInput, 0), max(usage.Input, 0))
+	reasoning := min(max(usage.Reasoning, 0), max(usage.Output, 0))
+	entry = models.UsageEntry{
+		Timestamp:       timestamp,
+		Model:           s.model,
+		InputTokens:     max(usage.Input, 0) - cached,
+		CacheReadTokens: cached,
+		OutputTokens:    max(usage.Output, 0) - reasoning,
+		ThinkingTokens:  reasoning,
+		SessionID:       s.id,
+		Project:         s.project,
+	}
+	entry.TotalTokens = entry.InputTokens + entry.OutputTokens + entry.CacheReadTokens + entry.ThinkingTokens
+	if entry.TotalTokens == 0 {
+		return entry, false
+	}
+
+	// The running total identifies the event within the session, so an event copied into
+	// another log when a session is resumed is only counted once
+	if hasTotal && s.id != "" {
+		entry.MessageID = s.id
+		entry.RequestID = fmt.Sprintf("codex-total-%d", total.Total)
+	}
+	return entry, true
+}
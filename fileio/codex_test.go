@@ -0,0 +1,121 @@
+package fileio
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/penwyp/claudecat/cache"
+	"github.com/penwyp/claudecat/models"
+	"github.com/penwyp/claudecat/models/pricing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const codexTestSessionID = "0199a1b2-c3d4-7e5f-8a9b-0c1d2e3f4a5b"
+
+var codexTestLog = strings.Join([]string{
+	`{"timestamp":"2025-09-15T10:00:00.000Z","type":"session_meta","payload":{"id":"` + codexTestSessionID + `","cwd":"/home/demo/webapp","originator":"codex_cli_rs"}}`,
+	`{"timestamp":"2025-09-15T10:00:01.000Z","type":"turn_context","payload":{"cwd":"/home/demo/webapp","model":"gpt-5-codex"}}`,
+	`{"timestamp":"2025-09-15T10:00:02.000Z","type":"event_msg","payload":{"type":"token_count","info":null}}`,
+	`{"timestamp":"2025-09-15T10:00:05.000Z","type":"event_msg","payload":{"type":"token_count","info":{"total_token_usage":{"input_tokens":1000,"cached_input_tokens":600,"output_tokens":200,"reasoning_output_tokens":50,"total_tokens":1200},"last_token_usage":{"input_tokens":1000,"cached_input_tokens":600,"output_tokens":200,"reasoning_output_tokens":50,"total_tokens":1200}}}}`,
+	`{"timestamp":"2025-09-15T10:00:06.000Z","type":"event_msg","payload":{"type":"token_count","info":{"total_token_usage":{"input_tokens":1000,"cached_input_tokens":600,"output_tokens":200,"reasoning_output_tokens":50,"total_tokens":1200},"last_token_usage":{"input_tokens":1000,"cached_input_tokens":600,"output_tokens":200,"reasoning_output_tokens":50,"total_tokens":1200}}}}`,
+	`{"timestamp":"2025-09-15T10:01:00.000Z","type":"response_item","payload":{"type":"message","role":"assistant","content":[]}}`,
+	`{"timestamp":"2025-09-15T10:02:00.000Z","type":"event_msg","payload":{"type":"token_count","info":{"total_token_usage":{"input_tokens":3000,"cached_input_tokens":2000,"output_tokens":300,"reasoning_output_tokens":0,"total_tokens":3300}}}}`,
+}, "\n") + "\n"
+
+func writeCodexLog(t *testing.T, dir string) string {
+	dayDir := filepath.Join(dir, "2025", "09", "15")
+	require.NoError(t, os.MkdirAll(dayDir, 0755))
+	path := filepath.Join(dayDir, "rollout-2025-09-15T10-00-00-"+codexTestSessionID+".jsonl")
+	require.NoError(t, os.WriteFile(path, []byte(codexTestLog), 0644))
+	return path
+}
+
+func TestIsCodexLogFile(t *testing.T) {
+	assert.True(t, IsCodexLogFile("/home/demo/.codex/sessions/2025/09/15/rollout-2025-09-15T10-00-00-abc.jsonl"))
+	assert.True(t, IsCodexLogFile("rollout-abc.jsonl.gz"))
+	assert.False(t, IsCodexLogFile("/home/demo/.claude/projects/-home-demo-webapp/session.jsonl"))
+	assert.False(t, IsCodexLogFile("rollout-abc.json"))
+}
+
+func TestCodexDataPaths(t *testing.T) {
+	t.Setenv(CodexHomeEnv, "/opt/codex")
+	assert.Equal(t, []string{filepath.Join("/opt/codex", "sessions")}, CodexDataPaths())
+
+	home := t.TempDir()
+	t.Setenv(CodexHomeEnv, "")
+	t.Setenv("HOME", home)
+	assert.Equal(t, []string{filepath.Join(home, ".codex", "sessions")}, CodexDataPaths())
+}
+
+func TestLoadUsageEntries_CodexLog(t *testing.T) {
+	dir := t.TempDir()
+	writeCodexLog(t, dir)
+
+	result, err := LoadUsageEntries(LoadUsageEntriesOptions{
+		DataPath:        dir,
+		Mode:            models.CostModeCalculated,
+		PricingProvider: pricing.NewDefaultProvider(),
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Entries, 2, "empty and repeated token counts are skipped")
+
+	first := result.Entries[0]
+	assert.Equal(t, "gpt-5-codex", first.Model)
+	assert.Equal(t, "webapp", first.Project)
+	assert.Equal(t, models.SourceCodex, first.Source)
+	assert.Equal(t, codexTestSessionID, first.SessionID)
+	assert.Equal(t, 400, first.InputTokens, "cached input is split out")
+	assert.Equal(t, 600, first.CacheReadTokens)
+	assert.Equal(t, 150, first.OutputTokens, "reasoning is split out")
+	assert.Equal(t, 50, first.ThinkingTokens)
+	assert.Equal(t, 1200, first.TotalTokens)
+	// 400 input at $1.25, 600 cached at $0.125 and 200 output at $10 per million
+	assert.InDelta(t, 0.000500+0.000075+0.002, first.CostUSD, 1e-9)
+
+	// Without last_token_usage the difference of the running totals is used
+	second := result.Entries[1]
+	assert.Equal(t, 600, second.InputTokens)
+	assert.Equal(t, 1400, second.CacheReadTokens)
+	assert.Equal(t, 100, second.OutputTokens)
+	assert.Equal(t, 2100, second.TotalTokens)
+}
+
+func TestLoadUsageEntries_CodexLogDeduplicated(t *testing.T) {
+	dir := t.TempDir()
+	path := writeCodexLog(t, dir)
+	// A resumed session copies the events it continues from into a new log
+	resumed := filepath.Join(filepath.Dir(path), "rollout-2025-09-15T11-00-00-"+codexTestSessionID+".jsonl")
+	require.NoError(t, os.WriteFile(resumed, []byte(codexTestLog), 0644))
+
+	result, err := LoadUsageEntries(LoadUsageEntriesOptions{DataPath: dir, Mode: models.CostModeCalculated, EnableDeduplication: true})
+	require.NoError(t, err)
+	assert.Len(t, result.Entries, 2)
+}
+
+func TestLoadUsageEntries_CodexLogCached(t *testing.T) {
+	dir := t.TempDir()
+	writeCodexLog(t, dir)
+	store, err := cache.NewFileBasedSummaryCache(t.TempDir())
+	require.NoError(t, err)
+	opts := LoadUsageEntriesOptions{DataPath: dir, Mode: models.CostModeCalculated, CacheStore: store}
+
+	fresh, err := LoadUsageEntries(opts)
+	require.NoError(t, err)
+	cached, err := LoadUsageEntries(opts)
+	require.NoError(t, err)
+
+	var freshTokens, cachedTokens int
+	for _, entry := range fresh.Entries {
+		freshTokens += entry.TotalTokens
+	}
+	for _, entry := range cached.Entries {
+		cachedTokens += entry.TotalTokens
+		assert.Equal(t, models.CostSourceSummary, entry.CostSource)
+		assert.Equal(t, "webapp", entry.Project, "the project survives the summary")
+		assert.Equal(t, models.SourceCodex, entry.Source, "the source survives the summary")
+	}
+	assert.Equal(t, freshTokens, cachedTokens)
+}
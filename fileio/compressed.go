@@ -0,0 +1,83 @@
+package fileio
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Usage log file extensions. Compressed logs are read through a streaming decompressor.
+const (
+	jsonlExt     = ".jsonl"
+	jsonlGzipExt = ".jsonl.gz"
+	jsonlZstdExt = ".jsonl.zst"
+)
+
+// IsUsageFile reports whether path names a usage log, either plain JSONL or JSONL
+// compressed with gzip or zstd
+func IsUsageFile(path string) bool {
+	lower := strings.ToLower(path)
+	return strings.HasSuffix(lower, jsonlExt) ||
+		strings.HasSuffix(lower, jsonlGzipExt) ||
+		strings.HasSuffix(lower, jsonlZstdExt)
+}
+
+// IsCompressedUsageFile reports whether path names a compressed usage log. Compressed logs
+// are complete archives that are never appended to.
+func IsCompressedUsageFile(path string) bool {
+	lower := strings.ToLower(path)
+	return strings.HasSuffix(lower, jsonlGzipExt) || strings.HasSuffix(lower, jsonlZstdExt)
+}
+
+// OpenUsageFile opens a usage log for reading, transparently decompressing .jsonl.gz and
+// .jsonl.zst files. Closing the reader closes the underlying file.
+func OpenUsageFile(path string) (io.ReadCloser, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	lower := strings.ToLower(path)
+	switch {
+	case strings.HasSuffix(lower, jsonlGzipExt):
+		reader, err := gzip.NewReader(file)
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to read gzip header: %w", err)
+		}
+		return &decompressingReader{Reader: reader, closeReader: reader.Close, file: file}, nil
+	case strings.HasSuffix(lower, jsonlZstdExt):
+		decoder, err := zstd.NewReader(file, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to create zstd reader: %w", err)
+		}
+		closeDecoder := func() error {
+			decoder.Close()
+			return nil
+		}
+		return &decompressingReader{Reader: decoder, closeReader: closeDecoder, file: file}, nil
+	default:
+		return file, nil
+	}
+}
+
+// decompressingReader reads through a decompressor and closes it along with its file
+type decompressingReader struct {
+	io.Reader
+	closeReader func() error
+	file        *os.File
+}
+
+// Close closes the decompressor and the underlying file
+func (r *decompressingReader) Close() error {
+	readerErr := r.closeReader()
+	if err := r.file.Close(); err != nil {
+		return err
+	}
+	return readerErr
+}
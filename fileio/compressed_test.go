@@ -0,0 +1,103 @@
+package fileio
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/penwyp/claudecat/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func gzipBytes(t *testing.T, data []byte) []byte {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, err := w.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func zstdBytes(t *testing.T, data []byte) []byte {
+	encoder, err := zstd.NewWriter(nil)
+	require.NoError(t, err)
+	defer encoder.Close()
+	return encoder.EncodeAll(data, nil)
+}
+
+func TestIsUsageFile(t *testing.T) {
+	assert.True(t, IsUsageFile("/a/session.jsonl"))
+	assert.True(t, IsUsageFile("/a/session.JSONL.GZ"))
+	assert.True(t, IsUsageFile("/a/session.jsonl.zst"))
+	assert.False(t, IsUsageFile("/a/session.gz"))
+	assert.False(t, IsUsageFile("/a/session.json"))
+
+	assert.False(t, IsCompressedUsageFile("/a/session.jsonl"))
+	assert.True(t, IsCompressedUsageFile("/a/session.jsonl.gz"))
+	assert.True(t, IsCompressedUsageFile("/a/session.jsonl.zst"))
+}
+
+func TestOpenUsageFile(t *testing.T) {
+	dir := t.TempDir()
+	content := []byte(`{"type":"assistant"}` + "\n")
+
+	files := map[string][]byte{
+		"plain.jsonl":    content,
+		"gzip.jsonl.gz":  gzipBytes(t, content),
+		"zstd.jsonl.zst": zstdBytes(t, content),
+	}
+	for name, data := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, data, 0644))
+
+		reader, err := OpenUsageFile(path)
+		require.NoError(t, err, name)
+		read, err := io.ReadAll(reader)
+		require.NoError(t, err, name)
+		require.NoError(t, reader.Close(), name)
+		assert.Equal(t, content, read, name)
+	}
+
+	corrupt := filepath.Join(dir, "corrupt.jsonl.gz")
+	require.NoError(t, os.WriteFile(corrupt, content, 0644))
+	_, err := OpenUsageFile(corrupt)
+	assert.Error(t, err)
+}
+
+func TestLoadUsageEntries_CompressedFiles(t *testing.T) {
+	dir := t.TempDir()
+	projectDir := filepath.Join(dir, "-Users-me-archive")
+	require.NoError(t, os.MkdirAll(projectDir, 0755))
+
+	plain := []byte(`{"type":"assistant","timestamp":"2025-06-01T10:00:00Z","requestId":"r1","message":{"id":"m1","model":"claude-sonnet-4-20250514","usage":{"input_tokens":100,"output_tokens":50}}}` + "\n")
+	gzipped := []byte(`{"type":"assistant","timestamp":"2025-05-01T10:00:00Z","requestId":"r2","message":{"id":"m2","model":"claude-sonnet-4-20250514","usage":{"input_tokens":200,"output_tokens":50}}}` + "\n")
+	zstded := []byte(`{"type":"assistant","timestamp":"2025-04-01T10:00:00Z","requestId":"r3","message":{"id":"m3","model":"claude-sonnet-4-20250514","usage":{"input_tokens":300,"output_tokens":50}}}` + "\n")
+
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, "current.jsonl"), plain, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, "old.jsonl.gz"), gzipBytes(t, gzipped), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, "older.jsonl.zst"), zstdBytes(t, zstded), 0644))
+
+	files, err := DiscoverFiles(dir)
+	require.NoError(t, err)
+	assert.Len(t, files, 3)
+
+	result, err := LoadUsageEntries(LoadUsageEntriesOptions{
+		DataPath: dir,
+		Mode:     models.CostModeCalculated,
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Entries, 3)
+
+	byMessage := make(map[string]models.UsageEntry)
+	for _, entry := range result.Entries {
+		byMessage[entry.MessageID] = entry
+	}
+	assert.Equal(t, 250, byMessage["m2"].TotalTokens)
+	assert.Equal(t, 350, byMessage["m3"].TotalTokens)
+	assert.Equal(t, "archive", byMessage["m3"].Project)
+}
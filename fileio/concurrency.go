@@ -0,0 +1,107 @@
+package fileio
+
+import (
+	"os"
+	"runtime"
+	"sort"
+)
+
+// DefaultConcurrencyThreshold is the number of files above which they are loaded concurrently
+const DefaultConcurrencyThreshold = 10
+
+// bytesPerWorker is the least amount of file data worth starting another worker for
+const bytesPerWorker = 1 << 20 // 1MB
+
+// ConcurrencyOptions configures how many files are loaded at once and in which order
+type ConcurrencyOptions struct {
+	MaxWorkers   int  // Upper bound on concurrent workers; 0 uses the CPU count
+	Threshold    int  // Load concurrently when there are more files than this; 0 uses DefaultConcurrencyThreshold
+	InOrder      bool // Hand out files in discovery order instead of largest first
+	FixedWorkers bool // Always start MaxWorkers workers instead of tuning the count to the file sizes
+}
+
+// useConcurrent reports whether fileCount files should go through the concurrent loader
+func (o ConcurrencyOptions) useConcurrent(fileCount int) bool {
+	threshold := o.Threshold
+	if threshold <= 0 {
+		threshold = DefaultConcurrencyThreshold
+	}
+	return fileCount > threshold
+}
+
+// plan orders files for the workers and picks the worker count
+func (o ConcurrencyOptions) plan(files []string) ([]string, int) {
+	sizes := fileSizes(files)
+
+	ordered := files
+	if !o.InOrder {
+		ordered, sizes = largestFirst(files, sizes)
+	}
+
+	workers := o.MaxWorkers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if !o.FixedWorkers {
+		workers = TuneWorkerCount(workers, sizes)
+	}
+	return ordered, workers
+}
+
+// TuneWorkerCount picks how many of maxWorkers to start for files of the given sizes: no more
+// than there are files, and one per bytesPerWorker of data so small loads don't pay for
+// goroutines that would sit idle. A maxWorkers of 0 uses the CPU count.
+func TuneWorkerCount(maxWorkers int, sizes []int64) int {
+	if maxWorkers <= 0 {
+		maxWorkers = runtime.NumCPU()
+	}
+
+	workers := maxWorkers
+	if len(sizes) < workers {
+		workers = len(sizes)
+	}
+
+	var total int64
+	for _, size := range sizes {
+		total += size
+	}
+	if byData := int((total + bytesPerWorker - 1) / bytesPerWorker); byData < workers {
+		workers = byData
+	}
+
+	if workers < 1 {
+		return 1
+	}
+	return workers
+}
+
+// fileSizes returns the size of each file; files that can't be read count as empty
+func fileSizes(files []string) []int64 {
+	sizes := make([]int64, len(files))
+	for i, file := range files {
+		if info, err := os.Stat(file); err == nil {
+			sizes[i] = info.Size()
+		}
+	}
+	return sizes
+}
+
+// largestFirst sorts files by descending size, so the long parses start early and don't
+// leave one worker busy after the others finished
+func largestFirst(files []string, sizes []int64) ([]string, []int64) {
+	indexes := make([]int, len(files))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	sort.SliceStable(indexes, func(a, b int) bool {
+		return sizes[indexes[a]] > sizes[indexes[b]]
+	})
+
+	orderedFiles := make([]string, len(files))
+	orderedSizes := make([]int64, len(sizes))
+	for i, index := range indexes {
+		orderedFiles[i] = files[index]
+		orderedSizes[i] = sizes[index]
+	}
+	return orderedFiles, orderedSizes
+}
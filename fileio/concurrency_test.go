@@ -0,0 +1,44 @@
+package fileio
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTuneWorkerCount(t *testing.T) {
+	mb := int64(bytesPerWorker)
+
+	// Small loads get one worker per megabyte
+	assert.Equal(t, 1, TuneWorkerCount(8, []int64{100, 200, 300}))
+	assert.Equal(t, 3, TuneWorkerCount(8, []int64{mb, mb, mb / 2, mb / 2}))
+	// Never more workers than files or than allowed
+	assert.Equal(t, 2, TuneWorkerCount(8, []int64{10 * mb, 10 * mb}))
+	assert.Equal(t, 4, TuneWorkerCount(4, []int64{mb, mb, mb, mb, mb, mb}))
+	assert.Equal(t, 1, TuneWorkerCount(4, nil))
+}
+
+func TestConcurrencyOptions_Plan(t *testing.T) {
+	dir := t.TempDir()
+	var files []string
+	for i, size := range []int{10, 300, 20} {
+		path := filepath.Join(dir, string(rune('a'+i))+".jsonl")
+		require.NoError(t, os.WriteFile(path, make([]byte, size), 0644))
+		files = append(files, path)
+	}
+
+	ordered, workers := ConcurrencyOptions{MaxWorkers: 4}.plan(files)
+	assert.Equal(t, []string{files[1], files[2], files[0]}, ordered)
+	assert.Equal(t, 1, workers)
+
+	ordered, workers = ConcurrencyOptions{MaxWorkers: 4, InOrder: true, FixedWorkers: true}.plan(files)
+	assert.Equal(t, files, ordered)
+	assert.Equal(t, 4, workers)
+
+	assert.False(t, ConcurrencyOptions{}.useConcurrent(DefaultConcurrencyThreshold))
+	assert.True(t, ConcurrencyOptions{}.useConcurrent(DefaultConcurrencyThreshold+1))
+	assert.True(t, ConcurrencyOptions{Threshold: 2}.useConcurrent(3))
+}
@@ -3,6 +3,7 @@ package fileio
 import (
 	"context"
 	"fmt"
+	"math"
 	"runtime"
 	"sync"
 	"sync/atomic"
@@ -32,7 +33,7 @@ type FileResult struct {
 	ProcessTime time.Duration
 }
 
-// LoadProgress tracks the progress of concurrent loading
+// LoadProgress tracks the progress of loading
 type LoadProgress struct {
 	TotalFiles     int32
 	ProcessedFiles int32
@@ -40,6 +41,66 @@ type LoadProgress struct {
 	CacheMisses    int32
 	Errors         int32
 	TotalEntries   int32
+	TotalBytes     int64 // Combined size of all files
+	ProcessedBytes int64 // Combined size of the processed files, including cache hits
+}
+
+// ProgressFunc receives the progress of a load after each processed file. Calls are
+// serialized but may come from loader goroutines, so implementations must return quickly.
+type ProgressFunc func(LoadProgress)
+
+// Fraction returns how much of the load is done, from 0 to 1. It goes by bytes when the
+// file sizes are known and by file count otherwise.
+func (p LoadProgress) Fraction() float64 {
+	if p.TotalBytes > 0 {
+		return math.Min(float64(p.ProcessedBytes)/float64(p.TotalBytes), 1)
+	}
+	if p.TotalFiles > 0 {
+		return math.Min(float64(p.ProcessedFiles)/float64(p.TotalFiles), 1)
+	}
+	return 0
+}
+
+// snapshot copies progress that workers are still updating
+func (p *LoadProgress) snapshot() LoadProgress {
+	return LoadProgress{
+		TotalFiles:     atomic.LoadInt32(&p.TotalFiles),
+		ProcessedFiles: atomic.LoadInt32(&p.ProcessedFiles),
+		CacheHits:      atomic.LoadInt32(&p.CacheHits),
+		CacheMisses:    atomic.LoadInt32(&p.CacheMisses),
+		Errors:         atomic.LoadInt32(&p.Errors),
+		TotalEntries:   atomic.LoadInt32(&p.TotalEntries),
+		TotalBytes:     atomic.LoadInt64(&p.TotalBytes),
+		ProcessedBytes: atomic.LoadInt64(&p.ProcessedBytes),
+	}
+}
+
+// record counts one processed file. Safe for concurrent use.
+func (p *LoadProgress) record(size int64, entries int, fromCache bool, err error) {
+	atomic.AddInt32(&p.ProcessedFiles, 1)
+	atomic.AddInt64(&p.ProcessedBytes, size)
+	if fromCache {
+		atomic.AddInt32(&p.CacheHits, 1)
+	} else {
+		atomic.AddInt32(&p.CacheMisses, 1)
+	}
+	if err != nil {
+		atomic.AddInt32(&p.Errors, 1)
+	} else {
+		atomic.AddInt32(&p.TotalEntries, int32(entries))
+	}
+}
+
+// fileSizeIndex maps each file to its size and returns the combined size
+func fileSizeIndex(files []string) (map[string]int64, int64) {
+	sizes := fileSizes(files)
+	index := make(map[string]int64, len(files))
+	var total int64
+	for i, file := range files {
+		index[file] = sizes[i]
+		total += sizes[i]
+	}
+	return index, total
 }
 
 // NewConcurrentLoader creates a new concurrent loader
@@ -62,8 +123,10 @@ func (cl *ConcurrentLoader) LoadFiles(ctx context.Context, files []string, opts
 	}
 
 	// Initialize progress tracking
+	sizes, totalBytes := fileSizeIndex(files)
 	progress := &LoadProgress{
 		TotalFiles: int32(len(files)),
+		TotalBytes: totalBytes,
 	}
 
 	// Create channels
@@ -84,7 +147,7 @@ func (cl *ConcurrentLoader) LoadFiles(ctx context.Context, files []string, opts
 	for i := 0; i < cl.workerCount; i++ {
 		go func(workerID int) {
 			defer wg.Done()
-			cl.worker(ctx, workerID, fileChan, resultChan, opts, cutoffTime, progress, progressCallback)
+			cl.worker(ctx, workerID, fileChan, resultChan, opts, cutoffTime, sizes, progress, progressCallback)
 		}(i)
 	}
 
@@ -134,6 +197,7 @@ func (cl *ConcurrentLoader) worker(
 	resultChan chan<- FileResult,
 	opts LoadUsageEntriesOptions,
 	cutoffTime *time.Time,
+	sizes map[string]int64,
 	progress *LoadProgress,
 	progressCallback func(*LoadProgress),
 ) {
@@ -162,20 +226,10 @@ func (cl *ConcurrentLoader) worker(
 			}
 
 			// Update progress
-			atomic.AddInt32(&progress.ProcessedFiles, 1)
-			if fromCache {
-				atomic.AddInt32(&progress.CacheHits, 1)
-			} else {
-				atomic.AddInt32(&progress.CacheMisses, 1)
-			}
-			if err != nil {
-				atomic.AddInt32(&progress.Errors, 1)
-			} else {
-				atomic.AddInt32(&progress.TotalEntries, int32(len(entries)))
-			}
+			progress.record(sizes[filePath], len(entries), fromCache, err)
 
 			// Send progress update
-			if progressCallback != nil && atomic.LoadInt32(&progress.ProcessedFiles)%10 == 0 {
+			if progressCallback != nil {
 				progressCallback(progress)
 			}
 
@@ -192,20 +246,30 @@ func (cl *ConcurrentLoader) worker(
 	}
 }
 
-// LoadFilesWithProgress is a convenience method that provides a default progress printer
+// LoadFilesWithProgress is a convenience method that logs the progress and forwards it to
+// opts.Progress
 func (cl *ConcurrentLoader) LoadFilesWithProgress(ctx context.Context, files []string, opts LoadUsageEntriesOptions) ([]FileResult, error) {
+	var mu sync.Mutex
 	lastUpdate := time.Now()
 
 	progressCallback := func(progress *LoadProgress) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		current := progress.snapshot()
+		if opts.Progress != nil {
+			opts.Progress(current)
+		}
+
 		if time.Since(lastUpdate) < 100*time.Millisecond {
-			return // Throttle updates
+			return // Throttle log messages
 		}
 		lastUpdate = time.Now()
 
-		processed := atomic.LoadInt32(&progress.ProcessedFiles)
-		total := atomic.LoadInt32(&progress.TotalFiles)
-		hits := atomic.LoadInt32(&progress.CacheHits)
-		misses := atomic.LoadInt32(&progress.CacheMisses)
+		processed := current.ProcessedFiles
+		total := current.TotalFiles
+		hits := current.CacheHits
+		misses := current.CacheMisses
 
 		hitRate := float64(0)
 		if hits+misses > 0 {
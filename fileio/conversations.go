@@ -0,0 +1,152 @@
+package fileio
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/bytedance/sonic"
+)
+
+// ConversationInfo describes a Claude Code conversation as its log records it
+type ConversationInfo struct {
+	SessionID   string `json:"session_id"`
+	Title       string `json:"title,omitempty"`        // Summary Claude Code generated for the conversation
+	FirstPrompt string `json:"first_prompt,omitempty"` // First prompt typed by the user, whitespace collapsed
+	Path        string `json:"path"`
+}
+
+// ReadConversationInfo reads the titles and first prompts of the conversations in a Claude Code
+// log, keyed by session ID. A log usually holds one conversation; a resumed conversation also
+// carries the session of the one it continues. Title summaries aren't tied to a session and
+// apply to every conversation of the log.
+func ReadConversationInfo(path string) (map[string]ConversationInfo, error) {
+	file, err := OpenUsageFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	infos := make(map[string]ConversationInfo)
+	var title string
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024) // 10MB max line size
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		// Skip the assistant messages and tool output making up most of a log without decoding them
+		if !bytes.Contains(line, []byte(`"summary"`)) && !bytes.Contains(line, []byte(`"user"`)) {
+			continue
+		}
+		var data map[string]interface{}
+		if err := sonic.Unmarshal(line, &data); err != nil {
+			continue
+		}
+
+		switch data["type"] {
+		case "summary":
+			if summary, ok := data["summary"].(string); ok && strings.TrimSpace(summary) != "" {
+				title = collapseWhitespace(summary)
+			}
+		case "user":
+			sessionID, _ := data["sessionId"].(string)
+			if sessionID == "" || infos[sessionID].FirstPrompt != "" {
+				continue
+			}
+			if meta, _ := data["isMeta"].(bool); meta {
+				continue
+			}
+			if prompt := userPrompt(data); prompt != "" {
+				infos[sessionID] = ConversationInfo{SessionID: sessionID, FirstPrompt: prompt, Path: path}
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	// A log without prompts still names its conversation
+	if len(infos) == 0 && title != "" {
+		sessionID, _, _ := strings.Cut(filepath.Base(path), ".")
+		infos[sessionID] = ConversationInfo{SessionID: sessionID, Path: path}
+	}
+	if title != "" {
+		for id, info := range infos {
+			info.Title = title
+			infos[id] = info
+		}
+	}
+	return infos, nil
+}
+
+// ReadConversationInfos reads the conversation info of every Claude Code log under paths. Logs
+// that fail to read are skipped. When a conversation spans several logs, the one holding its
+// first prompt wins.
+func ReadConversationInfos(paths []string) (map[string]ConversationInfo, error) {
+	files, err := DiscoverFilesInPaths(paths)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]ConversationInfo)
+	for _, path := range files {
+		if _, ok := SourceFor(path).(claudeSource); !ok {
+			continue
+		}
+		infos, err := ReadConversationInfo(path)
+		if err != nil {
+			continue
+		}
+		for id, info := range infos {
+			if existing, ok := result[id]; ok && existing.FirstPrompt != "" {
+				if existing.Title == "" {
+					existing.Title = info.Title
+					result[id] = existing
+				}
+				continue
+			}
+			result[id] = info
+		}
+	}
+	return result, nil
+}
+
+// userPrompt returns the text a user typed in a user record, or "" for tool results, slash
+// commands and other records Claude Code logs as user messages
+func userPrompt(data map[string]interface{}) string {
+	message, ok := data["message"].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+
+	var text string
+	switch content := message["content"].(type) {
+	case string:
+		text = content
+	case []interface{}:
+		for _, part := range content {
+			block, ok := part.(map[string]interface{})
+			if !ok || block["type"] != "text" {
+				continue
+			}
+			if blockText, ok := block["text"].(string); ok {
+				text = blockText
+				break
+			}
+		}
+	}
+
+	text = collapseWhitespace(text)
+	// Slash commands, their output and interruptions are wrapped in tags or brackets
+	if text == "" || strings.HasPrefix(text, "<") || strings.HasPrefix(text, "[Request interrupted") ||
+		strings.HasPrefix(text, "Caveat:") {
+		return ""
+	}
+	return text
+}
+
+// collapseWhitespace joins the words of s with single spaces
+func collapseWhitespace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
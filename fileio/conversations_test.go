@@ -0,0 +1,60 @@
+package fileio
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadConversationInfo(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "c1.jsonl")
+	content := `{"type":"summary","summary":"Fix the   login\nredirect","leafUuid":"u9"}
+{"type":"user","sessionId":"c1","isMeta":true,"message":{"role":"user","content":"Caveat: the messages below were generated by the user"}}
+{"type":"user","sessionId":"c1","message":{"role":"user","content":"<command-name>/clear</command-name>"}}
+{"type":"user","sessionId":"c1","message":{"role":"user","content":[{"type":"text","text":"Why does  login\n redirect twice?"}]}}
+{"type":"assistant","sessionId":"c1","message":{"id":"m1","model":"claude-sonnet-4-20250514","usage":{"input_tokens":10,"output_tokens":5}}}
+{"type":"user","sessionId":"c1","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":"ok"}]}}
+{"type":"user","sessionId":"c1","message":{"role":"user","content":"Now add a test"}}
+`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+	infos, err := ReadConversationInfo(path)
+	require.NoError(t, err)
+	require.Len(t, infos, 1)
+	assert.Equal(t, ConversationInfo{
+		SessionID:   "c1",
+		Title:       "Fix the login redirect",
+		FirstPrompt: "Why does login redirect twice?",
+		Path:        path,
+	}, infos["c1"])
+}
+
+func TestReadConversationInfos(t *testing.T) {
+	dir := t.TempDir()
+	project := filepath.Join(dir, "projects", "repo")
+	require.NoError(t, os.MkdirAll(project, 0755))
+
+	// The resumed conversation carries the prompt of the one it continues
+	require.NoError(t, os.WriteFile(filepath.Join(project, "c1.jsonl"), []byte(
+		`{"type":"user","sessionId":"c1","message":{"role":"user","content":"First question"}}
+`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(project, "c2.jsonl"), []byte(
+		`{"type":"summary","summary":"Resumed work"}
+{"type":"user","sessionId":"c1","message":{"role":"user","content":"First question"}}
+{"type":"user","sessionId":"c2","message":{"role":"user","content":"Follow-up"}}
+`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(project, "c3.jsonl"), []byte(
+		`{"type":"summary","summary":"No prompts yet"}
+`), 0644))
+
+	infos, err := ReadConversationInfos([]string{dir})
+	require.NoError(t, err)
+	require.Len(t, infos, 3)
+	assert.Equal(t, "First question", infos["c1"].FirstPrompt)
+	assert.Equal(t, "Resumed work", infos["c2"].Title)
+	assert.Equal(t, "Follow-up", infos["c2"].FirstPrompt)
+	assert.Equal(t, "No prompts yet", infos["c3"].Title, "logs without prompts are named after their file")
+}
@@ -1,15 +1,182 @@
 package fileio
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"runtime"
 	"strings"
+	"sync"
+	"time"
+
+	"github.com/penwyp/claudecat/logging"
 )
 
-// DiscoverFiles discovers JSONL files in a given path
+// ClaudeConfigDirEnv is the environment variable Claude Code uses to relocate its
+// configuration directory. It may hold several comma-separated directories.
+const ClaudeConfigDirEnv = "CLAUDE_CONFIG_DIR"
+
+// CandidateDataPaths returns every location Claude Code may write project logs to.
+// When CLAUDE_CONFIG_DIR is set its directories replace the defaults; otherwise the
+// XDG location (~/.config/claude), the legacy ~/.claude, the XDG data location
+// (~/.local/share/claude), on macOS ~/Library/Application Support/Claude, on Windows
+// %APPDATA%\claude and, inside WSL, the Claude directories of Windows users on the mounted
+// drives are probed.
+func CandidateDataPaths() []string {
+	var configDirs []string
+	if env := strings.TrimSpace(os.Getenv(ClaudeConfigDirEnv)); env != "" {
+		for _, dir := range strings.Split(env, ",") {
+			if dir = strings.TrimSpace(dir); dir != "" {
+				configDirs = append(configDirs, expandHome(dir))
+			}
+		}
+	} else {
+		homeDir, _ := os.UserHomeDir()
+		xdgConfig := os.Getenv("XDG_CONFIG_HOME")
+		if xdgConfig == "" {
+			xdgConfig = filepath.Join(homeDir, ".config")
+		}
+		xdgData := os.Getenv("XDG_DATA_HOME")
+		if xdgData == "" {
+			xdgData = filepath.Join(homeDir, ".local", "share")
+		}
+		configDirs = append(configDirs,
+			filepath.Join(xdgConfig, "claude"),
+			filepath.Join(homeDir, ".claude"),
+			filepath.Join(xdgData, "claude"),
+		)
+		switch runtime.GOOS {
+		case "darwin":
+			configDirs = append(configDirs, filepath.Join(homeDir, "Library", "Application Support", "Claude"))
+		case "windows":
+			if appData := os.Getenv("APPDATA"); appData != "" {
+				configDirs = append(configDirs, filepath.Join(appData, "claude"))
+			}
+			if localAppData := os.Getenv("LOCALAPPDATA"); localAppData != "" {
+				configDirs = append(configDirs, filepath.Join(localAppData, "claude"))
+			}
+		}
+		configDirs = append(configDirs, wslConfigDirs()...)
+	}
+
+	seen := make(map[string]bool)
+	var paths []string
+	for _, dir := range configDirs {
+		path := dir
+		if filepath.Base(path) != "projects" {
+			path = filepath.Join(dir, "projects")
+		}
+		path = filepath.Clean(path)
+		if !seen[path] {
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// DefaultDataPaths returns the candidate data paths that exist, together with the log
+// directories of the other sources that exist, so all of them can be monitored together. If
+// none exist yet, the first candidate is returned.
+func DefaultDataPaths() []string {
+	candidates := CandidateDataPaths()
+
+	existing := ExistingDataPaths(SourceDataPaths())
+	if len(existing) == 0 && len(candidates) > 0 {
+		return candidates[:1]
+	}
+	return existing
+}
+
+// ExistingDataPaths returns the paths that exist, either as directories or as single files.
+// Paths resolving to the same location, such as ~/.config/claude linked to ~/.claude, are
+// returned once so their logs are not read twice.
+func ExistingDataPaths(paths []string) []string {
+	seen := make(map[string]bool)
+	var existing []string
+	for _, path := range paths {
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		resolved, err := filepath.EvalSymlinks(path)
+		if err != nil {
+			resolved = path
+		}
+		if !seen[resolved] {
+			seen[resolved] = true
+			existing = append(existing, path)
+		}
+	}
+	return existing
+}
+
+// WaitForDataPaths checks every interval whether any of the candidate data paths exists and
+// returns the existing ones, or nil when ctx is done first. candidates is called on every
+// check, so locations that only show up later, such as a newly mounted drive, are found too.
+func WaitForDataPaths(ctx context.Context, candidates func() []string, interval time.Duration) []string {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if existing := ExistingDataPaths(candidates()); len(existing) > 0 {
+			return existing
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// wslMountRoot is where WSL mounts the Windows drives
+var wslMountRoot = "/mnt"
+
+// runningInWSL reports whether the process runs inside the Windows Subsystem for Linux
+var runningInWSL = func() bool {
+	if os.Getenv("WSL_DISTRO_NAME") != "" {
+		return true
+	}
+	release, err := os.ReadFile("/proc/sys/kernel/osrelease")
+	return err == nil && strings.Contains(strings.ToLower(string(release)), "microsoft")
+}
+
+// wslConfigDirs returns the existing Claude configuration directories of Windows users on
+// the drives mounted into WSL, for Claude Code running on the Windows side
+func wslConfigDirs() []string {
+	if runtime.GOOS != "linux" || !runningInWSL() {
+		return nil
+	}
+
+	var dirs []string
+	for _, dir := range []string{".claude", ".config/claude", "AppData/Roaming/claude"} {
+		matches, _ := filepath.Glob(filepath.Join(wslMountRoot, "[a-z]", "Users", "*", filepath.FromSlash(dir)))
+		dirs = append(dirs, matches...)
+	}
+	return dirs
+}
+
+// expandHome expands a leading ~/ to the user's home directory
+func expandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		homeDir, _ := os.UserHomeDir()
+		return filepath.Join(homeDir, strings.TrimPrefix(path[1:], "/"))
+	}
+	return path
+}
+
+// DiscoverFiles discovers JSONL files, including gzip and zstd compressed ones, and the logs
+// of the other sources in a given path.
+//
+// Symlinked directories and files are followed, so project directories linked to other
+// drives or network mounts are found; a directory reached twice, such as through a link
+// cycle, is walked once. Subdirectories that can't be read are logged and skipped, and the
+// discovery filter (see SetDiscoveryFilter) selects the logs returned. Inside WSL a Windows
+// path such as C:\Users\me\.claude is read from the drive mounted under /mnt.
 func DiscoverFiles(path string) ([]string, error) {
-	var files []string
+	path = NormalizeDataPath(path)
 
 	// Check if path exists
 	info, err := os.Stat(path)
@@ -17,29 +184,228 @@ func DiscoverFiles(path string) ([]string, error) {
 		return nil, fmt.Errorf("path does not exist: %w", err)
 	}
 
-	if info.IsDir() {
-		// Search for JSONL files in directory
-		err := filepath.Walk(path, func(walkPath string, info os.FileInfo, err error) error {
+	d := &discovery{
+		filter:  CurrentDiscoveryFilter(),
+		visited: make(map[string]bool),
+		seen:    make(map[string]bool),
+	}
+	if !info.IsDir() {
+		// Single file
+		if IsSourceFile(path) && d.filter.Selects(path, path, false) {
+			d.files = append(d.files, path)
+		}
+		return d.files, nil
+	}
+
+	if err := d.walk(path, d.filter.includes(path, path, true)); err != nil {
+		return nil, fmt.Errorf("failed to walk directory: %w", err)
+	}
+	return d.files, nil
+}
+
+// discovery walks a data path for DiscoverFiles
+type discovery struct {
+	filter  DiscoveryFilter
+	visited map[string]bool // Resolved directories already walked
+	seen    map[string]bool // Resolved files already found
+	files   []string
+}
+
+// walk collects the logs under dir in lexical order; included reports whether dir or a
+// directory above it matches an include pattern. Only a failure to read dir itself is
+// returned; unreadable subdirectories are skipped.
+func (d *discovery) walk(dir string, included bool) error {
+	resolved, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		resolved = dir
+	}
+	if d.visited[resolved] {
+		logging.LogDebugf("Skipping %s: already walked as %s", dir, resolved)
+		return nil
+	}
+	d.visited[resolved] = true
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		target := filepath.Join(resolved, entry.Name())
+		isDir := entry.IsDir()
+		if entry.Type()&os.ModeSymlink != 0 {
+			info, err := os.Stat(path)
 			if err != nil {
-				return err
+				logging.LogDebugf("Skipping broken link %s: %v", path, err)
+				continue
+			}
+			isDir = info.IsDir()
+			if linked, err := filepath.EvalSymlinks(path); err == nil {
+				target = linked
 			}
+		}
+		if d.filter.excludes(path, target, isDir) {
+			continue
+		}
 
-			if !info.IsDir() && strings.HasSuffix(strings.ToLower(walkPath), ".jsonl") {
-				files = append(files, walkPath)
+		if isDir {
+			if err := d.walk(path, included || d.filter.includes(path, target, true)); err != nil {
+				logging.LogWarnf("Skipping unreadable directory %s: %v", path, err)
 			}
+			continue
+		}
+		if !included && !d.filter.includes(path, target, false) {
+			continue
+		}
+		if IsSourceFile(path) && !d.seen[target] {
+			d.seen[target] = true
+			d.files = append(d.files, path)
+		}
+	}
+	return nil
+}
 
-			return nil
-		})
+// PathPattern matches file and directory paths, where * matches any text, ? matches one
+// character and a leading ~/ is the home directory. A pattern without a path separator,
+// such as *.gz or -tmp-*, is matched against the name alone.
+type PathPattern struct {
+	Pattern string
+	re      *regexp.Regexp
+	name    bool // Match the base name rather than the whole path
+}
+
+// NewPathPattern compiles pattern
+func NewPathPattern(pattern string) (PathPattern, error) {
+	if strings.TrimSpace(pattern) == "" {
+		return PathPattern{}, fmt.Errorf("empty path pattern")
+	}
+	expanded := filepath.FromSlash(expandHome(pattern))
+	quoted := strings.NewReplacer(`\*`, ".*", `\?`, ".").Replace(regexp.QuoteMeta(expanded))
+	re, err := regexp.Compile("^" + quoted + "$")
+	if err != nil {
+		return PathPattern{}, fmt.Errorf("invalid path pattern %q: %w", pattern, err)
+	}
+	name := !strings.ContainsAny(pattern, `/\`)
+	return PathPattern{Pattern: pattern, re: re, name: name}, nil
+}
+
+// Matches reports whether the pattern matches path. A directory also matches patterns for
+// its contents, such as */archive/*, so its whole tree is selected or skipped.
+func (p PathPattern) Matches(path string, isDir bool) bool {
+	if p.re == nil {
+		return false
+	}
+	if p.name {
+		return p.re.MatchString(filepath.Base(path))
+	}
+	return p.re.MatchString(path) || (isDir && p.re.MatchString(path+string(filepath.Separator)))
+}
 
+// DiscoveryFilter selects the logs DiscoverFiles returns. Without include patterns every
+// log is included; with them only logs that match one, or lie in a directory that does.
+// Logs and directories matching an exclude pattern are skipped even when included. Links
+// are matched both by their own path and by the path they point to.
+type DiscoveryFilter struct {
+	Include []PathPattern
+	Exclude []PathPattern
+}
+
+// Selects reports whether the filter selects the log at path, which resolves to target
+func (f DiscoveryFilter) Selects(path, target string, isDir bool) bool {
+	return f.includes(path, target, isDir) && !f.excludes(path, target, isDir)
+}
+
+// includes reports whether path is included by itself, ignoring the directories above it
+func (f DiscoveryFilter) includes(path, target string, isDir bool) bool {
+	return len(f.Include) == 0 || matchesAny(f.Include, path, target, isDir)
+}
+
+// excludes reports whether path matches an exclude pattern
+func (f DiscoveryFilter) excludes(path, target string, isDir bool) bool {
+	return matchesAny(f.Exclude, path, target, isDir)
+}
+
+// matchesAny reports whether one of patterns matches path or the target it resolves to
+func matchesAny(patterns []PathPattern, path, target string, isDir bool) bool {
+	for _, pattern := range patterns {
+		if pattern.Matches(path, isDir) || (target != path && pattern.Matches(target, isDir)) {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	discoveryFilterMu sync.RWMutex
+	discoveryFilter   DiscoveryFilter
+)
+
+// SetDiscoveryFilter replaces the filter selecting the logs DiscoverFiles returns
+func SetDiscoveryFilter(filter DiscoveryFilter) {
+	discoveryFilterMu.Lock()
+	defer discoveryFilterMu.Unlock()
+	discoveryFilter = DiscoveryFilter{
+		Include: append([]PathPattern(nil), filter.Include...),
+		Exclude: append([]PathPattern(nil), filter.Exclude...),
+	}
+}
+
+// CurrentDiscoveryFilter returns the filter selecting the logs DiscoverFiles returns
+func CurrentDiscoveryFilter() DiscoveryFilter {
+	discoveryFilterMu.RLock()
+	defer discoveryFilterMu.RUnlock()
+	return discoveryFilter
+}
+
+// windowsDrivePath matches absolute Windows paths such as C:\Users or c:/Users
+var windowsDrivePath = regexp.MustCompile(`^([A-Za-z]):[\\/]`)
+
+// NormalizeDataPath expands a leading ~/ and, inside WSL, turns a Windows path such as
+// C:\Users\me\.claude into the path of the drive mounted under /mnt
+func NormalizeDataPath(path string) string {
+	path = expandHome(path)
+	if runtime.GOOS != "linux" || !windowsDrivePath.MatchString(path) || !runningInWSL() {
+		return path
+	}
+	drive := strings.ToLower(path[:1])
+	rest := strings.ReplaceAll(path[3:], `\`, "/")
+	return filepath.Join(wslMountRoot, drive, rest)
+}
+
+// DiscoverFilesInPaths discovers JSONL files across several data paths. Missing paths
+// are skipped as long as at least one path can be read; files reachable from more than
+// one path are only returned once.
+func DiscoverFilesInPaths(paths []string) ([]string, error) {
+	if len(paths) == 1 {
+		return DiscoverFiles(paths[0])
+	}
+
+	seen := make(map[string]bool)
+	var files []string
+	var lastErr error
+	found := false
+	for _, path := range paths {
+		pathFiles, err := DiscoverFiles(path)
 		if err != nil {
-			return nil, fmt.Errorf("failed to walk directory: %w", err)
+			logging.LogDebugf("Skipping data path %s: %v", path, err)
+			lastErr = err
+			continue
 		}
-	} else {
-		// Single file
-		if strings.HasSuffix(strings.ToLower(path), ".jsonl") {
-			files = append(files, path)
+		found = true
+		for _, file := range pathFiles {
+			key := file
+			if abs, err := filepath.Abs(file); err == nil {
+				key = abs
+			}
+			if !seen[key] {
+				seen[key] = true
+				files = append(files, file)
+			}
 		}
 	}
 
+	if !found && lastErr != nil {
+		return nil, lastErr
+	}
 	return files, nil
-}
\ No newline at end of file
+}
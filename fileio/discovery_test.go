@@ -1,9 +1,12 @@
 package fileio
 
 import (
+	"context"
 	"os"
 	"path/filepath"
+	"runtime"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -149,4 +152,246 @@ func TestDiscoverFiles_CaseInsensitive(t *testing.T) {
 	files, err := DiscoverFiles(tempDir)
 	require.NoError(t, err)
 	assert.Len(t, files, 3)
-}
\ No newline at end of file
+}
+
+func TestCandidateDataPaths_ClaudeConfigDir(t *testing.T) {
+	first := t.TempDir()
+	second := t.TempDir()
+	t.Setenv(ClaudeConfigDirEnv, first+", "+filepath.Join(second, "projects"))
+
+	paths := CandidateDataPaths()
+	assert.Equal(t, []string{
+		filepath.Join(first, "projects"),
+		filepath.Join(second, "projects"),
+	}, paths)
+
+	// Separators alone name no location, so there is nothing to monitor
+	t.Setenv(ClaudeConfigDirEnv, ",")
+	t.Setenv("HOME", t.TempDir())
+	assert.Empty(t, CandidateDataPaths())
+	assert.Empty(t, DefaultDataPaths())
+}
+
+func TestCandidateDataPaths_Defaults(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv(ClaudeConfigDirEnv, "")
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("XDG_DATA_HOME", "")
+
+	paths := CandidateDataPaths()
+	require.GreaterOrEqual(t, len(paths), 3)
+	assert.Equal(t, filepath.Join(home, ".config", "claude", "projects"), paths[0])
+	assert.Equal(t, filepath.Join(home, ".claude", "projects"), paths[1])
+	assert.Equal(t, filepath.Join(home, ".local", "share", "claude", "projects"), paths[2])
+	if runtime.GOOS == "darwin" {
+		assert.Contains(t, paths, filepath.Join(home, "Library", "Application Support", "Claude", "projects"))
+	}
+
+	// Only existing locations are monitored; with none, the first candidate is used
+	assert.Equal(t, paths[:1], DefaultDataPaths())
+
+	legacy := filepath.Join(home, ".claude", "projects")
+	xdg := filepath.Join(home, ".config", "claude", "projects")
+	require.NoError(t, os.MkdirAll(legacy, 0755))
+	assert.Equal(t, []string{legacy}, DefaultDataPaths())
+
+	require.NoError(t, os.MkdirAll(xdg, 0755))
+	assert.Equal(t, []string{xdg, legacy}, DefaultDataPaths())
+}
+
+func TestDefaultDataPaths_Merging(t *testing.T) {
+	home := t.TempDir()
+	data := t.TempDir()
+	t.Setenv(ClaudeConfigDirEnv, "")
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("XDG_DATA_HOME", data)
+
+	legacy := filepath.Join(home, ".claude", "projects")
+	xdgData := filepath.Join(data, "claude", "projects")
+	require.NoError(t, os.MkdirAll(legacy, 0755))
+	require.NoError(t, os.MkdirAll(xdgData, 0755))
+	assert.Equal(t, []string{legacy, xdgData}, DefaultDataPaths())
+
+	// A config directory linked to the legacy one is monitored once
+	require.NoError(t, os.MkdirAll(filepath.Join(home, ".config"), 0755))
+	if err := os.Symlink(filepath.Join(home, ".claude"), filepath.Join(home, ".config", "claude")); err != nil {
+		t.Skipf("symlinks unavailable: %v", err)
+	}
+	assert.Equal(t, []string{filepath.Join(home, ".config", "claude", "projects"), xdgData}, DefaultDataPaths())
+}
+
+func TestCandidateDataPaths_WSL(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("WSL locations are only probed on Linux")
+	}
+	home := t.TempDir()
+	mounts := t.TempDir()
+	t.Setenv(ClaudeConfigDirEnv, "")
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+
+	windows := filepath.Join(mounts, "c", "Users", "alex", ".claude")
+	require.NoError(t, os.MkdirAll(windows, 0755))
+
+	oldRoot, oldDetect := wslMountRoot, runningInWSL
+	defer func() { wslMountRoot, runningInWSL = oldRoot, oldDetect }()
+	wslMountRoot = mounts
+
+	runningInWSL = func() bool { return false }
+	assert.NotContains(t, CandidateDataPaths(), filepath.Join(windows, "projects"))
+
+	runningInWSL = func() bool { return true }
+	paths := CandidateDataPaths()
+	assert.Equal(t, filepath.Join(windows, "projects"), paths[len(paths)-1])
+}
+
+func TestWaitForDataPaths(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "projects")
+	candidates := func() []string { return []string{dir} }
+
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		_ = os.MkdirAll(dir, 0755)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	assert.Equal(t, []string{dir}, WaitForDataPaths(ctx, candidates, 10*time.Millisecond))
+}
+
+func TestWaitForDataPaths_Cancelled(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing")
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	assert.Nil(t, WaitForDataPaths(ctx, func() []string { return []string{missing} }, 10*time.Millisecond))
+}
+
+func TestDiscoverFilesInPaths(t *testing.T) {
+	first := t.TempDir()
+	second := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(first, "a.jsonl"), []byte("{}"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(second, "b.jsonl"), []byte("{}"), 0644))
+
+	// Missing paths are skipped and duplicate paths only contribute once
+	files, err := DiscoverFilesInPaths([]string{first, filepath.Join(first, "missing"), second, first})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{filepath.Join(first, "a.jsonl"), filepath.Join(second, "b.jsonl")}, files)
+
+	_, err = DiscoverFilesInPaths([]string{filepath.Join(first, "missing"), filepath.Join(second, "missing")})
+	assert.Error(t, err)
+}
+
+func TestDiscoverFiles_Symlinks(t *testing.T) {
+	root := t.TempDir()
+	projects := filepath.Join(root, "projects")
+	elsewhere := filepath.Join(root, "other-drive", "proj-b")
+	require.NoError(t, os.MkdirAll(filepath.Join(projects, "proj-a"), 0755))
+	require.NoError(t, os.MkdirAll(elsewhere, 0755))
+	a := filepath.Join(projects, "proj-a", "a.jsonl")
+	require.NoError(t, os.WriteFile(a, []byte("{}\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(elsewhere, "b.jsonl"), []byte("{}\n"), 0644))
+
+	// A project linked to another drive, a link cycle, a second link to a known file and a
+	// broken link
+	if err := os.Symlink(elsewhere, filepath.Join(projects, "proj-b")); err != nil {
+		t.Skipf("symlinks unavailable: %v", err)
+	}
+	require.NoError(t, os.Symlink(projects, filepath.Join(projects, "proj-a", "loop")))
+	require.NoError(t, os.Symlink(a, filepath.Join(projects, "proj-a", "again.jsonl")))
+	require.NoError(t, os.Symlink(filepath.Join(root, "missing"), filepath.Join(projects, "broken")))
+
+	files, err := DiscoverFiles(projects)
+	require.NoError(t, err)
+	assert.Equal(t, []string{a, filepath.Join(projects, "proj-b", "b.jsonl")}, files)
+
+	// A linked data path is followed too
+	link := filepath.Join(root, "linked-projects")
+	require.NoError(t, os.Symlink(projects, link))
+	files, err = DiscoverFiles(link)
+	require.NoError(t, err)
+	assert.Len(t, files, 2)
+}
+
+func TestDiscoverFiles_UnreadableDirectory(t *testing.T) {
+	if runtime.GOOS == "windows" || os.Geteuid() == 0 {
+		t.Skip("directory permissions aren't enforced")
+	}
+	root := t.TempDir()
+	locked := filepath.Join(root, "locked")
+	require.NoError(t, os.MkdirAll(locked, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "a.jsonl"), []byte("{}\n"), 0644))
+	require.NoError(t, os.Chmod(locked, 0))
+	defer os.Chmod(locked, 0755)
+
+	files, err := DiscoverFiles(root)
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(root, "a.jsonl")}, files)
+}
+
+func TestDiscoverFiles_ExcludePatterns(t *testing.T) {
+	root := t.TempDir()
+	for _, file := range []string{"keep/a.jsonl", "archive/old/b.jsonl", "keep/c.jsonl.gz", "-tmp-scratch/d.jsonl"} {
+		path := filepath.Join(root, filepath.FromSlash(file))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte("{}\n"), 0644))
+	}
+
+	SetDiscoveryFilter(DiscoveryFilter{Exclude: testPathPatterns(t, "*/archive/*", "*.gz", root+"/-tmp-*")})
+	defer SetDiscoveryFilter(DiscoveryFilter{})
+
+	files, err := DiscoverFiles(root)
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(root, "keep", "a.jsonl")}, files)
+
+	_, err = NewPathPattern(" ")
+	assert.Error(t, err)
+}
+
+func TestDiscoverFiles_IncludePatterns(t *testing.T) {
+	root := t.TempDir()
+	for _, file := range []string{"-Users-me-work-api/a.jsonl", "-Users-me-work-api/old/b.jsonl", "-Users-me-home/c.jsonl", "-Users-me-home/d.jsonl"} {
+		path := filepath.Join(root, filepath.FromSlash(file))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte("{}\n"), 0644))
+	}
+
+	// Everything in an included directory is read unless excluded; elsewhere only matching logs
+	SetDiscoveryFilter(DiscoveryFilter{
+		Include: testPathPatterns(t, "-Users-me-work-*", "*/-Users-me-home/?.jsonl"),
+		Exclude: testPathPatterns(t, "old", "d.jsonl"),
+	})
+	defer SetDiscoveryFilter(DiscoveryFilter{})
+
+	files, err := DiscoverFiles(root)
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(root, "-Users-me-home", "c.jsonl"), filepath.Join(root, "-Users-me-work-api", "a.jsonl")}, files)
+}
+
+// testPathPatterns compiles patterns
+func testPathPatterns(t *testing.T, patterns ...string) []PathPattern {
+	t.Helper()
+	compiled := make([]PathPattern, 0, len(patterns))
+	for _, pattern := range patterns {
+		p, err := NewPathPattern(pattern)
+		require.NoError(t, err)
+		compiled = append(compiled, p)
+	}
+	return compiled
+}
+
+func TestNormalizeDataPath_WSL(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("WSL paths are only translated on Linux")
+	}
+	oldDetect := runningInWSL
+	defer func() { runningInWSL = oldDetect }()
+
+	runningInWSL = func() bool { return false }
+	assert.Equal(t, `C:\Users\alex\.claude`, NormalizeDataPath(`C:\Users\alex\.claude`))
+
+	runningInWSL = func() bool { return true }
+	assert.Equal(t, filepath.Join(wslMountRoot, "c", "Users", "alex", ".claude"), NormalizeDataPath(`C:\Users\alex\.claude`))
+	assert.Equal(t, "/home/alex/.claude", NormalizeDataPath("/home/alex/.claude"))
+}
@@ -0,0 +1,57 @@
+package fileio
+
+import (
+	"os"
+	"sort"
+	"time"
+)
+
+// FileState is the modification time and size of a file when it was loaded
+type FileState struct {
+	ModTime time.Time `json:"mod_time"`
+	Size    int64     `json:"size"`
+}
+
+// StatFiles records the current state of each file. Files that can't be read are left out,
+// so they show up as changed once they can.
+func StatFiles(files []string) map[string]FileState {
+	states := make(map[string]FileState, len(files))
+	for _, file := range files {
+		if info, err := os.Stat(file); err == nil {
+			states[file] = FileState{ModTime: info.ModTime(), Size: info.Size()}
+		}
+	}
+	return states
+}
+
+// FilesModifiedSince returns the files, in order, last modified at or after since. Files
+// that can't be read are kept, so loading them reports the error.
+func FilesModifiedSince(files []string, since time.Time) []string {
+	kept := make([]string, 0, len(files))
+	for _, file := range files {
+		if info, err := os.Stat(file); err != nil || !info.ModTime().Before(since) {
+			kept = append(kept, file)
+		}
+	}
+	return kept
+}
+
+// ChangedFiles compares the recorded file states with the current state of files and
+// returns, sorted, the files that were modified, added or removed since
+func ChangedFiles(recorded map[string]FileState, files []string) []string {
+	var changed []string
+	current := StatFiles(files)
+	for file, state := range current {
+		before, ok := recorded[file]
+		if !ok || !before.ModTime.Equal(state.ModTime) || before.Size != state.Size {
+			changed = append(changed, file)
+		}
+	}
+	for file := range recorded {
+		if _, ok := current[file]; !ok {
+			changed = append(changed, file)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
@@ -0,0 +1,48 @@
+package fileio
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangedFiles(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		return path
+	}
+
+	kept := write("kept.jsonl", "{}\n")
+	grown := write("grown.jsonl", "{}\n")
+	touched := write("touched.jsonl", "{}\n")
+	removed := write("removed.jsonl", "{}\n")
+	recorded := StatFiles([]string{kept, grown, touched, removed})
+	require.Len(t, recorded, 4)
+	assert.Empty(t, ChangedFiles(recorded, []string{kept, grown, touched, removed}))
+
+	write("grown.jsonl", "{}\n{}\n")
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(touched, later, later))
+	require.NoError(t, os.Remove(removed))
+	added := write("added.jsonl", "{}\n")
+
+	changed := ChangedFiles(recorded, []string{kept, grown, touched, added})
+	assert.Equal(t, []string{added, grown, removed, touched}, changed)
+}
+
+func TestLoadUsageEntries_RecordsFileStates(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "session.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte(`{"type":"user","timestamp":"2025-06-01T10:00:00Z"}`+"\n"), 0644))
+
+	result, err := LoadUsageEntries(LoadUsageEntriesOptions{DataPath: dir})
+	require.NoError(t, err)
+	require.Contains(t, result.Metadata.FileStates, path)
+	assert.Empty(t, ChangedFiles(result.Metadata.FileStates, []string{path}))
+}
@@ -0,0 +1,115 @@
+package fileio
+
+import (
+	"strings"
+	"time"
+
+	"github.com/penwyp/claudecat/models"
+)
+
+// EntryFilter selects usage entries by project, model and time. Empty fields match every entry.
+type EntryFilter struct {
+	Projects []string  // Project names, matched case-insensitively as substrings
+	Models   []string  // Model names, aliases or families such as "opus", matched against normalized names
+	Sources  []string  // Sources such as "claude" or "codex", matched case-insensitively
+	Tags     []string  // Cost allocation tags, matched case-insensitively; "untagged" matches entries without one
+	Since    time.Time // Inclusive start, zero for no limit
+	Until    time.Time // Exclusive end, zero for no limit
+}
+
+// IsEmpty reports whether the filter matches every entry
+func (f EntryFilter) IsEmpty() bool {
+	return len(f.Projects) == 0 && len(f.Models) == 0 && len(f.Sources) == 0 && len(f.Tags) == 0 &&
+		f.Since.IsZero() && f.Until.IsZero()
+}
+
+// WithoutTimeRange returns the filter without its time range, for callers that apply the
+// range to something other than entries, such as session blocks
+func (f EntryFilter) WithoutTimeRange() EntryFilter {
+	f.Since, f.Until = time.Time{}, time.Time{}
+	return f
+}
+
+// Matches reports whether the entry passes every part of the filter
+func (f EntryFilter) Matches(entry models.UsageEntry) bool {
+	if !f.Since.IsZero() && entry.Timestamp.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !entry.Timestamp.Before(f.Until) {
+		return false
+	}
+	if len(f.Projects) > 0 && !matchesProject(f.Projects, entry.Project) {
+		return false
+	}
+	if len(f.Models) > 0 && !matchesModel(f.Models, entry.Model) {
+		return false
+	}
+	if len(f.Sources) > 0 && !matchesSource(f.Sources, entry.Source) {
+		return false
+	}
+	if len(f.Tags) > 0 && !matchesTag(f.Tags, entry.Tag) {
+		return false
+	}
+	return true
+}
+
+// Apply returns the entries matching the filter, or entries itself when the filter is empty
+func (f EntryFilter) Apply(entries []models.UsageEntry) []models.UsageEntry {
+	if f.IsEmpty() {
+		return entries
+	}
+
+	filtered := make([]models.UsageEntry, 0, len(entries))
+	for _, entry := range entries {
+		if f.Matches(entry) {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered
+}
+
+func matchesProject(projects []string, project string) bool {
+	project = strings.ToLower(project)
+	for _, p := range projects {
+		if p != "" && strings.Contains(project, strings.ToLower(p)) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesModel(names []string, model string) bool {
+	model = strings.ToLower(models.NormalizeModelName(model))
+	for _, name := range names {
+		if name == "" {
+			continue
+		}
+		// Aliases resolve to full names; anything else matches as part of the name
+		if strings.Contains(model, strings.ToLower(models.NormalizeModelName(name))) ||
+			strings.Contains(model, strings.ToLower(name)) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesSource(sources []string, source string) bool {
+	for _, s := range sources {
+		if strings.EqualFold(s, source) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesTag(tags []string, tag string) bool {
+	if tag == "" {
+		tag = models.UntaggedAllocation
+	}
+	for _, t := range tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,52 @@
+package fileio
+
+import (
+	"testing"
+	"time"
+
+	"github.com/penwyp/claudecat/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEntryFilter(t *testing.T) {
+	day := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	entries := []models.UsageEntry{
+		{Timestamp: day.Add(1 * time.Hour), Project: "claudecat", Model: "claude-opus-4-20250514", Source: models.SourceClaude},
+		{Timestamp: day.Add(2 * time.Hour), Project: "claudecat", Model: "claude-sonnet-4-20250514", Source: models.SourceClaude},
+		{Timestamp: day.Add(26 * time.Hour), Project: "MoviePilot", Model: "claude-opus-4-20250514", Source: models.SourceClaude},
+		{Timestamp: day.Add(50 * time.Hour), Project: "MoviePilot", Model: "us.anthropic.claude-3-5-haiku-20241022-v1:0", Source: models.SourceBedrock},
+	}
+
+	tests := []struct {
+		name   string
+		filter EntryFilter
+		want   []int
+	}{
+		{"empty", EntryFilter{}, []int{0, 1, 2, 3}},
+		{"project substring ignoring case", EntryFilter{Projects: []string{"movie"}}, []int{2, 3}},
+		{"model family", EntryFilter{Models: []string{"opus"}}, []int{0, 2}},
+		{"wrapped model", EntryFilter{Models: []string{"haiku"}}, []int{3}},
+		{"several models", EntryFilter{Models: []string{"sonnet", "haiku"}}, []int{1, 3}},
+		{"since inclusive", EntryFilter{Since: day.Add(2 * time.Hour)}, []int{1, 2, 3}},
+		{"until exclusive", EntryFilter{Until: day.Add(26 * time.Hour)}, []int{0, 1}},
+		{"source ignoring case", EntryFilter{Sources: []string{"Bedrock"}}, []int{3}},
+		{"combined", EntryFilter{Projects: []string{"claudecat", "MoviePilot"}, Models: []string{"opus"}, Since: day.Add(24 * time.Hour)}, []int{2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []int
+			for i, entry := range entries {
+				if tt.filter.Matches(entry) {
+					got = append(got, i)
+				}
+			}
+			assert.Equal(t, tt.want, got)
+			assert.Len(t, tt.filter.Apply(entries), len(tt.want))
+		})
+	}
+
+	filter := EntryFilter{Models: []string{"opus"}, Since: day, Until: day.Add(time.Hour)}
+	assert.Equal(t, EntryFilter{Models: []string{"opus"}}, filter.WithoutTimeRange())
+	assert.True(t, EntryFilter{}.IsEmpty())
+	assert.False(t, filter.IsEmpty())
+}
@@ -0,0 +1,125 @@
+package fileio
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/penwyp/claudecat/models"
+)
+
+const (
+	// geminiDefaultModel prices messages whose record doesn't name a model, which is what
+	// Gemini CLI runs unless configured otherwise
+	geminiDefaultModel = "gemini-2.5-pro"
+
+	// geminiProjectPrefix starts the project of Gemini CLI sessions, which only record a hash
+	// of the project directory
+	geminiProjectPrefix = "gemini-"
+)
+
+// GeminiDataPaths returns the directory Gemini CLI keeps its per-project session recordings
+// in: ~/.gemini/tmp
+func GeminiDataPaths() []string {
+	homeDir, _ := os.UserHomeDir()
+	return []string{filepath.Join(homeDir, ".gemini", "tmp")}
+}
+
+// IsGeminiLogFile reports whether path names a Gemini CLI session recording. Gemini CLI
+// writes them as <project hash>/chats/session-<time>-<id>.json.
+func IsGeminiLogFile(path string) bool {
+	base := strings.ToLower(filepath.Base(path))
+	return strings.HasPrefix(base, "session-") && strings.HasSuffix(base, ".json") &&
+		filepath.Base(filepath.Dir(path)) == "chats"
+}
+
+// geminiSource reads Gemini CLI session recordings. Each one is a JSON document rewritten
+// after every message:
+//
+//	{"sessionId":...,"projectHash":...,"messages":[
+//	  {"id":...,"timestamp":...,"type":"gemini","model":"gemini-2.5-pro",
+//	   "tokens":{"input":...,"output":...,"cached":...,"thoughts":...,"tool":...,"total":...}}]}
+type geminiSource struct{}
+
+func (geminiSource) Name() string { return models.SourceGemini }
+
+func (geminiSource) DataPaths() []string { return GeminiDataPaths() }
+
+func (geminiSource) Owns(path string) bool { return IsGeminiLogFile(path) }
+
+func (geminiSource) NewExtractor(string) UsageExtractor { return extractGeminiEntry }
+
+// Records returns the messages of a recording, each carrying the session and project hash
+// of the recording
+func (geminiSource) Records(document map[string]interface{}) []map[string]interface{} {
+	messages, _ := document["messages"].([]interface{})
+	records := make([]map[string]interface{}, 0, len(messages))
+	for _, message := range messages {
+		record, ok := message.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, field := range []string{"sessionId", "projectHash"} {
+			if _, ok := record[field]; !ok {
+				record[field] = document[field]
+			}
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// extractGeminiEntry extracts the usage of a model response of a Gemini CLI recording. The
+// input count includes the cached input, while thinking tokens come on top of the output.
+// Tool use prompt tokens are billed as input.
+func extractGeminiEntry(data map[string]interface{}) (models.UsageEntry, bool) {
+	var entry models.UsageEntry
+	if data["type"] != "gemini" {
+		return entry, false
+	}
+	tokens, ok := data["tokens"].(map[string]interface{})
+	if !ok {
+		return entry, false
+	}
+	timestampStr, _ := data["timestamp"].(string)
+	timestamp, err := time.Parse(time.RFC3339, timestampStr)
+	if err != nil {
+		return entry, false
+	}
+
+	count := func(field string) int {
+		if val, ok := tokens[field].(float64); ok && val > 0 {
+			return int(val)
+		}
+		return 0
+	}
+	input := count("input")
+	cached := min(count("cached"), input)
+	entry = models.UsageEntry{
+		Timestamp:       timestamp,
+		Model:           geminiDefaultModel,
+		InputTokens:     input - cached + count("tool"),
+		CacheReadTokens: cached,
+		OutputTokens:    count("output"),
+		ThinkingTokens:  count("thoughts"),
+	}
+	entry.TotalTokens = entry.InputTokens + entry.OutputTokens + entry.CacheReadTokens + entry.ThinkingTokens
+	if entry.TotalTokens == 0 {
+		return entry, false
+	}
+
+	if model, ok := data["model"].(string); ok && model != "" {
+		entry.Model = model
+	}
+	entry.SessionID, _ = data["sessionId"].(string)
+	if hash, ok := data["projectHash"].(string); ok && hash != "" {
+		entry.Project = geminiProjectPrefix + hash[:min(len(hash), 8)]
+	}
+	// Message IDs are unique within a session
+	if id, ok := data["id"].(string); ok && id != "" && entry.SessionID != "" {
+		entry.MessageID = id
+		entry.RequestID = entry.SessionID
+	}
+	return entry, true
+}
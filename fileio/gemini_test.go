@@ -0,0 +1,98 @@
+package fileio
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/penwyp/claudecat/models"
+	"github.com/penwyp/claudecat/models/pricing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const geminiTestRecording = `{
+  "sessionId": "5f1c2a9e-8b7d-4c3e-9a1f-2b3c4d5e6f70",
+  "projectHash": "9a8b7c6d5e4f30211f2e3d4c5b6a79880a1b2c3d4e5f60718293a4b5c6d7e8f9",
+  "startTime": "2025-09-15T10:00:00.000Z",
+  "lastUpdated": "2025-09-15T10:05:00.000Z",
+  "messages": [
+    {"id": "u1", "timestamp": "2025-09-15T10:00:00.000Z", "type": "user", "content": "hello"},
+    {"id": "g1", "timestamp": "2025-09-15T10:00:05.000Z", "type": "gemini", "content": "hi", "model": "gemini-2.5-pro",
+     "tokens": {"input": 1000, "output": 200, "cached": 400, "thoughts": 50, "tool": 10, "total": 1260}},
+    {"id": "g2", "timestamp": "2025-09-15T10:05:00.000Z", "type": "gemini", "content": "", "tokens": null}
+  ]
+}`
+
+func writeGeminiRecording(t *testing.T, dir, content string) string {
+	chatsDir := filepath.Join(dir, "9a8b7c6d", "chats")
+	require.NoError(t, os.MkdirAll(chatsDir, 0755))
+	path := filepath.Join(chatsDir, "session-2025-09-15T10-00-5f1c2a9e.json")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestIsGeminiLogFile(t *testing.T) {
+	assert.True(t, IsGeminiLogFile("/home/demo/.gemini/tmp/9a8b/chats/session-2025-09-15T10-00-5f1c2a9e.json"))
+	assert.False(t, IsGeminiLogFile("/home/demo/.gemini/tmp/9a8b/logs.json"))
+	assert.False(t, IsGeminiLogFile("/home/demo/.gemini/settings.json"))
+}
+
+func TestLoadUsageEntries_GeminiRecording(t *testing.T) {
+	dir := t.TempDir()
+	writeGeminiRecording(t, dir, geminiTestRecording)
+
+	files, err := DiscoverFiles(dir)
+	require.NoError(t, err)
+	assert.Len(t, files, 1, "recordings are discovered")
+
+	result, err := LoadUsageEntries(LoadUsageEntriesOptions{
+		DataPath:        dir,
+		Mode:            models.CostModeCalculated,
+		PricingProvider: pricing.NewDefaultProvider(),
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Entries, 1)
+
+	entry := result.Entries[0]
+	assert.Equal(t, "gemini-2.5-pro", entry.Model)
+	assert.Equal(t, "gemini-9a8b7c6d", entry.Project)
+	assert.Equal(t, models.SourceGemini, entry.Source)
+	assert.Equal(t, "5f1c2a9e-8b7d-4c3e-9a1f-2b3c4d5e6f70", entry.SessionID)
+	assert.Equal(t, 610, entry.InputTokens, "cached input is split out and tool prompts added")
+	assert.Equal(t, 400, entry.CacheReadTokens)
+	assert.Equal(t, 200, entry.OutputTokens)
+	assert.Equal(t, 50, entry.ThinkingTokens)
+	assert.Equal(t, 1260, entry.TotalTokens)
+	// 610 input at $1.25, 400 cached at $0.125 and 250 output at $10 per million
+	assert.InDelta(t, 0.0007625+0.00005+0.0025, entry.CostUSD, 1e-9)
+}
+
+func TestLoadUsageEntries_GeminiRecordingBeingWritten(t *testing.T) {
+	dir := t.TempDir()
+	writeGeminiRecording(t, dir, geminiTestRecording[:200])
+
+	result, err := LoadUsageEntries(LoadUsageEntriesOptions{DataPath: dir, Mode: models.CostModeCalculated})
+	require.NoError(t, err)
+	assert.Empty(t, result.Entries)
+	assert.Empty(t, result.Metadata.ProcessingErrors)
+}
+
+func TestTailer_GeminiRecording(t *testing.T) {
+	dir := t.TempDir()
+	path := writeGeminiRecording(t, dir, `{"sessionId":"s1","projectHash":"abc","messages":[]}`)
+
+	var got []models.UsageEntry
+	tailer := NewTailer([]string{dir}, TailOptions{})
+	require.NoError(t, tailer.Poll(func(entry models.UsageEntry) { got = append(got, entry) }))
+	assert.Empty(t, got)
+
+	// Gemini CLI rewrites the recording after each message
+	require.NoError(t, os.WriteFile(path, []byte(geminiTestRecording), 0644))
+	require.NoError(t, tailer.Poll(func(entry models.UsageEntry) { got = append(got, entry) }))
+	require.Len(t, got, 1)
+	assert.Equal(t, "g1", got[0].MessageID)
+
+	require.NoError(t, tailer.Poll(func(entry models.UsageEntry) { got = append(got, entry) }))
+	assert.Len(t, got, 1, "unchanged recordings are not read again")
+}
@@ -0,0 +1,94 @@
+package fileio
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/penwyp/claudecat/cache"
+	"github.com/penwyp/claudecat/logging"
+)
+
+// identityHeadBytes is how much of the start of a file its identity covers. Logs begin with
+// lines unique to their session, so this is enough to tell them apart.
+const identityHeadBytes = 8 << 10
+
+// FileIdentity returns an identity of the file at path that a rename doesn't change: a
+// SHA-256 hash of its first bytes and its size, which is passed in so it matches the size a
+// summary was recorded with
+func FileIdentity(path string, size int64) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	h := sha256.New()
+	if _, err := io.CopyN(h, file, min(size, identityHeadBytes)); err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	fmt.Fprintf(h, ":%d", size)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// identityFinder is implemented by cache stores that can look summaries up by identity
+type identityFinder interface {
+	FindByIdentity(identity string) (*cache.FileSummary, error)
+}
+
+// renamedFileSummary recognizes a file new to the cache as a log the cache knows under
+// another path. It returns the summary to store for the file, or nil when it isn't known.
+// A file whose original is gone, or now holds other content, was renamed or rotated, and the
+// original's summary moves to it; one whose original still starts with the same content is
+// a copy, marked so that its entries aren't counted twice.
+func renamedFileSummary(absPath, filePath string, fileInfo os.FileInfo, opts LoadUsageEntriesOptions) *cache.FileSummary {
+	finder, ok := opts.CacheStore.(identityFinder)
+	if !ok {
+		return nil
+	}
+	identity, err := FileIdentity(filePath, fileInfo.Size())
+	if err != nil {
+		return nil
+	}
+	known, err := finder.FindByIdentity(identity)
+	if err != nil || known.AbsolutePath == absPath || known.HasNoAssistantMessages ||
+		!summaryCostModeMatches(known, opts) || !summaryValidationMatches(known, opts) {
+		return nil
+	}
+
+	summary := *known
+	summary.Path = filePath
+	summary.AbsolutePath = absPath
+	summary.ModTime = fileInfo.ModTime()
+	summary.ProcessedAt = time.Now()
+	summary.DuplicateOf = ""
+	if hasIdentity(known.AbsolutePath, known.FileSize, identity) {
+		logging.LogDebugf("%s is a copy of %s, not counting its entries again", filepath.Base(filePath), known.AbsolutePath)
+		summary.DuplicateOf = known.AbsolutePath
+		return &summary
+	}
+
+	logging.LogDebugf("%s was renamed to %s, reusing its summary", known.AbsolutePath, filepath.Base(filePath))
+	if !opts.ReadOnly {
+		if err := opts.CacheStore.InvalidateFileSummary(known.AbsolutePath); err != nil {
+			logging.LogWarnf("Failed to invalidate cache for %s: %v", known.AbsolutePath, err)
+		}
+	}
+	return &summary
+}
+
+// copiesExistingFile reports whether summary is of a copy of a log that still exists
+func copiesExistingFile(summary *cache.FileSummary) bool {
+	return summary.DuplicateOf != "" && hasIdentity(summary.DuplicateOf, summary.FileSize, summary.Identity)
+}
+
+// hasIdentity reports whether the first size bytes of the file at path still have identity,
+// which holds while the file is only appended to
+func hasIdentity(path string, size int64, identity string) bool {
+	current, err := FileIdentity(path, size)
+	return err == nil && current == identity
+}
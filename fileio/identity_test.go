@@ -0,0 +1,87 @@
+package fileio
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/penwyp/claudecat/cache"
+	"github.com/penwyp/claudecat/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileIdentity(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.jsonl")
+	require.NoError(t, os.WriteFile(a, []byte("first\n"), 0644))
+	before, err := FileIdentity(a, 6)
+	require.NoError(t, err)
+
+	// Appending changes the identity of the whole file but not of the bytes it had
+	require.NoError(t, os.WriteFile(a, []byte("first\nsecond\n"), 0644))
+	same, err := FileIdentity(a, 6)
+	require.NoError(t, err)
+	assert.Equal(t, before, same)
+	grown, err := FileIdentity(a, 13)
+	require.NoError(t, err)
+	assert.NotEqual(t, before, grown)
+
+	_, err = FileIdentity(a, 100)
+	assert.Error(t, err)
+}
+
+func TestLoadUsageEntries_RenamedFiles(t *testing.T) {
+	dataDir := t.TempDir()
+	projectDir := filepath.Join(dataDir, "proj")
+	require.NoError(t, os.MkdirAll(projectDir, 0755))
+	store, err := cache.NewFileBasedSummaryCache(t.TempDir())
+	require.NoError(t, err)
+
+	line := `{"type":"assistant","timestamp":"2025-06-01T10:00:00Z","requestId":"%s","message":{"id":"%s","model":"claude-sonnet-4-20250514","usage":{"input_tokens":100,"output_tokens":50}}}` + "\n"
+	write := func(name, content string) string {
+		path := filepath.Join(projectDir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		return path
+	}
+	load := func() (int, *LoadMetadata) {
+		result, err := LoadUsageEntries(LoadUsageEntriesOptions{DataPath: dataDir, Mode: models.CostModeCalculated, CacheStore: store})
+		require.NoError(t, err)
+		total := 0
+		for _, entry := range result.Entries {
+			total += entry.TotalTokens
+		}
+		return total, &result.Metadata
+	}
+
+	original := write("session.jsonl", fmt.Sprintf(line, "r1", "m1"))
+	total, _ := load()
+	assert.Equal(t, 150, total)
+
+	// A renamed log is served from the summary of its old path
+	renamed := filepath.Join(projectDir, "session-renamed.jsonl")
+	require.NoError(t, os.Rename(original, renamed))
+	total, metadata := load()
+	assert.Equal(t, 150, total)
+	assert.Equal(t, 1, metadata.CacheStats.Hits)
+	assert.False(t, store.HasFileSummary(original))
+	assert.True(t, store.HasFileSummary(renamed))
+
+	// A copy isn't counted while the log it copies still exists
+	content, err := os.ReadFile(renamed)
+	require.NoError(t, err)
+	backup := write("session-backup.jsonl", string(content))
+	total, _ = load()
+	assert.Equal(t, 150, total)
+	total, _ = load()
+	assert.Equal(t, 150, total)
+
+	// Once the copied log is rotated away and replaced, both are counted
+	write("session-renamed.jsonl", fmt.Sprintf(line, "r2", "m2"))
+	total, _ = load()
+	assert.Equal(t, 300, total)
+	summary, err := store.GetFileSummary(backup)
+	require.NoError(t, err)
+	assert.NotEmpty(t, summary.DuplicateOf)
+}
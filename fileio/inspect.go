@@ -0,0 +1,88 @@
+package fileio
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+
+	"github.com/bytedance/sonic"
+)
+
+// FileInspection summarizes how well a usage file parses
+type FileInspection struct {
+	Path             string `json:"path"`
+	Lines            int    `json:"lines"`              // Non-empty lines
+	UsageEntries     int    `json:"usage_entries"`      // Lines holding token usage
+	InvalidLines     int    `json:"invalid_lines"`      // Lines that are not valid JSON
+	FirstInvalidLine int    `json:"first_invalid_line"` // Line number of the first invalid line, 0 if none
+	FirstError       string `json:"first_error,omitempty"`
+}
+
+// InspectUsageFile parses every line of a usage file without loading its entries, counting
+// usage records and lines that are not valid JSON. An unterminated last line that fails to
+// parse is still being written and is not counted as invalid.
+func InspectUsageFile(path string) (FileInspection, error) {
+	inspection := FileInspection{Path: path}
+
+	file, err := OpenUsageFile(path)
+	if err != nil {
+		return inspection, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	// A JSON document log counts as a single line
+	if documents, ok := SourceFor(path).(DocumentSource); ok {
+		inspection.Lines = 1
+		records, _, err := readDocumentRecords(file, documents)
+		if err != nil {
+			inspection.InvalidLines = 1
+			inspection.FirstInvalidLine = 1
+			inspection.FirstError = err.Error()
+			return inspection, nil
+		}
+		extract := documents.NewExtractor(path)
+		for _, record := range records {
+			if _, hasUsage := extract(record); hasUsage {
+				inspection.UsageEntries++
+			}
+		}
+		return inspection, nil
+	}
+
+	var scanned int64
+	var terminated bool
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024) // 10MB max line size
+	scanner.Split(scanLinesTracked(&scanned, &terminated))
+
+	lineNumber := 0
+	extract := newUsageExtractor(path)
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Bytes()
+		if strings.TrimSpace(string(line)) == "" {
+			continue
+		}
+		inspection.Lines++
+
+		var data map[string]interface{}
+		if err := sonic.Unmarshal(line, &data); err != nil {
+			if !terminated {
+				continue
+			}
+			inspection.InvalidLines++
+			if inspection.FirstInvalidLine == 0 {
+				inspection.FirstInvalidLine = lineNumber
+				inspection.FirstError = err.Error()
+			}
+			continue
+		}
+		if _, hasUsage := extract(data); hasUsage {
+			inspection.UsageEntries++
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return inspection, fmt.Errorf("error reading file: %w", err)
+	}
+	return inspection, nil
+}
@@ -0,0 +1,33 @@
+package fileio
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInspectUsageFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	content := `{"type":"assistant","timestamp":"2025-06-01T10:00:00Z","requestId":"r1","message":{"id":"m1","model":"claude-sonnet-4-20250514","usage":{"input_tokens":100,"output_tokens":50}}}
+{"type":"user","timestamp":"2025-06-01T10:00:01Z"}
+
+{"type":"assistant", broken
+not json
+{"type":"assistant","timestamp":"2025-06-01T10:05:00Z","requestId":"r2","message":{"id":"m2","model":"claude-sonnet-4-20250514","usage":{"input_tokens":10,"output_tokens":5}}}
+{"type":"assistant","timestamp":"2025-06-01T10:06:00Z","requ`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+	inspection, err := InspectUsageFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 6, inspection.Lines)
+	assert.Equal(t, 2, inspection.UsageEntries)
+	assert.Equal(t, 2, inspection.InvalidLines, "the unterminated last line is still being written")
+	assert.Equal(t, 4, inspection.FirstInvalidLine)
+	assert.NotEmpty(t, inspection.FirstError)
+
+	_, err = InspectUsageFile(filepath.Join(t.TempDir(), "missing.jsonl"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,101 @@
+package fileio
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/penwyp/claudecat/logging"
+	"github.com/penwyp/claudecat/models"
+)
+
+const (
+	// loadMemoryPerByte is the peak memory of a load per byte of JSONL read. Parsed entries
+	// keep the line they were parsed from alive, and concurrent workers hold several files'
+	// results at once; measured at about 1.6 on Claude Code logs.
+	loadMemoryPerByte = 1.6
+
+	// rawMemoryPerByte is the memory raw records add per byte of JSONL when every record is
+	// kept rather than the few a RawFilter selects
+	rawMemoryPerByte = 1.3
+)
+
+// EstimateLoadMemory estimates the peak memory in bytes of loading totalBytes of JSONL files
+// with opts
+func EstimateLoadMemory(totalBytes int64, opts LoadUsageEntriesOptions) int64 {
+	factor := loadMemoryPerByte
+	if opts.IncludeRaw && opts.RawFilter == nil {
+		factor += rawMemoryPerByte
+	}
+	return int64(float64(totalBytes) * factor)
+}
+
+// exceedsMemoryBudget reports whether loading files with opts is estimated to need more than
+// opts.MemoryBudget, along with the data size and the estimate
+func exceedsMemoryBudget(files []string, opts LoadUsageEntriesOptions) (bool, int64, int64) {
+	if opts.MemoryBudget <= 0 {
+		return false, 0, 0
+	}
+	_, totalBytes := fileSizeIndex(files)
+	estimate := EstimateLoadMemory(totalBytes, opts)
+	return estimate > opts.MemoryBudget, totalBytes, estimate
+}
+
+// loadWithinBudget loads files one at a time through StreamUsageEntries and keeps compact
+// copies of the entries, so a history too large for the memory budget still loads. Raw
+// records are dropped, which turns off limit detection.
+func loadWithinBudget(ctx context.Context, files []string, opts LoadUsageEntriesOptions) (*LoadUsageEntriesResult, error) {
+	opts.Files = files
+	opts.IncludeRaw = false
+
+	fileStates := StatFiles(files)
+	interner := make(map[string]string)
+	var entries []models.UsageEntry
+	metadata, err := StreamUsageEntries(opts, func(entry models.UsageEntry) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		entries = append(entries, compactEntry(entry, interner))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to stream usage entries: %w", err)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Timestamp.Before(entries[j].Timestamp)
+	})
+	metadata.FileStates = fileStates
+	metadata.MemoryLimited = true
+	return &LoadUsageEntriesResult{Entries: entries, Metadata: *metadata}, nil
+}
+
+// compactEntry copies the strings of an entry, which otherwise share memory with the whole
+// line they were parsed from. Values that repeat across entries, like models and projects,
+// are shared through interner.
+func compactEntry(entry models.UsageEntry, interner map[string]string) models.UsageEntry {
+	intern := func(s string) string {
+		if shared, ok := interner[s]; ok {
+			return shared
+		}
+		s = strings.Clone(s)
+		interner[s] = s
+		return s
+	}
+	entry.Model = intern(entry.Model)
+	entry.CostSource = intern(entry.CostSource)
+	entry.SessionID = intern(entry.SessionID)
+	entry.Project = intern(entry.Project)
+	entry.Source = intern(entry.Source)
+	entry.Tag = intern(entry.Tag)
+	entry.MessageID = strings.Clone(entry.MessageID)
+	entry.RequestID = strings.Clone(entry.RequestID)
+	return entry
+}
+
+// logMemoryLimited warns that a load switched to streaming to stay within the budget
+func logMemoryLimited(totalBytes, estimate, budget int64) {
+	logging.LogWarnf("Loading %d MB of usage data would need about %d MB, over the memory budget of %d MB (performance.max_memory); loading files one at a time without limit detection",
+		totalBytes>>20, estimate>>20, budget>>20)
+}
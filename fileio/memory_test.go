@@ -0,0 +1,60 @@
+package fileio
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"unsafe"
+
+	"github.com/penwyp/claudecat/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateLoadMemory(t *testing.T) {
+	assert.Equal(t, int64(160), EstimateLoadMemory(100, LoadUsageEntriesOptions{}))
+	assert.Equal(t, int64(290), EstimateLoadMemory(100, LoadUsageEntriesOptions{IncludeRaw: true}))
+
+	filtered := LoadUsageEntriesOptions{IncludeRaw: true, RawFilter: func(map[string]interface{}) bool { return false }}
+	assert.Equal(t, int64(160), EstimateLoadMemory(100, filtered), "filtered raw records are few")
+}
+
+func TestLoadUsageEntries_StreamsOverMemoryBudget(t *testing.T) {
+	dir := t.TempDir()
+	for f := 0; f < 2; f++ {
+		var content string
+		for i := 0; i < 3; i++ {
+			content += fmt.Sprintf(`{"type":"assistant","timestamp":"2025-06-01T1%d:0%d:00Z","requestId":"r%d%d","message":{"id":"m%d%d","model":"claude-sonnet-4-20250514","usage":{"input_tokens":100,"output_tokens":50}}}`+"\n", i, f, f, i, f, i)
+		}
+		content += `{"type":"system","timestamp":"2025-06-01T12:00:00Z","content":"Claude usage limit reached"}` + "\n"
+		require.NoError(t, os.WriteFile(filepath.Join(dir, fmt.Sprintf("s%d.jsonl", f)), []byte(content), 0644))
+	}
+	opts := LoadUsageEntriesOptions{DataPath: dir, IncludeRaw: true}
+
+	full, err := LoadUsageEntries(opts)
+	require.NoError(t, err)
+	assert.False(t, full.Metadata.MemoryLimited)
+	assert.NotEmpty(t, full.RawEntries)
+
+	opts.MemoryBudget = 1
+	limited, err := LoadUsageEntries(opts)
+	require.NoError(t, err)
+	assert.True(t, limited.Metadata.MemoryLimited)
+	assert.Nil(t, limited.RawEntries, "raw records are dropped")
+	assert.Equal(t, full.Entries, limited.Entries)
+	assert.Equal(t, full.Metadata.FileStates, limited.Metadata.FileStates)
+	assert.Equal(t, 2, limited.Metadata.FilesProcessed)
+}
+
+func TestCompactEntry(t *testing.T) {
+	interner := make(map[string]string)
+	line := `claude-sonnet-4-20250514 m1`
+	first := compactEntry(models.UsageEntry{Model: line[:24], MessageID: line[25:]}, interner)
+	second := compactEntry(models.UsageEntry{Model: "claude-sonnet-4-20250514"}, interner)
+
+	assert.Equal(t, "claude-sonnet-4-20250514", first.Model)
+	assert.Equal(t, "m1", first.MessageID)
+	assert.Same(t, unsafe.StringData(first.Model), unsafe.StringData(second.Model), "repeated values are shared")
+	assert.NotSame(t, unsafe.StringData(line), unsafe.StringData(first.Model), "values no longer point into the line")
+}
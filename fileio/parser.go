@@ -2,8 +2,8 @@ package fileio
 
 import (
 	"bufio"
+	"context"
 	"fmt"
-	"os"
 	"path/filepath"
 	"strings"
 	"time"
@@ -14,7 +14,13 @@ import (
 
 // hasAssistantMessages checks if a file contains assistant messages
 func hasAssistantMessages(filePath string) bool {
-	file, err := os.Open(filePath)
+	if source := SourceFor(filePath); source != nil {
+		if _, ok := source.(claudeSource); !ok {
+			return sourceHasUsage(filePath, source)
+		}
+	}
+
+	file, err := OpenUsageFile(filePath)
 	if err != nil {
 		return false
 	}
@@ -42,7 +48,7 @@ func hasAssistantMessages(filePath string) bool {
 			if message, ok := data["message"].(map[string]interface{}); ok {
 				if usage, ok := message["usage"].(map[string]interface{}); ok {
 					// Check if usage has any tokens
-					for _, field := range []string{"input_tokens", "output_tokens", "cache_creation_input_tokens", "cache_read_input_tokens"} {
+					for _, field := range []string{"input_tokens", "output_tokens", "cache_creation_input_tokens", "cache_read_input_tokens", "thinking_tokens", "reasoning_tokens"} {
 						if val, ok := usage[field]; ok {
 							if tokens, ok := val.(float64); ok && tokens > 0 {
 								return true
@@ -115,8 +121,7 @@ func convertRawToUsageEntry(data map[string]interface{}, mode models.CostMode) (
 	}
 
 	// Calculate cost
-	pricing := models.GetPricing(entry.Model)
-	entry.CostUSD = entry.CalculateCost(pricing)
+	applyEntryCost(&entry, data, mode, nil)
 
 	// Don't normalize model name in tests - preserve original
 	// entry.NormalizeModel()
@@ -124,6 +129,64 @@ func convertRawToUsageEntry(data map[string]interface{}, mode models.CostMode) (
 	return entry, nil
 }
 
+// extractLogCost returns the costUSD reported in a log entry, if any
+func extractLogCost(data map[string]interface{}) (float64, bool) {
+	for _, field := range []string{"costUSD", "cost_usd"} {
+		if cost, ok := data[field].(float64); ok {
+			return cost, true
+		}
+	}
+	return 0, false
+}
+
+// applyEntryCost sets the entry's cost according to the cost mode and records its source.
+// Display mode uses the logged costUSD only, calculate mode ignores it, and auto mode
+// prefers it when present and falls back to token pricing otherwise.
+func applyEntryCost(entry *models.UsageEntry, data map[string]interface{}, mode models.CostMode, provider models.PricingProvider) {
+	pricing := lookupPricing(entry.Model, provider)
+	entry.CacheSavingsUSD = entry.CalculateCacheSavings(pricing)
+
+	if mode != models.CostModeCalculated {
+		if cost, ok := extractLogCost(data); ok {
+			entry.CostUSD = cost
+			entry.CostSource = models.CostSourceLog
+			return
+		}
+		if mode == models.CostModeDisplay {
+			entry.CostUSD = 0
+			entry.CostSource = models.CostSourceLog
+			return
+		}
+	}
+
+	entry.CostUSD = entry.CalculateCost(pricing)
+	entry.CostSource = models.CostSourceCalculated
+}
+
+// lookupPricing returns the provider's pricing for model, falling back to the built-in prices
+func lookupPricing(model string, provider models.PricingProvider) models.ModelPricing {
+	if provider != nil {
+		if pricing, err := provider.GetPricing(context.Background(), model); err == nil {
+			return pricing
+		}
+	}
+	return models.GetPricing(model)
+}
+
+// applyCacheSavings sets the cache savings of entries rebuilt from summaries, which only
+// keep costs
+func applyCacheSavings(entries []models.UsageEntry, provider models.PricingProvider) {
+	pricing := make(map[string]models.ModelPricing)
+	for i := range entries {
+		p, ok := pricing[entries[i].Model]
+		if !ok {
+			p = lookupPricing(entries[i].Model, provider)
+			pricing[entries[i].Model] = p
+		}
+		entries[i].CacheSavingsUSD = entries[i].CalculateCacheSavings(p)
+	}
+}
+
 // extractUsageEntry extracts usage entry from JSON data
 func extractUsageEntry(data map[string]interface{}) (models.UsageEntry, bool) {
 	var entry models.UsageEntry
@@ -172,6 +235,9 @@ func extractUsageEntry(data map[string]interface{}) (models.UsageEntry, bool) {
 				if val, ok := usage["cache_read_input_tokens"]; ok {
 					entry.CacheReadTokens = int(val.(float64))
 				}
+				if extractThinkingTokens(usage, &entry) {
+					hasUsage = true
+				}
 			}
 		}
 	} else if typeStr == "message" || !hasType {
@@ -195,16 +261,55 @@ func extractUsageEntry(data map[string]interface{}) (models.UsageEntry, bool) {
 			if val, ok := usage["cache_read_tokens"]; ok {
 				entry.CacheReadTokens = int(val.(float64))
 			}
+			if extractThinkingTokens(usage, &entry) {
+				hasUsage = true
+			}
 		}
 	}
 
-	// Extract request ID (at top level for both message types)
+	// Extract request ID (at top level for both message types; Claude Code writes requestId)
 	if requestID, ok := data["request_id"].(string); ok {
 		entry.RequestID = requestID
+	} else if requestID, ok := data["requestId"].(string); ok {
+		entry.RequestID = requestID
+	}
+
+	// Extract Claude Code session ID
+	if sessionID, ok := data["sessionId"].(string); ok {
+		entry.SessionID = sessionID
 	}
 
 	// Calculate total tokens
-	entry.TotalTokens = entry.InputTokens + entry.OutputTokens + entry.CacheCreationTokens + entry.CacheReadTokens
+	entry.TotalTokens = entry.InputTokens + entry.OutputTokens + entry.CacheCreationTokens + entry.CacheReadTokens + entry.ThinkingTokens
+
+	// Tell Bedrock and Vertex AI usage apart by the model ID, before it is normalized
+	entry.Source = models.ClaudeModelSource(entry.Model)
 
 	return entry, hasUsage
+}
+
+// extractThinkingTokens reads thinking/reasoning token counts from a usage object.
+// Top-level thinking_tokens/reasoning_tokens are reported in addition to output tokens,
+// while output_tokens_details.reasoning_tokens is a subset of output_tokens and is split out of it.
+func extractThinkingTokens(usage map[string]interface{}, entry *models.UsageEntry) bool {
+	for _, field := range []string{"thinking_tokens", "reasoning_tokens"} {
+		if val, ok := usage[field].(float64); ok {
+			entry.ThinkingTokens = int(val)
+			return true
+		}
+	}
+
+	if details, ok := usage["output_tokens_details"].(map[string]interface{}); ok {
+		if val, ok := details["reasoning_tokens"].(float64); ok && val > 0 {
+			reasoning := int(val)
+			if reasoning > entry.OutputTokens {
+				reasoning = entry.OutputTokens
+			}
+			entry.ThinkingTokens = reasoning
+			entry.OutputTokens -= reasoning
+			return true
+		}
+	}
+
+	return false
 }
\ No newline at end of file
@@ -0,0 +1,124 @@
+package fileio
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/bytedance/sonic"
+)
+
+// maxRecordedParseFailures caps the failures kept per file in a quarantine report
+const maxRecordedParseFailures = 5
+
+// ParseFailure is a line that is not valid JSON
+type ParseFailure struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+// FileParseReport lists the lines of a file that failed to parse
+type FileParseReport struct {
+	Path         string         `json:"path"`
+	InvalidLines int            `json:"invalid_lines"`
+	Failures     []ParseFailure `json:"failures"` // The first failures (capped)
+}
+
+// QuarantinedLine is the record written to a quarantine file for each invalid line
+type QuarantinedLine struct {
+	Source  string `json:"source"`
+	Line    int    `json:"line"`
+	Error   string `json:"error"`
+	Content string `json:"content"`
+}
+
+// Quarantine collects the lines that fail to parse while loading, and optionally copies them
+// to a writer as JSON lines, one QuarantinedLine each, so they can be inspected. It is safe
+// for concurrent use.
+type Quarantine struct {
+	mu       sync.Mutex
+	out      io.Writer
+	writeErr error
+	files    map[string]*FileParseReport
+	total    int
+}
+
+// NewQuarantine creates a quarantine. Invalid lines are copied to out unless it is nil.
+func NewQuarantine(out io.Writer) *Quarantine {
+	return &Quarantine{out: out, files: make(map[string]*FileParseReport)}
+}
+
+// record adds an invalid line of a file. A nil quarantine ignores it.
+func (q *Quarantine) record(path string, lineNumber int, line string, parseErr error) {
+	if q == nil {
+		return
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	report, ok := q.files[path]
+	if !ok {
+		report = &FileParseReport{Path: path}
+		q.files[path] = report
+	}
+	report.InvalidLines++
+	q.total++
+	if len(report.Failures) < maxRecordedParseFailures {
+		report.Failures = append(report.Failures, ParseFailure{Line: lineNumber, Error: parseErr.Error()})
+	}
+
+	if q.out == nil || q.writeErr != nil {
+		return
+	}
+	data, err := sonic.Marshal(QuarantinedLine{Source: path, Line: lineNumber, Error: parseErr.Error(), Content: line})
+	if err == nil {
+		_, err = q.out.Write(append(data, '\n'))
+	}
+	if err != nil {
+		q.writeErr = fmt.Errorf("failed to write quarantined line: %w", err)
+	}
+}
+
+// InvalidLines returns how many invalid lines were recorded
+func (q *Quarantine) InvalidLines() int {
+	if q == nil {
+		return 0
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.total
+}
+
+// Report returns the files with invalid lines, most affected first
+func (q *Quarantine) Report() []FileParseReport {
+	if q == nil {
+		return nil
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	reports := make([]FileParseReport, 0, len(q.files))
+	for _, report := range q.files {
+		r := *report
+		r.Failures = append([]ParseFailure(nil), report.Failures...)
+		reports = append(reports, r)
+	}
+	sort.Slice(reports, func(i, j int) bool {
+		if reports[i].InvalidLines != reports[j].InvalidLines {
+			return reports[i].InvalidLines > reports[j].InvalidLines
+		}
+		return reports[i].Path < reports[j].Path
+	})
+	return reports
+}
+
+// Err returns the first error writing a quarantined line; later lines are not copied after it
+func (q *Quarantine) Err() error {
+	if q == nil {
+		return nil
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.writeErr
+}
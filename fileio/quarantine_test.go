@@ -0,0 +1,90 @@
+package fileio
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bytedance/sonic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadUsageEntries_QuarantinesInvalidLines(t *testing.T) {
+	dir := t.TempDir()
+	broken := filepath.Join(dir, "broken.jsonl")
+	content := `{"type":"assistant","timestamp":"2025-06-01T10:00:00Z","requestId":"r1","message":{"id":"m1","model":"claude-sonnet-4-20250514","usage":{"input_tokens":100,"output_tokens":50}}}
+{"type":"assistant", broken
+not json
+{"type":"assistant","timestamp":"2025-06-01T10:06:00Z","requ`
+	require.NoError(t, os.WriteFile(broken, []byte(content), 0644))
+	clean := filepath.Join(dir, "clean.jsonl")
+	require.NoError(t, os.WriteFile(clean, []byte(`{"type":"user","timestamp":"2025-06-01T10:00:01Z"}`+"\n"), 0644))
+
+	var out bytes.Buffer
+	quarantine := NewQuarantine(&out)
+	result, err := LoadUsageEntries(LoadUsageEntriesOptions{Files: []string{broken, clean}, Quarantine: quarantine})
+	require.NoError(t, err)
+	require.NoError(t, quarantine.Err())
+
+	assert.Len(t, result.Entries, 1)
+	assert.Equal(t, 2, result.Metadata.InvalidLines, "the unterminated last line is still being written")
+	require.Len(t, result.Metadata.ParseErrors, 1)
+	report := result.Metadata.ParseErrors[0]
+	assert.Equal(t, broken, report.Path)
+	assert.Equal(t, 2, report.InvalidLines)
+	require.Len(t, report.Failures, 2)
+	assert.Equal(t, 2, report.Failures[0].Line)
+	assert.Equal(t, 3, report.Failures[1].Line)
+
+	var lines []QuarantinedLine
+	scanner := bufio.NewScanner(&out)
+	for scanner.Scan() {
+		var line QuarantinedLine
+		require.NoError(t, sonic.Unmarshal(scanner.Bytes(), &line))
+		lines = append(lines, line)
+	}
+	require.Len(t, lines, 2)
+	assert.Equal(t, QuarantinedLine{Source: broken, Line: 3, Error: lines[1].Error, Content: "not json"}, lines[1])
+	assert.NotEmpty(t, lines[1].Error)
+}
+
+func TestLoadUsageEntries_ReportsInvalidLinesWithoutQuarantine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte("not json\n"), 0644))
+
+	result, err := LoadUsageEntries(LoadUsageEntriesOptions{Files: []string{path}})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Metadata.InvalidLines)
+	require.Len(t, result.Metadata.ParseErrors, 1)
+	assert.Equal(t, path, result.Metadata.ParseErrors[0].Path)
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("disk full") }
+
+func TestQuarantine_Report(t *testing.T) {
+	quarantine := NewQuarantine(failingWriter{})
+	parseErr := errors.New("invalid character")
+	for i := 1; i <= maxRecordedParseFailures+2; i++ {
+		quarantine.record("a.jsonl", i, "x", parseErr)
+	}
+	quarantine.record("b.jsonl", 1, "x", parseErr)
+
+	assert.Equal(t, maxRecordedParseFailures+3, quarantine.InvalidLines())
+	reports := quarantine.Report()
+	require.Len(t, reports, 2)
+	assert.Equal(t, "a.jsonl", reports[0].Path, "most affected file first")
+	assert.Equal(t, maxRecordedParseFailures+2, reports[0].InvalidLines)
+	assert.Len(t, reports[0].Failures, maxRecordedParseFailures)
+	assert.ErrorContains(t, quarantine.Err(), "disk full")
+
+	var none *Quarantine
+	none.record("a.jsonl", 1, "x", parseErr)
+	assert.Zero(t, none.InvalidLines())
+	assert.Nil(t, none.Report())
+}
@@ -0,0 +1,76 @@
+package fileio
+
+import "strings"
+
+// maxLimitNoticeLength is the longest tool result kept by redaction as a usage limit notice;
+// longer output is tool output that happens to mention a limit
+const maxLimitNoticeLength = 300
+
+// redactedRecordFields are the top-level fields of a raw record kept by redaction: the type,
+// time and identity of the record and the usage it reports
+var redactedRecordFields = []string{
+	"type", "subtype", "level", "timestamp", "sessionId", "session_id", "requestId", "request_id",
+	"message_id", "uuid", "parentUuid", "isSidechain", "isMeta", "userType", "version",
+	"model", "usage", "costUSD",
+}
+
+// redactedMessageFields are the fields of a record's message kept by redaction
+var redactedMessageFields = []string{"id", "type", "role", "model", "usage", "stop_reason", "stop_sequence"}
+
+// RedactRecord returns a copy of a raw record without the text of prompts, responses, tool
+// output and conversation summaries, keeping only what usage and limits are computed from.
+// Claude Code's own system messages and short usage limit notices returned as tool results
+// are kept, so limits are still detected.
+func RedactRecord(data map[string]interface{}) map[string]interface{} {
+	redacted := make(map[string]interface{}, len(redactedRecordFields))
+	for _, field := range redactedRecordFields {
+		if value, ok := data[field]; ok {
+			redacted[field] = value
+		}
+	}
+	if data["type"] == "system" {
+		if content, ok := data["content"].(string); ok {
+			redacted["content"] = content
+		}
+	}
+
+	message, ok := data["message"].(map[string]interface{})
+	if !ok {
+		return redacted
+	}
+	redactedMessage := make(map[string]interface{}, len(redactedMessageFields)+1)
+	for _, field := range redactedMessageFields {
+		if value, ok := message[field]; ok {
+			redactedMessage[field] = value
+		}
+	}
+	if notices := limitNotices(message["content"]); len(notices) > 0 {
+		redactedMessage["content"] = notices
+	}
+	redacted["message"] = redactedMessage
+	return redacted
+}
+
+// limitNotices returns the tool results of message content that are usage limit notices
+func limitNotices(content interface{}) []interface{} {
+	items, ok := content.([]interface{})
+	if !ok {
+		return nil
+	}
+	var notices []interface{}
+	for _, item := range items {
+		block, ok := item.(map[string]interface{})
+		if !ok || block["type"] != "tool_result" {
+			continue
+		}
+		text, ok := block["content"].(string)
+		if !ok || len(text) > maxLimitNoticeLength {
+			continue
+		}
+		lower := strings.ToLower(text)
+		if strings.Contains(lower, "usage limit") || strings.Contains(lower, "rate limit") {
+			notices = append(notices, map[string]interface{}{"type": "tool_result", "content": text})
+		}
+	}
+	return notices
+}
@@ -0,0 +1,67 @@
+package fileio
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactRecord(t *testing.T) {
+	usage := map[string]interface{}{"input_tokens": 10.0, "output_tokens": 5.0}
+	assistant := map[string]interface{}{
+		"type":      "assistant",
+		"timestamp": "2025-06-10T14:00:00Z",
+		"sessionId": "c1",
+		"requestId": "r1",
+		"cwd":       "/home/me/secret-project",
+		"message": map[string]interface{}{
+			"id":          "m1",
+			"role":        "assistant",
+			"model":       "claude-sonnet-4-20250514",
+			"usage":       usage,
+			"stop_reason": "end_turn",
+			"content":     []interface{}{map[string]interface{}{"type": "text", "text": "Here is the fix"}},
+		},
+	}
+	assert.Equal(t, map[string]interface{}{
+		"type":      "assistant",
+		"timestamp": "2025-06-10T14:00:00Z",
+		"sessionId": "c1",
+		"requestId": "r1",
+		"message": map[string]interface{}{
+			"id":          "m1",
+			"role":        "assistant",
+			"model":       "claude-sonnet-4-20250514",
+			"usage":       usage,
+			"stop_reason": "end_turn",
+		},
+	}, RedactRecord(assistant))
+
+	summary := map[string]interface{}{"type": "summary", "summary": "Fix the login redirect", "leafUuid": "u9"}
+	assert.Equal(t, map[string]interface{}{"type": "summary"}, RedactRecord(summary))
+
+	system := map[string]interface{}{"type": "system", "content": "Claude AI usage limit reached|1749567600"}
+	assert.Equal(t, system, RedactRecord(system))
+}
+
+func TestRedactRecord_KeepsLimitNotices(t *testing.T) {
+	notice := "Claude usage limit reached. Your limit will reset at 3pm."
+	record := map[string]interface{}{
+		"type": "user",
+		"message": map[string]interface{}{
+			"role": "user",
+			"content": []interface{}{
+				map[string]interface{}{"type": "text", "text": "What is the rate limit of our API?"},
+				map[string]interface{}{"type": "tool_result", "tool_use_id": "t1", "content": notice},
+				map[string]interface{}{"type": "tool_result", "tool_use_id": "t2", "content": "rate limit " + strings.Repeat("x", 400)},
+				map[string]interface{}{"type": "tool_result", "tool_use_id": "t3", "content": "ok"},
+			},
+		},
+	}
+
+	redacted := RedactRecord(record)
+	message := redacted["message"].(map[string]interface{})
+	assert.Equal(t, []interface{}{map[string]interface{}{"type": "tool_result", "content": notice}}, message["content"])
+	assert.Equal(t, "user", message["role"])
+}
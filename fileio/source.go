@@ -0,0 +1,166 @@
+package fileio
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/bytedance/sonic"
+	"github.com/penwyp/claudecat/models"
+)
+
+// UsageExtractor extracts the usage entry, if any, from one record of a log
+type UsageExtractor func(data map[string]interface{}) (models.UsageEntry, bool)
+
+// Source reads the usage logs of one AI coding assistant, so the usage of every assistant
+// someone works with can be loaded and monitored together
+type Source interface {
+	// Name identifies the assistant, such as "claude" or "codex"
+	Name() string
+
+	// DataPaths returns the locations the assistant writes its logs to by default
+	DataPaths() []string
+
+	// Owns reports whether path names one of the assistant's logs
+	Owns(path string) bool
+
+	// NewExtractor returns the extractor for the records of the log at path, which are read
+	// in order, so it may carry state from one record to the next
+	NewExtractor(path string) UsageExtractor
+}
+
+// DocumentSource is a Source whose logs are single JSON documents, rewritten as a whole,
+// rather than JSONL files that are appended to
+type DocumentSource interface {
+	Source
+
+	// Records splits a log into the records its extractor reads
+	Records(document map[string]interface{}) []map[string]interface{}
+}
+
+var (
+	sourcesMu sync.RWMutex
+	// sources are consulted in order; Claude Code owns every JSONL file, so it comes last
+	sources = []Source{codexSource{}, geminiSource{}, claudeSource{}}
+)
+
+// RegisterSource adds a source, consulted before the built-in ones
+func RegisterSource(source Source) {
+	sourcesMu.Lock()
+	defer sourcesMu.Unlock()
+	sources = append([]Source{source}, sources...)
+}
+
+// Sources returns the registered sources in the order they are consulted
+func Sources() []Source {
+	sourcesMu.RLock()
+	defer sourcesMu.RUnlock()
+	return append([]Source(nil), sources...)
+}
+
+// SourceFor returns the source owning path, or nil when no source does
+func SourceFor(path string) Source {
+	for _, source := range Sources() {
+		if source.Owns(path) {
+			return source
+		}
+	}
+	return nil
+}
+
+// IsSourceFile reports whether path names a usage log of any source
+func IsSourceFile(path string) bool {
+	return SourceFor(path) != nil
+}
+
+// SourceDataPaths returns the default locations of every source's logs, Claude Code's first
+func SourceDataPaths() []string {
+	paths := CandidateDataPaths()
+	for _, source := range Sources() {
+		if _, ok := source.(claudeSource); !ok {
+			paths = append(paths, source.DataPaths()...)
+		}
+	}
+	return paths
+}
+
+// newUsageExtractor returns the extractor for the records of filePath, falling back to the
+// Claude Code format for files no source claims. Entries the extractor doesn't attribute are
+// tagged with the name of the source.
+func newUsageExtractor(filePath string) UsageExtractor {
+	source := SourceFor(filePath)
+	if source == nil {
+		return extractUsageEntry
+	}
+	extract, name := source.NewExtractor(filePath), source.Name()
+	return func(data map[string]interface{}) (models.UsageEntry, bool) {
+		entry, ok := extract(data)
+		if ok && entry.Source == "" {
+			entry.Source = name
+		}
+		return entry, ok
+	}
+}
+
+// readDocumentRecords reads a log of a DocumentSource and splits it into records, returning
+// the number of bytes read
+func readDocumentRecords(r io.Reader, source DocumentSource) ([]map[string]interface{}, int64, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read: %w", err)
+	}
+	var document map[string]interface{}
+	if err := sonic.Unmarshal(content, &document); err != nil {
+		return nil, int64(len(content)), fmt.Errorf("invalid JSON document: %w", err)
+	}
+	return source.Records(document), int64(len(content)), nil
+}
+
+// sourceHasUsage reports whether a log of a source other than Claude Code holds any usage.
+// Their usage may come anywhere in the log, so all of it is read.
+func sourceHasUsage(filePath string, source Source) bool {
+	file, err := OpenUsageFile(filePath)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	extract := source.NewExtractor(filePath)
+	if documents, ok := source.(DocumentSource); ok {
+		records, _, err := readDocumentRecords(file, documents)
+		if err != nil {
+			return false
+		}
+		for _, record := range records {
+			if _, ok := extract(record); ok {
+				return true
+			}
+		}
+		return false
+	}
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024) // 10MB max line size
+	for scanner.Scan() {
+		var data map[string]interface{}
+		if err := sonic.Unmarshal(scanner.Bytes(), &data); err != nil {
+			continue
+		}
+		if _, ok := extract(data); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// claudeSource reads Claude Code's JSONL project logs
+type claudeSource struct{}
+
+func (claudeSource) Name() string { return models.SourceClaude }
+
+func (claudeSource) DataPaths() []string { return CandidateDataPaths() }
+
+func (claudeSource) Owns(path string) bool { return IsUsageFile(path) }
+
+func (claudeSource) NewExtractor(string) UsageExtractor { return extractUsageEntry }
@@ -0,0 +1,40 @@
+package fileio
+
+import (
+	"testing"
+
+	"github.com/penwyp/claudecat/models"
+	"github.com/stretchr/testify/assert"
+)
+
+type testSource struct{}
+
+func (testSource) Name() string        { return "test" }
+func (testSource) DataPaths() []string { return []string{"/opt/test-assistant/logs"} }
+func (testSource) Owns(path string) bool {
+	return path == "/opt/test-assistant/logs/usage.log"
+}
+func (testSource) NewExtractor(string) UsageExtractor {
+	return func(map[string]interface{}) (models.UsageEntry, bool) { return models.UsageEntry{}, false }
+}
+
+func TestSourceFor(t *testing.T) {
+	assert.Equal(t, "claude", SourceFor("/home/demo/.claude/projects/-home-demo-app/session.jsonl").Name())
+	assert.Equal(t, "codex", SourceFor("/home/demo/.codex/sessions/2025/09/15/rollout-x.jsonl").Name())
+	assert.Equal(t, "gemini", SourceFor("/home/demo/.gemini/tmp/abc/chats/session-x.json").Name())
+	assert.Nil(t, SourceFor("/home/demo/notes.txt"))
+}
+
+func TestRegisterSource(t *testing.T) {
+	saved := Sources()
+	t.Cleanup(func() {
+		sourcesMu.Lock()
+		sources = saved
+		sourcesMu.Unlock()
+	})
+
+	assert.False(t, IsSourceFile("/opt/test-assistant/logs/usage.log"))
+	RegisterSource(testSource{})
+	assert.True(t, IsSourceFile("/opt/test-assistant/logs/usage.log"))
+	assert.Contains(t, SourceDataPaths(), "/opt/test-assistant/logs")
+}
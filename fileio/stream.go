@@ -0,0 +1,156 @@
+package fileio
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/penwyp/claudecat/cache"
+	"github.com/penwyp/claudecat/logging"
+	"github.com/penwyp/claudecat/models"
+)
+
+// ErrStopStream can be returned by a StreamUsageEntries callback to stop streaming early
+// without reporting an error
+var ErrStopStream = errors.New("stop streaming usage entries")
+
+// UsageEntryFunc is called by StreamUsageEntries for every loaded entry
+type UsageEntryFunc func(entry models.UsageEntry) error
+
+// StreamUsageEntries loads usage entries like LoadUsageEntries but hands them to fn one at a
+// time instead of collecting them, so only the entries of a single file are held in memory.
+//
+// Files are processed one after another and entries are delivered in file order, which is
+// not necessarily chronological; callers that need sorted output must sort it themselves.
+// Deduplication, the persistent dedup index, validation and the summary cache behave as in
+// LoadUsageEntries, and Progress is reported after each file; IncludeRaw is ignored. If fn returns an error streaming stops, and the
+// error is returned unless it is ErrStopStream. The returned metadata covers the files
+// processed so far.
+func StreamUsageEntries(opts LoadUsageEntriesOptions, fn UsageEntryFunc) (*LoadMetadata, error) {
+	startTime := time.Now()
+	opts.IncludeRaw = false
+
+	files := opts.Files
+	if len(files) == 0 {
+		var err error
+		files, err = DiscoverFilesInPaths(append([]string{opts.DataPath}, opts.ExtraDataPaths...))
+		if err != nil {
+			return nil, fmt.Errorf("failed to find JSONL files: %w", err)
+		}
+	}
+	if !opts.ModifiedSince.IsZero() {
+		files = FilesModifiedSince(files, opts.ModifiedSince)
+	}
+
+	var cutoffTime *time.Time
+	if opts.HoursBack != nil {
+		cutoff := time.Now().UTC().Add(-time.Duration(*opts.HoursBack) * time.Hour)
+		cutoffTime = &cutoff
+	}
+
+	var deduplicationSet map[string]bool
+	if opts.EnableDeduplication {
+		deduplicationSet = make(map[string]bool)
+	}
+	if opts.DedupIndex != nil && !opts.ReadOnly {
+		opts.DedupIndex.CompactIfDue(time.Now())
+	}
+	if opts.Quarantine == nil {
+		opts.Quarantine = NewQuarantine(nil)
+	}
+
+	metadata := &LoadMetadata{
+		CacheMissReasons: map[string]int{
+			"new_file":              0,
+			"modified_file":         0,
+			"no_assistant_messages": 0,
+			"other":                 0,
+		},
+		CacheStats: &CachePerformanceStats{},
+	}
+	var validation validationReport
+	var summariesToCache []*cache.FileSummary
+
+	var sizes map[string]int64
+	var progress LoadProgress
+	if opts.Progress != nil {
+		sizes, progress.TotalBytes = fileSizeIndex(files)
+		progress.TotalFiles = int32(len(files))
+	}
+
+	// Persist what was learned even when the callback stops streaming early
+	defer func() {
+		if !opts.ReadOnly {
+			storeSummaries(opts.CacheStore, summariesToCache)
+			saveDedupIndex(opts.DedupIndex)
+		}
+		metadata.InvalidLines = opts.Quarantine.InvalidLines()
+		metadata.ParseErrors = opts.Quarantine.Report()
+		finishStreamMetadata(metadata, validation, time.Since(startTime))
+	}()
+
+	for _, filePath := range files {
+		entries, _, fromCache, missReason, err, summary := processSingleFileWithCacheAndDedup(filePath, opts, cutoffTime, deduplicationSet)
+		metadata.FilesProcessed++
+		if opts.Progress != nil {
+			progress.record(sizes[filePath], len(entries), fromCache, err)
+			opts.Progress(progress)
+		}
+		if err != nil {
+			metadata.ProcessingErrors = append(metadata.ProcessingErrors, fmt.Sprintf("%s: %v", filePath, err))
+			continue
+		}
+
+		if fromCache {
+			metadata.CacheStats.Hits++
+		} else {
+			metadata.CacheStats.Misses++
+			if missReason != "" {
+				metadata.CacheMissReasons[missReason]++
+			}
+		}
+		if summary != nil {
+			summariesToCache = append(summariesToCache, summary)
+		}
+
+		entries, report := partitionSuspectEntries(entries, opts.Validator)
+		validation.add(report)
+
+		for _, entry := range entries {
+			metadata.EntriesLoaded++
+			if err := fn(entry); err != nil {
+				if errors.Is(err, ErrStopStream) {
+					return metadata, nil
+				}
+				return metadata, fmt.Errorf("usage entry callback failed for %s: %w", filepath.Base(filePath), err)
+			}
+		}
+	}
+
+	logging.LogInfof("Streamed %d entries from %d files in %v",
+		metadata.EntriesLoaded, len(files), time.Since(startTime))
+	return metadata, nil
+}
+
+// finishStreamMetadata fills in the derived fields of streaming metadata
+func finishStreamMetadata(metadata *LoadMetadata, validation validationReport, duration time.Duration) {
+	stats := metadata.CacheStats
+	if total := stats.Hits + stats.Misses; total > 0 {
+		stats.HitRate = float64(stats.Hits) / float64(total)
+	}
+	stats.NewFiles = metadata.CacheMissReasons["new_file"]
+	stats.ModifiedFiles = metadata.CacheMissReasons["modified_file"]
+	stats.NoAssistantMessages = metadata.CacheMissReasons["no_assistant_messages"]
+	stats.OtherMisses = metadata.CacheMissReasons["other"]
+
+	metadata.EntriesFiltered = validation.excluded
+	metadata.SuspectEntries = validation.suspect
+	metadata.ClampedEntries = validation.clamped
+	metadata.Anomalies = validation.anomalies
+	metadata.LoadDuration = duration
+
+	if validation.excluded > 0 {
+		logging.LogWarnf("Excluded %d suspect entries with implausible values (use --include-suspect to keep them)", validation.excluded)
+	}
+}
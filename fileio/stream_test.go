@@ -0,0 +1,89 @@
+package fileio
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/penwyp/claudecat/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeStreamTestData(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	projectDir := filepath.Join(dir, "proj")
+	require.NoError(t, os.MkdirAll(projectDir, 0755))
+
+	first := []string{
+		`{"type":"assistant","timestamp":"2025-06-01T10:00:00Z","requestId":"r1","message":{"id":"m1","model":"claude-sonnet-4-20250514","usage":{"input_tokens":100,"output_tokens":50}}}`,
+		`{"type":"assistant","timestamp":"2025-06-01T10:05:00Z","requestId":"r2","message":{"id":"m2","model":"claude-sonnet-4-20250514","usage":{"input_tokens":100,"output_tokens":1000000000}}}`,
+	}
+	second := []string{
+		// Duplicate of m1, counted once when deduplication is enabled
+		`{"type":"assistant","timestamp":"2025-06-01T10:00:00Z","requestId":"r1","message":{"id":"m1","model":"claude-sonnet-4-20250514","usage":{"input_tokens":100,"output_tokens":50}}}`,
+		`{"type":"assistant","timestamp":"2025-06-01T11:00:00Z","requestId":"r3","message":{"id":"m3","model":"claude-sonnet-4-20250514","usage":{"input_tokens":10,"output_tokens":5}}}`,
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, "a.jsonl"), []byte(strings.Join(first, "\n")+"\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, "b.jsonl"), []byte(strings.Join(second, "\n")+"\n"), 0644))
+	return dir
+}
+
+func TestStreamUsageEntries_MatchesLoadUsageEntries(t *testing.T) {
+	dir := writeStreamTestData(t)
+	bounds := models.EntryBounds{MaxInputTokens: 2_000_000, MaxOutputTokens: 1_000_000}
+	opts := LoadUsageEntriesOptions{
+		DataPath:            dir,
+		Mode:                models.CostModeCalculated,
+		EnableDeduplication: true,
+		Validator:           models.NewEntryValidator(bounds, models.AnomalyActionFlag, false),
+	}
+
+	loaded, err := LoadUsageEntries(opts)
+	require.NoError(t, err)
+
+	var streamed []string
+	metadata, err := StreamUsageEntries(opts, func(entry models.UsageEntry) error {
+		streamed = append(streamed, entry.MessageID)
+		return nil
+	})
+	require.NoError(t, err)
+
+	var expected []string
+	for _, entry := range loaded.Entries {
+		expected = append(expected, entry.MessageID)
+	}
+	assert.ElementsMatch(t, expected, streamed)
+	assert.ElementsMatch(t, []string{"m1", "m3"}, streamed)
+
+	assert.Equal(t, 2, metadata.FilesProcessed)
+	assert.Equal(t, 2, metadata.EntriesLoaded)
+	assert.Equal(t, 1, metadata.SuspectEntries)
+	assert.Equal(t, 1, metadata.EntriesFiltered)
+	require.Len(t, metadata.Anomalies, 1)
+	assert.Equal(t, "m2", metadata.Anomalies[0].MessageID)
+}
+
+func TestStreamUsageEntries_CallbackStops(t *testing.T) {
+	dir := writeStreamTestData(t)
+	opts := LoadUsageEntriesOptions{DataPath: dir, Mode: models.CostModeCalculated}
+
+	calls := 0
+	metadata, err := StreamUsageEntries(opts, func(entry models.UsageEntry) error {
+		calls++
+		return ErrStopStream
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, 1, metadata.EntriesLoaded)
+
+	callbackErr := errors.New("disk full")
+	_, err = StreamUsageEntries(opts, func(entry models.UsageEntry) error {
+		return callbackErr
+	})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, callbackErr)
+}
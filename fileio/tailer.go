@@ -0,0 +1,255 @@
+package fileio
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/penwyp/claudecat/logging"
+	"github.com/penwyp/claudecat/models"
+)
+
+// TailOptions configures a Tailer
+type TailOptions struct {
+	Interval        time.Duration          // Poll interval (default 1s)
+	FromStart       bool                   // Emit entries already present when tailing starts
+	Mode            models.CostMode        // Cost calculation mode
+	PricingProvider models.PricingProvider // Optional pricing provider for cost calculations
+	Validator       *models.EntryValidator // Optional validator; excluded suspect entries are not emitted
+}
+
+// Tailer follows JSONL files under a set of data paths and emits new usage entries as they are appended
+type Tailer struct {
+	paths      []string
+	options    TailOptions
+	offsets    map[string]int64          // Bytes consumed per file
+	partial    map[string][]byte         // Incomplete trailing line per file
+	extractors map[string]UsageExtractor // Entry extractor per file, which may hold session state
+	documented map[string]int            // Entries already seen per JSON document log
+	primed     bool
+	mu         sync.Mutex
+}
+
+// NewTailer creates a new tailer for the given data paths
+func NewTailer(paths []string, opts TailOptions) *Tailer {
+	if opts.Interval <= 0 {
+		opts.Interval = time.Second
+	}
+
+	return &Tailer{
+		paths:      paths,
+		options:    opts,
+		offsets:    make(map[string]int64),
+		partial:    make(map[string][]byte),
+		extractors: make(map[string]UsageExtractor),
+		documented: make(map[string]int),
+	}
+}
+
+// Run polls the data paths until the context is cancelled, calling onEntry for every new entry
+func (t *Tailer) Run(ctx context.Context, onEntry func(models.UsageEntry)) error {
+	if err := t.Poll(onEntry); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(t.options.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := t.Poll(onEntry); err != nil {
+				logging.LogWarnf("Tail poll failed: %v", err)
+			}
+		}
+	}
+}
+
+// Poll performs a single scan of all files. The first call only records the current
+// end of each file unless FromStart is set; files discovered later are read from the beginning.
+func (t *Tailer) Poll(onEntry func(models.UsageEntry)) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var files []string
+	for _, path := range t.paths {
+		found, err := DiscoverFiles(path)
+		if err != nil {
+			logging.LogDebugf("Skipping tail path %s: %v", path, err)
+			continue
+		}
+		files = append(files, found...)
+	}
+
+	for _, file := range files {
+		// Compressed logs are finished archives, there is nothing to tail
+		if IsCompressedUsageFile(file) {
+			continue
+		}
+
+		info, err := os.Stat(file)
+		if err != nil {
+			continue
+		}
+
+		offset, known := t.offsets[file]
+
+		// JSON document logs are rewritten as a whole, so they are read again when they change
+		if documents, ok := SourceFor(file).(DocumentSource); ok {
+			if known && info.Size() == offset {
+				continue
+			}
+			emit := known || t.primed || t.options.FromStart
+			if err := t.readDocument(file, documents, emit, onEntry); err != nil {
+				logging.LogDebugf("Failed to tail %s: %v", file, err)
+				continue
+			}
+			t.offsets[file] = info.Size()
+			continue
+		}
+
+		if !known && !t.primed && !t.options.FromStart {
+			t.offsets[file] = info.Size()
+			continue
+		}
+
+		// File was truncated or replaced, start over
+		if info.Size() < offset {
+			offset = 0
+			delete(t.partial, file)
+			delete(t.extractors, file)
+		}
+		if info.Size() == offset {
+			t.offsets[file] = offset
+			continue
+		}
+
+		consumed, err := t.readFrom(file, offset, onEntry)
+		if err != nil {
+			logging.LogDebugf("Failed to tail %s: %v", file, err)
+			continue
+		}
+		t.offsets[file] = offset + consumed
+	}
+
+	t.primed = true
+	return nil
+}
+
+// readFrom reads complete lines appended after offset and returns the number of bytes consumed
+func (t *Tailer) readFrom(file string, offset int64, onEntry func(models.UsageEntry)) (int64, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return 0, fmt.Errorf("failed to seek: %w", err)
+	}
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read: %w", err)
+	}
+
+	buffer := append(t.partial[file], data...)
+	lastNewline := bytes.LastIndexByte(buffer, '\n')
+	if lastNewline < 0 {
+		// No complete line yet, keep waiting for the writer to finish it
+		t.partial[file] = buffer
+		return int64(len(data)), nil
+	}
+
+	t.partial[file] = append([]byte(nil), buffer[lastNewline+1:]...)
+
+	extract, ok := t.extractors[file]
+	if !ok {
+		extract = newUsageExtractor(file)
+		t.extractors[file] = extract
+	}
+
+	for _, line := range bytes.Split(buffer[:lastNewline], []byte{'\n'}) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		var raw map[string]interface{}
+		if err := sonic.Unmarshal(line, &raw); err != nil {
+			logging.LogDebugf("Skipping invalid JSON while tailing %s: %v", file, err)
+			continue
+		}
+
+		if entry, hasUsage := extract(raw); hasUsage {
+			t.emit(file, entry, raw, onEntry)
+		}
+	}
+
+	return int64(len(data)), nil
+}
+
+// readDocument reads a JSON document log and emits the entries after the ones seen on the
+// previous read, or, when emit is false, only records how many there are
+func (t *Tailer) readDocument(file string, documents DocumentSource, emit bool, onEntry func(models.UsageEntry)) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %w", err)
+	}
+	defer f.Close()
+
+	records, _, err := readDocumentRecords(f, documents)
+	if err != nil {
+		return err
+	}
+
+	extract := newUsageExtractor(file)
+	seen := 0
+	for _, raw := range records {
+		entry, hasUsage := extract(raw)
+		if !hasUsage {
+			continue
+		}
+		seen++
+		if emit && seen > t.documented[file] {
+			t.emit(file, entry, raw, onEntry)
+		}
+	}
+	t.documented[file] = seen
+	return nil
+}
+
+// emit prices and checks an entry read from file and passes it on
+func (t *Tailer) emit(file string, entry models.UsageEntry, raw map[string]interface{}, onEntry func(models.UsageEntry)) {
+	if t.options.Validator != nil {
+		t.options.Validator.CheckTokens(&entry)
+	}
+	applyEntryCost(&entry, raw, t.options.Mode, t.options.PricingProvider)
+	if t.options.Validator != nil {
+		t.options.Validator.CheckCost(&entry)
+		if t.options.Validator.Excludes(entry) {
+			logging.LogDebugf("Skipping suspect entry while tailing %s: %v", file, entry.Anomalies)
+			return
+		}
+	}
+	entry.NormalizeModel()
+	if entry.Project == "" {
+		entry.Project = extractProjectFromPath(file)
+	}
+	entry.Tag = models.AllocationTag(file, entry.Project)
+
+	onEntry(entry)
+}
+
+// TrackedFiles returns the number of files currently being followed
+func (t *Tailer) TrackedFiles() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.offsets)
+}
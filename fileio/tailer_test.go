@@ -0,0 +1,64 @@
+package fileio
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/penwyp/claudecat/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const tailTestLine = `{"type":"assistant","timestamp":"2024-03-15T10:30:00Z","sessionId":"s1","requestId":"r1","message":{"id":"m1","model":"claude-3-5-sonnet-20241022","usage":{"input_tokens":100,"output_tokens":50}}}`
+
+func TestTailer_EmitsOnlyAppendedEntries(t *testing.T) {
+	dir := t.TempDir()
+	projectDir := filepath.Join(dir, "-Users-demo-myproject")
+	require.NoError(t, os.MkdirAll(projectDir, 0755))
+	file := filepath.Join(projectDir, "session.jsonl")
+	require.NoError(t, os.WriteFile(file, []byte(tailTestLine+"\n"), 0644))
+
+	var got []models.UsageEntry
+	collect := func(entry models.UsageEntry) { got = append(got, entry) }
+
+	tailer := NewTailer([]string{dir}, TailOptions{})
+	require.NoError(t, tailer.Poll(collect))
+	assert.Empty(t, got, "existing entries should be skipped")
+
+	f, err := os.OpenFile(file, os.O_APPEND|os.O_WRONLY, 0644)
+	require.NoError(t, err)
+	defer f.Close()
+
+	// A partially written line is held back until it is complete
+	half := len(tailTestLine) / 2
+	_, err = f.WriteString(tailTestLine[:half])
+	require.NoError(t, err)
+	require.NoError(t, tailer.Poll(collect))
+	assert.Empty(t, got)
+
+	_, err = f.WriteString(tailTestLine[half:] + "\n")
+	require.NoError(t, err)
+	require.NoError(t, tailer.Poll(collect))
+	require.Len(t, got, 1)
+	assert.Equal(t, 150, got[0].TotalTokens)
+	assert.Equal(t, "myproject", got[0].Project)
+	assert.Equal(t, "s1", got[0].SessionID)
+	assert.Equal(t, "r1", got[0].RequestID)
+	assert.Greater(t, got[0].CostUSD, 0.0)
+
+	// Files created after tailing started are read from the beginning
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, "new.jsonl"), []byte(tailTestLine+"\n"), 0644))
+	require.NoError(t, tailer.Poll(collect))
+	assert.Len(t, got, 2)
+}
+
+func TestTailer_FromStart(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.jsonl"), []byte(tailTestLine+"\n"), 0644))
+
+	var count int
+	tailer := NewTailer([]string{dir}, TailOptions{FromStart: true})
+	require.NoError(t, tailer.Poll(func(models.UsageEntry) { count++ }))
+	assert.Equal(t, 1, count)
+}
@@ -0,0 +1,47 @@
+package fileio
+
+import (
+	"context"
+	"time"
+
+	"github.com/penwyp/claudecat/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	tracer = telemetry.Tracer("fileio")
+
+	loadDuration = telemetry.Float64Histogram("fileio", "claudecat.load.duration",
+		"Time taken to load usage entries from the conversation logs", "s")
+	loadFiles = telemetry.Int64Counter("fileio", "claudecat.load.files",
+		"Conversation log files read, by whether their cached summary was used", "{file}")
+	loadEntries = telemetry.Int64Counter("fileio", "claudecat.load.entries",
+		"Usage entries loaded from the conversation logs", "{entry}")
+)
+
+// recordLoad ends the span of a load and records its metrics
+func recordLoad(ctx context.Context, span trace.Span, start time.Time, result *LoadUsageEntriesResult, err error) {
+	outcome := attribute.String("outcome", "ok")
+	if err != nil {
+		outcome = attribute.String("outcome", "error")
+	}
+	loadDuration.Record(ctx, telemetry.Since(start), metric.WithAttributes(outcome))
+
+	if result != nil {
+		meta := result.Metadata
+		span.SetAttributes(
+			attribute.Int("files", meta.FilesProcessed),
+			attribute.Int("entries", meta.EntriesLoaded),
+			attribute.Int("processing_errors", len(meta.ProcessingErrors)),
+		)
+		loadEntries.Add(ctx, int64(meta.EntriesLoaded))
+		if stats := meta.CacheStats; stats != nil {
+			span.SetAttributes(attribute.Int("cache.hits", stats.Hits), attribute.Int("cache.misses", stats.Misses))
+			loadFiles.Add(ctx, int64(stats.Hits), metric.WithAttributes(attribute.String("cache", "hit")))
+			loadFiles.Add(ctx, int64(stats.Misses), metric.WithAttributes(attribute.String("cache", "miss")))
+		}
+	}
+	telemetry.End(span, err)
+}
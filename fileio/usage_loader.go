@@ -24,14 +24,29 @@ func findJSONLFiles(dataPath string) ([]string, error) {
 // LoadUsageEntriesOptions configures the usage loading behavior
 type LoadUsageEntriesOptions struct {
 	DataPath            string                 // Path to Claude data directory
+	ExtraDataPaths      []string               // Additional data directories loaded together with DataPath
+	Files               []string               // Explicit JSONL files to load; skips directory discovery when set
 	HoursBack           *int                   // Only include entries from last N hours (nil = all data)
 	Mode                models.CostMode        // Cost calculation mode
 	IncludeRaw          bool                   // Whether to return raw JSON data alongside entries
+	RawFilter           RawRecordFilter        // Optional filter; when set only matching raw records are kept
+	RedactRaw           bool                   // Strip prompt and response text from the raw records kept
 	CacheStore          CacheStore             // Optional cache store for file summaries
 	EnableDeduplication bool                   // Whether to enable deduplication across all files
+	DedupIndex          *cache.DedupIndex      // Optional persistent dedup index shared across loads and restarts
 	PricingProvider     models.PricingProvider // Optional pricing provider for cost calculations
+	Validator           *models.EntryValidator // Optional validator for implausible token counts and costs
+	Concurrency         ConcurrencyOptions     // Worker count and scheduling of concurrent loading
+	Progress            ProgressFunc           // Optional callback reporting files and bytes processed
+	Quarantine          *Quarantine            // Optional collector of invalid lines; each load creates its own when nil
+	MemoryBudget        int64                  // Bytes a load may use; larger loads stream files one at a time without raw records (0 = no limit)
+	ModifiedSince       time.Time              // Skip files last modified before this time without reading them (zero = read all)
+	ReadOnly            bool                   // Leave the summary cache and dedup index unchanged, for quick one-shot loads
 }
 
+// RawRecordFilter selects the raw JSON records kept when IncludeRaw is set
+type RawRecordFilter func(raw map[string]interface{}) bool
+
 // CacheStore defines the interface for file summary caching
 type CacheStore interface {
 	GetFileSummary(absolutePath string) (*cache.FileSummary, error)
@@ -56,6 +71,27 @@ type LoadMetadata struct {
 	ProcessingErrors []string               `json:"processing_errors,omitempty"`
 	CacheMissReasons map[string]int         `json:"cache_miss_reasons,omitempty"`
 	CacheStats       *CachePerformanceStats `json:"cache_stats,omitempty"`
+	SuspectEntries   int                    `json:"suspect_entries,omitempty"` // Entries flagged as implausible
+	ClampedEntries   int                    `json:"clamped_entries,omitempty"` // Entries whose values were clamped into bounds
+	Anomalies        []EntryAnomaly         `json:"anomalies,omitempty"`       // Details of flagged entries (capped)
+	FileStates       map[string]FileState   `json:"file_states,omitempty"`     // State of each file before it was read
+	InvalidLines     int                    `json:"invalid_lines,omitempty"`   // Lines skipped because they are not valid JSON
+	ParseErrors      []FileParseReport      `json:"parse_errors,omitempty"`    // Files with invalid lines, most affected first
+	MemoryLimited    bool                   `json:"memory_limited,omitempty"`  // Streamed without raw records to stay within the memory budget
+}
+
+// maxRecordedAnomalies caps the number of anomaly details kept in LoadMetadata
+const maxRecordedAnomalies = 100
+
+// EntryAnomaly describes an entry that failed validation
+type EntryAnomaly struct {
+	Timestamp time.Time `json:"timestamp"`
+	Model     string    `json:"model"`
+	Project   string    `json:"project,omitempty"`
+	MessageID string    `json:"message_id,omitempty"`
+	RequestID string    `json:"request_id,omitempty"`
+	Flags     []string  `json:"flags"`
+	Excluded  bool      `json:"excluded"`
 }
 
 // CachePerformanceStats tracks cache performance metrics
@@ -71,16 +107,39 @@ type CachePerformanceStats struct {
 
 // LoadUsageEntries loads and converts JSONL files to UsageEntry objects
 func LoadUsageEntries(opts LoadUsageEntriesOptions) (*LoadUsageEntriesResult, error) {
+	return LoadUsageEntriesContext(context.Background(), opts)
+}
+
+// LoadUsageEntriesContext is LoadUsageEntries with a context that parents its trace span
+func LoadUsageEntriesContext(ctx context.Context, opts LoadUsageEntriesOptions) (result *LoadUsageEntriesResult, err error) {
 	startTime := time.Now()
+	ctx, span := tracer.Start(ctx, "fileio.LoadUsageEntries")
+	defer func() { recordLoad(ctx, span, startTime, result, err) }()
+
+	// Find all JSONL files unless the caller already knows them
+	jsonlFiles := opts.Files
+	if len(jsonlFiles) == 0 {
+		var err error
+		jsonlFiles, err = DiscoverFilesInPaths(append([]string{opts.DataPath}, opts.ExtraDataPaths...))
+		if err != nil {
+			return nil, fmt.Errorf("failed to find JSONL files: %w", err)
+		}
+	}
+	if !opts.ModifiedSince.IsZero() {
+		jsonlFiles = FilesModifiedSince(jsonlFiles, opts.ModifiedSince)
+	}
 
-	// Find all JSONL files
-	jsonlFiles, err := findJSONLFiles(opts.DataPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to find JSONL files: %w", err)
+	// Histories too large for the memory budget are streamed instead of loaded at once
+	if over, totalBytes, estimate := exceedsMemoryBudget(jsonlFiles, opts); over {
+		logMemoryLimited(totalBytes, estimate, opts.MemoryBudget)
+		return loadWithinBudget(ctx, jsonlFiles, opts)
 	}
 
+	// Record the files' state before reading them, so later changes can be detected
+	fileStates := StatFiles(jsonlFiles)
+
 	// Check if we should use concurrent loading
-	useConcurrent := len(jsonlFiles) > 10 // Use concurrent loading for more than 10 files
+	useConcurrent := opts.Concurrency.useConcurrent(len(jsonlFiles))
 
 	var allEntries []models.UsageEntry
 	var allRawEntries []map[string]interface{}
@@ -100,14 +159,22 @@ func LoadUsageEntries(opts LoadUsageEntriesOptions) (*LoadUsageEntriesResult, er
 		deduplicationSet = make(map[string]bool)
 		logging.LogDebugf("Deduplication enabled, tracking unique message+request ID combinations")
 	}
+	if opts.DedupIndex != nil && !opts.ReadOnly {
+		opts.DedupIndex.CompactIfDue(time.Now())
+	}
+	// Files served from the summary cache aren't parsed, so only parsed files are reported
+	if opts.Quarantine == nil {
+		opts.Quarantine = NewQuarantine(nil)
+	}
 
 	if useConcurrent {
 		// Use concurrent loader
-		loader := NewConcurrentLoader(0) // Use default worker count
-		ctx := context.Background()
+		files, workers := opts.Concurrency.plan(jsonlFiles)
+		logging.LogDebugf("Loading %d files with %d workers", len(files), workers)
+		loader := NewConcurrentLoader(workers)
 
 		// Load files concurrently with progress
-		results, err := loader.LoadFilesWithProgress(ctx, jsonlFiles, opts)
+		results, err := loader.LoadFilesWithProgress(ctx, files, opts)
 		if err != nil {
 			return nil, fmt.Errorf("concurrent loading failed: %w", err)
 		}
@@ -151,12 +218,23 @@ func LoadUsageEntries(opts LoadUsageEntriesOptions) (*LoadUsageEntriesResult, er
 			cutoffTime = &cutoff
 		}
 
+		var sizes map[string]int64
+		var progress LoadProgress
+		if opts.Progress != nil {
+			sizes, progress.TotalBytes = fileSizeIndex(jsonlFiles)
+			progress.TotalFiles = int32(len(jsonlFiles))
+		}
+
 		for i, filePath := range jsonlFiles {
 			if i < 5 || i%100 == 0 { // Log first 5 files and every 100th file
 				logging.LogDebugf("Processing file %d/%d: %s", i+1, len(jsonlFiles), filepath.Base(filePath))
 			}
 
 			entries, rawEntries, fromCache, missReason, err, summary := processSingleFileWithCacheAndDedup(filePath, opts, cutoffTime, deduplicationSet)
+			if opts.Progress != nil {
+				progress.record(sizes[filePath], len(entries), fromCache, err)
+				opts.Progress(progress)
+			}
 			if err != nil {
 				if i < 5 { // Log errors for first 5 files
 					logging.LogErrorf("Error processing file %s: %v", filepath.Base(filePath), err)
@@ -190,29 +268,18 @@ func LoadUsageEntries(opts LoadUsageEntriesOptions) (*LoadUsageEntriesResult, er
 		}
 	}
 
+	// Drop suspect entries unless they were explicitly requested
+	allEntries, validation := partitionSuspectEntries(allEntries, opts.Validator)
+
 	// Sort entries by timestamp
 	sort.Slice(allEntries, func(i, j int) bool {
 		return allEntries[i].Timestamp.Before(allEntries[j].Timestamp)
 	})
 
 	// Batch write summaries if we have any
-	if len(summariesToCache) > 0 && opts.CacheStore != nil {
-		if batcher, ok := opts.CacheStore.(interface {
-			BatchSet([]*cache.FileSummary) error
-		}); ok {
-			if err := batcher.BatchSet(summariesToCache); err != nil {
-				logging.LogWarnf("Failed to batch write %d summaries: %v", len(summariesToCache), err)
-			} else {
-				logging.LogDebugf("Batch wrote %d summaries to cache", len(summariesToCache))
-			}
-		} else {
-			// Fallback to individual writes if batch is not supported
-			for _, summary := range summariesToCache {
-				if err := opts.CacheStore.SetFileSummary(summary); err != nil {
-					logging.LogWarnf("Failed to cache summary for %s: %v", filepath.Base(summary.Path), err)
-				}
-			}
-		}
+	if !opts.ReadOnly {
+		storeSummaries(opts.CacheStore, summariesToCache)
+		saveDedupIndex(opts.DedupIndex)
 	}
 
 	// Calculate cache hit rate
@@ -234,12 +301,13 @@ func LoadUsageEntries(opts LoadUsageEntriesOptions) (*LoadUsageEntriesResult, er
 		}
 	}
 
-	result := &LoadUsageEntriesResult{
+	result = &LoadUsageEntriesResult{
 		Entries:    allEntries,
 		RawEntries: allRawEntries,
 		Metadata: LoadMetadata{
 			FilesProcessed:   len(jsonlFiles),
 			EntriesLoaded:    len(allEntries),
+			EntriesFiltered:  validation.excluded,
 			LoadDuration:     time.Since(startTime),
 			ProcessingErrors: processingErrors,
 			CacheMissReasons: cacheMissReasons,
@@ -252,9 +320,23 @@ func LoadUsageEntries(opts LoadUsageEntriesOptions) (*LoadUsageEntriesResult, er
 				NoAssistantMessages: cacheMissReasons["no_assistant_messages"],
 				OtherMisses:         cacheMissReasons["other"],
 			},
+			SuspectEntries: validation.suspect,
+			ClampedEntries: validation.clamped,
+			Anomalies:      validation.anomalies,
+			FileStates:     fileStates,
+			InvalidLines:   opts.Quarantine.InvalidLines(),
+			ParseErrors:    opts.Quarantine.Report(),
 		},
 	}
 
+	if invalid := result.Metadata.InvalidLines; invalid > 0 {
+		logging.LogWarnf("Skipped %d invalid lines in %d files (run claudecat doctor for details)", invalid, len(result.Metadata.ParseErrors))
+	}
+
+	if validation.excluded > 0 {
+		logging.LogWarnf("Excluded %d suspect entries with implausible values (use --include-suspect to keep them)", validation.excluded)
+	}
+
 	logging.LogInfof("Loaded %d entries from %d files in %v",
 		len(allEntries), len(jsonlFiles), time.Since(startTime))
 
@@ -273,6 +355,99 @@ func LoadUsageEntries(opts LoadUsageEntriesOptions) (*LoadUsageEntriesResult, er
 	return result, nil
 }
 
+// saveDedupIndex persists the dedup index, when there is one, logging failures
+func saveDedupIndex(index *cache.DedupIndex) {
+	if index == nil {
+		return
+	}
+	if err := index.Save(); err != nil {
+		logging.LogWarnf("Failed to save dedup index: %v", err)
+	}
+}
+
+// storeSummaries writes file summaries to the cache, in one batch when the store supports it
+func storeSummaries(store CacheStore, summaries []*cache.FileSummary) {
+	if len(summaries) == 0 || store == nil {
+		return
+	}
+
+	if batcher, ok := store.(interface {
+		BatchSet([]*cache.FileSummary) error
+	}); ok {
+		if err := batcher.BatchSet(summaries); err != nil {
+			logging.LogWarnf("Failed to batch write %d summaries: %v", len(summaries), err)
+		} else {
+			logging.LogDebugf("Batch wrote %d summaries to cache", len(summaries))
+		}
+		return
+	}
+
+	// Fallback to individual writes if batch is not supported
+	for _, summary := range summaries {
+		if err := store.SetFileSummary(summary); err != nil {
+			logging.LogWarnf("Failed to cache summary for %s: %v", filepath.Base(summary.Path), err)
+		}
+	}
+}
+
+// validationReport summarizes the anomalies found while loading
+type validationReport struct {
+	suspect   int
+	clamped   int
+	excluded  int
+	anomalies []EntryAnomaly
+}
+
+// add merges another report into r, keeping the anomaly details capped
+func (r *validationReport) add(other validationReport) {
+	r.suspect += other.suspect
+	r.clamped += other.clamped
+	r.excluded += other.excluded
+	for _, anomaly := range other.anomalies {
+		if len(r.anomalies) >= maxRecordedAnomalies {
+			break
+		}
+		r.anomalies = append(r.anomalies, anomaly)
+	}
+}
+
+// partitionSuspectEntries records flagged entries and removes suspect ones the validator excludes
+func partitionSuspectEntries(entries []models.UsageEntry, validator *models.EntryValidator) ([]models.UsageEntry, validationReport) {
+	var report validationReport
+	if validator == nil {
+		return entries, report
+	}
+
+	kept := entries[:0]
+	for _, entry := range entries {
+		if len(entry.Anomalies) > 0 {
+			if entry.Suspect {
+				report.suspect++
+			} else {
+				report.clamped++
+			}
+			excluded := validator.Excludes(entry)
+			if len(report.anomalies) < maxRecordedAnomalies {
+				report.anomalies = append(report.anomalies, EntryAnomaly{
+					Timestamp: entry.Timestamp,
+					Model:     entry.Model,
+					Project:   entry.Project,
+					MessageID: entry.MessageID,
+					RequestID: entry.RequestID,
+					Flags:     entry.Anomalies,
+					Excluded:  excluded,
+				})
+			}
+			if excluded {
+				report.excluded++
+				continue
+			}
+		}
+		kept = append(kept, entry)
+	}
+	return kept, report
+}
+
 // processSingleFileWithCacheWithReason processes a single JSONL file with caching support and returns cache miss reason
 func processSingleFileWithCacheWithReason(filePath string, opts LoadUsageEntriesOptions, cutoffTime *time.Time) ([]models.UsageEntry, []map[string]interface{}, bool, string, error, *cache.FileSummary) {
 	// Call the extended version with nil deduplication set
@@ -301,25 +476,48 @@ func processSingleFileWithCacheAndDedup(filePath string, opts LoadUsageEntriesOp
 
 		// Check cache first before reading file contents
 		if cachedSummary, err := opts.CacheStore.GetFileSummary(absPath); err == nil {
-			// Check if cache is still valid based on file mtime and size
-			if !cachedSummary.IsExpired(fileInfo.ModTime(), fileInfo.Size()) {
-				// Cache hit - check if this is a file without assistant messages
-				if cachedSummary.HasNoAssistantMessages {
-					// This file has no assistant messages, return empty results
+			// Check if cache is still valid based on file mtime and size, and that
+			// its costs were computed with the current cost mode
+			costModeMatches := summaryCostModeMatches(cachedSummary, opts)
+			validationMatches := summaryValidationMatches(cachedSummary, opts)
+			if !cachedSummary.IsExpired(fileInfo.ModTime(), fileInfo.Size()) && costModeMatches && validationMatches {
+				// Cache hit - files without assistant messages and copies of logs that still
+				// exist add no entries
+				if cachedSummary.HasNoAssistantMessages || copiesExistingFile(cachedSummary) {
 					return []models.UsageEntry{}, nil, true, "", nil, nil
 				}
 				// Normal cache hit with data
 				entries := createEntriesFromSummary(cachedSummary, cutoffTime)
+				applyCacheSavings(entries, opts.PricingProvider)
 				return entries, nil, true, "", nil, nil
 			} else {
-				// File has been modified, invalidate cache
-				logging.LogDebugf("Cache miss for %s: file modified (old mtime: %v, new mtime: %v, old size: %d, new size: %d)",
-					filepath.Base(filePath), cachedSummary.ModTime, fileInfo.ModTime(), cachedSummary.FileSize, fileInfo.Size())
-				if err := opts.CacheStore.InvalidateFileSummary(absPath); err != nil {
-					logging.LogWarnf("Failed to invalidate cache for %s: %v", filepath.Base(filePath), err)
+				// File has been modified or cost mode changed, invalidate cache
+				switch {
+				case !costModeMatches:
+					logging.LogDebugf("Cache miss for %s: cost mode changed (cached: %q, current: %q)",
+						filepath.Base(filePath), cachedSummary.CostMode, opts.Mode.String())
+				case !validationMatches:
+					logging.LogDebugf("Cache miss for %s: validation settings changed or suspect entries requested",
+						filepath.Base(filePath))
+				default:
+					logging.LogDebugf("Cache miss for %s: file modified (old mtime: %v, new mtime: %v, old size: %d, new size: %d)",
+						filepath.Base(filePath), cachedSummary.ModTime, fileInfo.ModTime(), cachedSummary.FileSize, fileInfo.Size())
+				}
+				if !opts.ReadOnly {
+					if err := opts.CacheStore.InvalidateFileSummary(absPath); err != nil {
+						logging.LogWarnf("Failed to invalidate cache for %s: %v", filepath.Base(filePath), err)
+					}
 				}
 				// Continue to process the file and track as modified
 			}
+		} else if summary = renamedFileSummary(absPath, filePath, fileInfo, opts); summary != nil {
+			// A renamed or copied log the cache knows under another path
+			if summary.DuplicateOf != "" {
+				return []models.UsageEntry{}, nil, true, "", nil, summary
+			}
+			entries := createEntriesFromSummary(summary, cutoffTime)
+			applyCacheSavings(entries, opts.PricingProvider)
+			return entries, nil, true, "", nil, summary
 		} else {
 			// Cache miss - file not in cache
 			logging.LogDebugf("Cache miss for %s: not in cache", filepath.Base(filePath))
@@ -345,7 +543,7 @@ func processSingleFileWithCacheAndDedup(filePath string, opts LoadUsageEntriesOp
 	}
 
 	// Cache miss or caching disabled, process normally
-	entries, rawEntries, err := processSingleFileWithDedup(filePath, opts.Mode, cutoffTime, opts.IncludeRaw, deduplicationSet, &opts)
+	entries, rawEntries, parsedSize, err := processSingleFileWithDedup(filePath, opts.Mode, cutoffTime, opts.IncludeRaw, deduplicationSet, &opts)
 	if err != nil {
 		return entries, rawEntries, false, missReason, err, nil
 	}
@@ -354,68 +552,125 @@ func processSingleFileWithCacheAndDedup(filePath string, opts LoadUsageEntriesOp
 	if opts.CacheStore != nil && len(entries) > 0 {
 		// Get file info if we don't have it yet
 		if fileInfo, err := os.Stat(filePath); err == nil {
-			summary = createSummaryFromEntries(absPath, filePath, entries, fileInfo)
+			validEntries := make([]models.UsageEntry, 0, len(entries))
+			for _, entry := range entries {
+				if !entry.Suspect {
+					validEntries = append(validEntries, entry)
+				}
+			}
+			summary = createSummaryFromEntries(absPath, filePath, validEntries, fileInfo)
+			// Record only the bytes that were parsed. When the last line was still being written,
+			// or the file grew while it was read, the size no longer matches on the next pass
+			// and the file is parsed again, picking up the finished line.
+			if !IsCompressedUsageFile(filePath) && parsedSize < summary.FileSize {
+				summary.FileSize = parsedSize
+			}
+			summary.CostMode = opts.Mode.String()
+			summary.ValidationKey = opts.Validator.Key()
+			summary.SuspectEntries = len(entries) - len(validEntries)
+			if identity, err := FileIdentity(filePath, summary.FileSize); err == nil {
+				summary.Identity = identity
+			}
 		}
 	}
 
 	return entries, rawEntries, false, missReason, nil, summary
 }
 
+// summaryCostModeMatches reports whether the costs of a summary were computed with the
+// cost mode of opts
+func summaryCostModeMatches(summary *cache.FileSummary, opts LoadUsageEntriesOptions) bool {
+	return summary.HasNoAssistantMessages || summary.CostMode == opts.Mode.String()
+}
+
+// summaryValidationMatches reports whether a summary can serve opts. Summaries only hold
+// entries that passed validation, so they can't serve a request that includes suspect
+// entries, nor one validated with different settings.
+func summaryValidationMatches(summary *cache.FileSummary, opts LoadUsageEntriesOptions) bool {
+	return summary.HasNoAssistantMessages ||
+		(summary.ValidationKey == opts.Validator.Key() &&
+			!(opts.Validator != nil && opts.Validator.IncludeSuspect && summary.SuspectEntries > 0))
+}
+
 // processSingleFile processes a single JSONL file
 func processSingleFile(filePath string, mode models.CostMode, cutoffTime *time.Time, includeRaw bool) ([]models.UsageEntry, []map[string]interface{}, error) {
 	// Call the extended version with nil deduplication set and no opts
-	return processSingleFileWithDedup(filePath, mode, cutoffTime, includeRaw, nil, nil)
+	entries, rawEntries, _, err := processSingleFileWithDedup(filePath, mode, cutoffTime, includeRaw, nil, nil)
+	return entries, rawEntries, err
 }
 
-// processSingleFileWithDedup processes a single JSONL file with optional deduplication
-func processSingleFileWithDedup(filePath string, mode models.CostMode, cutoffTime *time.Time, includeRaw bool, deduplicationSet map[string]bool, opts *LoadUsageEntriesOptions) ([]models.UsageEntry, []map[string]interface{}, error) {
-	file, err := os.Open(filePath)
+// incompleteLineGrace is how long an invalid last line without a newline is waited for. A
+// log untouched for longer isn't being written, so the line stays as it is, as when its
+// writer crashed mid-line, and is skipped like other invalid lines. Deferring it for good
+// would keep the summary short of the file's size and the file parsed again on every load.
+const incompleteLineGrace = 5 * time.Minute
+
+// lineStillWritten reports whether the unterminated last line of filePath may yet be finished
+func lineStillWritten(filePath string) bool {
+	info, err := os.Stat(filePath)
+	return err != nil || time.Since(info.ModTime()) < incompleteLineGrace
+}
+
+// scanLinesTracked splits like bufio.ScanLines and records how many bytes of the input were
+// returned and whether the latest line ended with a newline
+func scanLinesTracked(scanned *int64, terminated *bool) bufio.SplitFunc {
+	return func(data []byte, atEOF bool) (int, []byte, error) {
+		advance, token, err := bufio.ScanLines(data, atEOF)
+		if advance > 0 {
+			*scanned += int64(advance)
+			*terminated = data[advance-1] == '\n'
+		}
+		return advance, token, err
+	}
+}
+
+// processSingleFileWithDedup processes a single JSONL file, which may be compressed, or the
+// JSON document log of another source, with optional deduplication.
+// Besides the entries it returns how many bytes were parsed: everything up to an incomplete
+// trailing line, which the writer may still be appending to, so a later pass can parse it again.
+func processSingleFileWithDedup(filePath string, mode models.CostMode, cutoffTime *time.Time, includeRaw bool, deduplicationSet map[string]bool, opts *LoadUsageEntriesOptions) ([]models.UsageEntry, []map[string]interface{}, int64, error) {
+	file, err := OpenUsageFile(filePath)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to open file: %w", err)
+		return nil, nil, 0, fmt.Errorf("failed to open file: %w", err)
 	}
 	defer file.Close()
 
 	var entries []models.UsageEntry
 	var rawEntries []map[string]interface{}
 
-	scanner := bufio.NewScanner(file)
-	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024) // 10MB max line size
-
-	lineNumber := 0
-	processedLines := 0
-	skippedLines := 0
-
-	for scanner.Scan() {
-		lineNumber++
-		line := scanner.Text()
-
-		// Skip empty lines
-		if strings.TrimSpace(line) == "" {
-			continue
-		}
-
-		// Parse JSON
-		var data map[string]interface{}
-		if err := sonic.Unmarshal([]byte(line), &data); err != nil {
-			logging.LogDebugf("Skipping invalid JSON at line %d in %s: %v", lineNumber, filepath.Base(filePath), err)
-			skippedLines++
-			continue
+	// The persistent dedup index records entry owners by absolute path
+	var dedupIndex *cache.DedupIndex
+	dedupOwner := filePath
+	if opts != nil && opts.DedupIndex != nil {
+		dedupIndex = opts.DedupIndex
+		if absPath, err := filepath.Abs(filePath); err == nil {
+			dedupOwner = absPath
 		}
+	}
 
-		// Include raw data if requested
-		if includeRaw {
-			rawEntries = append(rawEntries, data)
+	processedLines := 0
+	extract := newUsageExtractor(filePath)
+
+	// processRecord turns one parsed record into an entry
+	processRecord := func(data map[string]interface{}) {
+		// Include raw data if requested, keeping only what the caller needs
+		if includeRaw && (opts == nil || opts.RawFilter == nil || opts.RawFilter(data)) {
+			if opts != nil && opts.RedactRaw {
+				rawEntries = append(rawEntries, RedactRecord(data))
+			} else {
+				rawEntries = append(rawEntries, data)
+			}
 		}
 
 		// Extract usage entry
-		entry, hasUsage := extractUsageEntry(data)
+		entry, hasUsage := extract(data)
 		if !hasUsage {
-			continue
+			return
 		}
 
 		// Apply time filter if specified
 		if cutoffTime != nil && entry.Timestamp.Before(*cutoffTime) {
-			continue
+			return
 		}
 
 		// Check for deduplication if enabled
@@ -424,39 +679,103 @@ func processSingleFileWithDedup(filePath string, mode models.CostMode, cutoffTim
 			if deduplicationSet[key] {
 				// Skip duplicate entry
 				logging.LogDebugf("Skipping duplicate entry with MessageID=%s, RequestID=%s", entry.MessageID, entry.RequestID)
-				continue
+				return
 			}
 			// Mark as seen
 			deduplicationSet[key] = true
 		}
 
-		// Calculate cost based on mode
-		if opts != nil && opts.PricingProvider != nil {
-			// Use pricing provider if available
-			pricing, err := opts.PricingProvider.GetPricing(context.Background(), entry.Model)
-			if err != nil {
-				// Fall back to default pricing on error
-				pricing = models.GetPricing(entry.Model)
+		// Skip entries already counted from another file, possibly in an earlier run
+		if dedupIndex != nil && entry.MessageID != "" && entry.RequestID != "" {
+			if !dedupIndex.Claim(cache.DedupKey(entry.MessageID, entry.RequestID), dedupOwner, entry.Timestamp) {
+				logging.LogDebugf("Skipping entry with MessageID=%s, RequestID=%s already counted from another file", entry.MessageID, entry.RequestID)
+				return
 			}
-			entry.CostUSD = entry.CalculateCost(pricing)
-		} else {
-			// Use default pricing
-			pricing := models.GetPricing(entry.Model)
-			entry.CostUSD = entry.CalculateCost(pricing)
+		}
+
+		// Validate token counts, then calculate cost based on mode
+		var provider models.PricingProvider
+		var validator *models.EntryValidator
+		if opts != nil {
+			provider = opts.PricingProvider
+			validator = opts.Validator
+		}
+		if validator != nil {
+			validator.CheckTokens(&entry)
+		}
+		applyEntryCost(&entry, data, mode, provider)
+		if validator != nil {
+			validator.CheckCost(&entry)
 		}
 
 		// Normalize model name
 		entry.NormalizeModel()
 
-		// Extract project from file path
-		entry.Project = extractProjectFromPath(filePath)
+		// Extract project from file path, unless the log records it
+		if entry.Project == "" {
+			entry.Project = extractProjectFromPath(filePath)
+		}
+		entry.Tag = models.AllocationTag(filePath, entry.Project)
 
 		entries = append(entries, entry)
 		processedLines++
 	}
 
+	// Logs that are JSON documents are rewritten as a whole, so a document that doesn't parse
+	// is still being written and is read again on a later pass
+	if documents, ok := SourceFor(filePath).(DocumentSource); ok {
+		records, size, err := readDocumentRecords(file, documents)
+		if err != nil {
+			logging.LogDebugf("Deferring %s: %v", filepath.Base(filePath), err)
+			return nil, nil, 0, nil
+		}
+		for _, record := range records {
+			processRecord(record)
+		}
+		return entries, rawEntries, size, nil
+	}
+
+	var scanned, parsedSize int64
+	var terminated bool
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024) // 10MB max line size
+	scanner.Split(scanLinesTracked(&scanned, &terminated))
+
+	lineNumber := 0
+	skippedLines := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Text()
+		lineStart := parsedSize
+		parsedSize = scanned
+
+		// Skip empty lines
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		// Parse JSON
+		var data map[string]interface{}
+		if err := sonic.Unmarshal([]byte(line), &data); err != nil {
+			if !terminated && lineStillWritten(filePath) {
+				// Last line without a newline: the writer hasn't finished it yet
+				logging.LogDebugf("Deferring incomplete line at offset %d in %s", lineStart, filepath.Base(filePath))
+				parsedSize = lineStart
+				continue
+			}
+			logging.LogDebugf("Skipping invalid JSON at line %d in %s: %v", lineNumber, filepath.Base(filePath), err)
+			skippedLines++
+			if opts != nil {
+				opts.Quarantine.record(filePath, lineNumber, line, err)
+			}
+			continue
+		}
+
+		processRecord(data)
+	}
+
 	if err := scanner.Err(); err != nil {
-		return nil, nil, fmt.Errorf("error reading file: %w", err)
+		return nil, nil, 0, fmt.Errorf("error reading file: %w", err)
 	}
 
 	if lineNumber > 0 && skippedLines > 0 {
@@ -464,5 +783,5 @@ func processSingleFileWithDedup(filePath string, mode models.CostMode, cutoffTim
 			filepath.Base(filePath), processedLines, lineNumber, skippedLines)
 	}
 
-	return entries, rawEntries, nil
-}
\ No newline at end of file
+	return entries, rawEntries, parsedSize, nil
+}
@@ -1,10 +1,17 @@
 package fileio
 
 import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/bytedance/sonic"
+	"github.com/penwyp/claudecat/cache"
+	"github.com/penwyp/claudecat/logging"
 	"github.com/penwyp/claudecat/models"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -89,6 +96,134 @@ func TestConvertRawToUsageEntry_LegacyFormat(t *testing.T) {
 	assert.Equal(t, "legacy-req-456", entry.RequestID)
 }
 
+func TestConvertRawToUsageEntry_ThinkingTokens(t *testing.T) {
+	tests := []struct {
+		name         string
+		usage        string
+		wantOutput   int
+		wantThinking int
+		wantTotal    int
+	}{
+		{
+			name:         "separate thinking_tokens field",
+			usage:        `{"input_tokens": 100, "output_tokens": 50, "thinking_tokens": 300}`,
+			wantOutput:   50,
+			wantThinking: 300,
+			wantTotal:    450,
+		},
+		{
+			name:         "separate reasoning_tokens field",
+			usage:        `{"input_tokens": 100, "output_tokens": 50, "reasoning_tokens": 30}`,
+			wantOutput:   50,
+			wantThinking: 30,
+			wantTotal:    180,
+		},
+		{
+			name:         "reasoning included in output tokens",
+			usage:        `{"input_tokens": 100, "output_tokens": 500, "output_tokens_details": {"reasoning_tokens": 400}}`,
+			wantOutput:   100,
+			wantThinking: 400,
+			wantTotal:    600,
+		},
+		{
+			name:         "no thinking tokens",
+			usage:        `{"input_tokens": 100, "output_tokens": 50}`,
+			wantOutput:   50,
+			wantThinking: 0,
+			wantTotal:    150,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jsonData := `{
+				"type": "assistant",
+				"timestamp": "2024-03-15T10:30:00Z",
+				"message": {
+					"id": "msg-1",
+					"model": "claude-sonnet-4-20250514",
+					"usage": ` + tt.usage + `
+				}
+			}`
+
+			var rawData map[string]interface{}
+			require.NoError(t, sonic.Unmarshal([]byte(jsonData), &rawData))
+
+			entry, err := convertRawToUsageEntry(rawData, models.CostModeCalculated)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantOutput, entry.OutputTokens)
+			assert.Equal(t, tt.wantThinking, entry.ThinkingTokens)
+			assert.Equal(t, tt.wantTotal, entry.TotalTokens)
+		})
+	}
+}
+
+func TestConvertRawToUsageEntry_CostModes(t *testing.T) {
+	withCost := `{
+		"type": "assistant",
+		"timestamp": "2024-03-15T10:30:00Z",
+		"costUSD": 0.5,
+		"message": {
+			"model": "claude-sonnet-4-20250514",
+			"usage": {"input_tokens": 1000, "output_tokens": 500}
+		}
+	}`
+	withoutCost := `{
+		"type": "assistant",
+		"timestamp": "2024-03-15T10:30:00Z",
+		"message": {
+			"model": "claude-sonnet-4-20250514",
+			"usage": {"input_tokens": 1000, "output_tokens": 500}
+		}
+	}`
+	calculated := 1000.0/1_000_000*3.0 + 500.0/1_000_000*15.0
+
+	tests := []struct {
+		name       string
+		data       string
+		mode       models.CostMode
+		wantCost   float64
+		wantSource string
+	}{
+		{"auto uses logged cost", withCost, models.CostModeAuto, 0.5, models.CostSourceLog},
+		{"auto calculates when missing", withoutCost, models.CostModeAuto, calculated, models.CostSourceCalculated},
+		{"display uses logged cost", withCost, models.CostModeDisplay, 0.5, models.CostSourceLog},
+		{"display is zero when missing", withoutCost, models.CostModeDisplay, 0, models.CostSourceLog},
+		{"calculate ignores logged cost", withCost, models.CostModeCalculated, calculated, models.CostSourceCalculated},
+	}
+
+	t.Run("cache savings", func(t *testing.T) {
+		var rawData map[string]interface{}
+		require.NoError(t, sonic.Unmarshal([]byte(`{
+			"type": "assistant",
+			"timestamp": "2024-03-15T10:30:00Z",
+			"costUSD": 0.5,
+			"message": {
+				"model": "claude-sonnet-4-20250514",
+				"usage": {"input_tokens": 10, "cache_read_input_tokens": 1000000}
+			}
+		}`), &rawData))
+
+		entry, err := convertRawToUsageEntry(rawData, models.CostModeAuto)
+		require.NoError(t, err)
+		assert.InDelta(t, 2.70, entry.CacheSavingsUSD, 0.000001, "also priced when the cost is logged")
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var rawData map[string]interface{}
+			require.NoError(t, sonic.Unmarshal([]byte(tt.data), &rawData))
+
+			entry, err := convertRawToUsageEntry(rawData, tt.mode)
+			require.NoError(t, err)
+
+			assert.InDelta(t, tt.wantCost, entry.CostUSD, 0.000001)
+			assert.Equal(t, tt.wantSource, entry.CostSource)
+		})
+	}
+}
+
 func TestConvertRawToUsageEntry_NonAssistantMessage(t *testing.T) {
 	// Test data representing a user message (should be skipped)
 	jsonData := `{
@@ -109,3 +244,281 @@ func TestConvertRawToUsageEntry_NonAssistantMessage(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "not an assistant message")
 }
+
+func TestLoadUsageEntries_SuspectEntries(t *testing.T) {
+	dir := t.TempDir()
+	projectDir := filepath.Join(dir, "proj")
+	require.NoError(t, os.MkdirAll(projectDir, 0755))
+
+	lines := []string{
+		`{"type":"assistant","timestamp":"2025-06-01T10:00:00Z","requestId":"r1","message":{"id":"m1","model":"claude-sonnet-4-20250514","usage":{"input_tokens":100,"output_tokens":50}}}`,
+		`{"type":"assistant","timestamp":"2025-06-01T10:05:00Z","requestId":"r2","message":{"id":"m2","model":"claude-sonnet-4-20250514","usage":{"input_tokens":100,"output_tokens":1000000000}}}`,
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, "session.jsonl"), []byte(strings.Join(lines, "\n")+"\n"), 0644))
+
+	bounds := models.EntryBounds{MaxInputTokens: 2_000_000, MaxOutputTokens: 1_000_000}
+
+	result, err := LoadUsageEntries(LoadUsageEntriesOptions{
+		DataPath:  dir,
+		Mode:      models.CostModeCalculated,
+		Validator: models.NewEntryValidator(bounds, models.AnomalyActionFlag, false),
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Entries, 1)
+	assert.Equal(t, "m1", result.Entries[0].MessageID)
+	assert.Equal(t, 1, result.Metadata.SuspectEntries)
+	assert.Equal(t, 1, result.Metadata.EntriesFiltered)
+	require.Len(t, result.Metadata.Anomalies, 1)
+	assert.Equal(t, "m2", result.Metadata.Anomalies[0].MessageID)
+	assert.True(t, result.Metadata.Anomalies[0].Excluded)
+
+	result, err = LoadUsageEntries(LoadUsageEntriesOptions{
+		DataPath:  dir,
+		Mode:      models.CostModeCalculated,
+		Validator: models.NewEntryValidator(bounds, models.AnomalyActionFlag, true),
+	})
+	require.NoError(t, err)
+	assert.Len(t, result.Entries, 2)
+	assert.Equal(t, 1, result.Metadata.SuspectEntries)
+	assert.Equal(t, 0, result.Metadata.EntriesFiltered)
+}
+
+func TestLoadUsageEntries_PersistentDedupAcrossRestarts(t *testing.T) {
+	dataDir := t.TempDir()
+	cacheDir := t.TempDir()
+	projectDir := filepath.Join(dataDir, "proj")
+	require.NoError(t, os.MkdirAll(projectDir, 0755))
+
+	shared := `{"type":"assistant","timestamp":"2025-06-01T10:00:00Z","requestId":"r1","message":{"id":"m1","model":"claude-sonnet-4-20250514","usage":{"input_tokens":100,"output_tokens":50}}}`
+	fileA := filepath.Join(projectDir, "a.jsonl")
+	fileB := filepath.Join(projectDir, "b.jsonl")
+	require.NoError(t, os.WriteFile(fileA, []byte(shared+"\n"), 0644))
+	require.NoError(t, os.WriteFile(fileB, []byte(shared+"\n"), 0644))
+
+	load := func() int {
+		// A fresh cache store and index on every load simulates a restart
+		store, err := cache.NewFileBasedSummaryCache(cacheDir)
+		require.NoError(t, err)
+		index, err := cache.OpenDedupIndex(cacheDir, 0)
+		require.NoError(t, err)
+
+		result, err := LoadUsageEntries(LoadUsageEntriesOptions{
+			DataPath:            dataDir,
+			Mode:                models.CostModeCalculated,
+			CacheStore:          store,
+			EnableDeduplication: true,
+			DedupIndex:          index,
+		})
+		require.NoError(t, err)
+
+		total := 0
+		for _, entry := range result.Entries {
+			total += entry.TotalTokens
+		}
+		return total
+	}
+
+	assert.Equal(t, 150, load())
+
+	// Only b.jsonl changes, so a.jsonl comes from its cached summary and its entries are
+	// never re-read; the index still knows m1 was counted from a.jsonl
+	f, err := os.OpenFile(fileB, os.O_APPEND|os.O_WRONLY, 0644)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"type":"assistant","timestamp":"2025-06-01T11:00:00Z","requestId":"r2","message":{"id":"m2","model":"claude-sonnet-4-20250514","usage":{"input_tokens":10,"output_tokens":5}}}` + "\n")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	assert.Equal(t, 165, load())
+}
+
+func TestLoadUsageEntries_IncompleteTrailingLine(t *testing.T) {
+	dataDir := t.TempDir()
+	projectDir := filepath.Join(dataDir, "proj")
+	require.NoError(t, os.MkdirAll(projectDir, 0755))
+	store, err := cache.NewFileBasedSummaryCache(t.TempDir())
+	require.NoError(t, err)
+
+	first := `{"type":"assistant","timestamp":"2025-06-01T10:00:00Z","requestId":"r1","message":{"id":"m1","model":"claude-sonnet-4-20250514","usage":{"input_tokens":100,"output_tokens":50}}}` + "\n"
+	second := `{"type":"assistant","timestamp":"2025-06-01T10:05:00Z","requestId":"r2","message":{"id":"m2","model":"claude-sonnet-4-20250514","usage":{"input_tokens":10,"output_tokens":5}}}` + "\n"
+	file := filepath.Join(projectDir, "a.jsonl")
+	// The writer is halfway through the second line
+	require.NoError(t, os.WriteFile(file, []byte(first+second[:40]), 0644))
+
+	load := func() []string {
+		result, err := LoadUsageEntries(LoadUsageEntriesOptions{
+			DataPath:   dataDir,
+			Mode:       models.CostModeCalculated,
+			CacheStore: store,
+		})
+		require.NoError(t, err)
+		assert.Empty(t, result.Metadata.ProcessingErrors)
+
+		var ids []string
+		for _, entry := range result.Entries {
+			ids = append(ids, entry.MessageID)
+		}
+		return ids
+	}
+
+	assert.Equal(t, []string{"m1"}, load())
+
+	// The summary only covers the complete line, so it can't be mistaken for the whole file
+	absPath, err := filepath.Abs(file)
+	require.NoError(t, err)
+	summary, err := store.GetFileSummary(absPath)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(first)), summary.FileSize)
+
+	f, err := os.OpenFile(file, os.O_APPEND|os.O_WRONLY, 0644)
+	require.NoError(t, err)
+	_, err = f.WriteString(second[40:])
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	assert.ElementsMatch(t, []string{"m1", "m2"}, load())
+	summary, err = store.GetFileSummary(absPath)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(first)+len(second)), summary.FileSize)
+}
+
+func TestLoadUsageEntries_AbandonedTrailingLine(t *testing.T) {
+	dataDir := t.TempDir()
+	projectDir := filepath.Join(dataDir, "proj")
+	require.NoError(t, os.MkdirAll(projectDir, 0755))
+	store, err := cache.NewFileBasedSummaryCache(t.TempDir())
+	require.NoError(t, err)
+
+	first := `{"type":"assistant","timestamp":"2025-06-01T10:00:00Z","requestId":"r1","message":{"id":"m1","model":"claude-sonnet-4-20250514","usage":{"input_tokens":100,"output_tokens":50}}}` + "\n"
+	broken := `{"type":"assistant","timestamp":"2025-06-01T10:05:00Z","requ`
+	file := filepath.Join(projectDir, "a.jsonl")
+	// The writer crashed halfway through the second line, long ago
+	require.NoError(t, os.WriteFile(file, []byte(first+broken), 0644))
+	crashed := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(file, crashed, crashed))
+
+	var out bytes.Buffer
+	quarantine := NewQuarantine(&out)
+	result, err := LoadUsageEntries(LoadUsageEntriesOptions{
+		DataPath:   dataDir,
+		Mode:       models.CostModeCalculated,
+		CacheStore: store,
+		Quarantine: quarantine,
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Entries, 1)
+	assert.Contains(t, out.String(), `"line":2`)
+
+	// The summary covers the whole file, so the next load is served from the cache
+	absPath, err := filepath.Abs(file)
+	require.NoError(t, err)
+	summary, err := store.GetFileSummary(absPath)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(first)+len(broken)), summary.FileSize)
+	info, err := os.Stat(file)
+	require.NoError(t, err)
+	assert.False(t, summary.IsExpired(info.ModTime(), info.Size()))
+}
+
+func TestLoadUsageEntries_QuickReadOnly(t *testing.T) {
+	dataDir := t.TempDir()
+	projectDir := filepath.Join(dataDir, "proj")
+	require.NoError(t, os.MkdirAll(projectDir, 0755))
+	store, err := cache.NewFileBasedSummaryCache(t.TempDir())
+	require.NoError(t, err)
+
+	line := `{"type":"assistant","timestamp":"2025-06-01T10:00:00Z","requestId":"%s","message":{"id":"%s","model":"claude-sonnet-4-20250514","usage":{"input_tokens":100,"output_tokens":50}}}` + "\n"
+	old := filepath.Join(projectDir, "old.jsonl")
+	recent := filepath.Join(projectDir, "recent.jsonl")
+	require.NoError(t, os.WriteFile(old, []byte(fmt.Sprintf(line, "r1", "m1")), 0644))
+	require.NoError(t, os.WriteFile(recent, []byte(fmt.Sprintf(line, "r2", "m2")), 0644))
+	lastWeek := time.Now().Add(-7 * 24 * time.Hour)
+	require.NoError(t, os.Chtimes(old, lastWeek, lastWeek))
+
+	result, err := LoadUsageEntries(LoadUsageEntriesOptions{
+		DataPath:      dataDir,
+		Mode:          models.CostModeCalculated,
+		CacheStore:    store,
+		ModifiedSince: time.Now().Add(-time.Hour),
+		ReadOnly:      true,
+	})
+	require.NoError(t, err)
+
+	// The old file is never opened, and nothing is written to the cache
+	require.Len(t, result.Entries, 1)
+	assert.Equal(t, "m2", result.Entries[0].MessageID)
+	assert.Equal(t, 1, result.Metadata.FilesProcessed)
+	absPath, err := filepath.Abs(recent)
+	require.NoError(t, err)
+	assert.False(t, store.HasFileSummary(absPath))
+}
+
+func TestLoadUsageEntries_RawFilter(t *testing.T) {
+	dir := t.TempDir()
+	projectDir := filepath.Join(dir, "proj")
+	require.NoError(t, os.MkdirAll(projectDir, 0755))
+
+	lines := []string{
+		`{"type":"assistant","timestamp":"2025-06-01T10:00:00Z","requestId":"r1","message":{"id":"m1","model":"claude-sonnet-4-20250514","usage":{"input_tokens":100,"output_tokens":50}}}`,
+		`{"type":"system","timestamp":"2025-06-01T10:01:00Z","content":"Claude usage limit reached"}`,
+		`{"type":"user","timestamp":"2025-06-01T10:02:00Z","message":{"content":"hello"}}`,
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, "session.jsonl"), []byte(strings.Join(lines, "\n")+"\n"), 0644))
+
+	opts := LoadUsageEntriesOptions{DataPath: dir, Mode: models.CostModeCalculated, IncludeRaw: true}
+	result, err := LoadUsageEntries(opts)
+	require.NoError(t, err)
+	assert.Len(t, result.RawEntries, 3)
+
+	opts.RawFilter = func(raw map[string]interface{}) bool { return raw["type"] == "system" }
+	result, err = LoadUsageEntries(opts)
+	require.NoError(t, err)
+	assert.Len(t, result.Entries, 1)
+	require.Len(t, result.RawEntries, 1)
+	assert.Equal(t, "system", result.RawEntries[0]["type"])
+}
+
+func TestLoadUsageEntries_Progress(t *testing.T) {
+	logging.InitLogger("error", "", true) // The concurrent loader logs through the global logger
+
+	dir := t.TempDir()
+	projectDir := filepath.Join(dir, "proj")
+	require.NoError(t, os.MkdirAll(projectDir, 0755))
+
+	var totalBytes int64
+	for i := 0; i < 4; i++ {
+		line := fmt.Sprintf(`{"type":"assistant","timestamp":"2025-06-01T10:0%d:00Z","requestId":"r%d","message":{"id":"m%d","model":"claude-sonnet-4-20250514","usage":{"input_tokens":100,"output_tokens":50}}}`+"\n", i, i, i)
+		require.NoError(t, os.WriteFile(filepath.Join(projectDir, fmt.Sprintf("session%d.jsonl", i)), []byte(line), 0644))
+		totalBytes += int64(len(line))
+	}
+
+	tests := []struct {
+		name      string
+		threshold int
+	}{
+		{"sequential", 10},
+		{"concurrent", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var reports []LoadProgress
+			_, err := LoadUsageEntries(LoadUsageEntriesOptions{
+				DataPath:    dir,
+				Mode:        models.CostModeCalculated,
+				Concurrency: ConcurrencyOptions{MaxWorkers: 2, Threshold: tt.threshold, FixedWorkers: true},
+				Progress:    func(progress LoadProgress) { reports = append(reports, progress) },
+			})
+			require.NoError(t, err)
+
+			require.GreaterOrEqual(t, len(reports), 4, "one report per file")
+			last := reports[len(reports)-1]
+			assert.EqualValues(t, 4, last.TotalFiles)
+			assert.EqualValues(t, 4, last.ProcessedFiles)
+			assert.EqualValues(t, 4, last.TotalEntries)
+			assert.Equal(t, totalBytes, last.TotalBytes)
+			assert.Equal(t, totalBytes, last.ProcessedBytes)
+			assert.Equal(t, 1.0, last.Fraction())
+			assert.Less(t, reports[0].Fraction(), 1.0)
+		})
+	}
+}
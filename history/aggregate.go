@@ -0,0 +1,108 @@
+package history
+
+import (
+	"sort"
+	"time"
+
+	"github.com/penwyp/claudecat/calculations"
+	"github.com/penwyp/claudecat/models"
+)
+
+// SummarizeDays aggregates the entries of session blocks into one summary per calendar day
+// in the store's location, oldest first. Days at the edges of the blocks' time range may be
+// partial; MergeDaily keeps the more complete stored version of such days.
+func (s *Store) SummarizeDays(blocks []models.SessionBlock, now time.Time) []DailySummary {
+	byDay := make(map[string]*DailySummary)
+	var order []string
+	sessionDays := make(map[string]map[string]bool) // date -> block IDs with usage that day
+
+	for _, block := range blocks {
+		if block.IsGap {
+			continue
+		}
+		for _, entry := range block.Entries {
+			date := s.DayKey(entry.Timestamp)
+			day, exists := byDay[date]
+			if !exists {
+				day = &DailySummary{
+					Date:      date,
+					PerModel:  make(map[string]ModelUsage),
+					UpdatedAt: now,
+				}
+				byDay[date] = day
+				order = append(order, date)
+				sessionDays[date] = make(map[string]bool)
+			}
+
+			tokens := addEntry(&day.TokenCounts, entry)
+			day.TotalTokens += tokens
+			day.CostUSD += entry.CostUSD
+			day.EntryCount++
+			addModelUsage(day.PerModel, entry, tokens)
+			sessionDays[date][block.ID] = true
+		}
+	}
+
+	sort.Strings(order)
+	days := make([]DailySummary, 0, len(byDay))
+	for _, date := range order {
+		day := byDay[date]
+		day.Sessions = len(sessionDays[date])
+		days = append(days, *day)
+	}
+	return days
+}
+
+// NewSnapshot captures the active session block, if any
+func NewSnapshot(blocks []models.SessionBlock, now time.Time) (Snapshot, bool) {
+	for _, block := range blocks {
+		if !block.IsActive || block.IsGap {
+			continue
+		}
+
+		snapshot := Snapshot{
+			Timestamp: now,
+			SessionID: block.ID,
+			PerModel:  make(map[string]ModelUsage),
+		}
+		for _, entry := range block.Entries {
+			tokens := addEntry(&snapshot.TokenCounts, entry)
+			snapshot.TotalTokens += tokens
+			snapshot.CostUSD += entry.CostUSD
+			snapshot.EntryCount++
+			addModelUsage(snapshot.PerModel, entry, tokens)
+		}
+		if burnRate := calculations.NewBurnRateCalculator().CalculateBurnRate(block); burnRate != nil {
+			snapshot.TokensPerMinute = burnRate.TokensPerMinute
+			snapshot.CostPerHour = burnRate.CostPerHour
+		}
+		return snapshot, true
+	}
+	return Snapshot{}, false
+}
+
+// addEntry adds an entry's tokens to counts and returns its total
+func addEntry(counts *models.TokenCounts, entry models.UsageEntry) int {
+	counts.InputTokens += entry.InputTokens
+	counts.OutputTokens += entry.OutputTokens
+	counts.CacheCreationTokens += entry.CacheCreationTokens
+	counts.CacheReadTokens += entry.CacheReadTokens
+	counts.ThinkingTokens += entry.ThinkingTokens
+
+	if entry.TotalTokens > 0 {
+		return entry.TotalTokens
+	}
+	return entry.CalculateTotalTokens()
+}
+
+func addModelUsage(perModel map[string]ModelUsage, entry models.UsageEntry, tokens int) {
+	model := entry.Model
+	if model == "" {
+		model = "unknown"
+	}
+	usage := perModel[model]
+	usage.TotalTokens += tokens
+	usage.CostUSD += entry.CostUSD
+	usage.EntryCount++
+	perModel[model] = usage
+}
@@ -0,0 +1,319 @@
+package history
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/penwyp/claudecat/models"
+	"github.com/penwyp/claudecat/timeutil"
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	// DBFileName is the name of the trend database inside the cache directory
+	DBFileName = "history.db"
+
+	// DefaultRetention is how long snapshots are kept when no retention is configured.
+	// Daily summaries are small and kept forever.
+	DefaultRetention = 365 * 24 * time.Hour
+
+	// lockTimeout bounds how long an operation waits for another claudecat process
+	// that has the database open
+	lockTimeout = 2 * time.Second
+)
+
+var (
+	snapshotsBucket = []byte("snapshots")
+	dailyBucket     = []byte("daily")
+)
+
+// ModelUsage contains aggregated usage for a single model
+type ModelUsage struct {
+	TotalTokens int     `json:"total_tokens"`
+	CostUSD     float64 `json:"cost_usd"`
+	EntryCount  int     `json:"entry_count"`
+}
+
+// Snapshot is the state of the active session at one point in time
+type Snapshot struct {
+	Timestamp       time.Time             `json:"timestamp"`
+	SessionID       string                `json:"session_id"`
+	TokenCounts     models.TokenCounts    `json:"token_counts"`
+	TotalTokens     int                   `json:"total_tokens"`
+	CostUSD         float64               `json:"cost_usd"`
+	TokensPerMinute float64               `json:"tokens_per_minute"`
+	CostPerHour     float64               `json:"cost_per_hour"`
+	EntryCount      int                   `json:"entry_count"`
+	PerModel        map[string]ModelUsage `json:"per_model"`
+}
+
+// DailySummary is the aggregated usage of one calendar day
+type DailySummary struct {
+	Date                string                `json:"date"` // YYYY-MM-DD in the store's location
+	TokenCounts         models.TokenCounts    `json:"token_counts"`
+	TotalTokens         int                   `json:"total_tokens"`
+	CostUSD             float64               `json:"cost_usd"`
+	EntryCount          int                   `json:"entry_count"`
+	Sessions            int                   `json:"sessions"`
+	PeakTokensPerMinute float64               `json:"peak_tokens_per_minute"` // Highest burn rate seen in a snapshot
+	PerModel            map[string]ModelUsage `json:"per_model"`
+	UpdatedAt           time.Time             `json:"updated_at"`
+}
+
+// Store persists metric snapshots and daily summaries in an embedded bbolt database, so
+// trends remain available after the conversation logs they came from are deleted.
+//
+// The database is opened only for the duration of each operation, which lets the monitor
+// and one-off commands such as `claudecat history` share it.
+type Store struct {
+	path      string
+	retention time.Duration
+	location  *time.Location
+	mu        sync.Mutex
+}
+
+// Open opens (or creates) the trend database in cacheDir. Days follow loc, a nil loc uses
+// the local zone and a retention of zero or less uses DefaultRetention.
+func Open(cacheDir string, retention time.Duration, loc *time.Location) (*Store, error) {
+	if retention <= 0 {
+		retention = DefaultRetention
+	}
+	if loc == nil {
+		loc = time.Local
+	}
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	store := &Store{
+		path:      filepath.Join(cacheDir, DBFileName),
+		retention: retention,
+		location:  loc,
+	}
+
+	err := store.update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{snapshotsBucket, dailyBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return fmt.Errorf("failed to create bucket %s: %w", name, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// Path returns the location of the database file
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Location returns the time zone that day boundaries follow
+func (s *Store) Location() *time.Location {
+	return s.location
+}
+
+// DayKey returns the date of t in the store's location, as used by DailySummary.Date
+func (s *Store) DayKey(t time.Time) string {
+	return timeutil.DateKey(t, s.location)
+}
+
+// AddSnapshot records a snapshot, raises its day's peak burn rate and drops snapshots
+// older than the retention period
+func (s *Store) AddSnapshot(snapshot Snapshot) error {
+	return s.update(func(tx *bolt.Tx) error {
+		data, err := json.Marshal(snapshot)
+		if err != nil {
+			return fmt.Errorf("failed to encode snapshot: %w", err)
+		}
+		snapshots := tx.Bucket(snapshotsBucket)
+		if err := snapshots.Put(timeKey(snapshot.Timestamp), data); err != nil {
+			return fmt.Errorf("failed to store snapshot: %w", err)
+		}
+
+		// Raise the day's peak burn rate, creating the day if it isn't known yet
+		daily := tx.Bucket(dailyBucket)
+		date := s.DayKey(snapshot.Timestamp)
+		day, err := getDaily(daily, date)
+		if err != nil {
+			return err
+		}
+		if day == nil {
+			day = &DailySummary{Date: date}
+		}
+		if snapshot.TokensPerMinute > day.PeakTokensPerMinute {
+			day.PeakTokensPerMinute = snapshot.TokensPerMinute
+			day.UpdatedAt = snapshot.Timestamp
+			if err := putDaily(daily, day); err != nil {
+				return err
+			}
+		}
+
+		// Drop expired snapshots; keys sort chronologically
+		cutoff := timeKey(snapshot.Timestamp.Add(-s.retention))
+		cursor := snapshots.Cursor()
+		for key, _ := cursor.First(); key != nil && string(key) < string(cutoff); key, _ = cursor.Next() {
+			if err := cursor.Delete(); err != nil {
+				return fmt.Errorf("failed to prune snapshot: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// MergeDaily stores daily summaries computed from the conversation logs.
+//
+// Usage only ever grows within a day, so a summary with fewer tokens than the stored one
+// means some of the day's logs are gone; the stored totals are kept in that case. The
+// highest peak burn rate of either summary is always kept.
+func (s *Store) MergeDaily(days []DailySummary) error {
+	if len(days) == 0 {
+		return nil
+	}
+
+	return s.update(func(tx *bolt.Tx) error {
+		daily := tx.Bucket(dailyBucket)
+		for i := range days {
+			day := days[i]
+			stored, err := getDaily(daily, day.Date)
+			if err != nil {
+				return err
+			}
+			if stored != nil {
+				if stored.TotalTokens > day.TotalTokens {
+					continue
+				}
+				if stored.PeakTokensPerMinute > day.PeakTokensPerMinute {
+					day.PeakTokensPerMinute = stored.PeakTokensPerMinute
+				}
+			}
+			if err := putDaily(daily, &day); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Daily returns the daily summaries between from and to inclusive, oldest first.
+// Zero times leave that end of the range open.
+func (s *Store) Daily(from, to time.Time) ([]DailySummary, error) {
+	var days []DailySummary
+	err := s.view(func(tx *bolt.Tx) error {
+		cursor := tx.Bucket(dailyBucket).Cursor()
+
+		key, value := cursor.First()
+		if !from.IsZero() {
+			key, value = cursor.Seek([]byte(s.DayKey(from)))
+		}
+		last := ""
+		if !to.IsZero() {
+			last = s.DayKey(to)
+		}
+
+		for ; key != nil; key, value = cursor.Next() {
+			if last != "" && string(key) > last {
+				break
+			}
+			var day DailySummary
+			if err := json.Unmarshal(value, &day); err != nil {
+				return fmt.Errorf("failed to decode daily summary %s: %w", key, err)
+			}
+			days = append(days, day)
+		}
+		return nil
+	})
+	return days, err
+}
+
+// Snapshots returns the snapshots between from and to inclusive, oldest first.
+// Zero times leave that end of the range open.
+func (s *Store) Snapshots(from, to time.Time) ([]Snapshot, error) {
+	var snapshots []Snapshot
+	err := s.view(func(tx *bolt.Tx) error {
+		cursor := tx.Bucket(snapshotsBucket).Cursor()
+
+		key, value := cursor.First()
+		if !from.IsZero() {
+			key, value = cursor.Seek(timeKey(from))
+		}
+
+		for ; key != nil; key, value = cursor.Next() {
+			if !to.IsZero() && string(key) > string(timeKey(to)) {
+				break
+			}
+			var snapshot Snapshot
+			if err := json.Unmarshal(value, &snapshot); err != nil {
+				return fmt.Errorf("failed to decode snapshot: %w", err)
+			}
+			snapshots = append(snapshots, snapshot)
+		}
+		return nil
+	})
+	return snapshots, err
+}
+
+// update runs fn in a read-write transaction
+func (s *Store) update(fn func(tx *bolt.Tx) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	db, err := bolt.Open(s.path, 0644, &bolt.Options{Timeout: lockTimeout})
+	if err != nil {
+		return fmt.Errorf("failed to open history database %s: %w", s.path, err)
+	}
+	defer db.Close()
+
+	return db.Update(fn)
+}
+
+// view runs fn in a read-only transaction
+func (s *Store) view(fn func(tx *bolt.Tx) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	db, err := bolt.Open(s.path, 0644, &bolt.Options{Timeout: lockTimeout, ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("failed to open history database %s: %w", s.path, err)
+	}
+	defer db.Close()
+
+	return db.View(fn)
+}
+
+// timeKey encodes t as a big-endian key so snapshots sort chronologically
+func timeKey(t time.Time) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(t.UnixNano()))
+	return key
+}
+
+func getDaily(bucket *bolt.Bucket, date string) (*DailySummary, error) {
+	value := bucket.Get([]byte(date))
+	if value == nil {
+		return nil, nil
+	}
+	var day DailySummary
+	if err := json.Unmarshal(value, &day); err != nil {
+		return nil, fmt.Errorf("failed to decode daily summary %s: %w", date, err)
+	}
+	return &day, nil
+}
+
+func putDaily(bucket *bolt.Bucket, day *DailySummary) error {
+	data, err := json.Marshal(day)
+	if err != nil {
+		return fmt.Errorf("failed to encode daily summary %s: %w", day.Date, err)
+	}
+	if err := bucket.Put([]byte(day.Date), data); err != nil {
+		return fmt.Errorf("failed to store daily summary %s: %w", day.Date, err)
+	}
+	return nil
+}
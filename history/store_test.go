@@ -0,0 +1,121 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"github.com/penwyp/claudecat/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func entryAt(ts time.Time, model string, input, output int, cost float64) models.UsageEntry {
+	return models.UsageEntry{
+		Timestamp:    ts,
+		Model:        model,
+		InputTokens:  input,
+		OutputTokens: output,
+		TotalTokens:  input + output,
+		CostUSD:      cost,
+	}
+}
+
+func TestStore_SummarizeAndMergeDaily(t *testing.T) {
+	store, err := Open(t.TempDir(), 0, time.UTC)
+	require.NoError(t, err)
+
+	day1 := time.Date(2025, 6, 2, 22, 0, 0, 0, time.UTC)
+	blocks := []models.SessionBlock{
+		{ID: "a", Entries: []models.UsageEntry{
+			entryAt(day1, "claude-sonnet-4", 100, 50, 0.5),
+			entryAt(day1.Add(150*time.Minute), "claude-opus-4", 200, 100, 2.0), // Next day
+		}},
+		{ID: "gap", IsGap: true, Entries: []models.UsageEntry{entryAt(day1, "claude-sonnet-4", 999, 0, 9)}},
+		{ID: "b", Entries: []models.UsageEntry{entryAt(day1.Add(3*time.Hour), "claude-sonnet-4", 10, 10, 0.1)}},
+	}
+
+	days := store.SummarizeDays(blocks, day1)
+	require.Len(t, days, 2)
+	assert.Equal(t, "2025-06-02", days[0].Date)
+	assert.Equal(t, 150, days[0].TotalTokens)
+	assert.Equal(t, 1, days[0].Sessions)
+	assert.Equal(t, "2025-06-03", days[1].Date)
+	assert.Equal(t, 320, days[1].TotalTokens)
+	assert.InDelta(t, 2.1, days[1].CostUSD, 1e-9)
+	assert.Equal(t, 2, days[1].Sessions)
+	assert.Equal(t, 300, days[1].PerModel["claude-opus-4"].TotalTokens)
+
+	require.NoError(t, store.MergeDaily(days))
+
+	// A later refresh that lost some of the day's logs keeps the stored totals
+	partial := days[1]
+	partial.TotalTokens = 20
+	partial.CostUSD = 0.1
+	require.NoError(t, store.MergeDaily([]DailySummary{partial}))
+
+	stored, err := store.Daily(time.Time{}, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, stored, 2)
+	assert.Equal(t, 320, stored[1].TotalTokens)
+
+	// Ranges are inclusive by day
+	stored, err = store.Daily(day1.Add(2*time.Hour), day1.Add(2*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, stored, 1)
+	assert.Equal(t, "2025-06-03", stored[0].Date)
+}
+
+func TestStore_AddSnapshot(t *testing.T) {
+	store, err := Open(t.TempDir(), 48*time.Hour, time.UTC)
+	require.NoError(t, err)
+
+	now := time.Date(2025, 6, 2, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, store.AddSnapshot(Snapshot{Timestamp: now.Add(-72 * time.Hour), TotalTokens: 1}))
+	require.NoError(t, store.AddSnapshot(Snapshot{Timestamp: now.Add(-time.Hour), TotalTokens: 100, TokensPerMinute: 250}))
+	require.NoError(t, store.AddSnapshot(Snapshot{Timestamp: now, TotalTokens: 200, TokensPerMinute: 120}))
+
+	snapshots, err := store.Snapshots(time.Time{}, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, snapshots, 2, "snapshots past the retention period are pruned")
+	assert.Equal(t, 100, snapshots[0].TotalTokens)
+	assert.True(t, snapshots[1].Timestamp.Equal(now))
+
+	// The day's peak burn rate survives merging a summary computed from the logs
+	require.NoError(t, store.MergeDaily([]DailySummary{{Date: "2025-06-02", TotalTokens: 200}}))
+	days, err := store.Daily(now, now)
+	require.NoError(t, err)
+	require.Len(t, days, 1)
+	assert.Equal(t, 200, days[0].TotalTokens)
+	assert.Equal(t, 250.0, days[0].PeakTokensPerMinute)
+}
+
+func TestNewSnapshot(t *testing.T) {
+	now := time.Date(2025, 6, 2, 12, 0, 0, 0, time.UTC)
+	start := now.Add(-time.Hour)
+
+	_, ok := NewSnapshot([]models.SessionBlock{{ID: "old", StartTime: start}}, now)
+	assert.False(t, ok, "no active block")
+
+	block := models.SessionBlock{
+		ID:        "active",
+		StartTime: start,
+		EndTime:   start.Add(5 * time.Hour),
+		IsActive:  true,
+		Entries: []models.UsageEntry{
+			entryAt(start.Add(time.Minute), "claude-sonnet-4", 1000, 500, 1.0),
+			entryAt(start.Add(30*time.Minute), "claude-sonnet-4", 1000, 500, 1.0),
+		},
+		TokenCounts: models.TokenCounts{InputTokens: 2000, OutputTokens: 1000},
+		CostUSD:     2.0,
+	}
+	now30 := start.Add(30 * time.Minute)
+	block.ActualEndTime = &now30
+
+	snapshot, ok := NewSnapshot([]models.SessionBlock{block}, now)
+	require.True(t, ok)
+	assert.Equal(t, "active", snapshot.SessionID)
+	assert.Equal(t, 3000, snapshot.TotalTokens)
+	assert.Equal(t, 2, snapshot.EntryCount)
+	assert.Equal(t, 3000, snapshot.PerModel["claude-sonnet-4"].TotalTokens)
+	assert.Greater(t, snapshot.TokensPerMinute, 0.0)
+}
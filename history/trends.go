@@ -0,0 +1,126 @@
+package history
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/penwyp/claudecat/timeutil"
+)
+
+// WeeklyTrend is the usage of one ISO week compared with the week before it
+type WeeklyTrend struct {
+	Week                string    `json:"week"` // ISO week, e.g. 2025-W24
+	WeekStart           time.Time `json:"week_start"`
+	ActiveDays          int       `json:"active_days"`
+	Sessions            int       `json:"sessions"`
+	TotalTokens         int       `json:"total_tokens"`
+	CostUSD             float64   `json:"cost_usd"`
+	PeakTokensPerMinute float64   `json:"peak_tokens_per_minute"`
+	TopModel            string    `json:"top_model,omitempty"` // Model with the most tokens
+
+	// Percentage change from the previous week, nil when the previous week had no usage
+	TokenChange *float64 `json:"token_change,omitempty"`
+	CostChange  *float64 `json:"cost_change,omitempty"`
+}
+
+// WeeklyTrends groups daily summaries into Monday-to-Sunday weeks in loc, oldest first.
+// Weeks without usage between the first and last week are included so changes always
+// compare consecutive weeks.
+func WeeklyTrends(days []DailySummary, loc *time.Location) ([]WeeklyTrend, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+
+	byWeek := make(map[time.Time]*WeeklyTrend)
+	modelTokens := make(map[time.Time]map[string]int)
+	for _, day := range days {
+		date, err := time.ParseInLocation(timeutil.DateLayout, day.Date, loc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid daily summary date %q: %w", day.Date, err)
+		}
+		start := WeekStart(date)
+
+		week, exists := byWeek[start]
+		if !exists {
+			week = newWeeklyTrend(start)
+			byWeek[start] = week
+			modelTokens[start] = make(map[string]int)
+		}
+
+		if day.TotalTokens > 0 {
+			week.ActiveDays++
+		}
+		week.Sessions += day.Sessions
+		week.TotalTokens += day.TotalTokens
+		week.CostUSD += day.CostUSD
+		if day.PeakTokensPerMinute > week.PeakTokensPerMinute {
+			week.PeakTokensPerMinute = day.PeakTokensPerMinute
+		}
+		for model, usage := range day.PerModel {
+			modelTokens[start][model] += usage.TotalTokens
+		}
+	}
+	if len(byWeek) == 0 {
+		return nil, nil
+	}
+
+	starts := make([]time.Time, 0, len(byWeek))
+	for start := range byWeek {
+		starts = append(starts, start)
+	}
+	sort.Slice(starts, func(i, j int) bool { return starts[i].Before(starts[j]) })
+
+	var trends []WeeklyTrend
+	var previous *WeeklyTrend
+	for start := starts[0]; !start.After(starts[len(starts)-1]); start = start.AddDate(0, 0, 7) {
+		week, exists := byWeek[start]
+		if !exists {
+			week = newWeeklyTrend(start)
+		}
+		week.TopModel = topModel(modelTokens[start])
+
+		if previous != nil {
+			if previous.TotalTokens > 0 {
+				change := percentChange(float64(previous.TotalTokens), float64(week.TotalTokens))
+				week.TokenChange = &change
+			}
+			if previous.CostUSD > 0 {
+				change := percentChange(previous.CostUSD, week.CostUSD)
+				week.CostChange = &change
+			}
+		}
+
+		trends = append(trends, *week)
+		previous = week
+	}
+	return trends, nil
+}
+
+func newWeeklyTrend(start time.Time) *WeeklyTrend {
+	year, week := start.ISOWeek()
+	return &WeeklyTrend{
+		Week:      fmt.Sprintf("%d-W%02d", year, week),
+		WeekStart: start,
+	}
+}
+
+// WeekStart returns midnight of the Monday that starts the week containing t, in t's location
+func WeekStart(t time.Time) time.Time {
+	return timeutil.StartOfWeek(t, t.Location())
+}
+
+func percentChange(from, to float64) float64 {
+	return (to - from) / from * 100
+}
+
+// topModel returns the model with the most tokens; names break ties
+func topModel(tokens map[string]int) string {
+	top, topTokens := "", 0
+	for model, count := range tokens {
+		if count > topTokens || (count == topTokens && count > 0 && model < top) {
+			top, topTokens = model, count
+		}
+	}
+	return top
+}
@@ -0,0 +1,57 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWeeklyTrends(t *testing.T) {
+	days := []DailySummary{
+		{Date: "2025-06-02", TotalTokens: 1000, CostUSD: 1, Sessions: 2, PeakTokensPerMinute: 50,
+			PerModel: map[string]ModelUsage{"claude-sonnet-4": {TotalTokens: 1000}}},
+		{Date: "2025-06-08", TotalTokens: 1000, CostUSD: 1, Sessions: 1}, // Sunday, same week
+		{Date: "2025-06-09", TotalTokens: 3000, CostUSD: 1, Sessions: 3, PeakTokensPerMinute: 80,
+			PerModel: map[string]ModelUsage{"claude-opus-4": {TotalTokens: 2000}, "claude-sonnet-4": {TotalTokens: 1000}}},
+		// No usage in the week of 2025-06-16
+		{Date: "2025-06-24", TotalTokens: 500, CostUSD: 0.5, Sessions: 1},
+	}
+
+	weeks, err := WeeklyTrends(days, time.UTC)
+	require.NoError(t, err)
+	require.Len(t, weeks, 4)
+
+	assert.Equal(t, "2025-W23", weeks[0].Week)
+	assert.Equal(t, time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC), weeks[0].WeekStart)
+	assert.Equal(t, 2, weeks[0].ActiveDays)
+	assert.Equal(t, 3, weeks[0].Sessions)
+	assert.Equal(t, 2000, weeks[0].TotalTokens)
+	assert.Nil(t, weeks[0].TokenChange)
+	assert.Equal(t, "claude-sonnet-4", weeks[0].TopModel)
+
+	require.NotNil(t, weeks[1].TokenChange)
+	assert.InDelta(t, 50, *weeks[1].TokenChange, 1e-9)
+	require.NotNil(t, weeks[1].CostChange)
+	assert.InDelta(t, -50, *weeks[1].CostChange, 1e-9)
+	assert.Equal(t, 80.0, weeks[1].PeakTokensPerMinute)
+	assert.Equal(t, "claude-opus-4", weeks[1].TopModel)
+
+	assert.Equal(t, time.Date(2025, 6, 16, 0, 0, 0, 0, time.UTC), weeks[2].WeekStart)
+	assert.Zero(t, weeks[2].TotalTokens)
+	require.NotNil(t, weeks[2].TokenChange)
+	assert.InDelta(t, -100, *weeks[2].TokenChange, 1e-9)
+
+	assert.Nil(t, weeks[3].TokenChange, "no change after a week without usage")
+	assert.Equal(t, 500, weeks[3].TotalTokens)
+}
+
+func TestWeeklyTrends_InvalidDate(t *testing.T) {
+	_, err := WeeklyTrends([]DailySummary{{Date: "June 2"}}, time.UTC)
+	assert.Error(t, err)
+
+	weeks, err := WeeklyTrends(nil, time.UTC)
+	require.NoError(t, err)
+	assert.Empty(t, weeks)
+}
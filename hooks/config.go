@@ -0,0 +1,53 @@
+package hooks
+
+import (
+	"fmt"
+
+	"github.com/penwyp/claudecat/config"
+	"github.com/penwyp/claudecat/logging"
+)
+
+// NewRegistryFromConfig creates a registry with the compiled-in hooks and the configured
+// command and plugin hooks. Hooks that can't be set up are logged and skipped.
+func NewRegistryFromConfig(cfgs []config.HookConfig) *Registry {
+	registry := NewRegistry()
+
+	defaultRegistry.mu.RLock()
+	registry.hooks = append(registry.hooks, defaultRegistry.hooks...)
+	defaultRegistry.mu.RUnlock()
+
+	for i, cfg := range cfgs {
+		hook, err := hookFromConfig(cfg, i)
+		if err != nil {
+			logging.LogWarnf("Hook disabled: %v", err)
+			continue
+		}
+		events := make([]EventType, len(cfg.Events))
+		for j, event := range cfg.Events {
+			events[j] = EventType(event)
+		}
+		registry.Register(hook, events...)
+	}
+	return registry
+}
+
+// hookFromConfig creates the command or plugin hook of one configuration entry
+func hookFromConfig(cfg config.HookConfig, index int) (Hook, error) {
+	name := cfg.Name
+	if name == "" {
+		name = fmt.Sprintf("hooks[%d]", index)
+	}
+
+	switch {
+	case cfg.Command != "":
+		return NewExecHook(name, cfg.Command, cfg.Args, cfg.Timeout), nil
+	case cfg.Plugin != "":
+		hook, err := LoadPlugin(cfg.Plugin)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		return hook, nil
+	default:
+		return nil, fmt.Errorf("%s: set command or plugin", name)
+	}
+}
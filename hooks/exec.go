@@ -0,0 +1,74 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/bytedance/sonic"
+)
+
+// DefaultExecTimeout bounds how long a command hook may run for one event
+const DefaultExecTimeout = 10 * time.Second
+
+// maxOutputInError is how much of a failed command's output is kept in its error
+const maxOutputInError = 512
+
+// ExecHook runs a command for each event, with the event as JSON on stdin and its type in
+// the CLAUDECAT_EVENT environment variable
+type ExecHook struct {
+	name    string
+	command string
+	args    []string
+	timeout time.Duration
+}
+
+// NewExecHook creates a hook running command with args. A zero timeout uses DefaultExecTimeout.
+func NewExecHook(name, command string, args []string, timeout time.Duration) *ExecHook {
+	if timeout <= 0 {
+		timeout = DefaultExecTimeout
+	}
+	return &ExecHook{name: name, command: command, args: args, timeout: timeout}
+}
+
+// Name returns the name of this hook
+func (h *ExecHook) Name() string {
+	return h.name
+}
+
+// Handle runs the command, failing when it exits non-zero or outlives the timeout
+func (h *ExecHook) Handle(ctx context.Context, event Event) error {
+	payload, err := sonic.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, h.command, h.args...)
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Env = append(os.Environ(), "CLAUDECAT_EVENT="+string(event.Type))
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("%s timed out after %s", h.command, h.timeout)
+		}
+		out := strings.TrimSpace(output.String())
+		if len(out) > maxOutputInError {
+			out = out[:maxOutputInError] + "..."
+		}
+		if out != "" {
+			return fmt.Errorf("%s: %w: %s", h.command, err, out)
+		}
+		return fmt.Errorf("%s: %w", h.command, err)
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+// Package hooks lets custom integrations observe the monitoring pipeline. Hooks are
+// registered for events such as new entries being loaded or a session ending, and are
+// either compiled in (Register), loaded from Go plugins (LoadPlugin) or run as external
+// commands (ExecHook).
+package hooks
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/penwyp/claudecat/logging"
+	"github.com/penwyp/claudecat/models"
+	"github.com/penwyp/claudecat/notify"
+)
+
+// EventType identifies a stage of the monitoring pipeline
+type EventType string
+
+const (
+	EventEntriesLoaded    EventType = "entries_loaded"    // New usage entries were loaded
+	EventBlockCreated     EventType = "block_created"     // A new session block appeared
+	EventSessionStart     EventType = "session_start"     // A session became active
+	EventSessionEnd       EventType = "session_end"       // The active session ended
+	EventThresholdCrossed EventType = "threshold_crossed" // A token limit, cost ceiling or budget threshold was crossed
+)
+
+// Events lists every event type, in the order they are dispatched within a refresh
+var Events = []EventType{EventEntriesLoaded, EventBlockCreated, EventSessionStart, EventSessionEnd, EventThresholdCrossed}
+
+// Event is what a hook receives. Only the fields of its type are set.
+type Event struct {
+	Type      EventType            `json:"event"`
+	Timestamp time.Time            `json:"timestamp"`
+	SessionID string               `json:"session_id,omitempty"` // session_start, session_end
+	Entries   []models.UsageEntry  `json:"entries,omitempty"`    // entries_loaded
+	Block     *models.SessionBlock `json:"block,omitempty"`      // block_created, without its entries
+	Alert     *notify.Notification `json:"alert,omitempty"`      // threshold_crossed
+}
+
+// Hook handles pipeline events
+type Hook interface {
+	// Name identifies the hook in logs
+	Name() string
+
+	// Handle processes one event. Errors are logged and don't stop other hooks.
+	Handle(ctx context.Context, event Event) error
+}
+
+// funcHook adapts a function to Hook
+type funcHook struct {
+	name string
+	fn   func(ctx context.Context, event Event) error
+}
+
+// Func returns a hook calling fn
+func Func(name string, fn func(ctx context.Context, event Event) error) Hook {
+	return &funcHook{name: name, fn: fn}
+}
+
+func (h *funcHook) Name() string { return h.name }
+
+func (h *funcHook) Handle(ctx context.Context, event Event) error { return h.fn(ctx, event) }
+
+// registration is a hook and the events it receives
+type registration struct {
+	hook   Hook
+	events map[EventType]bool // Empty receives every event
+}
+
+func (r registration) wants(event EventType) bool {
+	return len(r.events) == 0 || r.events[event]
+}
+
+// Registry holds hooks and delivers events to them
+type Registry struct {
+	mu    sync.RWMutex
+	hooks []registration
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register adds a hook for the given events; no events registers it for all of them
+func (r *Registry) Register(hook Hook, events ...EventType) {
+	reg := registration{hook: hook, events: make(map[EventType]bool, len(events))}
+	for _, event := range events {
+		reg.events[event] = true
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.hooks = append(r.hooks, reg)
+}
+
+// Len returns the number of registered hooks
+func (r *Registry) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.hooks)
+}
+
+// Wants reports whether any hook is registered for the event
+func (r *Registry) Wants(event EventType) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, reg := range r.hooks {
+		if reg.wants(event) {
+			return true
+		}
+	}
+	return false
+}
+
+// Dispatch delivers events in order to every hook registered for them. Hooks run one
+// at a time; failures and panics are logged. It blocks until all deliveries finish, so
+// callers usually run it in a goroutine.
+func (r *Registry) Dispatch(ctx context.Context, events []Event) {
+	r.mu.RLock()
+	hooks := make([]registration, len(r.hooks))
+	copy(hooks, r.hooks)
+	r.mu.RUnlock()
+
+	for _, event := range events {
+		for _, reg := range hooks {
+			if !reg.wants(event.Type) {
+				continue
+			}
+			if err := handle(ctx, reg.hook, event); err != nil {
+				logging.LogWarnf("Hook %s failed on %s: %v", reg.hook.Name(), event.Type, err)
+			}
+		}
+	}
+}
+
+// handle runs one hook, turning a panic into an error
+func handle(ctx context.Context, hook Hook, event Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return hook.Handle(ctx, event)
+}
+
+// defaultRegistry holds the hooks compiled into the binary
+var defaultRegistry = NewRegistry()
+
+// Register adds a compiled-in hook that every monitoring orchestrator delivers events to.
+// It is meant to be called from the init function of a package linked into a custom
+// claudecat build.
+func Register(hook Hook, events ...EventType) {
+	defaultRegistry.Register(hook, events...)
+}
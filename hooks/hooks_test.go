@@ -0,0 +1,100 @@
+package hooks
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/penwyp/claudecat/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_Dispatch(t *testing.T) {
+	registry := NewRegistry()
+	var all, sessions []EventType
+	registry.Register(Func("all", func(ctx context.Context, event Event) error {
+		all = append(all, event.Type)
+		return nil
+	}))
+	registry.Register(Func("sessions", func(ctx context.Context, event Event) error {
+		sessions = append(sessions, event.Type)
+		return errors.New("ignored")
+	}), EventSessionStart, EventSessionEnd)
+	registry.Register(Func("panics", func(ctx context.Context, event Event) error {
+		panic("boom")
+	}), EventBlockCreated)
+
+	assert.Equal(t, 3, registry.Len())
+	assert.True(t, registry.Wants(EventThresholdCrossed))
+
+	registry.Dispatch(context.Background(), []Event{
+		{Type: EventEntriesLoaded}, {Type: EventBlockCreated}, {Type: EventSessionEnd}, {Type: EventSessionStart},
+	})
+	assert.Equal(t, []EventType{EventEntriesLoaded, EventBlockCreated, EventSessionEnd, EventSessionStart}, all,
+		"a failing or panicking hook doesn't stop the others")
+	assert.Equal(t, []EventType{EventSessionEnd, EventSessionStart}, sessions)
+
+	filtered := NewRegistry()
+	filtered.Register(Func("sessions", nil), EventSessionStart)
+	assert.False(t, filtered.Wants(EventEntriesLoaded))
+}
+
+func TestEventsMatchConfig(t *testing.T) {
+	require.Len(t, config.HookEvents, len(Events))
+	for i, event := range Events {
+		assert.Equal(t, config.HookEvents[i], string(event))
+	}
+}
+
+func TestExecHook(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	dir := t.TempDir()
+	out := filepath.Join(dir, "event.json")
+	hook := NewExecHook("record", "sh", []string{"-c", `cat > "$0"; echo "$CLAUDECAT_EVENT" >> "$0"`, out}, 0)
+
+	event := Event{Type: EventSessionEnd, Timestamp: time.Date(2025, 6, 1, 15, 0, 0, 0, time.UTC), SessionID: "abc"}
+	require.NoError(t, hook.Handle(context.Background(), event))
+
+	written, err := os.ReadFile(out)
+	require.NoError(t, err)
+	var got Event
+	lines := string(written)
+	payload := lines[:len(lines)-len("session_end\n")]
+	require.NoError(t, sonic.UnmarshalString(payload, &got))
+	assert.Equal(t, event.SessionID, got.SessionID)
+	assert.True(t, event.Timestamp.Equal(got.Timestamp))
+	assert.Equal(t, "session_end\n", lines[len(payload):])
+
+	failing := NewExecHook("fail", "sh", []string{"-c", "echo bad input >&2; exit 3"}, 0)
+	err = failing.Handle(context.Background(), event)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "bad input")
+
+	slow := NewExecHook("slow", "sleep", []string{"5"}, 50*time.Millisecond)
+	err = slow.Handle(context.Background(), event)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "timed out")
+}
+
+func TestNewRegistryFromConfig(t *testing.T) {
+	Register(Func("compiled-in", nil), EventBlockCreated)
+	defer func() { defaultRegistry = NewRegistry() }()
+
+	registry := NewRegistryFromConfig([]config.HookConfig{
+		{Name: "cmd", Command: "true", Events: []string{"session_start"}},
+		{Name: "missing-plugin", Plugin: filepath.Join(t.TempDir(), "missing.so")},
+		{Name: "empty"},
+	})
+	assert.Equal(t, 2, registry.Len(), "the compiled-in and command hooks; the others are skipped")
+	assert.True(t, registry.Wants(EventBlockCreated))
+	assert.True(t, registry.Wants(EventSessionStart))
+	assert.False(t, registry.Wants(EventEntriesLoaded))
+}
@@ -0,0 +1,37 @@
+package hooks
+
+import (
+	"fmt"
+	"plugin"
+)
+
+// PluginSymbol is the name of the variable a Go plugin exports its hook as:
+//
+//	var Hook hooks.Hook = myHook{}
+const PluginSymbol = "Hook"
+
+// LoadPlugin opens a Go plugin (built with -buildmode=plugin against the same claudecat
+// version) and returns the hook it exports. Plugins are only supported on Linux, macOS
+// and FreeBSD builds with cgo.
+func LoadPlugin(path string) (Hook, error) {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open plugin %s: %w", path, err)
+	}
+	symbol, err := p.Lookup(PluginSymbol)
+	if err != nil {
+		return nil, fmt.Errorf("plugin %s: %w", path, err)
+	}
+
+	switch hook := symbol.(type) {
+	case *Hook:
+		if *hook == nil {
+			return nil, fmt.Errorf("plugin %s: %s is nil", path, PluginSymbol)
+		}
+		return *hook, nil
+	case Hook:
+		return hook, nil
+	default:
+		return nil, fmt.Errorf("plugin %s: %s is a %T, not a hooks.Hook", path, PluginSymbol, symbol)
+	}
+}
@@ -0,0 +1,243 @@
+// Package influx writes points in InfluxDB line protocol, either to an InfluxDB HTTP write
+// endpoint or appended to a file that Telegraf or influx write can pick up.
+package influx
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	errs "github.com/penwyp/claudecat/errors"
+)
+
+// DefaultBatchSize is the number of points sent per request when none is configured
+const DefaultBatchSize = 5000
+
+// Point is one line of line protocol. Field values are int, int64, float64, string or bool.
+type Point struct {
+	Measurement string
+	Tags        map[string]string
+	Fields      map[string]any
+	Time        time.Time
+}
+
+// Options configures a Client. Exactly one of URL and File is set.
+type Options struct {
+	URL       string // Base URL of the InfluxDB server, e.g. http://localhost:8086
+	Token     string // API token, sent as "Authorization: Token ..."
+	Org       string // InfluxDB 2 organization
+	Bucket    string // InfluxDB 2 bucket; with an empty bucket the 1.x /write endpoint is used
+	Database  string // InfluxDB 1.x database
+	File      string // File line protocol is appended to
+	BatchSize int    // Points per request or write, DefaultBatchSize when 0
+	Retry     errs.RetryPolicy
+}
+
+// Client writes points in batches, retrying failed HTTP requests
+type Client struct {
+	writeURL  string
+	token     string
+	file      string
+	batchSize int
+	retry     errs.RetryPolicy
+	http      *http.Client
+}
+
+// New creates a client from opts
+func New(opts Options) (*Client, error) {
+	c := &Client{
+		token:     opts.Token,
+		file:      opts.File,
+		batchSize: opts.BatchSize,
+		retry:     opts.Retry,
+		http:      &http.Client{Timeout: 30 * time.Second},
+	}
+	if c.batchSize <= 0 {
+		c.batchSize = DefaultBatchSize
+	}
+
+	switch {
+	case opts.URL != "" && opts.File != "":
+		return nil, fmt.Errorf("influx url and file are mutually exclusive")
+	case opts.File != "":
+		return c, nil
+	case opts.URL == "":
+		return nil, fmt.Errorf("influx url or file is required")
+	}
+
+	base, err := url.Parse(opts.URL)
+	if err != nil || (base.Scheme != "http" && base.Scheme != "https") || base.Host == "" {
+		return nil, fmt.Errorf("invalid influx URL: %q", opts.URL)
+	}
+	query := url.Values{"precision": {"ns"}}
+	if opts.Bucket != "" {
+		base = base.JoinPath("api", "v2", "write")
+		query.Set("bucket", opts.Bucket)
+		if opts.Org != "" {
+			query.Set("org", opts.Org)
+		}
+	} else if opts.Database != "" {
+		base = base.JoinPath("write")
+		query.Set("db", opts.Database)
+	} else {
+		return nil, fmt.Errorf("influx bucket or database is required")
+	}
+	base.RawQuery = query.Encode()
+	c.writeURL = base.String()
+	return c, nil
+}
+
+// Write sends points in batches. A batch that fails after its retries stops the write;
+// earlier batches stay written.
+func (c *Client) Write(ctx context.Context, points []Point) error {
+	for start := 0; start < len(points); start += c.batchSize {
+		end := min(start+c.batchSize, len(points))
+		var body []byte
+		for _, point := range points[start:end] {
+			body = AppendLine(body, point)
+		}
+
+		var err error
+		if c.file != "" {
+			err = c.appendFile(body)
+		} else {
+			err = c.retry.Retry(ctx, func() error { return c.post(ctx, body) }, nil)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// post sends one batch to the write endpoint. Client errors other than rate limiting are
+// permanent.
+func (c *Client) post(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.writeURL, bytes.NewReader(body))
+	if err != nil {
+		return errs.Permanent(fmt.Errorf("failed to create influx request: %w", err))
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Token "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to write to influx: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	err = fmt.Errorf("influx write returned %s: %s", resp.Status, strings.TrimSpace(string(message)))
+	if resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+		return errs.Permanent(err)
+	}
+	return err
+}
+
+// appendFile appends one batch to the output file
+func (c *Client) appendFile(body []byte) error {
+	if err := os.MkdirAll(filepath.Dir(c.file), 0755); err != nil {
+		return fmt.Errorf("failed to create influx output directory: %w", err)
+	}
+	f, err := os.OpenFile(c.file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open influx output file: %w", err)
+	}
+	if _, err := f.Write(body); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write influx output file: %w", err)
+	}
+	return f.Close()
+}
+
+// AppendLine appends point as one newline-terminated line of line protocol to buf. Tags
+// and fields are sorted by key; fields of unsupported types are skipped.
+func AppendLine(buf []byte, point Point) []byte {
+	buf = append(buf, escape(point.Measurement, ", ")...)
+	for _, key := range sortedKeys(point.Tags) {
+		if point.Tags[key] == "" {
+			continue // Line protocol has no empty tag values
+		}
+		buf = append(buf, ',')
+		buf = append(buf, escape(key, ",= ")...)
+		buf = append(buf, '=')
+		buf = append(buf, escape(point.Tags[key], ",= ")...)
+	}
+
+	first := true
+	for _, key := range sortedKeys(point.Fields) {
+		value, ok := formatField(point.Fields[key])
+		if !ok {
+			continue
+		}
+		if first {
+			buf = append(buf, ' ')
+		} else {
+			buf = append(buf, ',')
+		}
+		first = false
+		buf = append(buf, escape(key, ",= ")...)
+		buf = append(buf, '=')
+		buf = append(buf, value...)
+	}
+
+	buf = append(buf, ' ')
+	buf = strconv.AppendInt(buf, point.Time.UnixNano(), 10)
+	return append(buf, '\n')
+}
+
+// formatField renders a field value, with integers suffixed by i and strings quoted
+func formatField(value any) (string, bool) {
+	switch v := value.(type) {
+	case int:
+		return strconv.Itoa(v) + "i", true
+	case int64:
+		return strconv.FormatInt(v, 10) + "i", true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(v), true
+	case string:
+		return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(v) + `"`, true
+	}
+	return "", false
+}
+
+// escape backslash-escapes the characters in special, and replaces newlines, which line
+// protocol cannot escape
+func escape(s, special string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r == '\n' || r == '\r' {
+			r = ' '
+		}
+		if strings.ContainsRune(special, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,118 @@
+package influx
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	errs "github.com/penwyp/claudecat/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testTime = time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+func TestAppendLine(t *testing.T) {
+	line := AppendLine(nil, Point{
+		Measurement: "claude usage",
+		Tags:        map[string]string{"project": "my app,v2", "model": "claude-opus-4", "tag": ""},
+		Fields:      map[string]any{"total_tokens": 150, "cost_usd": 0.25, "note": `say "hi"`, "active": true, "skipped": []int{1}},
+		Time:        testTime,
+	})
+	assert.Equal(t, `claude\ usage,model=claude-opus-4,project=my\ app\,v2 active=true,cost_usd=0.25,note="say \"hi\"",total_tokens=150i 1773144000000000000`+"\n", string(line))
+}
+
+func TestClient_WriteHTTPBatches(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/write", r.URL.Path)
+		assert.Equal(t, "usage", r.URL.Query().Get("bucket"))
+		assert.Equal(t, "home", r.URL.Query().Get("org"))
+		assert.Equal(t, "ns", r.URL.Query().Get("precision"))
+		assert.Equal(t, "Token secret", r.Header.Get("Authorization"))
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, string(body))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client, err := New(Options{URL: server.URL, Token: "secret", Org: "home", Bucket: "usage", BatchSize: 2})
+	require.NoError(t, err)
+
+	points := make([]Point, 5)
+	for i := range points {
+		points[i] = Point{Measurement: "m", Fields: map[string]any{"v": i}, Time: testTime}
+	}
+	require.NoError(t, client.Write(context.Background(), points))
+	require.Len(t, requests, 3)
+	assert.Equal(t, 2, strings.Count(requests[0], "\n"))
+	assert.Equal(t, 1, strings.Count(requests[2], "\n"))
+}
+
+func TestClient_WriteRetries(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/write", r.URL.Path)
+		assert.Equal(t, "claude", r.URL.Query().Get("db"))
+		if attempts.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client, err := New(Options{URL: server.URL, Database: "claude", Retry: errs.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}})
+	require.NoError(t, err)
+	require.NoError(t, client.Write(context.Background(), []Point{{Measurement: "m", Fields: map[string]any{"v": 1}, Time: testTime}}))
+	assert.Equal(t, int32(3), attempts.Load())
+}
+
+func TestClient_WriteClientErrorIsPermanent(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		http.Error(w, "unauthorized access", http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	client, err := New(Options{URL: server.URL, Bucket: "usage", Retry: errs.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}})
+	require.NoError(t, err)
+	err = client.Write(context.Background(), []Point{{Measurement: "m", Fields: map[string]any{"v": 1}, Time: testTime}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unauthorized access")
+	assert.Equal(t, int32(1), attempts.Load())
+}
+
+func TestClient_WriteFileAppends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "influx", "usage.lp")
+	client, err := New(Options{File: path})
+	require.NoError(t, err)
+
+	point := Point{Measurement: "m", Fields: map[string]any{"v": 1.5}, Time: testTime}
+	require.NoError(t, client.Write(context.Background(), []Point{point}))
+	require.NoError(t, client.Write(context.Background(), []Point{point}))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, strings.Repeat("m v=1.5 1773144000000000000\n", 2), string(data))
+}
+
+func TestNew_Invalid(t *testing.T) {
+	for _, opts := range []Options{
+		{},
+		{URL: "http://localhost:8086", File: "usage.lp"},
+		{URL: "localhost:8086", Bucket: "usage"},
+		{URL: "http://localhost:8086"},
+	} {
+		_, err := New(opts)
+		assert.Error(t, err, "%+v", opts)
+	}
+}
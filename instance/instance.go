@@ -0,0 +1,260 @@
+// Package instance keeps a single claudecat monitor running per cache directory. Two
+// monitors sharing a cache overwrite each other's summaries and send every notification
+// twice, so monitors hold a PID file that names the running instance.
+package instance
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"syscall"
+	"time"
+
+	"github.com/bytedance/sonic"
+)
+
+// FileName is the name of the PID file in the cache directory
+const FileName = "claudecat.pid"
+
+// Info describes the process holding the lock
+type Info struct {
+	PID      int       `json:"pid"`
+	Command  string    `json:"command"`
+	Hostname string    `json:"hostname"`
+	Started  time.Time `json:"started"`
+}
+
+// String describes the instance for error messages
+func (i Info) String() string {
+	return fmt.Sprintf("pid %d on %s, %q, started %s", i.PID, i.Hostname, i.Command, i.Started.Local().Format("2006-01-02 15:04:05"))
+}
+
+// LockedError is returned when another instance holds the lock
+type LockedError struct {
+	Path   string // PID file
+	Holder Info
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("another claudecat instance is running (%s); stop it or use --force to run anyway (lock file: %s)", e.Holder, e.Path)
+}
+
+// Lock is a held single-instance lock
+type Lock struct {
+	path string
+	info Info
+}
+
+// Acquire takes the lock in dir. A PID file left behind by a process that has exited is
+// replaced; one held by a running process fails with a *LockedError unless force is set.
+func Acquire(dir, command string, force bool) (*Lock, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create lock directory: %w", err)
+	}
+	hostname, _ := os.Hostname()
+	lock := &Lock{
+		path: filepath.Join(dir, FileName),
+		info: Info{PID: os.Getpid(), Command: command, Hostname: hostname, Started: time.Now()},
+	}
+	data, err := sonic.Marshal(lock.info)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode lock: %w", err)
+	}
+
+	// A second attempt covers a stale file removed in between
+	for attempt := 0; attempt < 2; attempt++ {
+		if force {
+			if err := writeFile(lock.path, data, true); err != nil {
+				return nil, fmt.Errorf("failed to write lock file: %w", err)
+			}
+			return lock, nil
+		}
+
+		err := writeFile(lock.path, data, false)
+		if err == nil {
+			return lock, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("failed to create lock file: %w", err)
+		}
+
+		holder, held, err := currentHolder(lock.path)
+		if err != nil {
+			return nil, err
+		}
+		// The own PID is left by an earlier process that had it, as when a container restarts
+		if holder != nil && holder.PID == lock.info.PID && holder.Hostname == hostname {
+			if err := removeStale(lock.path, held); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if holder != nil {
+			return nil, &LockedError{Path: lock.path, Holder: *holder}
+		}
+	}
+	return nil, fmt.Errorf("failed to acquire lock %s", lock.path)
+}
+
+// Release removes the PID file, unless another instance has taken it over with --force
+func (l *Lock) Release() error {
+	holder, data, err := readInfo(l.path)
+	if err != nil || holder == nil || holder.PID != l.info.PID || holder.Hostname != l.info.Hostname {
+		return err
+	}
+	return removeStale(l.path, data)
+}
+
+// Holder returns the running instance holding the lock in dir, or nil when there is none.
+// Stale PID files, whose process has exited or can't be read, are removed.
+func Holder(dir string) (*Info, error) {
+	info, _, err := currentHolder(filepath.Join(dir, FileName))
+	return info, err
+}
+
+// currentHolder returns the running instance holding the lock at path and the contents of
+// its PID file, removing the file when it is stale
+func currentHolder(path string) (*Info, []byte, error) {
+	info, data, err := readInfo(path)
+	if err != nil || info == nil {
+		return nil, nil, err
+	}
+	if info.Hostname == currentHostname() && !processAlive(info.PID) {
+		if err := removeStale(path, data); err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, nil
+	}
+	// A process on another host sharing the directory can't be checked, so it counts as running
+	return info, data, nil
+}
+
+// removeStale removes the PID file at path if it still holds stale, the contents it had
+// when it was found stale. The file is moved aside and checked before it is deleted, so a
+// live lock another process put in place in between is restored rather than removed.
+func removeStale(path string, stale []byte) error {
+	aside := fmt.Sprintf("%s.%d.%d.stale", path, os.Getpid(), time.Now().UnixNano())
+	if err := os.Rename(path, aside); err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to remove stale lock file: %w", err)
+	}
+	defer os.Remove(aside)
+
+	moved, err := os.ReadFile(aside)
+	if err != nil {
+		return fmt.Errorf("failed to read lock file: %w", err)
+	}
+	if bytes.Equal(moved, stale) {
+		return nil
+	}
+	// Put the newer lock back, unless yet another process has created one since
+	if err := writeFile(path, moved, false); err != nil && !os.IsExist(err) {
+		return fmt.Errorf("failed to restore lock file: %w", err)
+	}
+	return nil
+}
+
+// linkFile creates a hard link; tests replace it to act as a filesystem without hard links
+var linkFile = os.Link
+
+// writeFile puts a PID file with data in place at path in one step, so other processes
+// never read it half written. Unless replace is set, an existing file is kept and the
+// error satisfies os.IsExist.
+func writeFile(path string, data []byte, replace bool) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), FileName+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if replace {
+		return os.Rename(tmp.Name(), path)
+	}
+	// Unlike a rename, a link fails when the PID file exists
+	err = linkFile(tmp.Name(), path)
+	if err == nil || os.IsExist(err) {
+		return err
+	}
+	// Filesystems without hard links, like exFAT and some network mounts, create the file
+	// exclusively instead. Readers leave it alone while it is written, see corruptGrace.
+	return createExclusive(path, data)
+}
+
+// createExclusive creates path with data, failing when it exists
+func createExclusive(path string, data []byte) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(data)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// corruptGrace is how long an unreadable PID file is left alone, in case an older version
+// that wrote the file in place is still writing it
+const corruptGrace = 10 * time.Second
+
+// readInfo reads a PID file and returns it with its contents; nil means there is none.
+// Unreadable contents, e.g. from a crash while it was written, are removed as stale once
+// they are older than corruptGrace.
+func readInfo(path string) (*Info, []byte, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read lock file: %w", err)
+	}
+	var info Info
+	if err := sonic.Unmarshal(data, &info); err != nil || info.PID <= 0 {
+		stat, err := os.Stat(path)
+		if os.IsNotExist(err) {
+			return nil, nil, nil
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read lock file: %w", err)
+		}
+		if time.Since(stat.ModTime()) < corruptGrace {
+			return nil, nil, fmt.Errorf("lock file %s is being written by another process; try again", path)
+		}
+		if err := removeStale(path, data); err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, nil
+	}
+	return &info, data, nil
+}
+
+func currentHostname() string {
+	hostname, _ := os.Hostname()
+	return hostname
+}
+
+// processAlive reports whether a process with the PID exists
+func processAlive(pid int) bool {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	// Windows only finds running processes and doesn't support signal 0
+	if runtime.GOOS == "windows" {
+		_ = process.Release()
+		return true
+	}
+	err = process.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, os.ErrPermission)
+}
@@ -0,0 +1,182 @@
+package instance
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeInfo writes a PID file as another process would
+func writeInfo(t *testing.T, dir string, info Info) {
+	t.Helper()
+	data, err := sonic.Marshal(info)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, FileName), data, 0644))
+}
+
+// exitedPID returns the PID of a process that has finished
+func exitedPID(t *testing.T) int {
+	t.Helper()
+	cmd := exec.Command("sh", "-c", "exit 0")
+	require.NoError(t, cmd.Run())
+	return cmd.Process.Pid
+}
+
+func TestAcquire(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "cache")
+
+	lock, err := Acquire(dir, "claudecat serve", false)
+	require.NoError(t, err)
+	holder, err := Holder(dir)
+	require.NoError(t, err)
+	require.NotNil(t, holder, "the own PID counts as running while the lock is held")
+	assert.Equal(t, os.Getpid(), holder.PID)
+	assert.Equal(t, "claudecat serve", holder.Command)
+
+	require.NoError(t, lock.Release())
+	assert.NoFileExists(t, filepath.Join(dir, FileName))
+}
+
+func TestAcquire_HeldByRunningProcess(t *testing.T) {
+	dir := t.TempDir()
+	hostname, _ := os.Hostname()
+	parent := Info{PID: os.Getppid(), Command: "claudecat", Hostname: hostname, Started: time.Now()}
+	writeInfo(t, dir, parent)
+
+	_, err := Acquire(dir, "claudecat serve", false)
+	var locked *LockedError
+	require.True(t, errors.As(err, &locked), "got %v", err)
+	assert.Equal(t, parent.PID, locked.Holder.PID)
+	assert.Contains(t, err.Error(), "--force")
+	assert.Contains(t, err.Error(), filepath.Join(dir, FileName))
+
+	// --force takes over, and the previous holder doesn't remove the new lock on exit
+	lock, err := Acquire(dir, "claudecat serve", true)
+	require.NoError(t, err)
+	previous := &Lock{path: filepath.Join(dir, FileName), info: parent}
+	require.NoError(t, previous.Release())
+	assert.FileExists(t, filepath.Join(dir, FileName))
+	require.NoError(t, lock.Release())
+	assert.NoFileExists(t, filepath.Join(dir, FileName))
+}
+
+func TestAcquire_ReplacesStaleLocks(t *testing.T) {
+	dir := t.TempDir()
+	hostname, _ := os.Hostname()
+
+	writeInfo(t, dir, Info{PID: exitedPID(t), Command: "claudecat", Hostname: hostname})
+	lock, err := Acquire(dir, "claudecat", false)
+	require.NoError(t, err)
+	require.NoError(t, lock.Release())
+
+	path := filepath.Join(dir, FileName)
+	require.NoError(t, os.WriteFile(path, []byte("garbage"), 0644))
+	old := time.Now().Add(-time.Minute)
+	require.NoError(t, os.Chtimes(path, old, old))
+	lock, err = Acquire(dir, "claudecat", false)
+	require.NoError(t, err)
+	require.NoError(t, lock.Release())
+
+	writeInfo(t, dir, Info{PID: os.Getpid(), Command: "claudecat", Hostname: hostname})
+	lock, err = Acquire(dir, "claudecat", false)
+	require.NoError(t, err, "a lock with the own PID is left from an earlier process")
+	require.NoError(t, lock.Release())
+}
+
+func TestAcquire_LeavesFileBeingWritten(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, FileName)
+	// Created, but not yet written, by another process
+	require.NoError(t, os.WriteFile(path, nil, 0644))
+
+	_, err := Acquire(dir, "claudecat", false)
+	require.Error(t, err)
+	assert.FileExists(t, path, "a file another process may still be writing isn't removed")
+
+	lock, err := Acquire(dir, "claudecat", true)
+	require.NoError(t, err)
+	holder, err := Holder(dir)
+	require.NoError(t, err)
+	require.NotNil(t, holder)
+	assert.Equal(t, os.Getpid(), holder.PID)
+	require.NoError(t, lock.Release())
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries, "no temporary files are left behind")
+}
+
+func TestAcquire_WithoutHardLinks(t *testing.T) {
+	linkFile = func(oldname, newname string) error {
+		return &os.LinkError{Op: "link", Old: oldname, New: newname, Err: syscall.ENOTSUP}
+	}
+	t.Cleanup(func() { linkFile = os.Link })
+
+	dir := t.TempDir()
+	lock, err := Acquire(dir, "claudecat", false)
+	require.NoError(t, err)
+	holder, err := Holder(dir)
+	require.NoError(t, err)
+	require.NotNil(t, holder)
+	assert.Equal(t, os.Getpid(), holder.PID)
+	require.NoError(t, lock.Release())
+
+	// The exclusive create still refuses a lock held by a running process
+	hostname, _ := os.Hostname()
+	writeInfo(t, dir, Info{PID: os.Getppid(), Command: "claudecat", Hostname: hostname, Started: time.Now()})
+	_, err = Acquire(dir, "claudecat", false)
+	var locked *LockedError
+	require.True(t, errors.As(err, &locked), "got %v", err)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "no temporary files are left behind")
+}
+
+func TestRemoveStale_KeepsReplacedLock(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, FileName)
+	hostname, _ := os.Hostname()
+
+	writeInfo(t, dir, Info{PID: exitedPID(t), Command: "claudecat", Hostname: hostname})
+	stale, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	// Another process replaces the stale lock after it was read
+	live := Info{PID: os.Getppid(), Command: "claudecat serve", Hostname: hostname, Started: time.Now()}
+	writeInfo(t, dir, live)
+	require.NoError(t, removeStale(path, stale))
+	holder, err := Holder(dir)
+	require.NoError(t, err)
+	require.NotNil(t, holder, "the live lock is put back")
+	assert.Equal(t, live.PID, holder.PID)
+
+	current, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, removeStale(path, current))
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestHolder_OtherHost(t *testing.T) {
+	dir := t.TempDir()
+	writeInfo(t, dir, Info{PID: exitedPID(t), Command: "claudecat", Hostname: "elsewhere"})
+
+	holder, err := Holder(dir)
+	require.NoError(t, err)
+	require.NotNil(t, holder, "processes on other hosts can't be checked")
+	assert.Equal(t, "elsewhere", holder.Hostname)
+
+	holder, err = Holder(t.TempDir())
+	require.NoError(t, err)
+	assert.Nil(t, holder)
+}
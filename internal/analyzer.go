@@ -13,6 +13,7 @@ import (
 	"github.com/penwyp/claudecat/logging"
 	"github.com/penwyp/claudecat/models"
 	"github.com/penwyp/claudecat/models/pricing"
+	"github.com/penwyp/claudecat/pkg/claudecat"
 )
 
 // Analyzer provides data analysis functionality
@@ -34,7 +35,7 @@ func NewAnalyzer(cfg *config.Config) (*Analyzer, error) {
 // Analyze performs analysis on the specified data paths
 func (a *Analyzer) Analyze(paths []string) ([]models.AnalysisResult, error) {
 	if len(paths) == 0 {
-		return nil, fmt.Errorf("no data paths found - please specify paths as arguments (e.g., claudecat analyze ~/claude-logs) or ensure ~/.claude/projects exists")
+		return nil, fmt.Errorf("no data paths found - please specify paths as arguments (e.g., claudecat analyze ~/claude-logs) or ensure ~/.claude/projects or ~/.config/claude/projects exists, or set CLAUDE_CONFIG_DIR")
 	}
 
 	logging.LogInfof("Starting analysis of %d paths: %v", len(paths), paths)
@@ -46,15 +47,15 @@ func (a *Analyzer) Analyze(paths []string) ([]models.AnalysisResult, error) {
 		cacheDir = filepath.Join(homeDir, cacheDir[2:])
 	}
 
-	// Create BadgerDB cache store if caching is enabled
+	// Open the configured summary cache backend
 	var cacheStore fileio.CacheStore
-	// Use file-based cache with memory preloading
-	fileCache, err := cache.NewFileBasedSummaryCache(cacheDir)
+	summaryStore, err := claudecat.OpenSummaryStore(a.config)
 	if err != nil {
-		logging.LogErrorf("Failed to create file-based cache: %v", err)
+		logging.LogErrorf("Failed to open %s summary cache: %v", a.config.Cache.Backend, err)
 		// Cache is disabled on error
 	} else {
-		cacheStore = fileCache
+		defer summaryStore.Close()
+		cacheStore = summaryStore
 	}
 
 	// Create pricing provider
@@ -65,26 +66,36 @@ func (a *Analyzer) Analyze(paths []string) ([]models.AnalysisResult, error) {
 		pricingProvider = pricing.NewDefaultProvider()
 	}
 
+	costMode, err := models.ParseCostMode(a.config.Data.CostMode)
+	if err != nil {
+		logging.LogWarnf("%v, falling back to auto", err)
+	}
+
+	validation := a.config.Data.Validation
+	validator := models.NewEntryValidator(models.EntryBounds(validation.Bounds), validation.Action, validation.IncludeSuspect)
+
+	var dedupIndex *cache.DedupIndex
+	if a.config.Data.Deduplication {
+		if dedupIndex, err = cache.OpenDedupIndex(cacheDir, a.config.Data.DedupRetention); err != nil {
+			logging.LogWarnf("Persistent deduplication disabled: %v", err)
+		}
+	}
+
 	var allResults []models.AnalysisResult
 	for _, path := range paths {
-		// Use LoadUsageEntries with caching support
 		opts := fileio.LoadUsageEntriesOptions{
 			DataPath:            path,
-			Mode:                models.CostModeCalculated,
+			Mode:                costMode,
 			CacheStore:          cacheStore,
 			EnableDeduplication: a.config.Data.Deduplication,
+			DedupIndex:          dedupIndex,
 			PricingProvider:     pricingProvider,
+			Validator:           validator,
 		}
 
-		result, err := fileio.LoadUsageEntries(opts)
-		if err != nil {
-			logging.LogErrorf("Failed to load usage entries from %s: %v", path, err)
-			continue
-		}
-
-		// Convert usage entries to analysis results
-		for _, entry := range result.Entries {
-			analysisResult := models.AnalysisResult{
+		// Stream entries so only the analysis results are kept in memory
+		metadata, err := fileio.StreamUsageEntries(opts, func(entry models.UsageEntry) error {
+			allResults = append(allResults, models.AnalysisResult{
 				Timestamp:           entry.Timestamp,
 				Model:               entry.Model,
 				SessionID:           a.generateSessionID(entry.Timestamp),
@@ -92,18 +103,24 @@ func (a *Analyzer) Analyze(paths []string) ([]models.AnalysisResult, error) {
 				OutputTokens:        entry.OutputTokens,
 				CacheCreationTokens: entry.CacheCreationTokens,
 				CacheReadTokens:     entry.CacheReadTokens,
+				ThinkingTokens:      entry.ThinkingTokens,
 				TotalTokens:         entry.TotalTokens,
 				CostUSD:             entry.CostUSD,
 				Count:               1,
 				Project:             entry.Project,
-			}
-			allResults = append(allResults, analysisResult)
+				Source:              entry.Source,
+			})
+			return nil
+		})
+		if err != nil {
+			logging.LogErrorf("Failed to load usage entries from %s: %v", path, err)
+			continue
 		}
 
 		logging.LogInfof("Processed %d entries from %s (files: %d, errors: %d)",
-			result.Metadata.EntriesLoaded, path,
-			result.Metadata.FilesProcessed,
-			len(result.Metadata.ProcessingErrors))
+			metadata.EntriesLoaded, path,
+			metadata.FilesProcessed,
+			len(metadata.ProcessingErrors))
 	}
 
 	// Sort results by timestamp
@@ -146,6 +163,7 @@ func (a *Analyzer) GetSummaryStats(results []models.AnalysisResult) models.Summa
 		stats.OutputTokens += result.OutputTokens
 		stats.CacheCreationTokens += result.CacheCreationTokens
 		stats.CacheReadTokens += result.CacheReadTokens
+		stats.ThinkingTokens += result.ThinkingTokens
 
 		stats.ModelCounts[result.Model]++
 
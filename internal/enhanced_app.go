@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
@@ -13,13 +14,21 @@ import (
 	"github.com/penwyp/claudecat/calculations"
 	"github.com/penwyp/claudecat/config"
 	"github.com/penwyp/claudecat/errors"
+	"github.com/penwyp/claudecat/fileio"
 	"github.com/penwyp/claudecat/logging"
 	"github.com/penwyp/claudecat/models"
+	"github.com/penwyp/claudecat/notify"
 	"github.com/penwyp/claudecat/orchestrator"
 	"github.com/penwyp/claudecat/output"
 	"github.com/penwyp/claudecat/sessions"
 )
 
+// loadProgressInterval is how often the progress of the initial load is redrawn
+const loadProgressInterval = 100 * time.Millisecond
+
+// dataPathPollInterval is how often missing data locations are checked for on first run
+const dataPathPollInterval = 2 * time.Second
+
 // EnhancedApplication represents the main application orchestrator using the new architecture
 type EnhancedApplication struct {
 	config       *config.Config
@@ -28,6 +37,12 @@ type EnhancedApplication struct {
 	cache        *cache.Store
 	formatter    *output.ConsoleFormatter
 	errorHandler *errors.EnhancedErrorHandler
+	notifiers    []notify.Notifier
+	limitWarner  *notify.LimitWarner
+
+	// Configuration reload on SIGHUP
+	configLoader       func() (*config.Config, error)
+	refreshRateChanged chan struct{}
 
 	ctx    context.Context
 	cancel context.CancelFunc
@@ -37,6 +52,12 @@ type EnhancedApplication struct {
 	logger         logging.LoggerInterface
 	currentData    orchestrator.MonitoringData
 	currentMetrics *calculations.RealtimeMetrics
+	budgetAlert    *calculations.BudgetAlert
+	costAlert      *calculations.SessionCostAlert
+	anomaly        *calculations.Anomaly
+	loadProgress   *fileio.LoadProgress // Progress of the initial load; nil once data arrived
+	waitingPaths   []string             // Locations searched while none exists; nil once found
+	stopWaiting    context.CancelFunc
 	dataMutex      sync.RWMutex
 
 	// Application state
@@ -46,14 +67,21 @@ type EnhancedApplication struct {
 
 // NewEnhancedApplication creates a new enhanced application instance
 func NewEnhancedApplication(cfg *config.Config) (*EnhancedApplication, error) {
+	logger, err := logging.New(LoggingOptions(cfg))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create logger: %w", err)
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 
 	app := &EnhancedApplication{
 		config:       cfg,
 		ctx:          ctx,
 		cancel:       cancel,
-		logger:       logging.NewLogger(cfg.App.LogLevel, cfg.App.LogFile),
+		logger:       logger,
 		errorHandler: errors.NewEnhancedErrorHandler(),
+
+		refreshRateChanged: make(chan struct{}, 1),
 	}
 
 	if err := app.bootstrap(); err != nil {
@@ -64,6 +92,12 @@ func NewEnhancedApplication(cfg *config.Config) (*EnhancedApplication, error) {
 	return app, nil
 }
 
+// SetConfigLoader sets how the configuration is re-read when the process receives SIGHUP.
+// Without a loader SIGHUP is ignored.
+func (ea *EnhancedApplication) SetConfigLoader(loader func() (*config.Config, error)) {
+	ea.configLoader = loader
+}
+
 // Run starts the enhanced application and blocks until shutdown
 func (ea *EnhancedApplication) Run() error {
 	ea.mu.Lock()
@@ -129,10 +163,19 @@ func (ea *EnhancedApplication) bootstrap() error {
 	// Initialize metrics calculator
 	ea.metricsCalc = calculations.NewEnhancedMetricsCalculator(ea.config)
 
+	// Retry failed startup with the configured policy
+	ea.errorHandler.SetRetryPolicy(errors.NewRetryPolicy(ea.config.Performance.Retry))
+
 	// Cache warming functionality has been removed as part of cache simplification
 
 	// Initialize orchestrator with data paths
-	dataPath := ea.getDataPath()
+	dataPaths, err := ea.getDataPaths()
+	if err != nil {
+		return err
+	}
+	if len(fileio.ExistingDataPaths(dataPaths)) == 0 {
+		ea.waitingPaths = ea.dataPathCandidates()
+	}
 	updateInterval := time.Duration(ea.config.UI.RefreshRate)
 	if updateInterval <= 0 {
 		updateInterval = 10 * time.Second // Default
@@ -140,16 +183,40 @@ func (ea *EnhancedApplication) bootstrap() error {
 
 	ea.orchestrator = orchestrator.NewMonitoringOrchestrator(
 		updateInterval,
-		dataPath,
+		dataPaths,
 		ea.config,
 	)
 
 	// Initialize console formatter
 	ea.formatter = output.NewConsoleFormatter(
 		ea.config.Subscription.Plan,
-		ea.config.UI.Timezone,
+		ea.timezoneName(),
 		ea.config.UI.TimeFormat,
 	)
+	ea.formatter.SetPlanCatalog(calculations.NewPlanCatalogFromConfig(ea.config.Subscription))
+	ea.formatter.SetLimitOverrides(
+		ea.config.Subscription.CustomTokenLimit,
+		ea.config.Subscription.CustomCostLimit,
+		ea.config.Subscription.TokenLimitP90,
+	)
+	ea.formatter.SetSessionDuration(ea.config.Session.WindowDuration)
+	ea.formatter.SetFields(ea.config.UI.Fields)
+	if !ea.config.UI.CompactMode {
+		ea.orchestrator.SetLoadProgress(ea.onLoadProgress)
+	}
+
+	// Initialize limit notifications
+	if ea.config.Limits.Notifies(config.NotifyDesktop) {
+		desktop := notify.NewDesktopNotifier()
+		if desktop.Available() {
+			ea.notifiers = append(ea.notifiers, desktop)
+		} else {
+			ea.logger.Info("Desktop notifications unavailable: no notification tool found")
+		}
+	}
+	if len(ea.notifiers) > 0 {
+		ea.limitWarner = notify.NewLimitWarner(ea.config.Limits.WarnBefore)
+	}
 
 	return nil
 }
@@ -164,6 +231,12 @@ func (ea *EnhancedApplication) start() error {
 	// Register session change callback
 	ea.orchestrator.RegisterSessionCallback(ea.onSessionChange)
 
+	// Register budget alert callback
+	ea.orchestrator.RegisterBudgetCallback(ea.onBudgetAlert)
+
+	// Register burn rate spike callback
+	ea.orchestrator.RegisterAnomalyCallback(ea.onAnomaly)
+
 	// Set command line arguments for token limit calculation
 	// This would be set from the CLI args in a real implementation
 	ea.orchestrator.SetArgs(map[string]interface{}{
@@ -175,9 +248,24 @@ func (ea *EnhancedApplication) start() error {
 		return fmt.Errorf("failed to start orchestrator: %w", err)
 	}
 
+	// On first run there is nothing to load until Claude Code creates its data directory
+	ea.dataMutex.Lock()
+	waiting := ea.waitingPaths != nil && ea.stopWaiting == nil
+	if waiting {
+		ctx, cancel := context.WithCancel(ea.ctx)
+		ea.stopWaiting = cancel
+		ea.wg.Add(1)
+		go ea.watchForDataPaths(ctx)
+	}
+	ea.dataMutex.Unlock()
+	if waiting {
+		ea.logger.Info("No Claude data found yet, waiting for it to appear")
+		return nil
+	}
+
 	// Wait for initial data with timeout
 	ea.logger.Info("Waiting for initial data...")
-	if !ea.orchestrator.WaitForInitialData(10 * time.Second) {
+	if !ea.waitForInitialData(10 * time.Second) {
 		ea.logger.Warn("Timeout waiting for initial data, continuing anyway")
 	} else {
 		ea.logger.Info("Initial data received successfully")
@@ -186,6 +274,68 @@ func (ea *EnhancedApplication) start() error {
 	return nil
 }
 
+// waitForInitialData waits for the first data update, showing the load progress on stderr
+// unless running in compact mode
+func (ea *EnhancedApplication) waitForInitialData(timeout time.Duration) bool {
+	if ea.config.UI.CompactMode {
+		return ea.orchestrator.WaitForInitialData(timeout)
+	}
+
+	// Clear the progress line when done
+	defer fmt.Fprint(os.Stderr, "\r\033[K")
+
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if ea.orchestrator.WaitForInitialData(loadProgressInterval) {
+			return true
+		}
+
+		ea.dataMutex.RLock()
+		progress := ea.loadProgress
+		ea.dataMutex.RUnlock()
+		if progress != nil {
+			fmt.Fprint(os.Stderr, "\r\033[K"+ea.formatter.FormatLoadProgress(*progress))
+		}
+	}
+	return false
+}
+
+// watchForDataPaths waits for one of the data locations to appear, then starts monitoring it
+func (ea *EnhancedApplication) watchForDataPaths(ctx context.Context) {
+	defer ea.wg.Done()
+
+	paths := fileio.WaitForDataPaths(ctx, ea.dataPathCandidates, dataPathPollInterval)
+	if paths == nil {
+		return
+	}
+
+	ea.dataMutex.Lock()
+	ea.waitingPaths = nil
+	ea.stopWaiting = nil
+	ea.dataMutex.Unlock()
+
+	ea.logger.Infof("Found Claude data in %s, starting monitoring", strings.Join(paths, ", "))
+	ea.orchestrator.SetDataPaths(paths)
+}
+
+// stopWaitingForData abandons the first-run wait for data locations, if any
+func (ea *EnhancedApplication) stopWaitingForData() {
+	ea.dataMutex.Lock()
+	defer ea.dataMutex.Unlock()
+	if ea.stopWaiting != nil {
+		ea.stopWaiting()
+		ea.stopWaiting = nil
+	}
+	ea.waitingPaths = nil
+}
+
+// onLoadProgress records the progress of the initial load for display
+func (ea *EnhancedApplication) onLoadProgress(progress fileio.LoadProgress) {
+	ea.dataMutex.Lock()
+	ea.loadProgress = &progress
+	ea.dataMutex.Unlock()
+}
+
 // runInteractive starts the console output application
 func (ea *EnhancedApplication) runInteractive() error {
 	ea.logger.Info("Starting interactive console mode")
@@ -194,17 +344,15 @@ func (ea *EnhancedApplication) runInteractive() error {
 	fmt.Print("\033[H\033[2J")
 
 	// Create ticker for refresh
-	refreshRate := ea.config.UI.RefreshRate
-	if refreshRate <= 0 {
-		refreshRate = time.Second
-	}
-	ticker := time.NewTicker(refreshRate)
+	ticker := time.NewTicker(ea.displayRefreshRate())
 	defer ticker.Stop()
 
 	for {
 		select {
 		case <-ea.ctx.Done():
 			return nil
+		case <-ea.refreshRateChanged:
+			ticker.Reset(ea.displayRefreshRate())
 		case <-ticker.C:
 			// Clear screen and move cursor to top
 			fmt.Print("\033[H\033[2J")
@@ -213,8 +361,40 @@ func (ea *EnhancedApplication) runInteractive() error {
 			ea.dataMutex.RLock()
 			metrics := ea.currentMetrics
 			blocks := ea.currentData.Data.Blocks
+			rolling := ea.currentData.Rolling
+			budgetAlert := ea.budgetAlert
+			costAlert := ea.costAlert
+			anomaly := ea.anomaly
+			loadProgress := ea.loadProgress
+			waitingPaths := ea.waitingPaths
 			ea.dataMutex.RUnlock()
 
+			if waitingPaths != nil {
+				fmt.Print(ea.formatter.FormatWaitingForData(waitingPaths))
+				continue
+			}
+
+			// Loads that outlast the startup wait keep showing their progress
+			if loadProgress != nil {
+				fmt.Println(ea.formatter.FormatLoadProgress(*loadProgress))
+				continue
+			}
+
+			// Show the active session's cost alert, the latest burn rate spike for the hour it was
+			// detected in, and the latest budget alert until its period ends
+			var banners []string
+			if costAlert != nil {
+				banners = append(banners, costAlert.Message())
+			}
+			if anomaly != nil && time.Since(anomaly.DetectedAt) < time.Hour {
+				banners = append(banners, anomaly.Message())
+			}
+			if budgetAlert != nil && time.Now().Before(budgetAlert.Status.PeriodEnd) {
+				banners = append(banners, budgetAlert.Message())
+			}
+			ea.formatter.SetBanner(banners...)
+			ea.formatter.SetRollingTotals(rolling)
+
 			// Format and print
 			output := ea.formatter.Format(metrics, blocks)
 			fmt.Print(output)
@@ -222,9 +402,22 @@ func (ea *EnhancedApplication) runInteractive() error {
 	}
 }
 
+// displayRefreshRate returns how often the console is redrawn
+func (ea *EnhancedApplication) displayRefreshRate() time.Duration {
+	ea.mu.RLock()
+	defer ea.mu.RUnlock()
+	if ea.config.UI.RefreshRate <= 0 {
+		return time.Second
+	}
+	return ea.config.UI.RefreshRate
+}
+
 // runBackground runs in background mode without TUI
 func (ea *EnhancedApplication) runBackground() error {
 	ea.logger.Info("Starting background mode")
+	if ea.config.UI.IdleTimeout > 0 {
+		ea.logger.Infof("Refreshes are suspended after %s without data file writes", ea.config.UI.IdleTimeout)
+	}
 
 	// In background mode, just wait for context cancellation
 	<-ea.ctx.Done()
@@ -253,6 +446,7 @@ func (ea *EnhancedApplication) onDataUpdate(data orchestrator.MonitoringData) {
 
 	// Update metrics calculator with new session blocks
 	ea.metricsCalc.UpdateSessionBlocks(data.Data.Blocks)
+	ea.metricsCalc.SetTokenLimit(data.TokenLimit)
 
 	// Calculate enhanced metrics
 	metrics := ea.metricsCalc.Calculate()
@@ -262,7 +456,11 @@ func (ea *EnhancedApplication) onDataUpdate(data orchestrator.MonitoringData) {
 	// Store data for console output
 	ea.dataMutex.Lock()
 	ea.currentData = data
+	ea.loadProgress = nil
+	ea.costAlert = nil
 	if metrics != nil {
+		ea.costAlert = metrics.CostAlert
+
 		// Convert enhanced metrics to realtime metrics
 		burnRate := float64(0)
 		if metrics.BurnRate != nil {
@@ -278,17 +476,40 @@ func (ea *EnhancedApplication) onDataUpdate(data orchestrator.MonitoringData) {
 			}
 		}
 
+		// Convert project distribution
+		projectDistribution := make(map[string]calculations.ProjectMetrics)
+		for project, stats := range metrics.ProjectDistribution {
+			projectDistribution[project] = calculations.ProjectMetrics{
+				TokenCount: stats.TotalTokens,
+				Cost:       stats.Cost,
+				Percentage: stats.Percentage,
+				LastUsed:   stats.LastUsed,
+			}
+		}
+
 		ea.currentMetrics = &calculations.RealtimeMetrics{
-			CurrentTokens:     metrics.CurrentTokens,
-			CurrentCost:       metrics.CurrentCost,
-			BurnRate:          burnRate,
-			SessionStart:      metrics.SessionStart,
-			SessionEnd:        metrics.SessionEnd,
-			ModelDistribution: modelDistribution,
+			CurrentTokens:       metrics.CurrentTokens,
+			CurrentCost:         metrics.CurrentCost,
+			BurnRate:            burnRate,
+			SessionStart:        metrics.SessionStart,
+			SessionEnd:          metrics.SessionEnd,
+			ModelDistribution:   modelDistribution,
+			ProjectDistribution: projectDistribution,
+			SourceDistribution:  metrics.SourceDistribution,
 		}
 	}
 	ea.dataMutex.Unlock()
 
+	// Warn about reached or approaching limits
+	if ea.limitWarner != nil && metrics != nil {
+		tokensPerMinute := float64(0)
+		if metrics.BurnRate != nil {
+			tokensPerMinute = metrics.BurnRate.TokensPerMinute
+		}
+		ea.sendNotifications(ea.limitWarner.Check(data.Data.Blocks, data.TokenLimit, tokensPerMinute, time.Now()))
+		ea.sendNotifications(ea.limitWarner.CheckCost(metrics.CostAlert, time.Now()))
+	}
+
 	// Update application metrics
 	ea.updateApplicationMetrics(metrics)
 
@@ -298,12 +519,46 @@ func (ea *EnhancedApplication) onDataUpdate(data orchestrator.MonitoringData) {
 
 // onSessionChange handles session change events
 func (ea *EnhancedApplication) onSessionChange(eventType, sessionID string, sessionData interface{}) {
+	if eventType == string(orchestrator.SessionUpdate) {
+		return // Fired on every refresh
+	}
 	ea.logger.Infof("Session change: %s for session %s", eventType, sessionID)
 
 	// Handle session changes if needed
 	// This could be used for notifications, logging, etc.
 }
 
+// onBudgetAlert keeps the latest budget alert for the console banner and notifies about it
+func (ea *EnhancedApplication) onBudgetAlert(alert calculations.BudgetAlert) {
+	ea.dataMutex.Lock()
+	ea.budgetAlert = &alert
+	ea.dataMutex.Unlock()
+
+	ea.sendNotifications([]notify.Notification{notify.BudgetNotification(alert)})
+}
+
+// onAnomaly keeps the latest burn rate spike for the console banner and notifies about it
+func (ea *EnhancedApplication) onAnomaly(anomaly calculations.Anomaly) {
+	ea.dataMutex.Lock()
+	ea.anomaly = &anomaly
+	ea.dataMutex.Unlock()
+
+	ea.sendNotifications([]notify.Notification{notify.AnomalyNotification(anomaly)})
+}
+
+// sendNotifications delivers notifications to every notifier in the background
+func (ea *EnhancedApplication) sendNotifications(notifications []notify.Notification) {
+	if len(notifications) == 0 || len(ea.notifiers) == 0 {
+		return
+	}
+
+	ea.wg.Add(1)
+	go func() {
+		defer ea.wg.Done()
+		notify.Send(ea.ctx, ea.notifiers, notifications)
+	}()
+}
+
 // convertBlocksToSessions converts session blocks to the format expected by the legacy UI
 func (ea *EnhancedApplication) convertBlocksToSessions(blocks []models.SessionBlock) []*sessions.Session {
 	var result []*sessions.Session
@@ -360,32 +615,51 @@ func (ea *EnhancedApplication) updateApplicationMetrics(metrics *calculations.En
 	}
 }
 
-// getDataPath determines the data path to monitor
-func (ea *EnhancedApplication) getDataPath() string {
-	if len(ea.config.Data.Paths) > 0 {
-		path := ea.config.Data.Paths[0]
-		ea.logger.Infof("Using configured data path: %s", path)
-		return path
+// timezoneName returns the configured display timezone, falling back to the app timezone
+// and then the system zone
+func (ea *EnhancedApplication) timezoneName() string {
+	loc, err := ea.config.Location()
+	if err != nil {
+		ea.logger.Warnf("%v, using local time", err)
 	}
+	return loc.String()
+}
 
-	// Try default paths in order of preference
-	homeDir, _ := os.UserHomeDir()
-	defaultPaths := []string{
-		fmt.Sprintf("%s/.claude/projects", homeDir),
+// getDataPaths determines the data paths to monitor. It fails when no standard location
+// can be derived, as when CLAUDE_CONFIG_DIR lists no directories and HOME is unknown.
+func (ea *EnhancedApplication) getDataPaths() ([]string, error) {
+	if len(ea.config.Data.Paths) > 0 {
+		ea.logger.Infof("Using configured data paths: %s", strings.Join(ea.config.Data.Paths, ", "))
+		return ea.config.Data.Paths, nil
 	}
 
-	for _, path := range defaultPaths {
-		if _, err := os.Stat(path); err == nil {
-			ea.logger.Infof("Using discovered data path: %s", path)
-			return path
-		}
+	// Monitor every standard location that exists (CLAUDE_CONFIG_DIR, XDG, ~/.claude, platform-specific)
+	paths := fileio.DefaultDataPaths()
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("%w: specify one with --paths or set %s", orchestrator.ErrNoDataPaths, fileio.ClaudeConfigDirEnv)
+	}
+	if _, err := os.Stat(paths[0]); err == nil {
+		ea.logger.Infof("Using discovered data paths: %s", strings.Join(paths, ", "))
+		return paths, nil
 	}
 
-	// Fallback to first default path even if it doesn't exist
-	defaultPath := defaultPaths[0]
-	ea.logger.Warnf("No existing data paths found, using default: %s", defaultPath)
-	ea.logger.Warnf("To specify a custom path, use: claudecat run --paths /path/to/claude/data")
-	return defaultPath
+	// Fallback to the first default path even if it doesn't exist
+	ea.logger.Warnf("No existing data paths found, using default: %s", paths[0])
+	ea.logger.Warnf("To specify a custom path, use: claudecat --paths /path/to/claude/data or set %s", fileio.ClaudeConfigDirEnv)
+	return paths, nil
+}
+
+// dataPathCandidates returns the locations that may hold Claude data: the configured paths,
+// or otherwise every standard location
+func (ea *EnhancedApplication) dataPathCandidates() []string {
+	ea.mu.RLock()
+	configured := ea.config.Data.Paths
+	ea.mu.RUnlock()
+
+	if len(configured) > 0 {
+		return configured
+	}
+	return fileio.SourceDataPaths()
 }
 
 // handleSignals handles OS signals
@@ -421,10 +695,89 @@ func (ea *EnhancedApplication) handleSignals(sigCh <-chan os.Signal) {
 	}
 }
 
-// reloadConfig reloads the configuration
+// reloadConfig re-reads the configuration and applies the changed settings to the running
+// orchestrator, formatter and logger. Other settings take effect after a restart.
 func (ea *EnhancedApplication) reloadConfig() error {
-	// This would implement configuration reloading
-	ea.logger.Info("Configuration reload not implemented yet")
+	if ea.configLoader == nil {
+		ea.logger.Warn("Configuration reload is not available")
+		return nil
+	}
+
+	cfg, err := ea.configLoader()
+	if err != nil {
+		return fmt.Errorf("failed to reload configuration: %w", err)
+	}
+
+	ea.mu.Lock()
+	changes := config.Diff(ea.config, cfg)
+	ea.config = cfg
+	ea.mu.Unlock()
+
+	if !changes.Any() {
+		ea.logger.Info("Configuration unchanged")
+		return nil
+	}
+
+	if changes.LogLevel {
+		ea.logger.SetLevel(logging.ParseLogLevel(cfg.App.LogLevel))
+		ea.logger.SetModuleLevels(cfg.App.LogModules)
+		logging.SetLogLevel(cfg.App.LogLevel)
+		logging.SetModuleLevels(cfg.App.LogModules)
+	}
+
+	if changes.Plan {
+		ea.formatter.SetPlan(cfg.Subscription.Plan)
+		ea.formatter.SetPlanCatalog(calculations.NewPlanCatalogFromConfig(cfg.Subscription))
+		ea.formatter.SetLimitOverrides(
+			cfg.Subscription.CustomTokenLimit,
+			cfg.Subscription.CustomCostLimit,
+			cfg.Subscription.TokenLimitP90,
+		)
+		ea.metricsCalc.SetCostCeilings(calculations.SessionCostCeilings(cfg.Subscription))
+		ea.orchestrator.SetArgs(map[string]interface{}{
+			"plan": cfg.Subscription.Plan,
+		})
+	}
+
+	if changes.Timezone {
+		ea.formatter.SetTimeSettings(ea.timezoneName(), cfg.UI.TimeFormat)
+	}
+
+	if changes.Fields {
+		ea.formatter.SetFields(cfg.UI.Fields)
+	}
+
+	// The orchestrator refreshes the data after each change
+	ea.orchestrator.SetConfig(cfg)
+	if changes.RefreshRate {
+		ea.orchestrator.SetUpdateInterval(cfg.UI.RefreshRate)
+		select {
+		case ea.refreshRateChanged <- struct{}{}:
+		default:
+		}
+	}
+	if changes.DataPaths {
+		if dataPaths, err := ea.getDataPaths(); err != nil {
+			ea.logger.Warnf("%v, keeping the current data paths", err)
+		} else {
+			ea.stopWaitingForData()
+			ea.orchestrator.SetDataPaths(dataPaths)
+		}
+	}
+	if changes.CostMode {
+		mode, err := models.ParseCostMode(cfg.Data.CostMode)
+		if err != nil {
+			ea.logger.Warnf("%v, falling back to auto", err)
+		}
+		ea.orchestrator.SetCostMode(mode)
+	}
+
+	if names := changes.Names(); len(names) > 0 {
+		ea.logger.Infof("Configuration reloaded, applied changes to: %s", strings.Join(names, ", "))
+	}
+	if changes.Other {
+		ea.logger.Warn("Some changed settings take effect after a restart")
+	}
 	return nil
 }
 
@@ -12,6 +12,7 @@ import (
 	"github.com/penwyp/claudecat/config"
 	"github.com/penwyp/claudecat/logging"
 	"github.com/penwyp/claudecat/models"
+	"github.com/penwyp/claudecat/timeutil"
 )
 
 // Exporter provides data export functionality
@@ -50,9 +51,14 @@ func NewExporter(cfg *config.Config) (*Exporter, error) {
 		return nil, fmt.Errorf("configuration is required")
 	}
 
+	logger, err := logging.New(LoggingOptions(cfg))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create logger: %w", err)
+	}
+
 	return &Exporter{
 		config: cfg,
-		logger: logging.NewLogger(cfg.App.LogLevel, cfg.App.LogFile),
+		logger: logger,
 	}, nil
 }
 
@@ -137,7 +143,11 @@ func (e *Exporter) filterByTimeRange(data []models.AnalysisResult, options Expor
 	now := time.Now()
 	switch options.TimeRange {
 	case "today":
-		fromTime = now.Truncate(24 * time.Hour)
+		loc, err := e.config.Location()
+		if err != nil {
+			e.logger.Warnf("%v, using local time", err)
+		}
+		fromTime = timeutil.StartOfDay(now, loc)
 		toTime = now
 	case "week":
 		fromTime = now.AddDate(0, 0, -7)
@@ -192,6 +202,7 @@ func (e *Exporter) aggregateData(data []models.AnalysisResult) []models.Analysis
 			existing.OutputTokens += item.OutputTokens
 			existing.CacheCreationTokens += item.CacheCreationTokens
 			existing.CacheReadTokens += item.CacheReadTokens
+			existing.ThinkingTokens += item.ThinkingTokens
 			existing.TotalTokens += item.TotalTokens
 			existing.CostUSD += item.CostUSD
 			existing.Count += item.Count
@@ -225,7 +236,7 @@ func (e *Exporter) exportCSV(data []models.AnalysisResult, options ExportOptions
 	// Write header
 	header := []string{
 		"Timestamp", "Model", "Session ID", "Input Tokens", "Output Tokens",
-		"Cache Creation", "Cache Read", "Total Tokens", "Cost USD",
+		"Cache Creation", "Cache Read", "Thinking Tokens", "Total Tokens", "Cost USD",
 	}
 	if options.Aggregate {
 		header = append(header, "Count")
@@ -245,6 +256,7 @@ func (e *Exporter) exportCSV(data []models.AnalysisResult, options ExportOptions
 			strconv.Itoa(item.OutputTokens),
 			strconv.Itoa(item.CacheCreationTokens),
 			strconv.Itoa(item.CacheReadTokens),
+			strconv.Itoa(item.ThinkingTokens),
 			strconv.Itoa(item.TotalTokens),
 			fmt.Sprintf("%.4f", item.CostUSD),
 		}
@@ -0,0 +1,23 @@
+package internal
+
+import (
+	"github.com/penwyp/claudecat/config"
+	"github.com/penwyp/claudecat/logging"
+)
+
+// LoggingOptions builds the logger options for the application settings. Debug mode also
+// logs to the console.
+func LoggingOptions(cfg *config.Config) logging.Options {
+	return logging.Options{
+		Level:        cfg.App.LogLevel,
+		ModuleLevels: cfg.App.LogModules,
+		Format:       logging.LogFormat(cfg.App.LogFormat),
+		File:         cfg.App.LogFile,
+		Rotation: logging.RotationOptions{
+			MaxSize:    cfg.App.LogRotation.MaxSize,
+			Interval:   cfg.App.LogRotation.Interval,
+			MaxBackups: cfg.App.LogRotation.MaxBackups,
+		},
+		Console: cfg.Debug.Enabled,
+	}
+}
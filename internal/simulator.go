@@ -0,0 +1,341 @@
+package internal
+
+import (
+	"bufio"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/bytedance/sonic"
+)
+
+// SimulationProfile describes the usage pattern of a synthetic user
+type SimulationProfile struct {
+	Name                string
+	SessionsPerDay      [2]int // Inclusive min/max number of sessions started per day
+	MessagesPerSession  [2]int // Inclusive min/max number of assistant messages per session
+	InputTokens         [2]int // Inclusive min/max fresh input tokens per message
+	OutputTokens        [2]int // Inclusive min/max output tokens per message
+	CacheReadTokens     [2]int // Inclusive min/max cache read tokens per message
+	CacheCreationChance float64
+	LimitHitChance      float64 // Probability that a session ends with a usage limit message
+	ModelWeights        map[string]int
+}
+
+// SimulationProfiles contains the built-in simulation profiles
+var SimulationProfiles = map[string]SimulationProfile{
+	"light": {
+		Name:                "light",
+		SessionsPerDay:      [2]int{0, 2},
+		MessagesPerSession:  [2]int{5, 40},
+		InputTokens:         [2]int{50, 2000},
+		OutputTokens:        [2]int{100, 1500},
+		CacheReadTokens:     [2]int{0, 20000},
+		CacheCreationChance: 0.2,
+		LimitHitChance:      0,
+		ModelWeights:        map[string]int{"claude-sonnet-4-20250514": 9, "claude-3-5-haiku-20241022": 1},
+	},
+	"moderate": {
+		Name:                "moderate",
+		SessionsPerDay:      [2]int{1, 4},
+		MessagesPerSession:  [2]int{20, 120},
+		InputTokens:         [2]int{100, 4000},
+		OutputTokens:        [2]int{200, 3000},
+		CacheReadTokens:     [2]int{5000, 60000},
+		CacheCreationChance: 0.3,
+		LimitHitChance:      0.05,
+		ModelWeights:        map[string]int{"claude-sonnet-4-20250514": 7, "claude-opus-4-20250514": 2, "claude-3-5-haiku-20241022": 1},
+	},
+	"heavy": {
+		Name:                "heavy",
+		SessionsPerDay:      [2]int{3, 6},
+		MessagesPerSession:  [2]int{80, 400},
+		InputTokens:         [2]int{200, 8000},
+		OutputTokens:        [2]int{500, 8000},
+		CacheReadTokens:     [2]int{20000, 150000},
+		CacheCreationChance: 0.4,
+		LimitHitChance:      0.25,
+		ModelWeights:        map[string]int{"claude-opus-4-20250514": 5, "claude-sonnet-4-20250514": 5},
+	},
+}
+
+// simulatedProjects are the project names used for generated data
+var simulatedProjects = []string{"webapp", "api-server", "data-pipeline", "mobile-app", "infra", "docs"}
+
+// SimulateOptions configures synthetic data generation
+type SimulateOptions struct {
+	OutputDir string    // Directory to write project folders into (temp dir if empty)
+	Days      int       // Number of days of history to generate
+	Profile   string    // Name of the simulation profile
+	Projects  int       // Number of distinct projects
+	Seed      int64     // Random seed (0 = time based)
+	EndTime   time.Time // Generation stops at this time (now if zero)
+}
+
+// SimulateResult describes the generated data set
+type SimulateResult struct {
+	OutputDir   string    `json:"output_dir"`
+	Profile     string    `json:"profile"`
+	Files       int       `json:"files"`
+	Entries     int       `json:"entries"`
+	LimitEvents int       `json:"limit_events"`
+	StartTime   time.Time `json:"start_time"`
+	EndTime     time.Time `json:"end_time"`
+	Seed        int64     `json:"seed"`
+}
+
+// Simulator writes realistic synthetic Claude Code conversation logs
+type Simulator struct {
+	options SimulateOptions
+	profile SimulationProfile
+	rng     *rand.Rand
+	models  []string
+}
+
+// NewSimulator creates a new simulator, validating the options
+func NewSimulator(opts SimulateOptions) (*Simulator, error) {
+	profile, exists := SimulationProfiles[opts.Profile]
+	if !exists {
+		return nil, fmt.Errorf("unknown simulation profile: %s", opts.Profile)
+	}
+	if opts.Days < 1 {
+		return nil, fmt.Errorf("days must be at least 1")
+	}
+	if opts.Projects < 1 || opts.Projects > len(simulatedProjects) {
+		return nil, fmt.Errorf("projects must be between 1 and %d", len(simulatedProjects))
+	}
+	if opts.Seed == 0 {
+		opts.Seed = time.Now().UnixNano()
+	}
+	if opts.EndTime.IsZero() {
+		opts.EndTime = time.Now().UTC()
+	}
+
+	// Expand model weights into a lookup slice, sorted for deterministic output
+	var names []string
+	for model := range profile.ModelWeights {
+		names = append(names, model)
+	}
+	sort.Strings(names)
+	var weighted []string
+	for _, model := range names {
+		for i := 0; i < profile.ModelWeights[model]; i++ {
+			weighted = append(weighted, model)
+		}
+	}
+
+	return &Simulator{
+		options: opts,
+		profile: profile,
+		rng:     rand.New(rand.NewSource(opts.Seed)),
+		models:  weighted,
+	}, nil
+}
+
+// Run generates the synthetic data set
+func (s *Simulator) Run() (*SimulateResult, error) {
+	outputDir := s.options.OutputDir
+	if outputDir == "" {
+		dir, err := os.MkdirTemp("", "claudecat-sim-")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create temp directory: %w", err)
+		}
+		outputDir = dir
+	}
+
+	endTime := s.options.EndTime.UTC()
+	startDay := time.Date(endTime.Year(), endTime.Month(), endTime.Day(), 0, 0, 0, 0, time.UTC).
+		AddDate(0, 0, -(s.options.Days - 1))
+
+	result := &SimulateResult{
+		OutputDir: outputDir,
+		Profile:   s.profile.Name,
+		StartTime: startDay,
+		EndTime:   endTime,
+		Seed:      s.options.Seed,
+	}
+
+	for day := 0; day < s.options.Days; day++ {
+		dayStart := startDay.AddDate(0, 0, day)
+		sessions := s.between(s.profile.SessionsPerDay)
+
+		for i := 0; i < sessions; i++ {
+			// Sessions start during working hours, spread across the day
+			sessionStart := dayStart.Add(time.Duration(8+s.rng.Intn(14))*time.Hour +
+				time.Duration(s.rng.Intn(60))*time.Minute)
+			if sessionStart.After(endTime) {
+				continue
+			}
+
+			project := simulatedProjects[s.rng.Intn(s.options.Projects)]
+			lines, entries, limitHit := s.generateSession(project, sessionStart, endTime)
+			if entries == 0 {
+				continue
+			}
+
+			if err := s.writeSession(outputDir, project, lines); err != nil {
+				return nil, err
+			}
+
+			result.Files++
+			result.Entries += entries
+			if limitHit {
+				result.LimitEvents++
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// generateSession builds the JSONL records for one conversation
+func (s *Simulator) generateSession(project string, start, endTime time.Time) ([]map[string]interface{}, int, bool) {
+	sessionID := s.newUUID()
+	cwd := "/Users/demo/projects/" + project
+	messages := s.between(s.profile.MessagesPerSession)
+
+	var lines []map[string]interface{}
+	var parentUUID interface{}
+	timestamp := start
+	entries := 0
+
+	for i := 0; i < messages; i++ {
+		// Each turn ends with the response, which must not come after endTime
+		responseTime := timestamp.Add(time.Duration(2+s.rng.Intn(40)) * time.Second)
+		if responseTime.After(endTime) {
+			break
+		}
+
+		userUUID := s.newUUID()
+		lines = append(lines, map[string]interface{}{
+			"type":       "user",
+			"uuid":       userUUID,
+			"parentUuid": parentUUID,
+			"sessionId":  sessionID,
+			"cwd":        cwd,
+			"timestamp":  formatSimTime(timestamp),
+			"message": map[string]interface{}{
+				"role":    "user",
+				"content": "simulated prompt",
+			},
+		})
+
+		timestamp = responseTime
+
+		usage := map[string]interface{}{
+			"input_tokens":            s.between(s.profile.InputTokens),
+			"output_tokens":           s.between(s.profile.OutputTokens),
+			"cache_read_input_tokens": s.between(s.profile.CacheReadTokens),
+		}
+		if s.rng.Float64() < s.profile.CacheCreationChance {
+			usage["cache_creation_input_tokens"] = 1000 + s.rng.Intn(20000)
+		} else {
+			usage["cache_creation_input_tokens"] = 0
+		}
+
+		assistantUUID := s.newUUID()
+		lines = append(lines, map[string]interface{}{
+			"type":       "assistant",
+			"uuid":       assistantUUID,
+			"parentUuid": userUUID,
+			"sessionId":  sessionID,
+			"requestId":  "req_" + s.randomID(24),
+			"cwd":        cwd,
+			"timestamp":  formatSimTime(timestamp),
+			"message": map[string]interface{}{
+				"id":    "msg_" + s.randomID(24),
+				"type":  "message",
+				"role":  "assistant",
+				"model": s.models[s.rng.Intn(len(s.models))],
+				"content": []interface{}{
+					map[string]interface{}{"type": "text", "text": "simulated response"},
+				},
+				"usage": usage,
+			},
+		})
+		entries++
+		parentUUID = assistantUUID
+
+		// Think time between turns
+		timestamp = timestamp.Add(time.Duration(20+s.rng.Intn(220)) * time.Second)
+	}
+
+	limitHit := entries > 0 && s.rng.Float64() < s.profile.LimitHitChance && !timestamp.After(endTime)
+	if limitHit {
+		reset := timestamp.Truncate(time.Hour).Add(time.Duration(1+s.rng.Intn(4)) * time.Hour)
+		lines = append(lines, map[string]interface{}{
+			"type":      "system",
+			"uuid":      s.newUUID(),
+			"sessionId": sessionID,
+			"cwd":       cwd,
+			"timestamp": formatSimTime(timestamp),
+			"content":   fmt.Sprintf("Claude AI usage limit reached|%d", reset.Unix()),
+			"level":     "warning",
+		})
+	}
+
+	return lines, entries, limitHit
+}
+
+// writeSession writes one session file using Claude Code's project directory layout
+func (s *Simulator) writeSession(outputDir, project string, lines []map[string]interface{}) error {
+	projectDir := filepath.Join(outputDir, "-Users-demo-projects-"+project)
+	if err := os.MkdirAll(projectDir, 0755); err != nil {
+		return fmt.Errorf("failed to create project directory: %w", err)
+	}
+
+	sessionID, _ := lines[0]["sessionId"].(string)
+	file, err := os.Create(filepath.Join(projectDir, sessionID+".jsonl"))
+	if err != nil {
+		return fmt.Errorf("failed to create session file: %w", err)
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	for _, line := range lines {
+		data, err := sonic.Marshal(line)
+		if err != nil {
+			return fmt.Errorf("failed to marshal record: %w", err)
+		}
+		if _, err := writer.Write(append(data, '\n')); err != nil {
+			return fmt.Errorf("failed to write record: %w", err)
+		}
+	}
+
+	return writer.Flush()
+}
+
+// between returns a random integer within the inclusive range
+func (s *Simulator) between(r [2]int) int {
+	if r[1] <= r[0] {
+		return r[0]
+	}
+	return r[0] + s.rng.Intn(r[1]-r[0]+1)
+}
+
+// newUUID returns a random version 4 style UUID
+func (s *Simulator) newUUID() string {
+	b := make([]byte, 16)
+	s.rng.Read(b)
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// randomID returns a random alphanumeric identifier of length n
+func (s *Simulator) randomID(n int) string {
+	const alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = alphabet[s.rng.Intn(len(alphabet))]
+	}
+	return string(b)
+}
+
+// formatSimTime formats timestamps the way Claude Code writes them
+func formatSimTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05.000Z")
+}
@@ -0,0 +1,66 @@
+package internal
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/penwyp/claudecat/fileio"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulator_Run(t *testing.T) {
+	end := time.Date(2025, 6, 30, 18, 0, 0, 0, time.UTC)
+	simulate := func(dir string) *SimulateResult {
+		simulator, err := NewSimulator(SimulateOptions{
+			OutputDir: dir,
+			Days:      3,
+			Profile:   "moderate",
+			Projects:  2,
+			Seed:      42,
+			EndTime:   end,
+		})
+		require.NoError(t, err)
+		result, err := simulator.Run()
+		require.NoError(t, err)
+		return result
+	}
+
+	dir := t.TempDir()
+	result := simulate(dir)
+	assert.Equal(t, dir, result.OutputDir)
+	assert.Equal(t, time.Date(2025, 6, 28, 0, 0, 0, 0, time.UTC), result.StartTime)
+	require.Positive(t, result.Entries)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*", "*.jsonl"))
+	require.NoError(t, err)
+	assert.Len(t, files, result.Files)
+
+	// The generated logs load like real ones, within the requested range
+	loaded, err := fileio.LoadUsageEntries(fileio.LoadUsageEntriesOptions{DataPath: dir})
+	require.NoError(t, err)
+	assert.Len(t, loaded.Entries, result.Entries)
+	assert.Empty(t, loaded.Metadata.ProcessingErrors)
+	for _, entry := range loaded.Entries {
+		assert.False(t, entry.Timestamp.Before(result.StartTime), "entry at %v", entry.Timestamp)
+		assert.False(t, entry.Timestamp.After(end), "entry at %v", entry.Timestamp)
+		assert.Positive(t, entry.TotalTokens)
+	}
+
+	// The same seed generates the same data set
+	again := t.TempDir()
+	assert.Equal(t, result.Entries, simulate(again).Entries)
+	reloaded, err := fileio.LoadUsageEntries(fileio.LoadUsageEntriesOptions{DataPath: again})
+	require.NoError(t, err)
+	assert.Equal(t, loaded.Entries, reloaded.Entries)
+}
+
+func TestNewSimulator_InvalidOptions(t *testing.T) {
+	_, err := NewSimulator(SimulateOptions{Days: 1, Profile: "extreme", Projects: 1})
+	assert.Error(t, err)
+	_, err = NewSimulator(SimulateOptions{Days: 0, Profile: "light", Projects: 1})
+	assert.Error(t, err)
+	_, err = NewSimulator(SimulateOptions{Days: 1, Profile: "light", Projects: len(simulatedProjects) + 1})
+	assert.Error(t, err)
+}
@@ -0,0 +1,94 @@
+package logging
+
+// The global logger and the LogX helpers below keep the call sites that predate the
+// structured logger working. They pass through the same levels, modules and outputs.
+
+// Init initializes the global logger. Only the first call has an effect.
+func Init(opts Options) error {
+	var err error
+	loggerOnce.Do(func() {
+		var logger *Logger
+		if logger, err = New(opts); err == nil {
+			globalLogger = logger
+		}
+	})
+	return err
+}
+
+// InitLogger initializes the global logger instance with debug mode support
+func InitLogger(logLevel, logFile string, debugToConsole bool) {
+	if err := Init(Options{Level: logLevel, File: logFile, Console: debugToConsole}); err != nil {
+		panic(err.Error())
+	}
+}
+
+// SetLogLevel changes the level of the global logger, if it has been initialized
+func SetLogLevel(levelStr string) {
+	if globalLogger != nil {
+		globalLogger.SetLevel(ParseLogLevel(levelStr))
+	}
+}
+
+// SetModuleLevels replaces the per-module levels of the global logger, if it has been initialized
+func SetModuleLevels(levels map[string]string) {
+	if globalLogger != nil {
+		globalLogger.SetModuleLevels(levels)
+	}
+}
+
+// GetLogger returns the global logger instance
+func GetLogger() LoggerInterface {
+	if globalLogger == nil {
+		panic("Global logger not initialized. Call InitLogger first.")
+	}
+	return globalLogger
+}
+
+// Global convenience functions for logging
+func LogInfo(msg string) {
+	if globalLogger != nil {
+		globalLogger.Info(msg)
+	}
+}
+
+func LogInfof(format string, args ...interface{}) {
+	if globalLogger != nil {
+		globalLogger.Infof(format, args...)
+	}
+}
+
+func LogDebug(msg string) {
+	if globalLogger != nil {
+		globalLogger.Debug(msg)
+	}
+}
+
+func LogDebugf(format string, args ...interface{}) {
+	if globalLogger != nil {
+		globalLogger.Debugf(format, args...)
+	}
+}
+
+func LogWarn(msg string) {
+	if globalLogger != nil {
+		globalLogger.Warn(msg)
+	}
+}
+
+func LogWarnf(format string, args ...interface{}) {
+	if globalLogger != nil {
+		globalLogger.Warnf(format, args...)
+	}
+}
+
+func LogError(msg string) {
+	if globalLogger != nil {
+		globalLogger.Error(msg)
+	}
+}
+
+func LogErrorf(format string, args ...interface{}) {
+	if globalLogger != nil {
+		globalLogger.Errorf(format, args...)
+	}
+}
@@ -3,10 +3,10 @@ package logging
 import (
 	"context"
 	"fmt"
-	"github.com/bytedance/sonic"
 	"io"
-	"log"
+	"log/slog"
 	"os"
+	"runtime"
 	"strings"
 	"sync"
 	"time"
@@ -38,28 +38,21 @@ const (
 	FormatJSON LogFormat = "json"
 )
 
-// Output represents a log output destination
-type Output interface {
-	Write(entry LogEntry) error
-	Close() error
+// Options configures a Logger
+type Options struct {
+	Level        string            // Default level: debug, info, warn or error
+	ModuleLevels map[string]string // Levels for single packages, keyed by package name such as "cache"
+	Format       LogFormat         // text (default) or json
+	File         string            // Log file; empty disables file output
+	Rotation     RotationOptions   // Rotation of File
+	Console      bool              // Also write to stderr
 }
 
-// LogEntry represents a single log entry
-type LogEntry struct {
-	Timestamp time.Time              `json:"timestamp"`
-	Level     string                 `json:"level"`
-	Message   string                 `json:"message"`
-	Fields    map[string]interface{} `json:"fields,omitempty"`
-	Caller    string                 `json:"caller,omitempty"`
-}
-
-// Logger provides structured logging functionality
+// Logger provides structured logging on top of log/slog. Records carry the name of the
+// package that logged them as the "module" attribute, which also selects its level.
 type Logger struct {
-	level   LogLevel
-	outputs []Output
-	fields  map[string]interface{}
-	format  LogFormat
-	mu      sync.RWMutex
+	handler slog.Handler
+	levels  *levels
 }
 
 // LoggerInterface defines the public interface for logging
@@ -77,7 +70,7 @@ type LoggerInterface interface {
 	With(fields ...Field) LoggerInterface
 	WithContext(ctx context.Context) LoggerInterface
 	SetLevel(level LogLevel)
-	AddOutput(output Output)
+	SetModuleLevels(levels map[string]string)
 }
 
 var (
@@ -85,6 +78,51 @@ var (
 	loggerOnce   sync.Once
 )
 
+// Extra slog levels for fatal and panic messages
+const (
+	slogLevelFatal = slog.LevelError + 4
+	slogLevelPanic = slog.LevelError + 8
+)
+
+// New creates a logger writing to the configured file and/or stderr
+func New(opts Options) (*Logger, error) {
+	var writers []io.Writer
+	if opts.Console {
+		writers = append(writers, os.Stderr)
+	}
+	if opts.File != "" {
+		file, err := openRotatingFile(opts.File, opts.Rotation)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open log file %s: %w", opts.File, err)
+		}
+		writers = append(writers, file)
+	}
+	if len(writers) == 0 {
+		return nil, fmt.Errorf("log file must be specified when not logging to the console")
+	}
+	return NewWithWriter(io.MultiWriter(writers...), opts), nil
+}
+
+// NewWithWriter creates a logger writing to w; opts.File, opts.Rotation and opts.Console are ignored
+func NewWithWriter(w io.Writer, opts Options) *Logger {
+	handlerOpts := &slog.HandlerOptions{
+		Level:       slog.LevelDebug - 4, // Levels are checked by the Logger
+		ReplaceAttr: replaceLevelName,
+	}
+
+	var handler slog.Handler
+	if opts.Format == FormatJSON {
+		handler = slog.NewJSONHandler(w, handlerOpts)
+	} else {
+		handler = slog.NewTextHandler(w, handlerOpts)
+	}
+
+	l := &Logger{handler: handler, levels: &levels{}}
+	l.levels.defaultLevel.Set(toSlogLevel(ParseLogLevel(opts.Level)))
+	l.SetModuleLevels(opts.ModuleLevels)
+	return l
+}
+
 // NewLogger creates a new logger with the specified level and log file
 func NewLogger(levelStr string, logFile string) *Logger {
 	return NewLoggerWithDebug(levelStr, logFile, false)
@@ -92,35 +130,15 @@ func NewLogger(levelStr string, logFile string) *Logger {
 
 // NewLoggerWithDebug creates a new logger with optional console output for debug mode
 func NewLoggerWithDebug(levelStr string, logFile string, debugToConsole bool) *Logger {
-	level := parseLogLevel(levelStr)
-
-	logger := &Logger{
-		level:   level,
-		outputs: make([]Output, 0),
-		fields:  make(map[string]interface{}),
-		format:  FormatText,
-	}
-
-	// Add appropriate output based on debug mode
-	if debugToConsole {
-		logger.AddOutput(NewConsoleOutput(os.Stderr, FormatText))
-	}
-
-	if logFile != "" {
-		fileOutput, err := NewFileOutput(logFile, FormatText)
-		if err != nil {
-			panic(fmt.Sprintf("Failed to create file output for %s: %v", logFile, err))
-		}
-		logger.AddOutput(fileOutput)
-	} else if !debugToConsole {
-		panic("Log file must be specified when not in debug mode")
+	logger, err := New(Options{Level: levelStr, File: logFile, Console: debugToConsole})
+	if err != nil {
+		panic(err.Error())
 	}
-
 	return logger
 }
 
-// parseLogLevel parses a log level string
-func parseLogLevel(levelStr string) LogLevel {
+// ParseLogLevel parses a log level string, defaulting to info
+func ParseLogLevel(levelStr string) LogLevel {
 	switch strings.ToLower(levelStr) {
 	case "debug":
 		return LevelDebug
@@ -159,39 +177,126 @@ func levelToString(level LogLevel) string {
 	}
 }
 
-// log writes a log entry to all outputs
-func (l *Logger) log(level LogLevel, msg string, fields ...Field) {
-	if l.level > level {
-		return
+func toSlogLevel(level LogLevel) slog.Level {
+	switch level {
+	case LevelDebug:
+		return slog.LevelDebug
+	case LevelWarn:
+		return slog.LevelWarn
+	case LevelError:
+		return slog.LevelError
+	case LevelFatal:
+		return slogLevelFatal
+	case LevelPanic:
+		return slogLevelPanic
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// replaceLevelName names the fatal and panic levels, which slog prints as ERROR+4 and ERROR+8
+func replaceLevelName(groups []string, attr slog.Attr) slog.Attr {
+	if attr.Key != slog.LevelKey || len(groups) > 0 {
+		return attr
+	}
+	switch attr.Value.Any().(slog.Level) {
+	case slogLevelFatal:
+		attr.Value = slog.StringValue(levelToString(LevelFatal))
+	case slogLevelPanic:
+		attr.Value = slog.StringValue(levelToString(LevelPanic))
+	}
+	return attr
+}
+
+// levels holds the default and per-module levels shared by a logger and those derived from it
+type levels struct {
+	defaultLevel slog.LevelVar
+	mu           sync.RWMutex
+	modules      map[string]slog.Level
+}
+
+// enabled reports whether a record at level from module is logged
+func (lv *levels) enabled(level slog.Level, module string) bool {
+	lv.mu.RLock()
+	moduleLevel, ok := lv.modules[module]
+	lv.mu.RUnlock()
+	if ok {
+		return level >= moduleLevel
+	}
+	return level >= lv.defaultLevel.Level()
+}
+
+// minimum returns the lowest level any module logs at
+func (lv *levels) minimum() slog.Level {
+	lowest := lv.defaultLevel.Level()
+	lv.mu.RLock()
+	defer lv.mu.RUnlock()
+	for _, level := range lv.modules {
+		if level < lowest {
+			lowest = level
+		}
 	}
+	return lowest
+}
+
+// log writes a log entry
+func (l *Logger) log(level LogLevel, msg string, fields ...Field) {
+	l.write(level, func() string { return msg }, fields)
+}
 
-	l.mu.RLock()
-	defer l.mu.RUnlock()
+// logf writes a formatted log entry, formatting only when the entry is logged
+func (l *Logger) logf(level LogLevel, format string, args []interface{}) {
+	l.write(level, func() string { return fmt.Sprintf(format, args...) }, nil)
+}
 
-	entry := LogEntry{
-		Timestamp: time.Now(),
-		Level:     levelToString(level),
-		Message:   msg,
-		Fields:    make(map[string]interface{}),
+func (l *Logger) write(level LogLevel, msg func() string, fields []Field) {
+	slogLevel := toSlogLevel(level)
+	// Skip the caller lookup when no module logs at this level
+	if slogLevel < l.levels.minimum() {
+		return
 	}
 
-	// Copy logger fields
-	for k, v := range l.fields {
-		entry.Fields[k] = v
+	pc, module := caller()
+	if !l.levels.enabled(slogLevel, module) {
+		return
 	}
 
-	// Add provided fields
+	record := slog.NewRecord(time.Now(), slogLevel, msg(), pc)
+	if module != "" {
+		record.AddAttrs(slog.String("module", module))
+	}
 	for _, field := range fields {
-		entry.Fields[field.Key] = field.Value
+		record.AddAttrs(slog.Any(field.Key, field.Value))
 	}
+	if err := l.handler.Handle(context.Background(), record); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write log entry: %v\n", err)
+	}
+}
 
-	// Write to all outputs
-	for _, output := range l.outputs {
-		if err := output.Write(entry); err != nil {
-			// Log to stderr if we can't write to an output
-			log.Printf("Failed to write log entry: %v", err)
+// caller returns the program counter and package name of the first caller outside this package
+func caller() (uintptr, string) {
+	var pcs [10]uintptr
+	n := runtime.Callers(3, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if pkg := packageName(frame.Function); pkg != "logging" {
+			return frame.PC, pkg
 		}
+		if !more {
+			return 0, ""
+		}
+	}
+}
+
+// packageName returns the last element of the package path of a function name such as
+// github.com/penwyp/claudecat/cache.(*Store).Get
+func packageName(function string) string {
+	name := function[strings.LastIndex(function, "/")+1:]
+	if dot := strings.Index(name, "."); dot >= 0 {
+		return name[:dot]
 	}
+	return name
 }
 
 // Debug logs a debug message
@@ -201,7 +306,7 @@ func (l *Logger) Debug(msg string, fields ...Field) {
 
 // Debugf logs a formatted debug message
 func (l *Logger) Debugf(format string, args ...interface{}) {
-	l.log(LevelDebug, fmt.Sprintf(format, args...))
+	l.logf(LevelDebug, format, args)
 }
 
 // Info logs an info message
@@ -211,7 +316,7 @@ func (l *Logger) Info(msg string, fields ...Field) {
 
 // Infof logs a formatted info message
 func (l *Logger) Infof(format string, args ...interface{}) {
-	l.log(LevelInfo, fmt.Sprintf(format, args...))
+	l.logf(LevelInfo, format, args)
 }
 
 // Warn logs a warning message
@@ -221,7 +326,7 @@ func (l *Logger) Warn(msg string, fields ...Field) {
 
 // Warnf logs a formatted warning message
 func (l *Logger) Warnf(format string, args ...interface{}) {
-	l.log(LevelWarn, fmt.Sprintf(format, args...))
+	l.logf(LevelWarn, format, args)
 }
 
 // Error logs an error message
@@ -231,7 +336,7 @@ func (l *Logger) Error(msg string, fields ...Field) {
 
 // Errorf logs a formatted error message
 func (l *Logger) Errorf(format string, args ...interface{}) {
-	l.log(LevelError, fmt.Sprintf(format, args...))
+	l.logf(LevelError, format, args)
 }
 
 // Fatal logs a fatal error and exits
@@ -242,30 +347,19 @@ func (l *Logger) Fatal(msg string, fields ...Field) {
 
 // Fatalf logs a formatted fatal error and exits
 func (l *Logger) Fatalf(format string, args ...interface{}) {
-	l.log(LevelFatal, fmt.Sprintf(format, args...))
+	l.logf(LevelFatal, format, args)
 	os.Exit(1)
 }
 
-// With returns a new logger with additional fields
+// With returns a new logger with additional fields. It shares its levels with l.
 func (l *Logger) With(fields ...Field) LoggerInterface {
-	l.mu.RLock()
-	defer l.mu.RUnlock()
-
-	newFields := make(map[string]interface{})
-	// Copy existing fields
-	for k, v := range l.fields {
-		newFields[k] = v
-	}
-	// Add new fields
+	attrs := make([]slog.Attr, 0, len(fields))
 	for _, field := range fields {
-		newFields[field.Key] = field.Value
+		attrs = append(attrs, slog.Any(field.Key, field.Value))
 	}
-
 	return &Logger{
-		level:   l.level,
-		outputs: l.outputs,
-		fields:  newFields,
-		format:  l.format,
+		handler: l.handler.WithAttrs(attrs),
+		levels:  l.levels,
 	}
 }
 
@@ -287,187 +381,19 @@ func (l *Logger) WithContext(ctx context.Context) LoggerInterface {
 	return l.With(fields...)
 }
 
-// SetLevel sets the logging level
+// SetLevel sets the default logging level
 func (l *Logger) SetLevel(level LogLevel) {
-	l.mu.Lock()
-	defer l.mu.Unlock()
-	l.level = level
-}
-
-// AddOutput adds a new output destination
-func (l *Logger) AddOutput(output Output) {
-	l.mu.Lock()
-	defer l.mu.Unlock()
-	l.outputs = append(l.outputs, output)
-}
-
-// InitLogger initializes the global logger instance with debug mode support
-func InitLogger(logLevel, logFile string, debugToConsole bool) {
-	loggerOnce.Do(func() {
-		globalLogger = NewLoggerWithDebug(logLevel, logFile, debugToConsole)
-	})
-}
-
-// GetLogger returns the global logger instance
-func GetLogger() LoggerInterface {
-	if globalLogger == nil {
-		panic("Global logger not initialized. Call InitLogger first.")
-	}
-	return globalLogger
-}
-
-// Global convenience functions for logging
-func LogInfo(msg string) {
-	if globalLogger != nil {
-		globalLogger.Info(msg)
-	}
-}
-
-func LogInfof(format string, args ...interface{}) {
-	if globalLogger != nil {
-		globalLogger.Infof(format, args...)
-	}
-}
-
-func LogDebug(msg string) {
-	if globalLogger != nil {
-		globalLogger.Debug(msg)
-	}
-}
-
-func LogDebugf(format string, args ...interface{}) {
-	if globalLogger != nil {
-		globalLogger.Debugf(format, args...)
-	}
-}
-
-func LogWarn(msg string) {
-	if globalLogger != nil {
-		globalLogger.Warn(msg)
-	}
-}
-
-func LogWarnf(format string, args ...interface{}) {
-	if globalLogger != nil {
-		globalLogger.Warnf(format, args...)
-	}
-}
-
-func LogError(msg string) {
-	if globalLogger != nil {
-		globalLogger.Error(msg)
-	}
-}
-
-func LogErrorf(format string, args ...interface{}) {
-	if globalLogger != nil {
-		globalLogger.Errorf(format, args...)
-	}
-}
-
-// ConsoleOutput writes logs to console
-type ConsoleOutput struct {
-	writer io.Writer
-	format LogFormat
-	mu     sync.Mutex
-}
-
-// NewConsoleOutput creates a new console output
-func NewConsoleOutput(writer io.Writer, format LogFormat) Output {
-	return &ConsoleOutput{
-		writer: writer,
-		format: format,
-	}
-}
-
-// Write writes a log entry to console
-func (c *ConsoleOutput) Write(entry LogEntry) error {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	var output string
-	if c.format == FormatJSON {
-		data, err := sonic.Marshal(entry)
-		if err != nil {
-			return err
-		}
-		output = string(data)
-	} else {
-		// Text format
-		timestamp := entry.Timestamp.Format("2006/01/02 15:04:05")
-		output = fmt.Sprintf("%s [%s] %s", timestamp, entry.Level, entry.Message)
-
-		// Add fields if any
-		if len(entry.Fields) > 0 {
-			fieldStrs := make([]string, 0, len(entry.Fields))
-			for k, v := range entry.Fields {
-				fieldStrs = append(fieldStrs, fmt.Sprintf("%s=%v", k, v))
-			}
-			output += " " + strings.Join(fieldStrs, " ")
-		}
-	}
-
-	_, err := fmt.Fprintln(c.writer, output)
-	return err
+	l.levels.defaultLevel.Set(toSlogLevel(level))
 }
 
-// Close closes the console output
-func (c *ConsoleOutput) Close() error {
-	return nil
-}
-
-// FileOutput writes logs to a file
-type FileOutput struct {
-	file   *os.File
-	format LogFormat
-	mu     sync.Mutex
-}
-
-// NewFileOutput creates a new file output
-func NewFileOutput(path string, format LogFormat) (Output, error) {
-	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
-	if err != nil {
-		return nil, err
-	}
-
-	return &FileOutput{
-		file:   file,
-		format: format,
-	}, nil
-}
-
-// Write writes a log entry to file
-func (f *FileOutput) Write(entry LogEntry) error {
-	f.mu.Lock()
-	defer f.mu.Unlock()
-
-	var output string
-	if f.format == FormatJSON {
-		data, err := sonic.Marshal(entry)
-		if err != nil {
-			return err
-		}
-		output = string(data)
-	} else {
-		// Text format with caller info
-		timestamp := entry.Timestamp.Format("2006/01/02 15:04:05")
-		output = fmt.Sprintf("%s [%s] %s", timestamp, entry.Level, entry.Message)
-
-		// Add fields if any
-		if len(entry.Fields) > 0 {
-			fieldStrs := make([]string, 0, len(entry.Fields))
-			for k, v := range entry.Fields {
-				fieldStrs = append(fieldStrs, fmt.Sprintf("%s=%v", k, v))
-			}
-			output += " " + strings.Join(fieldStrs, " ")
-		}
+// SetModuleLevels replaces the per-module levels, keyed by package name
+func (l *Logger) SetModuleLevels(moduleLevels map[string]string) {
+	modules := make(map[string]slog.Level, len(moduleLevels))
+	for module, level := range moduleLevels {
+		modules[module] = toSlogLevel(ParseLogLevel(level))
 	}
 
-	_, err := fmt.Fprintln(f.file, output)
-	return err
-}
-
-// Close closes the file
-func (f *FileOutput) Close() error {
-	return f.file.Close()
+	l.levels.mu.Lock()
+	defer l.levels.mu.Unlock()
+	l.levels.modules = modules
 }
@@ -0,0 +1,54 @@
+package logging_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/penwyp/claudecat/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogger_JSONFormat(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.NewWithWriter(&buf, logging.Options{Level: "info", Format: logging.FormatJSON})
+
+	logger.With(logging.Field{Key: "session", Value: "abc"}).Info("loaded", logging.Field{Key: "files", Value: 3})
+	logger.Debug("hidden")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 1)
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &entry))
+	assert.Equal(t, "INFO", entry["level"])
+	assert.Equal(t, "loaded", entry["msg"])
+	assert.Equal(t, "logging_test", entry["module"])
+	assert.Equal(t, "abc", entry["session"])
+	assert.Equal(t, float64(3), entry["files"])
+}
+
+func TestLogger_ModuleLevels(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.NewWithWriter(&buf, logging.Options{
+		Level:        "warn",
+		ModuleLevels: map[string]string{"logging_test": "debug"},
+	})
+
+	logger.Debugf("debug from %s", "test")
+	assert.Contains(t, buf.String(), "debug from test")
+
+	// Other modules fall back to the default level
+	buf.Reset()
+	logger.SetModuleLevels(map[string]string{"cache": "debug"})
+	logger.Info("info")
+	assert.Empty(t, buf.String())
+
+	// Derived loggers share the levels
+	derived := logger.With(logging.Field{Key: "k", Value: "v"})
+	logger.SetLevel(logging.LevelInfo)
+	derived.Info("info again")
+	assert.Contains(t, buf.String(), "info again")
+}
@@ -0,0 +1,171 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RotationOptions controls when the log file is rotated. Zero values disable that kind of
+// rotation.
+type RotationOptions struct {
+	MaxSize    int64         // Rotate before the file grows past this many bytes
+	Interval   time.Duration // Rotate when a write falls in a later interval than the previous one; intervals are aligned to UTC, so 24h rotates at UTC midnight
+	MaxBackups int           // Rotated files to keep; 0 keeps all
+}
+
+// backupTimeLayout is the timestamp appended to the names of rotated log files
+const backupTimeLayout = "20060102T150405.000"
+
+// rotatingFile is an append-only log file that renames itself to a timestamped backup when
+// it grows too large or a new interval starts
+type rotatingFile struct {
+	path      string
+	opts      RotationOptions
+	mu        sync.Mutex
+	file      *os.File
+	size      int64
+	lastWrite time.Time
+}
+
+// Every logger writing to the same path shares one rotatingFile, so a rotation by one of
+// them doesn't leave the others writing to the renamed backup
+var (
+	rotatingFiles   = make(map[string]*rotatingFile)
+	rotatingFilesMu sync.Mutex
+)
+
+// openRotatingFile returns the rotating writer for path, opening it on first use. The
+// rotation options of the first caller apply.
+func openRotatingFile(path string, opts RotationOptions) (*rotatingFile, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		absPath = path
+	}
+
+	rotatingFilesMu.Lock()
+	defer rotatingFilesMu.Unlock()
+
+	if file, exists := rotatingFiles[absPath]; exists {
+		return file, nil
+	}
+
+	file := &rotatingFile{path: absPath, opts: opts}
+	if err := file.open(); err != nil {
+		return nil, err
+	}
+	rotatingFiles[absPath] = file
+	return file, nil
+}
+
+// open opens the log file for appending, taking the size and last write time of an existing file
+func (f *rotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(f.path), 0755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	f.file = file
+	f.size = info.Size()
+	f.lastWrite = info.ModTime()
+	return nil
+}
+
+// Write appends p, rotating first when it's due. Rotation failures are reported on stderr
+// and the current file keeps being used.
+func (f *rotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := time.Now()
+	if f.rotationDue(len(p), now) {
+		if err := f.rotate(now); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to rotate log file %s: %v\n", f.path, err)
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	f.lastWrite = now
+	return n, err
+}
+
+// rotationDue reports whether writing n bytes at now should go to a fresh file
+func (f *rotatingFile) rotationDue(n int, now time.Time) bool {
+	if f.size == 0 {
+		return false
+	}
+	if f.opts.MaxSize > 0 && f.size+int64(n) > f.opts.MaxSize {
+		return true
+	}
+	return f.opts.Interval > 0 && !now.Truncate(f.opts.Interval).Equal(f.lastWrite.Truncate(f.opts.Interval))
+}
+
+// rotate renames the current file to a backup named after its last write, opens a new file
+// and removes backups beyond MaxBackups
+func (f *rotatingFile) rotate(now time.Time) error {
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+
+	ext := filepath.Ext(f.path)
+	backup := fmt.Sprintf("%s-%s%s", strings.TrimSuffix(f.path, ext), f.lastWrite.Format(backupTimeLayout), ext)
+	renameErr := os.Rename(f.path, backup)
+
+	// Keep logging even when the rename failed
+	if err := f.open(); err != nil {
+		return err
+	}
+	if renameErr != nil {
+		return renameErr
+	}
+	f.lastWrite = now
+	return f.removeOldBackups()
+}
+
+// removeOldBackups deletes the oldest backups so at most MaxBackups remain
+func (f *rotatingFile) removeOldBackups() error {
+	if f.opts.MaxBackups <= 0 {
+		return nil
+	}
+
+	ext := filepath.Ext(f.path)
+	prefix := strings.TrimSuffix(f.path, ext) + "-"
+	matches, err := filepath.Glob(prefix + "*" + ext)
+	if err != nil {
+		return err
+	}
+
+	// Only count files named like our backups
+	var backups []string
+	for _, match := range matches {
+		stamp := strings.TrimSuffix(strings.TrimPrefix(match, prefix), ext)
+		if _, err := time.Parse(backupTimeLayout, stamp); err == nil {
+			backups = append(backups, match)
+		}
+	}
+	if len(backups) <= f.opts.MaxBackups {
+		return nil
+	}
+
+	// Backup names end in their timestamp, so they sort oldest first
+	sort.Strings(backups)
+	for _, backup := range backups[:len(backups)-f.opts.MaxBackups] {
+		if err := os.Remove(backup); err != nil {
+			return err
+		}
+	}
+	return nil
+}
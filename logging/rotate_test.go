@@ -0,0 +1,72 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotatingFile_MaxSize(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	file, err := openRotatingFile(path, RotationOptions{MaxSize: 10, MaxBackups: 2})
+	require.NoError(t, err)
+	defer file.file.Close()
+
+	for i := 0; i < 5; i++ {
+		_, err := file.Write([]byte("12345678\n"))
+		require.NoError(t, err)
+		// Backup names have millisecond resolution
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	backups, err := filepath.Glob(filepath.Join(dir, "app-*.log"))
+	require.NoError(t, err)
+	assert.Len(t, backups, 2)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "12345678\n", string(data))
+}
+
+func TestRotatingFile_Interval(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	file, err := openRotatingFile(path, RotationOptions{Interval: time.Hour})
+	require.NoError(t, err)
+	defer file.file.Close()
+
+	_, err = file.Write([]byte("first\n"))
+	require.NoError(t, err)
+	assert.False(t, file.rotationDue(1, file.lastWrite))
+
+	// Pretend the last write was in the previous hour
+	file.lastWrite = file.lastWrite.Add(-time.Hour)
+	_, err = file.Write([]byte("second\n"))
+	require.NoError(t, err)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	for _, entry := range entries {
+		if entry.Name() != "app.log" {
+			assert.True(t, strings.HasPrefix(entry.Name(), "app-"))
+		}
+	}
+}
+
+func TestOpenRotatingFile_SharedPerPath(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	first, err := openRotatingFile(path, RotationOptions{})
+	require.NoError(t, err)
+	defer first.file.Close()
+
+	second, err := openRotatingFile(path, RotationOptions{MaxSize: 1})
+	require.NoError(t, err)
+	assert.Same(t, first, second)
+}
@@ -0,0 +1,197 @@
+// Package mcp implements a Model Context Protocol server over stdio, so MCP clients such as
+// Claude Code can query claudecat's live usage data from inside a conversation.
+package mcp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/penwyp/claudecat/calculations"
+	"github.com/penwyp/claudecat/config"
+	"github.com/penwyp/claudecat/history"
+	"github.com/penwyp/claudecat/logging"
+	"github.com/penwyp/claudecat/orchestrator"
+)
+
+// ProtocolVersion is the MCP revision this server implements
+const ProtocolVersion = "2024-11-05"
+
+// maxMessageSize bounds a single JSON-RPC message read from the client
+const maxMessageSize = 4 * 1024 * 1024
+
+// JSON-RPC 2.0 error codes
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeInternalError  = -32603
+)
+
+// Server answers MCP requests from the orchestrator's latest MonitoringData. Daily reports
+// for days outside the monitored window come from the trend history store.
+type Server struct {
+	version     string
+	store       *history.Store
+	metricsCalc *calculations.EnhancedMetricsCalculator
+	tools       []tool
+	now         func() time.Time
+
+	mu   sync.RWMutex
+	data *orchestrator.MonitoringData
+
+	writeMu sync.Mutex
+}
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// NewServer creates an MCP server reporting the given claudecat version. Daily reports
+// follow the store's time zone.
+func NewServer(version string, cfg *config.Config, store *history.Store) *Server {
+	s := &Server{
+		version:     version,
+		store:       store,
+		metricsCalc: calculations.NewEnhancedMetricsCalculator(cfg),
+		now:         time.Now,
+	}
+	s.tools = s.registerTools()
+	return s
+}
+
+// Update stores the latest monitoring data. It matches orchestrator.DataUpdateCallback,
+// so it can be registered directly with the orchestrator.
+func (s *Server) Update(data orchestrator.MonitoringData) {
+	s.metricsCalc.UpdateSessionBlocks(data.Data.Blocks)
+
+	s.mu.Lock()
+	s.data = &data
+	s.mu.Unlock()
+}
+
+// Serve reads newline-delimited JSON-RPC messages from r and writes responses to w until
+// r is exhausted. Nothing else may write to w while the server is running.
+func (s *Server) Serve(r io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxMessageSize)
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		if resp := s.handleMessage(line); resp != nil {
+			if err := s.write(w, resp); err != nil {
+				return err
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read MCP message: %w", err)
+	}
+	return nil
+}
+
+// handleMessage processes one message and returns the response, or nil for notifications
+func (s *Server) handleMessage(line []byte) *response {
+	var req request
+	if err := json.Unmarshal(line, &req); err != nil {
+		return errorResponse(nil, codeParseError, fmt.Sprintf("parse error: %v", err))
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		return errorResponse(req.ID, codeInvalidRequest, "invalid JSON-RPC 2.0 request")
+	}
+
+	// Requests without an id are notifications and never get a response
+	isNotification := len(req.ID) == 0
+	result, rpcErr := s.dispatch(req)
+	if isNotification {
+		if rpcErr != nil {
+			logging.LogDebugf("MCP notification %s failed: %s", req.Method, rpcErr.Message)
+		}
+		return nil
+	}
+	if rpcErr != nil {
+		return &response{JSONRPC: "2.0", ID: req.ID, Error: rpcErr}
+	}
+	return &response{JSONRPC: "2.0", ID: req.ID, Result: result}
+}
+
+func (s *Server) dispatch(req request) (interface{}, *rpcError) {
+	switch req.Method {
+	case "initialize":
+		return s.initialize(), nil
+	case "notifications/initialized", "notifications/cancelled":
+		return nil, nil
+	case "ping":
+		return struct{}{}, nil
+	case "tools/list":
+		return s.listTools(), nil
+	case "tools/call":
+		return s.callTool(req.Params)
+	default:
+		return nil, &rpcError{Code: codeMethodNotFound, Message: fmt.Sprintf("method not found: %s", req.Method)}
+	}
+}
+
+func (s *Server) initialize() map[string]interface{} {
+	return map[string]interface{}{
+		"protocolVersion": ProtocolVersion,
+		"capabilities": map[string]interface{}{
+			"tools": map[string]interface{}{},
+		},
+		"serverInfo": map[string]interface{}{
+			"name":    "claudecat",
+			"version": s.version,
+		},
+	}
+}
+
+// snapshot returns the latest data, or nil before the first update
+func (s *Server) snapshot() *orchestrator.MonitoringData {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.data
+}
+
+func (s *Server) write(w io.Writer, resp *response) error {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		logging.LogErrorf("Failed to encode MCP response: %v", err)
+		data, _ = json.Marshal(errorResponse(resp.ID, codeInternalError, "failed to encode response"))
+	}
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	if _, err := w.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write MCP response: %w", err)
+	}
+	return nil
+}
+
+func errorResponse(id json.RawMessage, code int, message string) *response {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	return &response{JSONRPC: "2.0", ID: id, Error: &rpcError{Code: code, Message: message}}
+}
@@ -0,0 +1,178 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/penwyp/claudecat/config"
+	"github.com/penwyp/claudecat/history"
+	"github.com/penwyp/claudecat/models"
+	"github.com/penwyp/claudecat/orchestrator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(t *testing.T, now time.Time) *Server {
+	t.Helper()
+	store, err := history.Open(t.TempDir(), 0, time.UTC)
+	require.NoError(t, err)
+
+	srv := NewServer("1.2.3", config.DefaultConfig(), store)
+	srv.now = func() time.Time { return now }
+	return srv
+}
+
+func testMonitoringData(now time.Time) orchestrator.MonitoringData {
+	entry := models.UsageEntry{
+		Timestamp:    now.Add(-30 * time.Minute),
+		Model:        "claude-sonnet-4-20250514",
+		InputTokens:  1000,
+		OutputTokens: 500,
+		TotalTokens:  1500,
+		CostUSD:      0.01,
+	}
+
+	return orchestrator.MonitoringData{
+		Data: orchestrator.AnalysisResult{
+			Blocks: []models.SessionBlock{
+				{ID: "active", StartTime: now.Add(-time.Hour), EndTime: now.Add(4 * time.Hour), IsActive: true,
+					Entries: []models.UsageEntry{entry}, CostUSD: 0.01},
+			},
+		},
+		TokenLimit:   44000,
+		SessionID:    "active",
+		SessionCount: 1,
+	}
+}
+
+// roundTrip sends the given messages and returns the decoded responses
+func roundTrip(t *testing.T, srv *Server, messages ...string) []map[string]interface{} {
+	t.Helper()
+	var out bytes.Buffer
+	require.NoError(t, srv.Serve(strings.NewReader(strings.Join(messages, "\n")+"\n"), &out))
+
+	var responses []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var resp map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &resp))
+		responses = append(responses, resp)
+	}
+	return responses
+}
+
+// toolText returns the text content of a tools/call response
+func toolText(t *testing.T, resp map[string]interface{}) (string, bool) {
+	t.Helper()
+	result, ok := resp["result"].(map[string]interface{})
+	require.True(t, ok, "response has no result: %v", resp)
+	content := result["content"].([]interface{})
+	require.Len(t, content, 1)
+	isError, _ := result["isError"].(bool)
+	return content[0].(map[string]interface{})["text"].(string), isError
+}
+
+func TestServer_InitializeAndListTools(t *testing.T) {
+	srv := newTestServer(t, time.Now())
+
+	responses := roundTrip(t, srv,
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05"}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+	)
+	require.Len(t, responses, 2, "notifications must not be answered")
+
+	initResult := responses[0]["result"].(map[string]interface{})
+	assert.Equal(t, ProtocolVersion, initResult["protocolVersion"])
+	assert.Equal(t, "1.2.3", initResult["serverInfo"].(map[string]interface{})["version"])
+
+	assert.EqualValues(t, 2, responses[1]["id"])
+	var names []string
+	for _, tool := range responses[1]["result"].(map[string]interface{})["tools"].([]interface{}) {
+		names = append(names, tool.(map[string]interface{})["name"].(string))
+	}
+	assert.Equal(t, []string{"get_current_session_usage", "get_daily_report"}, names)
+}
+
+func TestServer_ProtocolErrors(t *testing.T) {
+	srv := newTestServer(t, time.Now())
+
+	responses := roundTrip(t, srv,
+		`not json`,
+		`{"jsonrpc":"2.0","id":1,"method":"resources/list"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"nope"}}`,
+	)
+	require.Len(t, responses, 3)
+	assert.EqualValues(t, codeParseError, responses[0]["error"].(map[string]interface{})["code"])
+	assert.Nil(t, responses[0]["id"])
+	assert.EqualValues(t, codeMethodNotFound, responses[1]["error"].(map[string]interface{})["code"])
+	assert.EqualValues(t, codeInvalidParams, responses[2]["error"].(map[string]interface{})["code"])
+}
+
+func TestServer_CurrentSessionUsage(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	srv := newTestServer(t, now)
+
+	call := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"get_current_session_usage","arguments":{}}}`
+	text, isError := toolText(t, roundTrip(t, srv, call)[0])
+	assert.True(t, isError, "tool should fail before the first update")
+	assert.Contains(t, text, "not loaded yet")
+
+	srv.Update(testMonitoringData(now))
+	text, isError = toolText(t, roundTrip(t, srv, call)[0])
+	require.False(t, isError, text)
+
+	var usage SessionUsage
+	require.NoError(t, json.Unmarshal([]byte(text), &usage))
+	assert.True(t, usage.Active)
+	assert.Equal(t, "active", usage.SessionID)
+	assert.Equal(t, 44000, usage.TokenLimit)
+	require.NotNil(t, usage.Metrics)
+	assert.True(t, usage.Metrics.IsActive)
+}
+
+func TestServer_DailyReport(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	srv := newTestServer(t, now)
+	srv.Update(testMonitoringData(now))
+
+	// Stored history answers for days outside the monitored blocks
+	require.NoError(t, srv.store.MergeDaily([]history.DailySummary{
+		{Date: "2026-03-01", TotalTokens: 777, CostUSD: 1.5, Sessions: 2},
+	}))
+
+	tests := []struct {
+		name      string
+		arguments string
+		date      string
+		tokens    int
+	}{
+		{"defaults to today", `{}`, "2026-03-10", 1500},
+		{"explicit live day", `{"date":"2026-03-10"}`, "2026-03-10", 1500},
+		{"stored day", `{"date":"2026-03-01"}`, "2026-03-01", 777},
+		{"day without usage", `{"date":"2026-02-01"}`, "2026-02-01", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			call := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"get_daily_report","arguments":` + tt.arguments + `}}`
+			text, isError := toolText(t, roundTrip(t, srv, call)[0])
+			require.False(t, isError, text)
+
+			var day history.DailySummary
+			require.NoError(t, json.Unmarshal([]byte(text), &day))
+			assert.Equal(t, tt.date, day.Date)
+			assert.Equal(t, tt.tokens, day.TotalTokens)
+		})
+	}
+
+	call := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"get_daily_report","arguments":{"date":"March 1"}}}`
+	text, isError := toolText(t, roundTrip(t, srv, call)[0])
+	assert.True(t, isError)
+	assert.Contains(t, text, "invalid date")
+}
@@ -0,0 +1,166 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/penwyp/claudecat/calculations"
+	"github.com/penwyp/claudecat/history"
+)
+
+const dateLayout = "2006-01-02"
+
+// tool is an MCP tool definition together with its handler
+type tool struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	InputSchema map[string]interface{} `json:"inputSchema"`
+
+	handler func(args json.RawMessage) (interface{}, error)
+}
+
+// SessionUsage is the result of get_current_session_usage
+type SessionUsage struct {
+	Active       bool                                  `json:"active"`
+	SessionID    string                                `json:"session_id,omitempty"`
+	TokenLimit   int                                   `json:"token_limit"`
+	SessionCount int                                   `json:"session_count"`
+	Metrics      *calculations.EnhancedRealtimeMetrics `json:"metrics,omitempty"`
+	Budgets      []calculations.BudgetStatus           `json:"budgets,omitempty"`
+}
+
+type textContent struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type toolResult struct {
+	Content []textContent `json:"content"`
+	IsError bool          `json:"isError,omitempty"`
+}
+
+func (s *Server) registerTools() []tool {
+	return []tool{
+		{
+			Name:        "get_current_session_usage",
+			Description: "Token usage, cost, burn rate and projections of the current 5-hour Claude session, with the plan's token limit and any configured budgets.",
+			InputSchema: map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{},
+			},
+			handler: s.currentSessionUsage,
+		},
+		{
+			Name:        "get_daily_report",
+			Description: "Token usage, cost, session count and per-model breakdown of one calendar day.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"date": map[string]interface{}{
+						"type":        "string",
+						"description": "Day to report in YYYY-MM-DD format; defaults to today",
+					},
+				},
+			},
+			handler: s.dailyReport,
+		},
+	}
+}
+
+func (s *Server) listTools() map[string]interface{} {
+	return map[string]interface{}{"tools": s.tools}
+}
+
+// callTool runs a tool. Failures of the tool itself are reported in the result with
+// isError set, as MCP requires; only malformed calls are JSON-RPC errors.
+func (s *Server) callTool(params json.RawMessage) (interface{}, *rpcError) {
+	var call struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	}
+	if err := json.Unmarshal(params, &call); err != nil {
+		return nil, &rpcError{Code: codeInvalidParams, Message: fmt.Sprintf("invalid tool call: %v", err)}
+	}
+
+	for _, t := range s.tools {
+		if t.Name != call.Name {
+			continue
+		}
+		result, err := t.handler(call.Arguments)
+		if err != nil {
+			return toolResult{Content: []textContent{{Type: "text", Text: err.Error()}}, IsError: true}, nil
+		}
+		text, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return toolResult{Content: []textContent{{Type: "text", Text: fmt.Sprintf("failed to encode result: %v", err)}}, IsError: true}, nil
+		}
+		return toolResult{Content: []textContent{{Type: "text", Text: string(text)}}}, nil
+	}
+	return nil, &rpcError{Code: codeInvalidParams, Message: fmt.Sprintf("unknown tool: %s", call.Name)}
+}
+
+func (s *Server) currentSessionUsage(json.RawMessage) (interface{}, error) {
+	data := s.snapshot()
+	if data == nil {
+		return nil, fmt.Errorf("monitoring data not loaded yet, try again in a moment")
+	}
+
+	usage := SessionUsage{
+		TokenLimit:   data.TokenLimit,
+		SessionCount: data.SessionCount,
+		Budgets:      data.Budgets,
+	}
+	for _, block := range data.Data.Blocks {
+		if block.IsActive && !block.IsGap {
+			usage.Active = true
+			usage.SessionID = block.ID
+			usage.Metrics = s.metricsCalc.Calculate()
+			break
+		}
+	}
+	return usage, nil
+}
+
+// dailyReport summarizes one day from the monitored blocks, falling back to the trend
+// history for days the monitor no longer covers
+func (s *Server) dailyReport(args json.RawMessage) (interface{}, error) {
+	var params struct {
+		Date string `json:"date"`
+	}
+	if len(args) > 0 {
+		if err := json.Unmarshal(args, &params); err != nil {
+			return nil, fmt.Errorf("invalid arguments: %w", err)
+		}
+	}
+
+	now := s.now()
+	day := now.In(s.store.Location())
+	if params.Date != "" {
+		parsed, err := time.ParseInLocation(dateLayout, params.Date, s.store.Location())
+		if err != nil {
+			return nil, fmt.Errorf("invalid date %q (expected YYYY-MM-DD)", params.Date)
+		}
+		day = parsed
+	}
+	date := s.store.DayKey(day)
+
+	data := s.snapshot()
+	if data == nil {
+		return nil, fmt.Errorf("monitoring data not loaded yet, try again in a moment")
+	}
+	for _, summary := range s.store.SummarizeDays(data.Data.Blocks, now) {
+		if summary.Date == date {
+			return summary, nil
+		}
+	}
+
+	stored, err := s.store.Daily(day, day)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trend history: %w", err)
+	}
+	if len(stored) > 0 {
+		return stored[0], nil
+	}
+	return history.DailySummary{Date: date, PerModel: map[string]history.ModelUsage{}}, nil
+}
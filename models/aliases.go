@@ -0,0 +1,161 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// ModelAlias maps model identifiers matching Pattern to Model. Patterns match the whole
+// identifier, ignoring case, and may use * as a wildcard. An empty Model keeps the
+// identifier as is, lowercased.
+type ModelAlias struct {
+	Pattern string `json:"pattern"`
+	Model   string `json:"model"`
+
+	re *regexp.Regexp
+}
+
+// Matches reports whether the alias pattern matches model
+func (a ModelAlias) Matches(model string) bool {
+	if a.re == nil {
+		return strings.EqualFold(a.Pattern, model)
+	}
+	return a.re.MatchString(model)
+}
+
+// NewModelAlias compiles an alias mapping identifiers matching pattern to model
+func NewModelAlias(pattern, model string) (ModelAlias, error) {
+	if strings.TrimSpace(pattern) == "" {
+		return ModelAlias{}, fmt.Errorf("empty model alias pattern")
+	}
+
+	quoted := strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*")
+	re, err := regexp.Compile("(?i)^" + quoted + "$")
+	if err != nil {
+		return ModelAlias{}, fmt.Errorf("invalid model alias pattern %q: %w", pattern, err)
+	}
+	return ModelAlias{Pattern: pattern, Model: model, re: re}, nil
+}
+
+// mustModelAliases compiles the built-in alias table
+func mustModelAliases(pairs ...[2]string) []ModelAlias {
+	aliases := make([]ModelAlias, 0, len(pairs))
+	for _, pair := range pairs {
+		alias, err := NewModelAlias(pair[0], pair[1])
+		if err != nil {
+			panic(err)
+		}
+		aliases = append(aliases, alias)
+	}
+	return aliases
+}
+
+// builtinModelAliases map identifiers to pricing families, first match wins. Claude 4 and
+// later identifiers are kept since their pricing is looked up by the full name.
+var builtinModelAliases = mustModelAliases(
+	[2]string{"*opus-4-*", ""},
+	[2]string{"*sonnet-4-*", ""},
+	[2]string{"*haiku-4-*", ""},
+	[2]string{"*opus-4", ""},
+	[2]string{"*sonnet-4", ""},
+
+	[2]string{"*opus*4-*", ""},
+	[2]string{"*4-*opus*", ""},
+	[2]string{"*opus*", "claude-3-opus"},
+
+	[2]string{"*sonnet*4-*", ""},
+	[2]string{"*4-*sonnet*", ""},
+	[2]string{"*sonnet*3.5*", "claude-3-5-sonnet"},
+	[2]string{"*sonnet*3-5*", "claude-3-5-sonnet"},
+	[2]string{"*3.5*sonnet*", "claude-3-5-sonnet"},
+	[2]string{"*3-5*sonnet*", "claude-3-5-sonnet"},
+	[2]string{"*sonnet*", "claude-3-sonnet"},
+
+	[2]string{"*haiku*3.5*", "claude-3-5-haiku"},
+	[2]string{"*haiku*3-5*", "claude-3-5-haiku"},
+	[2]string{"*3.5*haiku*", "claude-3-5-haiku"},
+	[2]string{"*3-5*haiku*", "claude-3-5-haiku"},
+	[2]string{"*haiku*", "claude-3-haiku"},
+)
+
+var (
+	userAliasesMu sync.RWMutex
+	userAliases   []ModelAlias
+)
+
+// SetModelAliases replaces the user-defined aliases, which are consulted before the built-in
+// table. They match both the identifier as logged and its form with cloud provider prefixes
+// and suffixes removed.
+func SetModelAliases(aliases []ModelAlias) {
+	userAliasesMu.Lock()
+	defer userAliasesMu.Unlock()
+	userAliases = append([]ModelAlias(nil), aliases...)
+}
+
+// ModelAliases returns the user-defined aliases
+func ModelAliases() []ModelAlias {
+	userAliasesMu.RLock()
+	defer userAliasesMu.RUnlock()
+	return append([]ModelAlias(nil), userAliases...)
+}
+
+var (
+	// Bedrock cross-region inference profiles prefix model IDs with a geography
+	bedrockRegionPrefix = regexp.MustCompile(`^(us|eu|apac|us-gov|global)\.`)
+	// Bedrock model IDs end in a version such as -v2:0 or :0
+	bedrockVersionSuffix = regexp.MustCompile(`(-v\d+)?:\d+$`)
+)
+
+// stripProviderWrapping reduces Bedrock and Vertex AI identifiers to Anthropic model names:
+//
+//	arn:aws:bedrock:us-east-1::foundation-model/anthropic.claude-3-5-sonnet-20241022-v2:0
+//	us.anthropic.claude-sonnet-4-20250514-v1:0
+//	claude-sonnet-4@20250514
+//
+// Other identifiers are returned lowercased.
+func stripProviderWrapping(model string) string {
+	name := strings.ToLower(model)
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:] // ARNs and provider/model names
+	}
+	name = bedrockRegionPrefix.ReplaceAllString(name, "")
+	if strings.HasPrefix(name, "anthropic.") {
+		name = bedrockVersionSuffix.ReplaceAllString(strings.TrimPrefix(name, "anthropic."), "")
+	}
+	return strings.Replace(name, "@", "-", 1) // Vertex AI separates the date with @
+}
+
+// ClaudeModelSource returns the provider a Claude model identifier as logged was served by:
+// Bedrock for Bedrock model IDs and ARNs, Vertex AI for IDs versioned with @, and Anthropic
+// otherwise
+func ClaudeModelSource(model string) string {
+	name := strings.ToLower(model)
+	if strings.HasPrefix(name, "arn:aws:bedrock") {
+		return SourceBedrock
+	}
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	if strings.HasPrefix(bedrockRegionPrefix.ReplaceAllString(name, ""), "anthropic.") {
+		return SourceBedrock
+	}
+	if strings.Contains(name, "@") {
+		return SourceVertex
+	}
+	return SourceClaude
+}
+
+// resolveModelAlias returns the target of the first alias matching any of the names, and
+// the name it matched
+func resolveModelAlias(aliases []ModelAlias, names ...string) (string, string, bool) {
+	for _, alias := range aliases {
+		for _, name := range names {
+			if alias.Matches(name) {
+				return alias.Model, name, true
+			}
+		}
+	}
+	return "", "", false
+}
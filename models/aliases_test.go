@@ -0,0 +1,89 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeModelName(t *testing.T) {
+	tests := []struct {
+		model string
+		want  string
+	}{
+		{"", ""},
+		{"claude-sonnet-4-20250514", "claude-sonnet-4-20250514"},
+		{"Claude-Opus-4-1-20250805", "claude-opus-4-1-20250805"},
+		{"claude-sonnet-4-5", "claude-sonnet-4-5"},
+		{"claude-sonnet-4", "claude-sonnet-4"},
+		{"claude-3-5-sonnet-20241022", "claude-3-5-sonnet"},
+		{"claude-3-opus-20240229", "claude-3-opus"},
+		{"claude-3-haiku-20240307", "claude-3-haiku"},
+		{"claude-3-5-haiku-20241022", "claude-3-5-haiku"},
+		{"gpt-4o", "gpt-4o"},
+		{"<synthetic>", "<synthetic>"},
+
+		// Bedrock
+		{"anthropic.claude-3-5-sonnet-20241022-v2:0", "claude-3-5-sonnet"},
+		{"us.anthropic.claude-sonnet-4-20250514-v1:0", "claude-sonnet-4-20250514"},
+		{"arn:aws:bedrock:us-east-1::foundation-model/anthropic.claude-opus-4-20250514-v1:0", "claude-opus-4-20250514"},
+		{"arn:aws:bedrock:eu-west-1:123456789012:inference-profile/eu.anthropic.claude-3-5-haiku-20241022-v1:0", "claude-3-5-haiku"},
+
+		// Vertex AI
+		{"claude-sonnet-4@20250514", "claude-sonnet-4-20250514"},
+		{"claude-3-5-sonnet-v2@20241022", "claude-3-5-sonnet"},
+		{"vertex_ai/claude-opus-4@20250514", "claude-opus-4-20250514"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.model, func(t *testing.T) {
+			assert.Equal(t, tt.want, NormalizeModelName(tt.model))
+		})
+	}
+}
+
+func TestNormalizeModelName_UserAliases(t *testing.T) {
+	profile, err := NewModelAlias("arn:aws:bedrock:*:application-inference-profile/team-a", "claude-sonnet-4-20250514")
+	require.NoError(t, err)
+	internal, err := NewModelAlias("acme-coder-*", "claude-opus-4-20250514")
+	require.NoError(t, err)
+	override, err := NewModelAlias("claude-3-5-sonnet-20241022", "claude-3-5-sonnet-20241022")
+	require.NoError(t, err)
+
+	SetModelAliases([]ModelAlias{profile, internal, override})
+	defer SetModelAliases(nil)
+
+	assert.Equal(t, "claude-sonnet-4-20250514",
+		NormalizeModelName("arn:aws:bedrock:us-east-1:123456789012:application-inference-profile/team-a"))
+	assert.Equal(t, "claude-opus-4-20250514", NormalizeModelName("ACME-Coder-2"))
+	assert.Equal(t, "claude-3-5-sonnet-20241022", NormalizeModelName("claude-3-5-sonnet-20241022"), "user aliases win over the built-in table")
+	assert.Equal(t, "claude-3-5-sonnet-20241022", NormalizeModelName("anthropic.claude-3-5-sonnet-20241022-v2:0"), "user aliases match unwrapped names")
+	assert.Equal(t, "claude-3-haiku", NormalizeModelName("claude-3-haiku-20240307"))
+	assert.Len(t, ModelAliases(), 3)
+}
+
+func TestNewModelAlias(t *testing.T) {
+	_, err := NewModelAlias(" ", "claude-sonnet-4-20250514")
+	assert.Error(t, err)
+
+	alias, err := NewModelAlias("claude-(beta).*", "x")
+	require.NoError(t, err)
+	assert.True(t, alias.Matches("Claude-(beta).1"), "only * is special")
+	assert.False(t, alias.Matches("claude-beta-1"))
+}
+
+func TestClaudeModelSource(t *testing.T) {
+	tests := map[string]string{
+		"claude-sonnet-4-20250514":                      SourceClaude,
+		"anthropic.claude-3-5-sonnet-20241022-v2:0":     SourceBedrock,
+		"us.anthropic.claude-sonnet-4-20250514-v1:0":    SourceBedrock,
+		"arn:aws:bedrock:us-east-1:123456789012:foo":    SourceBedrock,
+		"claude-3-5-sonnet-v2@20241022":                 SourceVertex,
+		"publishers/anthropic/models/claude-opus-4@001": SourceVertex,
+		"<synthetic>": SourceClaude,
+	}
+	for model, want := range tests {
+		assert.Equal(t, want, ClaudeModelSource(model), model)
+	}
+}
@@ -0,0 +1,96 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// UntaggedAllocation is the tag reports group entries under when no allocation rule matched
+const UntaggedAllocation = "untagged"
+
+// AllocationRule tags usage entries for cost allocation, such as charging a team or cost
+// center. Path is a pattern matched against the whole path of the log an entry was read
+// from, where * matches any text including separators. Project is a regular expression
+// matched against the entry's project. A rule with both needs both to match.
+type AllocationRule struct {
+	Path    string `json:"path,omitempty"`
+	Project string `json:"project,omitempty"`
+	Tag     string `json:"tag"`
+
+	path    *regexp.Regexp
+	project *regexp.Regexp
+}
+
+// NewAllocationRule compiles a rule tagging entries from logs matching path and projects
+// matching project with tag. Either pattern may be empty, but not both.
+func NewAllocationRule(path, project, tag string) (AllocationRule, error) {
+	if strings.TrimSpace(tag) == "" {
+		return AllocationRule{}, fmt.Errorf("empty cost allocation tag")
+	}
+	if strings.TrimSpace(path) == "" && strings.TrimSpace(project) == "" {
+		return AllocationRule{}, fmt.Errorf("cost allocation rule for %q needs a path or project", tag)
+	}
+
+	rule := AllocationRule{Path: path, Project: project, Tag: tag}
+	if path != "" {
+		quoted := strings.ReplaceAll(regexp.QuoteMeta(path), `\*`, ".*")
+		re, err := regexp.Compile("^" + quoted + "$")
+		if err != nil {
+			return AllocationRule{}, fmt.Errorf("invalid cost allocation path %q: %w", path, err)
+		}
+		rule.path = re
+	}
+	if project != "" {
+		re, err := regexp.Compile(project)
+		if err != nil {
+			return AllocationRule{}, fmt.Errorf("invalid cost allocation project %q: %w", project, err)
+		}
+		rule.project = re
+	}
+	return rule, nil
+}
+
+// Matches reports whether the rule applies to entries of project read from the log at path
+func (r AllocationRule) Matches(path, project string) bool {
+	if r.path != nil && !r.path.MatchString(path) {
+		return false
+	}
+	if r.project != nil && !r.project.MatchString(project) {
+		return false
+	}
+	return r.path != nil || r.project != nil
+}
+
+var (
+	allocationRulesMu sync.RWMutex
+	allocationRules   []AllocationRule
+)
+
+// SetAllocationRules replaces the cost allocation rules entries are tagged with as they load
+func SetAllocationRules(rules []AllocationRule) {
+	allocationRulesMu.Lock()
+	defer allocationRulesMu.Unlock()
+	allocationRules = append([]AllocationRule(nil), rules...)
+}
+
+// AllocationRules returns the cost allocation rules
+func AllocationRules() []AllocationRule {
+	allocationRulesMu.RLock()
+	defer allocationRulesMu.RUnlock()
+	return append([]AllocationRule(nil), allocationRules...)
+}
+
+// AllocationTag returns the tag of the first rule matching entries of project read from the
+// log at path, or "" when none does
+func AllocationTag(path, project string) string {
+	allocationRulesMu.RLock()
+	defer allocationRulesMu.RUnlock()
+	for _, rule := range allocationRules {
+		if rule.Matches(path, project) {
+			return rule.Tag
+		}
+	}
+	return ""
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllocationTag(t *testing.T) {
+	mustRule := func(path, project, tag string) AllocationRule {
+		rule, err := NewAllocationRule(path, project, tag)
+		require.NoError(t, err)
+		return rule
+	}
+	SetAllocationRules([]AllocationRule{
+		mustRule("/home/ci/.claude/projects/*", "", "ci"),
+		mustRule("", "^(api|web)$", "platform"),
+		mustRule("*/-Users-me-work-*", "billing", "payments"),
+		mustRule("*/-Users-me-work-*", "", "work"),
+	})
+	t.Cleanup(func() { SetAllocationRules(nil) })
+
+	tests := []struct {
+		path, project, want string
+	}{
+		{"/home/ci/.claude/projects/-build-api/s1.jsonl", "api", "ci"}, // First match wins
+		{"/Users/me/.claude/projects/-Users-me-oss-api/s1.jsonl", "api", "platform"},
+		{"/Users/me/.claude/projects/-Users-me-oss-apis/s1.jsonl", "apis", ""},
+		{"/Users/me/.claude/projects/-Users-me-work-billing/s1.jsonl", "billing", "payments"},
+		{"/Users/me/.claude/projects/-Users-me-work-search/s1.jsonl", "search", "work"},
+		{"/Users/me/.claude/projects/-Users-me-home-billing/s1.jsonl", "billing", ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, AllocationTag(tt.path, tt.project), tt.path)
+	}
+}
+
+func TestNewAllocationRule_Invalid(t *testing.T) {
+	_, err := NewAllocationRule("*", "", "")
+	assert.Error(t, err)
+	_, err = NewAllocationRule("", "", "team")
+	assert.Error(t, err)
+	_, err = NewAllocationRule("", "api(", "team")
+	assert.Error(t, err)
+}
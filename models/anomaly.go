@@ -0,0 +1,122 @@
+package models
+
+import "fmt"
+
+// Anomaly actions
+const (
+	AnomalyActionFlag  = "flag"  // Mark implausible entries as suspect and exclude them from metrics
+	AnomalyActionClamp = "clamp" // Clamp implausible values into bounds and keep the entry
+	AnomalyActionOff   = "off"   // Disable validation
+)
+
+// EntryBounds defines the plausible range of values for a single usage entry.
+// Negative values are always implausible; a zero maximum disables that upper bound.
+type EntryBounds struct {
+	MaxInputTokens         int
+	MaxOutputTokens        int
+	MaxCacheCreationTokens int
+	MaxCacheReadTokens     int
+	MaxThinkingTokens      int
+	MaxCostUSD             float64
+}
+
+// EntryValidator flags or clamps usage entries whose values fall outside EntryBounds
+type EntryValidator struct {
+	Bounds         EntryBounds
+	Clamp          bool // Clamp values instead of marking the entry suspect
+	IncludeSuspect bool // Keep suspect entries in metrics
+}
+
+// NewEntryValidator creates a validator for the given bounds and action (flag, clamp or off).
+// It returns nil when validation is off; a nil validator accepts every entry.
+func NewEntryValidator(bounds EntryBounds, action string, includeSuspect bool) *EntryValidator {
+	if action == AnomalyActionOff {
+		return nil
+	}
+	return &EntryValidator{
+		Bounds:         bounds,
+		Clamp:          action == AnomalyActionClamp,
+		IncludeSuspect: includeSuspect,
+	}
+}
+
+// Key identifies the validation settings, so cached summaries built with different settings can be detected
+func (v *EntryValidator) Key() string {
+	if v == nil {
+		return ""
+	}
+	action := AnomalyActionFlag
+	if v.Clamp {
+		action = AnomalyActionClamp
+	}
+	b := v.Bounds
+	return fmt.Sprintf("%s:%d:%d:%d:%d:%d:%g", action, b.MaxInputTokens, b.MaxOutputTokens,
+		b.MaxCacheCreationTokens, b.MaxCacheReadTokens, b.MaxThinkingTokens, b.MaxCostUSD)
+}
+
+// CheckTokens validates the token counts of an entry, recording a flag for each implausible value.
+// In clamp mode the counts are corrected and the total recalculated; otherwise the entry is marked suspect.
+// Run it before costs are calculated so clamped counts are priced.
+func (v *EntryValidator) CheckTokens(entry *UsageEntry) {
+	fields := []struct {
+		name  string
+		value *int
+		max   int
+	}{
+		{"input_tokens", &entry.InputTokens, v.Bounds.MaxInputTokens},
+		{"output_tokens", &entry.OutputTokens, v.Bounds.MaxOutputTokens},
+		{"cache_creation_tokens", &entry.CacheCreationTokens, v.Bounds.MaxCacheCreationTokens},
+		{"cache_read_tokens", &entry.CacheReadTokens, v.Bounds.MaxCacheReadTokens},
+		{"thinking_tokens", &entry.ThinkingTokens, v.Bounds.MaxThinkingTokens},
+	}
+
+	changed := false
+	for _, field := range fields {
+		switch {
+		case *field.value < 0:
+			v.flag(entry, fmt.Sprintf("%s: negative value %d", field.name, *field.value))
+			if v.Clamp {
+				*field.value = 0
+				changed = true
+			}
+		case field.max > 0 && *field.value > field.max:
+			v.flag(entry, fmt.Sprintf("%s: %d exceeds bound %d", field.name, *field.value, field.max))
+			if v.Clamp {
+				*field.value = field.max
+				changed = true
+			}
+		}
+	}
+
+	if changed {
+		entry.TotalTokens = entry.CalculateTotalTokens()
+	}
+}
+
+// CheckCost validates the cost of an entry the same way CheckTokens validates token counts
+func (v *EntryValidator) CheckCost(entry *UsageEntry) {
+	switch {
+	case entry.CostUSD < 0:
+		v.flag(entry, fmt.Sprintf("cost_usd: negative value %.4f", entry.CostUSD))
+		if v.Clamp {
+			entry.CostUSD = 0
+		}
+	case v.Bounds.MaxCostUSD > 0 && entry.CostUSD > v.Bounds.MaxCostUSD:
+		v.flag(entry, fmt.Sprintf("cost_usd: %.4f exceeds bound %.2f", entry.CostUSD, v.Bounds.MaxCostUSD))
+		if v.Clamp {
+			entry.CostUSD = v.Bounds.MaxCostUSD
+		}
+	}
+}
+
+// Excludes reports whether the entry should be left out of metrics
+func (v *EntryValidator) Excludes(entry UsageEntry) bool {
+	return v != nil && entry.Suspect && !v.IncludeSuspect
+}
+
+func (v *EntryValidator) flag(entry *UsageEntry, reason string) {
+	entry.Anomalies = append(entry.Anomalies, reason)
+	if !v.Clamp {
+		entry.Suspect = true
+	}
+}
@@ -0,0 +1,89 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testEntryBounds() EntryBounds {
+	return EntryBounds{
+		MaxInputTokens:         1000,
+		MaxOutputTokens:        1000,
+		MaxCacheCreationTokens: 1000,
+		MaxCacheReadTokens:     1000,
+		MaxThinkingTokens:      1000,
+		MaxCostUSD:             10,
+	}
+}
+
+func TestEntryValidator_Flag(t *testing.T) {
+	validator := NewEntryValidator(testEntryBounds(), AnomalyActionFlag, false)
+
+	entry := UsageEntry{
+		Timestamp:    time.Now(),
+		Model:        "claude-sonnet-4-20250514",
+		InputTokens:  -5,
+		OutputTokens: 1_000_000_000,
+	}
+	entry.TotalTokens = entry.CalculateTotalTokens()
+	validator.CheckTokens(&entry)
+
+	assert.True(t, entry.Suspect)
+	assert.Len(t, entry.Anomalies, 2)
+	assert.Equal(t, -5, entry.InputTokens, "flag mode keeps original values")
+	assert.True(t, validator.Excludes(entry))
+
+	validator.IncludeSuspect = true
+	assert.False(t, validator.Excludes(entry))
+}
+
+func TestEntryValidator_Clamp(t *testing.T) {
+	validator := NewEntryValidator(testEntryBounds(), AnomalyActionClamp, false)
+
+	entry := UsageEntry{
+		InputTokens:     -5,
+		OutputTokens:    50_000,
+		CacheReadTokens: 200,
+		CostUSD:         500,
+	}
+	validator.CheckTokens(&entry)
+	validator.CheckCost(&entry)
+
+	assert.False(t, entry.Suspect)
+	assert.Len(t, entry.Anomalies, 3)
+	assert.Equal(t, 0, entry.InputTokens)
+	assert.Equal(t, 1000, entry.OutputTokens)
+	assert.Equal(t, 1200, entry.TotalTokens)
+	assert.Equal(t, 10.0, entry.CostUSD)
+	assert.False(t, validator.Excludes(entry))
+}
+
+func TestEntryValidator_PlausibleEntry(t *testing.T) {
+	validator := NewEntryValidator(testEntryBounds(), AnomalyActionFlag, false)
+
+	entry := UsageEntry{InputTokens: 100, OutputTokens: 50, CostUSD: 0.01}
+	validator.CheckTokens(&entry)
+	validator.CheckCost(&entry)
+
+	assert.False(t, entry.Suspect)
+	assert.Empty(t, entry.Anomalies)
+}
+
+func TestEntryValidator_Off(t *testing.T) {
+	validator := NewEntryValidator(testEntryBounds(), AnomalyActionOff, false)
+
+	assert.Nil(t, validator)
+	assert.Empty(t, validator.Key())
+	assert.False(t, validator.Excludes(UsageEntry{Suspect: true}))
+}
+
+func TestEntryValidator_Key(t *testing.T) {
+	flag := NewEntryValidator(testEntryBounds(), AnomalyActionFlag, false)
+	clamp := NewEntryValidator(testEntryBounds(), AnomalyActionClamp, false)
+	included := NewEntryValidator(testEntryBounds(), AnomalyActionFlag, true)
+
+	assert.NotEqual(t, flag.Key(), clamp.Key())
+	assert.Equal(t, flag.Key(), included.Key(), "including suspect entries does not change how entries are validated")
+}
@@ -15,11 +15,22 @@ const (
 	ModelHaiku  = "claude-3-5-haiku-20241022"
 )
 
+// Usage sources: the assistant that logged an entry, or for Claude Code the provider that
+// served it
+const (
+	SourceClaude  = "claude"
+	SourceBedrock = "bedrock"
+	SourceVertex  = "vertex"
+	SourceCodex   = "codex"
+	SourceGemini  = "gemini"
+)
+
 // Plan identifiers
 const (
-	PlanPro   = "pro"
-	PlanMax5  = "max5"
-	PlanMax20 = "max20"
+	PlanPro    = "pro"
+	PlanMax5   = "max5"
+	PlanMax20  = "max20"
+	PlanCustom = "custom" // Limits derived from past sessions
 )
 
 // View refresh rates
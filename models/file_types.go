@@ -87,6 +87,7 @@ type Usage struct {
 	CacheReadInputTokens     int           `json:"cache_read_input_tokens"`
 	InputTokens              int           `json:"input_tokens"`
 	OutputTokens             int           `json:"output_tokens"`
+	ThinkingTokens           int           `json:"thinking_tokens,omitempty"`
 	ServerToolUse            ServerToolUse `json:"server_tool_use,omitempty"`
 	ServiceTier              string        `json:"service_tier"`
 }
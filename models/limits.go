@@ -0,0 +1,40 @@
+package models
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// minTokenCeiling is the smallest count accepted as a session token limit, so per-request
+// limits such as "max_tokens: 8192" aren't mistaken for one
+const minTokenCeiling = 10000
+
+// limitCeilingPatterns match the token counts limit messages state, as in "1,000,000 tokens",
+// "2M tokens" or "token limit of 88k"
+var limitCeilingPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)(\d[\d,]*(?:\.\d+)?)\s*([km])?\s+tokens?\b`),
+	regexp.MustCompile(`(?i)\btokens?\s+limit\s*(?:of|is|:)?\s*(\d[\d,]*(?:\.\d+)?)\s*([km])?\b`),
+}
+
+// TokenCeiling returns the session token limit the message states, as in "You've used 950,000
+// of your 1,000,000 tokens". Of several counts the largest is the ceiling rather than the usage.
+func (m LimitMessage) TokenCeiling() (int, bool) {
+	ceiling := 0
+	for _, pattern := range limitCeilingPatterns {
+		for _, match := range pattern.FindAllStringSubmatch(m.Message, -1) {
+			value, err := strconv.ParseFloat(strings.ReplaceAll(match[1], ",", ""), 64)
+			if err != nil {
+				continue
+			}
+			switch strings.ToLower(match[2]) {
+			case "k":
+				value *= 1e3
+			case "m":
+				value *= 1e6
+			}
+			ceiling = max(ceiling, int(value))
+		}
+	}
+	return ceiling, ceiling >= minTokenCeiling
+}
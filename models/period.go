@@ -0,0 +1,97 @@
+package models
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/penwyp/claudecat/timeutil"
+)
+
+// Period names accepted by PeriodFor
+const (
+	PeriodHour  = "hour"
+	PeriodDay   = "day"
+	PeriodWeek  = "week"
+	PeriodMonth = "month"
+)
+
+// billingDay is the day of the month billing cycles start on; 0 or 1 means calendar months
+var billingDay atomic.Int32
+
+// SetBillingDay makes month periods follow billing cycles renewing on day of the month, as
+// configured with subscription.billing_day. 0 restores calendar months.
+func SetBillingDay(day int) {
+	billingDay.Store(int32(day))
+}
+
+// BillingDay returns the day of the month billing cycles start on, 0 for calendar months
+func BillingDay() int {
+	return int(billingDay.Load())
+}
+
+// PeriodBounds describes a calendar period in a specific location.
+// Start and End are absolute instants, so Hours reflects the real length of the period:
+// a day containing a DST transition is 23 or 25 hours long, never a fixed 24.
+type PeriodBounds struct {
+	Key      string    `json:"key"`
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"` // Exclusive
+	Hours    float64   `json:"hours"`
+	Timezone string    `json:"timezone"`
+}
+
+// Contains reports whether t falls inside the period
+func (p PeriodBounds) Contains(t time.Time) bool {
+	return !t.Before(p.Start) && t.Before(p.End)
+}
+
+// PeriodFor returns the hour, day, week (ISO, starting Monday) or month containing t in loc.
+// Months are billing cycles when a billing day is set, keyed by the month they start in.
+// Calendar boundaries come from timeutil and stay aligned to local midnight across DST
+// transitions. Hour periods are anchored
+// to absolute instants; when a wall-clock hour repeats (DST fall-back) the zone abbreviation
+// is appended to the key so the two hours are not merged.
+func PeriodFor(t time.Time, period string, loc *time.Location) (PeriodBounds, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+	local := t.In(loc)
+
+	var bounds PeriodBounds
+	switch period {
+	case PeriodHour:
+		start := local.Add(-time.Duration(local.Minute())*time.Minute -
+			time.Duration(local.Second())*time.Second -
+			time.Duration(local.Nanosecond()))
+		bounds.Start = start
+		bounds.End = start.Add(time.Hour)
+		bounds.Key = start.Format("2006-01-02 15:00")
+		if isRepeatedWallHour(start) {
+			bounds.Key += start.Format(" MST")
+		}
+	case PeriodDay:
+		bounds.Start, bounds.End = timeutil.DayBounds(local, loc)
+		bounds.Key = bounds.Start.Format(timeutil.DateLayout)
+	case PeriodWeek:
+		bounds.Start, bounds.End = timeutil.WeekBounds(local, loc)
+		year, week := local.ISOWeek()
+		bounds.Key = fmt.Sprintf("%d-W%02d", year, week)
+	case PeriodMonth:
+		bounds.Start, bounds.End = timeutil.BillingCycleBounds(local, loc, BillingDay())
+		bounds.Key = bounds.Start.Format("2006-01")
+	default:
+		return bounds, fmt.Errorf("unknown period: %s", period)
+	}
+
+	bounds.Hours = bounds.End.Sub(bounds.Start).Hours()
+	bounds.Timezone = loc.String()
+	return bounds, nil
+}
+
+// isRepeatedWallHour reports whether the wall-clock hour starting at start occurs twice
+func isRepeatedWallHour(start time.Time) bool {
+	key := start.Format("2006-01-02 15")
+	return start.Add(-time.Hour).Format("2006-01-02 15") == key ||
+		start.Add(time.Hour).Format("2006-01-02 15") == key
+}
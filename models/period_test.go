@@ -0,0 +1,104 @@
+package models
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func loadNewYork(t *testing.T) *time.Location {
+	loc, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	return loc
+}
+
+func TestPeriodFor_DayAcrossDST(t *testing.T) {
+	loc := loadNewYork(t)
+
+	tests := []struct {
+		name  string
+		at    time.Time
+		key   string
+		hours float64
+	}{
+		{"spring forward", time.Date(2025, 3, 9, 15, 0, 0, 0, time.UTC), "2025-03-09", 23},
+		{"fall back", time.Date(2025, 11, 2, 15, 0, 0, 0, time.UTC), "2025-11-02", 25},
+		{"regular day", time.Date(2025, 6, 15, 15, 0, 0, 0, time.UTC), "2025-06-15", 24},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bounds, err := PeriodFor(tt.at, PeriodDay, loc)
+			require.NoError(t, err)
+			assert.Equal(t, tt.key, bounds.Key)
+			assert.Equal(t, tt.hours, bounds.Hours)
+			assert.Equal(t, 0, bounds.Start.In(loc).Hour())
+			assert.Equal(t, 0, bounds.End.In(loc).Hour())
+			assert.True(t, bounds.Contains(tt.at))
+			assert.Equal(t, "America/New_York", bounds.Timezone)
+		})
+	}
+}
+
+func TestPeriodFor_RepeatedHour(t *testing.T) {
+	loc := loadNewYork(t)
+
+	// 01:30 EDT and 01:30 EST on 2025-11-02 share a wall clock but are an hour apart
+	first := time.Date(2025, 11, 2, 5, 30, 0, 0, time.UTC)
+	second := time.Date(2025, 11, 2, 6, 30, 0, 0, time.UTC)
+
+	a, err := PeriodFor(first, PeriodHour, loc)
+	require.NoError(t, err)
+	b, err := PeriodFor(second, PeriodHour, loc)
+	require.NoError(t, err)
+
+	assert.Equal(t, "2025-11-02 01:00 EDT", a.Key)
+	assert.Equal(t, "2025-11-02 01:00 EST", b.Key)
+	assert.Equal(t, 1.0, a.Hours)
+	assert.Equal(t, 1.0, b.Hours)
+	assert.True(t, a.End.Equal(b.Start))
+
+	regular, err := PeriodFor(time.Date(2025, 11, 2, 8, 30, 0, 0, time.UTC), PeriodHour, loc)
+	require.NoError(t, err)
+	assert.Equal(t, "2025-11-02 03:00", regular.Key)
+}
+
+func TestPeriodFor_WeekAndMonthAcrossDST(t *testing.T) {
+	loc := loadNewYork(t)
+	at := time.Date(2025, 3, 9, 12, 0, 0, 0, time.UTC)
+
+	week, err := PeriodFor(at, PeriodWeek, loc)
+	require.NoError(t, err)
+	assert.Equal(t, "2025-W10", week.Key)
+	assert.Equal(t, time.Monday, week.Start.Weekday())
+	assert.Equal(t, 167.0, week.Hours)
+
+	month, err := PeriodFor(at, PeriodMonth, loc)
+	require.NoError(t, err)
+	assert.Equal(t, "2025-03", month.Key)
+	assert.Equal(t, float64(31*24-1), month.Hours)
+}
+
+func TestPeriodFor_BillingCycle(t *testing.T) {
+	SetBillingDay(17)
+	defer SetBillingDay(0)
+
+	bounds, err := PeriodFor(time.Date(2025, 2, 3, 12, 0, 0, 0, time.UTC), PeriodMonth, time.UTC)
+	require.NoError(t, err)
+	assert.Equal(t, "2025-01", bounds.Key, "cycles are keyed by the month they start in")
+	assert.Equal(t, time.Date(2025, 1, 17, 0, 0, 0, 0, time.UTC), bounds.Start)
+	assert.Equal(t, time.Date(2025, 2, 17, 0, 0, 0, 0, time.UTC), bounds.End)
+
+	SetBillingDay(0)
+	bounds, err = PeriodFor(time.Date(2025, 2, 3, 12, 0, 0, 0, time.UTC), PeriodMonth, time.UTC)
+	require.NoError(t, err)
+	assert.Equal(t, "2025-02", bounds.Key)
+}
+
+func TestPeriodFor_UnknownPeriod(t *testing.T) {
+	_, err := PeriodFor(time.Now(), "fortnight", time.UTC)
+	assert.Error(t, err)
+}
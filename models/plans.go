@@ -0,0 +1,131 @@
+package models
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// PlanLimits are the per-session limits of a subscription plan
+type PlanLimits struct {
+	Name         string    `json:"name"`                  // Display name
+	TokenLimit   int       `json:"token_limit"`           // Tokens per session window
+	CostLimit    float64   `json:"cost_limit"`            // Cost in USD per session window
+	MessageLimit int       `json:"message_limit"`         // Messages per session window
+	CostAlerts   []float64 `json:"cost_alerts,omitempty"` // Session costs in USD that trigger alerts
+}
+
+// defaultPlanLimits are the built-in plans. The custom plan derives its limits from past
+// sessions; its entry is the fallback used while there is no history.
+var defaultPlanLimits = map[string]PlanLimits{
+	PlanPro: {
+		Name:         "Pro",
+		TokenLimit:   1000000,
+		CostLimit:    18.0,
+		MessageLimit: 1500,
+	},
+	PlanMax5: {
+		Name:         "Max 5x",
+		TokenLimit:   88000,
+		CostLimit:    35.0,
+		MessageLimit: 1000,
+	},
+	PlanMax20: {
+		Name:         "Max 20x",
+		TokenLimit:   8000000,
+		CostLimit:    140.0,
+		MessageLimit: 12000,
+	},
+	PlanCustom: {
+		Name:         "Custom",
+		TokenLimit:   1000000,
+		CostLimit:    18.0,
+		MessageLimit: 1500,
+	},
+}
+
+// PlanCatalog holds the limits of the known subscription plans, keyed by lower-case plan name
+type PlanCatalog struct {
+	plans map[string]PlanLimits
+}
+
+// DefaultPlanCatalog returns a catalog of the built-in plans
+func DefaultPlanCatalog() *PlanCatalog {
+	return NewPlanCatalog(nil)
+}
+
+// NewPlanCatalog returns the built-in plans with overrides applied. An override for a
+// built-in plan replaces only its non-zero limits; other names add new plans.
+func NewPlanCatalog(overrides map[string]PlanLimits) *PlanCatalog {
+	plans := make(map[string]PlanLimits, len(defaultPlanLimits)+len(overrides))
+	for name, limits := range defaultPlanLimits {
+		plans[name] = limits
+	}
+
+	for name, override := range overrides {
+		name = strings.ToLower(name)
+		limits, exists := plans[name]
+		if !exists {
+			limits = PlanLimits{Name: name}
+		}
+		if override.Name != "" {
+			limits.Name = override.Name
+		}
+		if override.TokenLimit > 0 {
+			limits.TokenLimit = override.TokenLimit
+		}
+		if override.CostLimit > 0 {
+			limits.CostLimit = override.CostLimit
+		}
+		if override.MessageLimit > 0 {
+			limits.MessageLimit = override.MessageLimit
+		}
+		if len(override.CostAlerts) > 0 {
+			limits.CostAlerts = override.CostAlerts
+		}
+		plans[name] = limits
+	}
+
+	return &PlanCatalog{plans: plans}
+}
+
+// Has reports whether the catalog knows the plan
+func (c *PlanCatalog) Has(plan string) bool {
+	_, exists := c.plans[strings.ToLower(plan)]
+	return exists
+}
+
+// Limits returns the limits of the plan, or those of the Pro plan for unknown plans
+func (c *PlanCatalog) Limits(plan string) PlanLimits {
+	if limits, exists := c.plans[strings.ToLower(plan)]; exists {
+		return limits
+	}
+	return c.plans[PlanPro]
+}
+
+// Names returns the names of all plans in the catalog, sorted
+func (c *PlanCatalog) Names() []string {
+	names := make([]string, 0, len(c.plans))
+	for name := range c.plans {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// PlanForTokenLimit returns the plan whose session token limit is within 1% of limit, the
+// closest one when several are. The custom plan never matches, as its limit is a fallback.
+func (c *PlanCatalog) PlanForTokenLimit(limit int) (string, bool) {
+	best, bestDiff := "", -1.0
+	for _, name := range c.Names() {
+		planLimit := c.plans[name].TokenLimit
+		if name == PlanCustom || planLimit <= 0 {
+			continue
+		}
+		diff := math.Abs(float64(planLimit-limit)) / float64(planLimit)
+		if diff <= 0.01 && (bestDiff < 0 || diff < bestDiff) {
+			best, bestDiff = name, diff
+		}
+	}
+	return best, best != ""
+}
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlanCatalog(t *testing.T) {
+	catalog := DefaultPlanCatalog()
+	assert.True(t, catalog.Has("MAX20"))
+	assert.Equal(t, 8000000, catalog.Limits(PlanMax20).TokenLimit)
+	assert.Equal(t, []string{PlanCustom, PlanMax20, PlanMax5, PlanPro}, catalog.Names())
+
+	// Unknown plans fall back to Pro
+	assert.False(t, catalog.Has("team"))
+	assert.Equal(t, catalog.Limits(PlanPro), catalog.Limits("team"))
+}
+
+func TestNewPlanCatalog_Overrides(t *testing.T) {
+	catalog := NewPlanCatalog(map[string]PlanLimits{
+		"Max5":       {TokenLimit: 220000, CostAlerts: []float64{10, 25}},
+		"enterprise": {TokenLimit: 5000000, CostLimit: 500},
+	})
+
+	// Only the non-zero limits of a built-in plan change
+	max5 := catalog.Limits(PlanMax5)
+	assert.Equal(t, 220000, max5.TokenLimit)
+	assert.Equal(t, 35.0, max5.CostLimit)
+	assert.Equal(t, "Max 5x", max5.Name)
+	assert.Equal(t, []float64{10, 25}, max5.CostAlerts)
+
+	assert.Equal(t, PlanLimits{Name: "enterprise", TokenLimit: 5000000, CostLimit: 500}, catalog.Limits("enterprise"))
+
+	// The defaults are not modified
+	assert.Equal(t, 88000, DefaultPlanCatalog().Limits(PlanMax5).TokenLimit)
+	assert.Empty(t, DefaultPlanCatalog().Limits(PlanMax5).CostAlerts)
+}
+
+func TestPlanCatalog_PlanForTokenLimit(t *testing.T) {
+	catalog := DefaultPlanCatalog()
+	plan, ok := catalog.PlanForTokenLimit(8000000)
+	assert.True(t, ok)
+	assert.Equal(t, PlanMax20, plan)
+
+	plan, ok = catalog.PlanForTokenLimit(995000)
+	assert.True(t, ok, "limits within 1% match")
+	assert.Equal(t, PlanPro, plan)
+
+	_, ok = catalog.PlanForTokenLimit(3000000)
+	assert.False(t, ok)
+}
+
+func TestLimitMessage_TokenCeiling(t *testing.T) {
+	tests := []struct {
+		message string
+		want    int
+		ok      bool
+	}{
+		{"You've used 950,000 of your 1,000,000 tokens for this session", 1000000, true},
+		{"Claude usage limit reached (8M tokens). Your limit will reset at 5pm", 8000000, true},
+		{"Token limit of 88k reached", 88000, true},
+		{"token limit: 2,500,000", 2500000, true},
+		{"Claude AI usage limit reached|1751000000", 0, false},
+		{"max_tokens: 8192 tokens exceeds the limit", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.message, func(t *testing.T) {
+			ceiling, ok := LimitMessage{Message: tt.message}.TokenCeiling()
+			assert.Equal(t, tt.ok, ok)
+			if tt.ok {
+				assert.Equal(t, tt.want, ceiling)
+			}
+		})
+	}
+}
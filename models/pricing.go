@@ -6,6 +6,15 @@ type ModelPricing struct {
 	Output        float64 // Per million tokens
 	CacheCreation float64 // Per million tokens
 	CacheRead     float64 // Per million tokens
+	Thinking      float64 // Per million tokens (0 = billed at the output rate)
+}
+
+// ThinkingRate returns the price for thinking tokens, falling back to the output price
+func (p ModelPricing) ThinkingRate() float64 {
+	if p.Thinking > 0 {
+		return p.Thinking
+	}
+	return p.Output
 }
 
 // Plan represents a subscription plan with token and cost limits
@@ -61,6 +70,9 @@ func GetPricing(model string) ModelPricing {
 	if pricing, ok := modelPricingMap[model]; ok {
 		return pricing
 	}
+	if pricing, ok := VendorPricing(model); ok {
+		return pricing
+	}
 	// Default to Sonnet pricing if model not found
 	return modelPricingMap[ModelSonnet]
 }
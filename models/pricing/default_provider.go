@@ -51,8 +51,13 @@ func (p *DefaultProvider) GetPricing(ctx context.Context, modelName string) (mod
 		return pricing, nil
 	}
 
-	// Fallback based on model type in name
-	modelLower := strings.ToLower(modelName)
+	// Models of other vendors, for the logs of other coding assistants
+	if pricing, ok := models.VendorPricing(normalized); ok {
+		return pricing, nil
+	}
+
+	// Fallback based on the model family in the normalized name, which resolves aliases
+	modelLower := strings.ToLower(normalized)
 	if strings.Contains(modelLower, "opus") {
 		return p.pricing[models.ModelOpus], nil
 	}
@@ -9,27 +9,27 @@ import (
 
 // CreatePricingProvider creates a pricing provider based on configuration
 func CreatePricingProvider(cfg *config.DataConfig, cacheDir string) (models.PricingProvider, error) {
-	// Create base provider based on source
-	var baseProvider models.PricingProvider
-
 	switch cfg.PricingSource {
 	case "default", "":
-		baseProvider = NewDefaultProvider()
-	case "litellm":
-		baseProvider = NewLiteLLMProvider()
-	default:
-		return nil, fmt.Errorf("unknown pricing source: %s", cfg.PricingSource)
-	}
+		baseProvider := NewDefaultProvider()
+		if !cfg.PricingOfflineMode {
+			return baseProvider, nil
+		}
 
-	// If offline mode or non-default provider, wrap with caching
-	if cfg.PricingOfflineMode || cfg.PricingSource != "default" {
+		// Offline mode prefers previously cached prices over the hardcoded ones
 		cacheManager, err := NewCacheManager(cacheDir)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create cache manager: %w", err)
 		}
-
-		return NewCachedProvider(baseProvider, cacheManager, cfg.PricingOfflineMode), nil
+		return NewCachedProvider(baseProvider, cacheManager, true), nil
+	case "litellm":
+		// LiteLLM manages its own disk cache and embedded snapshot fallback
+		cacheManager, err := NewCacheManager(cacheDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create cache manager: %w", err)
+		}
+		return NewSyncedLiteLLMProvider(cacheManager, cfg.PricingRefresh, cfg.PricingOfflineMode), nil
+	default:
+		return nil, fmt.Errorf("unknown pricing source: %s", cfg.PricingSource)
 	}
-
-	return baseProvider, nil
 }
@@ -2,6 +2,7 @@ package pricing
 
 import (
 	"context"
+	_ "embed"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -10,20 +11,46 @@ import (
 	"sync"
 	"time"
 
+	"github.com/penwyp/claudecat/logging"
 	"github.com/penwyp/claudecat/models"
 )
 
 const (
 	liteLLMPricingURL = "https://raw.githubusercontent.com/BerriAI/litellm/main/model_prices_and_context_window.json"
-	cacheExpiration   = 24 * time.Hour // Cache pricing data for 24 hours
+
+	// DefaultLiteLLMRefreshInterval is how often pricing is re-fetched when no interval is configured
+	DefaultLiteLLMRefreshInterval = 24 * time.Hour
+
+	// liteLLMRetryInterval throttles fetch attempts after a failure so an offline machine
+	// doesn't wait on the network for every price lookup
+	liteLLMRetryInterval = 5 * time.Minute
 )
 
-// LiteLLMProvider implements PricingProvider by fetching pricing from LiteLLM's repository
+// liteLLMSnapshot is a bundled copy of LiteLLM's Claude prices, used when neither the
+// network nor the disk cache can provide pricing
+//
+//go:embed litellm_snapshot.json
+var liteLLMSnapshot []byte
+
+// LiteLLMProvider implements PricingProvider by fetching pricing from LiteLLM's repository.
+//
+// Prices are re-fetched once the refresh interval has passed and persisted to the disk
+// cache. When a fetch fails, the provider keeps its current prices, or on first use falls
+// back to the disk cache and then to the embedded snapshot, so lookups never fail just
+// because the machine is offline.
 type LiteLLMProvider struct {
 	mu            sync.RWMutex
 	pricing       map[string]models.ModelPricing
+	source        string // remote, cache or snapshot
 	lastFetchTime time.Time
-	httpClient    *http.Client
+	lastAttempt   time.Time
+
+	fetchMu         sync.Mutex
+	httpClient      *http.Client
+	url             string
+	refreshInterval time.Duration
+	cacheManager    *CacheManager
+	offline         bool
 }
 
 // liteLLMModel represents the structure of a model in LiteLLM's pricing data
@@ -32,15 +59,30 @@ type liteLLMModel struct {
 	OutputCostPerToken          *float64 `json:"output_cost_per_token"`
 	CacheCreationInputTokenCost *float64 `json:"cache_creation_input_token_cost"`
 	CacheReadInputTokenCost     *float64 `json:"cache_read_input_token_cost"`
+	OutputCostPerReasoningToken *float64 `json:"output_cost_per_reasoning_token"`
 }
 
-// NewLiteLLMProvider creates a new LiteLLM pricing provider
+// NewLiteLLMProvider creates a new LiteLLM pricing provider without a disk cache
 func NewLiteLLMProvider() *LiteLLMProvider {
+	return NewSyncedLiteLLMProvider(nil, DefaultLiteLLMRefreshInterval, false)
+}
+
+// NewSyncedLiteLLMProvider creates a LiteLLM pricing provider that persists fetched prices
+// through cacheManager (may be nil) and re-fetches them every refreshInterval. In offline
+// mode it never touches the network and serves the disk cache or the embedded snapshot.
+func NewSyncedLiteLLMProvider(cacheManager *CacheManager, refreshInterval time.Duration, offline bool) *LiteLLMProvider {
+	if refreshInterval <= 0 {
+		refreshInterval = DefaultLiteLLMRefreshInterval
+	}
 	return &LiteLLMProvider{
 		pricing: make(map[string]models.ModelPricing),
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		url:             liteLLMPricingURL,
+		refreshInterval: refreshInterval,
+		cacheManager:    cacheManager,
+		offline:         offline,
 	}
 }
 
@@ -59,9 +101,11 @@ func (p *LiteLLMProvider) GetPricing(ctx context.Context, modelName string) (mod
 		return pricing, nil
 	}
 
-	// Try with provider prefix variations
+	// Try the normalized name, which resolves aliases, and provider prefix variations
+	normalized := models.NormalizeModelName(modelName)
 	variations := []string{
 		modelName,
+		normalized,
 		fmt.Sprintf("anthropic/%s", modelName),
 		fmt.Sprintf("claude-3-5-%s", modelName),
 		fmt.Sprintf("claude-3-%s", modelName),
@@ -74,6 +118,11 @@ func (p *LiteLLMProvider) GetPricing(ctx context.Context, modelName string) (mod
 		}
 	}
 
+	// Other vendors' models missing from the data, before partial matches that would pick the wrong one
+	if pricing, ok := models.VendorPricing(normalized); ok {
+		return pricing, nil
+	}
+
 	// Try partial matches
 	modelLower := strings.ToLower(modelName)
 	for key, pricing := range p.pricing {
@@ -106,6 +155,8 @@ func (p *LiteLLMProvider) GetAllPricings(ctx context.Context) (map[string]models
 
 // RefreshPricing forces a refresh of pricing data
 func (p *LiteLLMProvider) RefreshPricing(ctx context.Context) error {
+	p.fetchMu.Lock()
+	defer p.fetchMu.Unlock()
 	return p.fetchPricing(ctx)
 }
 
@@ -114,21 +165,106 @@ func (p *LiteLLMProvider) GetProviderName() string {
 	return "litellm"
 }
 
-// ensurePricingLoaded checks if pricing data needs to be loaded or refreshed
+// Source reports where the current prices came from: remote, cache or snapshot.
+// It is empty before the first lookup.
+func (p *LiteLLMProvider) Source() string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.source
+}
+
+// ensurePricingLoaded loads pricing on first use and re-fetches it once the refresh interval
+// has passed. A failed fetch is logged and retried after liteLLMRetryInterval.
 func (p *LiteLLMProvider) ensurePricingLoaded(ctx context.Context) error {
+	if !p.needsFetch() {
+		return nil
+	}
+
+	p.fetchMu.Lock()
+	defer p.fetchMu.Unlock()
+
+	// Another goroutine may have loaded pricing while we waited
+	if !p.needsFetch() {
+		return nil
+	}
+
 	p.mu.RLock()
-	needsRefresh := time.Since(p.lastFetchTime) > cacheExpiration || len(p.pricing) == 0
+	loaded := len(p.pricing) > 0
 	p.mu.RUnlock()
+	if !loaded {
+		p.loadFallback(ctx)
+		if !p.needsFetch() {
+			return nil
+		}
+	}
 
-	if needsRefresh {
-		return p.fetchPricing(ctx)
+	if err := p.fetchPricing(ctx); err != nil {
+		p.mu.RLock()
+		source := p.source
+		p.mu.RUnlock()
+		if source == "" {
+			return fmt.Errorf("%w: %v", models.ErrPricingUnavailable, err)
+		}
+		logging.LogWarnf("Failed to refresh LiteLLM pricing, using %s prices: %v", source, err)
 	}
 	return nil
 }
 
-// fetchPricing fetches the latest pricing data from LiteLLM
+// needsFetch reports whether a fetch should be attempted now
+func (p *LiteLLMProvider) needsFetch() bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if len(p.pricing) == 0 {
+		return true
+	}
+	if p.offline || time.Since(p.lastFetchTime) < p.refreshInterval {
+		return false
+	}
+	return time.Since(p.lastAttempt) >= liteLLMRetryInterval
+}
+
+// loadFallback seeds pricing from the disk cache, or from the embedded snapshot when there
+// is no usable cache. A cache younger than the refresh interval avoids a fetch at startup.
+func (p *LiteLLMProvider) loadFallback(ctx context.Context) {
+	if p.cacheManager != nil {
+		cache, err := p.cacheManager.LoadPricing(ctx)
+		if err == nil && cache.Source == p.GetProviderName() && len(cache.Pricing) > 0 {
+			p.mu.Lock()
+			p.pricing = cache.Pricing
+			p.source = "cache"
+			p.lastFetchTime = cache.UpdatedAt
+			p.mu.Unlock()
+			logging.LogDebugf("Loaded %d LiteLLM prices from disk cache (updated %s)",
+				len(cache.Pricing), cache.UpdatedAt.Format(time.RFC3339))
+			return
+		}
+	}
+
+	snapshot, err := parseLiteLLMPricing(liteLLMSnapshot)
+	if err != nil {
+		logging.LogErrorf("Failed to parse embedded LiteLLM pricing snapshot: %v", err)
+		return
+	}
+	p.mu.Lock()
+	p.pricing = snapshot
+	p.source = "snapshot"
+	p.mu.Unlock()
+	logging.LogDebugf("Loaded %d LiteLLM prices from embedded snapshot", len(snapshot))
+}
+
+// fetchPricing fetches the latest pricing data from LiteLLM and persists it to the disk cache.
+// Callers must hold fetchMu.
 func (p *LiteLLMProvider) fetchPricing(ctx context.Context) error {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, liteLLMPricingURL, nil)
+	if p.offline {
+		return fmt.Errorf("pricing fetch disabled in offline mode")
+	}
+
+	p.mu.Lock()
+	p.lastAttempt = time.Now()
+	p.mu.Unlock()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url, nil)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
@@ -148,10 +284,35 @@ func (p *LiteLLMProvider) fetchPricing(ctx context.Context) error {
 		return fmt.Errorf("failed to read response body: %w", err)
 	}
 
-	// Parse the JSON data
+	newPricing, err := parseLiteLLMPricing(body)
+	if err != nil {
+		return err
+	}
+	if len(newPricing) == 0 {
+		return fmt.Errorf("pricing data contains no priced models")
+	}
+
+	// Update the cached pricing
+	p.mu.Lock()
+	p.pricing = newPricing
+	p.source = "remote"
+	p.lastFetchTime = time.Now()
+	p.mu.Unlock()
+
+	if p.cacheManager != nil {
+		if err := p.cacheManager.SavePricing(ctx, p.GetProviderName(), newPricing); err != nil {
+			logging.LogWarnf("Failed to save LiteLLM pricing to disk cache: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// parseLiteLLMPricing converts LiteLLM's pricing JSON into per-million-token prices
+func parseLiteLLMPricing(body []byte) (map[string]models.ModelPricing, error) {
 	var rawData map[string]json.RawMessage
 	if err := json.Unmarshal(body, &rawData); err != nil {
-		return fmt.Errorf("failed to parse pricing data: %w", err)
+		return nil, fmt.Errorf("failed to parse pricing data: %w", err)
 	}
 
 	// Convert to our pricing format
@@ -189,14 +350,13 @@ func (p *LiteLLMProvider) fetchPricing(ctx context.Context) error {
 			pricing.CacheRead = pricing.Input * 0.1
 		}
 
+		// Thinking tokens are billed at the output rate unless a reasoning price is given
+		if model.OutputCostPerReasoningToken != nil {
+			pricing.Thinking = *model.OutputCostPerReasoningToken * 1_000_000
+		}
+
 		newPricing[modelName] = pricing
 	}
 
-	// Update the cached pricing
-	p.mu.Lock()
-	p.pricing = newPricing
-	p.lastFetchTime = time.Now()
-	p.mu.Unlock()
-
-	return nil
+	return newPricing, nil
 }
@@ -0,0 +1,136 @@
+package pricing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testLiteLLMPricing = `{
+  "sample_spec": {"max_tokens": "set to max_output_tokens if provider specifies it"},
+  "claude-sonnet-4-20250514": {
+    "input_cost_per_token": 4e-06,
+    "output_cost_per_token": 2e-05,
+    "cache_creation_input_token_cost": 5e-06,
+    "cache_read_input_token_cost": 4e-07
+  }
+}`
+
+// newTestLiteLLMServer serves pricing JSON, or a 500 when fail is set, and counts requests
+func newTestLiteLLMServer(t *testing.T, fail *atomic.Bool) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if fail.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte(testLiteLLMPricing))
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func newTestLiteLLMProvider(t *testing.T, url string, offline bool) (*LiteLLMProvider, *CacheManager) {
+	t.Helper()
+	cacheManager, err := NewCacheManager(t.TempDir())
+	require.NoError(t, err)
+	provider := NewSyncedLiteLLMProvider(cacheManager, time.Hour, offline)
+	provider.url = url
+	return provider, cacheManager
+}
+
+func TestLiteLLMProvider_FetchesAndCachesToDisk(t *testing.T) {
+	var fail atomic.Bool
+	server, requests := newTestLiteLLMServer(t, &fail)
+	provider, cacheManager := newTestLiteLLMProvider(t, server.URL, false)
+	ctx := context.Background()
+
+	pricing, err := provider.GetPricing(ctx, "claude-sonnet-4-20250514")
+	require.NoError(t, err)
+	assert.InDelta(t, 4.0, pricing.Input, 1e-9)
+	assert.InDelta(t, 20.0, pricing.Output, 1e-9)
+	assert.InDelta(t, 0.4, pricing.CacheRead, 1e-9)
+	assert.Equal(t, "remote", provider.Source())
+
+	// Within the refresh interval prices are served from memory
+	_, err = provider.GetPricing(ctx, "claude-sonnet-4-20250514")
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), requests.Load())
+
+	cache, err := cacheManager.LoadPricing(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "litellm", cache.Source)
+	assert.Contains(t, cache.Pricing, "claude-sonnet-4-20250514")
+}
+
+func TestLiteLLMProvider_FreshDiskCacheSkipsFetch(t *testing.T) {
+	var fail atomic.Bool
+	server, requests := newTestLiteLLMServer(t, &fail)
+	provider, cacheManager := newTestLiteLLMProvider(t, server.URL, false)
+	ctx := context.Background()
+
+	seed, err := parseLiteLLMPricing([]byte(testLiteLLMPricing))
+	require.NoError(t, err)
+	require.NoError(t, cacheManager.SavePricing(ctx, "litellm", seed))
+
+	pricing, err := provider.GetPricing(ctx, "claude-sonnet-4-20250514")
+	require.NoError(t, err)
+	assert.InDelta(t, 4.0, pricing.Input, 1e-9)
+	assert.Equal(t, "cache", provider.Source())
+	assert.Zero(t, requests.Load())
+}
+
+func TestLiteLLMProvider_FallsBackToSnapshotWhenOffline(t *testing.T) {
+	var fail atomic.Bool
+	fail.Store(true)
+	server, requests := newTestLiteLLMServer(t, &fail)
+	provider, _ := newTestLiteLLMProvider(t, server.URL, false)
+	ctx := context.Background()
+
+	pricing, err := provider.GetPricing(ctx, "claude-opus-4-20250514")
+	require.NoError(t, err)
+	assert.InDelta(t, 15.0, pricing.Input, 1e-9)
+	assert.InDelta(t, 75.0, pricing.Output, 1e-9)
+	assert.Equal(t, "snapshot", provider.Source())
+
+	// A failed fetch isn't retried on every lookup
+	_, err = provider.GetPricing(ctx, "claude-opus-4-20250514")
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), requests.Load())
+
+	// An explicit refresh picks up remote prices once the network is back
+	fail.Store(false)
+	require.NoError(t, provider.RefreshPricing(ctx))
+	assert.Equal(t, "remote", provider.Source())
+}
+
+func TestLiteLLMProvider_OfflineModeNeverFetches(t *testing.T) {
+	var fail atomic.Bool
+	server, requests := newTestLiteLLMServer(t, &fail)
+	provider, _ := newTestLiteLLMProvider(t, server.URL, true)
+
+	pricing, err := provider.GetPricing(context.Background(), "claude-3-5-haiku-20241022")
+	require.NoError(t, err)
+	assert.InDelta(t, 0.8, pricing.Input, 1e-9)
+	assert.Equal(t, "snapshot", provider.Source())
+	assert.Zero(t, requests.Load())
+	assert.Error(t, provider.RefreshPricing(context.Background()))
+}
+
+func TestParseLiteLLMPricing_EmbeddedSnapshot(t *testing.T) {
+	pricing, err := parseLiteLLMPricing(liteLLMSnapshot)
+	require.NoError(t, err)
+	assert.NotEmpty(t, pricing)
+	for name, price := range pricing {
+		assert.Positive(t, price.Input, name)
+		assert.Greater(t, price.Output, price.Input, name)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGetPricing(t *testing.T) {
@@ -179,3 +180,25 @@ func TestPlanConsistency(t *testing.T) {
 			"Plan %s should have a name", planID)
 	}
 }
+
+func TestVendorPricing(t *testing.T) {
+	pricing, ok := VendorPricing("gpt-5-codex")
+	require.True(t, ok)
+	assert.Equal(t, 1.25, pricing.Input)
+	assert.Equal(t, 0.125, pricing.CacheRead)
+
+	dated, ok := VendorPricing("openai/gpt-5-mini-2025-08-07")
+	require.True(t, ok, "provider prefixes and versions are matched")
+	assert.Equal(t, openAIPricingMap["gpt-5-mini"], dated)
+
+	_, ok = VendorPricing("gpt-50")
+	assert.False(t, ok)
+	_, ok = VendorPricing(ModelSonnet)
+	assert.False(t, ok)
+
+	gemini, ok := VendorPricing("gemini-2.5-flash-lite-preview-09-2025")
+	require.True(t, ok)
+	assert.Equal(t, geminiPricingMap["gemini-2.5-flash-lite"], gemini, "the longest known name wins")
+
+	assert.Equal(t, openAIPricingMap["o3"], GetPricing("o3"), "GetPricing doesn't price other vendors' models as Sonnet")
+}
@@ -11,11 +11,47 @@ import (
 type CostMode int
 
 const (
-	CostModeAuto CostMode = iota
-	CostModeCached
-	CostModeCalculated
+	CostModeAuto       CostMode = iota // Use costUSD from the logs when present, otherwise calculate
+	CostModeDisplay                    // Always use costUSD from the logs (zero when missing)
+	CostModeCalculated                 // Always calculate from token counts and pricing
 )
 
+// CostModeCached is the former name of CostModeDisplay
+const CostModeCached = CostModeDisplay
+
+// Cost sources recorded on each UsageEntry
+const (
+	CostSourceLog        = "log"        // costUSD reported in the log entry
+	CostSourceCalculated = "calculated" // Calculated from token counts and pricing
+	CostSourceSummary    = "summary"    // Restored from a cached file summary
+)
+
+// String returns the configuration name of the cost mode
+func (m CostMode) String() string {
+	switch m {
+	case CostModeDisplay:
+		return "display"
+	case CostModeCalculated:
+		return "calculate"
+	default:
+		return "auto"
+	}
+}
+
+// ParseCostMode parses a cost mode name; an empty string selects auto
+func ParseCostMode(s string) (CostMode, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "auto":
+		return CostModeAuto, nil
+	case "display", "cached":
+		return CostModeDisplay, nil
+	case "calculate", "calculated":
+		return CostModeCalculated, nil
+	default:
+		return CostModeAuto, fmt.Errorf("invalid cost mode: %s (valid options: auto, display, calculate)", s)
+	}
+}
+
 // UsageEntry represents a single token usage event from Claude API
 type UsageEntry struct {
 	Timestamp           time.Time `json:"timestamp"`
@@ -24,12 +60,19 @@ type UsageEntry struct {
 	OutputTokens        int       `json:"output_tokens"`
 	CacheCreationTokens int       `json:"cache_creation_tokens"`
 	CacheReadTokens     int       `json:"cache_read_tokens"`
-	TotalTokens         int       `json:"total_tokens"` // Calculated field
-	CostUSD             float64   `json:"cost_usd"`     // Calculated field
+	ThinkingTokens      int       `json:"thinking_tokens"`       // Reasoning tokens reported separately from output
+	TotalTokens         int       `json:"total_tokens"`          // Calculated field
+	CostUSD             float64   `json:"cost_usd"`              // Calculated field
+	CostSource          string    `json:"cost_source,omitempty"` // Where CostUSD came from (log, calculated, summary)
+	CacheSavingsUSD     float64   `json:"cache_savings_usd"`     // Saved by prompt caching versus uncached input pricing
 	MessageID           string    `json:"message_id"`
 	RequestID           string    `json:"request_id"`
-	SessionID           string    `json:"session_id"` // Claude Code session ID
-	Project             string    `json:"project"`     // Project name extracted from file path
+	SessionID           string    `json:"session_id"`          // Claude Code session ID
+	Project             string    `json:"project"`             // Project name extracted from file path
+	Source              string    `json:"source,omitempty"`    // Assistant or provider the entry came from (claude, bedrock, codex, ...)
+	Tag                 string    `json:"tag,omitempty"`       // Cost allocation tag from the first matching data.cost_allocation rule
+	Anomalies           []string  `json:"anomalies,omitempty"` // Validation flags for implausible values
+	Suspect             bool      `json:"suspect,omitempty"`   // Flagged as implausible and excluded from metrics by default
 }
 
 // TokenCounts aggregates token counts with computed totals
@@ -38,11 +81,12 @@ type TokenCounts struct {
 	OutputTokens        int `json:"output_tokens"`
 	CacheCreationTokens int `json:"cache_creation_tokens"`
 	CacheReadTokens     int `json:"cache_read_tokens"`
+	ThinkingTokens      int `json:"thinking_tokens"`
 }
 
 // TotalTokens returns the sum of all token types
 func (tc *TokenCounts) TotalTokens() int {
-	return tc.InputTokens + tc.OutputTokens + tc.CacheCreationTokens + tc.CacheReadTokens
+	return tc.InputTokens + tc.OutputTokens + tc.CacheCreationTokens + tc.CacheReadTokens + tc.ThinkingTokens
 }
 
 // BurnRate represents token consumption rate metrics
@@ -58,6 +102,21 @@ type UsageProjection struct {
 	RemainingMinutes     float64 `json:"remaining_minutes"`
 }
 
+// TokenDepletion projects when a session block's token limit runs out at the current burn rate
+type TokenDepletion struct {
+	TokenLimit         int           `json:"token_limit"`
+	TokensUsed         int           `json:"tokens_used"`
+	TokensRemaining    int           `json:"tokens_remaining"`
+	TokensPerMinute    float64       `json:"tokens_per_minute"`
+	DepletionTime      *time.Time    `json:"depletion_time,omitempty"` // Nil when no tokens are being used
+	TimeToDepletion    time.Duration `json:"time_to_depletion"`
+	ResetTime          time.Time     `json:"reset_time"`        // When the session block ends and the limit resets
+	ProjectedTokens    int           `json:"projected_tokens"`  // Tokens used by the reset time at the current rate
+	ProjectedPercent   float64       `json:"projected_percent"` // ProjectedTokens as a percentage of the limit
+	Exhausted          bool          `json:"exhausted"`         // The limit has already been reached
+	RunsOutBeforeReset bool          `json:"runs_out_before_reset"`
+}
+
 // LimitMessage represents a limit detection message
 type LimitMessage struct {
 	Message   string    `json:"message"`
@@ -96,23 +155,53 @@ type ModelStat struct {
 	OutputTokens        int     `json:"output_tokens"`
 	CacheCreationTokens int     `json:"cache_creation_tokens"`
 	CacheReadTokens     int     `json:"cache_read_tokens"`
+	ThinkingTokens      int     `json:"thinking_tokens"`
 	TotalTokens         int     `json:"total_tokens"`
 	Cost                float64 `json:"cost"`
 }
 
 // CalculateTotalTokens calculates the total tokens for a usage entry
 func (u *UsageEntry) CalculateTotalTokens() int {
-	return u.InputTokens + u.OutputTokens + u.CacheCreationTokens + u.CacheReadTokens
+	return u.InputTokens + u.OutputTokens + u.CacheCreationTokens + u.CacheReadTokens + u.ThinkingTokens
+}
+
+// CostBreakdown is the cost of each kind of token of a usage entry
+type CostBreakdown struct {
+	Input         float64 `json:"input"`
+	Output        float64 `json:"output"`
+	CacheCreation float64 `json:"cache_creation"`
+	CacheRead     float64 `json:"cache_read"`
+	Thinking      float64 `json:"thinking"`
+}
+
+// Total returns the sum of the costs
+func (b CostBreakdown) Total() float64 {
+	return b.Input + b.Output + b.CacheCreation + b.CacheRead + b.Thinking
+}
+
+// CostBreakdown prices each kind of token of the entry, cache writes and reads at their own rates
+func (u *UsageEntry) CostBreakdown(pricing ModelPricing) CostBreakdown {
+	return CostBreakdown{
+		Input:         float64(u.InputTokens) / 1_000_000 * pricing.Input,
+		Output:        float64(u.OutputTokens) / 1_000_000 * pricing.Output,
+		CacheCreation: float64(u.CacheCreationTokens) / 1_000_000 * pricing.CacheCreation,
+		CacheRead:     float64(u.CacheReadTokens) / 1_000_000 * pricing.CacheRead,
+		Thinking:      float64(u.ThinkingTokens) / 1_000_000 * pricing.ThinkingRate(),
+	}
 }
 
 // CalculateCost calculates the cost for a usage entry based on model pricing
 func (u *UsageEntry) CalculateCost(pricing ModelPricing) float64 {
-	inputCost := float64(u.InputTokens) / 1_000_000 * pricing.Input
-	outputCost := float64(u.OutputTokens) / 1_000_000 * pricing.Output
-	cacheCreationCost := float64(u.CacheCreationTokens) / 1_000_000 * pricing.CacheCreation
-	cacheReadCost := float64(u.CacheReadTokens) / 1_000_000 * pricing.CacheRead
+	return u.CostBreakdown(pricing).Total()
+}
 
-	return inputCost + outputCost + cacheCreationCost + cacheReadCost
+// CalculateCacheSavings returns how much less the entry's cached input cost than the same
+// tokens at the uncached input price. Cache writes cost more than plain input, so entries
+// that only write to the cache save a negative amount.
+func (u *UsageEntry) CalculateCacheSavings(pricing ModelPricing) float64 {
+	cachedTokens := float64(u.CacheCreationTokens+u.CacheReadTokens) / 1_000_000
+	breakdown := u.CostBreakdown(pricing)
+	return cachedTokens*pricing.Input - breakdown.CacheCreation - breakdown.CacheRead
 }
 
 // NormalizeModel normalizes the model name for the entry
@@ -131,6 +220,7 @@ func (s *SessionBlock) AddEntry(entry UsageEntry) {
 	stat.OutputTokens += entry.OutputTokens
 	stat.CacheCreationTokens += entry.CacheCreationTokens
 	stat.CacheReadTokens += entry.CacheReadTokens
+	stat.ThinkingTokens += entry.ThinkingTokens
 	stat.TotalTokens += entry.TotalTokens
 	stat.Cost += entry.CostUSD
 
@@ -154,12 +244,14 @@ func (s *SessionBlock) CalculateTotals() {
 	s.TokenCounts.OutputTokens = 0
 	s.TokenCounts.CacheCreationTokens = 0
 	s.TokenCounts.CacheReadTokens = 0
+	s.TokenCounts.ThinkingTokens = 0
 
 	for _, stat := range s.ModelStats {
 		s.TokenCounts.InputTokens += stat.InputTokens
 		s.TokenCounts.OutputTokens += stat.OutputTokens
 		s.TokenCounts.CacheCreationTokens += stat.CacheCreationTokens
 		s.TokenCounts.CacheReadTokens += stat.CacheReadTokens
+		s.TokenCounts.ThinkingTokens += stat.ThinkingTokens
 	}
 }
 
@@ -197,47 +289,33 @@ func (s *SessionBlock) GenerateID() string {
 	return s.ID
 }
 
-// NormalizeModelName normalizes model names for consistent usage across the application
+// NormalizeModelName normalizes model names for consistent usage across the application.
+// User-defined aliases (see SetModelAliases) are applied first, then Bedrock and Vertex AI
+// identifiers are unwrapped and mapped through the built-in alias table. Identifiers no
+// alias matches are returned unchanged.
 func NormalizeModelName(model string) string {
 	if model == "" {
 		return ""
 	}
 
-	modelLower := strings.ToLower(model)
-
-	// Handle Claude 4 models
-	if strings.Contains(modelLower, "claude-opus-4-") ||
-		strings.Contains(modelLower, "claude-sonnet-4-") ||
-		strings.Contains(modelLower, "claude-haiku-4-") ||
-		strings.Contains(modelLower, "sonnet-4-") ||
-		strings.Contains(modelLower, "opus-4-") ||
-		strings.Contains(modelLower, "haiku-4-") {
-		return modelLower
-	}
-
-	// Handle specific model types
-	if strings.Contains(modelLower, "opus") {
-		if strings.Contains(modelLower, "4-") {
-			return modelLower
-		}
-		return "claude-3-opus"
-	}
-	if strings.Contains(modelLower, "sonnet") {
-		if strings.Contains(modelLower, "4-") {
-			return modelLower
-		}
-		if strings.Contains(modelLower, "3.5") || strings.Contains(modelLower, "3-5") {
-			return "claude-3-5-sonnet"
+	unwrapped := stripProviderWrapping(model)
+	if target, matched, ok := resolveModelAlias(ModelAliases(), model, unwrapped); ok {
+		if target == "" {
+			return strings.ToLower(matched)
 		}
-		return "claude-3-sonnet"
+		return target
 	}
-	if strings.Contains(modelLower, "haiku") {
-		if strings.Contains(modelLower, "3.5") || strings.Contains(modelLower, "3-5") {
-			return "claude-3-5-haiku"
+
+	if target, _, ok := resolveModelAlias(builtinModelAliases, unwrapped); ok {
+		if target == "" {
+			return unwrapped
 		}
-		return "claude-3-haiku"
+		return target
 	}
 
+	if unwrapped != strings.ToLower(model) {
+		return unwrapped
+	}
 	return model
 }
 
@@ -250,11 +328,17 @@ type AnalysisResult struct {
 	OutputTokens        int       `json:"output_tokens"`
 	CacheCreationTokens int       `json:"cache_creation_tokens"`
 	CacheReadTokens     int       `json:"cache_read_tokens"`
+	ThinkingTokens      int       `json:"thinking_tokens"`
 	TotalTokens         int       `json:"total_tokens"`
 	CostUSD             float64   `json:"cost_usd"`
 	Count               int       `json:"count"`               // For grouped results
 	GroupKey            string    `json:"group_key,omitempty"` // For grouped results
-	Project             string    `json:"project"`              // Project name
+	Project             string    `json:"project"`             // Project name
+	Source              string    `json:"source,omitempty"`    // Assistant or provider the usage came from
+
+	// Period describes the calendar boundaries of time-based groups in the configured timezone,
+	// including the real length of days that contain a DST transition
+	Period *PeriodBounds `json:"period,omitempty"`
 }
 
 // SummaryStats represents summary statistics for analysis results
@@ -268,6 +352,7 @@ type SummaryStats struct {
 	OutputTokens        int            `json:"output_tokens"`
 	CacheCreationTokens int            `json:"cache_creation_tokens"`
 	CacheReadTokens     int            `json:"cache_read_tokens"`
+	ThinkingTokens      int            `json:"thinking_tokens"`
 	MaxCost             float64        `json:"max_cost"`
 	MaxTokens           int            `json:"max_tokens"`
 	AvgCost             float64        `json:"avg_cost"`
@@ -48,6 +48,15 @@ func TestUsageEntry_CalculateTotalTokens(t *testing.T) {
 			},
 			want: 1_800_000,
 		},
+		{
+			name: "with thinking tokens",
+			entry: UsageEntry{
+				InputTokens:    100,
+				OutputTokens:   50,
+				ThinkingTokens: 200,
+			},
+			want: 350,
+		},
 	}
 
 	for _, tt := range tests {
@@ -105,6 +114,25 @@ func TestUsageEntry_CalculateCost(t *testing.T) {
 			pricing: GetPricing(ModelHaiku),
 			want:    0.00008 + 0.0002, // Very small costs
 		},
+		{
+			name: "thinking tokens fall back to output pricing",
+			entry: UsageEntry{
+				Model:          ModelSonnet,
+				OutputTokens:   100_000,
+				ThinkingTokens: 200_000,
+			},
+			pricing: GetPricing(ModelSonnet),
+			want:    1.5 + 3.0, // $1.50 output + $3.00 thinking at the output rate
+		},
+		{
+			name: "thinking tokens with explicit pricing",
+			entry: UsageEntry{
+				Model:          ModelSonnet,
+				ThinkingTokens: 1_000_000,
+			},
+			pricing: ModelPricing{Input: 3.0, Output: 15.0, Thinking: 10.0},
+			want:    10.0,
+		},
 	}
 
 	for _, tt := range tests {
@@ -115,6 +143,23 @@ func TestUsageEntry_CalculateCost(t *testing.T) {
 	}
 }
 
+func TestUsageEntry_CacheSavings(t *testing.T) {
+	pricing := ModelPricing{Input: 3.0, Output: 15.0, CacheCreation: 3.75, CacheRead: 0.30}
+
+	entry := UsageEntry{InputTokens: 1_000_000, OutputTokens: 1_000_000, CacheReadTokens: 1_000_000}
+	breakdown := entry.CostBreakdown(pricing)
+	assert.InDelta(t, 3.0, breakdown.Input, 0.000001)
+	assert.InDelta(t, 15.0, breakdown.Output, 0.000001)
+	assert.InDelta(t, 0.30, breakdown.CacheRead, 0.000001)
+	assert.InDelta(t, entry.CalculateCost(pricing), breakdown.Total(), 0.000001)
+	assert.InDelta(t, 2.70, entry.CalculateCacheSavings(pricing), 0.000001, "cache reads instead of uncached input")
+
+	writeOnly := UsageEntry{CacheCreationTokens: 1_000_000}
+	assert.InDelta(t, -0.75, writeOnly.CalculateCacheSavings(pricing), 0.000001, "cache writes cost more than plain input")
+
+	assert.Zero(t, (&UsageEntry{InputTokens: 1000}).CalculateCacheSavings(pricing))
+}
+
 func TestSessionBlock_AddEntry(t *testing.T) {
 	session := &SessionBlock{
 		StartTime: time.Now(),
@@ -263,3 +308,34 @@ func TestSessionBlock_JSONMarshaling(t *testing.T) {
 	assert.Len(t, decoded.ModelStats, 1)
 	assert.Equal(t, session.ModelStats[ModelSonnet], decoded.ModelStats[ModelSonnet])
 }
+
+func TestParseCostMode(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    CostMode
+		wantErr bool
+	}{
+		{"", CostModeAuto, false},
+		{"auto", CostModeAuto, false},
+		{"Display", CostModeDisplay, false},
+		{"calculate", CostModeCalculated, false},
+		{"bogus", CostModeAuto, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseCostMode(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+
+			// String round-trips through ParseCostMode
+			again, err := ParseCostMode(got.String())
+			require.NoError(t, err)
+			assert.Equal(t, got, again)
+		})
+	}
+}
@@ -55,8 +55,12 @@ func (u *UsageEntry) Validate() error {
 		return ValidationError{Field: "CacheReadTokens", Message: "cache read tokens cannot be negative"}
 	}
 
+	if u.ThinkingTokens < 0 {
+		return ValidationError{Field: "ThinkingTokens", Message: "thinking tokens cannot be negative"}
+	}
+
 	// Validate that at least some tokens were used
-	totalTokens := u.InputTokens + u.OutputTokens + u.CacheCreationTokens + u.CacheReadTokens
+	totalTokens := u.InputTokens + u.OutputTokens + u.CacheCreationTokens + u.CacheReadTokens + u.ThinkingTokens
 	if totalTokens == 0 {
 		return ValidationError{Field: "Tokens", Message: "at least one token type must be greater than zero"}
 	}
@@ -151,6 +155,10 @@ func (m *ModelPricing) Validate() error {
 		return ValidationError{Field: "CacheRead", Message: "cache read price cannot be negative"}
 	}
 
+	if m.Thinking < 0 {
+		return ValidationError{Field: "Thinking", Message: "thinking price cannot be negative"}
+	}
+
 	// Validate that output is typically more expensive than input
 	if m.Output < m.Input {
 		return ValidationError{Field: "Output", Message: "output price is typically higher than input price"}
@@ -177,11 +185,15 @@ func validateModelStat(stat ModelStat) error {
 		return ValidationError{Field: "CacheReadTokens", Message: "cache read tokens cannot be negative"}
 	}
 
+	if stat.ThinkingTokens < 0 {
+		return ValidationError{Field: "ThinkingTokens", Message: "thinking tokens cannot be negative"}
+	}
+
 	if stat.Cost < 0 {
 		return ValidationError{Field: "Cost", Message: "cost cannot be negative"}
 	}
 
-	calculatedTotal := stat.InputTokens + stat.OutputTokens + stat.CacheCreationTokens + stat.CacheReadTokens
+	calculatedTotal := stat.InputTokens + stat.OutputTokens + stat.CacheCreationTokens + stat.CacheReadTokens + stat.ThinkingTokens
 	if stat.TotalTokens != calculatedTotal {
 		return ValidationError{Field: "TotalTokens", Message: "total tokens does not match sum of individual token types"}
 	}
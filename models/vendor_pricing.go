@@ -0,0 +1,56 @@
+package models
+
+import "strings"
+
+// openAIPricingMap stores pricing for the OpenAI models Codex CLI runs. OpenAI doesn't charge
+// for writing the prompt cache, so cache creation is priced like input; reasoning tokens are
+// billed as output.
+var openAIPricingMap = map[string]ModelPricing{
+	"gpt-5":              {Input: 1.25, Output: 10.00, CacheCreation: 1.25, CacheRead: 0.125},
+	"gpt-5-codex":        {Input: 1.25, Output: 10.00, CacheCreation: 1.25, CacheRead: 0.125},
+	"gpt-5-mini":         {Input: 0.25, Output: 2.00, CacheCreation: 0.25, CacheRead: 0.025},
+	"gpt-5-nano":         {Input: 0.05, Output: 0.40, CacheCreation: 0.05, CacheRead: 0.005},
+	"gpt-5.1":            {Input: 1.25, Output: 10.00, CacheCreation: 1.25, CacheRead: 0.125},
+	"gpt-5.1-codex":      {Input: 1.25, Output: 10.00, CacheCreation: 1.25, CacheRead: 0.125},
+	"gpt-5.1-codex-mini": {Input: 0.25, Output: 2.00, CacheCreation: 0.25, CacheRead: 0.025},
+	"gpt-4.1":            {Input: 2.00, Output: 8.00, CacheCreation: 2.00, CacheRead: 0.50},
+	"gpt-4.1-mini":       {Input: 0.40, Output: 1.60, CacheCreation: 0.40, CacheRead: 0.10},
+	"o3":                 {Input: 2.00, Output: 8.00, CacheCreation: 2.00, CacheRead: 0.50},
+	"o4-mini":            {Input: 1.10, Output: 4.40, CacheCreation: 1.10, CacheRead: 0.275},
+	"codex-mini-latest":  {Input: 1.50, Output: 6.00, CacheCreation: 1.50, CacheRead: 0.375},
+}
+
+// geminiPricingMap stores pricing for the Google models Gemini CLI runs, at the rates for
+// prompts up to 200k tokens. Gemini CLI relies on implicit caching, which isn't charged for
+// writing; thinking tokens are billed as output.
+var geminiPricingMap = map[string]ModelPricing{
+	"gemini-2.5-pro":        {Input: 1.25, Output: 10.00, CacheCreation: 1.25, CacheRead: 0.125},
+	"gemini-2.5-flash":      {Input: 0.30, Output: 2.50, CacheCreation: 0.30, CacheRead: 0.03},
+	"gemini-2.5-flash-lite": {Input: 0.10, Output: 0.40, CacheCreation: 0.10, CacheRead: 0.01},
+	"gemini-2.0-flash":      {Input: 0.10, Output: 0.40, CacheCreation: 0.10, CacheRead: 0.025},
+	"gemini-3-pro-preview":  {Input: 2.00, Output: 12.00, CacheCreation: 2.00, CacheRead: 0.20},
+}
+
+// VendorPricing returns the pricing of a model of another vendor than Anthropic, for the
+// logs of other coding assistants. Dated and suffixed versions such as
+// gpt-5-codex-2025-09-15 or gemini-2.5-flash-preview-09-2025 are priced like the longest
+// known name they start with.
+func VendorPricing(model string) (ModelPricing, bool) {
+	name := stripProviderWrapping(model)
+	for _, pricingMap := range []map[string]ModelPricing{openAIPricingMap, geminiPricingMap} {
+		if pricing, ok := pricingMap[name]; ok {
+			return pricing, true
+		}
+	}
+
+	var best ModelPricing
+	var bestName string
+	for _, pricingMap := range []map[string]ModelPricing{openAIPricingMap, geminiPricingMap} {
+		for known, pricing := range pricingMap {
+			if strings.HasPrefix(name, known+"-") && len(known) > len(bestName) {
+				best, bestName = pricing, known
+			}
+		}
+	}
+	return best, bestName != ""
+}
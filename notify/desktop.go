@@ -0,0 +1,102 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// DesktopNotifier shows native desktop notifications through the platform's own tooling:
+// osascript on macOS, notify-send on Linux and a PowerShell toast on Windows
+type DesktopNotifier struct {
+	goos string
+	run  func(ctx context.Context, name string, args ...string) error
+}
+
+// NewDesktopNotifier creates a desktop notifier for the current platform
+func NewDesktopNotifier() *DesktopNotifier {
+	return &DesktopNotifier{
+		goos: runtime.GOOS,
+		run: func(ctx context.Context, name string, args ...string) error {
+			output, err := exec.CommandContext(ctx, name, args...).CombinedOutput()
+			if err != nil && len(output) > 0 {
+				return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(output)))
+			}
+			return err
+		},
+	}
+}
+
+// Name returns the name of this notifier
+func (d *DesktopNotifier) Name() string {
+	return "desktop"
+}
+
+// Available reports whether the platform's notification tool is installed
+func (d *DesktopNotifier) Available() bool {
+	name, _, err := d.command(Notification{})
+	if err != nil {
+		return false
+	}
+	_, err = exec.LookPath(name)
+	return err == nil
+}
+
+// Notify shows a desktop notification
+func (d *DesktopNotifier) Notify(ctx context.Context, n Notification) error {
+	name, args, err := d.command(n)
+	if err != nil {
+		return err
+	}
+	if err := d.run(ctx, name, args...); err != nil {
+		return fmt.Errorf("failed to show desktop notification: %w", err)
+	}
+	return nil
+}
+
+// command returns the program and arguments that show n on this platform
+func (d *DesktopNotifier) command(n Notification) (string, []string, error) {
+	switch d.goos {
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s",
+			appleScriptString(n.Message), appleScriptString(n.Title))
+		if n.Urgent {
+			script += ` sound name "Basso"`
+		}
+		return "osascript", []string{"-e", script}, nil
+	case "linux", "freebsd", "openbsd", "netbsd":
+		urgency := "normal"
+		if n.Urgent {
+			urgency = "critical"
+		}
+		return "notify-send", []string{"--app-name=claudecat", "--urgency=" + urgency, n.Title, n.Message}, nil
+	case "windows":
+		script := fmt.Sprintf(windowsToastScript, powerShellString(n.Title), powerShellString(n.Message))
+		return "powershell", []string{"-NoProfile", "-NonInteractive", "-Command", script}, nil
+	default:
+		return "", nil, fmt.Errorf("%w: %s", ErrUnsupported, d.goos)
+	}
+}
+
+// windowsToastScript shows a toast through the WinRT notification API, which needs no extra modules
+const windowsToastScript = `[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] > $null
+$template = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)
+$text = $template.GetElementsByTagName('text')
+$text.Item(0).AppendChild($template.CreateTextNode(%s)) > $null
+$text.Item(1).AppendChild($template.CreateTextNode(%s)) > $null
+$toast = [Windows.UI.Notifications.ToastNotification]::new($template)
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier('claudecat').Show($toast)`
+
+// appleScriptString quotes s as an AppleScript string literal
+func appleScriptString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
+
+// powerShellString quotes s as a single-quoted PowerShell string literal
+func powerShellString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
@@ -0,0 +1,70 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDesktopNotifier_Command(t *testing.T) {
+	n := Notification{Title: `Limit "soon"`, Message: `It's 90% \ used`, Urgent: true}
+
+	tests := []struct {
+		goos     string
+		wantName string
+		wantArgs []string
+	}{
+		{
+			goos:     "darwin",
+			wantName: "osascript",
+			wantArgs: []string{"-e", `display notification "It's 90% \\ used" with title "Limit \"soon\"" sound name "Basso"`},
+		},
+		{
+			goos:     "linux",
+			wantName: "notify-send",
+			wantArgs: []string{"--app-name=claudecat", "--urgency=critical", `Limit "soon"`, `It's 90% \ used`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.goos, func(t *testing.T) {
+			d := &DesktopNotifier{goos: tt.goos}
+			name, args, err := d.command(n)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantName, name)
+			assert.Equal(t, tt.wantArgs, args)
+		})
+	}
+
+	t.Run("windows", func(t *testing.T) {
+		d := &DesktopNotifier{goos: "windows"}
+		name, args, err := d.command(n)
+		require.NoError(t, err)
+		assert.Equal(t, "powershell", name)
+		assert.Contains(t, args[len(args)-1], `CreateTextNode('It''s 90% \ used')`)
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		d := &DesktopNotifier{goos: "plan9"}
+		_, _, err := d.command(n)
+		assert.ErrorIs(t, err, ErrUnsupported)
+	})
+}
+
+func TestDesktopNotifier_Notify(t *testing.T) {
+	var gotName string
+	d := &DesktopNotifier{
+		goos: "linux",
+		run: func(ctx context.Context, name string, args ...string) error {
+			gotName = name
+			return errors.New("no display")
+		},
+	}
+
+	err := d.Notify(context.Background(), Notification{Title: "t", Message: "m"})
+	assert.Equal(t, "notify-send", gotName)
+	assert.ErrorContains(t, err, "no display")
+}
@@ -0,0 +1,67 @@
+package notify
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/penwyp/claudecat/calculations"
+)
+
+// BudgetNotification describes a budget threshold crossing
+func BudgetNotification(alert calculations.BudgetAlert) Notification {
+	return Notification{
+		Event:     EventBudgetAlert,
+		Title:     "claudecat budget alert",
+		Message:   alert.Message(),
+		Urgent:    alert.Exceeded(),
+		Timestamp: time.Now(),
+	}
+}
+
+// CostAlertNotification describes the active session block reaching a cost ceiling
+func CostAlertNotification(alert calculations.SessionCostAlert, now time.Time) Notification {
+	return Notification{
+		Event:     EventCostAlert,
+		Title:     "Claude session cost alert",
+		Message:   alert.Message(),
+		Urgent:    alert.Exceeded(),
+		SessionID: alert.BlockID,
+		Timestamp: now,
+	}
+}
+
+// AnomalyNotification describes a burn rate spike
+func AnomalyNotification(anomaly calculations.Anomaly) Notification {
+	return Notification{
+		Event:     EventUsageAnomaly,
+		Title:     "Claude usage spike",
+		Message:   anomaly.Message(),
+		SessionID: anomaly.BlockID,
+		Timestamp: anomaly.DetectedAt,
+	}
+}
+
+// SessionNotification describes a session starting or ending. Other session change
+// types, such as routine updates, return false.
+func SessionNotification(eventType, sessionID string, now time.Time) (Notification, bool) {
+	switch EventType(eventType) {
+	case EventSessionStart:
+		return Notification{
+			Event:     EventSessionStart,
+			Title:     "Claude session started",
+			Message:   fmt.Sprintf("Session %s started", sessionID),
+			SessionID: sessionID,
+			Timestamp: now,
+		}, true
+	case EventSessionEnd:
+		return Notification{
+			Event:     EventSessionEnd,
+			Title:     "Claude session ended",
+			Message:   fmt.Sprintf("Session %s ended", sessionID),
+			SessionID: sessionID,
+			Timestamp: now,
+		}, true
+	default:
+		return Notification{}, false
+	}
+}
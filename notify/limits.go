@@ -0,0 +1,138 @@
+package notify
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/penwyp/claudecat/calculations"
+	"github.com/penwyp/claudecat/models"
+)
+
+const maxLimitMessageLength = 200
+
+// LimitWarner turns monitoring updates into limit notifications. Each limit message found in
+// the logs is reported once, a projected token limit hit at most once per session block, and
+// each cost ceiling at most once per session block.
+type LimitWarner struct {
+	warnBefore time.Duration
+
+	mu          sync.Mutex
+	since       time.Time // Limit messages at or before this time were already reported or predate startup
+	warnedBlock string    // Session block already warned about its projected limit
+	costBlock   string    // Session block of the last cost alert
+	costCeiling float64   // Highest cost ceiling reported for costBlock
+}
+
+// NewLimitWarner creates a warner that reports a projected limit hit when the current burn
+// rate reaches the token limit within warnBefore. Limit messages logged before it was created
+// are not reported.
+func NewLimitWarner(warnBefore time.Duration) *LimitWarner {
+	return &LimitWarner{
+		warnBefore: warnBefore,
+		since:      time.Now(),
+	}
+}
+
+// Check returns the notifications due for the latest session blocks
+func (w *LimitWarner) Check(blocks []models.SessionBlock, tokenLimit int, tokensPerMinute float64, now time.Time) []Notification {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var notifications []Notification
+
+	// New limit messages from Claude itself
+	latest := w.since
+	for _, block := range blocks {
+		if block.IsGap {
+			continue
+		}
+		for _, limit := range block.LimitMessages {
+			if !limit.Timestamp.After(w.since) {
+				continue
+			}
+			if limit.Timestamp.After(latest) {
+				latest = limit.Timestamp
+			}
+			notifications = append(notifications, Notification{
+				Event:     EventLimitReached,
+				Title:     "Claude usage limit reached",
+				Message:   truncate(limit.Message, maxLimitMessageLength),
+				Urgent:    true,
+				SessionID: block.ID,
+				Timestamp: limit.Timestamp,
+			})
+		}
+	}
+	w.since = latest
+
+	// Projected limit hit for the active session
+	if tokenLimit <= 0 || w.warnBefore <= 0 {
+		return notifications
+	}
+	for _, block := range blocks {
+		if !block.IsActive || block.IsGap || block.ID == w.warnedBlock {
+			continue
+		}
+
+		tokens := block.TokenCounts.TotalTokens()
+		remaining := tokenLimit - tokens
+		if remaining <= 0 {
+			w.warnedBlock = block.ID
+			notifications = append(notifications, Notification{
+				Event:     EventLimitReached,
+				Title:     "Claude token limit reached",
+				Message:   fmt.Sprintf("Used %d of %d tokens in the current session", tokens, tokenLimit),
+				Urgent:    true,
+				SessionID: block.ID,
+				Timestamp: now,
+			})
+			break
+		}
+		if tokensPerMinute <= 0 {
+			break
+		}
+
+		untilLimit := time.Duration(float64(remaining) / tokensPerMinute * float64(time.Minute))
+		if untilLimit > w.warnBefore || !now.Add(untilLimit).Before(block.EndTime) {
+			break
+		}
+		w.warnedBlock = block.ID
+		notifications = append(notifications, Notification{
+			Event: EventLimitApproaching,
+			Title: "Claude token limit approaching",
+			Message: fmt.Sprintf("At %.0f tokens/min the %d token limit is reached in about %d min",
+				tokensPerMinute, tokenLimit, int(untilLimit.Minutes()+0.5)),
+			SessionID: block.ID,
+			Timestamp: now,
+		})
+		break
+	}
+
+	return notifications
+}
+
+// CheckCost returns a notification when alert reports a higher cost ceiling than was already
+// reported for its session block. A nil alert returns nothing.
+func (w *LimitWarner) CheckCost(alert *calculations.SessionCostAlert, now time.Time) []Notification {
+	if alert == nil {
+		return nil
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if alert.BlockID == w.costBlock && alert.Ceiling <= w.costCeiling {
+		return nil
+	}
+	w.costBlock, w.costCeiling = alert.BlockID, alert.Ceiling
+	return []Notification{CostAlertNotification(*alert, now)}
+}
+
+func truncate(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max-1]) + "…"
+}
@@ -0,0 +1,108 @@
+package notify
+
+import (
+	"testing"
+	"time"
+
+	"github.com/penwyp/claudecat/calculations"
+	"github.com/penwyp/claudecat/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func activeBlock(now time.Time, tokens int) models.SessionBlock {
+	return models.SessionBlock{
+		ID:          "block-1",
+		StartTime:   now.Add(-time.Hour),
+		EndTime:     now.Add(4 * time.Hour),
+		IsActive:    true,
+		TokenCounts: models.TokenCounts{InputTokens: tokens},
+	}
+}
+
+func TestLimitWarner_LimitMessages(t *testing.T) {
+	now := time.Now()
+	w := NewLimitWarner(30 * time.Minute)
+	w.since = now.Add(-10 * time.Minute)
+
+	block := activeBlock(now, 100)
+	block.LimitMessages = []models.LimitMessage{
+		{Message: "old limit", Timestamp: now.Add(-20 * time.Minute)},
+		{Message: "Claude AI usage limit reached", Timestamp: now.Add(-time.Minute)},
+	}
+
+	notifications := w.Check([]models.SessionBlock{block}, 0, 0, now)
+	require.Len(t, notifications, 1)
+	assert.Equal(t, "Claude AI usage limit reached", notifications[0].Message)
+	assert.True(t, notifications[0].Urgent)
+
+	// Already reported
+	assert.Empty(t, w.Check([]models.SessionBlock{block}, 0, 0, now))
+}
+
+func TestLimitWarner_ProjectedLimit(t *testing.T) {
+	now := time.Now()
+	w := NewLimitWarner(30 * time.Minute)
+	blocks := []models.SessionBlock{activeBlock(now, 80_000)}
+
+	// 20,000 tokens left at 500/min is 40 minutes away
+	assert.Empty(t, w.Check(blocks, 100_000, 500, now))
+
+	// At 1,000/min it is 20 minutes away
+	notifications := w.Check(blocks, 100_000, 1000, now)
+	require.Len(t, notifications, 1)
+	assert.Contains(t, notifications[0].Message, "about 20 min")
+	assert.False(t, notifications[0].Urgent)
+
+	// Only once per session block
+	assert.Empty(t, w.Check(blocks, 100_000, 2000, now))
+
+	// A new block that is already over the limit
+	over := activeBlock(now, 120_000)
+	over.ID = "block-2"
+	notifications = w.Check([]models.SessionBlock{over}, 100_000, 0, now)
+	require.Len(t, notifications, 1)
+	assert.True(t, notifications[0].Urgent)
+}
+
+func TestLimitWarner_IgnoresLimitAfterSessionEnd(t *testing.T) {
+	now := time.Now()
+	w := NewLimitWarner(time.Hour)
+	block := activeBlock(now, 80_000)
+	block.EndTime = now.Add(10 * time.Minute)
+
+	// The limit would be reached after the session resets
+	assert.Empty(t, w.Check([]models.SessionBlock{block}, 100_000, 1000, now))
+}
+
+func TestLimitWarner_CheckCost(t *testing.T) {
+	now := time.Now()
+	w := NewLimitWarner(30 * time.Minute)
+	ceilings := []float64{5, 10}
+
+	block := activeBlock(now, 100)
+	block.CostUSD = 4
+	assert.Empty(t, w.CheckCost(calculations.CheckSessionCost(block, ceilings), now))
+
+	block.CostUSD = 6
+	notifications := w.CheckCost(calculations.CheckSessionCost(block, ceilings), now)
+	require.Len(t, notifications, 1)
+	assert.Equal(t, EventCostAlert, notifications[0].Event)
+	assert.Equal(t, "block-1", notifications[0].SessionID)
+	assert.False(t, notifications[0].Urgent)
+
+	// The same ceiling is reported once per block
+	block.CostUSD = 7
+	assert.Empty(t, w.CheckCost(calculations.CheckSessionCost(block, ceilings), now))
+
+	block.CostUSD = 12
+	notifications = w.CheckCost(calculations.CheckSessionCost(block, ceilings), now)
+	require.Len(t, notifications, 1)
+	assert.True(t, notifications[0].Urgent)
+
+	// A new block starts over
+	next := activeBlock(now.Add(5*time.Hour), 100)
+	next.ID = "block-2"
+	next.CostUSD = 6
+	assert.Len(t, w.CheckCost(calculations.CheckSessionCost(next, ceilings), now), 1)
+}
@@ -0,0 +1,61 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/penwyp/claudecat/logging"
+)
+
+// EventType identifies what triggered a notification
+type EventType string
+
+const (
+	EventSessionStart     EventType = "session_start"
+	EventSessionEnd       EventType = "session_end"
+	EventLimitReached     EventType = "limit_reached"
+	EventLimitApproaching EventType = "limit_approaching"
+	EventBudgetAlert      EventType = "budget_alert"
+	EventCostAlert        EventType = "cost_alert"
+	EventUsageAnomaly     EventType = "usage_anomaly"
+)
+
+// Notification is a message delivered to the user outside the terminal
+type Notification struct {
+	Event     EventType `json:"event"`
+	Title     string    `json:"title"`
+	Message   string    `json:"message"`
+	Urgent    bool      `json:"urgent"` // Limit reached rather than approaching
+	SessionID string    `json:"session_id,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Notifier delivers notifications to one destination
+type Notifier interface {
+	// Notify delivers a single notification
+	Notify(ctx context.Context, n Notification) error
+
+	// Name returns the name of this notifier
+	Name() string
+}
+
+// ErrUnsupported is returned when a notifier can't run on this platform
+var ErrUnsupported = errors.New("notifications not supported on this platform")
+
+// sendTimeout bounds how long a single notifier may take to deliver one notification
+const sendTimeout = 10 * time.Second
+
+// Send delivers every notification through every notifier, logging failures.
+// It blocks until all deliveries finish, so callers usually run it in a goroutine.
+func Send(ctx context.Context, notifiers []Notifier, notifications []Notification) {
+	for _, notification := range notifications {
+		for _, notifier := range notifiers {
+			sendCtx, cancel := context.WithTimeout(ctx, sendTimeout)
+			if err := notifier.Notify(sendCtx, notification); err != nil {
+				logging.LogWarnf("Failed to send %s notification: %v", notifier.Name(), err)
+			}
+			cancel()
+		}
+	}
+}
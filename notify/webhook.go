@@ -0,0 +1,116 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"text/template"
+	"time"
+)
+
+// WebhookNotifier POSTs notifications to an HTTP endpoint such as a Slack, Discord or
+// PagerDuty webhook. Without a payload template the body is the Notification as JSON.
+type WebhookNotifier struct {
+	url      string
+	headers  map[string]string
+	template *template.Template
+	client   *http.Client
+}
+
+// NewWebhookNotifier creates a webhook notifier. payloadTemplate is an optional Go text/template
+// executed with the Notification; its json function quotes a value as JSON, e.g.
+//
+//	{"text": {{json .Message}}}
+func NewWebhookNotifier(webhookURL string, headers map[string]string, payloadTemplate string) (*WebhookNotifier, error) {
+	parsed, err := url.Parse(webhookURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid webhook URL: %q", webhookURL)
+	}
+
+	w := &WebhookNotifier{
+		url:     webhookURL,
+		headers: headers,
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+
+	if payloadTemplate != "" {
+		tmpl, err := template.New("webhook").Funcs(template.FuncMap{"json": jsonValue}).Parse(payloadTemplate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid webhook payload template: %w", err)
+		}
+		w.template = tmpl
+	}
+
+	return w, nil
+}
+
+// Name returns the name of this notifier
+func (w *WebhookNotifier) Name() string {
+	return "webhook"
+}
+
+// Notify posts the notification to the webhook
+func (w *WebhookNotifier) Notify(ctx context.Context, n Notification) error {
+	body, err := w.payload(n)
+	if err != nil {
+		return err
+	}
+	return w.Post(ctx, body)
+}
+
+// Post sends a prepared JSON body to the webhook, bypassing the payload template
+func (w *WebhookNotifier) Post(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "claudecat")
+	for key, value := range w.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook returned status %d: %s", resp.StatusCode, bytes.TrimSpace(detail))
+	}
+	return nil
+}
+
+// payload renders the request body for n
+func (w *WebhookNotifier) payload(n Notification) ([]byte, error) {
+	if w.template == nil {
+		body, err := json.Marshal(n)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode webhook payload: %w", err)
+		}
+		return body, nil
+	}
+
+	var buf bytes.Buffer
+	if err := w.template.Execute(&buf, n); err != nil {
+		return nil, fmt.Errorf("failed to render webhook payload: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// jsonValue quotes v as JSON for use inside payload templates
+func jsonValue(v interface{}) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,84 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type capturedRequest struct {
+	header http.Header
+	body   []byte
+}
+
+func newCaptureServer(t *testing.T, status int) (*httptest.Server, chan capturedRequest) {
+	t.Helper()
+	requests := make(chan capturedRequest, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- capturedRequest{header: r.Header, body: body}
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte("nope"))
+	}))
+	t.Cleanup(server.Close)
+	return server, requests
+}
+
+func TestWebhookNotifier_DefaultPayload(t *testing.T) {
+	server, requests := newCaptureServer(t, http.StatusNoContent)
+	webhook, err := NewWebhookNotifier(server.URL, map[string]string{"Authorization": "Bearer secret"}, "")
+	require.NoError(t, err)
+
+	n := Notification{
+		Event:     EventSessionStart,
+		Title:     "Claude session started",
+		Message:   "Session s1 started",
+		SessionID: "s1",
+		Timestamp: time.Date(2025, 6, 19, 12, 0, 0, 0, time.UTC),
+	}
+	require.NoError(t, webhook.Notify(context.Background(), n))
+
+	req := <-requests
+	assert.Equal(t, "application/json", req.header.Get("Content-Type"))
+	assert.Equal(t, "Bearer secret", req.header.Get("Authorization"))
+
+	var got Notification
+	require.NoError(t, json.Unmarshal(req.body, &got))
+	assert.Equal(t, n, got)
+}
+
+func TestWebhookNotifier_Template(t *testing.T) {
+	server, requests := newCaptureServer(t, http.StatusOK)
+	webhook, err := NewWebhookNotifier(server.URL, nil, `{"text": {{json (printf "%s: %s" .Title .Message)}}}`)
+	require.NoError(t, err)
+
+	require.NoError(t, webhook.Notify(context.Background(), Notification{Title: "Limit", Message: `Say "hi"`}))
+
+	var got map[string]string
+	require.NoError(t, json.Unmarshal((<-requests).body, &got))
+	assert.Equal(t, `Limit: Say "hi"`, got["text"])
+}
+
+func TestWebhookNotifier_ErrorStatus(t *testing.T) {
+	server, _ := newCaptureServer(t, http.StatusBadRequest)
+	webhook, err := NewWebhookNotifier(server.URL, nil, "")
+	require.NoError(t, err)
+
+	err = webhook.Notify(context.Background(), Notification{Title: "t"})
+	assert.ErrorContains(t, err, "status 400: nope")
+}
+
+func TestNewWebhookNotifier_Invalid(t *testing.T) {
+	_, err := NewWebhookNotifier("ftp://example.com", nil, "")
+	assert.Error(t, err)
+
+	_, err = NewWebhookNotifier("https://example.com/hook", nil, "{{.Message")
+	assert.ErrorContains(t, err, "template")
+}
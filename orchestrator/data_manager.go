@@ -2,13 +2,18 @@ package orchestrator
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/penwyp/claudecat/cache"
 	"github.com/penwyp/claudecat/config"
+	errs "github.com/penwyp/claudecat/errors"
 	"github.com/penwyp/claudecat/fileio"
 	"github.com/penwyp/claudecat/logging"
 	"github.com/penwyp/claudecat/models"
@@ -26,11 +31,12 @@ type FileTracker struct {
 // DataManager manages data fetching and caching for monitoring
 type DataManager struct {
 	hoursBack int
-	dataPath  string
+	dataPaths []string
 
 	// Cache management
 	cache          *AnalysisResult
 	cacheTimestamp time.Time
+	results        *resultsCache // Recent results by file state, reused when nothing changed
 	mu             sync.RWMutex
 
 	// Error tracking
@@ -47,20 +53,42 @@ type DataManager struct {
 	// Pricing and deduplication
 	pricingProvider     models.PricingProvider
 	enableDeduplication bool
+	dedupIndex          *cache.DedupIndex
+	costMode            models.CostMode
+	validator           *models.EntryValidator
+	concurrency         fileio.ConcurrencyOptions
+	memoryBudget        int64               // Loads estimated to need more stream files one at a time
+	loadProgress        fileio.ProgressFunc // Reports the progress of the initial load
+	retryPolicy         errs.RetryPolicy
+	redactRaw           bool // Strip conversation text from the raw records kept for limit detection
+
+	// File change tracking. trackedFiles is nil until a file watcher provides the file list;
+	// once set, loads use it instead of walking the data paths.
+	trackedFiles map[string]bool
+	dirty        bool
+	changeNotify chan struct{}
 
 	// Session window tracking
+	sessionDuration    time.Duration
+	lateWriteBuffer    time.Duration
 	activeSessionFiles map[string]*FileTracker
 	fileTrackerMutex   sync.RWMutex
 	cacheUpdateTicker  *time.Ticker
 	cacheUpdateStop    chan struct{}
 }
 
-// NewDataManager creates a new data manager with cache and fetch settings
-func NewDataManager(hoursBack int, dataPath string) *DataManager {
+// NewDataManager creates a new data manager with cache and fetch settings.
+// All data paths are loaded together as a single data set.
+func NewDataManager(hoursBack int, dataPaths []string) *DataManager {
 	return &DataManager{
 		hoursBack:          hoursBack,
-		dataPath:           dataPath,
+		dataPaths:          dataPaths,
+		sessionDuration:    models.SessionDuration,
+		lateWriteBuffer:    30 * time.Minute,
 		activeSessionFiles: make(map[string]*FileTracker),
+		changeNotify:       make(chan struct{}, 1),
+		retryPolicy:        errs.DefaultRetryPolicy(),
+		results:            newResultsCache(resultsCacheSize),
 	}
 }
 
@@ -72,11 +100,20 @@ func (dm *DataManager) SetCacheStore(cacheStore fileio.CacheStore, config config
 	dm.summaryCacheConfig = config
 }
 
+// swapCacheStore replaces the summary cache store, keeping its settings. A nil store loads
+// without the summary cache.
+func (dm *DataManager) swapCacheStore(cacheStore fileio.CacheStore) {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	dm.cacheStore = cacheStore
+}
+
 // SetPricingProvider sets the pricing provider for cost calculations
 func (dm *DataManager) SetPricingProvider(provider models.PricingProvider) {
 	dm.mu.Lock()
 	defer dm.mu.Unlock()
 	dm.pricingProvider = provider
+	dm.results.clear()
 }
 
 // SetDeduplication sets whether to enable deduplication
@@ -86,6 +123,187 @@ func (dm *DataManager) SetDeduplication(enabled bool) {
 	dm.enableDeduplication = enabled
 }
 
+// SetDedupIndex sets the persistent dedup index used when deduplication is enabled
+func (dm *DataManager) SetDedupIndex(index *cache.DedupIndex) {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	dm.dedupIndex = index
+}
+
+// SetSessionWindow sets the session window length and how long after a window ends its
+// files are still treated as active to catch late writes
+func (dm *DataManager) SetSessionWindow(duration, lateWriteBuffer time.Duration) {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	if duration > 0 {
+		dm.sessionDuration = duration
+	}
+	if lateWriteBuffer >= 0 {
+		dm.lateWriteBuffer = lateWriteBuffer
+	}
+}
+
+// SetRetryPolicy sets how failed data loads are retried
+func (dm *DataManager) SetRetryPolicy(policy errs.RetryPolicy) {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	dm.retryPolicy = policy
+}
+
+// currentRetryPolicy returns the retry policy, which may change while monitoring
+func (dm *DataManager) currentRetryPolicy() errs.RetryPolicy {
+	dm.mu.RLock()
+	defer dm.mu.RUnlock()
+	return dm.retryPolicy
+}
+
+// SetCostMode sets how entry costs are determined
+func (dm *DataManager) SetCostMode(mode models.CostMode) {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	dm.costMode = mode
+}
+
+// currentCostMode returns the cost mode, which may change while monitoring
+func (dm *DataManager) currentCostMode() models.CostMode {
+	dm.mu.RLock()
+	defer dm.mu.RUnlock()
+	return dm.costMode
+}
+
+// SetConcurrency sets the worker count and scheduling used when loading many files
+func (dm *DataManager) SetConcurrency(concurrency fileio.ConcurrencyOptions) {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	dm.concurrency = concurrency
+}
+
+// SetMemoryBudget sets how many bytes a load may use before it streams files one at a time
+// and drops the raw records limit detection needs; 0 means no limit
+func (dm *DataManager) SetMemoryBudget(budget int64) {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	dm.memoryBudget = budget
+}
+
+// SetLoadProgress sets a callback that reports the progress of the initial load, which can
+// take a while over a large history
+func (dm *DataManager) SetLoadProgress(progress fileio.ProgressFunc) {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	dm.loadProgress = progress
+}
+
+// SetRedaction sets whether conversation text is stripped from the raw records kept for limit
+// detection
+func (dm *DataManager) SetRedaction(enabled bool) {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	dm.redactRaw = enabled
+}
+
+// SetValidator sets the validator used to flag implausible entries
+func (dm *DataManager) SetValidator(validator *models.EntryValidator) {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	dm.validator = validator
+	dm.results.clear()
+}
+
+// SetDataPaths switches the monitored data paths. The tracked file list and session window
+// files are dropped and the next GetData performs a full load of the new paths.
+func (dm *DataManager) SetDataPaths(dataPaths []string) {
+	dm.mu.Lock()
+	dm.dataPaths = dataPaths
+	dm.trackedFiles = nil
+	dm.initialLoadCompleted = false
+	dm.mu.Unlock()
+
+	dm.fileTrackerMutex.Lock()
+	dm.activeSessionFiles = make(map[string]*FileTracker)
+	dm.fileTrackerMutex.Unlock()
+}
+
+// TrackFiles sets the known JSONL files. After this, loads use the tracked list and
+// ApplyFileChanges keeps it current, so refreshes no longer walk the data paths.
+func (dm *DataManager) TrackFiles(files []string) {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	dm.trackedFiles = make(map[string]bool, len(files))
+	for _, file := range files {
+		dm.trackedFiles[file] = true
+	}
+}
+
+// ApplyFileChanges invalidates the cached summaries of changed files, updates the tracked
+// file list and marks the data stale so the next GetData reloads it
+func (dm *DataManager) ApplyFileChanges(changes []FileChange) {
+	if len(changes) == 0 {
+		return
+	}
+
+	dm.mu.Lock()
+	for _, change := range changes {
+		if dm.cacheStore != nil && change.Op != FileCreated {
+			absPath, err := filepath.Abs(change.Path)
+			if err != nil {
+				absPath = change.Path
+			}
+			if err := dm.cacheStore.InvalidateFileSummary(absPath); err != nil {
+				logging.LogDebugf("Failed to invalidate cache for %s: %v", filepath.Base(change.Path), err)
+			}
+		}
+		if dm.trackedFiles != nil {
+			if change.Op == FileRemoved {
+				delete(dm.trackedFiles, change.Path)
+			} else {
+				dm.trackedFiles[change.Path] = true
+			}
+		}
+		logging.LogDebugf("Data file %s: %s", change.Op, change.Path)
+	}
+	dm.dirty = true
+	dm.mu.Unlock()
+
+	// Wake up the monitoring loop without blocking if a wake-up is already pending
+	select {
+	case dm.changeNotify <- struct{}{}:
+	default:
+	}
+}
+
+// Changes returns a channel that receives a value whenever files change
+func (dm *DataManager) Changes() <-chan struct{} {
+	return dm.changeNotify
+}
+
+// trackedFileList returns the tracked files, or nil when files are discovered on each load
+func (dm *DataManager) trackedFileList() []string {
+	dm.mu.RLock()
+	defer dm.mu.RUnlock()
+	if dm.trackedFiles == nil {
+		return nil
+	}
+	files := make([]string, 0, len(dm.trackedFiles))
+	for file := range dm.trackedFiles {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	return files
+}
+
+// dataPathList returns the data paths, which a configuration reload can replace
+func (dm *DataManager) dataPathList() []string {
+	dm.mu.RLock()
+	defer dm.mu.RUnlock()
+	return dm.dataPaths
+}
+
+// pathsDescription returns the data paths joined for log messages
+func (dm *DataManager) pathsDescription() string {
+	return strings.Join(dm.dataPathList(), ", ")
+}
+
 // Start starts the DataManager background tasks
 func (dm *DataManager) Start(ctx context.Context) {
 	dm.startCacheUpdater(ctx)
@@ -97,7 +315,7 @@ func (dm *DataManager) Stop() {
 }
 
 // GetData gets monitoring data with caching and error handling
-func (dm *DataManager) GetData(forceRefresh bool) (*AnalysisResult, error) {
+func (dm *DataManager) GetData(ctx context.Context, forceRefresh bool) (*AnalysisResult, error) {
 	dm.mu.RLock()
 	// Check if this is the first load
 	isInitialLoad := !dm.initialLoadCompleted
@@ -105,61 +323,65 @@ func (dm *DataManager) GetData(forceRefresh bool) (*AnalysisResult, error) {
 
 	// For initial load, always fetch fresh data but allow cache writing
 	if isInitialLoad {
-		return dm.performInitialLoad()
+		return dm.performInitialLoad(ctx)
 	}
 
-	dm.mu.RLock()
-	// Check cache validity for subsequent loads
-	if !forceRefresh {
+	dm.mu.Lock()
+	// Check cache validity for subsequent loads; files reported by the watcher make it stale
+	if !forceRefresh && !dm.dirty {
 		result := dm.cache
-		dm.mu.RUnlock()
+		dm.mu.Unlock()
 		return result, nil
 	}
-	dm.mu.RUnlock()
+	// Changes arriving while we reload mark the data dirty again
+	dm.dirty = false
+	dm.mu.Unlock()
 
 	// Fetch fresh data with retries (watch mode - no cache writing)
-	maxRetries := 3
-	for attempt := 0; attempt < maxRetries; attempt++ {
-		logging.LogDebugf("Fetching fresh usage data (attempt %d/%d)", attempt+1, maxRetries)
-
-		data, err := dm.analyzeUsageWatchMode()
+	retryPolicy := dm.currentRetryPolicy()
+	attempts := 0
+	var data *AnalysisResult
+	err := retryPolicy.Retry(ctx, func() error {
+		attempts++
+		logging.LogDebugf("Fetching fresh usage data (attempt %d/%d)", attempts, retryPolicy.MaxAttempts)
+
+		var err error
+		data, err = dm.analyzeUsageWatchMode(ctx)
 		if err != nil {
 			dm.mu.Lock()
 			dm.lastError = err
 			dm.mu.Unlock()
 
-			if attempt < maxRetries-1 {
-				// Exponential backoff
-				backoff := time.Duration(100*(1<<attempt)) * time.Millisecond
-				time.Sleep(backoff)
-				continue
-			}
-
-			// All retries failed, check if we have cached data to fall back on
-			dm.mu.RLock()
-			if dm.cache != nil {
-				logging.LogWarn("Using cached data due to fetch error")
-				result := dm.cache
-				dm.mu.RUnlock()
-				return result, nil
+			// Empty data directories stay empty on retry
+			if errors.Is(err, ErrNoUsageEntries) {
+				return errs.Permanent(err)
 			}
+		}
+		return err
+	}, nil)
+	if err != nil {
+		// All retries failed, check if we have cached data to fall back on
+		dm.mu.RLock()
+		if dm.cache != nil {
+			logging.LogWarn("Using cached data due to fetch error")
+			result := dm.cache
 			dm.mu.RUnlock()
-
-			return nil, fmt.Errorf("failed to get usage data after %d attempts: %w", maxRetries, err)
+			return result, nil
 		}
+		dm.mu.RUnlock()
 
-		// Success - update cache
-		dm.mu.Lock()
-		dm.cache = data
-		dm.cacheTimestamp = time.Now()
-		dm.lastSuccessfulFetch = time.Now()
-		dm.lastError = nil
-		dm.mu.Unlock()
-
-		return data, nil
+		return nil, fmt.Errorf("failed to get usage data after %d attempts: %w", attempts, err)
 	}
 
-	return nil, fmt.Errorf("unexpected error in data fetching loop")
+	// Success - update cache
+	dm.mu.Lock()
+	dm.cache = data
+	dm.cacheTimestamp = time.Now()
+	dm.lastSuccessfulFetch = time.Now()
+	dm.lastError = nil
+	dm.mu.Unlock()
+
+	return data, nil
 }
 
 // InvalidateCache invalidates the cache
@@ -168,6 +390,7 @@ func (dm *DataManager) InvalidateCache() {
 	defer dm.mu.Unlock()
 	dm.cache = nil
 	dm.cacheTimestamp = time.Time{}
+	dm.results.clear()
 }
 
 // GetCacheAge returns the age of cached data in seconds
@@ -182,6 +405,13 @@ func (dm *DataManager) GetCacheAge() float64 {
 	return time.Since(dm.cacheTimestamp).Seconds()
 }
 
+// HasCacheStore reports whether file summaries are cached between runs
+func (dm *DataManager) HasCacheStore() bool {
+	dm.mu.RLock()
+	defer dm.mu.RUnlock()
+	return dm.cacheStore != nil
+}
+
 // GetLastError returns the last error encountered
 func (dm *DataManager) GetLastError() error {
 	dm.mu.RLock()
@@ -197,8 +427,12 @@ func (dm *DataManager) GetLastSuccessfulFetchTime() time.Time {
 }
 
 // performInitialLoad performs initial data loading with cache writing allowed
-func (dm *DataManager) performInitialLoad() (*AnalysisResult, error) {
+func (dm *DataManager) performInitialLoad(ctx context.Context) (*AnalysisResult, error) {
 	logging.LogInfo("Performing initial data load with cache support")
+	dataPaths := dm.dataPathList()
+	if len(dataPaths) == 0 {
+		return nil, ErrNoDataPaths
+	}
 
 	// First try to load from cache to check if we have cached data
 	if dm.cacheStore != nil {
@@ -206,16 +440,25 @@ func (dm *DataManager) performInitialLoad() (*AnalysisResult, error) {
 
 		// Load with cache first to check cache status
 		optsCache := fileio.LoadUsageEntriesOptions{
-			DataPath:            dm.dataPath,
+			DataPath:            dataPaths[0],
+			ExtraDataPaths:      dataPaths[1:],
+			Files:               dm.trackedFileList(),
 			HoursBack:           &dm.hoursBack,
-			Mode:                models.CostModeAuto,
+			Mode:                dm.currentCostMode(),
 			IncludeRaw:          true,
+			RawFilter:           sessions.IsLimitRecord,
+			RedactRaw:           dm.redactRaw,
 			CacheStore:          dm.cacheStore,
 			EnableDeduplication: dm.enableDeduplication,
+			DedupIndex:          dm.dedupIndex,
 			PricingProvider:     dm.pricingProvider,
+			Validator:           dm.validator,
+			Concurrency:         dm.concurrency,
+			MemoryBudget:        dm.memoryBudget,
+			Progress:            dm.loadProgress,
 		}
 
-		resultCache, err := fileio.LoadUsageEntries(optsCache)
+		resultCache, err := fileio.LoadUsageEntriesContext(ctx, optsCache)
 		if err == nil && len(resultCache.Entries) > 0 {
 			// We have cached data, check if files have changed
 			logging.LogInfof("Found %d cached entries, checking for file changes...", len(resultCache.Entries))
@@ -228,7 +471,7 @@ func (dm *DataManager) performInitialLoad() (*AnalysisResult, error) {
 
 			if !hasChanges {
 				logging.LogInfo("No file changes detected, using cached data")
-				data, err := dm.processUsageData(resultCache, "initial-cached")
+				data, err := dm.processUsageData(ctx, resultCache, "initial-cached")
 				if err != nil {
 					return nil, err
 				}
@@ -254,12 +497,21 @@ func (dm *DataManager) performInitialLoad() (*AnalysisResult, error) {
 
 	// Load usage entries with cache support and allow cache writing for initial load
 	opts := fileio.LoadUsageEntriesOptions{
-		DataPath:            dm.dataPath,
+		DataPath:            dataPaths[0],
+		ExtraDataPaths:      dataPaths[1:],
+		Files:               dm.trackedFileList(),
 		HoursBack:           &dm.hoursBack,
-		Mode:                models.CostModeAuto,
+		Mode:                dm.currentCostMode(),
 		IncludeRaw:          true,
+		RawFilter:           sessions.IsLimitRecord,
+		RedactRaw:           dm.redactRaw,
 		EnableDeduplication: dm.enableDeduplication,
+		DedupIndex:          dm.dedupIndex,
 		PricingProvider:     dm.pricingProvider,
+		Validator:           dm.validator,
+		Concurrency:         dm.concurrency,
+		MemoryBudget:        dm.memoryBudget,
+		Progress:            dm.loadProgress,
 	}
 
 	// Set cache store if available
@@ -267,13 +519,13 @@ func (dm *DataManager) performInitialLoad() (*AnalysisResult, error) {
 		opts.CacheStore = dm.cacheStore
 	}
 
-	result, err := fileio.LoadUsageEntries(opts)
+	result, err := fileio.LoadUsageEntriesContext(ctx, opts)
 	if err != nil {
-		logging.LogErrorf("Error loading usage entries from %s during initial load: %v", dm.dataPath, err)
+		logging.LogErrorf("Error loading usage entries from %s during initial load: %v", dm.pathsDescription(), err)
 		return nil, fmt.Errorf("failed to load usage entries: %w", err)
 	}
 
-	data, err := dm.processUsageData(result, "initial")
+	data, err := dm.processUsageData(ctx, result, "initial")
 	if err != nil {
 		return nil, err
 	}
@@ -291,16 +543,40 @@ func (dm *DataManager) performInitialLoad() (*AnalysisResult, error) {
 	return data, nil
 }
 
-// analyzeUsageWatchMode performs analysis in watch mode (no cache writing)
-func (dm *DataManager) analyzeUsageWatchMode() (*AnalysisResult, error) {
+// analyzeUsageWatchMode performs analysis in watch mode (no cache writing). A refresh that
+// finds the files and options of an earlier one reuses its result.
+func (dm *DataManager) analyzeUsageWatchMode(ctx context.Context) (*AnalysisResult, error) {
+	dataPaths := dm.dataPathList()
+	if len(dataPaths) == 0 {
+		return nil, ErrNoDataPaths
+	}
+
+	key := dm.currentResultsKey()
+	if key != "" {
+		if cached, ok := dm.results.get(key, time.Now()); ok {
+			logging.LogDebug("Data files unchanged, reusing the blocks of an earlier refresh")
+			data := *cached
+			data.Metadata.CacheUsed = true
+			return &data, nil
+		}
+	}
+
 	// Load usage entries in watch mode - no cache writing
 	opts := fileio.LoadUsageEntriesOptions{
-		DataPath:            dm.dataPath,
+		DataPath:            dataPaths[0],
+		ExtraDataPaths:      dataPaths[1:],
+		Files:               dm.trackedFileList(),
 		HoursBack:           &dm.hoursBack,
-		Mode:                models.CostModeAuto,
+		Mode:                dm.currentCostMode(),
 		IncludeRaw:          true,
+		RawFilter:           sessions.IsLimitRecord,
+		RedactRaw:           dm.redactRaw,
 		EnableDeduplication: dm.enableDeduplication,
+		DedupIndex:          dm.dedupIndex,
 		PricingProvider:     dm.pricingProvider,
+		Validator:           dm.validator,
+		Concurrency:         dm.concurrency,
+		MemoryBudget:        dm.memoryBudget,
 	}
 
 	// Set cache store if available
@@ -308,21 +584,94 @@ func (dm *DataManager) analyzeUsageWatchMode() (*AnalysisResult, error) {
 		opts.CacheStore = dm.cacheStore
 	}
 
-	result, err := fileio.LoadUsageEntries(opts)
+	result, err := fileio.LoadUsageEntriesContext(ctx, opts)
+	if err != nil {
+		logging.LogErrorf("Error loading usage entries from %s in watch mode: %v", dm.pathsDescription(), err)
+		return nil, fmt.Errorf("failed to load usage entries: %w", err)
+	}
+
+	data, err := dm.processUsageData(ctx, result, "watch")
+	if err != nil {
+		return nil, err
+	}
+	if key != "" {
+		dm.results.put(key, data, resultExpiry(data.Blocks, dm.hoursBack, time.Now()))
+	}
+	return data, nil
+}
+
+// LoadEntries loads the usage entries of the last hoursBack hours through the summary
+// cache, for history beyond the monitored window. Limit records aren't kept, so files the
+// cache knows aren't parsed.
+func (dm *DataManager) LoadEntries(ctx context.Context, hoursBack int) ([]models.UsageEntry, error) {
+	dataPaths := dm.dataPathList()
+	if len(dataPaths) == 0 {
+		return nil, ErrNoDataPaths
+	}
+
+	opts := fileio.LoadUsageEntriesOptions{
+		DataPath:            dataPaths[0],
+		ExtraDataPaths:      dataPaths[1:],
+		Files:               dm.trackedFileList(),
+		HoursBack:           &hoursBack,
+		Mode:                dm.currentCostMode(),
+		EnableDeduplication: dm.enableDeduplication,
+		DedupIndex:          dm.dedupIndex,
+		PricingProvider:     dm.pricingProvider,
+		Validator:           dm.validator,
+		Concurrency:         dm.concurrency,
+		MemoryBudget:        dm.memoryBudget,
+	}
+
+	// Set cache store if available
+	if dm.cacheStore != nil {
+		opts.CacheStore = dm.cacheStore
+	}
+
+	result, err := fileio.LoadUsageEntriesContext(ctx, opts)
 	if err != nil {
-		logging.LogErrorf("Error loading usage entries from %s in watch mode: %v", dm.dataPath, err)
 		return nil, fmt.Errorf("failed to load usage entries: %w", err)
 	}
+	return result.Entries, nil
+}
 
-	return dm.processUsageData(result, "watch")
+// currentResultsKey returns the results cache key for the current files and options, or ""
+// when the files can't be listed
+func (dm *DataManager) currentResultsKey() string {
+	files := dm.trackedFileList()
+	if files == nil {
+		var err error
+		files, err = fileio.DiscoverFilesInPaths(dm.dataPathList())
+		if err != nil {
+			logging.LogDebugf("Not caching results, failed to list data files: %v", err)
+			return ""
+		}
+	}
+
+	dm.mu.RLock()
+	opts := resultsOptions{
+		dataPaths:       dm.dataPaths,
+		hoursBack:       dm.hoursBack,
+		costMode:        dm.costMode,
+		deduplication:   dm.enableDeduplication,
+		sessionDuration: dm.sessionDuration,
+	}
+	dm.mu.RUnlock()
+	return resultsKey(files, opts)
 }
 
+// ErrNoUsageEntries is returned when the data paths hold no usage yet, as on first run
+var ErrNoUsageEntries = errors.New("no usage entries found")
+
+// ErrNoDataPaths is returned when loading without any data path set
+var ErrNoDataPaths = errors.New("no data paths to load")
+
 // processUsageData processes loaded usage data into analysis result
-func (dm *DataManager) processUsageData(result *fileio.LoadUsageEntriesResult, mode string) (*AnalysisResult, error) {
-	logging.LogInfof("Loaded %d usage entries from %s (%s mode)", len(result.Entries), dm.dataPath, mode)
+func (dm *DataManager) processUsageData(ctx context.Context, result *fileio.LoadUsageEntriesResult, mode string) (*AnalysisResult, error) {
+	logging.LogInfof("Loaded %d usage entries from %s (%s mode)", len(result.Entries), dm.pathsDescription(), mode)
 	if len(result.Entries) == 0 {
-		logging.LogWarnf("No usage entries found in %s", dm.dataPath)
-		return nil, fmt.Errorf("no usage entries found")
+		logging.LogInfof("No usage entries found in %s", dm.pathsDescription())
+		return nil, ErrNoUsageEntries
 	}
 
 	loadTime := result.Metadata.LoadDuration
@@ -330,35 +679,17 @@ func (dm *DataManager) processUsageData(result *fileio.LoadUsageEntriesResult, m
 
 	// Transform entries to blocks using SessionAnalyzer
 	transformStart := time.Now()
-	analyzer := sessions.NewSessionAnalyzer(5) // 5-hour sessions
-	blocks := analyzer.TransformToBlocks(result.Entries)
+	analyzer := sessions.NewSessionAnalyzerWithDuration(dm.sessionDuration)
+	blocks := analyzer.TransformToBlocksContext(ctx, result.Entries)
 	transformTime := time.Since(transformStart)
 	logging.LogInfof("Created %d blocks in %.3fs (%s mode)", len(blocks), transformTime.Seconds(), mode)
 
-	// Detect limits if we have raw entries
+	// Detect limits if we have raw entries; the loader only kept limit records
 	var limitsDetected int
 	if result.RawEntries != nil {
-		// Convert raw entries to the format expected by analyzer
-		rawEntries := make([]map[string]interface{}, len(result.RawEntries))
-		for i, entry := range result.RawEntries {
-			rawEntries[i] = entry
-		}
-
-		limitDetections := analyzer.DetectLimits(rawEntries)
+		limitDetections := analyzer.DetectLimits(result.RawEntries)
 		limitsDetected = len(limitDetections)
-
-		// Add limit messages to appropriate blocks
-		for i := range blocks {
-			var blockLimits []models.LimitMessage
-			for _, limit := range limitDetections {
-				if dm.isLimitInBlockTimerange(limit, blocks[i]) {
-					blockLimits = append(blockLimits, limit)
-				}
-			}
-			if len(blockLimits) > 0 {
-				blocks[i].LimitMessages = blockLimits
-			}
-		}
+		sessions.AttachLimits(blocks, limitDetections)
 	}
 
 	// Create metadata
@@ -386,58 +717,32 @@ func (dm *DataManager) processUsageData(result *fileio.LoadUsageEntriesResult, m
 	return analysisResult, nil
 }
 
-// checkForFileChanges checks if any files in the data path have changed since the cached metadata
+// checkForFileChanges checks if any file was added, removed or modified since the cached load
+// read it. Files the cached load re-parsed because their summary was stale are current and
+// don't count; only changes made during or after that load do.
 func (dm *DataManager) checkForFileChanges(cachedMetadata *fileio.LoadMetadata) (bool, error) {
 	logging.LogDebug("Checking for file changes since last cache...")
 
-	// Walk through the data path to find all .jsonl files
-	var hasChanges bool
+	if cachedMetadata == nil || cachedMetadata.FileStates == nil {
+		return true, nil
+	}
 
-	err := filepath.Walk(dm.dataPath, func(path string, info os.FileInfo, err error) error {
+	files := dm.trackedFileList()
+	if len(files) == 0 {
+		var err error
+		files, err = fileio.DiscoverFilesInPaths(dm.dataPathList())
 		if err != nil {
-			logging.LogWarnf("Error accessing path %s: %v", path, err)
-			return nil // Continue walking, don't fail entirely
+			return true, fmt.Errorf("failed to list data files: %w", err)
 		}
-
-		// Skip directories and non-jsonl files
-		if info.IsDir() || filepath.Ext(path) != ".jsonl" {
-			return nil
-		}
-
-		// Check if this file was processed in the cached metadata
-		// For now, use a simple heuristic: if the file's modification time
-		// is newer than the cache load time, consider it changed
-		// Since LoadMetadata doesn't have CacheLoadTime, we'll use a different approach
-		// We'll check if any file is newer than 1 minute ago (simple heuristic)
-		oneMinuteAgo := time.Now().Add(-1 * time.Minute)
-
-		if info.ModTime().After(oneMinuteAgo) {
-			logging.LogDebugf("File %s modified recently (%s)",
-				filepath.Base(path), info.ModTime())
-			hasChanges = true
-			return filepath.SkipDir // Exit early
-		}
-
-		return nil
-	})
-
-	if err != nil {
-		return true, fmt.Errorf("error walking data path: %w", err)
 	}
 
-	if hasChanges {
-		logging.LogDebug("File changes detected")
-	} else {
-		logging.LogDebug("No file changes detected")
+	if changed := fileio.ChangedFiles(cachedMetadata.FileStates, files); len(changed) > 0 {
+		logging.LogDebugf("File changes detected in %d files, first: %s", len(changed), changed[0])
+		return true, nil
 	}
 
-	return hasChanges, nil
-}
-
-// isLimitInBlockTimerange checks if a limit detection falls within a block's time range
-func (dm *DataManager) isLimitInBlockTimerange(limit models.LimitMessage, block models.SessionBlock) bool {
-	return (limit.Timestamp.After(block.StartTime) || limit.Timestamp.Equal(block.StartTime)) &&
-		(limit.Timestamp.Before(block.EndTime) || limit.Timestamp.Equal(block.EndTime))
+	logging.LogDebug("No file changes detected")
+	return false, nil
 }
 
 // updateSessionWindowFiles updates the list of files that are in the active session window
@@ -447,8 +752,8 @@ func (dm *DataManager) updateSessionWindowFiles(blocks []models.SessionBlock) {
 	now := time.Now()
 
 	for _, block := range blocks {
-		// Consider blocks active if they are marked as active or ended within the last 5 hours
-		if block.IsActive || (now.Sub(block.EndTime) < 5*time.Hour) {
+		// Consider blocks active if they are marked as active or ended within the last session window
+		if block.IsActive || (now.Sub(block.EndTime) < dm.sessionDuration) {
 			activeBlocks = append(activeBlocks, block)
 		}
 	}
@@ -461,11 +766,15 @@ func (dm *DataManager) updateSessionWindowFiles(blocks []models.SessionBlock) {
 		tracker.InSessionWindow = false
 	}
 
-	// Scan all JSONL files
-	files, err := fileio.DiscoverFiles(dm.dataPath)
-	if err != nil {
-		logging.LogErrorf("Failed to discover files: %v", err)
-		return
+	// Use the tracked files when a watcher maintains them, otherwise scan all JSONL files
+	files := dm.trackedFileList()
+	if files == nil {
+		var err error
+		files, err = fileio.DiscoverFilesInPaths(dm.dataPathList())
+		if err != nil {
+			logging.LogErrorf("Failed to discover files: %v", err)
+			return
+		}
 	}
 
 	for _, file := range files {
@@ -476,8 +785,8 @@ func (dm *DataManager) updateSessionWindowFiles(blocks []models.SessionBlock) {
 
 		// Check if file modification time is within any active session window
 		for _, block := range activeBlocks {
-			// Add 30 minutes buffer after session end to catch late writes
-			if info.ModTime().After(block.StartTime) && info.ModTime().Before(block.EndTime.Add(30*time.Minute)) {
+			// Keep files active for a buffer after session end to catch late writes
+			if info.ModTime().After(block.StartTime) && info.ModTime().Before(block.EndTime.Add(dm.lateWriteBuffer)) {
 				if tracker, exists := dm.activeSessionFiles[file]; exists {
 					tracker.InSessionWindow = true
 					tracker.LastModTime = info.ModTime()
@@ -513,9 +822,12 @@ func (dm *DataManager) startCacheUpdater(ctx context.Context) {
 		return // Already running
 	}
 
-	dm.cacheUpdateTicker = time.NewTicker(1 * time.Minute)
-	dm.cacheUpdateStop = make(chan struct{})
+	ticker := time.NewTicker(1 * time.Minute)
+	stop := make(chan struct{})
+	dm.cacheUpdateTicker = ticker
+	dm.cacheUpdateStop = stop
 
+	// The goroutine keeps its own references since stopping clears the fields
 	go func() {
 		logging.LogInfo("Cache updater started")
 		for {
@@ -523,10 +835,10 @@ func (dm *DataManager) startCacheUpdater(ctx context.Context) {
 			case <-ctx.Done():
 				logging.LogInfo("Cache updater stopped (context cancelled)")
 				return
-			case <-dm.cacheUpdateStop:
+			case <-stop:
 				logging.LogInfo("Cache updater stopped")
 				return
-			case <-dm.cacheUpdateTicker.C:
+			case <-ticker.C:
 				dm.updateSessionWindowCaches()
 			}
 		}
@@ -585,7 +897,10 @@ func (dm *DataManager) updateFileCache(filePath string) error {
 		DataPath:            filePath,
 		CacheStore:          dm.cacheStore,
 		EnableDeduplication: dm.enableDeduplication,
+		DedupIndex:          dm.dedupIndex,
 		PricingProvider:     dm.pricingProvider,
+		Validator:           dm.validator,
+		Concurrency:         dm.concurrency,
 	}
 
 	// This will automatically update the cache since we removed IsWatchMode
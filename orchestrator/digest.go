@@ -0,0 +1,168 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/penwyp/claudecat/config"
+	"github.com/penwyp/claudecat/logging"
+	"github.com/penwyp/claudecat/models"
+	"github.com/penwyp/claudecat/notify"
+	"github.com/penwyp/claudecat/output"
+)
+
+// digestTimeout bounds posting a single digest
+const digestTimeout = 30 * time.Second
+
+// DigestScheduler decides when usage digests are due and posts them to a chat webhook.
+// Daily digests cover the previous calendar day and are posted once the configured time of
+// day has passed; session digests are posted when the active session ends.
+//
+// Scheduling state is kept in memory only: a daily digest whose posting time passed before
+// the monitor started is skipped rather than posted on every restart.
+type DigestScheduler struct {
+	schedule  string
+	hour      int
+	minute    int
+	location  *time.Location
+	topN      int
+	formatter *output.DigestFormatter
+	webhook   *notify.WebhookNotifier
+
+	mu            sync.Mutex
+	lastDay       time.Time // Midnight of the last day a daily digest was due
+	activeSession string    // ID of the session seen active at the previous check
+}
+
+// NewDigestScheduler creates a scheduler from the digest configuration, or returns nil when
+// digests are not configured. now determines which daily digest is the first one due.
+func NewDigestScheduler(cfg config.DigestConfig, location *time.Location, timeFormat string, now time.Time) (*DigestScheduler, error) {
+	if cfg.WebhookURL == "" {
+		return nil, nil
+	}
+	if location == nil {
+		location = time.Local
+	}
+
+	format, err := cfg.ResolvedFormat()
+	if err != nil {
+		return nil, err
+	}
+	formatter, err := output.NewDigestFormatter(format, location, timeFormat)
+	if err != nil {
+		return nil, err
+	}
+	webhook, err := notify.NewWebhookNotifier(cfg.WebhookURL, cfg.Headers, "")
+	if err != nil {
+		return nil, err
+	}
+
+	s := &DigestScheduler{
+		schedule:  cfg.Schedule,
+		location:  location,
+		topN:      cfg.TopN,
+		formatter: formatter,
+		webhook:   webhook,
+	}
+	if cfg.Schedule == config.DigestDaily {
+		offset, err := cfg.TimeOfDay()
+		if err != nil {
+			return nil, err
+		}
+		s.hour, s.minute = int(offset.Hours()), int(offset.Minutes())%60
+
+		// Today's digest counts as handled if its time has already passed
+		today := s.midnight(now)
+		s.lastDay = today.AddDate(0, 0, -1)
+		if !now.Before(s.postingTime(today)) {
+			s.lastDay = today
+		}
+	}
+	return s, nil
+}
+
+// Due returns the digests that became due since the previous call
+func (s *DigestScheduler) Due(blocks []models.SessionBlock, now time.Time) []output.Digest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.schedule == config.DigestSession {
+		return s.dueSessions(blocks)
+	}
+	return s.dueDaily(blocks, now)
+}
+
+// dueDaily returns yesterday's digest once today's posting time has passed
+func (s *DigestScheduler) dueDaily(blocks []models.SessionBlock, now time.Time) []output.Digest {
+	today := s.midnight(now)
+	if !today.After(s.lastDay) || now.Before(s.postingTime(today)) {
+		return nil
+	}
+	s.lastDay = today
+
+	yesterday := today.AddDate(0, 0, -1)
+	title := fmt.Sprintf("Claude usage for %s", yesterday.Format("Jan 2"))
+	return []output.Digest{output.NewDigest(title, blocks, yesterday, today, s.topN)}
+}
+
+// dueSessions returns the digest of the previously active session once it has ended
+func (s *DigestScheduler) dueSessions(blocks []models.SessionBlock) []output.Digest {
+	active := ""
+	for _, block := range blocks {
+		if block.IsActive && !block.IsGap {
+			active = block.ID
+			break
+		}
+	}
+
+	ended := s.activeSession
+	s.activeSession = active
+	if ended == "" || ended == active {
+		return nil
+	}
+
+	for _, block := range blocks {
+		if block.ID != ended {
+			continue
+		}
+		end := block.EndTime
+		if block.ActualEndTime != nil {
+			end = *block.ActualEndTime
+		}
+		digest := output.NewDigest("Claude session ended", []models.SessionBlock{block}, block.StartTime, block.EndTime, s.topN)
+		digest.End = end
+		return []output.Digest{digest}
+	}
+	return nil
+}
+
+// Post renders and sends digests, logging failures
+func (s *DigestScheduler) Post(ctx context.Context, digests []output.Digest) {
+	for _, digest := range digests {
+		body, err := s.formatter.Format(digest)
+		if err != nil {
+			logging.LogWarnf("Failed to render usage digest: %v", err)
+			continue
+		}
+
+		postCtx, cancel := context.WithTimeout(ctx, digestTimeout)
+		err = s.webhook.Post(postCtx, body)
+		cancel()
+		if err != nil {
+			logging.LogWarnf("Failed to post usage digest: %v", err)
+			continue
+		}
+		logging.LogInfof("Posted usage digest %q", digest.Title)
+	}
+}
+
+func (s *DigestScheduler) midnight(t time.Time) time.Time {
+	t = t.In(s.location)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, s.location)
+}
+
+func (s *DigestScheduler) postingTime(day time.Time) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), s.hour, s.minute, 0, 0, s.location)
+}
@@ -0,0 +1,107 @@
+package orchestrator
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/penwyp/claudecat/config"
+	"github.com/penwyp/claudecat/logging"
+	"github.com/penwyp/claudecat/models"
+	"github.com/penwyp/claudecat/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestDigestScheduler(t *testing.T, schedule string, now time.Time) *DigestScheduler {
+	t.Helper()
+	cfg := config.DefaultConfig().Digest
+	cfg.WebhookURL = "https://hooks.slack.com/services/T0/B0/x"
+	cfg.Schedule = schedule
+	scheduler, err := NewDigestScheduler(cfg, time.UTC, "24h", now)
+	require.NoError(t, err)
+	require.NotNil(t, scheduler)
+	return scheduler
+}
+
+func TestNewDigestScheduler_Disabled(t *testing.T) {
+	scheduler, err := NewDigestScheduler(config.DefaultConfig().Digest, time.UTC, "", time.Now())
+	assert.NoError(t, err)
+	assert.Nil(t, scheduler)
+}
+
+func TestDigestScheduler_Daily(t *testing.T) {
+	day := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	blocks := []models.SessionBlock{{ID: "s", Entries: []models.UsageEntry{
+		{Timestamp: day.Add(-2 * time.Hour), TotalTokens: 100},
+		{Timestamp: day.Add(time.Hour), TotalTokens: 50},
+	}}}
+
+	// Started before today's posting time: yesterday's digest is posted at 09:00
+	scheduler := newTestDigestScheduler(t, config.DigestDaily, day.Add(8*time.Hour))
+	assert.Empty(t, scheduler.Due(blocks, day.Add(8*time.Hour+59*time.Minute)))
+
+	digests := scheduler.Due(blocks, day.Add(9*time.Hour))
+	require.Len(t, digests, 1)
+	assert.Equal(t, "Claude usage for Mar 9", digests[0].Title)
+	assert.Equal(t, day.AddDate(0, 0, -1), digests[0].Start)
+	assert.Equal(t, 100, digests[0].TotalTokens)
+	assert.Empty(t, scheduler.Due(blocks, day.Add(12*time.Hour)), "posted once per day")
+
+	next := scheduler.Due(blocks, day.AddDate(0, 0, 1).Add(10*time.Hour))
+	require.Len(t, next, 1)
+	assert.Equal(t, 50, next[0].TotalTokens)
+
+	// Started after today's posting time: nothing until tomorrow
+	late := newTestDigestScheduler(t, config.DigestDaily, day.Add(10*time.Hour))
+	assert.Empty(t, late.Due(blocks, day.Add(11*time.Hour)))
+}
+
+func TestDigestScheduler_Session(t *testing.T) {
+	start := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	lastEntry := start.Add(90 * time.Minute)
+	session := models.SessionBlock{ID: "first", StartTime: start, EndTime: start.Add(5 * time.Hour), IsActive: true,
+		Entries: []models.UsageEntry{{Timestamp: start.Add(time.Minute), TotalTokens: 1200, CostUSD: 0.3}}}
+	scheduler := newTestDigestScheduler(t, config.DigestSession, start)
+
+	assert.Empty(t, scheduler.Due([]models.SessionBlock{session}, start))
+	assert.Empty(t, scheduler.Due([]models.SessionBlock{session}, start.Add(time.Hour)))
+
+	session.IsActive = false
+	session.ActualEndTime = &lastEntry
+	digests := scheduler.Due([]models.SessionBlock{session}, start.Add(6*time.Hour))
+	require.Len(t, digests, 1)
+	assert.Equal(t, "Claude session ended", digests[0].Title)
+	assert.Equal(t, lastEntry, digests[0].End)
+	assert.Equal(t, 1200, digests[0].TotalTokens)
+	assert.Equal(t, 1, digests[0].Sessions)
+
+	assert.Empty(t, scheduler.Due([]models.SessionBlock{session}, start.Add(7*time.Hour)))
+}
+
+func TestDigestScheduler_Post(t *testing.T) {
+	logging.InitLogger("error", "", true)
+
+	bodies := make(chan []byte, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies <- body
+	}))
+	defer server.Close()
+
+	cfg := config.DefaultConfig().Digest
+	cfg.WebhookURL = server.URL
+	cfg.Format = config.DigestDiscord
+	scheduler, err := NewDigestScheduler(cfg, time.UTC, "", time.Now())
+	require.NoError(t, err)
+
+	scheduler.Post(context.Background(), []output.Digest{{Title: "Claude usage for Mar 9"}})
+
+	var payload map[string]interface{}
+	require.NoError(t, json.Unmarshal(<-bodies, &payload))
+	assert.Contains(t, payload, "embeds")
+}
@@ -0,0 +1,105 @@
+package orchestrator
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/penwyp/claudecat/calculations"
+	"github.com/penwyp/claudecat/events"
+	"github.com/penwyp/claudecat/logging"
+	"github.com/penwyp/claudecat/models"
+)
+
+// recordBlockEvents records the session starts and ends and the limit hits of the blocks
+// that aren't in the event log yet
+func (mo *MonitoringOrchestrator) recordBlockEvents(blocks []models.SessionBlock) {
+	if mo.eventLog == nil {
+		return
+	}
+	recorded, err := mo.eventLog.Record(events.FromBlocks(blocks))
+	if err != nil {
+		logging.LogWarnf("Failed to record events: %v", err)
+		return
+	}
+	if len(recorded) > 0 {
+		logging.LogDebugf("Recorded %d events in %s", len(recorded), mo.eventLog.Path())
+	}
+}
+
+// recordBudgetEvents records the budget alerts whose budget's limit was reached
+func (mo *MonitoringOrchestrator) recordBudgetEvents(alerts []calculations.BudgetAlert, now time.Time) {
+	if mo.eventLog == nil {
+		return
+	}
+	var breaches []events.Event
+	for _, alert := range alerts {
+		if !alert.Exceeded() {
+			continue
+		}
+		breaches = append(breaches, events.Event{
+			Time:    now,
+			Type:    events.BudgetBreach,
+			Message: alert.Message(),
+			Data: map[string]interface{}{
+				"budget":  alert.Status.Budget.Name,
+				"period":  alert.Status.Budget.Period,
+				"metric":  alert.Metric,
+				"percent": alert.Percent,
+			},
+		})
+	}
+	if err := mo.eventLog.Append(breaches...); err != nil {
+		logging.LogWarnf("Failed to record budget events: %v", err)
+	}
+}
+
+// recordAnomalyEvent records a burn rate spike
+func (mo *MonitoringOrchestrator) recordAnomalyEvent(anomaly calculations.Anomaly) {
+	if mo.eventLog == nil {
+		return
+	}
+	event := events.Event{
+		Time:      anomaly.DetectedAt,
+		Type:      events.Anomaly,
+		SessionID: anomaly.BlockID,
+		Message:   anomaly.Message(),
+		Data: map[string]interface{}{
+			"tokens_per_minute": anomaly.TokensPerMinute,
+			"baseline_rate":     anomaly.BaselineRate,
+			"factor":            anomaly.Factor,
+		},
+	}
+	if err := mo.eventLog.Append(event); err != nil {
+		logging.LogWarnf("Failed to record anomaly event: %v", err)
+	}
+}
+
+// recordErrorEvent records a failed refresh. Only the first of consecutive failures with
+// the same error is recorded, and finding no usage yet isn't an error.
+func (mo *MonitoringOrchestrator) recordErrorEvent(err error, now time.Time) {
+	if mo.eventLog == nil {
+		return
+	}
+
+	mo.healthMu.Lock()
+	message := ""
+	if err != nil && !errors.Is(err, ErrNoUsageEntries) {
+		message = err.Error()
+	}
+	repeated := message == mo.lastEventError
+	mo.lastEventError = message
+	mo.healthMu.Unlock()
+	if message == "" || repeated {
+		return
+	}
+
+	event := events.Event{
+		Time:    now,
+		Type:    events.Error,
+		Message: fmt.Sprintf("Refresh failed: %s", message),
+	}
+	if err := mo.eventLog.Append(event); err != nil {
+		logging.LogWarnf("Failed to record error event: %v", err)
+	}
+}
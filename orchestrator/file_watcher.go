@@ -0,0 +1,262 @@
+package orchestrator
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/penwyp/claudecat/fileio"
+	"github.com/penwyp/claudecat/logging"
+)
+
+// FileChangeOp describes what happened to a watched file
+type FileChangeOp int
+
+const (
+	FileWritten FileChangeOp = iota // Existing file was appended to or rewritten
+	FileCreated                     // New file appeared (including the target of a rename)
+	FileRemoved                     // File was deleted or renamed away
+)
+
+// String returns the string representation of the operation
+func (op FileChangeOp) String() string {
+	switch op {
+	case FileCreated:
+		return "created"
+	case FileRemoved:
+		return "removed"
+	default:
+		return "written"
+	}
+}
+
+// FileChange is a single JSONL file change reported by the FileWatcher
+type FileChange struct {
+	Path string
+	Op   FileChangeOp
+}
+
+// FileWatcher watches data paths recursively for JSONL writes, creates and renames using
+// fsnotify. Events are coalesced per file and delivered in batches after a quiet period,
+// so a burst of appends to the same file results in a single change.
+type FileWatcher struct {
+	paths    []string
+	delay    time.Duration
+	onChange func([]FileChange)
+	watcher  *fsnotify.Watcher
+	stopCh   chan struct{}
+	doneCh   chan struct{} // Closed when event processing ends
+	stopOnce sync.Once
+	stopErr  error
+
+	pending map[string]FileChangeOp
+	timer   *time.Timer
+	mu      sync.Mutex
+	started atomic.Bool
+}
+
+// NewFileWatcher creates a watcher for the given data paths. onChange is called with each
+// batch of changes once no new events have arrived for delay.
+func NewFileWatcher(paths []string, delay time.Duration, onChange func([]FileChange)) (*FileWatcher, error) {
+	if delay <= 0 {
+		delay = 100 * time.Millisecond
+	}
+
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file watcher: %w", err)
+	}
+
+	return &FileWatcher{
+		paths:    paths,
+		delay:    delay,
+		onChange: onChange,
+		watcher:  fsWatcher,
+		stopCh:   make(chan struct{}),
+		doneCh:   make(chan struct{}),
+		pending:  make(map[string]FileChangeOp),
+	}, nil
+}
+
+// Start adds watches for every directory under the data paths and begins processing events
+func (w *FileWatcher) Start() error {
+	watched := 0
+	for _, path := range w.paths {
+		if _, err := os.Stat(path); err != nil {
+			logging.LogDebugf("Not watching missing data path %s: %v", path, err)
+			continue
+		}
+		if err := w.addTree(path); err != nil {
+			return fmt.Errorf("failed to watch %s: %w", path, err)
+		}
+		watched++
+	}
+	if watched == 0 {
+		return fmt.Errorf("no data paths to watch")
+	}
+
+	w.started.Store(true)
+	go w.processEvents()
+	return nil
+}
+
+// Alive reports whether the watcher has started and is still processing events
+func (w *FileWatcher) Alive() bool {
+	select {
+	case <-w.doneCh:
+		return false
+	case <-w.stopCh:
+		return false
+	default:
+		return w.started.Load()
+	}
+}
+
+// Stop stops watching and discards pending changes. Later calls return the result of the first.
+func (w *FileWatcher) Stop() error {
+	w.stopOnce.Do(func() {
+		close(w.stopCh)
+
+		w.mu.Lock()
+		if w.timer != nil {
+			w.timer.Stop()
+			w.timer = nil
+		}
+		w.pending = make(map[string]FileChangeOp)
+		w.mu.Unlock()
+
+		w.stopErr = w.watcher.Close()
+	})
+	return w.stopErr
+}
+
+// addTree watches root and every directory below it. fsnotify is not recursive, so each
+// directory needs its own watch.
+func (w *FileWatcher) addTree(root string) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			logging.LogDebugf("Skipping %s while adding watches: %v", path, err)
+			return nil
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if err := w.watcher.Add(path); err != nil {
+			return fmt.Errorf("failed to watch directory %s: %w", path, err)
+		}
+		return nil
+	})
+}
+
+// processEvents processes file system events until the watcher is stopped
+func (w *FileWatcher) processEvents() {
+	defer close(w.doneCh)
+	for {
+		select {
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			w.handleEvent(event)
+
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+			logging.LogWarnf("Data file watcher error: %v", err)
+
+		case <-w.stopCh:
+			return
+		}
+	}
+}
+
+// handleEvent translates a single fsnotify event into pending file changes
+func (w *FileWatcher) handleEvent(event fsnotify.Event) {
+	if event.Has(fsnotify.Create) {
+		if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+			// New project directory: watch it and pick up files written before the watch existed
+			if err := w.addTree(event.Name); err != nil {
+				logging.LogWarnf("Failed to watch new directory %s: %v", event.Name, err)
+			}
+			w.queueTree(event.Name)
+			return
+		}
+	}
+
+	if !isJSONLFile(event.Name) {
+		return
+	}
+
+	switch {
+	case event.Has(fsnotify.Remove), event.Has(fsnotify.Rename):
+		w.queue(event.Name, FileRemoved)
+	case event.Has(fsnotify.Create):
+		w.queue(event.Name, FileCreated)
+	case event.Has(fsnotify.Write):
+		w.queue(event.Name, FileWritten)
+	}
+}
+
+// queueTree queues every JSONL file below root as created
+func (w *FileWatcher) queueTree(root string) {
+	_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() && isJSONLFile(path) {
+			w.queue(path, FileCreated)
+		}
+		return nil
+	})
+}
+
+// queue records a change and restarts the quiet-period timer
+func (w *FileWatcher) queue(path string, op FileChangeOp) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	// A file created and then written within one batch is still new
+	if existing, ok := w.pending[path]; ok && existing == FileCreated && op == FileWritten {
+		op = FileCreated
+	}
+	w.pending[path] = op
+
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+	w.timer = time.AfterFunc(w.delay, w.flush)
+}
+
+// flush delivers the pending changes to the callback
+func (w *FileWatcher) flush() {
+	w.mu.Lock()
+	if len(w.pending) == 0 {
+		w.mu.Unlock()
+		return
+	}
+	changes := make([]FileChange, 0, len(w.pending))
+	for path, op := range w.pending {
+		changes = append(changes, FileChange{Path: path, Op: op})
+	}
+	w.pending = make(map[string]FileChangeOp)
+	w.timer = nil
+	w.mu.Unlock()
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+
+	if w.onChange != nil {
+		w.onChange(changes)
+	}
+}
+
+// isJSONLFile reports whether path names a usage log: a JSONL file, compressed or not, or
+// the log of another source
+func isJSONLFile(path string) bool {
+	return fileio.IsSourceFile(path)
+}
@@ -0,0 +1,107 @@
+package orchestrator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func waitForChanges(t *testing.T, ch <-chan []FileChange) []FileChange {
+	t.Helper()
+	select {
+	case changes := <-ch:
+		return changes
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for file changes")
+		return nil
+	}
+}
+
+func TestFileWatcher_ReportsJSONLChanges(t *testing.T) {
+	dir := t.TempDir()
+	projectDir := filepath.Join(dir, "project-a")
+	require.NoError(t, os.MkdirAll(projectDir, 0755))
+
+	changesCh := make(chan []FileChange, 10)
+	watcher, err := NewFileWatcher([]string{dir}, 50*time.Millisecond, func(changes []FileChange) {
+		changesCh <- changes
+	})
+	require.NoError(t, err)
+	assert.False(t, watcher.Alive())
+	require.NoError(t, watcher.Start())
+	defer watcher.Stop()
+	assert.True(t, watcher.Alive())
+
+	// New file, written several times, is reported once as created
+	file := filepath.Join(projectDir, "session.jsonl")
+	require.NoError(t, os.WriteFile(file, []byte("{}\n"), 0644))
+	f, err := os.OpenFile(file, os.O_APPEND|os.O_WRONLY, 0644)
+	require.NoError(t, err)
+	_, err = f.WriteString("{}\n")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	changes := waitForChanges(t, changesCh)
+	assert.Equal(t, []FileChange{{Path: file, Op: FileCreated}}, changes)
+
+	// Appending later is a write
+	f, err = os.OpenFile(file, os.O_APPEND|os.O_WRONLY, 0644)
+	require.NoError(t, err)
+	_, err = f.WriteString("{}\n")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	changes = waitForChanges(t, changesCh)
+	assert.Equal(t, []FileChange{{Path: file, Op: FileWritten}}, changes)
+
+	// Files in a new directory are picked up and other files ignored
+	newDir := filepath.Join(dir, "project-b")
+	require.NoError(t, os.MkdirAll(newDir, 0755))
+	newFile := filepath.Join(newDir, "other.jsonl")
+	require.NoError(t, os.WriteFile(newFile, []byte("{}\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(newDir, "notes.txt"), []byte("x"), 0644))
+
+	changes = waitForChanges(t, changesCh)
+	assert.Equal(t, []FileChange{{Path: newFile, Op: FileCreated}}, changes)
+
+	// Removal
+	require.NoError(t, os.Remove(file))
+	changes = waitForChanges(t, changesCh)
+	assert.Equal(t, []FileChange{{Path: file, Op: FileRemoved}}, changes)
+}
+
+func TestFileWatcher_StopTwice(t *testing.T) {
+	watcher, err := NewFileWatcher([]string{t.TempDir()}, 50*time.Millisecond, func([]FileChange) {})
+	require.NoError(t, err)
+	require.NoError(t, watcher.Start())
+
+	require.NoError(t, watcher.Stop())
+	assert.NotPanics(t, func() { assert.NoError(t, watcher.Stop()) })
+	assert.False(t, watcher.Alive())
+}
+
+func TestDataManager_ApplyFileChanges(t *testing.T) {
+	dm := NewDataManager(24, []string{"/data"})
+	dm.TrackFiles([]string{"/data/a.jsonl", "/data/b.jsonl"})
+
+	dm.ApplyFileChanges([]FileChange{
+		{Path: "/data/b.jsonl", Op: FileRemoved},
+		{Path: "/data/c.jsonl", Op: FileCreated},
+		{Path: "/data/a.jsonl", Op: FileWritten},
+	})
+
+	assert.Equal(t, []string{"/data/a.jsonl", "/data/c.jsonl"}, dm.trackedFileList())
+	select {
+	case <-dm.Changes():
+	default:
+		t.Fatal("expected a change notification")
+	}
+
+	dm.mu.RLock()
+	assert.True(t, dm.dirty)
+	dm.mu.RUnlock()
+}
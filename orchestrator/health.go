@@ -0,0 +1,211 @@
+package orchestrator
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// HealthStatus is the outcome of a health check, ordered from best to worst
+type HealthStatus string
+
+const (
+	HealthOK        HealthStatus = "ok"
+	HealthDegraded  HealthStatus = "degraded"
+	HealthUnhealthy HealthStatus = "unhealthy"
+)
+
+// severity orders statuses so the worst check determines the overall status
+func (s HealthStatus) severity() int {
+	switch s {
+	case HealthOK:
+		return 0
+	case HealthDegraded:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// unhealthyAfterErrors is how many fetches in a row must fail before monitoring is unhealthy
+const unhealthyAfterErrors = 3
+
+// minStaleAfter is the shortest time without a successful fetch before data counts as stale
+const minStaleAfter = time.Minute
+
+// HealthCheck is the result of a single health check
+type HealthCheck struct {
+	Name    string       `json:"name"`
+	Status  HealthStatus `json:"status"`
+	Message string       `json:"message"`
+}
+
+// Health summarizes the orchestrator state so headless deployments can be monitored
+type Health struct {
+	Status              HealthStatus  `json:"status"`
+	Ready               bool          `json:"ready"` // Usage data has been loaded
+	Monitoring          bool          `json:"monitoring"`
+	DataPaths           []string      `json:"data_paths"`
+	LastSuccessfulFetch *time.Time    `json:"last_successful_fetch,omitempty"`
+	LastFetchAgeSeconds float64       `json:"last_fetch_age_seconds"` // -1 before the first successful fetch
+	CacheAgeSeconds     float64       `json:"cache_age_seconds"`      // -1 when nothing is cached
+	SummaryCache        bool          `json:"summary_cache"`          // File summaries are cached between runs
+	WatcherAlive        bool          `json:"watcher_alive"`
+	Suspended           bool          `json:"suspended"`          // Refreshes are suspended until data files change
+	FetchErrors         int           `json:"fetch_errors"`       // Failed fetches since start
+	ConsecutiveErrors   int           `json:"consecutive_errors"` // Failed fetches since the last success
+	LastError           string        `json:"last_error,omitempty"`
+	Checks              []HealthCheck `json:"checks"`
+	CheckedAt           time.Time     `json:"checked_at"`
+}
+
+// fetchStats counts fetch outcomes for health reporting
+type fetchStats struct {
+	lastSuccess       time.Time // Includes refreshes served from unchanged cached data
+	errors            int
+	consecutiveErrors int
+	lastError         error
+}
+
+// recordFetch records the outcome of a fetch
+func (mo *MonitoringOrchestrator) recordFetch(err error) {
+	mo.healthMu.Lock()
+	defer mo.healthMu.Unlock()
+	if err == nil {
+		mo.fetchStats.lastSuccess = time.Now()
+		mo.fetchStats.consecutiveErrors = 0
+		mo.fetchStats.lastError = nil
+		return
+	}
+	mo.fetchStats.errors++
+	mo.fetchStats.consecutiveErrors++
+	mo.fetchStats.lastError = err
+}
+
+// GetHealth runs the health checks against the current monitoring state
+func (mo *MonitoringOrchestrator) GetHealth() Health {
+	now := time.Now()
+
+	mo.mu.RLock()
+	monitoring := mo.monitoring
+	dataPaths := append([]string(nil), mo.dataPaths...)
+	updateInterval := mo.updateInterval
+	if mo.refresh != nil && mo.refresh.current > updateInterval {
+		updateInterval = mo.refresh.current // Backed off while idle
+	}
+	watching := mo.config == nil || mo.config.Data.AutoDiscover
+	watcherAlive := mo.fileWatcher != nil && mo.fileWatcher.Alive()
+	ready := mo.lastValidData != nil
+	suspended := mo.suspended
+	suspendedAt := mo.suspendedAt
+	mo.mu.RUnlock()
+
+	mo.healthMu.Lock()
+	stats := mo.fetchStats
+	mo.healthMu.Unlock()
+
+	health := Health{
+		Monitoring:          monitoring,
+		DataPaths:           dataPaths,
+		Ready:               ready,
+		LastFetchAgeSeconds: -1,
+		CacheAgeSeconds:     mo.dataManager.GetCacheAge(),
+		SummaryCache:        mo.dataManager.HasCacheStore(),
+		WatcherAlive:        watcherAlive,
+		Suspended:           suspended,
+		FetchErrors:         stats.errors,
+		ConsecutiveErrors:   stats.consecutiveErrors,
+		CheckedAt:           now,
+	}
+	if stats.lastError != nil {
+		health.LastError = stats.lastError.Error()
+	}
+	if last := stats.lastSuccess; !last.IsZero() {
+		health.LastSuccessfulFetch = &last
+		health.LastFetchAgeSeconds = now.Sub(last).Seconds()
+	}
+
+	staleAfter := 3 * updateInterval
+	if staleAfter < minStaleAfter {
+		staleAfter = minStaleAfter
+	}
+	// Suspended data is as fresh as when the files went idle
+	if suspended && stats.lastSuccess.Before(suspendedAt) {
+		staleAfter += now.Sub(suspendedAt)
+	}
+
+	health.Checks = []HealthCheck{
+		checkMonitoring(monitoring),
+		checkData(health, stats, staleAfter),
+		checkFetchErrors(stats),
+		checkWatcher(monitoring, watching, watcherAlive),
+	}
+	health.Status = HealthOK
+	for _, check := range health.Checks {
+		if check.Status.severity() > health.Status.severity() {
+			health.Status = check.Status
+		}
+	}
+	return health
+}
+
+func checkMonitoring(monitoring bool) HealthCheck {
+	if !monitoring {
+		return HealthCheck{Name: "monitoring", Status: HealthUnhealthy, Message: "monitoring is not running"}
+	}
+	return HealthCheck{Name: "monitoring", Status: HealthOK, Message: "monitoring is running"}
+}
+
+func checkData(health Health, stats fetchStats, staleAfter time.Duration) HealthCheck {
+	check := HealthCheck{Name: "data"}
+	switch {
+	case health.LastFetchAgeSeconds < 0 && errors.Is(stats.lastError, ErrNoUsageEntries):
+		check.Status, check.Message = HealthDegraded, "no usage entries found yet"
+	case health.LastFetchAgeSeconds < 0:
+		check.Status, check.Message = HealthDegraded, "initial load has not completed"
+	case health.Suspended && health.LastFetchAgeSeconds <= staleAfter.Seconds():
+		check.Status = HealthOK
+		check.Message = fmt.Sprintf("refreshes suspended while idle, last successful fetch %s ago",
+			time.Duration(health.LastFetchAgeSeconds*float64(time.Second)).Round(time.Second))
+	case health.LastFetchAgeSeconds > staleAfter.Seconds():
+		check.Status = HealthDegraded
+		check.Message = fmt.Sprintf("last successful fetch %s ago, stale after %s",
+			time.Duration(health.LastFetchAgeSeconds*float64(time.Second)).Round(time.Second), staleAfter)
+	default:
+		check.Status = HealthOK
+		check.Message = fmt.Sprintf("last successful fetch %s ago",
+			time.Duration(health.LastFetchAgeSeconds*float64(time.Second)).Round(time.Second))
+	}
+	return check
+}
+
+func checkFetchErrors(stats fetchStats) HealthCheck {
+	check := HealthCheck{Name: "fetch_errors", Status: HealthOK, Message: "last fetch succeeded"}
+	switch {
+	case stats.consecutiveErrors >= unhealthyAfterErrors:
+		check.Status = HealthUnhealthy
+	case stats.consecutiveErrors > 0:
+		check.Status = HealthDegraded
+	default:
+		return check
+	}
+	// Finding no usage yet is expected before Claude Code has been used
+	if errors.Is(stats.lastError, ErrNoUsageEntries) {
+		check.Status = HealthDegraded
+	}
+	check.Message = fmt.Sprintf("%d fetches in a row failed: %v", stats.consecutiveErrors, stats.lastError)
+	return check
+}
+
+func checkWatcher(monitoring, watching, alive bool) HealthCheck {
+	switch {
+	case !watching:
+		return HealthCheck{Name: "watcher", Status: HealthOK, Message: "file watching disabled, using periodic scans"}
+	case alive:
+		return HealthCheck{Name: "watcher", Status: HealthOK, Message: "watching data files"}
+	case !monitoring:
+		return HealthCheck{Name: "watcher", Status: HealthDegraded, Message: "not watching data files"}
+	default:
+		return HealthCheck{Name: "watcher", Status: HealthDegraded, Message: "file watching unavailable, using periodic scans"}
+	}
+}
@@ -0,0 +1,83 @@
+package orchestrator
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/penwyp/claudecat/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func healthCheck(t *testing.T, health Health, name string) HealthCheck {
+	t.Helper()
+	for _, check := range health.Checks {
+		if check.Name == name {
+			return check
+		}
+	}
+	require.Failf(t, "missing health check", "%s", name)
+	return HealthCheck{}
+}
+
+func TestGetHealth(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Data.AutoDiscover = false
+	mo := &MonitoringOrchestrator{
+		updateInterval: 10 * time.Second,
+		dataPaths:      []string{"/data"},
+		config:         cfg,
+		dataManager:    NewDataManager(24, []string{"/data"}),
+	}
+
+	health := mo.GetHealth()
+	assert.Equal(t, HealthUnhealthy, health.Status, "not monitoring")
+	assert.False(t, health.Ready)
+	assert.Equal(t, -1.0, health.LastFetchAgeSeconds)
+	assert.Equal(t, []string{"/data"}, health.DataPaths)
+
+	mo.monitoring = true
+
+	// No usage yet is degraded however often it repeats
+	for i := 0; i < unhealthyAfterErrors; i++ {
+		mo.recordFetch(fmt.Errorf("failed to fetch data: %w", ErrNoUsageEntries))
+	}
+	health = mo.GetHealth()
+	assert.Equal(t, HealthDegraded, health.Status)
+	assert.Equal(t, "no usage entries found yet", healthCheck(t, health, "data").Message)
+	assert.Equal(t, unhealthyAfterErrors, health.ConsecutiveErrors)
+
+	// A successful fetch recovers
+	mo.recordFetch(nil)
+	health = mo.GetHealth()
+	assert.Equal(t, HealthOK, health.Status)
+	assert.Equal(t, unhealthyAfterErrors, health.FetchErrors)
+	assert.Zero(t, health.ConsecutiveErrors)
+	assert.Empty(t, health.LastError)
+
+	// Repeated failures are unhealthy
+	for i := 0; i < unhealthyAfterErrors; i++ {
+		mo.recordFetch(errors.New("permission denied"))
+	}
+	health = mo.GetHealth()
+	assert.Equal(t, HealthUnhealthy, health.Status)
+	assert.Equal(t, HealthUnhealthy, healthCheck(t, health, "fetch_errors").Status)
+	assert.Equal(t, "permission denied", health.LastError)
+
+	// Stale data is degraded
+	mo.recordFetch(nil)
+	mo.fetchStats.lastSuccess = time.Now().Add(-2 * time.Minute)
+	health = mo.GetHealth()
+	assert.Equal(t, HealthDegraded, health.Status)
+	assert.Contains(t, healthCheck(t, health, "data").Message, "stale after 1m0s")
+
+	// Without a watcher, file watching counts as degraded when enabled
+	mo.recordFetch(nil)
+	cfg.Data.AutoDiscover = true
+	health = mo.GetHealth()
+	assert.Equal(t, HealthDegraded, health.Status)
+	assert.False(t, health.WatcherAlive)
+	assert.Equal(t, HealthDegraded, healthCheck(t, health, "watcher").Status)
+}
@@ -0,0 +1,142 @@
+package orchestrator
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/penwyp/claudecat/hooks"
+	"github.com/penwyp/claudecat/models"
+	"github.com/penwyp/claudecat/notify"
+)
+
+// hookTracker remembers what the pipeline hooks have seen, so each refresh only reports
+// what is new. The first refresh sets the baseline: entries and blocks that exist when
+// monitoring starts are not reported.
+type hookTracker struct {
+	mu        sync.Mutex
+	primed    bool
+	lastEntry time.Time       // Newest entry timestamp reported so far
+	blocks    map[string]bool // IDs of the blocks reported so far
+	pending   []hooks.Event   // Session events collected during the refresh
+}
+
+// RegisterHook adds a hook for the given pipeline events; no events registers it for all
+// of them. Hooks run in the background after each refresh.
+func (mo *MonitoringOrchestrator) RegisterHook(hook hooks.Hook, events ...hooks.EventType) {
+	mo.hooks.Register(hook, events...)
+	if mo.hooks.Wants(hooks.EventThresholdCrossed) {
+		mo.ensureLimitWarner()
+	}
+}
+
+// ensureLimitWarner creates the limit warner, which otherwise only exists for webhooks
+func (mo *MonitoringOrchestrator) ensureLimitWarner() {
+	mo.mu.Lock()
+	defer mo.mu.Unlock()
+	if mo.limitWarner == nil {
+		mo.limitWarner = notify.NewLimitWarner(mo.config.Limits.WarnBefore)
+	}
+}
+
+// onSessionHook queues session start and end events for the hooks. It runs while the
+// session monitor holds its lock, so the events are dispatched after the update.
+func (mo *MonitoringOrchestrator) onSessionHook(eventType, sessionID string, sessionData interface{}) {
+	event := hooks.EventType(eventType)
+	if event != hooks.EventSessionStart && event != hooks.EventSessionEnd {
+		return
+	}
+	if !mo.hooks.Wants(event) {
+		return
+	}
+
+	mo.hookState.mu.Lock()
+	defer mo.hookState.mu.Unlock()
+	mo.hookState.pending = append(mo.hookState.pending, hooks.Event{
+		Type:      event,
+		Timestamp: time.Now(),
+		SessionID: sessionID,
+	})
+}
+
+// pipelineEvents returns the events of a refresh: the entries newer than any reported
+// before, the blocks not seen before and the queued session changes
+func (mo *MonitoringOrchestrator) pipelineEvents(blocks []models.SessionBlock, now time.Time) []hooks.Event {
+	state := &mo.hookState
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	var events []hooks.Event
+	wantEntries := mo.hooks.Wants(hooks.EventEntriesLoaded)
+	wantBlocks := mo.hooks.Wants(hooks.EventBlockCreated)
+
+	var entries []models.UsageEntry
+	newest := state.lastEntry
+	for i := range blocks {
+		block := &blocks[i]
+		if block.IsGap {
+			continue
+		}
+		for _, entry := range block.Entries {
+			if entry.Timestamp.After(state.lastEntry) {
+				if state.primed && wantEntries {
+					entries = append(entries, entry)
+				}
+				if entry.Timestamp.After(newest) {
+					newest = entry.Timestamp
+				}
+			}
+		}
+
+		if state.blocks == nil {
+			state.blocks = make(map[string]bool)
+		}
+		if !state.blocks[block.ID] {
+			state.blocks[block.ID] = true
+			if state.primed && wantBlocks {
+				created := *block
+				created.Entries = nil
+				events = append(events, hooks.Event{Type: hooks.EventBlockCreated, Timestamp: now, Block: &created})
+			}
+		}
+	}
+	state.lastEntry = newest
+	state.primed = true
+
+	if len(entries) > 0 {
+		events = append([]hooks.Event{{Type: hooks.EventEntriesLoaded, Timestamp: now, Entries: entries}}, events...)
+	}
+	events = append(events, state.pending...)
+	state.pending = nil
+	return events
+}
+
+// thresholdEvents returns a threshold_crossed event for each limit, cost or budget
+// notification
+func (mo *MonitoringOrchestrator) thresholdEvents(notifications []notify.Notification) []hooks.Event {
+	if !mo.hooks.Wants(hooks.EventThresholdCrossed) {
+		return nil
+	}
+	var events []hooks.Event
+	for i := range notifications {
+		switch notifications[i].Event {
+		case notify.EventLimitApproaching, notify.EventLimitReached, notify.EventCostAlert, notify.EventBudgetAlert, notify.EventUsageAnomaly:
+			alert := notifications[i]
+			events = append(events, hooks.Event{
+				Type:      hooks.EventThresholdCrossed,
+				Timestamp: alert.Timestamp,
+				SessionID: alert.SessionID,
+				Alert:     &alert,
+			})
+		}
+	}
+	return events
+}
+
+// dispatchHooks delivers events to the hooks in the background
+func (mo *MonitoringOrchestrator) dispatchHooks(events []hooks.Event) {
+	if len(events) == 0 {
+		return
+	}
+	go mo.hooks.Dispatch(context.Background(), events)
+}
@@ -0,0 +1,83 @@
+package orchestrator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/penwyp/claudecat/config"
+	"github.com/penwyp/claudecat/hooks"
+	"github.com/penwyp/claudecat/models"
+	"github.com/penwyp/claudecat/notify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPipelineEvents(t *testing.T) {
+	mo := &MonitoringOrchestrator{config: config.DefaultConfig(), hooks: hooks.NewRegistry()}
+	mo.hooks.Register(hooks.Func("test", nil))
+
+	base := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	entry := func(minutes int) models.UsageEntry {
+		return models.UsageEntry{Timestamp: base.Add(time.Duration(minutes) * time.Minute), TotalTokens: 100}
+	}
+	blocks := []models.SessionBlock{
+		{ID: "a", Entries: []models.UsageEntry{entry(0), entry(10)}},
+	}
+
+	// The first refresh only sets the baseline
+	assert.Empty(t, mo.pipelineEvents(blocks, base))
+
+	// A new entry in the same block and a new block with one entry
+	blocks[0].Entries = append(blocks[0].Entries, entry(20))
+	blocks = append(blocks,
+		models.SessionBlock{ID: "gap", IsGap: true},
+		models.SessionBlock{ID: "b", Entries: []models.UsageEntry{entry(400)}})
+	mo.onSessionHook(string(SessionStart), "b", nil)
+	mo.onSessionHook(string(SessionUpdate), "b", nil)
+
+	events := mo.pipelineEvents(blocks, base)
+	require.Len(t, events, 3)
+	assert.Equal(t, hooks.EventEntriesLoaded, events[0].Type)
+	assert.Len(t, events[0].Entries, 2)
+	assert.Equal(t, hooks.EventBlockCreated, events[1].Type)
+	assert.Equal(t, "b", events[1].Block.ID)
+	assert.Nil(t, events[1].Block.Entries, "blocks are reported without their entries")
+	assert.Equal(t, hooks.EventSessionStart, events[2].Type)
+	assert.Equal(t, "b", events[2].SessionID)
+
+	// Nothing new
+	assert.Empty(t, mo.pipelineEvents(blocks, base))
+}
+
+func TestPipelineEvents_OnlyWantedEvents(t *testing.T) {
+	mo := &MonitoringOrchestrator{config: config.DefaultConfig(), hooks: hooks.NewRegistry()}
+	mo.hooks.Register(hooks.Func("test", nil), hooks.EventBlockCreated)
+
+	mo.pipelineEvents(nil, time.Now())
+	mo.onSessionHook(string(SessionEnd), "a", nil)
+	events := mo.pipelineEvents([]models.SessionBlock{
+		{ID: "a", Entries: []models.UsageEntry{{Timestamp: time.Now()}}},
+	}, time.Now())
+	require.Len(t, events, 1)
+	assert.Equal(t, hooks.EventBlockCreated, events[0].Type)
+}
+
+func TestThresholdEvents(t *testing.T) {
+	mo := &MonitoringOrchestrator{config: config.DefaultConfig(), hooks: hooks.NewRegistry()}
+	notifications := []notify.Notification{
+		{Event: notify.EventSessionStart, SessionID: "a"},
+		{Event: notify.EventLimitApproaching, SessionID: "a", Message: "limit in 20m"},
+		{Event: notify.EventBudgetAlert, Message: "daily budget at 90%"},
+	}
+	assert.Empty(t, mo.thresholdEvents(notifications), "no hook wants threshold events")
+
+	mo.RegisterHook(hooks.Func("test", nil), hooks.EventThresholdCrossed)
+	assert.NotNil(t, mo.limitWarner, "threshold hooks need limit warnings")
+
+	events := mo.thresholdEvents(notifications)
+	require.Len(t, events, 2)
+	assert.Equal(t, hooks.EventThresholdCrossed, events[0].Type)
+	assert.Equal(t, "limit in 20m", events[0].Alert.Message)
+	assert.Equal(t, "a", events[0].SessionID)
+	assert.Equal(t, notify.EventBudgetAlert, events[1].Alert.Event)
+}
@@ -0,0 +1,77 @@
+package orchestrator
+
+import (
+	"time"
+
+	"github.com/penwyp/claudecat/cache"
+	"github.com/penwyp/claudecat/logging"
+)
+
+// idleExpired reports whether monitoring has gone idle long enough to be suspended. Only a
+// live file watcher notices new writes while suspended, so without one nothing suspends.
+func (mo *MonitoringOrchestrator) idleExpired(idle time.Duration) bool {
+	mo.mu.RLock()
+	defer mo.mu.RUnlock()
+	return mo.idleTimeout > 0 && idle >= mo.idleTimeout && !mo.suspended &&
+		mo.fileWatcher != nil && mo.fileWatcher.Alive()
+}
+
+// suspend stops the DataManager background tasks and closes the summary cache, leaving only
+// the file watcher running until the next write
+func (mo *MonitoringOrchestrator) suspend(idle time.Duration) {
+	mo.mu.Lock()
+	defer mo.mu.Unlock()
+	if mo.suspended {
+		return
+	}
+
+	mo.suspended = true
+	mo.suspendedAt = time.Now()
+	mo.dataManager.Stop()
+	if mo.summaryStore != nil {
+		mo.dataManager.swapCacheStore(nil)
+		if err := mo.summaryStore.Close(); err != nil {
+			logging.LogWarnf("Failed to close summary cache while idle: %v", err)
+		}
+	}
+	logging.LogInfof("No data file writes for %s, suspending refreshes until the next write", idle.Round(time.Second))
+}
+
+// resume restarts what suspend stopped. It reports whether monitoring was suspended.
+func (mo *MonitoringOrchestrator) resume() bool {
+	mo.mu.Lock()
+	defer mo.mu.Unlock()
+	if !mo.suspended {
+		return false
+	}
+
+	logging.LogInfof("Resuming refreshes after %s idle", time.Since(mo.suspendedAt).Round(time.Second))
+	mo.resumeLocked()
+	return true
+}
+
+// resumeLocked reopens the summary cache and, while monitoring, restarts the DataManager
+// background tasks. The caller must hold mo.mu.
+func (mo *MonitoringOrchestrator) resumeLocked() {
+	mo.suspended = false
+	if mo.summaryStore != nil {
+		store, err := cache.OpenSummaryStore(mo.summaryBackend)
+		if err != nil {
+			logging.LogErrorf("Failed to reopen %s summary cache, continuing without it: %v", mo.summaryBackend.Backend, err)
+			mo.summaryStore = nil
+		} else {
+			mo.summaryStore = store
+			mo.dataManager.swapCacheStore(store)
+		}
+	}
+	if mo.monitoring {
+		mo.dataManager.Start(mo.stopEvent)
+	}
+}
+
+// IsSuspended reports whether refreshes are suspended while the data files are idle
+func (mo *MonitoringOrchestrator) IsSuspended() bool {
+	mo.mu.RLock()
+	defer mo.mu.RUnlock()
+	return mo.suspended
+}
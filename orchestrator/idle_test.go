@@ -0,0 +1,66 @@
+package orchestrator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/penwyp/claudecat/cache"
+	"github.com/penwyp/claudecat/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdleSuspendAndResume(t *testing.T) {
+	dataDir := t.TempDir()
+	backend := cache.BackendConfig{Backend: cache.BackendFile, Dir: t.TempDir()}
+	store, err := cache.OpenSummaryStore(backend)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	mo := &MonitoringOrchestrator{
+		updateInterval: 10 * time.Second,
+		dataPaths:      []string{dataDir},
+		config:         config.DefaultConfig(),
+		dataManager:    NewDataManager(24, []string{dataDir}),
+		monitoring:     true,
+		stopEvent:      ctx,
+		idleTimeout:    time.Minute,
+		summaryStore:   store,
+		summaryBackend: backend,
+	}
+	mo.dataManager.SetCacheStore(store, mo.config.Data.SummaryCache)
+	defer mo.dataManager.Stop()
+
+	// Nothing would notice new writes without a file watcher
+	assert.False(t, mo.idleExpired(2*time.Minute))
+
+	watcher, err := NewFileWatcher([]string{dataDir}, 0, nil)
+	require.NoError(t, err)
+	require.NoError(t, watcher.Start())
+	defer watcher.Stop()
+	mo.fileWatcher = watcher
+
+	assert.False(t, mo.idleExpired(30*time.Second))
+	require.True(t, mo.idleExpired(2*time.Minute))
+
+	mo.recordFetch(nil)
+	mo.suspend(2 * time.Minute)
+	assert.True(t, mo.IsSuspended())
+	assert.False(t, mo.dataManager.HasCacheStore(), "summary cache is closed while idle")
+	assert.False(t, mo.idleExpired(time.Hour), "already suspended")
+
+	// Data stays healthy while suspended, however old the last fetch is
+	mo.fetchStats.lastSuccess = time.Now().Add(-time.Hour)
+	mo.suspendedAt = time.Now().Add(-59*time.Minute - 30*time.Second)
+	health := mo.GetHealth()
+	assert.True(t, health.Suspended)
+	assert.Equal(t, HealthOK, healthCheck(t, health, "data").Status)
+	assert.Contains(t, healthCheck(t, health, "data").Message, "suspended while idle")
+
+	require.True(t, mo.resume())
+	assert.False(t, mo.IsSuspended())
+	assert.True(t, mo.dataManager.HasCacheStore(), "summary cache is reopened")
+	assert.False(t, mo.resume(), "not suspended")
+}
@@ -0,0 +1,183 @@
+package orchestrator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/penwyp/claudecat/calculations"
+	"github.com/penwyp/claudecat/config"
+	errs "github.com/penwyp/claudecat/errors"
+	"github.com/penwyp/claudecat/influx"
+	"github.com/penwyp/claudecat/logging"
+	"github.com/penwyp/claudecat/models"
+)
+
+// InfluxSink writes usage to InfluxDB after every refresh: a <prefix>_usage point per usage
+// entry at the entry's time, and a <prefix>_session point with the active session's totals
+// and burn rate at the time of the refresh.
+//
+// Which entries were written is kept in memory only, so after a restart the loaded history
+// is written again. InfluxDB overwrites the duplicate points; a file receives them twice.
+type InfluxSink struct {
+	client *influx.Client
+	prefix string
+	tags   map[string]string
+
+	mu      sync.Mutex
+	writing bool                // A write is in progress; refreshes meanwhile are skipped
+	written map[string]struct{} // Keys of the loaded entries already written
+}
+
+// NewInfluxSink creates a sink from the influx configuration, or returns nil when neither a
+// server nor a file is configured
+func NewInfluxSink(cfg config.InfluxConfig) (*InfluxSink, error) {
+	if cfg.URL == "" && cfg.File == "" {
+		return nil, nil
+	}
+
+	file := cfg.File
+	if strings.HasPrefix(file, "~/") {
+		homeDir, _ := os.UserHomeDir()
+		file = filepath.Join(homeDir, file[2:])
+	}
+	client, err := influx.New(influx.Options{
+		URL:       cfg.URL,
+		Token:     cfg.Token,
+		Org:       cfg.Org,
+		Bucket:    cfg.Bucket,
+		Database:  cfg.Database,
+		File:      file,
+		BatchSize: cfg.BatchSize,
+		Retry:     errs.NewRetryPolicy(cfg.Retry),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &InfluxSink{
+		client:  client,
+		prefix:  cfg.Prefix,
+		tags:    cfg.Tags,
+		written: make(map[string]struct{}),
+	}, nil
+}
+
+// Write writes the entries of data not written yet and a session point at now, logging
+// failures. Entries of a failed write are retried on the next refresh.
+func (s *InfluxSink) Write(ctx context.Context, data MonitoringData, now time.Time) {
+	s.mu.Lock()
+	if s.writing {
+		s.mu.Unlock()
+		return
+	}
+	s.writing = true
+	points, keys := s.points(data, now)
+	s.mu.Unlock()
+
+	err := s.client.Write(ctx, points)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.writing = false
+	if err != nil {
+		logging.LogWarnf("Failed to write usage to InfluxDB: %v", err)
+		return
+	}
+
+	// Only remember entries still loaded, so the set doesn't outgrow the loaded history
+	written := make(map[string]struct{}, len(keys))
+	for key, isNew := range keys {
+		if _, ok := s.written[key]; ok || isNew {
+			written[key] = struct{}{}
+		}
+	}
+	s.written = written
+}
+
+// points returns the points of the entries of data not written yet plus the session point,
+// and the keys of all loaded entries mapped to whether they are among the points. The
+// caller holds s.mu.
+func (s *InfluxSink) points(data MonitoringData, now time.Time) ([]influx.Point, map[string]bool) {
+	var points []influx.Point
+	keys := make(map[string]bool)
+	var active *models.SessionBlock
+	for i := range data.Data.Blocks {
+		block := &data.Data.Blocks[i]
+		if block.IsGap {
+			continue
+		}
+		if block.IsActive && active == nil {
+			active = block
+		}
+		for _, entry := range block.Entries {
+			key := entry.Timestamp.Format(time.RFC3339Nano) + "|" + entry.MessageID + "|" + entry.RequestID
+			_, done := s.written[key]
+			keys[key] = !done
+			if !done {
+				points = append(points, s.entryPoint(entry))
+			}
+		}
+	}
+	return append(points, s.sessionPoint(active, data.TokenLimit, now)), keys
+}
+
+// entryPoint returns the point of a usage entry
+func (s *InfluxSink) entryPoint(entry models.UsageEntry) influx.Point {
+	tags := s.pointTags()
+	tags["model"] = entry.Model
+	tags["project"] = entry.Project
+	tags["source"] = entry.Source
+	tags["tag"] = entry.Tag
+	return influx.Point{
+		Measurement: s.prefix + "_usage",
+		Tags:        tags,
+		Fields: map[string]any{
+			"input_tokens":          entry.InputTokens,
+			"output_tokens":         entry.OutputTokens,
+			"cache_creation_tokens": entry.CacheCreationTokens,
+			"cache_read_tokens":     entry.CacheReadTokens,
+			"total_tokens":          entry.TotalTokens,
+			"cost_usd":              entry.CostUSD,
+		},
+		Time: entry.Timestamp,
+	}
+}
+
+// sessionPoint returns the point of the active session block, with zero usage when none is
+// active
+func (s *InfluxSink) sessionPoint(active *models.SessionBlock, tokenLimit int, now time.Time) influx.Point {
+	fields := map[string]any{
+		"active":            active != nil,
+		"tokens":            0,
+		"cost_usd":          0.0,
+		"tokens_per_minute": 0.0,
+		"cost_per_hour":     0.0,
+		"token_limit":       tokenLimit,
+	}
+	if active != nil {
+		fields["tokens"] = active.TokenCounts.TotalTokens()
+		fields["cost_usd"] = active.CostUSD
+		if burnRate := calculations.NewBurnRateCalculator().CalculateBurnRate(*active); burnRate != nil {
+			fields["tokens_per_minute"] = burnRate.TokensPerMinute
+			fields["cost_per_hour"] = burnRate.CostPerHour
+		}
+	}
+	return influx.Point{
+		Measurement: s.prefix + "_session",
+		Tags:        s.pointTags(),
+		Fields:      fields,
+		Time:        now,
+	}
+}
+
+// pointTags returns a copy of the configured tags
+func (s *InfluxSink) pointTags() map[string]string {
+	tags := make(map[string]string, len(s.tags)+4)
+	for key, value := range s.tags {
+		tags[key] = value
+	}
+	return tags
+}
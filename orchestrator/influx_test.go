@@ -0,0 +1,61 @@
+package orchestrator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/penwyp/claudecat/config"
+	"github.com/penwyp/claudecat/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewInfluxSink_Disabled(t *testing.T) {
+	sink, err := NewInfluxSink(config.DefaultConfig().Influx)
+	assert.NoError(t, err)
+	assert.Nil(t, sink)
+}
+
+func TestInfluxSink_WritesNewEntriesOnce(t *testing.T) {
+	cfg := config.DefaultConfig().Influx
+	cfg.File = filepath.Join(t.TempDir(), "usage.lp")
+	cfg.Tags = map[string]string{"host": "nas"}
+	sink, err := NewInfluxSink(cfg)
+	require.NoError(t, err)
+	require.NotNil(t, sink)
+
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	block := models.SessionBlock{
+		ID:        "s",
+		StartTime: now.Add(-time.Hour),
+		EndTime:   now.Add(4 * time.Hour),
+		IsActive:  true,
+		Entries: []models.UsageEntry{
+			{Timestamp: now.Add(-time.Hour), Model: "claude-opus-4-20250514", Project: "api", MessageID: "m1", TotalTokens: 100, CostUSD: 1},
+		},
+		TokenCounts: models.TokenCounts{InputTokens: 100},
+		CostUSD:     1,
+	}
+	data := MonitoringData{Data: AnalysisResult{Blocks: []models.SessionBlock{block}}, TokenLimit: 44000}
+	sink.Write(context.Background(), data, now)
+
+	block.Entries = append(block.Entries, models.UsageEntry{
+		Timestamp: now.Add(-time.Minute), Model: "claude-sonnet-4-20250514", Project: "api", MessageID: "m2", TotalTokens: 10, CostUSD: 0.1,
+	})
+	data.Data.Blocks = []models.SessionBlock{block}
+	sink.Write(context.Background(), data, now.Add(time.Minute))
+
+	content, err := os.ReadFile(cfg.File)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	require.Len(t, lines, 4)
+	assert.True(t, strings.HasPrefix(lines[0], "claude_usage,host=nas,model=claude-opus-4-20250514,project=api "), lines[0])
+	assert.True(t, strings.HasPrefix(lines[1], "claude_session,host=nas active=true,"), lines[1])
+	assert.Contains(t, lines[1], "token_limit=44000i")
+	assert.Contains(t, lines[2], "model=claude-sonnet-4-20250514")
+	assert.True(t, strings.HasPrefix(lines[3], "claude_session,"), lines[3])
+}
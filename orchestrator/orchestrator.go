@@ -2,26 +2,40 @@ package orchestrator
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/penwyp/claudecat/cache"
+	"github.com/penwyp/claudecat/calculations"
 	"github.com/penwyp/claudecat/config"
+	errs "github.com/penwyp/claudecat/errors"
+	"github.com/penwyp/claudecat/events"
+	"github.com/penwyp/claudecat/fileio"
+	"github.com/penwyp/claudecat/history"
+	"github.com/penwyp/claudecat/hooks"
 	"github.com/penwyp/claudecat/logging"
 	"github.com/penwyp/claudecat/models"
 	"github.com/penwyp/claudecat/models/pricing"
+	"github.com/penwyp/claudecat/notify"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // MonitoringData represents the data structure passed to callbacks
 type MonitoringData struct {
-	Data         AnalysisResult `json:"data"`
-	TokenLimit   int            `json:"token_limit"`
-	Args         interface{}    `json:"args,omitempty"`
-	SessionID    string         `json:"session_id"`
-	SessionCount int            `json:"session_count"`
+	Data         AnalysisResult              `json:"data"`
+	TokenLimit   int                         `json:"token_limit"`
+	Args         interface{}                 `json:"args,omitempty"`
+	SessionID    string                      `json:"session_id"`
+	SessionCount int                         `json:"session_count"`
+	Budgets      []calculations.BudgetStatus `json:"budgets,omitempty"`
+	Rolling      calculations.RollingTotals  `json:"rolling"`
 }
 
 // AnalysisResult represents the processed analysis data
@@ -49,15 +63,30 @@ type DataUpdateCallback func(MonitoringData)
 // SessionChangeCallback represents a callback function for session changes
 type SessionChangeCallback func(eventType, sessionID string, sessionData interface{})
 
+// BudgetAlertCallback represents a callback function for budget threshold crossings
+type BudgetAlertCallback func(calculations.BudgetAlert)
+
+// AnomalyCallback represents a callback function for burn rate spikes
+type AnomalyCallback func(calculations.Anomaly)
+
 // MonitoringOrchestrator orchestrates monitoring components following SRP
 type MonitoringOrchestrator struct {
 	updateInterval time.Duration
-	dataPath       string
+	dataPaths      []string
 	config         *config.Config
 
 	// Internal components
 	dataManager    *DataManager
 	sessionMonitor *SessionMonitor
+	p90Calculator  *calculations.P90Calculator
+	budgetTracker  *calculations.BudgetTracker
+	rolling        *calculations.RollingWindows
+	rollingLoading atomic.Bool                   // A load of the rolling window history is running
+	anomalies      *calculations.AnomalyDetector // Nil when anomaly detection is disabled
+	fileWatcher    *FileWatcher
+
+	// Token limit last warned about for contradicting the configured plan
+	warnedObservedLimit int
 
 	// State management
 	monitoring    bool
@@ -68,11 +97,51 @@ type MonitoringOrchestrator struct {
 	// Callbacks
 	updateCallbacks  []DataUpdateCallback
 	sessionCallbacks []SessionChangeCallback
+	budgetCallbacks  []BudgetAlertCallback
+	anomalyCallbacks []AnomalyCallback
+
+	// Notifications sent by the orchestrator itself, such as webhooks
+	notifiers   []notify.Notifier
+	limitWarner *notify.LimitWarner
+	digests     *DigestScheduler
+	statsd      *StatsdReporter
+	influx      *InfluxSink
+
+	// Pipeline hooks and what they have been told about
+	hooks     *hooks.Registry
+	hookState hookTracker
+
+	// Trend database snapshots
+	historyStore     *history.Store
+	snapshotInterval time.Duration
+	lastSnapshot     time.Time
+
+	// Event log of session starts and ends, limit hits, budget breaches and errors
+	eventLog *events.Log
 
 	// Data tracking
 	lastValidData  *MonitoringData
 	firstDataEvent chan struct{}
 
+	// Fetch outcomes for health checks
+	fetchStats     fetchStats
+	lastEventError string // Error of the last failed refresh recorded in the event log
+	healthMu       sync.Mutex
+
+	// Wakes the monitoring loop after a configuration reload
+	reconfigured chan struct{}
+
+	// Adaptive refresh interval
+	refresh          *refreshScheduler
+	lastActivityMark activityMark
+
+	// Idle suspension. The summary store is closed while suspended and reopened on resume.
+	idleTimeout    time.Duration // Zero never suspends
+	suspended      bool
+	suspendedAt    time.Time
+	summaryStore   cache.SummaryStore // Nil when the summary cache is disabled
+	summaryBackend cache.BackendConfig
+
 	// Args from CLI
 	args interface{}
 
@@ -81,10 +150,23 @@ type MonitoringOrchestrator struct {
 }
 
 // NewMonitoringOrchestrator creates a new monitoring orchestrator
-func NewMonitoringOrchestrator(updateInterval time.Duration, dataPath string, cfg *config.Config) *MonitoringOrchestrator {
+func NewMonitoringOrchestrator(updateInterval time.Duration, dataPaths []string, cfg *config.Config) *MonitoringOrchestrator {
 	ctx, cancel := context.WithCancel(context.Background())
 
-	dataManager := NewDataManager(192, dataPath) // 192 hours back
+	// Budgets need history back to the start of their longest period
+	loc, err := cfg.Location()
+	if err != nil {
+		logging.LogWarnf("%v, using local time for budgets", err)
+	}
+	budgetTracker := calculations.NewBudgetTrackerFromConfig(cfg.Budgets, loc)
+	hoursBack := 192 // 8 days, the rolling 7-day window and its history refresh (calculations.RollingHistoryRefresh)
+	if budgetTracker != nil {
+		if budgetHours := int(budgetTracker.Lookback().Hours()); budgetHours > hoursBack {
+			hoursBack = budgetHours
+		}
+	}
+
+	dataManager := NewDataManager(hoursBack, dataPaths)
 
 	// Expand cache directory path for use in both cache and pricing
 	cacheDir := cfg.Cache.Dir
@@ -94,12 +176,18 @@ func NewMonitoringOrchestrator(updateInterval time.Duration, dataPath string, cf
 	}
 
 	// Set up cache if enabled
-	fileCache, err := cache.NewFileBasedSummaryCache(cacheDir)
+	summaryBackend := cache.NewBackendConfig(cfg.Cache, cacheDir, false)
+	summaryStore, err := cache.OpenSummaryStore(summaryBackend)
 	if err != nil {
-		logging.LogErrorf("Failed to create file-based cache: %v", err)
+		logging.LogErrorf("Failed to open %s summary cache: %v", cfg.Cache.Backend, err)
 		// Cache is disabled on error
+		summaryStore = nil
 	} else {
-		dataManager.SetCacheStore(fileCache, cfg.Data.SummaryCache)
+		// Summaries damaged by a crash are dropped here and rebuilt by the first load
+		if _, err := cache.RecoverStore(summaryStore); err != nil {
+			logging.LogWarnf("%v", err)
+		}
+		dataManager.SetCacheStore(summaryStore, cfg.Data.SummaryCache)
 	}
 
 	// Set up pricing provider
@@ -111,22 +199,136 @@ func NewMonitoringOrchestrator(updateInterval time.Duration, dataPath string, cf
 	}
 	dataManager.SetPricingProvider(pricingProvider)
 
+	// Set session window length
+	dataManager.SetSessionWindow(cfg.Session.WindowDuration, cfg.Session.LateWriteBuffer)
+
 	// Set deduplication flag
 	dataManager.SetDeduplication(cfg.Data.Deduplication)
+	if cfg.Data.Deduplication {
+		if dedupIndex, err := cache.OpenDedupIndex(cacheDir, cfg.Data.DedupRetention); err != nil {
+			logging.LogWarnf("Persistent deduplication disabled: %v", err)
+		} else {
+			dataManager.SetDedupIndex(dedupIndex)
+		}
+	}
+
+	// Set privacy redaction
+	dataManager.SetRedaction(cfg.Privacy.RedactContent)
 
-	return &MonitoringOrchestrator{
+	// Set cost mode
+	costMode, err := models.ParseCostMode(cfg.Data.CostMode)
+	if err != nil {
+		logging.LogWarnf("%v, falling back to auto", err)
+	}
+	dataManager.SetCostMode(costMode)
+
+	// Set entry validation
+	validation := cfg.Data.Validation
+	dataManager.SetValidator(models.NewEntryValidator(models.EntryBounds(validation.Bounds), validation.Action, validation.IncludeSuspect))
+
+	// Set loader concurrency
+	dataManager.SetConcurrency(fileio.ConcurrencyOptions{
+		MaxWorkers:   cfg.Performance.WorkerCount,
+		Threshold:    cfg.Performance.ConcurrencyThreshold,
+		InOrder:      cfg.Performance.Scheduling == "in_order",
+		FixedWorkers: cfg.Performance.FixedWorkers,
+	})
+	dataManager.SetMemoryBudget(cfg.Performance.MaxMemory)
+	dataManager.SetRetryPolicy(errs.NewRetryPolicy(cfg.Performance.Retry))
+
+	mo := &MonitoringOrchestrator{
 		updateInterval:   updateInterval,
-		dataPath:         dataPath,
+		dataPaths:        dataPaths,
 		config:           cfg,
 		dataManager:      dataManager,
 		sessionMonitor:   NewSessionMonitor(),
+		p90Calculator:    calculations.NewP90Calculator(),
+		budgetTracker:    budgetTracker,
+		rolling:          calculations.NewRollingWindows(),
 		monitoring:       false,
 		stopEvent:        ctx,
 		stopCancel:       cancel,
 		updateCallbacks:  make([]DataUpdateCallback, 0),
 		sessionCallbacks: make([]SessionChangeCallback, 0),
 		firstDataEvent:   make(chan struct{}, 1),
+		reconfigured:     make(chan struct{}, 1),
+		summaryStore:     summaryStore,
+		summaryBackend:   summaryBackend,
+		hooks:            hooks.NewRegistryFromConfig(cfg.Hooks),
 	}
+
+	mo.refresh = mo.newRefreshSchedulerLocked()
+
+	// Only background mode suspends; the console shows a live session countdown
+	if cfg.UI.CompactMode {
+		mo.idleTimeout = cfg.UI.IdleTimeout
+	}
+
+	// Set up webhook notifications
+	if cfg.Limits.Notifies(config.NotifyWebhook) {
+		limits := cfg.Limits
+		webhook, err := notify.NewWebhookNotifier(limits.WebhookURL, limits.WebhookHeaders, limits.WebhookTemplate)
+		if err != nil {
+			logging.LogWarnf("Webhook notifications disabled: %v", err)
+		} else {
+			mo.notifiers = append(mo.notifiers, webhook)
+			mo.limitWarner = notify.NewLimitWarner(limits.WarnBefore)
+			mo.sessionMonitor.RegisterCallback(mo.onSessionChange)
+		}
+	}
+
+	// Set up pipeline hooks; threshold hooks need limit warnings even without webhooks
+	mo.sessionMonitor.RegisterCallback(mo.onSessionHook)
+	if mo.hooks.Wants(hooks.EventThresholdCrossed) && mo.limitWarner == nil {
+		mo.limitWarner = notify.NewLimitWarner(cfg.Limits.WarnBefore)
+	}
+
+	// Set up usage digests
+	if digests, err := NewDigestScheduler(cfg.Digest, loc, cfg.UI.TimeFormat, time.Now()); err != nil {
+		logging.LogWarnf("Usage digests disabled: %v", err)
+	} else {
+		mo.digests = digests
+	}
+
+	// Set up statsd metrics
+	if reporter, err := NewStatsdReporter(cfg.Statsd, loc); err != nil {
+		logging.LogWarnf("Statsd metrics disabled: %v", err)
+	} else {
+		mo.statsd = reporter
+	}
+
+	// Set up the InfluxDB sink
+	if sink, err := NewInfluxSink(cfg.Influx); err != nil {
+		logging.LogWarnf("InfluxDB sink disabled: %v", err)
+	} else {
+		mo.influx = sink
+	}
+
+	// Set up trend snapshots
+	if !cfg.History.Disabled && cfg.History.SnapshotInterval > 0 {
+		if store, err := history.Open(cacheDir, cfg.History.Retention, loc); err != nil {
+			logging.LogWarnf("Trend history disabled: %v", err)
+		} else {
+			mo.historyStore = store
+			mo.snapshotInterval = cfg.History.SnapshotInterval
+		}
+	}
+
+	// Set up burn rate spike detection
+	if !cfg.Anomalies.Disabled {
+		mo.anomalies = calculations.NewAnomalyDetector(cfg.Anomalies.Factor, cfg.Anomalies.Window, loc)
+	}
+
+	// Set up the event log
+	if !cfg.Events.Disabled {
+		if eventLog, err := events.Open(cacheDir); err != nil {
+			logging.LogWarnf("Event log disabled: %v", err)
+		} else {
+			mo.eventLog = eventLog
+		}
+	}
+
+	return mo
 }
 
 // Start begins monitoring
@@ -146,6 +348,11 @@ func (mo *MonitoringOrchestrator) Start() error {
 	// Start DataManager background tasks
 	mo.dataManager.Start(mo.stopEvent)
 
+	// Watch data files for near-real-time updates
+	if mo.config == nil || mo.config.Data.AutoDiscover {
+		mo.startFileWatcher()
+	}
+
 	// Start monitoring goroutine
 	mo.monitorThread = &Goroutine{
 		name: "MonitoringThread",
@@ -173,6 +380,19 @@ func (mo *MonitoringOrchestrator) Stop() {
 	// Stop DataManager background tasks
 	mo.dataManager.Stop()
 
+	// Reopen the summary cache closed while idle, so a restart finds it
+	if mo.suspended {
+		mo.resumeLocked()
+	}
+
+	// Stop file watcher
+	if mo.fileWatcher != nil {
+		if err := mo.fileWatcher.Stop(); err != nil {
+			logging.LogWarnf("Failed to stop file watcher: %v", err)
+		}
+		mo.fileWatcher = nil
+	}
+
 	// Wait for goroutine to finish with timeout
 	if mo.monitorThread != nil {
 		select {
@@ -191,6 +411,38 @@ func (mo *MonitoringOrchestrator) Stop() {
 	}
 }
 
+// startFileWatcher starts an fsnotify watcher that pushes file changes to the DataManager.
+// When watching is unavailable the DataManager keeps discovering files on every load.
+func (mo *MonitoringOrchestrator) startFileWatcher() {
+	var delay time.Duration
+	if mo.config != nil {
+		delay = mo.config.Data.WatchInterval
+	}
+
+	watcher, err := NewFileWatcher(mo.dataPaths, delay, mo.dataManager.ApplyFileChanges)
+	if err != nil {
+		logging.LogWarnf("File watching unavailable, falling back to periodic scans: %v", err)
+		return
+	}
+
+	// Start watching before listing files so nothing created in between is missed
+	if err := watcher.Start(); err != nil {
+		logging.LogWarnf("File watching unavailable, falling back to periodic scans: %v", err)
+		_ = watcher.Stop()
+		return
+	}
+
+	files, err := fileio.DiscoverFilesInPaths(mo.dataPaths)
+	if err != nil {
+		logging.LogWarnf("Failed to list data files, falling back to periodic scans: %v", err)
+		_ = watcher.Stop()
+		return
+	}
+	mo.dataManager.TrackFiles(files)
+	mo.fileWatcher = watcher
+	logging.LogInfof("Watching %d data files in %s", len(files), strings.Join(mo.dataPaths, ", "))
+}
+
 // SetArgs sets command line arguments for token limit calculation
 func (mo *MonitoringOrchestrator) SetArgs(args interface{}) {
 	mo.mu.Lock()
@@ -198,6 +450,72 @@ func (mo *MonitoringOrchestrator) SetArgs(args interface{}) {
 	mo.args = args
 }
 
+// SetConfig replaces the configuration used for token limits after a reload and refreshes
+// the data with it
+func (mo *MonitoringOrchestrator) SetConfig(cfg *config.Config) {
+	mo.mu.Lock()
+	mo.config = cfg
+	mo.refresh = mo.newRefreshSchedulerLocked()
+	mo.mu.Unlock()
+	if cfg != nil {
+		mo.dataManager.SetRetryPolicy(errs.NewRetryPolicy(cfg.Performance.Retry))
+	}
+	mo.notifyReconfigured()
+}
+
+// SetUpdateInterval changes how often data is refreshed while monitoring. It is the
+// shortest interval unless ui.min_refresh_interval is set.
+func (mo *MonitoringOrchestrator) SetUpdateInterval(interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	mo.mu.Lock()
+	mo.updateInterval = interval
+	mo.refresh = mo.newRefreshSchedulerLocked()
+	mo.mu.Unlock()
+	mo.notifyReconfigured()
+}
+
+// SetDataPaths switches the monitored data paths. The file watcher is restarted on the new
+// paths and the next refresh performs a full load.
+func (mo *MonitoringOrchestrator) SetDataPaths(dataPaths []string) {
+	mo.mu.Lock()
+	mo.dataPaths = dataPaths
+	mo.dataManager.SetDataPaths(dataPaths)
+	if mo.fileWatcher != nil {
+		if err := mo.fileWatcher.Stop(); err != nil {
+			logging.LogWarnf("Failed to stop file watcher: %v", err)
+		}
+		mo.fileWatcher = nil
+	}
+	if mo.monitoring && (mo.config == nil || mo.config.Data.AutoDiscover) {
+		mo.startFileWatcher()
+	}
+	mo.mu.Unlock()
+	mo.notifyReconfigured()
+}
+
+// SetLoadProgress sets a callback that reports the progress of the initial load. Set it
+// before Start.
+func (mo *MonitoringOrchestrator) SetLoadProgress(progress fileio.ProgressFunc) {
+	mo.dataManager.SetLoadProgress(progress)
+}
+
+// SetCostMode changes how entry costs are determined. Cached file summaries computed with
+// another mode are ignored, so the next refresh recalculates every file.
+func (mo *MonitoringOrchestrator) SetCostMode(mode models.CostMode) {
+	mo.dataManager.SetCostMode(mode)
+	mo.notifyReconfigured()
+}
+
+// notifyReconfigured wakes the monitoring loop without blocking if a wake-up is already pending
+func (mo *MonitoringOrchestrator) notifyReconfigured() {
+	select {
+	case mo.reconfigured <- struct{}{}:
+	default:
+	}
+}
+
 // RegisterUpdateCallback registers a callback for data updates
 func (mo *MonitoringOrchestrator) RegisterUpdateCallback(callback DataUpdateCallback) {
 	mo.mu.Lock()
@@ -210,6 +528,21 @@ func (mo *MonitoringOrchestrator) RegisterSessionCallback(callback SessionChange
 	mo.mu.Lock()
 	defer mo.mu.Unlock()
 	mo.sessionCallbacks = append(mo.sessionCallbacks, callback)
+	mo.sessionMonitor.RegisterCallback(callback)
+}
+
+// RegisterBudgetCallback registers a callback for budget threshold crossings
+func (mo *MonitoringOrchestrator) RegisterBudgetCallback(callback BudgetAlertCallback) {
+	mo.mu.Lock()
+	defer mo.mu.Unlock()
+	mo.budgetCallbacks = append(mo.budgetCallbacks, callback)
+}
+
+// RegisterAnomalyCallback registers a callback for burn rate spikes
+func (mo *MonitoringOrchestrator) RegisterAnomalyCallback(callback AnomalyCallback) {
+	mo.mu.Lock()
+	defer mo.mu.Unlock()
+	mo.anomalyCallbacks = append(mo.anomalyCallbacks, callback)
 }
 
 // ForceRefresh forces immediate data refresh
@@ -231,30 +564,101 @@ func (mo *MonitoringOrchestrator) WaitForInitialData(timeout time.Duration) bool
 func (mo *MonitoringOrchestrator) monitoringLoop() {
 	// Initial fetch
 	if _, err := mo.fetchAndProcessData(false); err != nil {
-		logging.LogErrorf("Initial data fetch failed: %v", err)
+		logFetchError("Initial data fetch failed: %v", err)
 	}
 
-	ticker := time.NewTicker(mo.updateInterval)
+	// The ticker is reset after every refresh, so its interval follows the activity
+	ticker := time.NewTicker(mo.resetRefresh())
 	defer ticker.Stop()
 
+	lastActivity := time.Now()
 	for {
 		select {
 		case <-mo.stopEvent.Done():
 			return
+		case <-mo.reconfigured:
+			lastActivity = time.Now()
+			mo.resume()
+			mo.resetRefresh()
+			result, err := mo.fetchAndProcessData(true)
+			if err != nil {
+				logFetchError("Data fetch after configuration reload failed: %v", err)
+			}
+			ticker.Reset(mo.nextRefresh(result, true))
 		case <-ticker.C:
-			if _, err := mo.fetchAndProcessData(false); err != nil {
-				logging.LogErrorf("Periodic data fetch failed: %v", err)
+			if mo.idleExpired(time.Since(lastActivity)) {
+				ticker.Stop()
+				mo.suspend(time.Since(lastActivity))
+				continue
 			}
+			result, err := mo.fetchAndProcessData(false)
+			if err != nil {
+				logFetchError("Periodic data fetch failed: %v", err)
+			}
+			ticker.Reset(mo.nextRefresh(result, false))
+		case <-mo.dataManager.Changes():
+			lastActivity = time.Now()
+			mo.resume()
+			result, err := mo.fetchAndProcessData(false)
+			if err != nil {
+				logFetchError("Data fetch after file change failed: %v", err)
+			}
+			ticker.Reset(mo.nextRefresh(result, true))
 		}
 	}
 }
 
+// logFetchError logs a failed data fetch; finding no usage yet is expected until Claude Code
+// has been used, so it is not reported as an error
+func logFetchError(format string, err error) {
+	if errors.Is(err, ErrNoUsageEntries) {
+		logging.LogInfof(format, err)
+		return
+	}
+	logging.LogErrorf(format, err)
+}
+
+// rollingHistoryRetry is how long a failed load of the rolling window history waits
+// before the next attempt
+const rollingHistoryRetry = time.Minute
+
+// updateRolling updates the rolling window totals with blocks. The 30-day history before
+// the blocks is loaded in the background when due, so refreshes keep loading only the
+// monitored window; until it is loaded the totals are partial.
+func (mo *MonitoringOrchestrator) updateRolling(blocks []models.SessionBlock, now time.Time) calculations.RollingTotals {
+	if mo.rolling.HistoryDue(now) && mo.rollingLoading.CompareAndSwap(false, true) {
+		go func() {
+			defer mo.rollingLoading.Store(false)
+			entries, err := mo.dataManager.LoadEntries(mo.stopEvent, int(calculations.RollingMonth.Hours())+1)
+			if err != nil {
+				if mo.stopEvent.Err() == nil {
+					logging.LogWarnf("Failed to load the rolling window history: %v", err)
+				}
+				select {
+				case <-time.After(rollingHistoryRetry):
+				case <-mo.stopEvent.Done():
+				}
+				return
+			}
+			mo.rolling.SetHistory(entries, now)
+		}()
+	}
+	return mo.rolling.Update(blocks, now)
+}
+
 // fetchAndProcessData fetches data and notifies callbacks
-func (mo *MonitoringOrchestrator) fetchAndProcessData(forceRefresh bool) (*MonitoringData, error) {
+func (mo *MonitoringOrchestrator) fetchAndProcessData(forceRefresh bool) (result *MonitoringData, err error) {
 	startTime := time.Now()
+	ctx, span := tracer.Start(context.Background(), "orchestrator.Refresh",
+		trace.WithAttributes(attribute.Bool("forced", forceRefresh)))
+	defer func() {
+		mo.recordFetch(err)
+		mo.recordErrorEvent(err, time.Now())
+		recordRefresh(ctx, span, startTime, result, err)
+	}()
 
 	// Fetch data using DataManager
-	data, err := mo.dataManager.GetData(forceRefresh)
+	data, err := mo.dataManager.GetData(ctx, forceRefresh)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch data: %w", err)
 	}
@@ -269,18 +673,34 @@ func (mo *MonitoringOrchestrator) fetchAndProcessData(forceRefresh bool) (*Monit
 		return nil, fmt.Errorf("data validation failed: %v", errors)
 	}
 
+	mo.dispatchHooks(mo.pipelineEvents(data.Blocks, time.Now()))
+	mo.recordBlockEvents(data.Blocks)
+
 	// Calculate token limit
 	tokenLimit := mo.calculateTokenLimit(data)
 
+	mo.mu.RLock()
+	args := mo.args
+	cfg := mo.config
+	mo.mu.RUnlock()
+
 	// Prepare monitoring data
 	monitoringData := &MonitoringData{
 		Data:         *data,
 		TokenLimit:   tokenLimit,
-		Args:         mo.args,
+		Args:         args,
 		SessionID:    mo.sessionMonitor.GetCurrentSessionID(),
 		SessionCount: mo.sessionMonitor.GetSessionCount(),
 	}
 
+	// Evaluate budgets
+	var budgetAlerts []calculations.BudgetAlert
+	if mo.budgetTracker != nil {
+		monitoringData.Budgets, budgetAlerts = mo.budgetTracker.Evaluate(data.Blocks, time.Now())
+	}
+
+	monitoringData.Rolling = mo.updateRolling(data.Blocks, time.Now())
+
 	// Store last valid data
 	mo.mu.Lock()
 	mo.lastValidData = monitoringData
@@ -295,6 +715,44 @@ func (mo *MonitoringOrchestrator) fetchAndProcessData(forceRefresh bool) (*Monit
 
 	// Notify callbacks
 	mo.notifyCallbacks(*monitoringData)
+	mo.notifyBudgetCallbacks(budgetAlerts)
+	mo.recordBudgetEvents(budgetAlerts, time.Now())
+
+	// Push metrics to statsd
+	if mo.statsd != nil {
+		mo.statsd.Report(*monitoringData, time.Now())
+	}
+	if mo.influx != nil {
+		go mo.influx.Write(context.Background(), *monitoringData, time.Now())
+	}
+
+	// Flag burn rate spikes
+	if mo.anomalies != nil {
+		if anomaly := mo.anomalies.Check(data.Blocks, time.Now()); anomaly != nil {
+			mo.notifyAnomalyCallbacks(*anomaly)
+		}
+	}
+
+	// Send limit notifications
+	mo.mu.RLock()
+	limitWarner := mo.limitWarner
+	mo.mu.RUnlock()
+	if limitWarner != nil {
+		mo.sendNotifications(limitWarner.Check(data.Blocks, tokenLimit, activeTokensPerMinute(data.Blocks), time.Now()))
+		if cfg != nil {
+			costAlert := activeCostAlert(data.Blocks, calculations.SessionCostCeilings(cfg.Subscription))
+			mo.sendNotifications(limitWarner.CheckCost(costAlert, time.Now()))
+		}
+	}
+
+	// Post usage digests that became due
+	if mo.digests != nil {
+		if digests := mo.digests.Due(data.Blocks, time.Now()); len(digests) > 0 {
+			go mo.digests.Post(context.Background(), digests)
+		}
+	}
+
+	mo.recordHistory(data.Blocks, time.Now())
 
 	elapsed := time.Since(startTime)
 	logging.LogInfof("Data processing completed in %.3fs", elapsed.Seconds())
@@ -302,12 +760,42 @@ func (mo *MonitoringOrchestrator) fetchAndProcessData(forceRefresh bool) (*Monit
 	return monitoringData, nil
 }
 
-// calculateTokenLimit calculates token limit based on plan and data
+// calculateTokenLimit calculates token limit based on plan and data.
+// A configured custom limit wins, then the limit Claude's limit messages state, then the P90
+// of past sessions when requested or when the plan has no documented limit, then the plan's
+// limit from the plan catalog.
 func (mo *MonitoringOrchestrator) calculateTokenLimit(data *AnalysisResult) int {
-	// This would implement the same logic as Claude Monitor's token limit calculation
-	// For now, return a default value
-	// TODO: Implement proper token limit calculation based on plan type
-	return 500000 // Default token limit
+	mo.mu.RLock()
+	cfg := mo.config
+	mo.mu.RUnlock()
+
+	if cfg != nil {
+		if cfg.Subscription.CustomTokenLimit > 0 {
+			return cfg.Subscription.CustomTokenLimit
+		}
+		catalog := calculations.NewPlanCatalogFromConfig(cfg.Subscription)
+		if observed, ok := calculations.DetectObservedLimit(data.Blocks, catalog); ok {
+			mo.warnObservedLimit(observed, cfg.Subscription.Plan, catalog)
+			return observed.TokenLimit
+		}
+		if cfg.Subscription.TokenLimitP90 || strings.EqualFold(cfg.Subscription.Plan, models.PlanCustom) {
+			return mo.p90Calculator.CalculateP90Limit(data.Blocks, true)
+		}
+		return catalog.Limits(cfg.Subscription.Plan).TokenLimit
+	}
+
+	return models.DefaultPlanCatalog().Limits(models.PlanPro).TokenLimit
+}
+
+// warnObservedLimit logs once per observed limit when it contradicts the configured plan
+func (mo *MonitoringOrchestrator) warnObservedLimit(observed calculations.ObservedLimit, plan string, catalog *models.PlanCatalog) {
+	mo.mu.Lock()
+	defer mo.mu.Unlock()
+	if observed.TokenLimit == mo.warnedObservedLimit || !observed.ConflictsWith(plan, catalog) {
+		return
+	}
+	mo.warnedObservedLimit = observed.TokenLimit
+	logging.LogWarnf("Plan mismatch: %s; using the observed limit", observed.Warning(plan))
 }
 
 // notifyCallbacks notifies all registered callbacks
@@ -329,6 +817,139 @@ func (mo *MonitoringOrchestrator) notifyCallbacks(data MonitoringData) {
 	}
 }
 
+// notifyAnomalyCallbacks logs a burn rate spike, records it in the event log and passes it to
+// all registered anomaly callbacks
+func (mo *MonitoringOrchestrator) notifyAnomalyCallbacks(anomaly calculations.Anomaly) {
+	mo.mu.RLock()
+	anomalyCallbacks := make([]AnomalyCallback, len(mo.anomalyCallbacks))
+	copy(anomalyCallbacks, mo.anomalyCallbacks)
+	mo.mu.RUnlock()
+
+	logging.LogWarnf("Usage anomaly: %s", anomaly.Message())
+	mo.sendNotifications([]notify.Notification{notify.AnomalyNotification(anomaly)})
+	mo.recordAnomalyEvent(anomaly)
+
+	for _, callback := range anomalyCallbacks {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					logging.LogErrorf("Anomaly callback panic: %v", r)
+				}
+			}()
+			callback(anomaly)
+		}()
+	}
+}
+
+// notifyBudgetCallbacks logs each budget alert and passes it to all registered budget callbacks
+func (mo *MonitoringOrchestrator) notifyBudgetCallbacks(alerts []calculations.BudgetAlert) {
+	if len(alerts) == 0 {
+		return
+	}
+
+	mo.mu.RLock()
+	budgetCallbacks := make([]BudgetAlertCallback, len(mo.budgetCallbacks))
+	copy(budgetCallbacks, mo.budgetCallbacks)
+	mo.mu.RUnlock()
+
+	for _, alert := range alerts {
+		mo.sendNotifications([]notify.Notification{notify.BudgetNotification(alert)})
+
+		if alert.Exceeded() {
+			logging.LogWarnf("Budget alert: %s", alert.Message())
+		} else {
+			logging.LogInfof("Budget alert: %s", alert.Message())
+		}
+
+		for _, callback := range budgetCallbacks {
+			func() {
+				defer func() {
+					if r := recover(); r != nil {
+						logging.LogErrorf("Budget callback panic: %v", r)
+					}
+				}()
+				callback(alert)
+			}()
+		}
+	}
+}
+
+// onSessionChange sends a notification when a session starts or ends. It runs while the
+// session monitor holds its lock, so it must not call back into the monitor.
+func (mo *MonitoringOrchestrator) onSessionChange(eventType, sessionID string, sessionData interface{}) {
+	if notification, ok := notify.SessionNotification(eventType, sessionID, time.Now()); ok {
+		mo.sendNotifications([]notify.Notification{notification})
+	}
+}
+
+// sendNotifications delivers notifications to the orchestrator's notifiers in the
+// background, and threshold crossings to the hooks
+func (mo *MonitoringOrchestrator) sendNotifications(notifications []notify.Notification) {
+	if len(notifications) == 0 {
+		return
+	}
+	mo.dispatchHooks(mo.thresholdEvents(notifications))
+	if len(mo.notifiers) == 0 {
+		return
+	}
+	go notify.Send(context.Background(), mo.notifiers, notifications)
+}
+
+// recordHistory snapshots the active session and refreshes daily summaries in the trend
+// database, at most once per snapshot interval
+func (mo *MonitoringOrchestrator) recordHistory(blocks []models.SessionBlock, now time.Time) {
+	if mo.historyStore == nil {
+		return
+	}
+
+	mo.mu.Lock()
+	due := now.Sub(mo.lastSnapshot) >= mo.snapshotInterval
+	if due {
+		mo.lastSnapshot = now
+	}
+	mo.mu.Unlock()
+	if !due {
+		return
+	}
+
+	if err := mo.historyStore.MergeDaily(mo.historyStore.SummarizeDays(blocks, now)); err != nil {
+		logging.LogWarnf("Failed to record daily history: %v", err)
+		return
+	}
+	if snapshot, ok := history.NewSnapshot(blocks, now); ok {
+		if err := mo.historyStore.AddSnapshot(snapshot); err != nil {
+			logging.LogWarnf("Failed to record history snapshot: %v", err)
+			return
+		}
+	}
+	logging.LogDebugf("Recorded trend history in %s", mo.historyStore.Path())
+}
+
+// activeTokensPerMinute returns the burn rate of the active session block, or 0 when idle
+func activeTokensPerMinute(blocks []models.SessionBlock) float64 {
+	burnRateCalc := calculations.NewBurnRateCalculator()
+	for _, block := range blocks {
+		if !block.IsActive || block.IsGap {
+			continue
+		}
+		if burnRate := burnRateCalc.CalculateBurnRate(block); burnRate != nil {
+			return burnRate.TokensPerMinute
+		}
+	}
+	return 0
+}
+
+// activeCostAlert returns the cost alert of the active session block, or nil when it reached
+// none of the ceilings
+func activeCostAlert(blocks []models.SessionBlock, ceilings []float64) *calculations.SessionCostAlert {
+	for _, block := range blocks {
+		if block.IsActive && !block.IsGap {
+			return calculations.CheckSessionCost(block, ceilings)
+		}
+	}
+	return nil
+}
+
 // Goroutine represents a managed goroutine
 type Goroutine struct {
 	name string
@@ -0,0 +1,92 @@
+package orchestrator
+
+import (
+	"time"
+
+	"github.com/penwyp/claudecat/models"
+)
+
+// refreshScheduler adapts the refresh interval to activity: it refreshes at the minimum
+// interval while the active session receives writes and doubles the interval on every idle
+// refresh, up to the maximum
+type refreshScheduler struct {
+	min     time.Duration
+	max     time.Duration
+	current time.Duration
+}
+
+// newRefreshScheduler creates a scheduler starting at min. A max not above min keeps the
+// interval fixed at min.
+func newRefreshScheduler(min, max time.Duration) *refreshScheduler {
+	if max < min {
+		max = min
+	}
+	return &refreshScheduler{min: min, max: max, current: min}
+}
+
+// next returns the interval until the following refresh, given whether the data showed
+// new writes since the previous one
+func (s *refreshScheduler) next(active bool) time.Duration {
+	if active {
+		s.current = s.min
+		return s.current
+	}
+	s.current *= 2
+	if s.current > s.max {
+		s.current = s.max
+	}
+	return s.current
+}
+
+// activityMark identifies how far the active session block had grown at a refresh
+type activityMark struct {
+	blockID string
+	entries int
+}
+
+// markActivity returns the mark of the active block, or the zero mark when no block is active
+func markActivity(blocks []models.SessionBlock) activityMark {
+	for i := len(blocks) - 1; i >= 0; i-- {
+		if blocks[i].IsActive && !blocks[i].IsGap {
+			return activityMark{blockID: blocks[i].ID, entries: len(blocks[i].Entries)}
+		}
+	}
+	return activityMark{}
+}
+
+// newRefreshSchedulerLocked builds the scheduler from the update interval and the configured
+// bounds. The caller must hold mo.mu.
+func (mo *MonitoringOrchestrator) newRefreshSchedulerLocked() *refreshScheduler {
+	min, max := mo.updateInterval, time.Duration(0)
+	if mo.config != nil {
+		if mo.config.UI.MinRefreshInterval > 0 {
+			min = mo.config.UI.MinRefreshInterval
+		}
+		max = mo.config.UI.MaxRefreshInterval
+	}
+	return newRefreshScheduler(min, max)
+}
+
+// nextRefresh records the data of a refresh and returns the interval until the next one.
+// Writes reported by the file watcher count as activity even before the data shows them.
+func (mo *MonitoringOrchestrator) nextRefresh(result *MonitoringData, written bool) time.Duration {
+	mo.mu.Lock()
+	defer mo.mu.Unlock()
+
+	if result != nil {
+		mark := markActivity(result.Data.Blocks)
+		if mark.blockID != "" && mark != mo.lastActivityMark {
+			written = true
+		}
+		mo.lastActivityMark = mark
+	}
+	return mo.refresh.next(written)
+}
+
+// resetRefresh restarts the schedule at the minimum interval and returns it
+func (mo *MonitoringOrchestrator) resetRefresh() time.Duration {
+	mo.mu.Lock()
+	defer mo.mu.Unlock()
+	mo.refresh = mo.newRefreshSchedulerLocked()
+	return mo.refresh.current
+}
@@ -0,0 +1,54 @@
+package orchestrator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/penwyp/claudecat/config"
+	"github.com/penwyp/claudecat/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRefreshScheduler(t *testing.T) {
+	s := newRefreshScheduler(5*time.Second, time.Minute)
+	assert.Equal(t, 5*time.Second, s.current)
+
+	// Idle refreshes back off to the maximum
+	var intervals []time.Duration
+	for i := 0; i < 5; i++ {
+		intervals = append(intervals, s.next(false))
+	}
+	assert.Equal(t, []time.Duration{10 * time.Second, 20 * time.Second, 40 * time.Second, time.Minute, time.Minute}, intervals)
+
+	// Writes return to the minimum at once
+	assert.Equal(t, 5*time.Second, s.next(true))
+
+	// Without a higher maximum the interval is fixed
+	fixed := newRefreshScheduler(time.Second, 0)
+	assert.Equal(t, time.Second, fixed.next(false))
+}
+
+func TestNextRefresh(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.UI.MinRefreshInterval = 2 * time.Second
+	cfg.UI.MaxRefreshInterval = 8 * time.Second
+	mo := &MonitoringOrchestrator{updateInterval: time.Second, config: cfg}
+	assert.Equal(t, 2*time.Second, mo.resetRefresh(), "the configured minimum replaces the update interval")
+
+	active := func(entries int) *MonitoringData {
+		return &MonitoringData{Data: AnalysisResult{Blocks: []models.SessionBlock{
+			{ID: "old", Entries: make([]models.UsageEntry, 3)},
+			{ID: "current", IsActive: true, Entries: make([]models.UsageEntry, entries)},
+		}}}
+	}
+
+	assert.Equal(t, 2*time.Second, mo.nextRefresh(active(1), false), "first sight of the active block")
+	assert.Equal(t, 4*time.Second, mo.nextRefresh(active(1), false))
+	assert.Equal(t, 8*time.Second, mo.nextRefresh(nil, false), "failed fetches back off too")
+	assert.Equal(t, 2*time.Second, mo.nextRefresh(active(2), false), "the active block grew")
+	assert.Equal(t, 4*time.Second, mo.nextRefresh(active(2), false))
+	assert.Equal(t, 2*time.Second, mo.nextRefresh(active(2), true), "the watcher saw a write")
+
+	// Without an active block there is nothing to speed up for
+	assert.Equal(t, 4*time.Second, mo.nextRefresh(&MonitoringData{}, false))
+}
@@ -0,0 +1,140 @@
+package orchestrator
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/penwyp/claudecat/fileio"
+	"github.com/penwyp/claudecat/models"
+)
+
+const (
+	// resultsCacheSize is how many analysis results are kept. Each one holds every block of
+	// the lookback window, so only a few are worth keeping: the current files and options,
+	// and the ones switched away from, like another cost mode or data path.
+	resultsCacheSize = 4
+
+	// resultsWindowTolerance is how long a result is reused while the lookback window moves
+	// on, leaving entries behind that a fresh load would drop
+	resultsWindowTolerance = time.Minute
+)
+
+// resultsCache keeps recent analysis results keyed by the state of the data files and the
+// options they were computed with, so refreshes that find nothing changed skip loading the
+// entries and grouping them into blocks
+type resultsCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List // Most recently used first
+	items    map[string]*list.Element
+}
+
+// cachedResult is an analysis result and when it stops being current
+type cachedResult struct {
+	key        string
+	result     *AnalysisResult
+	validUntil time.Time // Zero when it stays current until the files change
+}
+
+// newResultsCache creates a cache holding up to capacity results
+func newResultsCache(capacity int) *resultsCache {
+	return &resultsCache{
+		capacity: capacity,
+		order:    list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+// get returns the result cached under key, unless it has expired by now
+func (c *resultsCache) get(key string, now time.Time) (*AnalysisResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	cached := elem.Value.(*cachedResult)
+	if !cached.validUntil.IsZero() && !now.Before(cached.validUntil) {
+		c.order.Remove(elem)
+		delete(c.items, key)
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	return cached.result, true
+}
+
+// put caches a result under key, evicting the least recently used one when full
+func (c *resultsCache) put(key string, result *AnalysisResult, validUntil time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.items[key]; ok {
+		elem.Value = &cachedResult{key: key, result: result, validUntil: validUntil}
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.items[key] = c.order.PushFront(&cachedResult{key: key, result: result, validUntil: validUntil})
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*cachedResult).key)
+	}
+}
+
+// clear drops every result, for changes the keys don't capture such as new pricing
+func (c *resultsCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.order.Init()
+	c.items = make(map[string]*list.Element)
+}
+
+// resultsOptions are the settings besides the files that an analysis result depends on
+type resultsOptions struct {
+	dataPaths       []string
+	hoursBack       int
+	costMode        models.CostMode
+	deduplication   bool
+	sessionDuration time.Duration
+}
+
+// resultsKey hashes the path, modification time and size of each file together with the
+// options. Files that can't be read are left out, so they change the key once they can.
+func resultsKey(files []string, opts resultsOptions) string {
+	states := fileio.StatFiles(files)
+	paths := make([]string, 0, len(states))
+	for path := range states {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%q %d %v %t %s\n", opts.dataPaths, opts.hoursBack, opts.costMode, opts.deduplication, opts.sessionDuration)
+	for _, path := range paths {
+		state := states[path]
+		fmt.Fprintf(hash, "%s\x00%d\x00%d\n", path, state.ModTime.UnixNano(), state.Size)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// resultExpiry returns when a result computed at now stops being current although no file
+// changed: when its first active block ends, and, with a lookback window, once the window has
+// moved on by resultsWindowTolerance. The zero time means it stays current.
+func resultExpiry(blocks []models.SessionBlock, hoursBack int, now time.Time) time.Time {
+	var expiry time.Time
+	if hoursBack > 0 {
+		expiry = now.Add(resultsWindowTolerance)
+	}
+	for _, block := range blocks {
+		if block.IsActive && (expiry.IsZero() || block.EndTime.Before(expiry)) {
+			expiry = block.EndTime
+		}
+	}
+	return expiry
+}
@@ -0,0 +1,120 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/penwyp/claudecat/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResultsCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	c := newResultsCache(2)
+	now := time.Now()
+	a, b, d := &AnalysisResult{}, &AnalysisResult{}, &AnalysisResult{}
+
+	c.put("a", a, time.Time{})
+	c.put("b", b, time.Time{})
+	_, ok := c.get("a", now)
+	require.True(t, ok)
+	c.put("d", d, time.Time{})
+
+	_, ok = c.get("b", now)
+	assert.False(t, ok, "least recently used result is evicted")
+	got, ok := c.get("a", now)
+	assert.True(t, ok)
+	assert.Same(t, a, got)
+	got, ok = c.get("d", now)
+	assert.True(t, ok)
+	assert.Same(t, d, got)
+
+	c.clear()
+	_, ok = c.get("a", now)
+	assert.False(t, ok)
+}
+
+func TestResultsCache_Expiry(t *testing.T) {
+	c := newResultsCache(2)
+	now := time.Now()
+	c.put("a", &AnalysisResult{}, now.Add(time.Minute))
+
+	_, ok := c.get("a", now)
+	assert.True(t, ok)
+	_, ok = c.get("a", now.Add(time.Minute))
+	assert.False(t, ok)
+}
+
+func TestResultExpiry(t *testing.T) {
+	now := time.Now()
+	blocks := []models.SessionBlock{
+		{EndTime: now.Add(-time.Hour)},
+		{IsActive: true, EndTime: now.Add(30 * time.Second)},
+	}
+
+	assert.Equal(t, now.Add(30*time.Second), resultExpiry(blocks, 24, now), "the active block ends first")
+	assert.Equal(t, now.Add(resultsWindowTolerance), resultExpiry(blocks[:1], 24, now))
+	assert.True(t, resultExpiry(blocks[:1], 0, now).IsZero(), "nothing expires without a window or active block")
+}
+
+func TestResultsKey(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "session.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte("{}\n"), 0644))
+	opts := resultsOptions{dataPaths: []string{dir}, hoursBack: 24}
+
+	key := resultsKey([]string{path}, opts)
+	assert.Equal(t, key, resultsKey([]string{path}, opts))
+
+	other := opts
+	other.costMode = models.CostModeCalculated
+	assert.NotEqual(t, key, resultsKey([]string{path}, other), "options are part of the key")
+
+	require.NoError(t, os.WriteFile(path, []byte("{}\n{}\n"), 0644))
+	assert.NotEqual(t, key, resultsKey([]string{path}, opts), "a written file changes the key")
+}
+
+func TestDataManager_ReusesUnchangedResults(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "session.jsonl")
+	writeUsage := func(lines int) {
+		var content string
+		for i := 0; i < lines; i++ {
+			timestamp := time.Now().UTC().Add(-time.Duration(lines-i) * time.Minute).Format(time.RFC3339)
+			content += fmt.Sprintf(`{"type":"assistant","timestamp":%q,"requestId":"r%d","message":{"id":"m%d","model":"claude-sonnet-4-20250514","usage":{"input_tokens":100,"output_tokens":50}}}`+"\n", timestamp, i, i)
+		}
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	writeUsage(1)
+
+	dm := NewDataManager(24, []string{dir})
+	ctx := context.Background()
+	first, err := dm.analyzeUsageWatchMode(ctx)
+	require.NoError(t, err)
+	assert.False(t, first.Metadata.CacheUsed)
+
+	second, err := dm.analyzeUsageWatchMode(ctx)
+	require.NoError(t, err)
+	assert.True(t, second.Metadata.CacheUsed)
+	assert.Equal(t, first.Blocks, second.Blocks)
+
+	writeUsage(2)
+	third, err := dm.analyzeUsageWatchMode(ctx)
+	require.NoError(t, err)
+	assert.False(t, third.Metadata.CacheUsed)
+	assert.Equal(t, 2, third.Metadata.EntriesProcessed)
+}
+
+func TestDataManager_NoDataPaths(t *testing.T) {
+	dm := NewDataManager(24, nil)
+	ctx := context.Background()
+
+	_, err := dm.analyzeUsageWatchMode(ctx)
+	assert.ErrorIs(t, err, ErrNoDataPaths)
+	_, err = dm.performInitialLoad(ctx)
+	assert.ErrorIs(t, err, ErrNoDataPaths)
+}
@@ -0,0 +1,157 @@
+package orchestrator
+
+import (
+	"time"
+
+	"github.com/penwyp/claudecat/calculations"
+	"github.com/penwyp/claudecat/config"
+	"github.com/penwyp/claudecat/logging"
+	"github.com/penwyp/claudecat/models"
+	"github.com/penwyp/claudecat/statsd"
+	"github.com/penwyp/claudecat/timeutil"
+)
+
+// StatsdReporter pushes token, cost and burn rate gauges to a statsd or DogStatsD agent
+// after every refresh. Per-model and per-budget gauges are tagged with the model or budget
+// name; plain statsd appends the name to the metric instead.
+type StatsdReporter struct {
+	client   *statsd.Client
+	location *time.Location
+}
+
+// NewStatsdReporter creates a reporter from the statsd configuration, or returns nil when no
+// agent address is configured
+func NewStatsdReporter(cfg config.StatsdConfig, location *time.Location) (*StatsdReporter, error) {
+	if cfg.Address == "" {
+		return nil, nil
+	}
+	if location == nil {
+		location = time.Local
+	}
+
+	client, err := statsd.New(statsd.Options{
+		Address:   cfg.Address,
+		Prefix:    cfg.Prefix,
+		Tags:      cfg.Tags,
+		DogStatsD: cfg.Flavor != config.StatsdFlavorPlain,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &StatsdReporter{client: client, location: location}, nil
+}
+
+// Report sends the gauges of data, logging failures
+func (r *StatsdReporter) Report(data MonitoringData, now time.Time) {
+	if err := r.client.Send(r.Gauges(data, now)); err != nil {
+		logging.LogDebugf("statsd: %v", err)
+	}
+}
+
+// Gauges returns the metrics of the active session, today's usage and budgets at now
+func (r *StatsdReporter) Gauges(data MonitoringData, now time.Time) []statsd.Gauge {
+	gauges := []statsd.Gauge{{Name: "session.token_limit", Value: float64(data.TokenLimit)}}
+
+	var active *models.SessionBlock
+	for i := range data.Data.Blocks {
+		if block := &data.Data.Blocks[i]; block.IsActive && !block.IsGap {
+			active = block
+			break
+		}
+	}
+
+	var session struct {
+		tokens          int
+		cost            float64
+		tokensPerMinute float64
+		costPerHour     float64
+	}
+	if active != nil {
+		session.tokens, session.cost = active.TokenCounts.TotalTokens(), active.CostUSD
+		if burnRate := calculations.NewBurnRateCalculator().CalculateBurnRate(*active); burnRate != nil {
+			session.tokensPerMinute, session.costPerHour = burnRate.TokensPerMinute, burnRate.CostPerHour
+		}
+	}
+	gauges = append(gauges,
+		statsd.Gauge{Name: "session.active", Value: boolGauge(active != nil)},
+		statsd.Gauge{Name: "session.tokens", Value: float64(session.tokens)},
+		statsd.Gauge{Name: "session.cost_usd", Value: session.cost},
+		statsd.Gauge{Name: "session.tokens_per_minute", Value: session.tokensPerMinute},
+		statsd.Gauge{Name: "session.cost_per_hour", Value: session.costPerHour},
+	)
+	if active != nil {
+		gauges = append(gauges, modelGauges(active.Entries)...)
+	}
+
+	// Today's usage across sessions
+	dayStart, dayEnd := timeutil.DayBounds(now.In(r.location), r.location)
+	var todayTokens int
+	var todayCost float64
+	for _, block := range data.Data.Blocks {
+		if block.IsGap {
+			continue
+		}
+		for _, entry := range block.Entries {
+			if !entry.Timestamp.Before(dayStart) && entry.Timestamp.Before(dayEnd) {
+				todayTokens += entry.TotalTokens
+				todayCost += entry.CostUSD
+			}
+		}
+	}
+	gauges = append(gauges,
+		statsd.Gauge{Name: "today.tokens", Value: float64(todayTokens)},
+		statsd.Gauge{Name: "today.cost_usd", Value: todayCost},
+	)
+
+	for _, budget := range data.Budgets {
+		tags := map[string]string{"budget": budget.Budget.Name}
+		gauges = append(gauges,
+			statsd.Gauge{Name: "budget.cost_usd", Value: budget.Cost, Tags: tags},
+			statsd.Gauge{Name: "budget.tokens", Value: float64(budget.Tokens), Tags: tags},
+		)
+		if budget.Budget.CostLimit > 0 {
+			gauges = append(gauges, statsd.Gauge{Name: "budget.cost_percent", Value: budget.CostPercent, Tags: tags})
+		}
+		if budget.Budget.TokenLimit > 0 {
+			gauges = append(gauges, statsd.Gauge{Name: "budget.token_percent", Value: budget.TokenPercent, Tags: tags})
+		}
+	}
+	return gauges
+}
+
+// modelGauges returns the tokens and cost of entries per model, in order of first use
+func modelGauges(entries []models.UsageEntry) []statsd.Gauge {
+	type usage struct {
+		tokens int
+		cost   float64
+	}
+	var order []string
+	byModel := make(map[string]*usage)
+	for _, entry := range entries {
+		u, ok := byModel[entry.Model]
+		if !ok {
+			u = &usage{}
+			byModel[entry.Model] = u
+			order = append(order, entry.Model)
+		}
+		u.tokens += entry.TotalTokens
+		u.cost += entry.CostUSD
+	}
+
+	gauges := make([]statsd.Gauge, 0, 2*len(order))
+	for _, model := range order {
+		tags := map[string]string{"model": model}
+		gauges = append(gauges,
+			statsd.Gauge{Name: "session.model.tokens", Value: float64(byModel[model].tokens), Tags: tags},
+			statsd.Gauge{Name: "session.model.cost_usd", Value: byModel[model].cost, Tags: tags},
+		)
+	}
+	return gauges
+}
+
+func boolGauge(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
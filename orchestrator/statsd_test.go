@@ -0,0 +1,81 @@
+package orchestrator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/penwyp/claudecat/calculations"
+	"github.com/penwyp/claudecat/config"
+	"github.com/penwyp/claudecat/models"
+	"github.com/penwyp/claudecat/statsd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewStatsdReporter_Disabled(t *testing.T) {
+	reporter, err := NewStatsdReporter(config.DefaultConfig().Statsd, time.UTC)
+	assert.NoError(t, err)
+	assert.Nil(t, reporter)
+}
+
+func TestStatsdReporter_Gauges(t *testing.T) {
+	cfg := config.DefaultConfig().Statsd
+	cfg.Address = "127.0.0.1:8125"
+	reporter, err := NewStatsdReporter(cfg, time.UTC)
+	require.NoError(t, err)
+	require.NotNil(t, reporter)
+	defer reporter.client.Close()
+
+	now := time.Date(2026, 3, 10, 1, 0, 0, 0, time.UTC)
+	start := now.Add(-3 * time.Hour)
+	entries := []models.UsageEntry{
+		{Timestamp: start, Model: "claude-opus-4-20250514", TotalTokens: 1000, CostUSD: 1.5},
+		{Timestamp: now.Add(-30 * time.Minute), Model: "claude-sonnet-4-20250514", TotalTokens: 200, CostUSD: 0.1},
+	}
+	data := MonitoringData{
+		Data: AnalysisResult{Blocks: []models.SessionBlock{{
+			ID:          "s",
+			StartTime:   start,
+			EndTime:     start.Add(5 * time.Hour),
+			IsActive:    true,
+			Entries:     entries,
+			TokenCounts: models.TokenCounts{InputTokens: 1200},
+			CostUSD:     1.6,
+		}}},
+		TokenLimit: 44000,
+		Budgets: []calculations.BudgetStatus{{
+			Budget:      calculations.Budget{Name: "daily", CostLimit: 10},
+			Cost:        0.1,
+			CostPercent: 1,
+		}},
+	}
+
+	values := make(map[string]float64)
+	for _, gauge := range reporter.Gauges(data, now) {
+		name := gauge.Name
+		for _, key := range []string{"model", "budget"} {
+			if value, ok := gauge.Tags[key]; ok {
+				name += "{" + value + "}"
+			}
+		}
+		values[name] = gauge.Value
+	}
+
+	assert.Equal(t, 1.0, values["session.active"])
+	assert.Equal(t, 44000.0, values["session.token_limit"])
+	assert.Equal(t, 1200.0, values["session.tokens"])
+	assert.InDelta(t, 1.6, values["session.cost_usd"], 1e-9)
+	assert.Equal(t, 1000.0, values["session.model.tokens{claude-opus-4-20250514}"])
+	assert.InDelta(t, 0.1, values["session.model.cost_usd{claude-sonnet-4-20250514}"], 1e-9)
+	// The session started yesterday; today only counts the later entry
+	assert.Equal(t, 200.0, values["today.tokens"])
+	assert.Equal(t, 1.0, values["budget.cost_percent{daily}"])
+	assert.NotContains(t, values, "budget.token_percent{daily}")
+}
+
+func TestStatsdReporter_GaugesWithoutActiveSession(t *testing.T) {
+	reporter := &StatsdReporter{location: time.UTC}
+	gauges := reporter.Gauges(MonitoringData{}, time.Now())
+	assert.Contains(t, gauges, statsd.Gauge{Name: "session.active", Value: 0})
+	assert.Contains(t, gauges, statsd.Gauge{Name: "session.tokens", Value: 0})
+}
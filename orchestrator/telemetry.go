@@ -0,0 +1,35 @@
+package orchestrator
+
+import (
+	"context"
+	"time"
+
+	"github.com/penwyp/claudecat/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	tracer = telemetry.Tracer("orchestrator")
+
+	refreshDuration = telemetry.Float64Histogram("orchestrator", "claudecat.refresh.duration",
+		"Time taken by a monitoring refresh cycle, from loading data to notifying listeners", "s")
+)
+
+// recordRefresh ends the span of a refresh cycle and records its duration
+func recordRefresh(ctx context.Context, span trace.Span, start time.Time, result *MonitoringData, err error) {
+	outcome := attribute.String("outcome", "ok")
+	if err != nil {
+		outcome = attribute.String("outcome", "error")
+	}
+	refreshDuration.Record(ctx, telemetry.Since(start), metric.WithAttributes(outcome))
+
+	if result != nil {
+		span.SetAttributes(
+			attribute.Int("blocks", len(result.Data.Blocks)),
+			attribute.String("session_id", result.SessionID),
+		)
+	}
+	telemetry.End(span, err)
+}
@@ -0,0 +1,243 @@
+package output
+
+import (
+	"time"
+
+	"github.com/penwyp/claudecat/models"
+)
+
+// The types below mirror the JSON reports of ccusage (`ccusage daily --json` and friends),
+// so dashboards and scripts written against ccusage can read claudecat's reports unchanged.
+// ccusage has no separate thinking token count; thinking tokens are reported as output.
+
+// ccusageTimeLayout is the ISO 8601 form ccusage uses for block times
+const ccusageTimeLayout = "2006-01-02T15:04:05.000Z"
+
+// CCUsageTotals is the totals object of a ccusage report
+type CCUsageTotals struct {
+	InputTokens         int     `json:"inputTokens"`
+	OutputTokens        int     `json:"outputTokens"`
+	CacheCreationTokens int     `json:"cacheCreationTokens"`
+	CacheReadTokens     int     `json:"cacheReadTokens"`
+	TotalTokens         int     `json:"totalTokens"`
+	TotalCost           float64 `json:"totalCost"`
+}
+
+// CCUsageModelBreakdown is the usage of one model within a ccusage report row
+type CCUsageModelBreakdown struct {
+	ModelName           string  `json:"modelName"`
+	InputTokens         int     `json:"inputTokens"`
+	OutputTokens        int     `json:"outputTokens"`
+	CacheCreationTokens int     `json:"cacheCreationTokens"`
+	CacheReadTokens     int     `json:"cacheReadTokens"`
+	Cost                float64 `json:"cost"`
+}
+
+// CCUsageDay is one row of a ccusage daily report
+type CCUsageDay struct {
+	Date string `json:"date"`
+	CCUsageTotals
+	ModelsUsed      []string                `json:"modelsUsed"`
+	ModelBreakdowns []CCUsageModelBreakdown `json:"modelBreakdowns"`
+}
+
+// CCUsageMonth is one row of a ccusage monthly report
+type CCUsageMonth struct {
+	Month string `json:"month"`
+	CCUsageTotals
+	ModelsUsed      []string                `json:"modelsUsed"`
+	ModelBreakdowns []CCUsageModelBreakdown `json:"modelBreakdowns"`
+}
+
+// CCUsageSession is one row of a ccusage session report
+type CCUsageSession struct {
+	SessionID string `json:"sessionId"`
+	CCUsageTotals
+	LastActivity    string                  `json:"lastActivity"`
+	ModelsUsed      []string                `json:"modelsUsed"`
+	ModelBreakdowns []CCUsageModelBreakdown `json:"modelBreakdowns"`
+	ProjectPath     string                  `json:"projectPath"`
+}
+
+// CCUsageDailyReport is the output of `ccusage daily --json`
+type CCUsageDailyReport struct {
+	Daily  []CCUsageDay  `json:"daily"`
+	Totals CCUsageTotals `json:"totals"`
+}
+
+// CCUsageMonthlyReport is the output of `ccusage monthly --json`
+type CCUsageMonthlyReport struct {
+	Monthly []CCUsageMonth `json:"monthly"`
+	Totals  CCUsageTotals  `json:"totals"`
+}
+
+// CCUsageSessionReport is the output of `ccusage session --json`
+type CCUsageSessionReport struct {
+	Sessions []CCUsageSession `json:"sessions"`
+	Totals   CCUsageTotals    `json:"totals"`
+}
+
+// CCUsageBlockTokens are the token counts of a ccusage block
+type CCUsageBlockTokens struct {
+	InputTokens              int `json:"inputTokens"`
+	OutputTokens             int `json:"outputTokens"`
+	CacheCreationInputTokens int `json:"cacheCreationInputTokens"`
+	CacheReadInputTokens     int `json:"cacheReadInputTokens"`
+}
+
+// CCUsageBurnRate is the burn rate of an active ccusage block
+type CCUsageBurnRate struct {
+	TokensPerMinute float64 `json:"tokensPerMinute"`
+	CostPerHour     float64 `json:"costPerHour"`
+}
+
+// CCUsageProjection is the end-of-block projection of an active ccusage block
+type CCUsageProjection struct {
+	TotalTokens      int     `json:"totalTokens"`
+	TotalCost        float64 `json:"totalCost"`
+	RemainingMinutes float64 `json:"remainingMinutes"`
+}
+
+// CCUsageBlock is one 5-hour block of a ccusage blocks report. As in ccusage, TotalTokens
+// counts input and output tokens only.
+type CCUsageBlock struct {
+	ID            string             `json:"id"`
+	StartTime     string             `json:"startTime"`
+	EndTime       string             `json:"endTime"`
+	ActualEndTime *string            `json:"actualEndTime"`
+	IsActive      bool               `json:"isActive"`
+	IsGap         bool               `json:"isGap"`
+	Entries       int                `json:"entries"`
+	TokenCounts   CCUsageBlockTokens `json:"tokenCounts"`
+	TotalTokens   int                `json:"totalTokens"`
+	CostUSD       float64            `json:"costUSD"`
+	Models        []string           `json:"models"`
+	BurnRate      *CCUsageBurnRate   `json:"burnRate"`
+	Projection    *CCUsageProjection `json:"projection"`
+}
+
+// CCUsageBlocksReport is the output of `ccusage blocks --json`
+type CCUsageBlocksReport struct {
+	Blocks []CCUsageBlock `json:"blocks"`
+}
+
+// NewCCUsageDailyReport converts rows keyed by date into a ccusage daily report
+func NewCCUsageDailyReport(rows []ReportRow) CCUsageDailyReport {
+	report := CCUsageDailyReport{Daily: make([]CCUsageDay, 0, len(rows)), Totals: ccusageTotals(ReportTotals(rows))}
+	for _, row := range rows {
+		report.Daily = append(report.Daily, CCUsageDay{
+			Date:            row.Key,
+			CCUsageTotals:   ccusageTotals(row),
+			ModelsUsed:      row.ModelNames(),
+			ModelBreakdowns: ccusageBreakdowns(row.Models),
+		})
+	}
+	return report
+}
+
+// NewCCUsageMonthlyReport converts rows keyed by month into a ccusage monthly report
+func NewCCUsageMonthlyReport(rows []ReportRow) CCUsageMonthlyReport {
+	report := CCUsageMonthlyReport{Monthly: make([]CCUsageMonth, 0, len(rows)), Totals: ccusageTotals(ReportTotals(rows))}
+	for _, row := range rows {
+		report.Monthly = append(report.Monthly, CCUsageMonth{
+			Month:           row.Key,
+			CCUsageTotals:   ccusageTotals(row),
+			ModelsUsed:      row.ModelNames(),
+			ModelBreakdowns: ccusageBreakdowns(row.Models),
+		})
+	}
+	return report
+}
+
+// NewCCUsageSessionReport converts rows keyed by session ID into a ccusage session report.
+// Last activity dates are taken in location.
+func NewCCUsageSessionReport(rows []ReportRow, location *time.Location) CCUsageSessionReport {
+	report := CCUsageSessionReport{Sessions: make([]CCUsageSession, 0, len(rows)), Totals: ccusageTotals(ReportTotals(rows))}
+	for _, row := range rows {
+		report.Sessions = append(report.Sessions, CCUsageSession{
+			SessionID:       row.Key,
+			CCUsageTotals:   ccusageTotals(row),
+			LastActivity:    row.LastActivity.In(location).Format("2006-01-02"),
+			ModelsUsed:      row.ModelNames(),
+			ModelBreakdowns: ccusageBreakdowns(row.Models),
+			ProjectPath:     row.Project,
+		})
+	}
+	return report
+}
+
+// NewCCUsageBlocksReport converts session blocks into a ccusage blocks report
+func NewCCUsageBlocksReport(blocks []models.SessionBlock) CCUsageBlocksReport {
+	report := CCUsageBlocksReport{Blocks: make([]CCUsageBlock, 0, len(blocks))}
+	for _, block := range blocks {
+		tokens := CCUsageBlockTokens{
+			InputTokens:              block.TokenCounts.InputTokens,
+			OutputTokens:             block.TokenCounts.OutputTokens + block.TokenCounts.ThinkingTokens,
+			CacheCreationInputTokens: block.TokenCounts.CacheCreationTokens,
+			CacheReadInputTokens:     block.TokenCounts.CacheReadTokens,
+		}
+		ccBlock := CCUsageBlock{
+			ID:          block.StartTime.UTC().Format(ccusageTimeLayout),
+			StartTime:   block.StartTime.UTC().Format(ccusageTimeLayout),
+			EndTime:     block.EndTime.UTC().Format(ccusageTimeLayout),
+			IsActive:    block.IsActive,
+			IsGap:       block.IsGap,
+			Entries:     len(block.Entries),
+			TokenCounts: tokens,
+			TotalTokens: tokens.InputTokens + tokens.OutputTokens,
+			CostUSD:     block.CostUSD,
+			Models:      block.Models,
+		}
+		if block.IsGap {
+			ccBlock.ID = "gap-" + ccBlock.ID
+		}
+		if ccBlock.Models == nil {
+			ccBlock.Models = []string{}
+		}
+		if block.ActualEndTime != nil {
+			actualEnd := block.ActualEndTime.UTC().Format(ccusageTimeLayout)
+			ccBlock.ActualEndTime = &actualEnd
+		}
+		if block.IsActive && block.BurnRate != nil {
+			ccBlock.BurnRate = &CCUsageBurnRate{
+				TokensPerMinute: block.BurnRate.TokensPerMinute,
+				CostPerHour:     block.BurnRate.CostPerHour,
+			}
+		}
+		if block.IsActive && block.ProjectionData != nil {
+			ccBlock.Projection = &CCUsageProjection{
+				TotalTokens:      block.ProjectionData.ProjectedTotalTokens,
+				TotalCost:        block.ProjectionData.ProjectedTotalCost,
+				RemainingMinutes: block.ProjectionData.RemainingMinutes,
+			}
+		}
+		report.Blocks = append(report.Blocks, ccBlock)
+	}
+	return report
+}
+
+func ccusageTotals(row ReportRow) CCUsageTotals {
+	return CCUsageTotals{
+		InputTokens:         row.TokenCounts.InputTokens,
+		OutputTokens:        row.TokenCounts.OutputTokens + row.TokenCounts.ThinkingTokens,
+		CacheCreationTokens: row.TokenCounts.CacheCreationTokens,
+		CacheReadTokens:     row.TokenCounts.CacheReadTokens,
+		TotalTokens:         row.TotalTokens,
+		TotalCost:           row.CostUSD,
+	}
+}
+
+func ccusageBreakdowns(usage []ReportModelUsage) []CCUsageModelBreakdown {
+	breakdowns := make([]CCUsageModelBreakdown, 0, len(usage))
+	for _, model := range usage {
+		breakdowns = append(breakdowns, CCUsageModelBreakdown{
+			ModelName:           model.Model,
+			InputTokens:         model.TokenCounts.InputTokens,
+			OutputTokens:        model.TokenCounts.OutputTokens + model.TokenCounts.ThinkingTokens,
+			CacheCreationTokens: model.TokenCounts.CacheCreationTokens,
+			CacheReadTokens:     model.TokenCounts.CacheReadTokens,
+			Cost:                model.CostUSD,
+		})
+	}
+	return breakdowns
+}
@@ -0,0 +1,117 @@
+package output
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/penwyp/claudecat/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func reportEntries() []models.UsageEntry {
+	day := time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC)
+	return []models.UsageEntry{
+		{Timestamp: day, Model: "claude-sonnet-4-20250514", SessionID: "s1", Project: "api",
+			InputTokens: 1000, OutputTokens: 400, ThinkingTokens: 100, TotalTokens: 1500, CostUSD: 0.01},
+		{Timestamp: day.Add(time.Hour), Model: "claude-opus-4-20250514", SessionID: "s1", Project: "web",
+			InputTokens: 2000, OutputTokens: 700, CacheReadTokens: 300, TotalTokens: 3000, CostUSD: 0.08,
+			CacheSavingsUSD: 0.004},
+		{Timestamp: day.AddDate(0, 0, 1), Model: "claude-sonnet-4-20250514", SessionID: "s2",
+			InputTokens: 100, OutputTokens: 50, TotalTokens: 150, CostUSD: 0.001},
+	}
+}
+
+func dayKey(entry models.UsageEntry) string { return entry.Timestamp.Format("2006-01-02") }
+
+func TestGroupUsage(t *testing.T) {
+	rows := GroupUsage(reportEntries(), func(entry models.UsageEntry) string { return entry.SessionID })
+	require.Len(t, rows, 2)
+
+	assert.Equal(t, "s1", rows[0].Key)
+	assert.Equal(t, "web", rows[0].Project, "project of the latest entry")
+	assert.Equal(t, 4500, rows[0].TotalTokens)
+	assert.Equal(t, 2, rows[0].Entries)
+	assert.Equal(t, []string{"claude-opus-4-20250514", "claude-sonnet-4-20250514"}, rows[0].ModelNames())
+
+	totals := ReportTotals(rows)
+	assert.Equal(t, 4650, totals.TotalTokens)
+	assert.Equal(t, 3100, totals.TokenCounts.InputTokens)
+	assert.InDelta(t, 0.091, totals.CostUSD, 1e-9)
+	assert.InDelta(t, 0.004, totals.CacheSavingsUSD, 1e-9)
+	assert.InDelta(t, 0.004, rows[0].Models[0].CacheSavingsUSD, 1e-9, "opus is the most expensive model")
+}
+
+func TestNewCCUsageDailyReport(t *testing.T) {
+	body, err := json.Marshal(NewCCUsageDailyReport(GroupUsage(reportEntries(), dayKey)))
+	require.NoError(t, err)
+
+	var report map[string]interface{}
+	require.NoError(t, json.Unmarshal(body, &report))
+
+	daily := report["daily"].([]interface{})
+	require.Len(t, daily, 2)
+	first := daily[0].(map[string]interface{})
+	assert.Equal(t, "2025-06-02", first["date"])
+	assert.EqualValues(t, 1200, first["outputTokens"], "thinking tokens count as output")
+	assert.EqualValues(t, 4500, first["totalTokens"])
+	assert.Equal(t, []interface{}{"claude-opus-4-20250514", "claude-sonnet-4-20250514"}, first["modelsUsed"])
+
+	breakdown := first["modelBreakdowns"].([]interface{})[0].(map[string]interface{})
+	assert.ElementsMatch(t, []string{"modelName", "inputTokens", "outputTokens", "cacheCreationTokens", "cacheReadTokens", "cost"}, keys(breakdown))
+
+	totals := report["totals"].(map[string]interface{})
+	assert.ElementsMatch(t, []string{"inputTokens", "outputTokens", "cacheCreationTokens", "cacheReadTokens", "totalTokens", "totalCost"}, keys(totals))
+	assert.EqualValues(t, 4650, totals["totalTokens"])
+}
+
+func TestNewCCUsageSessionReport(t *testing.T) {
+	location := time.FixedZone("UTC-12", -12*3600)
+	report := NewCCUsageSessionReport(GroupUsage(reportEntries(), func(entry models.UsageEntry) string { return entry.SessionID }), location)
+
+	require.Len(t, report.Sessions, 2)
+	assert.Equal(t, "s1", report.Sessions[0].SessionID)
+	assert.Equal(t, "2025-06-01", report.Sessions[0].LastActivity)
+	assert.Equal(t, "web", report.Sessions[0].ProjectPath)
+}
+
+func TestNewCCUsageBlocksReport(t *testing.T) {
+	start := time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC)
+	lastEntry := start.Add(90 * time.Minute)
+	blocks := []models.SessionBlock{
+		{StartTime: start, EndTime: start.Add(5 * time.Hour), ActualEndTime: &lastEntry, Entries: make([]models.UsageEntry, 2),
+			TokenCounts: models.TokenCounts{InputTokens: 3000, OutputTokens: 1100, ThinkingTokens: 100, CacheReadTokens: 300},
+			CostUSD:     0.09, Models: []string{"claude-opus-4-20250514"}},
+		{IsGap: true, StartTime: lastEntry, EndTime: lastEntry.Add(8 * time.Hour)},
+		{StartTime: start.AddDate(0, 0, 1), EndTime: start.AddDate(0, 0, 1).Add(5 * time.Hour), IsActive: true,
+			BurnRate:       &models.BurnRate{TokensPerMinute: 12.5, CostPerHour: 0.4},
+			ProjectionData: &models.UsageProjection{ProjectedTotalTokens: 9000, ProjectedTotalCost: 1.2, RemainingMinutes: 42}},
+	}
+
+	report := NewCCUsageBlocksReport(blocks)
+	require.Len(t, report.Blocks, 3)
+
+	completed := report.Blocks[0]
+	assert.Equal(t, "2025-06-02T10:00:00.000Z", completed.ID)
+	require.NotNil(t, completed.ActualEndTime)
+	assert.Equal(t, "2025-06-02T11:30:00.000Z", *completed.ActualEndTime)
+	assert.Equal(t, 4200, completed.TotalTokens, "ccusage counts input and output tokens only")
+	assert.Nil(t, completed.BurnRate)
+
+	assert.Equal(t, "gap-2025-06-02T11:30:00.000Z", report.Blocks[1].ID)
+	assert.Equal(t, []string{}, report.Blocks[1].Models)
+
+	active := report.Blocks[2]
+	require.NotNil(t, active.BurnRate)
+	require.NotNil(t, active.Projection)
+	assert.Equal(t, 9000, active.Projection.TotalTokens)
+}
+
+func keys(m map[string]interface{}) []string {
+	result := make([]string, 0, len(m))
+	for key := range m {
+		result = append(result, key)
+	}
+	return result
+}
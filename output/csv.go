@@ -0,0 +1,121 @@
+package output
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/penwyp/claudecat/models"
+)
+
+// UsageEntryCSVHeader is the header row written by WriteUsageEntriesCSV
+var UsageEntryCSVHeader = []string{
+	"timestamp", "session_id", "project", "model", "message_id", "request_id",
+	"input_tokens", "output_tokens", "cache_creation_tokens", "cache_read_tokens", "thinking_tokens",
+	"total_tokens", "cost_usd", "cost_source", "tag",
+}
+
+// SessionBlockCSVHeader is the header row written by WriteSessionBlocksCSV
+var SessionBlockCSVHeader = []string{
+	"block_id", "start_time", "end_time", "actual_end_time", "is_active", "is_gap",
+	"entries", "sent_messages",
+	"input_tokens", "output_tokens", "cache_creation_tokens", "cache_read_tokens", "thinking_tokens",
+	"total_tokens", "cost_usd", "tokens_per_minute", "cost_per_hour", "models",
+}
+
+// WriteUsageEntriesCSV writes one row per usage entry after a header row.
+// Timestamps are RFC 3339 in UTC so spreadsheets and BI tools parse them unambiguously.
+func WriteUsageEntriesCSV(w io.Writer, entries []models.UsageEntry) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(UsageEntryCSVHeader); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	for _, entry := range entries {
+		record := []string{
+			formatCSVTime(entry.Timestamp),
+			entry.SessionID,
+			entry.Project,
+			entry.Model,
+			entry.MessageID,
+			entry.RequestID,
+			strconv.Itoa(entry.InputTokens),
+			strconv.Itoa(entry.OutputTokens),
+			strconv.Itoa(entry.CacheCreationTokens),
+			strconv.Itoa(entry.CacheReadTokens),
+			strconv.Itoa(entry.ThinkingTokens),
+			strconv.Itoa(entry.TotalTokens),
+			formatCSVFloat(entry.CostUSD),
+			entry.CostSource,
+			entry.Tag,
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("failed to write CSV record: %w", err)
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteSessionBlocksCSV writes one aggregated row per session block after a header row.
+// Models are joined with ";" so the column stays a single CSV field.
+func WriteSessionBlocksCSV(w io.Writer, blocks []models.SessionBlock) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(SessionBlockCSVHeader); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	for _, block := range blocks {
+		actualEnd := ""
+		if block.ActualEndTime != nil {
+			actualEnd = formatCSVTime(*block.ActualEndTime)
+		}
+		tokensPerMinute, costPerHour := "", ""
+		if block.BurnRate != nil {
+			tokensPerMinute = formatCSVFloat(block.BurnRate.TokensPerMinute)
+			costPerHour = formatCSVFloat(block.BurnRate.CostPerHour)
+		}
+
+		record := []string{
+			block.ID,
+			formatCSVTime(block.StartTime),
+			formatCSVTime(block.EndTime),
+			actualEnd,
+			strconv.FormatBool(block.IsActive),
+			strconv.FormatBool(block.IsGap),
+			strconv.Itoa(len(block.Entries)),
+			strconv.Itoa(block.SentMessagesCount),
+			strconv.Itoa(block.TokenCounts.InputTokens),
+			strconv.Itoa(block.TokenCounts.OutputTokens),
+			strconv.Itoa(block.TokenCounts.CacheCreationTokens),
+			strconv.Itoa(block.TokenCounts.CacheReadTokens),
+			strconv.Itoa(block.TokenCounts.ThinkingTokens),
+			strconv.Itoa(block.TokenCounts.TotalTokens()),
+			formatCSVFloat(block.CostUSD),
+			tokensPerMinute,
+			costPerHour,
+			strings.Join(block.Models, ";"),
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("failed to write CSV record: %w", err)
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func formatCSVTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func formatCSVFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', 6, 64)
+}
@@ -0,0 +1,77 @@
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"github.com/penwyp/claudecat/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteUsageEntriesCSV(t *testing.T) {
+	entries := []models.UsageEntry{
+		{
+			Timestamp:    time.Date(2025, 6, 1, 10, 0, 0, 0, time.FixedZone("CEST", 2*60*60)),
+			SessionID:    "s1",
+			Project:      "api, v2",
+			Model:        "claude-sonnet-4-20250514",
+			MessageID:    "m1",
+			RequestID:    "r1",
+			InputTokens:  100,
+			OutputTokens: 50,
+			TotalTokens:  150,
+			CostUSD:      0.00105,
+			CostSource:   "calculated",
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteUsageEntriesCSV(&buf, entries))
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, UsageEntryCSVHeader, records[0])
+	assert.Equal(t, []string{
+		"2025-06-01T08:00:00Z", "s1", "api, v2", "claude-sonnet-4-20250514", "m1", "r1",
+		"100", "50", "0", "0", "0", "150", "0.001050", "calculated", "",
+	}, records[1])
+}
+
+func TestWriteSessionBlocksCSV(t *testing.T) {
+	start := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	actualEnd := start.Add(90 * time.Minute)
+	blocks := []models.SessionBlock{
+		{
+			ID:                "b1",
+			StartTime:         start,
+			EndTime:           start.Add(5 * time.Hour),
+			ActualEndTime:     &actualEnd,
+			Entries:           make([]models.UsageEntry, 3),
+			SentMessagesCount: 3,
+			TokenCounts:       models.TokenCounts{InputTokens: 1000, OutputTokens: 500},
+			CostUSD:           1.5,
+			BurnRate:          &models.BurnRate{TokensPerMinute: 16.5, CostPerHour: 1},
+			Models:            []string{"opus-4", "sonnet-4"},
+		},
+		{ID: "gap", StartTime: start.Add(5 * time.Hour), EndTime: start.Add(8 * time.Hour), IsGap: true},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteSessionBlocksCSV(&buf, blocks))
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, SessionBlockCSVHeader, records[0])
+	assert.Equal(t, []string{
+		"b1", "2025-06-01T10:00:00Z", "2025-06-01T15:00:00Z", "2025-06-01T11:30:00Z", "false", "false",
+		"3", "3", "1000", "500", "0", "0", "0", "1500", "1.500000", "16.500000", "1.000000", "opus-4;sonnet-4",
+	}, records[1])
+	assert.Equal(t, "", records[2][3], "missing actual end time is empty")
+	assert.Equal(t, "true", records[2][5])
+	assert.Equal(t, "", records[2][15], "missing burn rate is empty")
+}
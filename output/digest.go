@@ -0,0 +1,236 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/penwyp/claudecat/models"
+)
+
+// digestColor is the accent color of Discord digest embeds
+const digestColor = 0xD97757
+
+// DigestItem is one model or project in a digest's top list
+type DigestItem struct {
+	Name        string  `json:"name"`
+	TotalTokens int     `json:"total_tokens"`
+	CostUSD     float64 `json:"cost_usd"`
+}
+
+// Digest summarizes the usage of a period for posting to a chat channel
+type Digest struct {
+	Title       string       `json:"title"`
+	Start       time.Time    `json:"start"`
+	End         time.Time    `json:"end"`
+	TotalTokens int          `json:"total_tokens"`
+	CostUSD     float64      `json:"cost_usd"`
+	Entries     int          `json:"entries"`
+	Sessions    int          `json:"sessions"`
+	TopModels   []DigestItem `json:"top_models"`
+	TopProjects []DigestItem `json:"top_projects"`
+}
+
+// NewDigest summarizes the entries of blocks timestamped in [start, end), listing at most
+// topN models and projects by tokens. A session counts when any of its entries does.
+func NewDigest(title string, blocks []models.SessionBlock, start, end time.Time, topN int) Digest {
+	digest := Digest{Title: title, Start: start, End: end}
+	modelUsage := make(map[string]*DigestItem)
+	projectUsage := make(map[string]*DigestItem)
+
+	for _, block := range blocks {
+		if block.IsGap {
+			continue
+		}
+		counted := false
+		for _, entry := range block.Entries {
+			if entry.Timestamp.Before(start) || !entry.Timestamp.Before(end) {
+				continue
+			}
+			if !counted {
+				digest.Sessions++
+				counted = true
+			}
+			digest.Entries++
+			digest.TotalTokens += entry.TotalTokens
+			digest.CostUSD += entry.CostUSD
+			addDigestUsage(modelUsage, models.NormalizeModelName(entry.Model), entry)
+			addDigestUsage(projectUsage, entry.Project, entry)
+		}
+	}
+
+	digest.TopModels = topDigestItems(modelUsage, topN)
+	digest.TopProjects = topDigestItems(projectUsage, topN)
+	return digest
+}
+
+func addDigestUsage(usage map[string]*DigestItem, name string, entry models.UsageEntry) {
+	if name == "" {
+		name = "unknown"
+	}
+	item, ok := usage[name]
+	if !ok {
+		item = &DigestItem{Name: name}
+		usage[name] = item
+	}
+	item.TotalTokens += entry.TotalTokens
+	item.CostUSD += entry.CostUSD
+}
+
+// topDigestItems returns the n items with the most tokens, ties broken by name
+func topDigestItems(usage map[string]*DigestItem, n int) []DigestItem {
+	items := make([]DigestItem, 0, len(usage))
+	for _, item := range usage {
+		items = append(items, *item)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].TotalTokens != items[j].TotalTokens {
+			return items[i].TotalTokens > items[j].TotalTokens
+		}
+		return items[i].Name < items[j].Name
+	})
+	if len(items) > n {
+		items = items[:n]
+	}
+	return items
+}
+
+// DigestFormatter renders digests as Slack or Discord webhook payloads
+type DigestFormatter struct {
+	format     string
+	location   *time.Location
+	timeFormat string
+}
+
+// NewDigestFormatter creates a formatter for the given format (slack or discord). Times
+// are shown in location, on a 12-hour clock only when timeFormat is 12h.
+func NewDigestFormatter(format string, location *time.Location, timeFormat string) (*DigestFormatter, error) {
+	if format != "slack" && format != "discord" {
+		return nil, fmt.Errorf("unsupported digest format: %s", format)
+	}
+	if location == nil {
+		location = time.Local
+	}
+	return &DigestFormatter{format: format, location: location, timeFormat: timeFormat}, nil
+}
+
+// Format renders the webhook request body for d
+func (f *DigestFormatter) Format(d Digest) ([]byte, error) {
+	var payload interface{}
+	if f.format == "slack" {
+		payload = f.slackPayload(d)
+	} else {
+		payload = f.discordPayload(d)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode digest: %w", err)
+	}
+	return body, nil
+}
+
+// slackPayload renders d as Block Kit blocks, with a plain text fallback for notifications
+func (f *DigestFormatter) slackPayload(d Digest) map[string]interface{} {
+	mrkdwn := func(text string) map[string]interface{} {
+		return map[string]interface{}{"type": "mrkdwn", "text": text}
+	}
+
+	blocks := []map[string]interface{}{
+		{"type": "header", "text": map[string]interface{}{"type": "plain_text", "text": d.Title}},
+		{"type": "context", "elements": []interface{}{mrkdwn(f.period(d))}},
+		{"type": "section", "fields": []interface{}{
+			mrkdwn("*Tokens*\n" + formatThousands(d.TotalTokens)),
+			mrkdwn(fmt.Sprintf("*Cost*\n$%.2f", d.CostUSD)),
+			mrkdwn(fmt.Sprintf("*Sessions*\n%d", d.Sessions)),
+			mrkdwn(fmt.Sprintf("*Messages*\n%d", d.Entries)),
+		}},
+		{"type": "section", "text": mrkdwn("*Top models*\n" + digestList(d.TopModels))},
+		{"type": "section", "text": mrkdwn("*Top projects*\n" + digestList(d.TopProjects))},
+	}
+
+	return map[string]interface{}{
+		"text":   f.summary(d),
+		"blocks": blocks,
+	}
+}
+
+// discordPayload renders d as a single embed
+func (f *DigestFormatter) discordPayload(d Digest) map[string]interface{} {
+	field := func(name, value string, inline bool) map[string]interface{} {
+		return map[string]interface{}{"name": name, "value": value, "inline": inline}
+	}
+
+	embed := map[string]interface{}{
+		"title":       d.Title,
+		"description": f.period(d),
+		"color":       digestColor,
+		"timestamp":   d.End.UTC().Format(time.RFC3339),
+		"fields": []interface{}{
+			field("Tokens", formatThousands(d.TotalTokens), true),
+			field("Cost", fmt.Sprintf("$%.2f", d.CostUSD), true),
+			field("Sessions", fmt.Sprintf("%d", d.Sessions), true),
+			field("Top models", digestList(d.TopModels), false),
+			field("Top projects", digestList(d.TopProjects), false),
+		},
+	}
+
+	return map[string]interface{}{
+		"content": f.summary(d),
+		"embeds":  []interface{}{embed},
+	}
+}
+
+// summary is the one-line text shown in chat notifications
+func (f *DigestFormatter) summary(d Digest) string {
+	return fmt.Sprintf("%s: %s tokens, $%.2f across %d sessions", d.Title, formatThousands(d.TotalTokens), d.CostUSD, d.Sessions)
+}
+
+// period describes the time range of d
+func (f *DigestFormatter) period(d Digest) string {
+	start, end := d.Start.In(f.location), d.End.In(f.location)
+	clock := "15:04"
+	if f.timeFormat == "12h" {
+		clock = "3:04 PM"
+	}
+
+	// Whole days are shown by date only
+	if start.Equal(time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, f.location)) &&
+		end.Equal(start.AddDate(0, 0, 1)) {
+		return start.Format("Monday, Jan 2, 2006")
+	}
+	if start.YearDay() == end.YearDay() && start.Year() == end.Year() {
+		return fmt.Sprintf("%s, %s – %s", start.Format("Jan 2"), start.Format(clock), end.Format(clock))
+	}
+	return fmt.Sprintf("%s %s – %s %s", start.Format("Jan 2"), start.Format(clock), end.Format("Jan 2"), end.Format(clock))
+}
+
+// digestList renders a top list as bullet lines; both Slack and Discord accept this markup
+func digestList(items []DigestItem) string {
+	if len(items) == 0 {
+		return "No usage"
+	}
+	lines := make([]string, 0, len(items))
+	for _, item := range items {
+		lines = append(lines, fmt.Sprintf("• %s: %s tokens, $%.2f", item.Name, formatThousands(item.TotalTokens), item.CostUSD))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatThousands formats n with comma thousands separators
+func formatThousands(n int) string {
+	if n < 0 {
+		return "-" + formatThousands(-n)
+	}
+	str := fmt.Sprintf("%d", n)
+	var b strings.Builder
+	for i, digit := range str {
+		if i > 0 && (len(str)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(digit)
+	}
+	return b.String()
+}
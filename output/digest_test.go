@@ -0,0 +1,101 @@
+package output
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/penwyp/claudecat/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func digestBlocks(day time.Time) []models.SessionBlock {
+	entry := func(offset time.Duration, model, project string, tokens int, cost float64) models.UsageEntry {
+		return models.UsageEntry{Timestamp: day.Add(offset), Model: model, Project: project, TotalTokens: tokens, CostUSD: cost}
+	}
+	return []models.SessionBlock{
+		{ID: "before", Entries: []models.UsageEntry{
+			entry(-time.Hour, "claude-opus-4-20250514", "old", 9999, 9),
+		}},
+		{ID: "morning", Entries: []models.UsageEntry{
+			entry(-30*time.Minute, "claude-opus-4-20250514", "old", 9999, 9),
+			entry(9*time.Hour, "claude-sonnet-4-20250514", "api", 1000, 0.5),
+			entry(10*time.Hour, "claude-opus-4-20250514", "web", 3000, 2),
+		}},
+		{ID: "gap", IsGap: true},
+		{ID: "evening", Entries: []models.UsageEntry{
+			entry(20*time.Hour, "claude-sonnet-4-20250514", "api", 1500, 0.25),
+			entry(24*time.Hour, "claude-sonnet-4-20250514", "api", 9999, 9),
+		}},
+	}
+}
+
+func TestNewDigest(t *testing.T) {
+	day := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+	digest := NewDigest("Daily", digestBlocks(day), day, day.AddDate(0, 0, 1), 1)
+
+	assert.Equal(t, 5500, digest.TotalTokens)
+	assert.InDelta(t, 2.75, digest.CostUSD, 1e-9)
+	assert.Equal(t, 3, digest.Entries)
+	assert.Equal(t, 2, digest.Sessions)
+	assert.Equal(t, []DigestItem{{Name: "claude-opus-4-20250514", TotalTokens: 3000, CostUSD: 2}}, digest.TopModels)
+	assert.Equal(t, []DigestItem{{Name: "web", TotalTokens: 3000, CostUSD: 2}}, digest.TopProjects)
+}
+
+func TestDigestFormatter_Slack(t *testing.T) {
+	day := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+	formatter, err := NewDigestFormatter("slack", time.UTC, "24h")
+	require.NoError(t, err)
+
+	body, err := formatter.Format(NewDigest("Claude usage for Mar 9", digestBlocks(day), day, day.AddDate(0, 0, 1), 5))
+	require.NoError(t, err)
+
+	var payload struct {
+		Text   string `json:"text"`
+		Blocks []struct {
+			Type     string `json:"type"`
+			Elements []struct {
+				Text string `json:"text"`
+			} `json:"elements"`
+			Text struct {
+				Text string `json:"text"`
+			} `json:"text"`
+		} `json:"blocks"`
+	}
+	require.NoError(t, json.Unmarshal(body, &payload))
+	assert.Equal(t, "Claude usage for Mar 9: 5,500 tokens, $2.75 across 2 sessions", payload.Text)
+	require.Len(t, payload.Blocks, 5)
+	assert.Equal(t, "header", payload.Blocks[0].Type)
+	assert.Equal(t, "Monday, Mar 9, 2026", payload.Blocks[1].Elements[0].Text)
+	assert.Equal(t, "*Top projects*\n• web: 3,000 tokens, $2.00\n• api: 2,500 tokens, $0.75", payload.Blocks[4].Text.Text)
+}
+
+func TestDigestFormatter_Discord(t *testing.T) {
+	start := time.Date(2026, 3, 9, 9, 0, 0, 0, time.UTC)
+	formatter, err := NewDigestFormatter("discord", time.UTC, "12h")
+	require.NoError(t, err)
+
+	body, err := formatter.Format(Digest{Title: "Claude session ended", Start: start, End: start.Add(2 * time.Hour)})
+	require.NoError(t, err)
+
+	var payload struct {
+		Embeds []struct {
+			Description string `json:"description"`
+			Fields      []struct {
+				Name  string `json:"name"`
+				Value string `json:"value"`
+			} `json:"fields"`
+		} `json:"embeds"`
+	}
+	require.NoError(t, json.Unmarshal(body, &payload))
+	require.Len(t, payload.Embeds, 1)
+	assert.Equal(t, "Mar 9, 9:00 AM – 11:00 AM", payload.Embeds[0].Description)
+	require.Len(t, payload.Embeds[0].Fields, 5)
+	assert.Equal(t, "No usage", payload.Embeds[0].Fields[3].Value, "Discord rejects empty field values")
+}
+
+func TestNewDigestFormatter_UnknownFormat(t *testing.T) {
+	_, err := NewDigestFormatter("teams", time.UTC, "")
+	assert.Error(t, err)
+}
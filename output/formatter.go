@@ -2,48 +2,154 @@ package output
 
 import (
 	"fmt"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/penwyp/claudecat/calculations"
+	"github.com/penwyp/claudecat/config"
+	"github.com/penwyp/claudecat/fileio"
 	"github.com/penwyp/claudecat/models"
+	"github.com/penwyp/claudecat/timeutil"
 )
 
 // ConsoleFormatter formats data for console output
 type ConsoleFormatter struct {
 	plan             string
+	plans            *models.PlanCatalog
 	timezone         string
+	location         *time.Location
 	timeFormat       string
 	tokenLimit       int
 	costLimitP90     float64
 	messagesLimitP90 int
 	p90Calculator    *calculations.P90Calculator
+
+	// Limit overrides for custom and enterprise plans
+	tokenLimitOverride int
+	costLimitOverride  float64
+	tokenLimitP90      bool
+	limitEstimate      *calculations.P90Estimate   // Set while the token limit comes from past sessions
+	observedLimit      *calculations.ObservedLimit // Set while the token limit comes from limit messages
+
+	sessionDuration time.Duration
+	banners         []string
+	rateHistory     *calculations.RateHistory   // Burn and cost rates of the last hour, for the trend sparklines
+	fields          []string                    // Console fields shown, in display order
+	rolling         *calculations.RollingTotals // Usage of the last 7 and 30 days, shown in the footer
+
+	// Guards the settings, which can be changed by a configuration reload while rendering
+	mu sync.Mutex
 }
 
+// rateHistoryWindow is how far back the burn rate and cost rate sparklines reach
+const rateHistoryWindow = time.Hour
+
+// sparklineWidth is the number of bars in the trend sparklines, one per minute
+const sparklineWidth = 60
+
+// bytesPerMB converts byte counts for the load progress line
+const bytesPerMB = 1 << 20
+
 // NewConsoleFormatter creates a new console formatter
 func NewConsoleFormatter(plan, timezone, timeFormat string) *ConsoleFormatter {
-	if timezone == "" || timezone == "auto" {
-		timezone = "Asia/Shanghai"
-	}
+	f := &ConsoleFormatter{
+		plan:            strings.ToLower(plan),
+		plans:           models.DefaultPlanCatalog(),
+		p90Calculator:   calculations.NewP90Calculator(),
+		sessionDuration: models.SessionDuration,
+		rateHistory:     calculations.NewRateHistory(rateHistoryWindow, time.Minute),
+		fields:          append([]string(nil), config.DefaultConsoleFields...),
+	}
+	f.SetTimeSettings(timezone, timeFormat)
+	return f
+}
+
+// SetPlan sets the subscription plan the limits are derived from
+func (f *ConsoleFormatter) SetPlan(plan string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.plan = strings.ToLower(plan)
+}
+
+// SetPlanCatalog sets the catalog the limits of fixed plans are taken from
+func (f *ConsoleFormatter) SetPlanCatalog(plans *models.PlanCatalog) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.plans = plans
+}
+
+// SetTimeSettings sets the timezone and time format (12h or 24h) of displayed times.
+// An empty, "auto" or invalid timezone uses the system zone; an empty or "auto" format uses 24h.
+func (f *ConsoleFormatter) SetTimeSettings(timezone, timeFormat string) {
+	loc, _ := timeutil.LoadLocation(timezone)
 	if timeFormat == "" || timeFormat == "auto" {
 		timeFormat = "24h"
 	}
 
-	return &ConsoleFormatter{
-		plan:          strings.ToLower(plan),
-		timezone:      timezone,
-		timeFormat:    timeFormat,
-		p90Calculator: calculations.NewP90Calculator(),
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.timezone = loc.String()
+	f.location = loc
+	f.timeFormat = timeFormat
+}
+
+// SetSessionDuration sets the session window length used for the time-to-reset display
+func (f *ConsoleFormatter) SetSessionDuration(duration time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if duration > 0 {
+		f.sessionDuration = duration
 	}
 }
 
+// SetBanner sets the warnings shown below the header, such as budget and cost alerts.
+// Empty warnings are skipped; none hides the banner.
+func (f *ConsoleFormatter) SetBanner(banners ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.banners = f.banners[:0]
+	for _, banner := range banners {
+		if banner != "" {
+			f.banners = append(f.banners, banner)
+		}
+	}
+}
+
+// SetRollingTotals sets the usage of the rolling 7 and 30 day windows shown in the footer
+func (f *ConsoleFormatter) SetRollingTotals(totals calculations.RollingTotals) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.rolling = &totals
+}
+
+// SetLimitOverrides overrides the plan-derived limits. A positive tokenLimit or costLimit
+// replaces the plan value; useP90 derives the token limit from past sessions instead.
+func (f *ConsoleFormatter) SetLimitOverrides(tokenLimit int, costLimit float64, useP90 bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.tokenLimitOverride = tokenLimit
+	f.costLimitOverride = costLimit
+	f.tokenLimitP90 = useP90
+}
+
 // Format formats the monitoring data for console output
 func (f *ConsoleFormatter) Format(metrics *calculations.RealtimeMetrics, blocks []models.SessionBlock) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
 	f.updateLimits(blocks)
 
 	var lines []string
 	lines = append(lines, f.renderHeader()...)
 	lines = append(lines, "")
+	if len(f.banners) > 0 {
+		for _, banner := range f.banners {
+			lines = append(lines, fmt.Sprintf("🚨 %s", banner))
+		}
+		lines = append(lines, "")
+	}
 
 	// Check if there's an active session
 	hasActiveSession := false
@@ -67,6 +173,40 @@ func (f *ConsoleFormatter) Format(metrics *calculations.RealtimeMetrics, blocks
 	return strings.Join(lines, "\n")
 }
 
+// FormatLoadProgress renders a one-line progress bar for the initial data load
+func (f *ConsoleFormatter) FormatLoadProgress(progress fileio.LoadProgress) string {
+	percentage := progress.Fraction() * 100
+	line := fmt.Sprintf("📂 Loading usage data %s %5.1f%%  %d/%d files",
+		f.renderWideProgressBar(percentage, ""), percentage, progress.ProcessedFiles, progress.TotalFiles)
+	if progress.TotalBytes > 0 {
+		line += fmt.Sprintf("  %.1f/%.1f MB", float64(progress.ProcessedBytes)/bytesPerMB, float64(progress.TotalBytes)/bytesPerMB)
+	}
+	return line
+}
+
+// FormatWaitingForData renders the first-run screen shown while none of the searched data
+// locations exists yet
+func (f *ConsoleFormatter) FormatWaitingForData(searched []string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	lines := append(f.renderHeader(),
+		"",
+		"👋 No Claude Code usage data found yet. Looked in:",
+	)
+	for _, path := range searched {
+		lines = append(lines, "   • "+path)
+	}
+	lines = append(lines,
+		"",
+		"⏳ Waiting for data to appear; monitoring starts automatically once it does.",
+		fmt.Sprintf("💡 Data stored elsewhere? Run claudecat --paths /path/to/claude/projects or set %s.", fileio.ClaudeConfigDirEnv),
+		"",
+		fmt.Sprintf("⏰ %s 📝 Waiting for data", f.formatTime(time.Now())),
+	)
+	return strings.Join(lines, "\n")
+}
+
 // renderHeader renders the header section
 func (f *ConsoleFormatter) renderHeader() []string {
 	sparkles := "✦ ✧ ✦ ✧"
@@ -78,11 +218,25 @@ func (f *ConsoleFormatter) renderHeader() []string {
 		plan = "pro"
 	}
 
-	return []string{
+	info := fmt.Sprintf("[ %s | %s ]", plan, strings.ToLower(f.timezone))
+	if f.limitEstimate != nil {
+		info = fmt.Sprintf("[ %s | %s | limit %s tokens, %s ]", plan, strings.ToLower(f.timezone),
+			f.formatNumberWithCommas(f.limitEstimate.Limit), f.limitEstimate.Description())
+	}
+	if f.observedLimit != nil {
+		info = fmt.Sprintf("[ %s | %s | limit %s tokens, from limit messages ]", plan, strings.ToLower(f.timezone),
+			f.formatNumberWithCommas(f.observedLimit.TokenLimit))
+	}
+
+	lines := []string{
 		fmt.Sprintf("%s %s %s", sparkles, title, sparkles),
 		separator,
-		fmt.Sprintf("[ %s | %s ]", plan, strings.ToLower(f.timezone)),
+		info,
+	}
+	if f.observedLimit != nil && f.observedLimit.ConflictsWith(plan, f.plans) {
+		lines = append(lines, "⚠️  Plan mismatch: "+f.observedLimit.Warning(plan))
 	}
+	return lines
 }
 
 // renderNoActiveSession renders the display when there's no active session
@@ -116,154 +270,101 @@ func (f *ConsoleFormatter) renderNoActiveSession(metrics *calculations.RealtimeM
 	}
 
 	// Progress bar
-	progressBar := f.renderWideProgressBar(tokenUsage, "🟨")
-	lines = append(lines, fmt.Sprintf("📊 Token Usage:    %s", progressBar))
-	lines = append(lines, "")
+	if f.hasField("tokens") {
+		progressBar := f.renderWideProgressBar(tokenUsage, "🟨")
+		lines = append(lines, fmt.Sprintf("📊 Token Usage:    %s", progressBar))
+		lines = append(lines, "")
+	}
 
 	// Stats - show actual values if any tokens were used
-	if tokensUsed > 0 {
+	if f.hasField("tokens") && tokensUsed > 0 {
 		lines = append(lines, fmt.Sprintf("🎯 Tokens:         %s / ~%s (%s left)",
 			f.formatNumber(tokensUsed),
 			f.formatNumber(f.tokenLimit),
 			f.formatNumber(f.tokenLimit-tokensUsed)))
+	} else if f.hasField("tokens") {
+		lines = append(lines, fmt.Sprintf("🎯 Tokens:         0 / ~%s (0 left)", f.formatNumber(f.tokenLimit)))
+	}
+	if f.hasField("cost") {
 		lines = append(lines, fmt.Sprintf("💲 Session Cost:   $%.2f", costUsed))
+	}
+	if f.hasField("messages") {
 		lines = append(lines, fmt.Sprintf("📨 Sent Messages:  %d messages", messagesUsed))
-	} else {
-		lines = append(lines, fmt.Sprintf("🎯 Tokens:         0 / ~%s (0 left)", f.formatNumber(f.tokenLimit)))
-		lines = append(lines, "💲 Session Cost:   $0.00")
-		lines = append(lines, "📨 Sent Messages:  0 messages")
 	}
 
-	lines = append(lines, "🔥 Burn Rate:      0.0 tokens/min")
-	lines = append(lines, "💵 Cost Rate:      $0.00 $/min")
+	if f.hasField("burn_rate") {
+		lines = append(lines, "🔥 Burn Rate:      0.0 tokens/min")
+	}
+	if f.hasField("cost_rate") {
+		lines = append(lines, "💵 Cost Rate:      $0.00 $/min")
+	}
 	lines = append(lines, "")
 
 	return lines
 }
 
-// renderActiveSession renders the display for an active session
+// renderActiveSession renders the display for an active session, laid out from the
+// configured console fields
 func (f *ConsoleFormatter) renderActiveSession(metrics *calculations.RealtimeMetrics, blocks []models.SessionBlock) []string {
-	var lines []string
-
-	// Calculate burn rate
-	burnRate := f.calculateBurnRate(blocks)
-
-	// Calculate percentages
-	tokenUsage := float64(metrics.CurrentTokens) / float64(f.tokenLimit) * 100
-	costUsage := metrics.CurrentCost / f.costLimitP90 * 100
-
-	// Get message count from current active session
-	messageCount := 0
-	if len(blocks) > 0 {
-		for _, block := range blocks {
-			if block.IsActive {
-				messageCount = block.SentMessagesCount
-				break
-			}
+	view := &sessionView{metrics: metrics}
+	for i := range blocks {
+		if blocks[i].IsActive {
+			view.block = &blocks[i]
+			break
 		}
 	}
-	messagesUsage := float64(messageCount) / float64(f.messagesLimitP90) * 100
 
-	// Time calculations
-	sessionStart := metrics.SessionStart
-	if sessionStart.IsZero() && len(blocks) > 0 {
-		for _, block := range blocks {
-			if block.IsActive {
-				sessionStart = block.StartTime
-				break
-			}
-		}
+	view.sessionStart = metrics.SessionStart
+	if view.sessionStart.IsZero() && view.block != nil {
+		view.sessionStart = view.block.StartTime
 	}
 
-	elapsed := time.Since(sessionStart).Minutes()
-	totalMinutes := 300.0 // 5 hours
-	timePercentage := (elapsed / totalMinutes) * 100
-	timeRemaining := totalMinutes - elapsed
+	// Rates are recorded even when hidden, so the trends have history once shown
+	view.burnRate = f.calculateBurnRate(blocks)
+	view.costRate = f.calculateCostRate(metrics)
+	f.rateHistory.Record(time.Now(), view.burnRate, view.costRate)
 
+	lines := []string{"", ""}
+	lines = append(lines, f.layoutFields(view)...)
 	lines = append(lines, "")
-	lines = append(lines, "")
-
-	// Cost Usage
-	costIndicator := f.getColorIndicator(costUsage)
-	costBar := f.renderWideProgressBar(costUsage, "")
-	lines = append(lines, fmt.Sprintf("💰 Cost Usage:           %s %s %5.1f%%    $%.2f / $%.2f",
-		costIndicator, costBar, costUsage, metrics.CurrentCost, f.costLimitP90))
-	lines = append(lines, "")
-
-	// Token Usage
-	tokenIndicator := f.getColorIndicator(tokenUsage)
-	tokenBar := f.renderWideProgressBar(tokenUsage, "")
-	lines = append(lines, fmt.Sprintf("📊 Token Usage:          %s %s %5.1f%%    %s / %s",
-		tokenIndicator, tokenBar, tokenUsage,
-		f.formatNumberWithCommas(metrics.CurrentTokens),
-		f.formatNumberWithCommas(f.tokenLimit)))
-	lines = append(lines, "")
-
-	// Messages Usage
-	messagesIndicator := f.getColorIndicator(messagesUsage)
-	messagesBar := f.renderWideProgressBar(messagesUsage, "")
-	lines = append(lines, fmt.Sprintf("📨 Messages Usage:       %s %s %5.1f%%    %d / %s",
-		messagesIndicator, messagesBar, messagesUsage, messageCount,
-		f.formatNumberWithCommas(f.messagesLimitP90)))
-	lines = append(lines, strings.Repeat("─", 60))
-
-	// Time to Reset
-	timeIndicator := f.getColorIndicator(timePercentage)
-	timeBar := f.renderWideProgressBar(timePercentage, "")
-	hours := int(timeRemaining / 60)
-	mins := int(timeRemaining) % 60
-	lines = append(lines, fmt.Sprintf("⏱️  Time to Reset:       %s %s %dh %dm",
-		timeIndicator, timeBar, hours, mins))
-	lines = append(lines, "")
-
-	// Model Distribution
-	modelBar := f.renderModelDistributionSimple(metrics)
-	lines = append(lines, fmt.Sprintf("🤖 Model Distribution:   🤖 %s", modelBar))
-	lines = append(lines, strings.Repeat("─", 60))
+	return lines
+}
 
-	// Burn Rate with appropriate emoji
-	emoji := "🐌"
-	if burnRate > 100 {
-		emoji = "🚀"
-	} else if burnRate > 50 {
-		emoji = "🏃"
+// renderRateTrends renders sparklines of the burn rate and cost rate over the last hour,
+// once there are at least two minutes of history
+func (f *ConsoleFormatter) renderRateTrends() []string {
+	tokenRates := f.rateHistory.TokenRates()
+	if len(tokenRates) < 2 {
+		return nil
 	}
-	lines = append(lines, fmt.Sprintf("🔥 Burn Rate:              %.1f tokens/min %s", burnRate, emoji))
+	costRates := f.rateHistory.CostRates()
 
-	// Cost Rate
-	costRate := f.calculateCostRate(metrics)
-	lines = append(lines, fmt.Sprintf("💲 Cost Rate:              $%.4f $/min", costRate))
-
-	lines = append(lines, "")
-	lines = append(lines, "🔮 Predictions:")
-
-	// Calculate when tokens will run out
-	if burnRate > 0 {
-		minutesUntilOut := float64(f.tokenLimit-metrics.CurrentTokens) / burnRate
-		runOutTime := time.Now().Add(time.Duration(minutesUntilOut) * time.Minute)
-		lines = append(lines, fmt.Sprintf("   Tokens will run out: %s", f.formatTimeShort(runOutTime)))
-	} else {
-		lines = append(lines, "   Tokens will run out: --:--")
+	return []string{
+		fmt.Sprintf("📈 Burn Rate (1h):         %s %s", sparkline(tokenRates, sparklineWidth), describeTrend(tokenRates)),
+		fmt.Sprintf("📈 Cost Rate (1h):         %s %s", sparkline(costRates, sparklineWidth), describeTrend(costRates)),
 	}
-
-	// Reset time
-	resetTime := sessionStart.Add(5 * time.Hour)
-	lines = append(lines, fmt.Sprintf("   Limit resets at:     %s", f.formatTimeShort(resetTime)))
-	lines = append(lines, "")
-
-	return lines
 }
 
-// renderFooter renders the footer
+// renderFooter renders the footer: the rolling window totals, once set, and the time
 func (f *ConsoleFormatter) renderFooter(hasActiveSession bool) string {
 	currentTime := f.formatTime(time.Now())
-	
+
 	statusText := "No active session"
 	if hasActiveSession {
 		statusText = "Active session"
 	}
 
-	return fmt.Sprintf("⏰ %s 📝 %s", currentTime, statusText)
+	footer := fmt.Sprintf("⏰ %s 📝 %s", currentTime, statusText)
+	if f.rolling != nil {
+		// Until the history is loaded, the 30-day total would be short
+		month := "loading…"
+		if !f.rolling.Partial {
+			month = fmt.Sprintf("%s tokens, $%.2f", f.formatNumber(f.rolling.Last30Days.Tokens), f.rolling.Last30Days.Cost)
+		}
+		footer = fmt.Sprintf("📅 Last 7 days: %s tokens, $%.2f · Last 30 days: %s\n%s",
+			f.formatNumber(f.rolling.Last7Days.Tokens), f.rolling.Last7Days.Cost, month, footer)
+	}
+	return footer
 }
 
 // renderWideProgressBar renders a 50-character wide progress bar
@@ -334,6 +435,73 @@ func (f *ConsoleFormatter) renderModelDistributionSimple(metrics *calculations.R
 	return fmt.Sprintf("[%s] %s %.1f%%", bar, displayName, maxPercentage)
 }
 
+// renderDepletion renders when tokens run out and a bar of the usage projected at reset
+func (f *ConsoleFormatter) renderDepletion(depletion *models.TokenDepletion) []string {
+	if depletion == nil {
+		return []string{"   Tokens will run out: --:--"}
+	}
+
+	var runOut string
+	switch {
+	case depletion.Exhausted:
+		runOut = "now"
+	case depletion.DepletionTime == nil:
+		runOut = "--:--"
+	case depletion.RunsOutBeforeReset:
+		minutes := int(depletion.TimeToDepletion.Minutes())
+		runOut = fmt.Sprintf("%s (in %dh %dm)", f.formatTimeShort(*depletion.DepletionTime), minutes/60, minutes%60)
+	default:
+		runOut = fmt.Sprintf("%s (after reset)", f.formatTimeShort(*depletion.DepletionTime))
+	}
+
+	indicator := f.getColorIndicator(depletion.ProjectedPercent)
+	bar := f.renderWideProgressBar(depletion.ProjectedPercent, "")
+	return []string{
+		fmt.Sprintf("   Tokens will run out: %s", runOut),
+		fmt.Sprintf("   Projected at reset:  %s %s %5.1f%%    ~%s / %s",
+			indicator, bar, depletion.ProjectedPercent,
+			f.formatNumberWithCommas(depletion.ProjectedTokens),
+			f.formatNumberWithCommas(depletion.TokenLimit)),
+	}
+}
+
+// maxProjectLines is how many projects the console shows before summarizing the rest
+const maxProjectLines = 5
+
+// renderProjectDistribution renders the projects with the most tokens in the current session
+func (f *ConsoleFormatter) renderProjectDistribution(metrics *calculations.RealtimeMetrics) []string {
+	if metrics == nil || len(metrics.ProjectDistribution) == 0 {
+		return nil
+	}
+
+	projects := make([]string, 0, len(metrics.ProjectDistribution))
+	for project := range metrics.ProjectDistribution {
+		projects = append(projects, project)
+	}
+	sort.Slice(projects, func(i, j int) bool {
+		a, b := metrics.ProjectDistribution[projects[i]], metrics.ProjectDistribution[projects[j]]
+		if a.TokenCount != b.TokenCount {
+			return a.TokenCount > b.TokenCount
+		}
+		return projects[i] < projects[j]
+	})
+
+	var lines []string
+	for i, project := range projects {
+		if i == maxProjectLines {
+			lines = append(lines, fmt.Sprintf("   … and %d more", len(projects)-maxProjectLines))
+			break
+		}
+		projectMetrics := metrics.ProjectDistribution[project]
+		lines = append(lines, fmt.Sprintf("   %-28s %12s tokens  $%8.2f  %5.1f%%",
+			truncateString(project, 28),
+			f.formatNumberWithCommas(projectMetrics.TokenCount),
+			projectMetrics.Cost,
+			projectMetrics.Percentage))
+	}
+	return lines
+}
+
 // getColorIndicator returns the appropriate color indicator based on percentage
 func (f *ConsoleFormatter) getColorIndicator(percentage float64) string {
 	if percentage < 50 {
@@ -398,14 +566,19 @@ func (f *ConsoleFormatter) formatNumberWithCommas(n int) string {
 	return result
 }
 
+// truncateString shortens s to at most max runes, marking the cut with an ellipsis
+func truncateString(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max-1]) + "…"
+}
+
 // formatTime formats time according to the configured format
 func (f *ConsoleFormatter) formatTime(t time.Time) string {
 	// Convert to configured timezone
-	loc, err := time.LoadLocation(f.timezone)
-	if err != nil {
-		loc = time.UTC
-	}
-	t = t.In(loc)
+	t = t.In(f.location)
 
 	if f.timeFormat == "24h" {
 		return t.Format("15:04:05")
@@ -416,11 +589,7 @@ func (f *ConsoleFormatter) formatTime(t time.Time) string {
 // formatTimeShort formats time in short format (HH:MM)
 func (f *ConsoleFormatter) formatTimeShort(t time.Time) string {
 	// Convert to configured timezone
-	loc, err := time.LoadLocation(f.timezone)
-	if err != nil {
-		loc = time.UTC
-	}
-	t = t.In(loc)
+	t = t.In(f.location)
 
 	if f.timeFormat == "24h" {
 		return t.Format("15:04")
@@ -430,30 +599,45 @@ func (f *ConsoleFormatter) formatTimeShort(t time.Time) string {
 
 // updateLimits updates the limits based on plan or P90 calculations
 func (f *ConsoleFormatter) updateLimits(blocks []models.SessionBlock) {
+	f.limitEstimate = nil
+	f.observedLimit = nil
+
 	// Calculate P90 limits if on custom plan
-	if f.plan == "custom" && f.p90Calculator != nil {
-		f.tokenLimit = f.p90Calculator.CalculateP90Limit(blocks, true)
+	if f.plan == models.PlanCustom && f.p90Calculator != nil {
+		f.setP90TokenLimit(blocks)
 		f.costLimitP90 = f.p90Calculator.GetCostP90(blocks)
 		f.messagesLimitP90 = f.p90Calculator.GetMessagesP90(blocks)
 	} else {
 		// Set fixed limits based on plan
-		switch f.plan {
-		case "pro":
-			f.tokenLimit = 1000000
-			f.costLimitP90 = 18.0
-			f.messagesLimitP90 = 1500
-		case "max5":
-			f.tokenLimit = 88000
-			f.costLimitP90 = 35.0
-			f.messagesLimitP90 = 1000
-		case "max20":
-			f.tokenLimit = 8000000
-			f.costLimitP90 = 140.0
-			f.messagesLimitP90 = 12000
-		default:
-			f.tokenLimit = 1000000
-			f.costLimitP90 = 18.0
-			f.messagesLimitP90 = 1500
-		}
+		limits := f.plans.Limits(f.plan)
+		f.tokenLimit = limits.TokenLimit
+		f.costLimitP90 = limits.CostLimit
+		f.messagesLimitP90 = limits.MessageLimit
+	}
+
+	// Apply user overrides on top of the plan limits
+	if f.tokenLimitP90 && f.p90Calculator != nil {
+		f.setP90TokenLimit(blocks)
+	}
+	// The limit Claude states in its limit messages beats the plan and estimates
+	if observed, ok := calculations.DetectObservedLimit(blocks, f.plans); ok {
+		f.tokenLimit = observed.TokenLimit
+		f.limitEstimate = nil
+		f.observedLimit = &observed
+	}
+	if f.tokenLimitOverride > 0 {
+		f.tokenLimit = f.tokenLimitOverride
+		f.limitEstimate = nil
+		f.observedLimit = nil
+	}
+	if f.costLimitOverride > 0 {
+		f.costLimitP90 = f.costLimitOverride
 	}
-}
\ No newline at end of file
+}
+
+// setP90TokenLimit derives the token limit from past sessions and remembers how it was derived
+func (f *ConsoleFormatter) setP90TokenLimit(blocks []models.SessionBlock) {
+	estimate := f.p90Calculator.EstimateLimit(blocks, true)
+	f.tokenLimit = estimate.Limit
+	f.limitEstimate = &estimate
+}
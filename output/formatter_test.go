@@ -0,0 +1,280 @@
+package output
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/penwyp/claudecat/calculations"
+	"github.com/penwyp/claudecat/config"
+	"github.com/penwyp/claudecat/fileio"
+	"github.com/penwyp/claudecat/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsoleFormatter_RenderProjectDistribution(t *testing.T) {
+	f := NewConsoleFormatter("pro", "UTC", "24h")
+
+	assert.Nil(t, f.renderProjectDistribution(nil))
+	assert.Nil(t, f.renderProjectDistribution(&calculations.RealtimeMetrics{}))
+
+	metrics := &calculations.RealtimeMetrics{
+		ProjectDistribution: map[string]calculations.ProjectMetrics{
+			"small":  {TokenCount: 100, Cost: 0.01, Percentage: 1},
+			"large":  {TokenCount: 90000, Cost: 1.5, Percentage: 90},
+			"medium": {TokenCount: 9900, Cost: 0.2, Percentage: 9},
+		},
+	}
+	lines := f.renderProjectDistribution(metrics)
+	require.Len(t, lines, 3)
+	assert.True(t, strings.Contains(lines[0], "large"))
+	assert.True(t, strings.Contains(lines[0], "90,000 tokens"))
+	assert.True(t, strings.Contains(lines[0], "$    1.50"))
+	assert.True(t, strings.Contains(lines[1], "medium"))
+	assert.True(t, strings.Contains(lines[2], "small"))
+}
+
+func TestConsoleFormatter_RenderProjectDistributionTruncates(t *testing.T) {
+	f := NewConsoleFormatter("pro", "UTC", "24h")
+
+	metrics := &calculations.RealtimeMetrics{ProjectDistribution: map[string]calculations.ProjectMetrics{}}
+	for i, name := range []string{"a", "b", "c", "d", "e", "f", "g"} {
+		metrics.ProjectDistribution[name] = calculations.ProjectMetrics{TokenCount: 100 - i}
+	}
+	metrics.ProjectDistribution[strings.Repeat("x", 40)] = calculations.ProjectMetrics{TokenCount: 1000}
+
+	lines := f.renderProjectDistribution(metrics)
+	require.Len(t, lines, maxProjectLines+1)
+	assert.Contains(t, lines[0], strings.Repeat("x", 27)+"…")
+	assert.Contains(t, lines[maxProjectLines], "and 3 more")
+}
+
+func TestConsoleFormatter_RenderSourcesField(t *testing.T) {
+	f := NewConsoleFormatter("pro", "UTC", "24h")
+	entries := []models.UsageEntry{
+		{Source: models.SourceClaude, InputTokens: 1000, CostUSD: 3},
+		{Source: models.SourceCodex, InputTokens: 500, OutputTokens: 500, CostUSD: 1},
+		{Source: models.SourceClaude, OutputTokens: 2000, CostUSD: 4},
+	}
+
+	single := &calculations.RealtimeMetrics{SourceDistribution: calculations.AggregateBySource(entries[:1])}
+	assert.Nil(t, f.renderSourcesField(&sessionView{metrics: single}), "a single source isn't broken down")
+
+	metrics := &calculations.RealtimeMetrics{SourceDistribution: calculations.AggregateBySource(entries)}
+	require.Len(t, metrics.SourceDistribution, 2)
+	claude := metrics.SourceDistribution[0]
+	assert.Equal(t, models.SourceClaude, claude.Source, "the most expensive source comes first")
+	assert.Equal(t, 3000, claude.TotalTokens)
+	assert.Equal(t, 2, claude.EntryCount)
+	assert.InDelta(t, 87.5, claude.CostPercentage, 1e-9)
+
+	lines := f.renderSourcesField(&sessionView{metrics: metrics})
+	require.Len(t, lines, 3)
+	assert.Contains(t, lines[1], "claude")
+	assert.Contains(t, lines[1], "3,000 tokens")
+	assert.Contains(t, lines[1], "$    7.00")
+	assert.Contains(t, lines[2], "codex")
+	assert.Contains(t, lines[2], "12.5%")
+}
+
+func TestConsoleFormatter_RenderDepletion(t *testing.T) {
+	f := NewConsoleFormatter("pro", "UTC", "24h")
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	brc := calculations.NewBurnRateCalculator()
+
+	assert.Equal(t, []string{"   Tokens will run out: --:--"}, f.renderDepletion(nil))
+
+	lines := f.renderDepletion(brc.ProjectDepletion(40_000, 100_000, 1000, now.Add(2*time.Hour), now))
+	require.Len(t, lines, 2)
+	assert.Equal(t, "   Tokens will run out: 13:00 (in 1h 0m)", lines[0])
+	assert.Contains(t, lines[1], "🔴")
+	assert.Contains(t, lines[1], "160.0%")
+	assert.Contains(t, lines[1], "~160,000 / 100,000")
+
+	lines = f.renderDepletion(brc.ProjectDepletion(40_000, 100_000, 100, now.Add(2*time.Hour), now))
+	assert.Equal(t, "   Tokens will run out: 22:00 (after reset)", lines[0])
+	assert.Contains(t, lines[1], "🟡")
+
+	lines = f.renderDepletion(&models.TokenDepletion{TokenLimit: 100, TokensUsed: 100, Exhausted: true, ProjectedTokens: 100, ProjectedPercent: 100})
+	assert.Equal(t, "   Tokens will run out: now", lines[0])
+}
+
+func TestConsoleFormatter_HeaderShowsP90Limit(t *testing.T) {
+	start := time.Now().Add(-12 * time.Hour)
+	block := models.SessionBlock{
+		StartTime: start,
+		EndTime:   start.Add(5 * time.Hour),
+		Entries:   []models.UsageEntry{{Timestamp: start.Add(time.Minute), TotalTokens: 120000}},
+		LimitMessages: []models.LimitMessage{
+			{Type: "system_limit", Timestamp: start.Add(2 * time.Minute)},
+		},
+	}
+
+	f := NewConsoleFormatter("custom", "UTC", "24h")
+	f.updateLimits([]models.SessionBlock{block})
+	assert.Equal(t, 120000, f.tokenLimit)
+	assert.Contains(t, f.renderHeader()[2], "limit 120,000 tokens, P90 of 1 limit hits")
+
+	// An explicit limit replaces the estimate
+	f.SetLimitOverrides(50000, 0, false)
+	f.updateLimits([]models.SessionBlock{block})
+	assert.Equal(t, 50000, f.tokenLimit)
+	assert.Equal(t, "[ custom | utc ]", f.renderHeader()[2])
+
+	// Fixed plans keep their documented limit
+	f = NewConsoleFormatter("pro", "UTC", "24h")
+	f.updateLimits([]models.SessionBlock{block})
+	assert.Equal(t, 1000000, f.tokenLimit)
+	assert.Equal(t, "[ pro | utc ]", f.renderHeader()[2])
+
+	// Configured plans and overrides come from the catalog
+	f = NewConsoleFormatter("enterprise", "UTC", "24h")
+	f.SetPlanCatalog(models.NewPlanCatalog(map[string]models.PlanLimits{
+		"enterprise": {TokenLimit: 5000000, CostLimit: 500, MessageLimit: 9000},
+	}))
+	f.updateLimits([]models.SessionBlock{block})
+	assert.Equal(t, 5000000, f.tokenLimit)
+	assert.Equal(t, 500.0, f.costLimitP90)
+	assert.Equal(t, 9000, f.messagesLimitP90)
+}
+
+func TestConsoleFormatter_ObservedLimit(t *testing.T) {
+	start := time.Now().Add(-time.Hour)
+	block := models.SessionBlock{
+		StartTime: start,
+		EndTime:   start.Add(5 * time.Hour),
+		Entries:   []models.UsageEntry{{Timestamp: start.Add(time.Minute), TotalTokens: 7900000}},
+		LimitMessages: []models.LimitMessage{
+			{Type: "system_limit", Timestamp: start.Add(2 * time.Minute), Message: "Usage limit reached: 7,900,000 of 8,000,000 tokens"},
+		},
+	}
+
+	f := NewConsoleFormatter("pro", "UTC", "24h")
+	f.updateLimits([]models.SessionBlock{block})
+	assert.Equal(t, 8000000, f.tokenLimit, "the stated limit beats the plan's")
+	header := f.renderHeader()
+	require.Len(t, header, 4)
+	assert.Contains(t, header[2], "limit 8,000,000 tokens, from limit messages")
+	assert.Contains(t, header[3], "which is the max20 plan, but the configured plan is pro")
+
+	f.SetPlan("max20")
+	f.updateLimits([]models.SessionBlock{block})
+	assert.Len(t, f.renderHeader(), 3, "no warning when the plan agrees")
+
+	f.SetLimitOverrides(50000, 0, false)
+	f.updateLimits([]models.SessionBlock{block})
+	assert.Equal(t, 50000, f.tokenLimit, "an explicit limit still wins")
+}
+
+func TestSparkline(t *testing.T) {
+	assert.Equal(t, "", sparkline(nil, 10))
+	assert.Equal(t, "▁▁▁", sparkline([]float64{0, 0, 0}, 10))
+	assert.Equal(t, "▁▄█", sparkline([]float64{0, 50, 100}, 10))
+	// Only the newest values fit
+	assert.Equal(t, "▄█", sparkline([]float64{100, 50, 100}, 2))
+}
+
+func TestConsoleFormatter_RenderRateTrends(t *testing.T) {
+	f := NewConsoleFormatter("pro", "UTC", "24h")
+	start := time.Now().Add(-10 * time.Minute)
+
+	f.rateHistory.Record(start, 100, 0.01)
+	assert.Nil(t, f.renderRateTrends())
+
+	f.rateHistory.Record(start.Add(time.Minute), 100, 0.01)
+	f.rateHistory.Record(start.Add(2*time.Minute), 300, 0.01)
+	f.rateHistory.Record(start.Add(3*time.Minute), 300, 0.01)
+	lines := f.renderRateTrends()
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[0], "▃▃██")
+	assert.Contains(t, lines[0], "accelerating (+200%)")
+	assert.Contains(t, lines[1], "steady")
+}
+
+func TestConsoleFormatter_FormatWaitingForData(t *testing.T) {
+	f := NewConsoleFormatter("pro", "UTC", "24h")
+
+	screen := f.FormatWaitingForData([]string{"/home/me/.config/claude/projects", "/home/me/.claude/projects"})
+	assert.Contains(t, screen, "CLAUDE CODE USAGE MONITOR")
+	assert.Contains(t, screen, "• /home/me/.config/claude/projects")
+	assert.Contains(t, screen, "• /home/me/.claude/projects")
+	assert.Contains(t, screen, "monitoring starts automatically")
+	assert.Contains(t, screen, fileio.ClaudeConfigDirEnv)
+}
+
+func TestConsoleFormatter_FormatLoadProgress(t *testing.T) {
+	f := NewConsoleFormatter("pro", "UTC", "24h")
+
+	line := f.FormatLoadProgress(fileio.LoadProgress{
+		TotalFiles:     40,
+		ProcessedFiles: 10,
+		TotalBytes:     8 << 20,
+		ProcessedBytes: 4 << 20,
+	})
+	assert.Contains(t, line, " 50.0%")
+	assert.Contains(t, line, "10/40 files")
+	assert.Contains(t, line, "4.0/8.0 MB")
+	assert.Equal(t, 25, strings.Count(line, "█"))
+
+	// Without file sizes the bar follows the file count
+	line = f.FormatLoadProgress(fileio.LoadProgress{TotalFiles: 4, ProcessedFiles: 1})
+	assert.Contains(t, line, " 25.0%")
+	assert.NotContains(t, line, "MB")
+}
+
+func TestConsoleFormatter_RollingTotalsFooter(t *testing.T) {
+	f := NewConsoleFormatter("pro", "UTC", "24h")
+	assert.NotContains(t, f.Format(nil, nil), "Last 7 days")
+
+	f.SetRollingTotals(calculations.RollingTotals{
+		Last7Days:  calculations.UsageTotal{Tokens: 12_300_000, Cost: 45.678},
+		Last30Days: calculations.UsageTotal{Tokens: 48_100_000, Cost: 180.2},
+	})
+	screen := f.Format(nil, nil)
+	assert.Contains(t, screen, "📅 Last 7 days: 12M tokens, $45.68 · Last 30 days: 48M tokens, $180.20")
+	assert.True(t, strings.HasPrefix(screen[strings.LastIndex(screen, "\n")+1:], "⏰"), "the time stays last")
+
+	f.SetRollingTotals(calculations.RollingTotals{Last7Days: calculations.UsageTotal{Tokens: 1000, Cost: 1}, Partial: true})
+	assert.Contains(t, f.Format(nil, nil), "Last 30 days: loading…")
+}
+
+func TestConsoleFormatter_Fields(t *testing.T) {
+	start := time.Now().Add(-time.Hour)
+	block := models.SessionBlock{
+		StartTime: start, EndTime: start.Add(5 * time.Hour), IsActive: true,
+		TokenCounts: models.TokenCounts{InputTokens: 1000, CacheCreationTokens: 1000, CacheReadTokens: 8000},
+		Entries:     []models.UsageEntry{{CacheSavingsUSD: 0.5}, {CacheSavingsUSD: 0.25}},
+	}
+	metrics := &calculations.RealtimeMetrics{SessionStart: start, CurrentTokens: 10000, CurrentCost: 1}
+
+	f := NewConsoleFormatter("pro", "UTC", "24h")
+	screen := f.Format(metrics, []models.SessionBlock{block})
+	assert.Contains(t, screen, "💰 Cost Usage:")
+	assert.Contains(t, screen, "🔮 Predictions:")
+	assert.NotContains(t, screen, "💾 Cache Tokens:", "cache tokens are not shown by default")
+
+	f.SetFields([]string{"cache", "unknown", "burn_rate", "tokens"})
+	screen = f.Format(metrics, []models.SessionBlock{block})
+	assert.Contains(t, screen, "💾 Cache Tokens:         1,000 written, 8,000 read (80.0% of input from cache), saved $0.75")
+	assert.NotContains(t, screen, "Cost Usage")
+	assert.NotContains(t, screen, "Predictions")
+	cache := strings.Index(screen, "Cache Tokens")
+	burn := strings.Index(screen, "Burn Rate")
+	tokens := strings.Index(screen, "Token Usage")
+	assert.True(t, cache < burn && burn < tokens, "fields follow the configured order")
+	assert.Contains(t, screen[cache:burn], strings.Repeat("─", 60), "a rule separates sections")
+
+	// Idle screens show the configured fields too
+	screen = f.Format(nil, nil)
+	assert.Contains(t, screen, "🔥 Burn Rate:")
+	assert.Contains(t, screen, "🎯 Tokens:")
+	assert.NotContains(t, screen, "Session Cost")
+
+	f.SetFields(nil)
+	assert.Equal(t, config.DefaultConsoleFields, f.fields)
+
+	for _, name := range config.ConsoleFields {
+		assert.Contains(t, consoleFields, name, "every configurable field has a renderer")
+	}
+}
@@ -0,0 +1,226 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/penwyp/claudecat/calculations"
+	"github.com/penwyp/claudecat/config"
+	"github.com/penwyp/claudecat/models"
+	"github.com/penwyp/claudecat/timeutil"
+)
+
+// consoleSection groups related console fields. Fields of one section are kept together;
+// a change of section starts a new part of the display.
+type consoleSection int
+
+const (
+	sectionUsage consoleSection = iota
+	sectionSession
+	sectionRates
+	sectionPredictions
+)
+
+// consoleField renders one part of the active session display
+type consoleField struct {
+	section consoleSection
+	render  func(f *ConsoleFormatter, view *sessionView) []string
+}
+
+// consoleFields maps the names in config.ConsoleFields to their renderers
+var consoleFields = map[string]consoleField{
+	"cost":        {sectionUsage, (*ConsoleFormatter).renderCostField},
+	"tokens":      {sectionUsage, (*ConsoleFormatter).renderTokensField},
+	"messages":    {sectionUsage, (*ConsoleFormatter).renderMessagesField},
+	"cache":       {sectionUsage, (*ConsoleFormatter).renderCacheField},
+	"time":        {sectionSession, (*ConsoleFormatter).renderTimeField},
+	"models":      {sectionSession, (*ConsoleFormatter).renderModelsField},
+	"projects":    {sectionSession, (*ConsoleFormatter).renderProjectsField},
+	"sources":     {sectionSession, (*ConsoleFormatter).renderSourcesField},
+	"burn_rate":   {sectionRates, (*ConsoleFormatter).renderBurnRateField},
+	"cost_rate":   {sectionRates, (*ConsoleFormatter).renderCostRateField},
+	"trends":      {sectionRates, (*ConsoleFormatter).renderTrendsField},
+	"predictions": {sectionPredictions, (*ConsoleFormatter).renderPredictionsField},
+}
+
+// sessionView holds what the fields of the active session display are computed from
+type sessionView struct {
+	metrics      *calculations.RealtimeMetrics
+	block        *models.SessionBlock // The active block
+	sessionStart time.Time
+	burnRate     float64 // Tokens per minute
+	costRate     float64 // Dollars per minute
+}
+
+// SetFields sets the console fields shown, in display order. Unknown names are skipped;
+// no fields restores the default layout.
+func (f *ConsoleFormatter) SetFields(fields []string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fields = f.fields[:0]
+	for _, field := range fields {
+		if _, ok := consoleFields[field]; ok {
+			f.fields = append(f.fields, field)
+		}
+	}
+	if len(f.fields) == 0 {
+		f.fields = append(f.fields, config.DefaultConsoleFields...)
+	}
+}
+
+// hasField reports whether the console shows the field
+func (f *ConsoleFormatter) hasField(name string) bool {
+	for _, field := range f.fields {
+		if field == name {
+			return true
+		}
+	}
+	return false
+}
+
+// layoutFields renders the configured fields in order. Usage and session fields are spaced
+// by blank lines, rate fields are kept together, and a rule separates sections, except
+// before the predictions, which only get a blank line.
+func (f *ConsoleFormatter) layoutFields(view *sessionView) []string {
+	var lines []string
+	var previous consoleSection
+	for _, name := range f.fields {
+		field := consoleFields[name]
+		rendered := field.render(f, view)
+		if len(rendered) == 0 {
+			continue
+		}
+
+		if len(lines) > 0 {
+			switch {
+			case field.section == sectionPredictions:
+				lines = append(lines, "")
+			case field.section != previous:
+				lines = append(lines, strings.Repeat("─", 60))
+			case field.section != sectionRates:
+				lines = append(lines, "")
+			}
+		}
+		lines = append(lines, rendered...)
+		previous = field.section
+	}
+	return lines
+}
+
+func (f *ConsoleFormatter) renderCostField(view *sessionView) []string {
+	costUsage := view.metrics.CurrentCost / f.costLimitP90 * 100
+	return []string{fmt.Sprintf("💰 Cost Usage:           %s %s %5.1f%%    $%.2f / $%.2f",
+		f.getColorIndicator(costUsage), f.renderWideProgressBar(costUsage, ""), costUsage,
+		view.metrics.CurrentCost, f.costLimitP90)}
+}
+
+func (f *ConsoleFormatter) renderTokensField(view *sessionView) []string {
+	tokenUsage := float64(view.metrics.CurrentTokens) / float64(f.tokenLimit) * 100
+	return []string{fmt.Sprintf("📊 Token Usage:          %s %s %5.1f%%    %s / %s",
+		f.getColorIndicator(tokenUsage), f.renderWideProgressBar(tokenUsage, ""), tokenUsage,
+		f.formatNumberWithCommas(view.metrics.CurrentTokens),
+		f.formatNumberWithCommas(f.tokenLimit))}
+}
+
+func (f *ConsoleFormatter) renderMessagesField(view *sessionView) []string {
+	messageCount := 0
+	if view.block != nil {
+		messageCount = view.block.SentMessagesCount
+	}
+	messagesUsage := float64(messageCount) / float64(f.messagesLimitP90) * 100
+	return []string{fmt.Sprintf("📨 Messages Usage:       %s %s %5.1f%%    %d / %s",
+		f.getColorIndicator(messagesUsage), f.renderWideProgressBar(messagesUsage, ""), messagesUsage,
+		messageCount, f.formatNumberWithCommas(f.messagesLimitP90))}
+}
+
+// renderCacheField renders the prompt cache tokens of the session, how much of the input
+// was read from the cache and what that saved
+func (f *ConsoleFormatter) renderCacheField(view *sessionView) []string {
+	if view.block == nil {
+		return nil
+	}
+	counts := view.block.TokenCounts
+	input := counts.InputTokens + counts.CacheCreationTokens + counts.CacheReadTokens
+	hitRate := 0.0
+	if input > 0 {
+		hitRate = float64(counts.CacheReadTokens) / float64(input) * 100
+	}
+	savings := 0.0
+	for _, entry := range view.block.Entries {
+		savings += entry.CacheSavingsUSD
+	}
+	return []string{fmt.Sprintf("💾 Cache Tokens:         %s written, %s read (%.1f%% of input from cache), saved $%.2f",
+		f.formatNumberWithCommas(counts.CacheCreationTokens),
+		f.formatNumberWithCommas(counts.CacheReadTokens), hitRate, savings)}
+}
+
+func (f *ConsoleFormatter) renderTimeField(view *sessionView) []string {
+	elapsed := time.Since(view.sessionStart).Minutes()
+	totalMinutes := f.sessionDuration.Minutes()
+	timePercentage := (elapsed / totalMinutes) * 100
+	timeRemaining := totalMinutes - elapsed
+
+	hours := int(timeRemaining / 60)
+	mins := int(timeRemaining) % 60
+	return []string{fmt.Sprintf("⏱️  Time to Reset:       %s %s %dh %dm",
+		f.getColorIndicator(timePercentage), f.renderWideProgressBar(timePercentage, ""), hours, mins)}
+}
+
+func (f *ConsoleFormatter) renderModelsField(view *sessionView) []string {
+	return []string{fmt.Sprintf("🤖 Model Distribution:   🤖 %s", f.renderModelDistributionSimple(view.metrics))}
+}
+
+func (f *ConsoleFormatter) renderProjectsField(view *sessionView) []string {
+	projectLines := f.renderProjectDistribution(view.metrics)
+	if len(projectLines) == 0 {
+		return nil
+	}
+	return append([]string{"📁 Projects:"}, projectLines...)
+}
+
+// renderSourcesField shows where the session's usage came from, once it comes from more
+// than one source
+func (f *ConsoleFormatter) renderSourcesField(view *sessionView) []string {
+	if view.metrics == nil || len(view.metrics.SourceDistribution) < 2 {
+		return nil
+	}
+	lines := []string{"🔌 Sources:"}
+	for _, source := range view.metrics.SourceDistribution {
+		lines = append(lines, fmt.Sprintf("   %-28s %12s tokens  $%8.2f  %5.1f%%",
+			truncateString(source.Source, 28),
+			f.formatNumberWithCommas(source.TotalTokens),
+			source.Cost,
+			source.CostPercentage))
+	}
+	return lines
+}
+
+func (f *ConsoleFormatter) renderBurnRateField(view *sessionView) []string {
+	emoji := "🐌"
+	if view.burnRate > 100 {
+		emoji = "🚀"
+	} else if view.burnRate > 50 {
+		emoji = "🏃"
+	}
+	return []string{fmt.Sprintf("🔥 Burn Rate:              %.1f tokens/min %s", view.burnRate, emoji)}
+}
+
+func (f *ConsoleFormatter) renderCostRateField(view *sessionView) []string {
+	return []string{fmt.Sprintf("💲 Cost Rate:              $%.4f $/min", view.costRate)}
+}
+
+func (f *ConsoleFormatter) renderTrendsField(view *sessionView) []string {
+	return f.renderRateTrends()
+}
+
+// renderPredictionsField renders when the tokens run out and when the limit resets
+func (f *ConsoleFormatter) renderPredictionsField(view *sessionView) []string {
+	resetTime := timeutil.ResetTime(view.sessionStart, f.sessionDuration, f.location)
+	depletion := calculations.NewBurnRateCalculator().ProjectDepletion(
+		view.metrics.CurrentTokens, f.tokenLimit, view.burnRate, resetTime, time.Now())
+
+	lines := []string{"🔮 Predictions:"}
+	lines = append(lines, f.renderDepletion(depletion)...)
+	return append(lines, fmt.Sprintf("   Limit resets at:     %s", f.formatTimeShort(resetTime)))
+}
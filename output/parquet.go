@@ -0,0 +1,151 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/compress/zstd"
+	"github.com/penwyp/claudecat/models"
+)
+
+// ParquetSchemaVersion is stored in the key/value metadata of Parquet exports under
+// "claudecat.schema_version" and bumped whenever a column is renamed, retyped or removed
+const ParquetSchemaVersion = "1"
+
+// UsageEntryParquetRow is the schema of usage entries exported to Parquet, one row per
+// request. Columns match the CSV export; timestamps are UTC with millisecond precision.
+type UsageEntryParquetRow struct {
+	Timestamp           time.Time `parquet:"timestamp,timestamp(millisecond)"`
+	SessionID           string    `parquet:"session_id,dict"`
+	Project             string    `parquet:"project,dict"`
+	Model               string    `parquet:"model,dict"`
+	MessageID           string    `parquet:"message_id"`
+	RequestID           string    `parquet:"request_id"`
+	InputTokens         int64     `parquet:"input_tokens"`
+	OutputTokens        int64     `parquet:"output_tokens"`
+	CacheCreationTokens int64     `parquet:"cache_creation_tokens"`
+	CacheReadTokens     int64     `parquet:"cache_read_tokens"`
+	ThinkingTokens      int64     `parquet:"thinking_tokens"`
+	TotalTokens         int64     `parquet:"total_tokens"`
+	CostUSD             float64   `parquet:"cost_usd"`
+	CostSource          string    `parquet:"cost_source,dict"` // "logged" or "calculated"
+	Tag                 string    `parquet:"tag,dict"`         // Cost allocation tag, empty when untagged
+}
+
+// SessionBlockParquetRow is the schema of session blocks exported to Parquet, one row per
+// block. Columns match the CSV export, except that models are a list rather than joined,
+// and actual_end_time and the burn rate are null when unknown. The timestamp type of
+// actual_end_time is carried by an int64 field as it's the only one the writer leaves null
+// when unset.
+type SessionBlockParquetRow struct {
+	BlockID             string    `parquet:"block_id"`
+	StartTime           time.Time `parquet:"start_time,timestamp(millisecond)"`
+	EndTime             time.Time `parquet:"end_time,timestamp(millisecond)"`
+	ActualEndTime       int64     `parquet:"actual_end_time,optional,timestamp(millisecond)"` // Unix milliseconds
+	IsActive            bool      `parquet:"is_active"`
+	IsGap               bool      `parquet:"is_gap"`
+	Entries             int64     `parquet:"entries"`
+	SentMessages        int64     `parquet:"sent_messages"`
+	InputTokens         int64     `parquet:"input_tokens"`
+	OutputTokens        int64     `parquet:"output_tokens"`
+	CacheCreationTokens int64     `parquet:"cache_creation_tokens"`
+	CacheReadTokens     int64     `parquet:"cache_read_tokens"`
+	ThinkingTokens      int64     `parquet:"thinking_tokens"`
+	TotalTokens         int64     `parquet:"total_tokens"`
+	CostUSD             float64   `parquet:"cost_usd"`
+	TokensPerMinute     *float64  `parquet:"tokens_per_minute,optional"`
+	CostPerHour         *float64  `parquet:"cost_per_hour,optional"`
+	Models              []string  `parquet:"models,list"`
+}
+
+// NewUsageEntryParquetRow converts a usage entry to its Parquet row
+func NewUsageEntryParquetRow(entry models.UsageEntry) UsageEntryParquetRow {
+	return UsageEntryParquetRow{
+		Timestamp:           entry.Timestamp.UTC(),
+		SessionID:           entry.SessionID,
+		Project:             entry.Project,
+		Model:               entry.Model,
+		MessageID:           entry.MessageID,
+		RequestID:           entry.RequestID,
+		InputTokens:         int64(entry.InputTokens),
+		OutputTokens:        int64(entry.OutputTokens),
+		CacheCreationTokens: int64(entry.CacheCreationTokens),
+		CacheReadTokens:     int64(entry.CacheReadTokens),
+		ThinkingTokens:      int64(entry.ThinkingTokens),
+		TotalTokens:         int64(entry.TotalTokens),
+		CostUSD:             entry.CostUSD,
+		CostSource:          entry.CostSource,
+		Tag:                 entry.Tag,
+	}
+}
+
+// NewSessionBlockParquetRow converts a session block to its Parquet row
+func NewSessionBlockParquetRow(block models.SessionBlock) SessionBlockParquetRow {
+	row := SessionBlockParquetRow{
+		BlockID:             block.ID,
+		StartTime:           block.StartTime.UTC(),
+		EndTime:             block.EndTime.UTC(),
+		IsActive:            block.IsActive,
+		IsGap:               block.IsGap,
+		Entries:             int64(len(block.Entries)),
+		SentMessages:        int64(block.SentMessagesCount),
+		InputTokens:         int64(block.TokenCounts.InputTokens),
+		OutputTokens:        int64(block.TokenCounts.OutputTokens),
+		CacheCreationTokens: int64(block.TokenCounts.CacheCreationTokens),
+		CacheReadTokens:     int64(block.TokenCounts.CacheReadTokens),
+		ThinkingTokens:      int64(block.TokenCounts.ThinkingTokens),
+		TotalTokens:         int64(block.TokenCounts.TotalTokens()),
+		CostUSD:             block.CostUSD,
+		Models:              block.Models,
+	}
+	if row.Models == nil {
+		row.Models = []string{}
+	}
+	if block.ActualEndTime != nil {
+		row.ActualEndTime = block.ActualEndTime.UnixMilli()
+	}
+	if block.BurnRate != nil {
+		tokensPerMinute, costPerHour := block.BurnRate.TokensPerMinute, block.BurnRate.CostPerHour
+		row.TokensPerMinute = &tokensPerMinute
+		row.CostPerHour = &costPerHour
+	}
+	return row
+}
+
+// WriteUsageEntriesParquet writes usage entries as a zstd-compressed Parquet file with the
+// UsageEntryParquetRow schema
+func WriteUsageEntriesParquet(w io.Writer, entries []models.UsageEntry) error {
+	rows := make([]UsageEntryParquetRow, len(entries))
+	for i, entry := range entries {
+		rows[i] = NewUsageEntryParquetRow(entry)
+	}
+	return writeParquet(w, rows, "entries")
+}
+
+// WriteSessionBlocksParquet writes session blocks as a zstd-compressed Parquet file with the
+// SessionBlockParquetRow schema
+func WriteSessionBlocksParquet(w io.Writer, blocks []models.SessionBlock) error {
+	rows := make([]SessionBlockParquetRow, len(blocks))
+	for i, block := range blocks {
+		rows[i] = NewSessionBlockParquetRow(block)
+	}
+	return writeParquet(w, rows, "blocks")
+}
+
+func writeParquet[T any](w io.Writer, rows []T, exportType string) error {
+	writer := parquet.NewGenericWriter[T](w,
+		parquet.Compression(&zstd.Codec{}),
+		parquet.CreatedBy("claudecat", "", ""),
+		parquet.KeyValueMetadata("claudecat.schema_version", ParquetSchemaVersion),
+		parquet.KeyValueMetadata("claudecat.export_type", exportType),
+	)
+	if _, err := writer.Write(rows); err != nil {
+		return fmt.Errorf("failed to write Parquet rows: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to finish Parquet file: %w", err)
+	}
+	return nil
+}
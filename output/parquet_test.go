@@ -0,0 +1,99 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/penwyp/claudecat/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteUsageEntriesParquet(t *testing.T) {
+	entries := []models.UsageEntry{
+		{
+			Timestamp:    time.Date(2025, 6, 1, 10, 0, 0, 0, time.FixedZone("CEST", 2*60*60)),
+			SessionID:    "s1",
+			Project:      "api",
+			Model:        "claude-sonnet-4-20250514",
+			MessageID:    "m1",
+			RequestID:    "r1",
+			InputTokens:  100,
+			OutputTokens: 50,
+			TotalTokens:  150,
+			CostUSD:      0.00105,
+			CostSource:   "calculated",
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteUsageEntriesParquet(&buf, entries))
+
+	file, err := parquet.OpenFile(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	version, ok := file.Lookup("claudecat.schema_version")
+	assert.True(t, ok)
+	assert.Equal(t, ParquetSchemaVersion, version)
+
+	rows, err := parquet.Read[UsageEntryParquetRow](bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.True(t, rows[0].Timestamp.Equal(time.Date(2025, 6, 1, 8, 0, 0, 0, time.UTC)))
+	rows[0].Timestamp = time.Time{}
+	assert.Equal(t, UsageEntryParquetRow{
+		SessionID: "s1", Project: "api", Model: "claude-sonnet-4-20250514", MessageID: "m1", RequestID: "r1",
+		InputTokens: 100, OutputTokens: 50, TotalTokens: 150, CostUSD: 0.00105, CostSource: "calculated",
+	}, rows[0])
+}
+
+func TestWriteSessionBlocksParquet(t *testing.T) {
+	start := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	actualEnd := start.Add(90 * time.Minute)
+	blocks := []models.SessionBlock{
+		{
+			ID:            "b1",
+			StartTime:     start,
+			EndTime:       start.Add(5 * time.Hour),
+			ActualEndTime: &actualEnd,
+			Entries:       []models.UsageEntry{{}, {}},
+			TokenCounts:   models.TokenCounts{InputTokens: 1000, OutputTokens: 500},
+			CostUSD:       1.5,
+			BurnRate:      &models.BurnRate{TokensPerMinute: 16.5, CostPerHour: 1},
+			Models:        []string{"opus", "sonnet"},
+		},
+		{ID: "gap", StartTime: start.Add(5 * time.Hour), EndTime: start.Add(6 * time.Hour), IsGap: true},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteSessionBlocksParquet(&buf, blocks))
+
+	rows, err := parquet.Read[SessionBlockParquetRow](bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+
+	assert.Equal(t, "b1", rows[0].BlockID)
+	assert.Equal(t, actualEnd.UnixMilli(), rows[0].ActualEndTime)
+	assert.Equal(t, int64(2), rows[0].Entries)
+	assert.Equal(t, int64(1500), rows[0].TotalTokens)
+	require.NotNil(t, rows[0].TokensPerMinute)
+	assert.Equal(t, 16.5, *rows[0].TokensPerMinute)
+	assert.Equal(t, []string{"opus", "sonnet"}, rows[0].Models)
+
+	// Unknown values are null rather than zero
+	assert.True(t, rows[1].IsGap)
+	assert.Zero(t, rows[1].ActualEndTime)
+	assert.Nil(t, rows[1].TokensPerMinute)
+	assert.Nil(t, rows[1].CostPerHour)
+	assert.Empty(t, rows[1].Models)
+
+	file, err := parquet.OpenFile(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	nulls := make(map[string]int64)
+	for _, column := range file.Metadata().RowGroups[0].Columns {
+		nulls[column.MetaData.PathInSchema[0]] = column.MetaData.Statistics.NullCount
+	}
+	assert.Equal(t, int64(1), nulls["actual_end_time"])
+	assert.Equal(t, int64(1), nulls["tokens_per_minute"])
+}
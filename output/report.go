@@ -0,0 +1,124 @@
+package output
+
+import (
+	"sort"
+	"time"
+
+	"github.com/penwyp/claudecat/models"
+)
+
+// ReportModelUsage is the usage of one model within a report row
+type ReportModelUsage struct {
+	Model           string             `json:"model"`
+	TokenCounts     models.TokenCounts `json:"token_counts"`
+	TotalTokens     int                `json:"total_tokens"`
+	CostUSD         float64            `json:"cost_usd"`
+	CacheSavingsUSD float64            `json:"cache_savings_usd"`
+}
+
+// ReportRow aggregates the usage of one day, month or Claude Code session
+type ReportRow struct {
+	Key             string             `json:"key"`               // Date, month or session ID
+	Project         string             `json:"project,omitempty"` // Project of the session's last entry
+	TokenCounts     models.TokenCounts `json:"token_counts"`
+	TotalTokens     int                `json:"total_tokens"`
+	CostUSD         float64            `json:"cost_usd"`
+	CacheSavingsUSD float64            `json:"cache_savings_usd"` // Saved by prompt caching versus uncached input pricing
+	Entries         int                `json:"entries"`
+	LastActivity    time.Time          `json:"last_activity"`
+	Models          []ReportModelUsage `json:"models"` // Most expensive first
+}
+
+// GroupUsage aggregates entries into one row per key, ordered by key
+func GroupUsage(entries []models.UsageEntry, key func(models.UsageEntry) string) []ReportRow {
+	rows := make(map[string]*ReportRow)
+	perModel := make(map[string]map[string]*ReportModelUsage)
+
+	for _, entry := range entries {
+		k := key(entry)
+		row, ok := rows[k]
+		if !ok {
+			row = &ReportRow{Key: k}
+			rows[k] = row
+			perModel[k] = make(map[string]*ReportModelUsage)
+		}
+		row.addEntry(entry)
+
+		model, ok := perModel[k][entry.Model]
+		if !ok {
+			model = &ReportModelUsage{Model: entry.Model}
+			perModel[k][entry.Model] = model
+		}
+		addReportTokens(&model.TokenCounts, entry)
+		model.TotalTokens += entry.TotalTokens
+		model.CostUSD += entry.CostUSD
+		model.CacheSavingsUSD += entry.CacheSavingsUSD
+	}
+
+	result := make([]ReportRow, 0, len(rows))
+	for k, row := range rows {
+		for _, model := range perModel[k] {
+			row.Models = append(row.Models, *model)
+		}
+		sort.Slice(row.Models, func(i, j int) bool {
+			if row.Models[i].CostUSD != row.Models[j].CostUSD {
+				return row.Models[i].CostUSD > row.Models[j].CostUSD
+			}
+			return row.Models[i].Model < row.Models[j].Model
+		})
+		result = append(result, *row)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Key < result[j].Key })
+	return result
+}
+
+// ReportTotals sums the token counts, totals, costs and entries of rows
+func ReportTotals(rows []ReportRow) ReportRow {
+	var totals ReportRow
+	for _, row := range rows {
+		totals.TokenCounts.InputTokens += row.TokenCounts.InputTokens
+		totals.TokenCounts.OutputTokens += row.TokenCounts.OutputTokens
+		totals.TokenCounts.CacheCreationTokens += row.TokenCounts.CacheCreationTokens
+		totals.TokenCounts.CacheReadTokens += row.TokenCounts.CacheReadTokens
+		totals.TokenCounts.ThinkingTokens += row.TokenCounts.ThinkingTokens
+		totals.TotalTokens += row.TotalTokens
+		totals.CostUSD += row.CostUSD
+		totals.CacheSavingsUSD += row.CacheSavingsUSD
+		totals.Entries += row.Entries
+		if row.LastActivity.After(totals.LastActivity) {
+			totals.LastActivity = row.LastActivity
+		}
+	}
+	return totals
+}
+
+// ModelNames returns the models of the row, most expensive first
+func (r ReportRow) ModelNames() []string {
+	names := make([]string, 0, len(r.Models))
+	for _, model := range r.Models {
+		names = append(names, model.Model)
+	}
+	return names
+}
+
+func (r *ReportRow) addEntry(entry models.UsageEntry) {
+	addReportTokens(&r.TokenCounts, entry)
+	r.TotalTokens += entry.TotalTokens
+	r.CostUSD += entry.CostUSD
+	r.CacheSavingsUSD += entry.CacheSavingsUSD
+	r.Entries++
+	if !entry.Timestamp.Before(r.LastActivity) {
+		r.LastActivity = entry.Timestamp
+		if entry.Project != "" {
+			r.Project = entry.Project
+		}
+	}
+}
+
+func addReportTokens(counts *models.TokenCounts, entry models.UsageEntry) {
+	counts.InputTokens += entry.InputTokens
+	counts.OutputTokens += entry.OutputTokens
+	counts.CacheCreationTokens += entry.CacheCreationTokens
+	counts.CacheReadTokens += entry.CacheReadTokens
+	counts.ThinkingTokens += entry.ThinkingTokens
+}
@@ -0,0 +1,51 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/penwyp/claudecat/calculations"
+)
+
+// sparkBlocks are the bar heights of a sparkline, lowest first
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline renders the last width values as a row of bars scaled from zero to the largest value
+func sparkline(values []float64, width int) string {
+	if width > 0 && len(values) > width {
+		values = values[len(values)-width:]
+	}
+
+	var max float64
+	for _, v := range values {
+		if v > max {
+			max = v
+		}
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		level := 0
+		if max > 0 && v > 0 {
+			level = int(v / max * float64(len(sparkBlocks)-1))
+		}
+		b.WriteRune(sparkBlocks[level])
+	}
+	return b.String()
+}
+
+// trendThreshold is the relative change of the rate below which it counts as steady
+const trendThreshold = 0.2
+
+// describeTrend labels the change between the older and newer half of values
+func describeTrend(values []float64) string {
+	trend := calculations.Trend(values)
+	switch {
+	case trend >= trendThreshold:
+		return fmt.Sprintf("↗ accelerating (+%.0f%%)", trend*100)
+	case trend <= -trendThreshold:
+		return fmt.Sprintf("↘ slowing (%.0f%%)", trend*100)
+	default:
+		return "→ steady"
+	}
+}
@@ -0,0 +1,309 @@
+package output
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/penwyp/claudecat/models"
+
+	// Registers the sqlite3 driver; binaries built without cgo report an error when opening
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// SQLiteSchemaVersion is stored in the metadata table of SQLite exports and bumped whenever
+// a table or column is renamed, retyped or removed
+const SQLiteSchemaVersion = "1"
+
+// sqliteSchema is the normalized schema of SQLite exports. Model and project names live in
+// lookup tables; the usage view joins them back for ad-hoc queries. Timestamps are UTC
+// ISO 8601 text, which SQLite's date and time functions accept.
+const sqliteSchema = `
+CREATE TABLE metadata (
+	key   TEXT PRIMARY KEY,
+	value TEXT NOT NULL
+);
+CREATE TABLE models (
+	id   INTEGER PRIMARY KEY,
+	name TEXT NOT NULL UNIQUE
+);
+CREATE TABLE projects (
+	id   INTEGER PRIMARY KEY,
+	name TEXT NOT NULL UNIQUE
+);
+CREATE TABLE blocks (
+	id                    TEXT PRIMARY KEY,
+	start_time            TEXT NOT NULL,
+	end_time              TEXT NOT NULL,
+	actual_end_time       TEXT,
+	is_active             INTEGER NOT NULL,
+	is_gap                INTEGER NOT NULL,
+	entries               INTEGER NOT NULL,
+	sent_messages         INTEGER NOT NULL,
+	input_tokens          INTEGER NOT NULL,
+	output_tokens         INTEGER NOT NULL,
+	cache_creation_tokens INTEGER NOT NULL,
+	cache_read_tokens     INTEGER NOT NULL,
+	thinking_tokens       INTEGER NOT NULL,
+	total_tokens          INTEGER NOT NULL,
+	cost_usd              REAL NOT NULL,
+	tokens_per_minute     REAL,
+	cost_per_hour         REAL
+);
+CREATE TABLE block_models (
+	block_id TEXT NOT NULL REFERENCES blocks(id),
+	model_id INTEGER NOT NULL REFERENCES models(id),
+	PRIMARY KEY (block_id, model_id)
+);
+CREATE TABLE entries (
+	id                    INTEGER PRIMARY KEY,
+	timestamp             TEXT NOT NULL,
+	block_id              TEXT REFERENCES blocks(id),
+	session_id            TEXT NOT NULL,
+	project_id            INTEGER REFERENCES projects(id),
+	model_id              INTEGER NOT NULL REFERENCES models(id),
+	message_id            TEXT NOT NULL,
+	request_id            TEXT NOT NULL,
+	input_tokens          INTEGER NOT NULL,
+	output_tokens         INTEGER NOT NULL,
+	cache_creation_tokens INTEGER NOT NULL,
+	cache_read_tokens     INTEGER NOT NULL,
+	thinking_tokens       INTEGER NOT NULL,
+	total_tokens          INTEGER NOT NULL,
+	cost_usd              REAL NOT NULL,
+	cost_source           TEXT NOT NULL,
+	tag                   TEXT
+);
+CREATE TABLE limits (
+	id        INTEGER PRIMARY KEY,
+	timestamp TEXT NOT NULL,
+	block_id  TEXT REFERENCES blocks(id),
+	type      TEXT NOT NULL,
+	message   TEXT NOT NULL
+);
+CREATE INDEX entries_timestamp ON entries (timestamp);
+CREATE INDEX entries_model ON entries (model_id, timestamp);
+CREATE INDEX entries_project ON entries (project_id, timestamp);
+CREATE INDEX entries_session ON entries (session_id);
+CREATE INDEX entries_block ON entries (block_id);
+CREATE INDEX entries_tag ON entries (tag, timestamp);
+CREATE INDEX blocks_start_time ON blocks (start_time);
+CREATE INDEX limits_timestamp ON limits (timestamp);
+CREATE VIEW usage AS
+SELECT e.timestamp, e.block_id, e.session_id, p.name AS project, m.name AS model,
+	e.message_id, e.request_id, e.input_tokens, e.output_tokens, e.cache_creation_tokens,
+	e.cache_read_tokens, e.thinking_tokens, e.total_tokens, e.cost_usd, e.cost_source, e.tag
+FROM entries e
+JOIN models m ON m.id = e.model_id
+LEFT JOIN projects p ON p.id = e.project_id;
+`
+
+// WriteSQLiteExport writes entries, session blocks and limit messages to a new SQLite database
+// at path, replacing any file there. Entries and limits are linked to the block they fall in,
+// when it was exported. The database is built next to path and renamed into place, so a
+// failed export leaves an existing file untouched.
+func WriteSQLiteExport(path string, entries []models.UsageEntry, blocks []models.SessionBlock, limits []models.LimitMessage) error {
+	tmpPath := path + ".tmp"
+	os.Remove(tmpPath)
+
+	db, err := sql.Open("sqlite3", "file:"+tmpPath+"?_foreign_keys=on")
+	if err != nil {
+		return fmt.Errorf("failed to create database %s: %w", tmpPath, err)
+	}
+	err = writeSQLiteExport(db, entries, blocks, limits)
+	if closeErr := db.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to move database into place: %w", err)
+	}
+	return nil
+}
+
+func writeSQLiteExport(db *sql.DB, entries []models.UsageEntry, blocks []models.SessionBlock, limits []models.LimitMessage) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(sqliteSchema); err != nil {
+		return fmt.Errorf("failed to create schema: %w", err)
+	}
+	if _, err := tx.Exec(`INSERT INTO metadata (key, value) VALUES ('schema_version', ?), ('exported_at', ?)`,
+		SQLiteSchemaVersion, formatSQLiteTime(time.Now())); err != nil {
+		return fmt.Errorf("failed to write metadata: %w", err)
+	}
+
+	w := &sqliteExportWriter{tx: tx, models: make(map[string]int64), projects: make(map[string]int64)}
+	blockOf, err := w.writeBlocks(blocks)
+	if err != nil {
+		return err
+	}
+	if err := w.writeEntries(entries, blockOf); err != nil {
+		return err
+	}
+	if err := w.writeLimits(limits, blocks); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit export: %w", err)
+	}
+	return nil
+}
+
+// sqliteExportWriter inserts rows in an export transaction, assigning lookup IDs to model and
+// project names as they're first seen
+type sqliteExportWriter struct {
+	tx       *sql.Tx
+	models   map[string]int64
+	projects map[string]int64
+}
+
+// sqliteEntryKey identifies a usage entry across the entry list and the blocks built from it
+type sqliteEntryKey struct {
+	timestamp int64
+	messageID string
+	requestID string
+}
+
+func newSQLiteEntryKey(entry models.UsageEntry) sqliteEntryKey {
+	return sqliteEntryKey{timestamp: entry.Timestamp.UnixNano(), messageID: entry.MessageID, requestID: entry.RequestID}
+}
+
+// writeBlocks inserts blocks and their models, returning the block of each entry they hold
+func (w *sqliteExportWriter) writeBlocks(blocks []models.SessionBlock) (map[sqliteEntryKey]string, error) {
+	stmt, err := w.tx.Prepare(`INSERT INTO blocks (id, start_time, end_time, actual_end_time, is_active, is_gap,
+		entries, sent_messages, input_tokens, output_tokens, cache_creation_tokens, cache_read_tokens,
+		thinking_tokens, total_tokens, cost_usd, tokens_per_minute, cost_per_hour)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare block insert: %w", err)
+	}
+	defer stmt.Close()
+
+	blockOf := make(map[sqliteEntryKey]string)
+	for _, block := range blocks {
+		var actualEnd interface{}
+		if block.ActualEndTime != nil {
+			actualEnd = formatSQLiteTime(*block.ActualEndTime)
+		}
+		var tokensPerMinute, costPerHour interface{}
+		if block.BurnRate != nil {
+			tokensPerMinute, costPerHour = block.BurnRate.TokensPerMinute, block.BurnRate.CostPerHour
+		}
+		counts := block.TokenCounts
+		if _, err := stmt.Exec(block.ID, formatSQLiteTime(block.StartTime), formatSQLiteTime(block.EndTime), actualEnd,
+			block.IsActive, block.IsGap, len(block.Entries), block.SentMessagesCount,
+			counts.InputTokens, counts.OutputTokens, counts.CacheCreationTokens, counts.CacheReadTokens,
+			counts.ThinkingTokens, counts.TotalTokens(), block.CostUSD, tokensPerMinute, costPerHour); err != nil {
+			return nil, fmt.Errorf("failed to write block %s: %w", block.ID, err)
+		}
+
+		for _, model := range block.Models {
+			modelID, err := w.lookupID(w.models, "models", model)
+			if err != nil {
+				return nil, err
+			}
+			if _, err := w.tx.Exec(`INSERT OR IGNORE INTO block_models (block_id, model_id) VALUES (?, ?)`, block.ID, modelID); err != nil {
+				return nil, fmt.Errorf("failed to write models of block %s: %w", block.ID, err)
+			}
+		}
+		for _, entry := range block.Entries {
+			blockOf[newSQLiteEntryKey(entry)] = block.ID
+		}
+	}
+	return blockOf, nil
+}
+
+func (w *sqliteExportWriter) writeEntries(entries []models.UsageEntry, blockOf map[sqliteEntryKey]string) error {
+	stmt, err := w.tx.Prepare(`INSERT INTO entries (timestamp, block_id, session_id, project_id, model_id,
+		message_id, request_id, input_tokens, output_tokens, cache_creation_tokens, cache_read_tokens,
+		thinking_tokens, total_tokens, cost_usd, cost_source, tag)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare entry insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, entry := range entries {
+		var blockID, projectID, tag interface{}
+		if id, ok := blockOf[newSQLiteEntryKey(entry)]; ok {
+			blockID = id
+		}
+		if entry.Tag != "" {
+			tag = entry.Tag
+		}
+		if entry.Project != "" {
+			id, err := w.lookupID(w.projects, "projects", entry.Project)
+			if err != nil {
+				return err
+			}
+			projectID = id
+		}
+		modelID, err := w.lookupID(w.models, "models", entry.Model)
+		if err != nil {
+			return err
+		}
+
+		if _, err := stmt.Exec(formatSQLiteTime(entry.Timestamp), blockID, entry.SessionID, projectID, modelID,
+			entry.MessageID, entry.RequestID, entry.InputTokens, entry.OutputTokens, entry.CacheCreationTokens,
+			entry.CacheReadTokens, entry.ThinkingTokens, entry.TotalTokens, entry.CostUSD, entry.CostSource, tag); err != nil {
+			return fmt.Errorf("failed to write entry: %w", err)
+		}
+	}
+	return nil
+}
+
+// writeLimits inserts limit messages, linking each to the session block it was hit in
+func (w *sqliteExportWriter) writeLimits(limits []models.LimitMessage, blocks []models.SessionBlock) error {
+	stmt, err := w.tx.Prepare(`INSERT INTO limits (timestamp, block_id, type, message) VALUES (?, ?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare limit insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, limit := range limits {
+		var blockID interface{}
+		for _, block := range blocks {
+			if !block.IsGap && !limit.Timestamp.Before(block.StartTime) && limit.Timestamp.Before(block.EndTime) {
+				blockID = block.ID
+				break
+			}
+		}
+		if _, err := stmt.Exec(formatSQLiteTime(limit.Timestamp), blockID, limit.Type, limit.Message); err != nil {
+			return fmt.Errorf("failed to write limit: %w", err)
+		}
+	}
+	return nil
+}
+
+// lookupID returns the ID of name in a lookup table, inserting it when first seen
+func (w *sqliteExportWriter) lookupID(ids map[string]int64, table, name string) (int64, error) {
+	if id, ok := ids[name]; ok {
+		return id, nil
+	}
+	result, err := w.tx.Exec(`INSERT INTO `+table+` (name) VALUES (?)`, name)
+	if err != nil {
+		return 0, fmt.Errorf("failed to write %s %q: %w", table, name, err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read ID of %s %q: %w", table, name, err)
+	}
+	ids[name] = id
+	return id, nil
+}
+
+// formatSQLiteTime formats t as UTC ISO 8601 with milliseconds, which sorts chronologically
+func formatSQLiteTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05.000Z")
+}
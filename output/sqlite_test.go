@@ -0,0 +1,82 @@
+package output
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/penwyp/claudecat/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteSQLiteExport(t *testing.T) {
+	start := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	entries := []models.UsageEntry{
+		{Timestamp: start, SessionID: "s1", Project: "api", Model: "claude-opus-4-20250514", MessageID: "m1", RequestID: "r1",
+			InputTokens: 100, OutputTokens: 50, TotalTokens: 150, CostUSD: 0.5, CostSource: "calculated"},
+		{Timestamp: start.Add(time.Minute), SessionID: "s1", Project: "api", Model: "claude-sonnet-4-20250514", MessageID: "m2", RequestID: "r2",
+			InputTokens: 10, OutputTokens: 5, TotalTokens: 15, CostUSD: 0.01, CostSource: "logged"},
+		{Timestamp: start.Add(6 * time.Hour), SessionID: "s2", Model: "claude-sonnet-4-20250514", MessageID: "m3", RequestID: "r3",
+			InputTokens: 1, TotalTokens: 1},
+	}
+	actualEnd := start.Add(time.Minute)
+	blocks := []models.SessionBlock{{
+		ID:            "b1",
+		StartTime:     start,
+		EndTime:       start.Add(5 * time.Hour),
+		ActualEndTime: &actualEnd,
+		Entries:       entries[:2],
+		TokenCounts:   models.TokenCounts{InputTokens: 110, OutputTokens: 55},
+		CostUSD:       0.51,
+		Models:        []string{"claude-opus-4-20250514", "claude-sonnet-4-20250514"},
+	}}
+	limits := []models.LimitMessage{
+		{Timestamp: start.Add(30 * time.Minute), Type: "opus_limit", Message: "Opus limit reached"},
+		{Timestamp: start.Add(7 * time.Hour), Type: "general_limit", Message: "Usage limit reached"},
+	}
+
+	path := filepath.Join(t.TempDir(), "usage.sqlite")
+	require.NoError(t, os.WriteFile(path, []byte("previous export"), 0644))
+	require.NoError(t, WriteSQLiteExport(path, entries, blocks, limits))
+	assert.NoFileExists(t, path+".tmp")
+
+	db, err := sql.Open("sqlite3", path)
+	require.NoError(t, err)
+	defer db.Close()
+
+	var version string
+	require.NoError(t, db.QueryRow(`SELECT value FROM metadata WHERE key = 'schema_version'`).Scan(&version))
+	assert.Equal(t, SQLiteSchemaVersion, version)
+
+	// Model names are normalized and joined back by the usage view
+	var modelCount, tokens int
+	var cost float64
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM models`).Scan(&modelCount))
+	assert.Equal(t, 2, modelCount)
+	require.NoError(t, db.QueryRow(`SELECT SUM(total_tokens), SUM(cost_usd) FROM usage
+		WHERE model = 'claude-sonnet-4-20250514'`).Scan(&tokens, &cost))
+	assert.Equal(t, 16, tokens)
+	assert.InDelta(t, 0.01, cost, 1e-9)
+
+	// Entries and limits outside the exported blocks aren't linked to one
+	var inBlock, outside int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(block_id), COUNT(*) - COUNT(block_id) FROM entries`).Scan(&inBlock, &outside))
+	assert.Equal(t, 2, inBlock)
+	assert.Equal(t, 1, outside)
+	var limitBlock sql.NullString
+	require.NoError(t, db.QueryRow(`SELECT block_id FROM limits WHERE type = 'opus_limit'`).Scan(&limitBlock))
+	assert.Equal(t, "b1", limitBlock.String)
+	require.NoError(t, db.QueryRow(`SELECT block_id FROM limits WHERE type = 'general_limit'`).Scan(&limitBlock))
+	assert.False(t, limitBlock.Valid)
+
+	// Timestamps work with SQLite's date functions
+	var day string
+	var blockModels int
+	require.NoError(t, db.QueryRow(`SELECT date(start_time), (SELECT COUNT(*) FROM block_models WHERE block_id = blocks.id)
+		FROM blocks`).Scan(&day, &blockModels))
+	assert.Equal(t, "2025-06-01", day)
+	assert.Equal(t, 2, blockModels)
+}
@@ -0,0 +1,215 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/penwyp/claudecat/models"
+)
+
+// DefaultStatusLineFormat renders e.g. "45% · $3.21 · 2h13m", or the session's tokens when
+// there is no token limit
+const DefaultStatusLineFormat = `{{if not .Active}}idle{{else}}{{if .Limit}}{{.Percent}}%{{else}}{{.Tokens}}{{end}} · {{.Cost}} · {{.Reset}}{{end}}`
+
+// DefaultClaudeCodeStatusLineFormat renders e.g. "[Opus] claudecat · $0.12 · 45% · 2h13m"
+// with the model, directory and conversation cost Claude Code reports
+const DefaultClaudeCodeStatusLineFormat = `[{{.Model}}] {{.Dir}} · {{.SessionCost}}{{if .Active}} · {{if .Limit}}{{.Percent}}%{{else}}{{.Tokens}}{{end}} · {{.Reset}}{{end}}`
+
+// Status line colors
+const (
+	StatusColorNone = "none"
+	StatusColorANSI = "ansi" // ANSI escape codes, for shell prompts
+	StatusColorTmux = "tmux" // tmux #[fg=...] styles, for status-left and status-right
+)
+
+// StatusLine is the usage of the active session shown in a status bar or prompt
+type StatusLine struct {
+	Active      bool      `json:"active"`
+	SessionID   string    `json:"session_id,omitempty"`
+	Tokens      int       `json:"tokens"`
+	TokenLimit  int       `json:"token_limit"`
+	CostUSD     float64   `json:"cost_usd"`
+	ResetAt     time.Time `json:"reset_at"` // End of the active session block
+	GeneratedAt time.Time `json:"generated_at"`
+
+	// Context Claude Code passed to the statusline command, nil outside Claude Code
+	ClaudeCode *ClaudeCodeStatus `json:"-"`
+}
+
+// ClaudeCodeStatus is the JSON context Claude Code writes to the stdin of statusline commands
+type ClaudeCodeStatus struct {
+	SessionID      string `json:"session_id"`
+	TranscriptPath string `json:"transcript_path"`
+	Cwd            string `json:"cwd"`
+	Version        string `json:"version"`
+	Model          struct {
+		ID          string `json:"id"`
+		DisplayName string `json:"display_name"`
+	} `json:"model"`
+	Workspace struct {
+		CurrentDir string `json:"current_dir"`
+		ProjectDir string `json:"project_dir"`
+	} `json:"workspace"`
+	Cost struct {
+		TotalCostUSD      float64 `json:"total_cost_usd"`
+		TotalDurationMS   int64   `json:"total_duration_ms"`
+		TotalLinesAdded   int     `json:"total_lines_added"`
+		TotalLinesRemoved int     `json:"total_lines_removed"`
+	} `json:"cost"`
+}
+
+// ParseClaudeCodeStatus reads the statusline context Claude Code writes to stdin
+func ParseClaudeCodeStatus(r io.Reader) (*ClaudeCodeStatus, error) {
+	var status ClaudeCodeStatus
+	if err := json.NewDecoder(r).Decode(&status); err != nil {
+		return nil, fmt.Errorf("failed to parse Claude Code statusline input: %w", err)
+	}
+	return &status, nil
+}
+
+// NewStatusLine returns the status of the active session block, which is nil when no
+// session is active
+func NewStatusLine(block *models.SessionBlock, tokenLimit int, now time.Time) StatusLine {
+	status := StatusLine{TokenLimit: tokenLimit, GeneratedAt: now}
+	if block != nil {
+		status.Active = true
+		status.SessionID = block.ID
+		status.Tokens = block.TokenCounts.TotalTokens()
+		status.CostUSD = block.CostUSD
+		status.ResetAt = block.EndTime
+	}
+	return status
+}
+
+// Percent returns the session's tokens as a percentage of the token limit, 0 without a limit
+func (s StatusLine) Percent() float64 {
+	if s.TokenLimit <= 0 {
+		return 0
+	}
+	return float64(s.Tokens) / float64(s.TokenLimit) * 100
+}
+
+// statusLineView is what status line templates are executed with
+type statusLineView struct {
+	Active  bool
+	Percent int    // Tokens as a percentage of the token limit
+	Tokens  string // Compact token count, e.g. 1.2M
+	Limit   string // Compact token limit; empty without a limit
+	Cost    string // e.g. $3.21
+	Reset   string // Time until the session resets, e.g. 2h13m
+	ResetAt time.Time
+
+	// From Claude Code's statusline context; empty outside Claude Code
+	Model        string // Model display name, e.g. Opus
+	Dir          string // Base name of the working directory
+	SessionCost  string // Cost of the Claude Code conversation, e.g. $0.12
+	LinesAdded   int
+	LinesRemoved int
+}
+
+// StatusLineFormatter renders a StatusLine as one line with a Go text/template, colored by
+// how much of the token limit is used
+type StatusLineFormatter struct {
+	template *template.Template
+	color    string
+}
+
+// NewStatusLineFormatter creates a formatter; an empty format uses DefaultStatusLineFormat
+func NewStatusLineFormatter(format, color string) (*StatusLineFormatter, error) {
+	if format == "" {
+		format = DefaultStatusLineFormat
+	}
+	tmpl, err := template.New("statusline").Parse(format)
+	if err != nil {
+		return nil, fmt.Errorf("invalid status line format: %w", err)
+	}
+	switch color {
+	case "", StatusColorNone, StatusColorANSI, StatusColorTmux:
+	default:
+		return nil, fmt.Errorf("invalid status line color %q (valid: none, ansi, tmux)", color)
+	}
+	return &StatusLineFormatter{template: tmpl, color: color}, nil
+}
+
+// Format renders status at now
+func (f *StatusLineFormatter) Format(status StatusLine, now time.Time) (string, error) {
+	view := statusLineView{
+		Active:  status.Active,
+		Percent: int(status.Percent()),
+		Tokens:  formatCompactTokens(status.Tokens),
+		Cost:    fmt.Sprintf("$%.2f", status.CostUSD),
+		Reset:   formatResetIn(status.ResetAt.Sub(now)),
+		ResetAt: status.ResetAt,
+	}
+	if status.TokenLimit > 0 {
+		view.Limit = formatCompactTokens(status.TokenLimit)
+	}
+	if cc := status.ClaudeCode; cc != nil {
+		view.Model = cc.Model.DisplayName
+		if view.Model == "" {
+			view.Model = cc.Model.ID
+		}
+		dir := cc.Workspace.CurrentDir
+		if dir == "" {
+			dir = cc.Cwd
+		}
+		if dir != "" {
+			view.Dir = filepath.Base(dir)
+		}
+		view.SessionCost = fmt.Sprintf("$%.2f", cc.Cost.TotalCostUSD)
+		view.LinesAdded, view.LinesRemoved = cc.Cost.TotalLinesAdded, cc.Cost.TotalLinesRemoved
+	}
+
+	var b strings.Builder
+	if err := f.template.Execute(&b, view); err != nil {
+		return "", fmt.Errorf("failed to render status line: %w", err)
+	}
+	line := strings.ReplaceAll(b.String(), "\n", " ")
+	if !status.Active || status.TokenLimit <= 0 {
+		return line, nil
+	}
+
+	level := "green"
+	switch percent := status.Percent(); {
+	case percent >= 90:
+		level = "red"
+	case percent >= 75:
+		level = "yellow"
+	}
+	switch f.color {
+	case StatusColorANSI:
+		codes := map[string]string{"green": "32", "yellow": "33", "red": "31"}
+		return "\033[" + codes[level] + "m" + line + "\033[0m", nil
+	case StatusColorTmux:
+		return "#[fg=" + level + "]" + line + "#[default]", nil
+	}
+	return line, nil
+}
+
+// formatCompactTokens formats n with a k or M suffix, e.g. 1.2M
+func formatCompactTokens(n int) string {
+	switch {
+	case n >= 1_000_000:
+		return strings.TrimSuffix(fmt.Sprintf("%.1f", float64(n)/1_000_000), ".0") + "M"
+	case n >= 1_000:
+		return strings.TrimSuffix(fmt.Sprintf("%.1f", float64(n)/1_000), ".0") + "k"
+	}
+	return fmt.Sprintf("%d", n)
+}
+
+// formatResetIn formats the time until a reset as hours and minutes, e.g. 2h13m
+func formatResetIn(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	minutes := int(d.Round(time.Minute).Minutes())
+	if minutes >= 60 {
+		return fmt.Sprintf("%dh%02dm", minutes/60, minutes%60)
+	}
+	return fmt.Sprintf("%dm", minutes)
+}
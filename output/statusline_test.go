@@ -0,0 +1,94 @@
+package output
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/penwyp/claudecat/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusLineFormatter_Format(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	block := &models.SessionBlock{
+		ID:          "s1",
+		EndTime:     now.Add(2*time.Hour + 13*time.Minute),
+		TokenCounts: models.TokenCounts{InputTokens: 19800},
+		CostUSD:     3.214,
+	}
+	status := NewStatusLine(block, 44000, now)
+
+	formatter, err := NewStatusLineFormatter("", StatusColorNone)
+	require.NoError(t, err)
+	line, err := formatter.Format(status, now)
+	require.NoError(t, err)
+	assert.Equal(t, "45% · $3.21 · 2h13m", line)
+
+	// Without a token limit the tokens are shown instead
+	line, err = formatter.Format(NewStatusLine(block, 0, now), now)
+	require.NoError(t, err)
+	assert.Equal(t, "19.8k · $3.21 · 2h13m", line)
+
+	line, err = formatter.Format(NewStatusLine(nil, 44000, now), now)
+	require.NoError(t, err)
+	assert.Equal(t, "idle", line)
+}
+
+func TestStatusLineFormatter_Colors(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	status := StatusLine{Active: true, Tokens: 40000, TokenLimit: 44000, ResetAt: now.Add(30 * time.Minute)}
+
+	tmux, err := NewStatusLineFormatter("{{.Percent}}% {{.Reset}}", StatusColorTmux)
+	require.NoError(t, err)
+	line, err := tmux.Format(status, now)
+	require.NoError(t, err)
+	assert.Equal(t, "#[fg=red]90% 30m#[default]", line)
+
+	ansi, err := NewStatusLineFormatter("{{.Tokens}}/{{.Limit}}", StatusColorANSI)
+	require.NoError(t, err)
+	status.Tokens = 1_500_000
+	status.TokenLimit = 5_000_000
+	line, err = ansi.Format(status, now)
+	require.NoError(t, err)
+	assert.Equal(t, "\033[32m1.5M/5M\033[0m", line)
+}
+
+func TestNewStatusLineFormatter_Invalid(t *testing.T) {
+	_, err := NewStatusLineFormatter("{{.Percent", "")
+	assert.Error(t, err)
+	_, err = NewStatusLineFormatter("", "blue")
+	assert.Error(t, err)
+}
+
+func TestStatusLineFormatter_ClaudeCode(t *testing.T) {
+	input := `{
+		"hook_event_name": "Status",
+		"session_id": "abc123",
+		"cwd": "/home/me/src/claudecat",
+		"model": {"id": "claude-opus-4-1", "display_name": "Opus"},
+		"workspace": {"current_dir": "/home/me/src/claudecat/cmd", "project_dir": "/home/me/src/claudecat"},
+		"version": "1.0.80",
+		"cost": {"total_cost_usd": 0.1234, "total_duration_ms": 45000, "total_lines_added": 156, "total_lines_removed": 23}
+	}`
+	claudeCode, err := ParseClaudeCodeStatus(strings.NewReader(input))
+	require.NoError(t, err)
+
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	status := StatusLine{Active: true, Tokens: 22000, TokenLimit: 44000, ResetAt: now.Add(time.Hour), ClaudeCode: claudeCode}
+	formatter, err := NewStatusLineFormatter(DefaultClaudeCodeStatusLineFormat, StatusColorNone)
+	require.NoError(t, err)
+	line, err := formatter.Format(status, now)
+	require.NoError(t, err)
+	assert.Equal(t, "[Opus] cmd · $0.12 · 50% · 1h00m", line)
+
+	lines, err := NewStatusLineFormatter("+{{.LinesAdded}} -{{.LinesRemoved}}", StatusColorNone)
+	require.NoError(t, err)
+	line, err = lines.Format(status, now)
+	require.NoError(t, err)
+	assert.Equal(t, "+156 -23", line)
+
+	_, err = ParseClaudeCodeStatus(strings.NewReader("not json"))
+	assert.Error(t, err)
+}
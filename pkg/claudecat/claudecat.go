@@ -0,0 +1,177 @@
+// Package claudecat is the Go library API of claudecat. It loads Claude usage logs,
+// groups them into session blocks and calculates the live metrics the claudecat
+// commands show, for tools that build on claudecat's data without its CLI:
+//
+//	cfg := config.DefaultConfig()
+//	usage, err := claudecat.Load(ctx, cfg, claudecat.LoadOptions{})
+//	if err != nil {
+//		return err
+//	}
+//	analysis := claudecat.Analyze(cfg, usage)
+//	if active := analysis.ActiveBlock(); active != nil {
+//		fmt.Printf("$%.2f in the current session\n", active.CostUSD)
+//	}
+//
+// Watch keeps the analysis current as Claude writes new usage.
+package claudecat
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/penwyp/claudecat/calculations"
+	"github.com/penwyp/claudecat/config"
+	"github.com/penwyp/claudecat/fileio"
+	"github.com/penwyp/claudecat/logging"
+	"github.com/penwyp/claudecat/models"
+	"github.com/penwyp/claudecat/models/pricing"
+	"github.com/penwyp/claudecat/sessions"
+)
+
+// LoadOptions selects what Load reads
+type LoadOptions struct {
+	HoursBack     int  // Only entries from the last N hours; 0 loads all
+	IncludeLimits bool // Also keep the raw limit messages, which bypasses the summary cache
+	UseCache      bool // Read and write the configured summary cache
+
+	// Quick answers one-shot queries without paying for a full load: files last modified
+	// before the HoursBack window are skipped unread, and the summary cache is opened
+	// read-only and the dedup index never saved, so a running monitor is left undisturbed
+	Quick bool
+}
+
+// quickLoadSlack widens the modification time window of quick loads, for logs whose
+// timestamps run ahead of the file system's clock
+const quickLoadSlack = time.Hour
+
+// Usage is the usage loaded from the data paths
+type Usage struct {
+	Entries      []models.UsageEntry      // Sorted by timestamp
+	LimitRecords []map[string]interface{} // Raw limit message records, with IncludeLimits only
+}
+
+// Analysis is usage grouped into session blocks, with the metrics of the current session
+type Analysis struct {
+	Blocks    []models.SessionBlock                 // Oldest first, gaps included
+	Metrics   *calculations.EnhancedRealtimeMetrics // Metrics of the active session
+	UpdatedAt time.Time
+}
+
+// ActiveBlock returns the active session block, or nil when no session is active
+func (a *Analysis) ActiveBlock() *models.SessionBlock {
+	for i := range a.Blocks {
+		if a.Blocks[i].IsActive && !a.Blocks[i].IsGap {
+			return &a.Blocks[i]
+		}
+	}
+	return nil
+}
+
+// Load reads the usage entries of every data path of cfg, after applying its process-wide
+// settings (see ApplySettings). Paths that don't exist or fail to load are logged and
+// skipped; an error is returned only for invalid settings or when ctx is done.
+func Load(ctx context.Context, cfg *config.Config, opts LoadOptions) (*Usage, error) {
+	if err := ApplySettings(cfg); err != nil {
+		return nil, err
+	}
+	cacheDir := CacheDir(cfg)
+
+	pricingProvider, err := pricing.CreatePricingProvider(&cfg.Data, cacheDir)
+	if err != nil {
+		logging.LogErrorf("Failed to create pricing provider: %v", err)
+		pricingProvider = pricing.NewDefaultProvider()
+	}
+
+	// Cached files carry neither raw records nor exact per-message timestamps
+	var cacheStore fileio.CacheStore
+	if opts.UseCache && !opts.IncludeLimits {
+		openStore := OpenSummaryStore
+		if opts.Quick {
+			openStore = OpenReadOnlySummaryStore
+		}
+		if summaryStore, err := openStore(cfg); err != nil {
+			logging.LogErrorf("Failed to open %s summary cache: %v", cfg.Cache.Backend, err)
+		} else {
+			defer summaryStore.Close()
+			cacheStore = summaryStore
+		}
+	}
+
+	var hoursBack *int
+	var modifiedSince time.Time
+	if opts.HoursBack > 0 {
+		hoursBack = &opts.HoursBack
+		if opts.Quick {
+			modifiedSince = time.Now().Add(-time.Duration(opts.HoursBack)*time.Hour - quickLoadSlack)
+		}
+	}
+	index := dedupIndex(cfg, cacheDir)
+
+	usage := &Usage{}
+	for _, path := range DataPaths(cfg) {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			logging.LogWarnf("Data path does not exist: %s", path)
+			continue
+		}
+
+		result, err := fileio.LoadUsageEntriesContext(ctx, fileio.LoadUsageEntriesOptions{
+			DataPath:            path,
+			HoursBack:           hoursBack,
+			Mode:                CostMode(cfg),
+			IncludeRaw:          opts.IncludeLimits,
+			RawFilter:           sessions.IsLimitRecord,
+			RedactRaw:           cfg.Privacy.RedactContent,
+			CacheStore:          cacheStore,
+			EnableDeduplication: cfg.Data.Deduplication,
+			DedupIndex:          index,
+			PricingProvider:     pricingProvider,
+			Validator:           EntryValidator(cfg),
+			Concurrency:         Concurrency(cfg),
+			MemoryBudget:        cfg.Performance.MaxMemory,
+			ModifiedSince:       modifiedSince,
+			ReadOnly:            opts.Quick,
+		})
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("failed to load usage entries: %w", ctx.Err())
+		}
+		if err != nil {
+			logging.LogErrorf("Failed to load usage entries from %s: %v", path, err)
+			continue
+		}
+
+		usage.Entries = append(usage.Entries, result.Entries...)
+		usage.LimitRecords = append(usage.LimitRecords, result.RawEntries...)
+	}
+
+	sort.Slice(usage.Entries, func(i, j int) bool {
+		return usage.Entries[i].Timestamp.Before(usage.Entries[j].Timestamp)
+	})
+	return usage, nil
+}
+
+// Analyze groups usage into session blocks of the configured window, attaches the
+// detected limit messages and calculates the metrics of the active session. Month periods
+// follow the billing day of cfg.
+func Analyze(cfg *config.Config, usage *Usage) *Analysis {
+	models.SetBillingDay(cfg.Subscription.BillingDay)
+	analyzer := sessions.NewSessionAnalyzerWithDuration(cfg.Session.WindowDuration)
+	blocks := analyzer.TransformToBlocks(usage.Entries)
+	if len(usage.LimitRecords) > 0 {
+		sessions.AttachLimits(blocks, analyzer.DetectLimits(usage.LimitRecords))
+	}
+	return newAnalysis(calculations.NewEnhancedMetricsCalculator(cfg), blocks)
+}
+
+// newAnalysis calculates the metrics of blocks with calc, which keeps the rate history
+// of earlier updates
+func newAnalysis(calc *calculations.EnhancedMetricsCalculator, blocks []models.SessionBlock) *Analysis {
+	calc.UpdateSessionBlocks(blocks)
+	return &Analysis{
+		Blocks:    blocks,
+		Metrics:   calc.Calculate(),
+		UpdatedAt: time.Now(),
+	}
+}
@@ -0,0 +1,158 @@
+package claudecat
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/penwyp/claudecat/config"
+	"github.com/penwyp/claudecat/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testConfig returns a configuration reading a project with one old and one active
+// session, and a limit message in the active one
+func testConfig(t *testing.T, now time.Time) *config.Config {
+	t.Helper()
+	dataDir := t.TempDir()
+	projectDir := filepath.Join(dataDir, "projects", "proj-a")
+	require.NoError(t, os.MkdirAll(projectDir, 0o755))
+
+	assistant := `{"type":"assistant","timestamp":"%s","requestId":"%s","message":{"id":"%s","model":"claude-sonnet-4-20250514","usage":{"input_tokens":1000,"output_tokens":500}}}`
+	lines := []string{
+		fmt.Sprintf(assistant, now.Add(-48*time.Hour).Format(time.RFC3339), "r1", "m1"),
+		fmt.Sprintf(assistant, now.Add(-30*time.Minute).Format(time.RFC3339), "r2", "m2"),
+		fmt.Sprintf(assistant, now.Add(-20*time.Minute).Format(time.RFC3339), "r3", "m3"),
+		fmt.Sprintf(`{"type":"system","timestamp":"%s","content":"Claude usage limit reached. Your limit will reset at 3pm"}`,
+			now.Add(-10*time.Minute).Format(time.RFC3339)),
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, "session.jsonl"), []byte(strings.Join(lines, "\n")+"\n"), 0o644))
+
+	cfg := config.DefaultConfig()
+	cfg.Data.Paths = []string{dataDir}
+	cfg.Cache.Dir = t.TempDir()
+	return cfg
+}
+
+func TestLoadAndAnalyze(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	cfg := testConfig(t, now)
+
+	usage, err := Load(context.Background(), cfg, LoadOptions{IncludeLimits: true})
+	require.NoError(t, err)
+	require.Len(t, usage.Entries, 3)
+	assert.True(t, usage.Entries[0].Timestamp.Before(usage.Entries[1].Timestamp))
+	assert.Len(t, usage.LimitRecords, 1)
+
+	analysis := Analyze(cfg, usage)
+	active := analysis.ActiveBlock()
+	require.NotNil(t, active)
+	assert.Equal(t, 2, len(active.Entries))
+	assert.Len(t, active.LimitMessages, 1)
+	require.NotNil(t, analysis.Metrics)
+	assert.Equal(t, 3000, analysis.Metrics.CurrentTokens)
+
+	// Without the limit records only the entries are loaded
+	usage, err = Load(context.Background(), cfg, LoadOptions{HoursBack: 1})
+	require.NoError(t, err)
+	assert.Len(t, usage.Entries, 2)
+	assert.Empty(t, usage.LimitRecords)
+}
+
+func TestLoad_Quick(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	cfg := testConfig(t, now)
+
+	usage, err := Load(context.Background(), cfg, LoadOptions{HoursBack: 1, UseCache: true, Quick: true})
+	require.NoError(t, err)
+	assert.Len(t, usage.Entries, 2)
+
+	// A log last modified before the window isn't read at all
+	file := filepath.Join(cfg.Data.Paths[0], "projects", "proj-a", "session.jsonl")
+	earlier := now.Add(-3 * time.Hour)
+	require.NoError(t, os.Chtimes(file, earlier, earlier))
+	usage, err = Load(context.Background(), cfg, LoadOptions{HoursBack: 1, UseCache: true, Quick: true})
+	require.NoError(t, err)
+	assert.Empty(t, usage.Entries)
+}
+
+func TestLoad_QuickReadsSummaryCache(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	cfg := testConfig(t, now)
+	file := filepath.Join(cfg.Data.Paths[0], "projects", "proj-a", "session.jsonl")
+	earlier := now.Add(-10 * time.Minute)
+	require.NoError(t, os.Chtimes(file, earlier, earlier))
+
+	// A full load, like a running monitor's, fills the summary cache
+	usage, err := Load(context.Background(), cfg, LoadOptions{HoursBack: 2, UseCache: true})
+	require.NoError(t, err)
+	require.Len(t, usage.Entries, 2)
+
+	// The file isn't parsed again: its entries come from the cached summary
+	usage, err = Load(context.Background(), cfg, LoadOptions{HoursBack: 2, UseCache: true, Quick: true})
+	require.NoError(t, err)
+	require.Len(t, usage.Entries, 2)
+	for _, entry := range usage.Entries {
+		assert.Equal(t, models.CostSourceSummary, entry.CostSource)
+	}
+
+	// Without the cache, a quick load parses the file
+	usage, err = Load(context.Background(), cfg, LoadOptions{HoursBack: 2, Quick: true})
+	require.NoError(t, err)
+	require.Len(t, usage.Entries, 2)
+	assert.NotEqual(t, models.CostSourceSummary, usage.Entries[0].CostSource)
+}
+
+func TestLoad_AppliesSettings(t *testing.T) {
+	t.Cleanup(func() { require.NoError(t, ApplySettings(config.DefaultConfig())) })
+	now := time.Now().UTC().Truncate(time.Second)
+	cfg := testConfig(t, now)
+	cfg.Data.ModelAliases = []config.ModelAliasConfig{{Pattern: "claude-sonnet-4-*", Model: "claude-opus-4-20250514"}}
+	cfg.Data.CostAllocation = []config.AllocationRuleConfig{{Project: "^proj-a$", Tag: "team-a"}}
+	cfg.Subscription.BillingDay = 17
+
+	usage, err := Load(context.Background(), cfg, LoadOptions{})
+	require.NoError(t, err)
+	require.NotEmpty(t, usage.Entries)
+	for _, entry := range usage.Entries {
+		assert.Equal(t, "claude-opus-4-20250514", entry.Model)
+		assert.Equal(t, "team-a", entry.Tag)
+	}
+	assert.Equal(t, 17, models.BillingDay())
+
+	// Logs the configured patterns exclude aren't read
+	cfg.Data.Exclude = []string{"*/proj-a/*"}
+	usage, err = Load(context.Background(), cfg, LoadOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, usage.Entries)
+
+	// Invalid settings fail the load and leave those in effect alone
+	invalid := testConfig(t, now)
+	invalid.Data.ModelAliases = []config.ModelAliasConfig{{Pattern: " ", Model: "claude-opus-4-20250514"}}
+	invalid.Subscription.BillingDay = 3
+	_, err = Load(context.Background(), invalid, LoadOptions{})
+	assert.ErrorContains(t, err, "invalid model alias")
+	assert.Equal(t, 17, models.BillingDay())
+
+	Analyze(invalid, &Usage{})
+	assert.Equal(t, 3, models.BillingDay(), "analysis follows the billing day of its configuration")
+}
+
+func TestLoad_Canceled(t *testing.T) {
+	cfg := testConfig(t, time.Now())
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := Load(ctx, cfg, LoadOptions{})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestAnalysis_NoActiveBlock(t *testing.T) {
+	analysis := Analyze(config.DefaultConfig(), &Usage{})
+	assert.Nil(t, analysis.ActiveBlock())
+}
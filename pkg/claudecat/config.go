@@ -0,0 +1,176 @@
+package claudecat
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/penwyp/claudecat/cache"
+	"github.com/penwyp/claudecat/config"
+	"github.com/penwyp/claudecat/fileio"
+	"github.com/penwyp/claudecat/logging"
+	"github.com/penwyp/claudecat/models"
+)
+
+// DataPaths returns the configured data paths, with ~/ and, inside WSL, Windows paths
+// resolved (see fileio.NormalizeDataPath), falling back to every standard Claude data
+// location that exists (see fileio.CandidateDataPaths)
+func DataPaths(cfg *config.Config) []string {
+	if len(cfg.Data.Paths) > 0 {
+		paths := make([]string, len(cfg.Data.Paths))
+		for i, path := range cfg.Data.Paths {
+			paths[i] = fileio.NormalizeDataPath(path)
+		}
+		return paths
+	}
+	return fileio.DefaultDataPaths()
+}
+
+// CacheDir returns the configured cache directory with a leading ~/ expanded
+func CacheDir(cfg *config.Config) string {
+	cacheDir := cfg.Cache.Dir
+	if len(cacheDir) >= 2 && cacheDir[:2] == "~/" {
+		homeDir, _ := os.UserHomeDir()
+		cacheDir = filepath.Join(homeDir, cacheDir[2:])
+	}
+	return cacheDir
+}
+
+// OpenSummaryStore opens the configured summary cache backend
+func OpenSummaryStore(cfg *config.Config) (cache.SummaryStore, error) {
+	return cache.OpenSummaryStore(SummaryBackend(cfg, false))
+}
+
+// OpenReadOnlySummaryStore opens the configured summary cache backend without creating or
+// changing anything, so one-shot commands can share it with a running monitor
+func OpenReadOnlySummaryStore(cfg *config.Config) (cache.SummaryStore, error) {
+	return cache.OpenSummaryStore(SummaryBackend(cfg, true))
+}
+
+// SummaryBackend returns the configured summary cache backend, in the cache directory
+// of cfg
+func SummaryBackend(cfg *config.Config, readOnly bool) cache.BackendConfig {
+	return cache.NewBackendConfig(cfg.Cache, CacheDir(cfg), readOnly)
+}
+
+// CostMode returns the configured cost mode, falling back to auto
+func CostMode(cfg *config.Config) models.CostMode {
+	mode, err := models.ParseCostMode(cfg.Data.CostMode)
+	if err != nil {
+		logging.LogWarnf("%v, falling back to auto", err)
+	}
+	return mode
+}
+
+// EntryValidator returns the configured entry validator, or nil when validation is off
+func EntryValidator(cfg *config.Config) *models.EntryValidator {
+	validation := cfg.Data.Validation
+	return models.NewEntryValidator(models.EntryBounds(validation.Bounds), validation.Action, validation.IncludeSuspect)
+}
+
+// Concurrency returns how the loader schedules files across workers
+func Concurrency(cfg *config.Config) fileio.ConcurrencyOptions {
+	return fileio.ConcurrencyOptions{
+		MaxWorkers:   cfg.Performance.WorkerCount,
+		Threshold:    cfg.Performance.ConcurrencyThreshold,
+		InOrder:      cfg.Performance.Scheduling == "in_order",
+		FixedWorkers: cfg.Performance.FixedWorkers,
+	}
+}
+
+// ApplySettings installs the settings of cfg that apply process-wide, as entries are parsed
+// and periods calculated everywhere: model aliases, cost allocation rules, the discovery
+// filter and the billing day. Load and Watch apply them, so the configuration passed last
+// is in effect; nothing is installed when a pattern is invalid.
+func ApplySettings(cfg *config.Config) error {
+	aliases, err := modelAliases(cfg)
+	if err != nil {
+		return err
+	}
+	rules, err := allocationRules(cfg)
+	if err != nil {
+		return err
+	}
+	filter, err := discoveryFilter(cfg)
+	if err != nil {
+		return err
+	}
+
+	models.SetModelAliases(aliases)
+	models.SetAllocationRules(rules)
+	fileio.SetDiscoveryFilter(filter)
+	models.SetBillingDay(cfg.Subscription.BillingDay)
+	return nil
+}
+
+// modelAliases compiles the configured model aliases for model name normalization
+func modelAliases(cfg *config.Config) ([]models.ModelAlias, error) {
+	aliases := make([]models.ModelAlias, 0, len(cfg.Data.ModelAliases))
+	for _, alias := range cfg.Data.ModelAliases {
+		compiled, err := models.NewModelAlias(alias.Pattern, alias.Model)
+		if err != nil {
+			return nil, fmt.Errorf("invalid model alias: %w", err)
+		}
+		aliases = append(aliases, compiled)
+	}
+	return aliases, nil
+}
+
+// allocationRules compiles the configured cost allocation rules entries are tagged with
+func allocationRules(cfg *config.Config) ([]models.AllocationRule, error) {
+	rules := make([]models.AllocationRule, 0, len(cfg.Data.CostAllocation))
+	for _, rule := range cfg.Data.CostAllocation {
+		path := rule.Path
+		if strings.HasPrefix(path, "~/") {
+			if home, err := os.UserHomeDir(); err == nil {
+				path = filepath.Join(home, path[2:])
+			}
+		}
+		compiled, err := models.NewAllocationRule(path, rule.Project, rule.Tag)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cost allocation rule: %w", err)
+		}
+		rules = append(rules, compiled)
+	}
+	return rules, nil
+}
+
+// discoveryFilter compiles the configured patterns of the logs discovery reads and skips
+func discoveryFilter(cfg *config.Config) (fileio.DiscoveryFilter, error) {
+	compile := func(kind string, patterns []string) ([]fileio.PathPattern, error) {
+		compiled := make([]fileio.PathPattern, 0, len(patterns))
+		for _, pattern := range patterns {
+			p, err := fileio.NewPathPattern(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s pattern: %w", kind, err)
+			}
+			compiled = append(compiled, p)
+		}
+		return compiled, nil
+	}
+
+	include, err := compile("include", cfg.Data.Include)
+	if err != nil {
+		return fileio.DiscoveryFilter{}, err
+	}
+	exclude, err := compile("exclude", cfg.Data.Exclude)
+	if err != nil {
+		return fileio.DiscoveryFilter{}, err
+	}
+	return fileio.DiscoveryFilter{Include: include, Exclude: exclude}, nil
+}
+
+// dedupIndex opens the persistent dedup index when deduplication is enabled. It returns
+// nil when deduplication is off or the index can't be opened.
+func dedupIndex(cfg *config.Config, cacheDir string) *cache.DedupIndex {
+	if !cfg.Data.Deduplication {
+		return nil
+	}
+	index, err := cache.OpenDedupIndex(cacheDir, cfg.Data.DedupRetention)
+	if err != nil {
+		logging.LogWarnf("Persistent deduplication disabled: %v", err)
+		return nil
+	}
+	return index
+}
@@ -0,0 +1,39 @@
+package claudecat
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/penwyp/claudecat/calculations"
+	"github.com/penwyp/claudecat/config"
+	"github.com/penwyp/claudecat/orchestrator"
+)
+
+// Watch monitors the data paths of cfg like the claudecat console and calls fn with a new
+// analysis after every refresh, until ctx is done. Refreshes follow the configured
+// refresh rate and file changes; fn runs on the monitoring goroutine and should return
+// quickly. The process-wide settings of cfg are applied first (see ApplySettings).
+func Watch(ctx context.Context, cfg *config.Config, fn func(*Analysis)) error {
+	if err := ApplySettings(cfg); err != nil {
+		return err
+	}
+
+	interval := cfg.UI.RefreshRate
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+
+	calc := calculations.NewEnhancedMetricsCalculator(cfg)
+	monitor := orchestrator.NewMonitoringOrchestrator(interval, DataPaths(cfg), cfg)
+	monitor.RegisterUpdateCallback(func(data orchestrator.MonitoringData) {
+		fn(newAnalysis(calc, data.Data.Blocks))
+	})
+	if err := monitor.Start(); err != nil {
+		return fmt.Errorf("failed to start monitoring: %w", err)
+	}
+	defer monitor.Stop()
+
+	<-ctx.Done()
+	return nil
+}
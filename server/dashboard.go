@@ -0,0 +1,63 @@
+package server
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+// dashboardFiles holds the web dashboard, a static page that polls the JSON API
+//
+//go:embed dashboard
+var dashboardFiles embed.FS
+
+// dashboardHandler serves the dashboard assets under /dashboard/
+func dashboardHandler() http.Handler {
+	assets, err := fs.Sub(dashboardFiles, "dashboard")
+	if err != nil {
+		panic(err) // The embedded directory is fixed at build time
+	}
+	return http.StripPrefix("/dashboard/", http.FileServer(http.FS(assets)))
+}
+
+// handleIndex serves the dashboard page at / and a JSON 404 for every other unknown path
+func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	enabled := s.dashboard
+	s.mu.RUnlock()
+
+	if r.URL.Path != "/" || !enabled {
+		writeNotFound(w, r)
+		return
+	}
+	page, err := dashboardFiles.ReadFile("dashboard/index.html")
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "dashboard unavailable")
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	_, _ = w.Write(page)
+}
+
+// serveDashboardAssets serves the dashboard's scripts and styles while the dashboard is enabled
+func (s *Server) serveDashboardAssets(assets http.Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.RLock()
+		enabled := s.dashboard
+		s.mu.RUnlock()
+
+		if !enabled {
+			writeNotFound(w, r)
+			return
+		}
+		assets.ServeHTTP(w, r)
+	}
+}
+
+// SetDashboard enables or disables the web dashboard; it is enabled by default
+func (s *Server) SetDashboard(enabled bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dashboard = enabled
+}
@@ -0,0 +1,190 @@
+// claudecat dashboard: follows the metrics stream of the JSON API and renders the live
+// gauges, the hourly burn rate and the session history. Browsers without EventSource
+// poll instead. No dependencies, so it works offline.
+(function () {
+  "use strict";
+
+  var REFRESH_MS = 5000;
+  var HISTORY_BLOCKS = 20;
+  var CHART_HOURS = 24;
+  var NS_PER_MINUTE = 60e9;
+
+  var numberFormat = new Intl.NumberFormat();
+
+  function $(selector) {
+    return document.querySelector(selector);
+  }
+
+  function fetchJSON(path) {
+    return fetch(path, { cache: "no-store" }).then(function (res) {
+      return res.json().then(function (body) {
+        if (!res.ok) {
+          throw new Error(body.error || res.statusText);
+        }
+        return body;
+      });
+    });
+  }
+
+  function formatCost(usd) {
+    return "$" + (usd || 0).toFixed(2);
+  }
+
+  function formatTime(value) {
+    var date = new Date(value);
+    if (isNaN(date) || date.getFullYear() < 2000) {
+      return "–";
+    }
+    return date.toLocaleString([], { month: "short", day: "numeric", hour: "2-digit", minute: "2-digit" });
+  }
+
+  function formatDuration(ns) {
+    var minutes = Math.max(0, Math.round(ns / NS_PER_MINUTE));
+    return Math.floor(minutes / 60) + "h " + (minutes % 60) + "m";
+  }
+
+  function setGauge(id, percent, text) {
+    var fill = $("#" + id + " .fill");
+    var clamped = Math.max(0, Math.min(100, percent || 0));
+    fill.style.width = clamped + "%";
+    fill.className = "fill" + (percent >= 90 ? " critical" : percent >= 70 ? " warn" : "");
+    $("#" + id + " .value").textContent = text;
+  }
+
+  function blockTokens(block) {
+    var counts = block.token_counts || {};
+    return (counts.input_tokens || 0) + (counts.output_tokens || 0) +
+      (counts.cache_creation_tokens || 0) + (counts.cache_read_tokens || 0);
+  }
+
+  function renderMetrics(payload) {
+    var m = payload.metrics || {};
+    var limit = payload.token_limit || 0;
+
+    var tokenPercent = limit > 0 ? m.current_tokens / limit * 100 : 0;
+    setGauge("gauge-tokens", tokenPercent, numberFormat.format(m.current_tokens || 0) +
+      (limit > 0 ? " / " + numberFormat.format(limit) + " (" + tokenPercent.toFixed(1) + "%)" : ""));
+
+    var projectedCost = m.projection ? m.projection.projected_total_cost : 0;
+    var costPercent = projectedCost > 0 ? m.current_cost / projectedCost * 100 : 0;
+    setGauge("gauge-cost", costPercent, formatCost(m.current_cost) +
+      (projectedCost > 0 ? " of " + formatCost(projectedCost) + " projected" : ""));
+
+    setGauge("gauge-time", m.session_progress, m.is_active
+      ? formatDuration(m.time_remaining) + " remaining"
+      : "no active session");
+
+    $("#rate-tokens").textContent = (m.tokens_per_minute || 0).toFixed(1) + " tokens/min";
+    $("#rate-cost").textContent = "$" + (m.cost_per_hour || 0).toFixed(2) + "/hour";
+    $("#depletion").textContent = m.depletion && m.depletion.tokens_per_minute > 0
+      ? formatTime(m.depletion.depletion_time)
+      : "–";
+    $("#reset").textContent = m.is_active ? formatTime(m.session_end) : "–";
+  }
+
+  function renderBurnChart(trend) {
+    var svg = $("#burn-chart");
+    var buckets = (trend.buckets || []).slice(-CHART_HOURS);
+    var width = 720, height = 180, bottom = 16;
+    var max = 0;
+    buckets.forEach(function (bucket) {
+      max = Math.max(max, bucket.total_tokens / 60);
+    });
+
+    var slot = width / CHART_HOURS;
+    var offset = CHART_HOURS - buckets.length;
+    var parts = [];
+    buckets.forEach(function (bucket, i) {
+      var rate = bucket.total_tokens / 60;
+      var barHeight = max > 0 ? rate / max * (height - bottom - 8) : 0;
+      var x = (offset + i) * slot + 2;
+      var start = new Date(bucket.start);
+      parts.push('<rect x="' + x.toFixed(1) + '" y="' + (height - bottom - barHeight).toFixed(1) +
+        '" width="' + (slot - 4).toFixed(1) + '" height="' + barHeight.toFixed(1) + '"' +
+        (i === buckets.length - 1 ? ' class="current"' : "") + '><title>' +
+        start.toLocaleTimeString([], { hour: "2-digit", minute: "2-digit" }) + ": " +
+        rate.toFixed(1) + ' tokens/min</title></rect>');
+      if (start.getHours() % 3 === 0) {
+        parts.push('<text x="' + x.toFixed(1) + '" y="' + (height - 4) + '">' + start.getHours() + ':00</text>');
+      }
+    });
+    svg.innerHTML = parts.join("");
+  }
+
+  function renderSessions(payload) {
+    var tbody = $("#sessions tbody");
+    var blocks = (payload.blocks || []).slice().reverse();
+    tbody.innerHTML = "";
+    blocks.forEach(function (block) {
+      var row = document.createElement("tr");
+      if (block.is_active) {
+        row.className = "active";
+      }
+      [
+        formatTime(block.start_time),
+        block.is_active ? "active" : formatTime(block.actual_end_time || block.end_time),
+        (block.models || []).join(", "),
+        numberFormat.format(block.sent_messages_count || 0),
+        numberFormat.format(blockTokens(block)),
+        formatCost(block.cost_usd)
+      ].forEach(function (text, i) {
+        var cell = document.createElement("td");
+        cell.textContent = text;
+        if (i >= 3) {
+          cell.className = "num";
+        }
+        row.appendChild(cell);
+      });
+      tbody.appendChild(row);
+    });
+  }
+
+  function setStatus(text, isError) {
+    var status = $("#status");
+    status.className = "status" + (isError ? " error" : "");
+    status.textContent = text;
+  }
+
+  // update renders the metrics and reloads the chart and history they belong to
+  function update(metrics) {
+    renderMetrics(metrics);
+    return Promise.all([
+      fetchJSON("/api/v1/trend?interval=hourly"),
+      fetchJSON("/api/v1/blocks?limit=" + HISTORY_BLOCKS)
+    ]).then(function (results) {
+      renderBurnChart(results[0]);
+      renderSessions(results[1]);
+      setStatus("updated " + new Date(metrics.updated_at).toLocaleTimeString(), false);
+    });
+  }
+
+  function poll() {
+    fetchJSON("/api/v1/metrics").then(update).catch(function (err) {
+      setStatus(err.message, true);
+    }).then(function () {
+      setTimeout(poll, REFRESH_MS);
+    });
+  }
+
+  function stream() {
+    var source = new EventSource("/api/v1/stream");
+    source.addEventListener("metrics", function (event) {
+      update(JSON.parse(event.data)).catch(function (err) {
+        setStatus(err.message, true);
+      });
+    });
+    source.onopen = function () {
+      setStatus("connected, waiting for data…", false);
+    };
+    // EventSource reconnects on its own
+    source.onerror = function () {
+      setStatus("disconnected, reconnecting…", true);
+    };
+  }
+
+  if (window.EventSource) {
+    stream();
+  } else {
+    poll();
+  }
+})();
@@ -0,0 +1,280 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	claudecatv1 "github.com/penwyp/claudecat/api/claudecatv1"
+	"github.com/penwyp/claudecat/calculations"
+	"github.com/penwyp/claudecat/logging"
+	"github.com/penwyp/claudecat/models"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// GRPCServer serves the data of an API Server over gRPC with the protobuf types of
+// api/claudecatv1, for tools that embed claudecat as a usage data backend
+type GRPCServer struct {
+	claudecatv1.UnimplementedUsageServiceServer
+
+	addr       string
+	api        *Server
+	grpcServer *grpc.Server
+
+	closeOnce sync.Once
+	closing   chan struct{} // Closed on shutdown to end the WatchMetrics streams
+}
+
+// NewGRPCServer creates a gRPC server listening on addr (host:port) that serves the
+// data api receives through Update
+func NewGRPCServer(addr string, api *Server) *GRPCServer {
+	g := &GRPCServer{
+		addr:       addr,
+		api:        api,
+		grpcServer: grpc.NewServer(),
+		closing:    make(chan struct{}),
+	}
+	claudecatv1.RegisterUsageServiceServer(g.grpcServer, g)
+	return g
+}
+
+// Serve listens on the configured address and serves requests until Shutdown is called
+func (g *GRPCServer) Serve() error {
+	listener, err := net.Listen("tcp", g.addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", g.addr, err)
+	}
+	logging.LogInfof("gRPC API listening on %s", listener.Addr())
+
+	if err := g.grpcServer.Serve(listener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+		return fmt.Errorf("gRPC server failed: %w", err)
+	}
+	return nil
+}
+
+// Shutdown ends the metrics streams and gracefully stops the server, stopping it
+// forcibly when ctx is done first
+func (g *GRPCServer) Shutdown(ctx context.Context) error {
+	g.closeOnce.Do(func() { close(g.closing) })
+
+	stopped := make(chan struct{})
+	go func() {
+		g.grpcServer.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		g.grpcServer.Stop()
+		return ctx.Err()
+	}
+}
+
+// GetMetrics returns the real-time metrics of the current session
+func (g *GRPCServer) GetMetrics(ctx context.Context, req *claudecatv1.GetMetricsRequest) (*claudecatv1.GetMetricsResponse, error) {
+	if data, _ := g.api.snapshot(); data == nil {
+		return nil, errNotLoaded()
+	}
+	return metricsToProto(g.api.metricsResponse()), nil
+}
+
+// ListBlocks returns session blocks, oldest first
+func (g *GRPCServer) ListBlocks(ctx context.Context, req *claudecatv1.ListBlocksRequest) (*claudecatv1.ListBlocksResponse, error) {
+	data, updatedAt := g.api.snapshot()
+	if data == nil {
+		return nil, errNotLoaded()
+	}
+	if req.GetLimit() < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid limit: %d", req.GetLimit())
+	}
+
+	blocks := selectBlocks(data.Data.Blocks, int(req.GetLimit()), req.GetIncludeGaps(), req.GetIncludeEntries())
+	resp := &claudecatv1.ListBlocksResponse{
+		Blocks:    make([]*claudecatv1.SessionBlock, 0, len(blocks)),
+		UpdatedAt: timestamppb.New(updatedAt),
+	}
+	for i := range blocks {
+		resp.Blocks = append(resp.Blocks, blockToProto(&blocks[i]))
+	}
+	return resp, nil
+}
+
+// GetActiveSession returns the active session block, or NotFound when no session is active
+func (g *GRPCServer) GetActiveSession(ctx context.Context, req *claudecatv1.GetActiveSessionRequest) (*claudecatv1.SessionBlock, error) {
+	data, _ := g.api.snapshot()
+	if data == nil {
+		return nil, errNotLoaded()
+	}
+	block, ok := activeBlock(data.Data.Blocks, req.GetIncludeEntries())
+	if !ok {
+		return nil, status.Error(codes.NotFound, "no active session")
+	}
+	return blockToProto(&block), nil
+}
+
+// WatchMetrics sends the current metrics once data has been loaded, then new metrics
+// with every update, until the client goes away or the server shuts down
+func (g *GRPCServer) WatchMetrics(req *claudecatv1.WatchMetricsRequest, stream grpc.ServerStreamingServer[claudecatv1.GetMetricsResponse]) error {
+	ch := g.api.subscribe()
+	defer g.api.unsubscribe(ch)
+
+	if data, _ := g.api.snapshot(); data != nil {
+		if err := stream.Send(metricsToProto(g.api.metricsResponse())); err != nil {
+			return err
+		}
+	}
+	for {
+		select {
+		case metrics := <-ch:
+			if err := stream.Send(metricsToProto(metrics)); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return nil
+		case <-g.closing:
+			return nil
+		}
+	}
+}
+
+func errNotLoaded() error {
+	return status.Error(codes.Unavailable, "monitoring data not loaded yet")
+}
+
+// timestampToProto converts t, leaving zero times unset
+func timestampToProto(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+func metricsToProto(resp MetricsResponse) *claudecatv1.GetMetricsResponse {
+	return &claudecatv1.GetMetricsResponse{
+		Metrics:      realtimeMetricsToProto(resp.Metrics),
+		TokenLimit:   int64(resp.TokenLimit),
+		SessionId:    resp.SessionID,
+		SessionCount: int64(resp.SessionCount),
+		UpdatedAt:    timestampToProto(resp.UpdatedAt),
+	}
+}
+
+func realtimeMetricsToProto(m *calculations.EnhancedRealtimeMetrics) *claudecatv1.RealtimeMetrics {
+	if m == nil {
+		return nil
+	}
+	pb := &claudecatv1.RealtimeMetrics{
+		SessionStart:      timestampToProto(m.SessionStart),
+		SessionEnd:        timestampToProto(m.SessionEnd),
+		SessionProgress:   m.SessionProgress,
+		TimeRemaining:     durationpb.New(m.TimeRemaining),
+		IsActive:          m.IsActive,
+		CurrentTokens:     int64(m.CurrentTokens),
+		CurrentCost:       m.CurrentCost,
+		BurnRate:          burnRateToProto(m.BurnRate),
+		Projection:        projectionToProto(m.Projection),
+		ModelDistribution: make(map[string]*claudecatv1.ModelMetrics, len(m.ModelDistribution)),
+		TokensPerMinute:   m.TokensPerMinute,
+		TokensPerHour:     m.TokensPerHour,
+		CostPerMinute:     m.CostPerMinute,
+		CostPerHour:       m.CostPerHour,
+		ConfidenceLevel:   m.ConfidenceLevel,
+		HealthStatus:      m.HealthStatus,
+		LastUpdated:       timestampToProto(m.LastUpdated),
+	}
+	for model, stats := range m.ModelDistribution {
+		pb.ModelDistribution[model] = &claudecatv1.ModelMetrics{
+			TokenCounts: tokenCountsToProto(stats.TokenCounts),
+			TotalTokens: int64(stats.TotalTokens),
+			Cost:        stats.Cost,
+			Percentage:  stats.Percentage,
+			LastUsed:    timestampToProto(stats.LastUsed),
+			EntryCount:  int64(stats.EntryCount),
+		}
+	}
+	return pb
+}
+
+func blockToProto(block *models.SessionBlock) *claudecatv1.SessionBlock {
+	pb := &claudecatv1.SessionBlock{
+		Id:                block.ID,
+		StartTime:         timestampToProto(block.StartTime),
+		EndTime:           timestampToProto(block.EndTime),
+		IsActive:          block.IsActive,
+		IsGap:             block.IsGap,
+		TokenCounts:       tokenCountsToProto(block.TokenCounts),
+		CostUsd:           block.CostUSD,
+		Models:            block.Models,
+		SentMessagesCount: int64(block.SentMessagesCount),
+		BurnRate:          burnRateToProto(block.BurnRate),
+		Projection:        projectionToProto(block.ProjectionData),
+	}
+	if block.ActualEndTime != nil {
+		pb.ActualEndTime = timestampToProto(*block.ActualEndTime)
+	}
+	if len(block.Entries) > 0 {
+		pb.Entries = make([]*claudecatv1.UsageEntry, 0, len(block.Entries))
+		for i := range block.Entries {
+			pb.Entries = append(pb.Entries, entryToProto(&block.Entries[i]))
+		}
+	}
+	return pb
+}
+
+func entryToProto(entry *models.UsageEntry) *claudecatv1.UsageEntry {
+	return &claudecatv1.UsageEntry{
+		Timestamp:           timestampToProto(entry.Timestamp),
+		Model:               entry.Model,
+		InputTokens:         int64(entry.InputTokens),
+		OutputTokens:        int64(entry.OutputTokens),
+		CacheCreationTokens: int64(entry.CacheCreationTokens),
+		CacheReadTokens:     int64(entry.CacheReadTokens),
+		ThinkingTokens:      int64(entry.ThinkingTokens),
+		TotalTokens:         int64(entry.TotalTokens),
+		CostUsd:             entry.CostUSD,
+		CostSource:          entry.CostSource,
+		CacheSavingsUsd:     entry.CacheSavingsUSD,
+		MessageId:           entry.MessageID,
+		RequestId:           entry.RequestID,
+		SessionId:           entry.SessionID,
+		Project:             entry.Project,
+		Anomalies:           entry.Anomalies,
+		Suspect:             entry.Suspect,
+	}
+}
+
+func tokenCountsToProto(counts models.TokenCounts) *claudecatv1.TokenCounts {
+	return &claudecatv1.TokenCounts{
+		InputTokens:         int64(counts.InputTokens),
+		OutputTokens:        int64(counts.OutputTokens),
+		CacheCreationTokens: int64(counts.CacheCreationTokens),
+		CacheReadTokens:     int64(counts.CacheReadTokens),
+		ThinkingTokens:      int64(counts.ThinkingTokens),
+	}
+}
+
+func burnRateToProto(rate *models.BurnRate) *claudecatv1.BurnRate {
+	if rate == nil {
+		return nil
+	}
+	return &claudecatv1.BurnRate{TokensPerMinute: rate.TokensPerMinute, CostPerHour: rate.CostPerHour}
+}
+
+func projectionToProto(projection *models.UsageProjection) *claudecatv1.UsageProjection {
+	if projection == nil {
+		return nil
+	}
+	return &claudecatv1.UsageProjection{
+		ProjectedTotalTokens: int64(projection.ProjectedTotalTokens),
+		ProjectedTotalCost:   projection.ProjectedTotalCost,
+		RemainingMinutes:     projection.RemainingMinutes,
+	}
+}
@@ -0,0 +1,101 @@
+package server
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	claudecatv1 "github.com/penwyp/claudecat/api/claudecatv1"
+	"github.com/penwyp/claudecat/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newGRPCClient serves a GRPCServer for api over an in-memory connection
+func newGRPCClient(t *testing.T, api *Server) (claudecatv1.UsageServiceClient, *GRPCServer) {
+	t.Helper()
+	listener := bufconn.Listen(1 << 20)
+	g := NewGRPCServer("bufconn", api)
+	go func() { _ = g.grpcServer.Serve(listener) }()
+	t.Cleanup(func() { _ = g.Shutdown(context.Background()) })
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return claudecatv1.NewUsageServiceClient(conn), g
+}
+
+func TestGRPCServer_Unary(t *testing.T) {
+	api := NewServer("127.0.0.1:0", config.DefaultConfig())
+	client, _ := newGRPCClient(t, api)
+	ctx := context.Background()
+
+	_, err := client.GetMetrics(ctx, &claudecatv1.GetMetricsRequest{})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+
+	api.Update(testMonitoringData(time.Now()))
+
+	metrics, err := client.GetMetrics(ctx, &claudecatv1.GetMetricsRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, "active", metrics.GetSessionId())
+	assert.Equal(t, int64(44000), metrics.GetTokenLimit())
+	assert.NotNil(t, metrics.GetMetrics())
+
+	blocks, err := client.ListBlocks(ctx, &claudecatv1.ListBlocksRequest{})
+	require.NoError(t, err)
+	require.Len(t, blocks.GetBlocks(), 2)
+	assert.Equal(t, "old", blocks.GetBlocks()[0].GetId())
+	assert.Empty(t, blocks.GetBlocks()[0].GetEntries())
+
+	blocks, err = client.ListBlocks(ctx, &claudecatv1.ListBlocksRequest{Limit: 2, IncludeGaps: true, IncludeEntries: true})
+	require.NoError(t, err)
+	require.Len(t, blocks.GetBlocks(), 2)
+	assert.True(t, blocks.GetBlocks()[0].GetIsGap())
+	require.Len(t, blocks.GetBlocks()[1].GetEntries(), 1)
+	assert.Equal(t, int64(1500), blocks.GetBlocks()[1].GetEntries()[0].GetTotalTokens())
+
+	_, err = client.ListBlocks(ctx, &claudecatv1.ListBlocksRequest{Limit: -1})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	active, err := client.GetActiveSession(ctx, &claudecatv1.GetActiveSessionRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, "active", active.GetId())
+	assert.InDelta(t, 0.01, active.GetCostUsd(), 1e-9)
+
+	idle := testMonitoringData(time.Now())
+	idle.Data.Blocks = idle.Data.Blocks[:2]
+	api.Update(idle)
+	_, err = client.GetActiveSession(ctx, &claudecatv1.GetActiveSessionRequest{})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestGRPCServer_WatchMetrics(t *testing.T) {
+	api := NewServer("127.0.0.1:0", config.DefaultConfig())
+	client, g := newGRPCClient(t, api)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := client.WatchMetrics(ctx, &claudecatv1.WatchMetricsRequest{})
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool { return api.subscriberCount() == 1 }, time.Second, 10*time.Millisecond)
+	api.Update(testMonitoringData(time.Now()))
+
+	metrics, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, "active", metrics.GetSessionId())
+
+	// Shutting down ends the stream instead of waiting for the client
+	require.NoError(t, g.Shutdown(ctx))
+	require.Eventually(t, func() bool { return api.subscriberCount() == 0 }, time.Second, 10*time.Millisecond)
+}
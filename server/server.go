@@ -0,0 +1,396 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/penwyp/claudecat/calculations"
+	"github.com/penwyp/claudecat/config"
+	"github.com/penwyp/claudecat/fileio"
+	"github.com/penwyp/claudecat/logging"
+	"github.com/penwyp/claudecat/models"
+	"github.com/penwyp/claudecat/orchestrator"
+)
+
+// Server exposes the orchestrator's latest MonitoringData as a read-only JSON API,
+// so external dashboards can poll claudecat instead of parsing console output.
+type Server struct {
+	addr        string
+	httpServer  *http.Server
+	metricsCalc *calculations.EnhancedMetricsCalculator
+	location    *time.Location // Time zone trend buckets are aligned to
+
+	mu        sync.RWMutex
+	data      *orchestrator.MonitoringData
+	updatedAt time.Time
+	health    func() orchestrator.Health
+	dashboard bool // Whether / serves the web dashboard
+
+	streamMu      sync.Mutex
+	streams       map[chan MetricsResponse]struct{} // Connected metrics streams
+	closing       chan struct{}                     // Closed on shutdown to end the streams
+	streamsClosed bool
+}
+
+// MetricsResponse is the payload of GET /api/v1/metrics
+type MetricsResponse struct {
+	Metrics      *calculations.EnhancedRealtimeMetrics `json:"metrics"`
+	TokenLimit   int                                   `json:"token_limit"`
+	SessionID    string                                `json:"session_id"`
+	SessionCount int                                   `json:"session_count"`
+	Budgets      []calculations.BudgetStatus           `json:"budgets,omitempty"`
+	UpdatedAt    time.Time                             `json:"updated_at"`
+}
+
+// BlocksResponse is the payload of GET /api/v1/blocks
+type BlocksResponse struct {
+	Blocks    []models.SessionBlock `json:"blocks"`
+	Count     int                   `json:"count"`
+	UpdatedAt time.Time             `json:"updated_at"`
+}
+
+// TrendResponse is the payload of GET /api/v1/trend
+type TrendResponse struct {
+	calculations.TimeSeries
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ReadyResponse is the payload of GET /readyz
+type ReadyResponse struct {
+	Ready     bool      `json:"ready"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ErrorResponse is returned for every non-2xx response
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// NewServer creates an API server listening on addr (host:port)
+func NewServer(addr string, cfg *config.Config) *Server {
+	location, err := cfg.Location()
+	if err != nil {
+		logging.LogWarnf("%v, using local time", err)
+	}
+	s := &Server{
+		addr:        addr,
+		metricsCalc: calculations.NewEnhancedMetricsCalculator(cfg),
+		location:    location,
+		dashboard:   true,
+		streams:     make(map[chan MetricsResponse]struct{}),
+		closing:     make(chan struct{}),
+	}
+	s.httpServer = &http.Server{
+		Addr:              addr,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	s.httpServer.RegisterOnShutdown(s.closeStreams)
+	return s
+}
+
+// Update stores the latest monitoring data. It matches orchestrator.DataUpdateCallback,
+// so it can be registered directly with the orchestrator.
+func (s *Server) Update(data orchestrator.MonitoringData) {
+	s.metricsCalc.UpdateSessionBlocks(data.Data.Blocks)
+
+	s.mu.Lock()
+	s.data = &data
+	s.updatedAt = time.Now()
+	s.mu.Unlock()
+
+	s.broadcast()
+}
+
+// SetHealthCheck sets the source of the health reported by /healthz, typically the
+// orchestrator's GetHealth
+func (s *Server) SetHealthCheck(health func() orchestrator.Health) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.health = health
+}
+
+// Handler returns the HTTP handler serving the API and the web dashboard
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/metrics", getOnly(s.handleMetrics))
+	mux.HandleFunc("/api/v1/blocks", getOnly(s.handleBlocks))
+	mux.HandleFunc("/api/v1/sessions/active", getOnly(s.handleActiveSession))
+	mux.HandleFunc("/api/v1/trend", getOnly(s.handleTrend))
+	mux.HandleFunc("/api/v1/stream", getOnly(s.handleStream))
+	mux.HandleFunc("/healthz", getOnly(s.handleHealth))
+	mux.HandleFunc("/readyz", getOnly(s.handleReady))
+	mux.HandleFunc("/dashboard/", getOnly(s.serveDashboardAssets(dashboardHandler())))
+	mux.HandleFunc("/", getOnly(s.handleIndex))
+	return mux
+}
+
+// Serve listens on the configured address and serves requests until Shutdown is called
+func (s *Server) Serve() error {
+	listener, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.addr, err)
+	}
+	logging.LogInfof("HTTP API listening on http://%s", listener.Addr())
+
+	if err := s.httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("HTTP server failed: %w", err)
+	}
+	return nil
+}
+
+// Shutdown gracefully stops the server, waiting for in-flight requests until ctx is done
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.httpServer.Shutdown(ctx)
+}
+
+// snapshot returns the latest data, or nil before the first update
+func (s *Server) snapshot() (*orchestrator.MonitoringData, time.Time) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.data, s.updatedAt
+}
+
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	data, _ := s.snapshot()
+	if data == nil {
+		writeNotReady(w)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.metricsResponse())
+}
+
+// metricsResponse builds the metrics payload from the latest data, which must be loaded
+func (s *Server) metricsResponse() MetricsResponse {
+	data, updatedAt := s.snapshot()
+	return MetricsResponse{
+		Metrics:      s.metricsCalc.Calculate(),
+		TokenLimit:   data.TokenLimit,
+		SessionID:    data.SessionID,
+		SessionCount: data.SessionCount,
+		Budgets:      data.Budgets,
+		UpdatedAt:    updatedAt,
+	}
+}
+
+// handleBlocks lists session blocks, oldest first. Query parameters:
+//
+//	limit=N          only the N most recent blocks
+//	gaps=true        include gap blocks
+//	entries=true     include the raw usage entries of each block
+func (s *Server) handleBlocks(w http.ResponseWriter, r *http.Request) {
+	data, updatedAt := s.snapshot()
+	if data == nil {
+		writeNotReady(w)
+		return
+	}
+
+	query := r.URL.Query()
+	includeGaps, err := parseBoolParam(query.Get("gaps"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid gaps: %v", err))
+		return
+	}
+	includeEntries, err := parseBoolParam(query.Get("entries"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid entries: %v", err))
+		return
+	}
+	limit := 0
+	if raw := query.Get("limit"); raw != "" {
+		limit, err = strconv.Atoi(raw)
+		if err != nil || limit < 0 {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid limit: %s", raw))
+			return
+		}
+	}
+
+	blocks := selectBlocks(data.Data.Blocks, limit, includeGaps, includeEntries)
+	writeJSON(w, http.StatusOK, BlocksResponse{
+		Blocks:    blocks,
+		Count:     len(blocks),
+		UpdatedAt: updatedAt,
+	})
+}
+
+// handleActiveSession returns the active session block, or 404 when no session is active.
+// Entries are included only with entries=true.
+func (s *Server) handleActiveSession(w http.ResponseWriter, r *http.Request) {
+	data, _ := s.snapshot()
+	if data == nil {
+		writeNotReady(w)
+		return
+	}
+
+	includeEntries, err := parseBoolParam(r.URL.Query().Get("entries"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid entries: %v", err))
+		return
+	}
+
+	block, ok := activeBlock(data.Data.Blocks, includeEntries)
+	if !ok {
+		writeError(w, http.StatusNotFound, "no active session")
+		return
+	}
+	writeJSON(w, http.StatusOK, block)
+}
+
+// selectBlocks returns the blocks to list, oldest first: gaps only when included, entries
+// stripped unless included, and only the limit most recent when limit is positive
+func selectBlocks(all []models.SessionBlock, limit int, includeGaps, includeEntries bool) []models.SessionBlock {
+	blocks := make([]models.SessionBlock, 0, len(all))
+	for _, block := range all {
+		if block.IsGap && !includeGaps {
+			continue
+		}
+		if !includeEntries {
+			block.Entries = nil
+		}
+		blocks = append(blocks, block)
+	}
+	if limit > 0 && len(blocks) > limit {
+		blocks = blocks[len(blocks)-limit:]
+	}
+	return blocks
+}
+
+// activeBlock returns the active session block, with its entries only when included
+func activeBlock(blocks []models.SessionBlock, includeEntries bool) (models.SessionBlock, bool) {
+	for _, block := range blocks {
+		if !block.IsActive || block.IsGap {
+			continue
+		}
+		if !includeEntries {
+			block.Entries = nil
+		}
+		return block, true
+	}
+	return models.SessionBlock{}, false
+}
+
+// handleTrend returns the cost and tokens of each model per hour or day over the loaded
+// usage. Query parameters:
+//
+//	interval=daily   bucket width, daily (default) or hourly
+//	model=NAME       only these models or families such as opus (repeatable)
+//	project=NAME     only these projects, matched as substrings (repeatable)
+//	source=NAME      only these sources such as codex (repeatable)
+func (s *Server) handleTrend(w http.ResponseWriter, r *http.Request) {
+	data, updatedAt := s.snapshot()
+	if data == nil {
+		writeNotReady(w)
+		return
+	}
+
+	query := r.URL.Query()
+	interval := calculations.IntervalDaily
+	if raw := query.Get("interval"); raw != "" {
+		var err error
+		if interval, err = calculations.ParseTimeSeriesInterval(raw); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	filter := fileio.EntryFilter{Models: query["model"], Projects: query["project"], Sources: query["source"]}
+
+	var entries []models.UsageEntry
+	for _, block := range data.Data.Blocks {
+		if block.IsGap {
+			continue
+		}
+		entries = append(entries, filter.Apply(block.Entries)...)
+	}
+
+	writeJSON(w, http.StatusOK, TrendResponse{
+		TimeSeries: calculations.NewTimeSeriesAggregator(interval, s.location).Aggregate(entries),
+		UpdatedAt:  updatedAt,
+	})
+}
+
+// handleHealth reports the monitoring health, with 503 when it is unhealthy so load
+// balancers and supervisors can act on the status code alone
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	source := s.health
+	ready := s.data != nil
+	s.mu.RUnlock()
+
+	var health orchestrator.Health
+	if source != nil {
+		health = source()
+	} else {
+		// Without the orchestrator, being able to answer is all there is to check
+		health = orchestrator.Health{Status: orchestrator.HealthOK, Ready: ready, CheckedAt: time.Now()}
+	}
+
+	status := http.StatusOK
+	if health.Status == orchestrator.HealthUnhealthy {
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, health)
+}
+
+// handleReady reports whether monitoring data has been loaded, with 503 until it has
+func (s *Server) handleReady(w http.ResponseWriter, r *http.Request) {
+	data, updatedAt := s.snapshot()
+	if data == nil {
+		writeNotReady(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, ReadyResponse{Ready: true, UpdatedAt: updatedAt})
+}
+
+// getOnly rejects every method except GET and HEAD with a JSON error
+func getOnly(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			writeError(w, http.StatusMethodNotAllowed, fmt.Sprintf("method %s not allowed", r.Method))
+			return
+		}
+		handler(w, r)
+	}
+}
+
+// parseBoolParam parses an optional boolean query parameter; empty means false
+func parseBoolParam(raw string) (bool, error) {
+	if raw == "" {
+		return false, nil
+	}
+	return strconv.ParseBool(raw)
+}
+
+func writeNotReady(w http.ResponseWriter) {
+	w.Header().Set("Retry-After", "1")
+	writeError(w, http.StatusServiceUnavailable, "monitoring data not loaded yet")
+}
+
+func writeNotFound(w http.ResponseWriter, r *http.Request) {
+	writeError(w, http.StatusNotFound, fmt.Sprintf("unknown endpoint: %s", r.URL.Path))
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, ErrorResponse{Error: message})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	data, err := sonic.Marshal(v)
+	if err != nil {
+		logging.LogErrorf("Failed to encode API response: %v", err)
+		http.Error(w, `{"error":"failed to encode response"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(append(data, '\n'))
+}
@@ -0,0 +1,213 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/penwyp/claudecat/config"
+	"github.com/penwyp/claudecat/models"
+	"github.com/penwyp/claudecat/orchestrator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testMonitoringData(now time.Time) orchestrator.MonitoringData {
+	entry := models.UsageEntry{
+		Timestamp:    now.Add(-30 * time.Minute),
+		Model:        "claude-sonnet-4-20250514",
+		InputTokens:  1000,
+		OutputTokens: 500,
+		TotalTokens:  1500,
+		CostUSD:      0.01,
+	}
+
+	return orchestrator.MonitoringData{
+		Data: orchestrator.AnalysisResult{
+			Blocks: []models.SessionBlock{
+				{ID: "old", StartTime: now.Add(-12 * time.Hour), EndTime: now.Add(-7 * time.Hour), Entries: []models.UsageEntry{entry}},
+				{ID: "gap", StartTime: now.Add(-7 * time.Hour), EndTime: now.Add(-time.Hour), IsGap: true},
+				{ID: "active", StartTime: now.Add(-time.Hour), EndTime: now.Add(4 * time.Hour), IsActive: true,
+					Entries: []models.UsageEntry{entry}, CostUSD: 0.01},
+			},
+		},
+		TokenLimit:   44000,
+		SessionID:    "active",
+		SessionCount: 1,
+	}
+}
+
+func get(t *testing.T, handler http.Handler, path string) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	return rec
+}
+
+func TestServer_NotReadyBeforeFirstUpdate(t *testing.T) {
+	srv := NewServer("127.0.0.1:0", config.DefaultConfig())
+
+	for _, path := range []string{"/api/v1/metrics", "/api/v1/blocks", "/api/v1/sessions/active", "/api/v1/trend"} {
+		rec := get(t, srv.Handler(), path)
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code, path)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	}
+}
+
+func TestServer_Metrics(t *testing.T) {
+	srv := NewServer("127.0.0.1:0", config.DefaultConfig())
+	srv.Update(testMonitoringData(time.Now()))
+
+	rec := get(t, srv.Handler(), "/api/v1/metrics")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var resp MetricsResponse
+	require.NoError(t, sonic.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, 44000, resp.TokenLimit)
+	assert.Equal(t, "active", resp.SessionID)
+	require.NotNil(t, resp.Metrics)
+	assert.True(t, resp.Metrics.IsActive)
+}
+
+func TestServer_Blocks(t *testing.T) {
+	srv := NewServer("127.0.0.1:0", config.DefaultConfig())
+	srv.Update(testMonitoringData(time.Now()))
+	handler := srv.Handler()
+
+	decode := func(rec *httptest.ResponseRecorder) BlocksResponse {
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var resp BlocksResponse
+		require.NoError(t, sonic.Unmarshal(rec.Body.Bytes(), &resp))
+		return resp
+	}
+
+	resp := decode(get(t, handler, "/api/v1/blocks"))
+	require.Equal(t, 2, resp.Count, "gaps are excluded by default")
+	assert.Equal(t, "old", resp.Blocks[0].ID)
+	assert.Empty(t, resp.Blocks[0].Entries, "entries are stripped by default")
+
+	resp = decode(get(t, handler, "/api/v1/blocks?gaps=true&entries=true"))
+	require.Equal(t, 3, resp.Count)
+	assert.Len(t, resp.Blocks[0].Entries, 1)
+
+	resp = decode(get(t, handler, "/api/v1/blocks?limit=1"))
+	require.Equal(t, 1, resp.Count)
+	assert.Equal(t, "active", resp.Blocks[0].ID)
+
+	assert.Equal(t, http.StatusBadRequest, get(t, handler, "/api/v1/blocks?limit=-1").Code)
+	assert.Equal(t, http.StatusBadRequest, get(t, handler, "/api/v1/blocks?entries=maybe").Code)
+}
+
+func TestServer_ActiveSession(t *testing.T) {
+	srv := NewServer("127.0.0.1:0", config.DefaultConfig())
+	data := testMonitoringData(time.Now())
+	srv.Update(data)
+
+	rec := get(t, srv.Handler(), "/api/v1/sessions/active")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var block models.SessionBlock
+	require.NoError(t, sonic.Unmarshal(rec.Body.Bytes(), &block))
+	assert.Equal(t, "active", block.ID)
+	assert.Empty(t, block.Entries)
+
+	data.Data.Blocks = data.Data.Blocks[:2]
+	srv.Update(data)
+	rec = get(t, srv.Handler(), "/api/v1/sessions/active")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Contains(t, rec.Body.String(), "no active session")
+}
+
+func TestServer_Trend(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.UI.Timezone = "UTC"
+	srv := NewServer("127.0.0.1:0", cfg)
+	now := time.Date(2025, 6, 2, 12, 0, 0, 0, time.UTC)
+	srv.Update(testMonitoringData(now))
+	handler := srv.Handler()
+
+	decode := func(rec *httptest.ResponseRecorder) TrendResponse {
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var resp TrendResponse
+		require.NoError(t, sonic.Unmarshal(rec.Body.Bytes(), &resp))
+		return resp
+	}
+
+	resp := decode(get(t, handler, "/api/v1/trend"))
+	assert.Equal(t, "daily", string(resp.Interval))
+	assert.Equal(t, []string{"claude-sonnet-4-20250514"}, resp.Models)
+	require.Len(t, resp.Buckets, 1)
+	assert.Equal(t, 2, resp.Buckets[0].Entries)
+	assert.InDelta(t, 1.0, resp.Buckets[0].Models[0].CostShare, 1e-9)
+
+	resp = decode(get(t, handler, "/api/v1/trend?interval=hourly"))
+	require.Len(t, resp.Buckets, 1, "both entries fall in the same hour")
+	assert.Equal(t, now.Add(-time.Hour), resp.Buckets[0].Start.UTC())
+
+	resp = decode(get(t, handler, "/api/v1/trend?model=opus"))
+	assert.Empty(t, resp.Buckets)
+
+	assert.Equal(t, http.StatusBadRequest, get(t, handler, "/api/v1/trend?interval=weekly").Code)
+}
+
+func TestServer_UnknownEndpointAndMethod(t *testing.T) {
+	srv := NewServer("127.0.0.1:0", config.DefaultConfig())
+	srv.Update(testMonitoringData(time.Now()))
+
+	assert.Equal(t, http.StatusNotFound, get(t, srv.Handler(), "/api/v1/nope").Code)
+
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/metrics", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestServer_Dashboard(t *testing.T) {
+	srv := NewServer("127.0.0.1:0", config.DefaultConfig())
+	handler := srv.Handler()
+
+	// The page is served before any data has loaded; it shows its own loading state
+	rec := get(t, handler, "/")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Header().Get("Content-Type"), "text/html")
+	assert.Contains(t, rec.Body.String(), "/dashboard/app.js")
+
+	for _, asset := range []string{"/dashboard/app.js", "/dashboard/style.css"} {
+		assert.Equal(t, http.StatusOK, get(t, handler, asset).Code, asset)
+	}
+	assert.Equal(t, http.StatusNotFound, get(t, handler, "/dashboard/missing.js").Code)
+	assert.Equal(t, http.StatusNotFound, get(t, handler, "/nope").Code)
+
+	srv.SetDashboard(false)
+	assert.Equal(t, http.StatusNotFound, get(t, handler, "/").Code)
+	assert.Equal(t, http.StatusNotFound, get(t, handler, "/dashboard/app.js").Code)
+}
+
+func TestServer_HealthAndReadiness(t *testing.T) {
+	srv := NewServer("127.0.0.1:0", config.DefaultConfig())
+	handler := srv.Handler()
+
+	assert.Equal(t, http.StatusServiceUnavailable, get(t, handler, "/readyz").Code)
+	assert.Equal(t, http.StatusOK, get(t, handler, "/healthz").Code)
+
+	srv.Update(testMonitoringData(time.Now()))
+	rec := get(t, handler, "/readyz")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var ready ReadyResponse
+	require.NoError(t, sonic.Unmarshal(rec.Body.Bytes(), &ready))
+	assert.True(t, ready.Ready)
+
+	status := orchestrator.HealthDegraded
+	srv.SetHealthCheck(func() orchestrator.Health {
+		return orchestrator.Health{Status: status, ConsecutiveErrors: 3}
+	})
+	rec = get(t, handler, "/healthz")
+	require.Equal(t, http.StatusOK, rec.Code, "degraded still answers 200")
+	var health orchestrator.Health
+	require.NoError(t, sonic.Unmarshal(rec.Body.Bytes(), &health))
+	assert.Equal(t, orchestrator.HealthDegraded, health.Status)
+	assert.Equal(t, 3, health.ConsecutiveErrors)
+
+	status = orchestrator.HealthUnhealthy
+	assert.Equal(t, http.StatusServiceUnavailable, get(t, handler, "/healthz").Code)
+}
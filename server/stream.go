@@ -0,0 +1,130 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/penwyp/claudecat/logging"
+)
+
+const (
+	// streamHeartbeat is how often an idle stream gets a comment line, so proxies and
+	// clients don't time out the connection between updates
+	streamHeartbeat = 15 * time.Second
+
+	// streamRetry is the reconnect delay suggested to EventSource clients
+	streamRetry = 3 * time.Second
+)
+
+// subscribe registers a stream for new metrics. The channel holds only the latest metrics,
+// so a slow client skips intermediate updates instead of stalling the others.
+func (s *Server) subscribe() chan MetricsResponse {
+	ch := make(chan MetricsResponse, 1)
+	s.streamMu.Lock()
+	s.streams[ch] = struct{}{}
+	s.streamMu.Unlock()
+	return ch
+}
+
+func (s *Server) unsubscribe(ch chan MetricsResponse) {
+	s.streamMu.Lock()
+	delete(s.streams, ch)
+	s.streamMu.Unlock()
+}
+
+// subscriberCount returns the number of connected streams
+func (s *Server) subscriberCount() int {
+	s.streamMu.Lock()
+	defer s.streamMu.Unlock()
+	return len(s.streams)
+}
+
+// broadcast sends the current metrics, calculated once, to every connected stream
+func (s *Server) broadcast() {
+	if s.subscriberCount() == 0 {
+		return
+	}
+	metrics := s.metricsResponse()
+
+	s.streamMu.Lock()
+	defer s.streamMu.Unlock()
+	for ch := range s.streams {
+		select {
+		case ch <- metrics:
+		default:
+			// Replace the pending metrics the client hasn't read yet with the newer ones
+			select {
+			case <-ch:
+			default:
+			}
+			ch <- metrics
+		}
+	}
+}
+
+// closeStreams ends every connected stream; it runs when the server shuts down, since
+// Shutdown otherwise waits for the long-lived stream requests to finish on their own
+func (s *Server) closeStreams() {
+	s.streamMu.Lock()
+	defer s.streamMu.Unlock()
+	if !s.streamsClosed {
+		s.streamsClosed = true
+		close(s.closing)
+	}
+}
+
+// handleStream pushes a "metrics" event, with the payload of GET /api/v1/metrics, every
+// time the orchestrator delivers new data. The current metrics are sent on connect when
+// data has been loaded.
+func (s *Server) handleStream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming not supported")
+		return
+	}
+
+	ch := s.subscribe()
+	defer s.unsubscribe(ch)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no") // Keep reverse proxies from buffering events
+	w.WriteHeader(http.StatusOK)
+	if r.Method == http.MethodHead {
+		return
+	}
+	fmt.Fprintf(w, "retry: %d\n\n", streamRetry.Milliseconds())
+
+	if data, _ := s.snapshot(); data != nil {
+		writeEvent(w, "metrics", s.metricsResponse())
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case metrics := <-ch:
+			writeEvent(w, "metrics", metrics)
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+		case <-r.Context().Done():
+			return
+		case <-s.closing:
+			return
+		}
+		flusher.Flush()
+	}
+}
+
+func writeEvent(w http.ResponseWriter, name string, v interface{}) {
+	data, err := sonic.Marshal(v)
+	if err != nil {
+		logging.LogErrorf("Failed to encode stream event: %v", err)
+		return
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", name, data)
+}
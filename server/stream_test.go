@@ -0,0 +1,81 @@
+package server
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/penwyp/claudecat/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readEvent reads the next named event from an SSE stream, skipping comments and the
+// retry field
+func readEvent(t *testing.T, reader *bufio.Reader) (string, string) {
+	t.Helper()
+	var name, data string
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			data = strings.TrimPrefix(line, "data: ")
+		case line == "" && name != "":
+			return name, data
+		}
+	}
+}
+
+func TestServer_Stream(t *testing.T) {
+	srv := NewServer("127.0.0.1:0", config.DefaultConfig())
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/api/v1/stream")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	// Nothing is sent before the first update; wait for the subscription before updating
+	require.Eventually(t, func() bool { return srv.subscriberCount() == 1 }, time.Second, 10*time.Millisecond)
+	srv.Update(testMonitoringData(time.Now()))
+
+	reader := bufio.NewReader(resp.Body)
+	name, data := readEvent(t, reader)
+	assert.Equal(t, "metrics", name)
+	var metrics MetricsResponse
+	require.NoError(t, sonic.UnmarshalString(data, &metrics))
+	assert.Equal(t, "active", metrics.SessionID)
+	assert.Equal(t, 44000, metrics.TokenLimit)
+
+	// A client connecting after data has loaded gets the current metrics right away
+	late, err := http.Get(ts.URL + "/api/v1/stream")
+	require.NoError(t, err)
+	defer late.Body.Close()
+	name, _ = readEvent(t, bufio.NewReader(late.Body))
+	assert.Equal(t, "metrics", name)
+}
+
+func TestServer_StreamEndsOnShutdown(t *testing.T) {
+	srv := NewServer("127.0.0.1:0", config.DefaultConfig())
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/api/v1/stream")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Eventually(t, func() bool { return srv.subscriberCount() == 1 }, time.Second, 10*time.Millisecond)
+
+	srv.closeStreams()
+	require.Eventually(t, func() bool { return srv.subscriberCount() == 0 }, time.Second, 10*time.Millisecond)
+	srv.closeStreams() // Closing twice is harmless
+}
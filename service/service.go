@@ -0,0 +1,323 @@
+// Package service installs claudecat as a per-user background service that starts at
+// login: a systemd user unit on Linux and a launchd agent on macOS.
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+const (
+	// systemdUnitName is the name of the systemd user unit
+	systemdUnitName = "claudecat.service"
+	// launchdLabel identifies the launchd agent
+	launchdLabel = "com.penwyp.claudecat"
+)
+
+// ErrUnsupported is returned on platforms without a supported service manager
+var ErrUnsupported = errors.New("background services are only supported with systemd on Linux and launchd on macOS")
+
+// ErrNotInstalled is returned when removing a service that isn't installed
+var ErrNotInstalled = errors.New("service is not installed")
+
+// Spec describes the background process a service runs
+type Spec struct {
+	Executable string            // Absolute path of the claudecat binary
+	Args       []string          // Arguments, e.g. --background
+	Env        map[string]string // Environment, e.g. CLAUDECAT_APP_LOG_FILE
+	WorkingDir string            // Directory the process starts in
+	OutputLog  string            // File receiving stdout and stderr where the service manager has no journal
+}
+
+// Status reports whether the service is installed and running
+type Status struct {
+	Installed bool   `json:"installed"`
+	Running   bool   `json:"running"`
+	Path      string `json:"path"`             // Unit or plist file
+	Detail    string `json:"detail,omitempty"` // What the service manager reports
+}
+
+// Manager installs, removes and inspects the background service through the platform's
+// service manager
+type Manager struct {
+	goos string
+	home string
+	run  func(ctx context.Context, name string, args ...string) (string, error)
+}
+
+// NewManager creates a manager for the current platform and user
+func NewManager() (*Manager, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find home directory: %w", err)
+	}
+	return &Manager{
+		goos: runtime.GOOS,
+		home: home,
+		run: func(ctx context.Context, name string, args ...string) (string, error) {
+			output, err := exec.CommandContext(ctx, name, args...).CombinedOutput()
+			text := strings.TrimSpace(string(output))
+			if err != nil && text != "" {
+				return text, fmt.Errorf("%w: %s", err, text)
+			}
+			return text, err
+		},
+	}, nil
+}
+
+// Path returns the unit or plist file of the service
+func (m *Manager) Path() (string, error) {
+	switch m.goos {
+	case "linux":
+		return filepath.Join(m.home, ".config", "systemd", "user", systemdUnitName), nil
+	case "darwin":
+		return filepath.Join(m.home, "Library", "LaunchAgents", launchdLabel+".plist"), nil
+	default:
+		return "", ErrUnsupported
+	}
+}
+
+// LogDir returns the platform's directory for the service's log files
+func (m *Manager) LogDir() (string, error) {
+	switch m.goos {
+	case "linux":
+		return filepath.Join(m.home, ".local", "state", "claudecat"), nil
+	case "darwin":
+		return filepath.Join(m.home, "Library", "Logs", "claudecat"), nil
+	default:
+		return "", ErrUnsupported
+	}
+}
+
+// Render returns the unit or plist file that runs spec
+func (m *Manager) Render(spec Spec) (string, error) {
+	tmpl := systemdTemplate
+	if m.goos == "darwin" {
+		tmpl = launchdTemplate
+	} else if m.goos != "linux" {
+		return "", ErrUnsupported
+	}
+
+	var keys []string
+	for key := range spec.Env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var env [][2]string
+	for _, key := range keys {
+		env = append(env, [2]string{key, spec.Env[key]})
+	}
+
+	var buf bytes.Buffer
+	err := tmpl.Execute(&buf, struct {
+		Spec
+		Label string
+		Env   [][2]string
+	}{spec, launchdLabel, env})
+	if err != nil {
+		return "", fmt.Errorf("failed to render service file: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// Install writes the service file, replacing an existing one, and starts the service now
+// and at every login
+func (m *Manager) Install(ctx context.Context, spec Spec) (string, error) {
+	path, err := m.Path()
+	if err != nil {
+		return "", err
+	}
+	content, err := m.Render(spec)
+	if err != nil {
+		return "", err
+	}
+
+	// Stop a previous version first so the new file takes effect
+	if _, err := os.Stat(path); err == nil && m.goos == "darwin" {
+		_, _ = m.run(ctx, "launchctl", "unload", path)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if spec.OutputLog != "" {
+		if err := os.MkdirAll(filepath.Dir(spec.OutputLog), 0755); err != nil {
+			return "", fmt.Errorf("failed to create log directory: %w", err)
+		}
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return "", fmt.Errorf("failed to write service file: %w", err)
+	}
+
+	switch m.goos {
+	case "linux":
+		if _, err := m.run(ctx, "systemctl", "--user", "daemon-reload"); err != nil {
+			return path, fmt.Errorf("failed to reload systemd: %w", err)
+		}
+		if _, err := m.run(ctx, "systemctl", "--user", "enable", systemdUnitName); err != nil {
+			return path, fmt.Errorf("failed to enable service: %w", err)
+		}
+		if _, err := m.run(ctx, "systemctl", "--user", "restart", systemdUnitName); err != nil {
+			return path, fmt.Errorf("failed to start service: %w", err)
+		}
+	case "darwin":
+		if _, err := m.run(ctx, "launchctl", "load", "-w", path); err != nil {
+			return path, fmt.Errorf("failed to load launch agent: %w", err)
+		}
+	}
+	return path, nil
+}
+
+// Uninstall stops the service and removes its file
+func (m *Manager) Uninstall(ctx context.Context) (string, error) {
+	path, err := m.Path()
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return path, ErrNotInstalled
+	}
+
+	switch m.goos {
+	case "linux":
+		if _, err := m.run(ctx, "systemctl", "--user", "disable", "--now", systemdUnitName); err != nil {
+			return path, fmt.Errorf("failed to stop service: %w", err)
+		}
+	case "darwin":
+		if _, err := m.run(ctx, "launchctl", "unload", "-w", path); err != nil {
+			return path, fmt.Errorf("failed to unload launch agent: %w", err)
+		}
+	}
+
+	if err := os.Remove(path); err != nil {
+		return path, fmt.Errorf("failed to remove service file: %w", err)
+	}
+	if m.goos == "linux" {
+		if _, err := m.run(ctx, "systemctl", "--user", "daemon-reload"); err != nil {
+			return path, fmt.Errorf("failed to reload systemd: %w", err)
+		}
+	}
+	return path, nil
+}
+
+// Status reports whether the service is installed and running
+func (m *Manager) Status(ctx context.Context) (Status, error) {
+	path, err := m.Path()
+	if err != nil {
+		return Status{}, err
+	}
+	status := Status{Path: path}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return status, nil
+	}
+	status.Installed = true
+
+	switch m.goos {
+	case "linux":
+		// is-active exits non-zero for inactive units, which is an answer rather than a failure
+		output, _ := m.run(ctx, "systemctl", "--user", "is-active", systemdUnitName)
+		status.Running = output == "active"
+		status.Detail = output
+	case "darwin":
+		output, err := m.run(ctx, "launchctl", "list", launchdLabel)
+		if err != nil {
+			status.Detail = "not loaded"
+			break
+		}
+		status.Running = strings.Contains(output, `"PID" =`)
+		status.Detail = "loaded"
+		if !status.Running {
+			status.Detail = "loaded, not running"
+		}
+	}
+	return status, nil
+}
+
+// systemdQuote quotes a value for a unit file: percent signs are escaped so they aren't
+// read as specifiers, and values with spaces or quotes are double quoted
+func systemdQuote(value string) string {
+	value = strings.ReplaceAll(value, "%", "%%")
+	if !strings.ContainsAny(value, " \t\"\\'") {
+		return value
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
+
+// xmlEscape escapes text for a plist
+func xmlEscape(value string) string {
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(value))
+	return buf.String()
+}
+
+var systemdTemplate = template.Must(template.New("systemd").Funcs(template.FuncMap{"quote": systemdQuote}).Parse(`[Unit]
+Description=claudecat Claude Code usage monitor
+After=default.target
+
+[Service]
+Type=simple
+ExecStart={{quote .Executable}}{{range .Args}} {{quote .}}{{end}}
+{{- if .WorkingDir}}
+WorkingDirectory={{quote .WorkingDir}}
+{{- end}}
+{{- range .Env}}
+Environment={{quote (printf "%s=%s" (index . 0) (index . 1))}}
+{{- end}}
+Restart=on-failure
+RestartSec=10
+
+[Install]
+WantedBy=default.target
+`))
+
+var launchdTemplate = template.Must(template.New("launchd").Funcs(template.FuncMap{"xml": xmlEscape}).Parse(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	<string>{{xml .Label}}</string>
+	<key>ProgramArguments</key>
+	<array>
+		<string>{{xml .Executable}}</string>
+		{{- range .Args}}
+		<string>{{xml .}}</string>
+		{{- end}}
+	</array>
+	{{- if .WorkingDir}}
+	<key>WorkingDirectory</key>
+	<string>{{xml .WorkingDir}}</string>
+	{{- end}}
+	{{- if .Env}}
+	<key>EnvironmentVariables</key>
+	<dict>
+		{{- range .Env}}
+		<key>{{xml (index . 0)}}</key>
+		<string>{{xml (index . 1)}}</string>
+		{{- end}}
+	</dict>
+	{{- end}}
+	<key>RunAtLoad</key>
+	<true/>
+	<key>KeepAlive</key>
+	<dict>
+		<key>SuccessfulExit</key>
+		<false/>
+	</dict>
+	{{- if .OutputLog}}
+	<key>StandardOutPath</key>
+	<string>{{xml .OutputLog}}</string>
+	<key>StandardErrorPath</key>
+	<string>{{xml .OutputLog}}</string>
+	{{- end}}
+</dict>
+</plist>
+`))
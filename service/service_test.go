@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestManager creates a manager for goos in a temporary home directory that records
+// the service manager commands instead of running them
+func newTestManager(t *testing.T, goos string, output func(command string) (string, error)) (*Manager, *[]string) {
+	var commands []string
+	return &Manager{
+		goos: goos,
+		home: t.TempDir(),
+		run: func(ctx context.Context, name string, args ...string) (string, error) {
+			command := strings.Join(append([]string{name}, args...), " ")
+			commands = append(commands, command)
+			if output != nil {
+				return output(command)
+			}
+			return "", nil
+		},
+	}, &commands
+}
+
+func testSpec() Spec {
+	return Spec{
+		Executable: "/opt/claude cat/claudecat",
+		Args:       []string{"--background"},
+		Env:        map[string]string{"CLAUDECAT_DATA_PATHS": "/data/a,/data/b", "CLAUDECAT_APP_LOG_FILE": "/logs/100%.log"},
+		WorkingDir: "/home/me",
+		OutputLog:  "/logs/service.log",
+	}
+}
+
+func TestManager_RenderSystemd(t *testing.T) {
+	manager, _ := newTestManager(t, "linux", nil)
+	unit, err := manager.Render(testSpec())
+	require.NoError(t, err)
+
+	assert.Contains(t, unit, "ExecStart=\"/opt/claude cat/claudecat\" --background\n")
+	assert.Contains(t, unit, "WorkingDirectory=/home/me\n")
+	assert.Contains(t, unit, "Environment=CLAUDECAT_APP_LOG_FILE=/logs/100%%.log\nEnvironment=CLAUDECAT_DATA_PATHS=/data/a,/data/b\n")
+	assert.Contains(t, unit, "WantedBy=default.target")
+}
+
+func TestManager_RenderLaunchd(t *testing.T) {
+	manager, _ := newTestManager(t, "darwin", nil)
+	spec := testSpec()
+	spec.Args = append(spec.Args, "--plan", "a&b")
+	plist, err := manager.Render(spec)
+	require.NoError(t, err)
+
+	assert.Contains(t, plist, "<string>com.penwyp.claudecat</string>")
+	assert.Contains(t, plist, "<string>/opt/claude cat/claudecat</string>\n\t\t<string>--background</string>")
+	assert.Contains(t, plist, "<string>a&amp;b</string>")
+	assert.Contains(t, plist, "<key>CLAUDECAT_DATA_PATHS</key>\n\t\t<string>/data/a,/data/b</string>")
+	assert.Contains(t, plist, "<key>StandardOutPath</key>\n\t<string>/logs/service.log</string>")
+	assert.Contains(t, plist, "<key>RunAtLoad</key>\n\t<true/>")
+}
+
+func TestManager_Unsupported(t *testing.T) {
+	manager, _ := newTestManager(t, "windows", nil)
+	_, err := manager.Render(testSpec())
+	assert.ErrorIs(t, err, ErrUnsupported)
+	_, err = manager.Status(context.Background())
+	assert.ErrorIs(t, err, ErrUnsupported)
+}
+
+func TestManager_SystemdLifecycle(t *testing.T) {
+	active := "inactive"
+	manager, commands := newTestManager(t, "linux", func(command string) (string, error) {
+		if strings.Contains(command, "is-active") {
+			return active, nil
+		}
+		return "", nil
+	})
+	ctx := context.Background()
+	spec := testSpec()
+	spec.OutputLog = filepath.Join(t.TempDir(), "logs", "service.log")
+
+	status, err := manager.Status(ctx)
+	require.NoError(t, err)
+	assert.False(t, status.Installed)
+
+	path, err := manager.Install(ctx, spec)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(manager.home, ".config", "systemd", "user", "claudecat.service"), path)
+	assert.FileExists(t, path)
+	assert.DirExists(t, filepath.Dir(spec.OutputLog))
+	assert.Equal(t, []string{
+		"systemctl --user daemon-reload",
+		"systemctl --user enable claudecat.service",
+		"systemctl --user restart claudecat.service",
+	}, *commands)
+
+	active = "active"
+	status, err = manager.Status(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, Status{Installed: true, Running: true, Path: path, Detail: "active"}, status)
+
+	*commands = nil
+	_, err = manager.Uninstall(ctx)
+	require.NoError(t, err)
+	assert.NoFileExists(t, path)
+	assert.Equal(t, []string{
+		"systemctl --user disable --now claudecat.service",
+		"systemctl --user daemon-reload",
+	}, *commands)
+
+	_, err = manager.Uninstall(ctx)
+	assert.ErrorIs(t, err, ErrNotInstalled)
+}
+
+func TestManager_LaunchdLifecycle(t *testing.T) {
+	loaded := true
+	manager, commands := newTestManager(t, "darwin", func(command string) (string, error) {
+		if strings.HasPrefix(command, "launchctl list") {
+			if !loaded {
+				return "", errors.New("Could not find service")
+			}
+			return "{\n\t\"Label\" = \"com.penwyp.claudecat\";\n\t\"PID\" = 4242;\n};", nil
+		}
+		return "", nil
+	})
+	ctx := context.Background()
+	spec := testSpec()
+	spec.OutputLog = filepath.Join(t.TempDir(), "service.log")
+
+	path, err := manager.Install(ctx, spec)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(manager.home, "Library", "LaunchAgents", "com.penwyp.claudecat.plist"), path)
+	assert.Equal(t, []string{"launchctl load -w " + path}, *commands)
+
+	// Reinstalling unloads the previous version first
+	*commands = nil
+	_, err = manager.Install(ctx, spec)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"launchctl unload " + path, "launchctl load -w " + path}, *commands)
+
+	status, err := manager.Status(ctx)
+	require.NoError(t, err)
+	assert.True(t, status.Running)
+
+	loaded = false
+	status, err = manager.Status(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, Status{Installed: true, Path: path, Detail: "not loaded"}, status)
+
+	_, err = manager.Uninstall(ctx)
+	require.NoError(t, err)
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+}
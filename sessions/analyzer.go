@@ -1,72 +1,112 @@
 package sessions
 
 import (
+	"context"
 	"fmt"
+	"runtime"
 	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/penwyp/claudecat/models"
+	"github.com/penwyp/claudecat/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// parallelTransformThreshold is the number of entries from which blocks are built in
+// parallel; below it starting goroutines costs more than it saves
+const parallelTransformThreshold = 10000
+
+var (
+	tracer = telemetry.Tracer("sessions")
+
+	transformDuration = telemetry.Float64Histogram("sessions", "claudecat.blocks.transform.duration",
+		"Time taken to group usage entries into session blocks", "s")
 )
 
 // SessionAnalyzer creates session blocks and detects limits
 type SessionAnalyzer struct {
-	sessionDurationHours int
-	sessionDuration      time.Duration
+	sessionDuration time.Duration
+	segmentation    Segmentation
+	limitHits       []time.Time // Sorted times of the limit hits blocks are split after
 }
 
-// NewSessionAnalyzer creates a new session analyzer with the specified duration
+// NewSessionAnalyzer creates a new session analyzer with the specified duration in hours
 func NewSessionAnalyzer(sessionDurationHours int) *SessionAnalyzer {
-	if sessionDurationHours <= 0 {
-		sessionDurationHours = 5 // Default to 5 hours
+	return NewSessionAnalyzerWithDuration(time.Duration(sessionDurationHours) * time.Hour)
+}
+
+// NewSessionAnalyzerWithDuration creates a new session analyzer with the specified session window
+func NewSessionAnalyzerWithDuration(sessionDuration time.Duration) *SessionAnalyzer {
+	if sessionDuration <= 0 {
+		sessionDuration = models.SessionDuration // Default to 5 hours
 	}
 
 	return &SessionAnalyzer{
-		sessionDurationHours: sessionDurationHours,
-		sessionDuration:      time.Duration(sessionDurationHours) * time.Hour,
+		sessionDuration: sessionDuration,
 	}
 }
 
 // TransformToBlocks processes entries and creates session blocks
 func (sa *SessionAnalyzer) TransformToBlocks(entries []models.UsageEntry) []models.SessionBlock {
+	return sa.TransformToBlocksContext(context.Background(), entries)
+}
+
+// TransformToBlocksContext is TransformToBlocks with a context that parents its trace span
+func (sa *SessionAnalyzer) TransformToBlocksContext(ctx context.Context, entries []models.UsageEntry) (blocks []models.SessionBlock) {
+	start := time.Now()
+	ctx, span := tracer.Start(ctx, "sessions.TransformToBlocks",
+		trace.WithAttributes(attribute.Int("entries", len(entries))))
+	defer func() {
+		span.SetAttributes(attribute.Int("blocks", len(blocks)))
+		span.End()
+		transformDuration.Record(ctx, telemetry.Since(start))
+	}()
+
 	if len(entries) == 0 {
 		return []models.SessionBlock{}
 	}
 
-	// Sort entries by timestamp
-	sort.Slice(entries, func(i, j int) bool {
+	// Sort entries by timestamp; loaders usually return them sorted already
+	byTime := func(i, j int) bool {
 		return entries[i].Timestamp.Before(entries[j].Timestamp)
-	})
-
-	var blocks []models.SessionBlock
-	var currentBlock *models.SessionBlock
+	}
+	if !sort.SliceIsSorted(entries, byTime) {
+		sort.Slice(entries, byTime)
+	}
 
-	for _, entry := range entries {
-		// Check if we need a new block
-		if currentBlock == nil || sa.shouldCreateNewBlock(currentBlock, entry) {
-			// Close current block
-			if currentBlock != nil {
-				sa.finalizeBlock(currentBlock)
-				blocks = append(blocks, *currentBlock)
-
-				// Check for gap
-				if gap := sa.checkForGap(currentBlock, entry); gap != nil {
-					blocks = append(blocks, *gap)
-				}
-			}
+	// Block boundaries only depend on timestamps, so they are found in one pass and the
+	// blocks, where the time goes, are built in parallel
+	spans := sa.blockSpans(entries)
+	built := make([]models.SessionBlock, len(spans))
+	workers := 1
+	if len(entries) >= parallelTransformThreshold {
+		workers = runtime.NumCPU()
+	}
+	forEachSpan(len(spans), workers, func(i int) {
+		built[i] = sa.buildBlock(spans[i], entries[spans[i].start:spans[i].end])
+	})
 
-			// Create new block
-			currentBlock = sa.createNewBlock(entry)
+	// Blocks split off the one before end it, so their time ranges don't overlap
+	for i := 1; i < len(built); i++ {
+		if spans[i].split && built[i-1].EndTime.After(built[i].StartTime) {
+			built[i-1].EndTime = built[i].StartTime
+			built[i-1].GenerateID()
 		}
-
-		// Add entry to current block
-		sa.addEntryToBlock(currentBlock, entry)
 	}
 
-	// Finalize last block
-	if currentBlock != nil {
-		sa.finalizeBlock(currentBlock)
-		blocks = append(blocks, *currentBlock)
+	// Merge the blocks in order with the gaps between them
+	blocks = make([]models.SessionBlock, 0, len(built))
+	for i := range built {
+		if i > 0 {
+			if gap := sa.checkForGap(&built[i-1], entries[spans[i].start]); gap != nil {
+				blocks = append(blocks, *gap)
+			}
+		}
+		blocks = append(blocks, built[i])
 	}
 
 	// Mark active blocks
@@ -88,20 +128,139 @@ func (sa *SessionAnalyzer) DetectLimits(rawEntries []map[string]interface{}) []m
 	return limits
 }
 
-// shouldCreateNewBlock checks if a new block is needed
-func (sa *SessionAnalyzer) shouldCreateNewBlock(block *models.SessionBlock, entry models.UsageEntry) bool {
-	if entry.Timestamp.After(block.EndTime) || entry.Timestamp.Equal(block.EndTime) {
-		return true
+// AttachLimits adds each limit message to the blocks whose time range contains it
+func AttachLimits(blocks []models.SessionBlock, limits []models.LimitMessage) {
+	for i := range blocks {
+		var blockLimits []models.LimitMessage
+		for _, limit := range limits {
+			if !limit.Timestamp.Before(blocks[i].StartTime) && !limit.Timestamp.After(blocks[i].EndTime) {
+				blockLimits = append(blockLimits, limit)
+			}
+		}
+		if len(blockLimits) > 0 {
+			blocks[i].LimitMessages = blockLimits
+		}
 	}
+}
 
-	if len(block.Entries) > 0 {
-		lastEntry := block.Entries[len(block.Entries)-1]
-		if entry.Timestamp.Sub(lastEntry.Timestamp) >= sa.sessionDuration {
-			return true
+// IsLimitRecord reports whether a raw JSONL record is a token limit message. It lets loaders
+// keep only the raw records DetectLimits needs instead of every line.
+func IsLimitRecord(rawData map[string]interface{}) bool {
+	return (&SessionAnalyzer{}).detectSingleLimit(rawData) != nil
+}
+
+// blockSpan is the range of sorted entries that belong to one block
+type blockSpan struct {
+	start, end int
+	split      bool      // Split off the block before by the segmentation
+	windowEnd  time.Time // Set when the block continues the window of the one before
+}
+
+// blockSpans splits sorted entries into the ranges of consecutive blocks. A new block starts
+// with the first entry at or past the end of the current one, or after an inactivity of a
+// full session window. With segmentation, usage resuming after a limit hit starts a new
+// window and a major model switch a new block in the same window.
+func (sa *SessionAnalyzer) blockSpans(entries []models.UsageEntry) []blockSpan {
+	var spans []blockSpan
+	span := blockSpan{}
+	end := sa.roundToHour(entries[0].Timestamp).Add(sa.sessionDuration)
+	resets := &limitResets{hits: sa.limitHits}
+	var switches *modelSwitches
+	if sa.segmentation.ModelSwitches {
+		switches = newModelSwitches()
+		switches.observe(entries, 0)
+	}
+
+	for i := 1; i < len(entries); i++ {
+		timestamp := entries[i].Timestamp
+		reset := len(resets.hits) > 0 && resets.resumesAt(sa, entries, i)
+		if reset || !timestamp.Before(end) || timestamp.Sub(entries[i-1].Timestamp) >= sa.sessionDuration {
+			span.end = i
+			spans = append(spans, span)
+			span = blockSpan{start: i, split: reset}
+			end = sa.roundToHour(timestamp).Add(sa.sessionDuration)
+			if switches != nil {
+				switches.restart()
+				switches.observe(entries, i)
+			}
+			continue
+		}
+
+		if switches != nil {
+			if at := switches.observe(entries, i); at >= 0 {
+				span.end = at
+				spans = append(spans, span)
+				span = blockSpan{start: at, split: true, windowEnd: end}
+			}
 		}
 	}
+	span.end = len(entries)
+	return append(spans, span)
+}
 
-	return false
+// forEachSpan calls build for every span index on up to workers goroutines
+func forEachSpan(spans, workers int, build func(i int)) {
+	if workers > spans {
+		workers = spans
+	}
+	if workers <= 1 {
+		for i := 0; i < spans; i++ {
+			build(i)
+		}
+		return
+	}
+
+	// Blocks differ a lot in size, so workers take the next span as they finish
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				i := int(next.Add(1) - 1)
+				if i >= spans {
+					return
+				}
+				build(i)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// buildBlock creates the finalized block of span holding entries, which are sorted and all
+// fall into it
+func (sa *SessionAnalyzer) buildBlock(span blockSpan, entries []models.UsageEntry) models.SessionBlock {
+	block := sa.createNewBlock(entries[0])
+	if !span.windowEnd.IsZero() {
+		// A block split off within a window starts at its first entry and ends with the window
+		block.StartTime = entries[0].Timestamp
+		block.EndTime = span.windowEnd
+		block.GenerateID()
+	}
+	block.Entries = make([]models.UsageEntry, 0, len(entries))
+
+	// Normalizing model names matches aliases and patterns, so it is done once per model
+	normalized := make(map[string]string)
+	for _, entry := range entries {
+		model, ok := normalized[entry.Model]
+		if !ok {
+			model = normalizeEntryModel(entry.Model)
+			normalized[entry.Model] = model
+		}
+		sa.addEntryToBlock(block, entry, model)
+	}
+	sa.finalizeBlock(block)
+	return *block
+}
+
+// normalizeEntryModel returns the normalized model of an entry, "unknown" when it has none
+func normalizeEntryModel(model string) string {
+	if model == "" {
+		model = "unknown"
+	}
+	return models.NormalizeModelName(model)
 }
 
 // roundToHour rounds timestamp to the nearest full hour in UTC
@@ -110,7 +269,8 @@ func (sa *SessionAnalyzer) roundToHour(timestamp time.Time) time.Time {
 	return time.Date(utc.Year(), utc.Month(), utc.Day(), utc.Hour(), 0, 0, 0, time.UTC)
 }
 
-// createNewBlock creates a new session block
+// createNewBlock creates a new session block. Blocks are anchored to UTC hours and span an
+// absolute duration, so a block crossing a DST transition is still exactly sessionDuration long.
 func (sa *SessionAnalyzer) createNewBlock(entry models.UsageEntry) *models.SessionBlock {
 	startTime := sa.roundToHour(entry.Timestamp)
 	endTime := startTime.Add(sa.sessionDuration)
@@ -138,17 +298,11 @@ func (sa *SessionAnalyzer) createNewBlock(entry models.UsageEntry) *models.Sessi
 	return block
 }
 
-// addEntryToBlock adds entry to block and aggregates data per model
-func (sa *SessionAnalyzer) addEntryToBlock(block *models.SessionBlock, entry models.UsageEntry) {
+// addEntryToBlock adds entry to block and aggregates data per model, the entry's
+// normalized model
+func (sa *SessionAnalyzer) addEntryToBlock(block *models.SessionBlock, entry models.UsageEntry, model string) {
 	block.Entries = append(block.Entries, entry)
 
-	// Normalize model name
-	rawModel := entry.Model
-	if rawModel == "" {
-		rawModel = "unknown"
-	}
-	model := models.NormalizeModelName(rawModel)
-
 	// Initialize per-model stats if not exists
 	if _, exists := block.PerModelStats[model]; !exists {
 		block.PerModelStats[model] = map[string]any{
@@ -156,6 +310,7 @@ func (sa *SessionAnalyzer) addEntryToBlock(block *models.SessionBlock, entry mod
 			"output_tokens":         0,
 			"cache_creation_tokens": 0,
 			"cache_read_tokens":     0,
+			"thinking_tokens":       0,
 			"cost_usd":              0.0,
 			"entries_count":         0,
 		}
@@ -167,6 +322,7 @@ func (sa *SessionAnalyzer) addEntryToBlock(block *models.SessionBlock, entry mod
 	modelStats["output_tokens"] = modelStats["output_tokens"].(int) + entry.OutputTokens
 	modelStats["cache_creation_tokens"] = modelStats["cache_creation_tokens"].(int) + entry.CacheCreationTokens
 	modelStats["cache_read_tokens"] = modelStats["cache_read_tokens"].(int) + entry.CacheReadTokens
+	modelStats["thinking_tokens"] = modelStats["thinking_tokens"].(int) + entry.ThinkingTokens
 	modelStats["cost_usd"] = modelStats["cost_usd"].(float64) + entry.CostUSD
 	modelStats["entries_count"] = modelStats["entries_count"].(int) + 1
 
@@ -175,6 +331,7 @@ func (sa *SessionAnalyzer) addEntryToBlock(block *models.SessionBlock, entry mod
 	block.TokenCounts.OutputTokens += entry.OutputTokens
 	block.TokenCounts.CacheCreationTokens += entry.CacheCreationTokens
 	block.TokenCounts.CacheReadTokens += entry.CacheReadTokens
+	block.TokenCounts.ThinkingTokens += entry.ThinkingTokens
 
 	// Update aggregated cost
 	block.CostUSD += entry.CostUSD
@@ -197,6 +354,7 @@ func (sa *SessionAnalyzer) addEntryToBlock(block *models.SessionBlock, entry mod
 	legacyStats.OutputTokens += entry.OutputTokens
 	legacyStats.CacheCreationTokens += entry.CacheCreationTokens
 	legacyStats.CacheReadTokens += entry.CacheReadTokens
+	legacyStats.ThinkingTokens += entry.ThinkingTokens
 	legacyStats.TotalTokens += entry.TotalTokens
 	legacyStats.Cost += entry.CostUSD
 	block.ModelStats[model] = legacyStats
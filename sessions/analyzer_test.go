@@ -0,0 +1,136 @@
+package sessions
+
+import (
+	"testing"
+	"time"
+
+	"github.com/penwyp/claudecat/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionAnalyzer_CustomWindowDuration(t *testing.T) {
+	base := time.Date(2025, 6, 1, 10, 5, 0, 0, time.UTC)
+	newEntries := func() []models.UsageEntry {
+		return []models.UsageEntry{
+			{Timestamp: base, Model: "claude-sonnet-4-20250514", InputTokens: 100, TotalTokens: 100},
+			{Timestamp: base.Add(2*time.Hour + 25*time.Minute), Model: "claude-sonnet-4-20250514", InputTokens: 100, TotalTokens: 100},
+			{Timestamp: base.Add(3*time.Hour + 5*time.Minute), Model: "claude-sonnet-4-20250514", InputTokens: 100, TotalTokens: 100},
+		}
+	}
+
+	blocks := NewSessionAnalyzer(5).TransformToBlocks(newEntries())
+	require.Len(t, blocks, 1)
+	assert.Equal(t, 5*time.Hour, blocks[0].EndTime.Sub(blocks[0].StartTime))
+
+	blocks = NewSessionAnalyzerWithDuration(3 * time.Hour).TransformToBlocks(newEntries())
+	require.Len(t, blocks, 2)
+	assert.Equal(t, time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC), blocks[0].StartTime)
+	assert.Equal(t, time.Date(2025, 6, 1, 13, 0, 0, 0, time.UTC), blocks[0].EndTime)
+	assert.Len(t, blocks[0].Entries, 2)
+	assert.Equal(t, time.Date(2025, 6, 1, 13, 0, 0, 0, time.UTC), blocks[1].StartTime)
+	assert.Len(t, blocks[1].Entries, 1)
+}
+
+func TestTransformToBlocks_LargeHistory(t *testing.T) {
+	// One entry a minute, pausing for eight hours every 5000 entries, in reverse order
+	base := time.Date(2025, 6, 1, 10, 5, 0, 0, time.UTC)
+	count := 3 * parallelTransformThreshold
+	entries := make([]models.UsageEntry, count)
+	timestamp := base
+	for i := 0; i < count; i++ {
+		if i > 0 && i%5000 == 0 {
+			timestamp = timestamp.Add(8 * time.Hour)
+		}
+		entries[count-1-i] = models.UsageEntry{Timestamp: timestamp, Model: "claude-sonnet-4-20250514", InputTokens: 10}
+		timestamp = timestamp.Add(time.Minute)
+	}
+
+	blocks := NewSessionAnalyzer(5).TransformToBlocks(entries)
+
+	var total, gaps int
+	for i, block := range blocks {
+		if i > 0 {
+			assert.False(t, block.StartTime.Before(blocks[i-1].StartTime), "blocks are in order")
+		}
+		if block.IsGap {
+			gaps++
+			continue
+		}
+		total += len(block.Entries)
+		assert.Equal(t, len(block.Entries)*10, block.TokenCounts.InputTokens)
+		for _, entry := range block.Entries {
+			assert.False(t, entry.Timestamp.Before(block.StartTime))
+			assert.True(t, entry.Timestamp.Before(block.EndTime))
+		}
+	}
+	assert.Equal(t, count, total)
+	assert.Equal(t, count/5000-1, gaps)
+}
+
+func TestForEachSpan(t *testing.T) {
+	for _, workers := range []int{1, 4, 100} {
+		visits := make([]int, 50)
+		forEachSpan(len(visits), workers, func(i int) { visits[i]++ })
+		for i, n := range visits {
+			assert.Equal(t, 1, n, "span %d with %d workers", i, workers)
+		}
+	}
+}
+
+func TestNewSessionAnalyzerWithDuration_DefaultsToFiveHours(t *testing.T) {
+	assert.Equal(t, models.SessionDuration, NewSessionAnalyzerWithDuration(0).sessionDuration)
+	assert.Equal(t, models.SessionDuration, NewSessionAnalyzer(-1).sessionDuration)
+}
+
+func TestIsLimitRecord(t *testing.T) {
+	systemLimit := map[string]interface{}{
+		"type":      "system",
+		"timestamp": "2025-06-01T10:00:00Z",
+		"content":   "Claude usage limit reached. Your limit will reset at 3pm",
+	}
+	toolLimit := map[string]interface{}{
+		"type":      "user",
+		"timestamp": "2025-06-01T10:00:00Z",
+		"message": map[string]interface{}{
+			"content": []interface{}{
+				map[string]interface{}{"type": "tool_result", "content": "Rate limit exceeded"},
+			},
+		},
+	}
+	assistant := map[string]interface{}{
+		"type":      "assistant",
+		"timestamp": "2025-06-01T10:00:00Z",
+		"message":   map[string]interface{}{"id": "m1"},
+	}
+	otherSystem := map[string]interface{}{
+		"type":      "system",
+		"timestamp": "2025-06-01T10:00:00Z",
+		"content":   "Conversation compacted",
+	}
+
+	assert.True(t, IsLimitRecord(systemLimit))
+	assert.True(t, IsLimitRecord(toolLimit))
+	assert.False(t, IsLimitRecord(assistant))
+	assert.False(t, IsLimitRecord(otherSystem))
+
+	limits := NewSessionAnalyzer(5).DetectLimits([]map[string]interface{}{systemLimit, assistant, toolLimit})
+	assert.Len(t, limits, 2)
+}
+
+func TestAttachLimits(t *testing.T) {
+	base := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	blocks := []models.SessionBlock{
+		{StartTime: base, EndTime: base.Add(5 * time.Hour)},
+		{StartTime: base.Add(5 * time.Hour), EndTime: base.Add(10 * time.Hour)},
+	}
+	limits := []models.LimitMessage{
+		{Timestamp: base, Type: "system_limit"},
+		{Timestamp: base.Add(5 * time.Hour), Type: "system_limit"}, // On the boundary of both
+		{Timestamp: base.Add(12 * time.Hour), Type: "system_limit"},
+	}
+
+	AttachLimits(blocks, limits)
+	assert.Len(t, blocks[0].LimitMessages, 2)
+	assert.Len(t, blocks[1].LimitMessages, 1)
+}
@@ -0,0 +1,343 @@
+package sessions
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/penwyp/claudecat/models"
+)
+
+// HistoryFileName is the name of the session history file inside the cache directory
+const HistoryFileName = "session_history.json"
+
+// LimitEvent represents a single detected limit hit within a session
+type LimitEvent struct {
+	Type        string        `json:"type"`
+	Timestamp   time.Time     `json:"timestamp"`
+	SessionID   string        `json:"session_id"`
+	Message     string        `json:"message"`
+	TokensAtHit int           `json:"tokens_at_hit"`
+	Downtime    time.Duration `json:"downtime"`
+}
+
+// ModelUsage contains aggregated usage for a single model within a session
+type ModelUsage struct {
+	InputTokens         int     `json:"input_tokens"`
+	OutputTokens        int     `json:"output_tokens"`
+	CacheCreationTokens int     `json:"cache_creation_tokens"`
+	CacheReadTokens     int     `json:"cache_read_tokens"`
+	ThinkingTokens      int     `json:"thinking_tokens"`
+	TotalTokens         int     `json:"total_tokens"`
+	CostUSD             float64 `json:"cost_usd"`
+	EntryCount          int     `json:"entry_count"`
+}
+
+// HistoryRecord is the persisted summary of a single session block
+type HistoryRecord struct {
+	ID            string                `json:"id"`
+	StartTime     time.Time             `json:"start_time"`
+	EndTime       time.Time             `json:"end_time"`
+	ActualEndTime *time.Time            `json:"actual_end_time,omitempty"`
+	TokenCounts   models.TokenCounts    `json:"token_counts"`
+	TotalTokens   int                   `json:"total_tokens"`
+	CostUSD       float64               `json:"cost_usd"`
+	EntryCount    int                   `json:"entry_count"`
+	Models        []string              `json:"models"`
+	PerModel      map[string]ModelUsage `json:"per_model"`
+	Projects      map[string]int        `json:"projects"` // Project name -> total tokens
+	Limits        []LimitEvent          `json:"limits"`
+	IsActive      bool                  `json:"is_active"`
+	RecordedAt    time.Time             `json:"recorded_at"`
+}
+
+// Duration returns how long the session was in use: until its last activity, or until the
+// window ends when that isn't known
+func (r *HistoryRecord) Duration() time.Duration {
+	end := r.EndTime
+	if r.ActualEndTime != nil {
+		end = *r.ActualEndTime
+	}
+	return end.Sub(r.StartTime)
+}
+
+// historyFile is the on-disk format of the session history
+type historyFile struct {
+	UpdatedAt time.Time        `json:"updated_at"`
+	Records   []*HistoryRecord `json:"records"`
+}
+
+// HistoryStore persists session block summaries and limit events across runs
+type HistoryStore struct {
+	mu      sync.RWMutex
+	path    string
+	records map[string]*HistoryRecord
+}
+
+// NewHistoryStore opens (or creates) the session history in the given cache directory
+func NewHistoryStore(cacheDir string) (*HistoryStore, error) {
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	store := &HistoryStore{
+		path:    filepath.Join(cacheDir, HistoryFileName),
+		records: make(map[string]*HistoryRecord),
+	}
+
+	if err := store.load(); err != nil {
+		return nil, err
+	}
+
+	return store, nil
+}
+
+// load reads the history file from disk if it exists
+func (h *HistoryStore) load() error {
+	data, err := os.ReadFile(h.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read session history: %w", err)
+	}
+
+	var file historyFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("failed to unmarshal session history: %w", err)
+	}
+
+	for _, record := range file.Records {
+		if record != nil && record.ID != "" {
+			h.records[record.ID] = record
+		}
+	}
+
+	return nil
+}
+
+// Path returns the location of the history file
+func (h *HistoryStore) Path() string {
+	return h.path
+}
+
+// Record merges the given blocks into the history, replacing existing records with the same ID.
+// Gap blocks are skipped. Limits are assigned to the block whose window contains them.
+func (h *HistoryStore) Record(blocks []models.SessionBlock, limits []models.LimitMessage) {
+	records := BuildHistoryRecords(blocks, limits)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for i := range records {
+		h.records[records[i].ID] = &records[i]
+	}
+}
+
+// Save writes the history to disk atomically
+func (h *HistoryStore) Save() error {
+	h.mu.RLock()
+	file := historyFile{
+		UpdatedAt: time.Now(),
+		Records:   make([]*HistoryRecord, 0, len(h.records)),
+	}
+	for _, record := range h.records {
+		file.Records = append(file.Records, record)
+	}
+	h.mu.RUnlock()
+
+	sort.Slice(file.Records, func(i, j int) bool {
+		return file.Records[i].StartTime.Before(file.Records[j].StartTime)
+	})
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal session history: %w", err)
+	}
+
+	// Write to temporary file first
+	tmpFile := h.path + ".tmp"
+	if err := os.WriteFile(tmpFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to write session history: %w", err)
+	}
+
+	// Rename to final location (atomic operation)
+	if err := os.Rename(tmpFile, h.path); err != nil {
+		os.Remove(tmpFile) // Clean up temp file
+		return fmt.Errorf("failed to rename session history: %w", err)
+	}
+
+	return nil
+}
+
+// Sessions returns records starting within [from, to), sorted by start time.
+// A zero from or to leaves that side of the range open.
+func (h *HistoryStore) Sessions(from, to time.Time) []HistoryRecord {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	var result []HistoryRecord
+	for _, record := range h.records {
+		if !from.IsZero() && record.StartTime.Before(from) {
+			continue
+		}
+		if !to.IsZero() && !record.StartTime.Before(to) {
+			continue
+		}
+		result = append(result, *record)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].StartTime.Before(result[j].StartTime)
+	})
+
+	return result
+}
+
+// Session returns the record with the given ID, or the only record whose ID starts with it
+func (h *HistoryStore) Session(id string) (*HistoryRecord, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	record, exists := h.records[id]
+	if !exists && id != "" {
+		var matches []string
+		for recordID, candidate := range h.records {
+			if strings.HasPrefix(recordID, id) {
+				matches = append(matches, recordID)
+				record = candidate
+			}
+		}
+		if len(matches) > 1 {
+			sort.Strings(matches)
+			return nil, fmt.Errorf("session ID %s is ambiguous: matches %s", id, strings.Join(matches, ", "))
+		}
+		exists = len(matches) == 1
+	}
+	if !exists {
+		return nil, fmt.Errorf("session not found: %s", id)
+	}
+
+	result := *record
+	return &result, nil
+}
+
+// LimitEvents returns all limit events within [from, to), sorted by timestamp.
+// A zero from or to leaves that side of the range open.
+func (h *HistoryStore) LimitEvents(from, to time.Time) []LimitEvent {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	var events []LimitEvent
+	for _, record := range h.records {
+		for _, event := range record.Limits {
+			if !from.IsZero() && event.Timestamp.Before(from) {
+				continue
+			}
+			if !to.IsZero() && !event.Timestamp.Before(to) {
+				continue
+			}
+			events = append(events, event)
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Timestamp.Before(events[j].Timestamp)
+	})
+
+	return events
+}
+
+// BuildHistoryRecords converts session blocks into history records, attaching limit events
+func BuildHistoryRecords(blocks []models.SessionBlock, limits []models.LimitMessage) []HistoryRecord {
+	now := time.Now()
+	records := make([]HistoryRecord, 0, len(blocks))
+
+	for _, block := range blocks {
+		if block.IsGap {
+			continue
+		}
+
+		record := HistoryRecord{
+			ID:            block.ID,
+			StartTime:     block.StartTime,
+			EndTime:       block.EndTime,
+			ActualEndTime: block.ActualEndTime,
+			TokenCounts:   block.TokenCounts,
+			TotalTokens:   block.TokenCounts.TotalTokens(),
+			CostUSD:       block.CostUSD,
+			EntryCount:    len(block.Entries),
+			Models:        append([]string{}, block.Models...),
+			PerModel:      make(map[string]ModelUsage),
+			Projects:      make(map[string]int),
+			Limits:        []LimitEvent{},
+			IsActive:      block.IsActive,
+			RecordedAt:    now,
+		}
+
+		for _, entry := range block.Entries {
+			model := entry.Model
+			if model == "" {
+				model = "unknown"
+			}
+			model = models.NormalizeModelName(model)
+
+			usage := record.PerModel[model]
+			usage.InputTokens += entry.InputTokens
+			usage.OutputTokens += entry.OutputTokens
+			usage.CacheCreationTokens += entry.CacheCreationTokens
+			usage.CacheReadTokens += entry.CacheReadTokens
+			usage.ThinkingTokens += entry.ThinkingTokens
+			usage.TotalTokens += entry.TotalTokens
+			usage.CostUSD += entry.CostUSD
+			usage.EntryCount++
+			record.PerModel[model] = usage
+
+			if entry.Project != "" {
+				record.Projects[entry.Project] += entry.TotalTokens
+			}
+		}
+
+		for _, limit := range limits {
+			if limit.Timestamp.Before(block.StartTime) || !limit.Timestamp.Before(block.EndTime) {
+				continue
+			}
+			record.Limits = append(record.Limits, newLimitEvent(block, limit))
+		}
+
+		records = append(records, record)
+	}
+
+	return records
+}
+
+// newLimitEvent builds a limit event for a limit message inside the given block.
+// Downtime lasts until the next usage entry or, if none follows, until the block resets.
+func newLimitEvent(block models.SessionBlock, limit models.LimitMessage) LimitEvent {
+	event := LimitEvent{
+		Type:      limit.Type,
+		Timestamp: limit.Timestamp,
+		SessionID: block.ID,
+		Message:   limit.Message,
+	}
+
+	resumeAt := block.EndTime
+	for _, entry := range block.Entries {
+		if entry.Timestamp.After(limit.Timestamp) {
+			resumeAt = entry.Timestamp
+			break
+		}
+		event.TokensAtHit += entry.TotalTokens
+	}
+
+	if resumeAt.After(limit.Timestamp) {
+		event.Downtime = resumeAt.Sub(limit.Timestamp)
+	}
+
+	return event
+}
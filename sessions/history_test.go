@@ -0,0 +1,104 @@
+package sessions
+
+import (
+	"testing"
+	"time"
+
+	"github.com/penwyp/claudecat/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildHistoryRecords_AttachesLimitEvents(t *testing.T) {
+	baseTime := time.Date(2024, 3, 15, 10, 0, 0, 0, time.UTC)
+	entries := []models.UsageEntry{
+		{Timestamp: baseTime.Add(5 * time.Minute), Model: "claude-3-5-sonnet", InputTokens: 1000, TotalTokens: 1000, CostUSD: 0.01, Project: "alpha"},
+		{Timestamp: baseTime.Add(30 * time.Minute), Model: "claude-3-opus", InputTokens: 2000, TotalTokens: 2000, CostUSD: 0.05, Project: "beta"},
+		{Timestamp: baseTime.Add(3 * time.Hour), Model: "claude-3-5-sonnet", InputTokens: 500, TotalTokens: 500, CostUSD: 0.005, Project: "alpha"},
+	}
+
+	analyzer := NewSessionAnalyzer(5)
+	blocks := analyzer.TransformToBlocks(entries)
+	require.Len(t, blocks, 1)
+
+	limits := []models.LimitMessage{
+		{Type: "system_limit", Timestamp: baseTime.Add(time.Hour), Message: "rate limit reached"},
+		{Type: "system_limit", Timestamp: baseTime.Add(6 * time.Hour), Message: "outside of block"},
+	}
+
+	records := BuildHistoryRecords(blocks, limits)
+	require.Len(t, records, 1)
+
+	record := records[0]
+	assert.Equal(t, blocks[0].ID, record.ID)
+	assert.Equal(t, 3, record.EntryCount)
+	assert.Equal(t, 3500, record.TotalTokens)
+	assert.Equal(t, 1500, record.Projects["alpha"])
+	assert.Equal(t, 2000, record.Projects["beta"])
+	assert.Len(t, record.PerModel, 2)
+
+	require.Len(t, record.Limits, 1)
+	event := record.Limits[0]
+	assert.Equal(t, blocks[0].ID, event.SessionID)
+	assert.Equal(t, 3000, event.TokensAtHit)
+	assert.Equal(t, 2*time.Hour, event.Downtime)
+}
+
+func TestHistoryStore_PersistsAcrossReopen(t *testing.T) {
+	dir := t.TempDir()
+	baseTime := time.Date(2024, 3, 15, 10, 0, 0, 0, time.UTC)
+
+	store, err := NewHistoryStore(dir)
+	require.NoError(t, err)
+
+	analyzer := NewSessionAnalyzer(5)
+	blocks := analyzer.TransformToBlocks([]models.UsageEntry{
+		{Timestamp: baseTime, Model: "claude-3-5-sonnet", InputTokens: 100, TotalTokens: 100},
+	})
+	store.Record(blocks, []models.LimitMessage{
+		{Type: "opus_limit", Timestamp: baseTime.Add(time.Hour)},
+	})
+	require.NoError(t, store.Save())
+
+	reopened, err := NewHistoryStore(dir)
+	require.NoError(t, err)
+
+	sessions := reopened.Sessions(time.Time{}, time.Time{})
+	require.Len(t, sessions, 1)
+	assert.Equal(t, blocks[0].ID, sessions[0].ID)
+
+	events := reopened.LimitEvents(baseTime, baseTime.Add(2*time.Hour))
+	require.Len(t, events, 1)
+	assert.Equal(t, "opus_limit", events[0].Type)
+	// No entry follows the hit, so downtime lasts until the block resets
+	assert.Equal(t, 4*time.Hour, events[0].Downtime)
+
+	assert.Empty(t, reopened.LimitEvents(baseTime.Add(2*time.Hour), time.Time{}))
+}
+
+func TestHistoryStore_SessionByPrefix(t *testing.T) {
+	store, err := NewHistoryStore(t.TempDir())
+	require.NoError(t, err)
+
+	start := time.Date(2024, 3, 15, 10, 0, 0, 0, time.UTC)
+	lastUsed := start.Add(90 * time.Minute)
+	store.records = map[string]*HistoryRecord{
+		"abc123": {ID: "abc123", StartTime: start, EndTime: start.Add(5 * time.Hour), ActualEndTime: &lastUsed},
+		"abd456": {ID: "abd456", StartTime: start, EndTime: start.Add(5 * time.Hour)},
+	}
+
+	record, err := store.Session("abc")
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", record.ID)
+	assert.Equal(t, 90*time.Minute, record.Duration())
+
+	record, err = store.Session("abd456")
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Hour, record.Duration())
+
+	_, err = store.Session("ab")
+	assert.ErrorContains(t, err, "ambiguous")
+
+	_, err = store.Session("zzz")
+	assert.ErrorContains(t, err, "not found")
+}
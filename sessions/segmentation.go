@@ -0,0 +1,151 @@
+package sessions
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/penwyp/claudecat/models"
+)
+
+// Block split strategies, as named in configuration
+const (
+	SplitLimitReset  = "limit_reset"  // Start a new window where usage resumes after a limit was hit
+	SplitModelSwitch = "model_switch" // Start a new block within the window where usage moves to another model family
+)
+
+// modelSwitchRun is how many consecutive entries of another model family make a major
+// model switch. Shorter runs, like the quick background calls Claude Code makes to a
+// smaller model, stay in the block they interrupt.
+const modelSwitchRun = 5
+
+// Segmentation selects where session blocks are split besides the session window, for
+// cost attribution finer than whole windows
+type Segmentation struct {
+	LimitResets   bool
+	ModelSwitches bool
+}
+
+// ParseSegmentation returns the segmentation of the named split strategies
+func ParseSegmentation(names []string) (Segmentation, error) {
+	var seg Segmentation
+	for _, name := range names {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case SplitLimitReset:
+			seg.LimitResets = true
+		case SplitModelSwitch:
+			seg.ModelSwitches = true
+		default:
+			return Segmentation{}, fmt.Errorf("invalid block split strategy: %s (valid: %s, %s)",
+				name, SplitLimitReset, SplitModelSwitch)
+		}
+	}
+	return seg, nil
+}
+
+// SetSegmentation makes the analyzer split blocks further as seg selects. Limit resets are
+// found in limits, as returned by DetectLimits; only system limit messages count, since
+// tool results often just mention limits.
+func (sa *SessionAnalyzer) SetSegmentation(seg Segmentation, limits []models.LimitMessage) {
+	sa.segmentation = seg
+	sa.limitHits = nil
+	if !seg.LimitResets {
+		return
+	}
+	for _, limit := range limits {
+		if limit.Type == "system_limit" || limit.Type == "opus_limit" {
+			sa.limitHits = append(sa.limitHits, limit.Timestamp)
+		}
+	}
+	sort.Slice(sa.limitHits, func(i, j int) bool {
+		return sa.limitHits[i].Before(sa.limitHits[j])
+	})
+}
+
+// limitResets finds the entries where usage resumes after a limit was hit
+type limitResets struct {
+	hits []time.Time // Sorted times limits were hit
+	next int         // First hit not before the previous entry
+}
+
+// resumesAt reports whether entries[i] is the first after a limit hit and starts a window
+// of its own. Usage resuming within the hour of its last entry before the hit didn't wait
+// for a reset, so it continues the window.
+func (r *limitResets) resumesAt(sa *SessionAnalyzer, entries []models.UsageEntry, i int) bool {
+	previous, current := entries[i-1].Timestamp, entries[i].Timestamp
+	for r.next < len(r.hits) && r.hits[r.next].Before(previous) {
+		r.next++
+	}
+	if r.next == len(r.hits) || !r.hits[r.next].Before(current) {
+		return false
+	}
+	r.next++
+	return sa.roundToHour(current).After(previous)
+}
+
+// modelSwitches finds major model switches: runs of at least modelSwitchRun entries of
+// another model family than the block they are in
+type modelSwitches struct {
+	families  map[string]string // Family of each raw model name
+	current   string            // Family of the block; empty until an entry has a known family
+	runStart  int
+	runFamily string
+	run       int
+}
+
+func newModelSwitches() *modelSwitches {
+	return &modelSwitches{families: make(map[string]string)}
+}
+
+// restart forgets the family of the block, as a new one starts
+func (m *modelSwitches) restart() {
+	m.current, m.runFamily, m.run = "", "", 0
+}
+
+// observe records entries[i] and returns the index of the entry a major switch started
+// at, or -1. Entries without a known model neither start nor interrupt a run.
+func (m *modelSwitches) observe(entries []models.UsageEntry, i int) int {
+	family := m.family(entries[i].Model)
+	switch {
+	case family == "":
+		return -1
+	case m.current == "":
+		m.current = family
+		return -1
+	case family == m.current:
+		m.runFamily, m.run = "", 0
+		return -1
+	case family != m.runFamily:
+		m.runStart, m.runFamily, m.run = i, family, 1
+	default:
+		m.run++
+	}
+
+	if m.run < modelSwitchRun {
+		return -1
+	}
+	start := m.runStart
+	m.current, m.runFamily, m.run = family, "", 0
+	return start
+}
+
+// family returns the model family of a raw model name: opus, sonnet or haiku for Claude
+// models and the normalized name for others, empty for unknown and synthetic models
+func (m *modelSwitches) family(model string) string {
+	if family, ok := m.families[model]; ok {
+		return family
+	}
+	family := ""
+	if model != "" && model != "<synthetic>" {
+		family = strings.ToLower(normalizeEntryModel(model))
+		for _, name := range []string{"opus", "sonnet", "haiku"} {
+			if strings.Contains(family, name) {
+				family = name
+				break
+			}
+		}
+	}
+	m.families[model] = family
+	return family
+}
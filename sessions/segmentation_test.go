@@ -0,0 +1,95 @@
+package sessions
+
+import (
+	"testing"
+	"time"
+
+	"github.com/penwyp/claudecat/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSegmentation(t *testing.T) {
+	seg, err := ParseSegmentation(nil)
+	require.NoError(t, err)
+	assert.Equal(t, Segmentation{}, seg)
+
+	seg, err = ParseSegmentation([]string{"limit_reset", " Model_Switch"})
+	require.NoError(t, err)
+	assert.Equal(t, Segmentation{LimitResets: true, ModelSwitches: true}, seg)
+
+	_, err = ParseSegmentation([]string{"project"})
+	assert.Error(t, err)
+}
+
+func TestTransformToBlocks_SplitAtLimitReset(t *testing.T) {
+	base := time.Date(2025, 6, 1, 10, 5, 0, 0, time.UTC)
+	entries := []models.UsageEntry{
+		{Timestamp: base, Model: "claude-sonnet-4-20250514", InputTokens: 100},
+		{Timestamp: base.Add(time.Hour), Model: "claude-sonnet-4-20250514", InputTokens: 100},
+		// Resumes within the hour of the last entry, so no reset happened
+		{Timestamp: base.Add(time.Hour + 20*time.Minute), Model: "claude-sonnet-4-20250514", InputTokens: 100},
+		{Timestamp: base.Add(3 * time.Hour), Model: "claude-sonnet-4-20250514", InputTokens: 100},
+	}
+	limits := []models.LimitMessage{
+		{Type: "system_limit", Timestamp: base.Add(time.Hour + 10*time.Minute)},
+		{Type: "tool_result_limit", Timestamp: base.Add(2 * time.Hour)}, // A tool merely mentioning limits
+		{Type: "system_limit", Timestamp: base.Add(2*time.Hour + 30*time.Minute)},
+	}
+
+	analyzer := NewSessionAnalyzer(5)
+	require.Len(t, analyzer.TransformToBlocks(entries), 1)
+
+	analyzer.SetSegmentation(Segmentation{LimitResets: true}, limits)
+	blocks := analyzer.TransformToBlocks(entries)
+	require.Len(t, blocks, 2)
+	assert.Len(t, blocks[0].Entries, 3)
+	assert.Equal(t, time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC), blocks[0].StartTime)
+	assert.Equal(t, time.Date(2025, 6, 1, 13, 0, 0, 0, time.UTC), blocks[0].EndTime, "ends where the next window starts")
+	assert.Len(t, blocks[1].Entries, 1)
+	assert.Equal(t, time.Date(2025, 6, 1, 13, 0, 0, 0, time.UTC), blocks[1].StartTime)
+	assert.Equal(t, time.Date(2025, 6, 1, 18, 0, 0, 0, time.UTC), blocks[1].EndTime)
+	assert.NotEqual(t, blocks[0].ID, blocks[1].ID)
+}
+
+func TestTransformToBlocks_SplitAtModelSwitch(t *testing.T) {
+	base := time.Date(2025, 6, 1, 10, 5, 0, 0, time.UTC)
+	var entries []models.UsageEntry
+	add := func(model string, count int) {
+		for i := 0; i < count; i++ {
+			entries = append(entries, models.UsageEntry{
+				Timestamp: base.Add(time.Duration(len(entries)) * time.Minute), Model: model, InputTokens: 10, CostUSD: 0.01,
+			})
+		}
+	}
+	add("claude-opus-4-20250514", 10)
+	add("claude-3-5-haiku-20241022", 2) // A background call, not a switch
+	add("claude-opus-4-1-20250805", 5)  // Another Opus version, same family
+	add("claude-sonnet-4-20250514", 3)
+	add("", 1) // Unknown models don't interrupt the run
+	add("claude-sonnet-4-20250514", 4)
+
+	analyzer := NewSessionAnalyzer(5)
+	analyzer.SetSegmentation(Segmentation{ModelSwitches: true}, nil)
+	blocks := analyzer.TransformToBlocks(entries)
+	require.Len(t, blocks, 2)
+
+	switchTime := base.Add(17 * time.Minute)
+	assert.Len(t, blocks[0].Entries, 17)
+	assert.Equal(t, time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC), blocks[0].StartTime)
+	assert.Equal(t, switchTime, blocks[0].EndTime)
+	assert.False(t, blocks[0].IsActive)
+
+	assert.Len(t, blocks[1].Entries, 8)
+	assert.Equal(t, switchTime, blocks[1].StartTime)
+	assert.Equal(t, time.Date(2025, 6, 1, 15, 0, 0, 0, time.UTC), blocks[1].EndTime, "continues the window")
+	assert.InDelta(t, 0.08, blocks[1].CostUSD, 1e-9)
+
+	// A new window starts with the family its first entry uses
+	later := base.Add(6 * time.Hour)
+	entries = append(entries, models.UsageEntry{Timestamp: later, Model: "claude-sonnet-4-20250514"})
+	blocks = analyzer.TransformToBlocks(entries)
+	require.Len(t, blocks, 4)
+	assert.True(t, blocks[2].IsGap)
+	assert.Len(t, blocks[3].Entries, 1)
+}
@@ -0,0 +1,190 @@
+// Package snapshot bundles parsed usage entries, session blocks and the settings they were
+// computed with into a single compressed archive, so reports can be reproduced on another
+// machine without access to the original Claude data directory.
+//
+// An archive is a gzipped tar file holding metadata.json, entries.json, blocks.json and
+// limits.json.
+package snapshot
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/penwyp/claudecat/models"
+)
+
+// FormatVersion is the version of the archive layout written by Write
+const FormatVersion = 1
+
+// Names of the files inside an archive
+const (
+	metadataFile = "metadata.json"
+	entriesFile  = "entries.json"
+	blocksFile   = "blocks.json"
+	limitsFile   = "limits.json"
+)
+
+// maxMemberSize bounds a single archive member when reading, guarding against archives
+// that decompress to more than any real usage history would
+const maxMemberSize = 4 << 30
+
+// Metadata describes where and how an archive's data was produced
+type Metadata struct {
+	Version          int           `json:"version"`
+	ClaudecatVersion string        `json:"claudecat_version"`
+	CreatedAt        time.Time     `json:"created_at"`
+	Host             string        `json:"host,omitempty"`
+	DataPaths        []string      `json:"data_paths"`
+	From             *time.Time    `json:"from,omitempty"` // Start of the exported range, if limited
+	To               *time.Time    `json:"to,omitempty"`   // End of the exported range, if limited
+	Timezone         string        `json:"timezone"`       // Zone calendar reports were grouped in
+	CostMode         string        `json:"cost_mode"`
+	PricingSource    string        `json:"pricing_source"`
+	SessionWindow    time.Duration `json:"session_window"`
+	Redacted         bool          `json:"redacted,omitempty"` // Raw records were stripped of conversation content
+	Entries          int           `json:"entries"`
+	Blocks           int           `json:"blocks"`
+	Limits           int           `json:"limits"`
+}
+
+// Archive is the content of a snapshot archive
+type Archive struct {
+	Metadata Metadata
+	Entries  []models.UsageEntry
+	Blocks   []models.SessionBlock
+	Limits   []models.LimitMessage
+}
+
+// Write stores archive at path, filling in the metadata's version and counts
+func Write(path string, archive Archive) (err error) {
+	archive.Metadata.Version = FormatVersion
+	archive.Metadata.Entries = len(archive.Entries)
+	archive.Metadata.Blocks = len(archive.Blocks)
+	archive.Metadata.Limits = len(archive.Limits)
+
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create directory: %w", err)
+		}
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot: %w", err)
+	}
+	defer func() {
+		if closeErr := file.Close(); err == nil && closeErr != nil {
+			err = fmt.Errorf("failed to write snapshot: %w", closeErr)
+		}
+		if err != nil {
+			os.Remove(path)
+		}
+	}()
+
+	gz := gzip.NewWriter(file)
+	tw := tar.NewWriter(gz)
+	members := []struct {
+		name  string
+		value interface{}
+	}{
+		{metadataFile, archive.Metadata},
+		{entriesFile, archive.Entries},
+		{blocksFile, archive.Blocks},
+		{limitsFile, archive.Limits},
+	}
+	for _, member := range members {
+		if err := writeMember(tw, member.name, member.value, archive.Metadata.CreatedAt); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return nil
+}
+
+// Read loads the archive at path
+func Read(path string) (Archive, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return Archive{}, fmt.Errorf("failed to open snapshot: %w", err)
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return Archive{}, fmt.Errorf("not a snapshot archive: %w", err)
+	}
+	defer gz.Close()
+
+	var archive Archive
+	seen := make(map[string]bool)
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return Archive{}, fmt.Errorf("failed to read snapshot: %w", err)
+		}
+
+		var target interface{}
+		switch header.Name {
+		case metadataFile:
+			target = &archive.Metadata
+		case entriesFile:
+			target = &archive.Entries
+		case blocksFile:
+			target = &archive.Blocks
+		case limitsFile:
+			target = &archive.Limits
+		default:
+			continue // Written by a newer version
+		}
+		if err := json.NewDecoder(io.LimitReader(tr, maxMemberSize)).Decode(target); err != nil {
+			return Archive{}, fmt.Errorf("failed to decode %s: %w", header.Name, err)
+		}
+		seen[header.Name] = true
+	}
+
+	if !seen[metadataFile] {
+		return Archive{}, fmt.Errorf("not a snapshot archive: %s is missing", metadataFile)
+	}
+	if archive.Metadata.Version > FormatVersion {
+		return Archive{}, fmt.Errorf("unsupported snapshot version %d", archive.Metadata.Version)
+	}
+	if !seen[entriesFile] {
+		return Archive{}, fmt.Errorf("snapshot is incomplete: %s is missing", entriesFile)
+	}
+	return archive, nil
+}
+
+func writeMember(tw *tar.Writer, name string, value interface{}, modTime time.Time) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", name, err)
+	}
+	header := &tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: modTime,
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package snapshot
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/penwyp/claudecat/models"
+)
+
+func TestWriteRead_RoundTrip(t *testing.T) {
+	start := time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC)
+	entries := []models.UsageEntry{
+		{Timestamp: start, Model: "claude-sonnet-4-20250514", InputTokens: 1000, OutputTokens: 500, CostUSD: 0.0105, SessionID: "s1", Project: "proj-a"},
+		{Timestamp: start.Add(time.Hour), Model: "claude-opus-4-20250514", InputTokens: 2000, OutputTokens: 700, CacheReadTokens: 300, CostUSD: 0.08, SessionID: "s1", Project: "proj-a"},
+	}
+	archive := Archive{
+		Metadata: Metadata{
+			ClaudecatVersion: "v1.2.3",
+			CreatedAt:        start.Add(24 * time.Hour),
+			DataPaths:        []string{"/home/me/.claude/projects"},
+			Timezone:         "Europe/Berlin",
+			CostMode:         "auto",
+			PricingSource:    "default",
+			SessionWindow:    5 * time.Hour,
+		},
+		Entries: entries,
+		Blocks: []models.SessionBlock{{
+			ID:        start.Format(time.RFC3339),
+			StartTime: start,
+			EndTime:   start.Add(5 * time.Hour),
+			Entries:   entries,
+			CostUSD:   0.0905,
+			Models:    []string{"claude-sonnet-4-20250514", "claude-opus-4-20250514"},
+		}},
+		Limits: []models.LimitMessage{{Message: "limit reached", Timestamp: start.Add(2 * time.Hour), Type: "general_limit"}},
+	}
+
+	path := filepath.Join(t.TempDir(), "nested", "snapshot.tar.gz")
+	require.NoError(t, Write(path, archive))
+
+	loaded, err := Read(path)
+	require.NoError(t, err)
+	assert.Equal(t, FormatVersion, loaded.Metadata.Version)
+	assert.Equal(t, 2, loaded.Metadata.Entries)
+	assert.Equal(t, 1, loaded.Metadata.Blocks)
+	assert.Equal(t, 1, loaded.Metadata.Limits)
+	assert.Equal(t, "Europe/Berlin", loaded.Metadata.Timezone)
+	assert.Equal(t, 5*time.Hour, loaded.Metadata.SessionWindow)
+	assert.True(t, loaded.Metadata.CreatedAt.Equal(archive.Metadata.CreatedAt))
+	assert.Nil(t, loaded.Metadata.From)
+
+	require.Len(t, loaded.Entries, 2)
+	assert.Equal(t, entries[1].Model, loaded.Entries[1].Model)
+	assert.Equal(t, entries[1].CacheReadTokens, loaded.Entries[1].CacheReadTokens)
+	assert.InDelta(t, entries[1].CostUSD, loaded.Entries[1].CostUSD, 1e-9)
+	assert.True(t, loaded.Entries[0].Timestamp.Equal(start))
+
+	require.Len(t, loaded.Blocks, 1)
+	assert.Len(t, loaded.Blocks[0].Entries, 2)
+	assert.Equal(t, archive.Blocks[0].Models, loaded.Blocks[0].Models)
+	require.Len(t, loaded.Limits, 1)
+	assert.Equal(t, "limit reached", loaded.Limits[0].Message)
+}
+
+func TestRead_NotAnArchive(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "entries.json")
+	require.NoError(t, os.WriteFile(path, []byte(`[]`), 0644))
+
+	_, err := Read(path)
+	assert.ErrorContains(t, err, "not a snapshot archive")
+}
+
+func TestRead_RejectsNewerVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "future.tar.gz")
+	file, err := os.Create(path)
+	require.NoError(t, err)
+	gz := gzip.NewWriter(file)
+	tw := tar.NewWriter(gz)
+	require.NoError(t, writeMember(tw, metadataFile, Metadata{Version: FormatVersion + 1}, time.Now()))
+	require.NoError(t, writeMember(tw, entriesFile, []models.UsageEntry{}, time.Now()))
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	require.NoError(t, file.Close())
+
+	_, err = Read(path)
+	assert.ErrorContains(t, err, "unsupported snapshot version")
+}
@@ -0,0 +1,149 @@
+// Package statsd pushes gauges to a statsd or DogStatsD agent over UDP.
+//
+// DogStatsD agents receive tags in the datagram; plain statsd has no tags, so their values
+// are appended to the metric name instead.
+package statsd
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxPacketSize keeps datagrams below the common 1500 byte MTU once IP and UDP headers are added
+const maxPacketSize = 1432
+
+// Options configures a Client
+type Options struct {
+	Address   string            // host:port of the agent
+	Prefix    string            // Prepended to every metric name, joined with a dot
+	Tags      map[string]string // Tags added to every metric
+	DogStatsD bool              // Send tags in the DogStatsD format rather than in metric names
+}
+
+// Gauge is a metric value reported as is
+type Gauge struct {
+	Name  string
+	Value float64
+	Tags  map[string]string // Tags of this value only, such as the model it is for
+}
+
+// Client sends gauges to an agent. Sending is fire-and-forget: UDP reports no delivery,
+// and a stopped agent only surfaces as an occasional write error.
+type Client struct {
+	conn      net.Conn
+	prefix    string
+	tags      map[string]string
+	dogstatsd bool
+}
+
+// New creates a client sending to opts.Address
+func New(opts Options) (*Client, error) {
+	if _, _, err := net.SplitHostPort(opts.Address); err != nil {
+		return nil, fmt.Errorf("invalid statsd address %q: %w", opts.Address, err)
+	}
+	conn, err := net.Dial("udp", opts.Address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to statsd at %s: %w", opts.Address, err)
+	}
+	return &Client{
+		conn:      conn,
+		prefix:    strings.TrimSuffix(opts.Prefix, "."),
+		tags:      opts.Tags,
+		dogstatsd: opts.DogStatsD,
+	}, nil
+}
+
+// Send writes gauges, batching as many lines into each datagram as fit
+func (c *Client) Send(gauges []Gauge) error {
+	var packet []byte
+	for _, gauge := range gauges {
+		line := c.format(gauge)
+		if len(packet) > 0 && len(packet)+1+len(line) > maxPacketSize {
+			if _, err := c.conn.Write(packet); err != nil {
+				return fmt.Errorf("failed to send metrics: %w", err)
+			}
+			packet = packet[:0]
+		}
+		if len(packet) > 0 {
+			packet = append(packet, '\n')
+		}
+		packet = append(packet, line...)
+	}
+	if len(packet) > 0 {
+		if _, err := c.conn.Write(packet); err != nil {
+			return fmt.Errorf("failed to send metrics: %w", err)
+		}
+	}
+	return nil
+}
+
+// Close closes the connection
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// format renders a gauge as one line, e.g. claudecat.session.tokens:1200|g|#model:opus
+func (c *Client) format(gauge Gauge) string {
+	var b strings.Builder
+	if c.prefix != "" {
+		b.WriteString(c.prefix)
+		b.WriteByte('.')
+	}
+	b.WriteString(sanitize(gauge.Name, "."))
+
+	if !c.dogstatsd {
+		for _, key := range sortedKeys(gauge.Tags) {
+			b.WriteByte('.')
+			b.WriteString(sanitize(gauge.Tags[key], ""))
+		}
+	}
+	b.WriteByte(':')
+	b.WriteString(strconv.FormatFloat(gauge.Value, 'f', -1, 64))
+	b.WriteString("|g")
+
+	if c.dogstatsd && len(c.tags)+len(gauge.Tags) > 0 {
+		b.WriteString("|#")
+		first := true
+		for _, tags := range []map[string]string{c.tags, gauge.Tags} {
+			for _, key := range sortedKeys(tags) {
+				if !first {
+					b.WriteByte(',')
+				}
+				first = false
+				b.WriteString(sanitize(key, "."))
+				if value := tags[key]; value != "" {
+					b.WriteByte(':')
+					b.WriteString(sanitize(value, ":."))
+				}
+			}
+		}
+	}
+	return b.String()
+}
+
+// sanitize replaces the characters the line format reserves, and dots unless allowed, with
+// underscores
+func sanitize(s, allowed string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == ':' || r == '|' || r == '@' || r == '#' || r == ',' || r == '.' || r == ' ' || r == '\n':
+			if strings.ContainsRune(allowed, r) {
+				return r
+			}
+			return '_'
+		}
+		return r
+	}, s)
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
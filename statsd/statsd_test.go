@@ -0,0 +1,87 @@
+package statsd
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func listen(t *testing.T) *net.UDPConn {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func receive(t *testing.T, conn *net.UDPConn) string {
+	buf := make([]byte, 65536)
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+	n, err := conn.Read(buf)
+	require.NoError(t, err)
+	return string(buf[:n])
+}
+
+func TestClient_SendDogStatsD(t *testing.T) {
+	agent := listen(t)
+	client, err := New(Options{
+		Address:   agent.LocalAddr().String(),
+		Prefix:    "claudecat.",
+		Tags:      map[string]string{"team": "platform", "env": "ci"},
+		DogStatsD: true,
+	})
+	require.NoError(t, err)
+	defer client.Close()
+
+	require.NoError(t, client.Send([]Gauge{
+		{Name: "session.tokens", Value: 1200},
+		{Name: "session.model.cost_usd", Value: 0.25, Tags: map[string]string{"model": "claude-3.5-sonnet"}},
+	}))
+	assert.Equal(t, "claudecat.session.tokens:1200|g|#env:ci,team:platform\n"+
+		"claudecat.session.model.cost_usd:0.25|g|#env:ci,team:platform,model:claude-3.5-sonnet", receive(t, agent))
+}
+
+func TestClient_SendStatsdFoldsTagsIntoNames(t *testing.T) {
+	agent := listen(t)
+	client, err := New(Options{
+		Address: agent.LocalAddr().String(),
+		Prefix:  "claudecat",
+		Tags:    map[string]string{"team": "platform"},
+	})
+	require.NoError(t, err)
+	defer client.Close()
+
+	require.NoError(t, client.Send([]Gauge{
+		{Name: "session.model.tokens", Value: 7, Tags: map[string]string{"model": "claude-3.5-sonnet"}},
+	}))
+	assert.Equal(t, "claudecat.session.model.tokens.claude-3_5-sonnet:7|g", receive(t, agent))
+}
+
+func TestClient_SendSplitsPackets(t *testing.T) {
+	agent := listen(t)
+	client, err := New(Options{Address: agent.LocalAddr().String(), Prefix: "claudecat"})
+	require.NoError(t, err)
+	defer client.Close()
+
+	gauges := make([]Gauge, 200)
+	for i := range gauges {
+		gauges[i] = Gauge{Name: "session.tokens", Value: float64(i)}
+	}
+	require.NoError(t, client.Send(gauges))
+
+	var lines int
+	for lines < len(gauges) {
+		packet := receive(t, agent)
+		assert.LessOrEqual(t, len(packet), maxPacketSize)
+		lines += len(strings.Split(packet, "\n"))
+	}
+	assert.Equal(t, len(gauges), lines)
+}
+
+func TestNew_InvalidAddress(t *testing.T) {
+	_, err := New(Options{Address: "localhost"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,174 @@
+package team
+
+import (
+	"sort"
+
+	"github.com/penwyp/claudecat/models"
+	"github.com/penwyp/claudecat/output"
+)
+
+// UserUsage is the combined usage of one user across their machines
+type UserUsage struct {
+	User        string                    `json:"user"`
+	Hosts       []string                  `json:"hosts"`
+	TokenCounts models.TokenCounts        `json:"token_counts"`
+	TotalTokens int                       `json:"total_tokens"`
+	CostUSD     float64                   `json:"cost_usd"`
+	Entries     int                       `json:"entries"`
+	ActiveDays  int                       `json:"active_days"`
+	FirstDay    string                    `json:"first_day"`
+	LastDay     string                    `json:"last_day"`
+	CostShare   float64                   `json:"cost_share"` // Percentage of the team's cost
+	Models      []output.ReportModelUsage `json:"models"`     // Most expensive first
+}
+
+// Day is the team's usage on one UTC day with each user's part of it
+type Day struct {
+	Date        string             `json:"date"`
+	TotalTokens int                `json:"total_tokens"`
+	CostUSD     float64            `json:"cost_usd"`
+	Users       map[string]float64 `json:"users"` // Cost per user
+}
+
+// Report is the combined usage of a team
+type Report struct {
+	From        string      `json:"from,omitempty"` // First day included, if limited
+	To          string      `json:"to,omitempty"`   // Last day included, if limited
+	Users       []UserUsage `json:"users"`          // Most expensive first
+	Days        []Day       `json:"days"`
+	TotalTokens int         `json:"total_tokens"`
+	CostUSD     float64     `json:"cost_usd"`
+	Entries     int         `json:"entries"`
+}
+
+// Aggregate combines snapshots into a team report covering the UTC days from..to, where an
+// empty bound is open. When a user's machine was exported more than once only the newest
+// snapshot of it counts, so re-exporting replaces earlier numbers instead of adding to them.
+func Aggregate(snapshots []Snapshot, from, to string) Report {
+	report := Report{From: from, To: to}
+	users := make(map[string]*UserUsage)
+	userModels := make(map[string]map[string]*output.ReportModelUsage)
+	userDays := make(map[string]map[string]bool)
+	days := make(map[string]*Day)
+
+	for _, snapshot := range latestSnapshots(snapshots) {
+		user, ok := users[snapshot.User]
+		if !ok {
+			user = &UserUsage{User: snapshot.User}
+			users[snapshot.User] = user
+			userModels[snapshot.User] = make(map[string]*output.ReportModelUsage)
+			userDays[snapshot.User] = make(map[string]bool)
+		}
+		if snapshot.Host != "" {
+			user.Hosts = append(user.Hosts, snapshot.Host)
+		}
+
+		for _, row := range snapshot.Days {
+			if (from != "" && row.Key < from) || (to != "" && row.Key > to) {
+				continue
+			}
+			addTokenCounts(&user.TokenCounts, row.TokenCounts)
+			user.TotalTokens += row.TotalTokens
+			user.CostUSD += row.CostUSD
+			user.Entries += row.Entries
+			userDays[snapshot.User][row.Key] = true
+
+			for _, usage := range row.Models {
+				model, ok := userModels[snapshot.User][usage.Model]
+				if !ok {
+					model = &output.ReportModelUsage{Model: usage.Model}
+					userModels[snapshot.User][usage.Model] = model
+				}
+				addTokenCounts(&model.TokenCounts, usage.TokenCounts)
+				model.TotalTokens += usage.TotalTokens
+				model.CostUSD += usage.CostUSD
+			}
+
+			day, ok := days[row.Key]
+			if !ok {
+				day = &Day{Date: row.Key, Users: make(map[string]float64)}
+				days[row.Key] = day
+			}
+			day.TotalTokens += row.TotalTokens
+			day.CostUSD += row.CostUSD
+			day.Users[snapshot.User] += row.CostUSD
+
+			report.TotalTokens += row.TotalTokens
+			report.CostUSD += row.CostUSD
+			report.Entries += row.Entries
+		}
+	}
+
+	for name, user := range users {
+		activeDays := make([]string, 0, len(userDays[name]))
+		for date := range userDays[name] {
+			activeDays = append(activeDays, date)
+		}
+		sort.Strings(activeDays)
+		user.ActiveDays = len(activeDays)
+		if len(activeDays) > 0 {
+			user.FirstDay, user.LastDay = activeDays[0], activeDays[len(activeDays)-1]
+		}
+		if report.CostUSD > 0 {
+			user.CostShare = user.CostUSD / report.CostUSD * 100
+		}
+
+		for _, model := range userModels[name] {
+			user.Models = append(user.Models, *model)
+		}
+		sort.Slice(user.Models, func(i, j int) bool {
+			if user.Models[i].CostUSD != user.Models[j].CostUSD {
+				return user.Models[i].CostUSD > user.Models[j].CostUSD
+			}
+			return user.Models[i].Model < user.Models[j].Model
+		})
+		sort.Strings(user.Hosts)
+		report.Users = append(report.Users, *user)
+	}
+	sort.Slice(report.Users, func(i, j int) bool {
+		if report.Users[i].CostUSD != report.Users[j].CostUSD {
+			return report.Users[i].CostUSD > report.Users[j].CostUSD
+		}
+		return report.Users[i].User < report.Users[j].User
+	})
+
+	report.Days = make([]Day, 0, len(days))
+	for _, day := range days {
+		report.Days = append(report.Days, *day)
+	}
+	sort.Slice(report.Days, func(i, j int) bool { return report.Days[i].Date < report.Days[j].Date })
+	return report
+}
+
+// latestSnapshots keeps the newest snapshot of every user and host. Snapshots without a
+// host, such as plain entry exports, are all kept.
+func latestSnapshots(snapshots []Snapshot) []Snapshot {
+	type machine struct{ user, host string }
+	latest := make(map[machine]int)
+	var result []Snapshot
+
+	for _, snapshot := range snapshots {
+		if snapshot.Host == "" {
+			result = append(result, snapshot)
+			continue
+		}
+		key := machine{snapshot.User, snapshot.Host}
+		if i, ok := latest[key]; ok {
+			if snapshot.ExportedAt.After(result[i].ExportedAt) {
+				result[i] = snapshot
+			}
+			continue
+		}
+		latest[key] = len(result)
+		result = append(result, snapshot)
+	}
+	return result
+}
+
+func addTokenCounts(total *models.TokenCounts, counts models.TokenCounts) {
+	total.InputTokens += counts.InputTokens
+	total.OutputTokens += counts.OutputTokens
+	total.CacheCreationTokens += counts.CacheCreationTokens
+	total.CacheReadTokens += counts.CacheReadTokens
+	total.ThinkingTokens += counts.ThinkingTokens
+}
@@ -0,0 +1,102 @@
+package team
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultRedisKeyPrefix prefixes the keys of snapshots stored in redis
+const DefaultRedisKeyPrefix = "claudecat:team:"
+
+// redisTimeout bounds each redis operation
+const redisTimeout = 5 * time.Second
+
+// RedisStore shares snapshots through a redis server, one key per user and host
+type RedisStore struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisStore connects to the redis server at url. An empty prefix uses DefaultRedisKeyPrefix.
+func NewRedisStore(url, prefix string) (*RedisStore, error) {
+	if url == "" {
+		return nil, fmt.Errorf("sharing snapshots requires a redis URL (cache.redis_url)")
+	}
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redis URL: %w", err)
+	}
+	if prefix == "" {
+		prefix = DefaultRedisKeyPrefix
+	}
+
+	client := redis.NewClient(opts)
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to connect to redis at %s: %w", opts.Addr, err)
+	}
+	return &RedisStore{client: client, prefix: prefix}, nil
+}
+
+// Push stores snapshot, replacing the previous snapshot of its user and host
+func (s *RedisStore) Push(snapshot Snapshot) error {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+	if err := s.client.Set(ctx, s.key(snapshot), data, 0).Err(); err != nil {
+		return fmt.Errorf("failed to store snapshot: %w", err)
+	}
+	return nil
+}
+
+// Snapshots returns every stored snapshot. Keys that can't be decoded are reported in the
+// returned errors and skipped.
+func (s *RedisStore) Snapshots() ([]Snapshot, []error, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	var snapshots []Snapshot
+	var problems []error
+	iter := s.client.Scan(ctx, 0, s.prefix+"*", 500).Iterator()
+	for iter.Next(ctx) {
+		key := iter.Val()
+		data, err := s.client.Get(ctx, key).Bytes()
+		if errors.Is(err, redis.Nil) {
+			continue // Removed while scanning
+		}
+		if err != nil {
+			problems = append(problems, fmt.Errorf("%s: %w", key, err))
+			continue
+		}
+		snapshot, err := DecodeSnapshot(data, "")
+		if err != nil {
+			problems = append(problems, fmt.Errorf("%s: %w", key, err))
+			continue
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	if err := iter.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to list snapshots: %w", err)
+	}
+	return snapshots, problems, nil
+}
+
+// Close closes the redis connection pool
+func (s *RedisStore) Close() error {
+	return s.client.Close()
+}
+
+func (s *RedisStore) key(snapshot Snapshot) string {
+	return s.prefix + snapshot.User + "@" + snapshot.Host
+}
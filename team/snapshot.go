@@ -0,0 +1,125 @@
+// Package team combines usage snapshots exported on several machines into team-level
+// reports with per-user breakdowns.
+//
+// Each user runs `claudecat team snapshot` to export a snapshot of their daily usage, either
+// to a file in a shared directory or to a shared redis server, and `claudecat team report`
+// aggregates all snapshots found there.
+package team
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/penwyp/claudecat/models"
+	"github.com/penwyp/claudecat/output"
+)
+
+// SnapshotVersion is the version of the snapshot format written by NewSnapshot
+const SnapshotVersion = 1
+
+// dateLayout is the layout of snapshot days, which are calendar days in UTC so snapshots
+// from machines in different time zones line up
+const dateLayout = "2006-01-02"
+
+// Snapshot is the daily usage of one user on one machine
+type Snapshot struct {
+	Version    int                `json:"version"`
+	User       string             `json:"user"`
+	Host       string             `json:"host"`
+	ExportedAt time.Time          `json:"exported_at"`
+	Days       []output.ReportRow `json:"days"` // Keyed by UTC date
+}
+
+// NewSnapshot aggregates entries into a snapshot of user's usage on host
+func NewSnapshot(user, host string, entries []models.UsageEntry, now time.Time) Snapshot {
+	days := output.GroupUsage(entries, func(entry models.UsageEntry) string {
+		return entry.Timestamp.UTC().Format(dateLayout)
+	})
+	for i := range days {
+		days[i].Project = "" // Days span projects; only the last one would be reported
+	}
+	return Snapshot{
+		Version:    SnapshotVersion,
+		User:       user,
+		Host:       host,
+		ExportedAt: now.UTC(),
+		Days:       days,
+	}
+}
+
+// DecodeSnapshot decodes a snapshot. A JSON array of usage entries, as written by
+// `claudecat export --format json`, is accepted too and attributed to defaultUser.
+func DecodeSnapshot(data []byte, defaultUser string) (Snapshot, error) {
+	trimmed := strings.TrimSpace(string(data))
+	if strings.HasPrefix(trimmed, "[") {
+		var entries []models.UsageEntry
+		if err := json.Unmarshal(data, &entries); err != nil {
+			return Snapshot{}, fmt.Errorf("failed to decode usage entries: %w", err)
+		}
+		return NewSnapshot(defaultUser, "", entries, time.Time{}), nil
+	}
+
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return Snapshot{}, fmt.Errorf("failed to decode snapshot: %w", err)
+	}
+	if snapshot.Version > SnapshotVersion {
+		return Snapshot{}, fmt.Errorf("unsupported snapshot version %d", snapshot.Version)
+	}
+	if snapshot.User == "" {
+		snapshot.User = defaultUser
+	}
+	return snapshot, nil
+}
+
+// LoadDir reads the snapshots in the *.json files of dir. Files that aren't snapshots are
+// reported in the returned errors and skipped.
+func LoadDir(dir string) ([]Snapshot, []error, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return nil, nil, fmt.Errorf("failed to read snapshot directory: %w", err)
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list snapshots: %w", err)
+	}
+	sort.Strings(paths)
+
+	var snapshots []Snapshot
+	var problems []error
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			problems = append(problems, fmt.Errorf("%s: %w", path, err))
+			continue
+		}
+		snapshot, err := DecodeSnapshot(data, strings.TrimSuffix(filepath.Base(path), ".json"))
+		if err != nil {
+			problems = append(problems, fmt.Errorf("%s: %w", path, err))
+			continue
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots, problems, nil
+}
+
+// WriteFile writes snapshot to path as indented JSON
+func WriteFile(path string, snapshot Snapshot) error {
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create directory: %w", err)
+		}
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package team
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/penwyp/claudecat/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func teamEntries(day time.Time, cost float64) []models.UsageEntry {
+	return []models.UsageEntry{
+		{Timestamp: day.Add(10 * time.Hour), Model: "claude-sonnet-4-20250514", InputTokens: 800, OutputTokens: 200, TotalTokens: 1000, CostUSD: cost},
+		{Timestamp: day.Add(34 * time.Hour), Model: "claude-opus-4-20250514", InputTokens: 400, OutputTokens: 100, TotalTokens: 500, CostUSD: cost * 2},
+	}
+}
+
+func TestNewSnapshot(t *testing.T) {
+	day := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	snapshot := NewSnapshot("alice", "laptop", teamEntries(day, 1), day.AddDate(0, 0, 3))
+
+	assert.Equal(t, SnapshotVersion, snapshot.Version)
+	require.Len(t, snapshot.Days, 2)
+	assert.Equal(t, "2025-06-01", snapshot.Days[0].Key)
+	assert.Equal(t, "2025-06-02", snapshot.Days[1].Key)
+	assert.Equal(t, 500, snapshot.Days[1].TotalTokens)
+}
+
+func TestLoadDir(t *testing.T) {
+	dir := t.TempDir()
+	day := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	require.NoError(t, WriteFile(filepath.Join(dir, "alice@laptop.json"), NewSnapshot("alice", "laptop", teamEntries(day, 1), day)))
+	export, err := json.Marshal(teamEntries(day, 2))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "carol.json"), export, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.json"), []byte("not json"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "readme.txt"), []byte("ignored"), 0644))
+
+	snapshots, problems, err := LoadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, snapshots, 2)
+	assert.Equal(t, "alice", snapshots[0].User)
+	assert.Equal(t, "carol", snapshots[1].User, "plain exports are attributed to their file name")
+	assert.Equal(t, 1500, snapshots[1].Days[0].TotalTokens+snapshots[1].Days[1].TotalTokens)
+	require.Len(t, problems, 1)
+	assert.Contains(t, problems[0].Error(), "notes.json")
+
+	_, _, err = LoadDir(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+}
+
+func TestAggregate(t *testing.T) {
+	day := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	snapshots := []Snapshot{
+		NewSnapshot("alice", "laptop", teamEntries(day, 1), day),
+		NewSnapshot("alice", "desktop", teamEntries(day, 0.5), day),
+		NewSnapshot("bob", "laptop", teamEntries(day, 3), day),
+		// A newer export of bob's laptop replaces the first one
+		NewSnapshot("bob", "laptop", teamEntries(day, 4), day.Add(time.Hour)),
+	}
+
+	report := Aggregate(snapshots, "", "")
+	require.Len(t, report.Users, 2)
+
+	bob, alice := report.Users[0], report.Users[1]
+	assert.Equal(t, "bob", bob.User)
+	assert.InDelta(t, 12, bob.CostUSD, 1e-9)
+	assert.Equal(t, []string{"desktop", "laptop"}, alice.Hosts)
+	assert.InDelta(t, 4.5, alice.CostUSD, 1e-9)
+	assert.Equal(t, 3000, alice.TotalTokens)
+	assert.Equal(t, 2, alice.ActiveDays)
+	assert.Equal(t, "2025-06-01", alice.FirstDay)
+	assert.Equal(t, "2025-06-02", alice.LastDay)
+	assert.Equal(t, "claude-opus-4-20250514", alice.Models[0].Model)
+	assert.InDelta(t, 100*4.5/16.5, alice.CostShare, 1e-9)
+
+	assert.InDelta(t, 16.5, report.CostUSD, 1e-9)
+	require.Len(t, report.Days, 2)
+	assert.InDelta(t, 4, report.Days[0].Users["bob"], 1e-9)
+
+	limited := Aggregate(snapshots, "2025-06-02", "2025-06-02")
+	require.Len(t, limited.Days, 1)
+	assert.InDelta(t, 11, limited.CostUSD, 1e-9)
+	assert.Equal(t, 1, limited.Users[0].ActiveDays)
+}
+
+func TestRedisStore(t *testing.T) {
+	// Redis needs a running server, e.g. CLAUDECAT_TEST_REDIS_URL=redis://localhost:6379/15
+	url := os.Getenv("CLAUDECAT_TEST_REDIS_URL")
+	if url == "" {
+		t.Skip("CLAUDECAT_TEST_REDIS_URL not set")
+	}
+
+	store, err := NewRedisStore(url, "claudecat:test:team:")
+	require.NoError(t, err)
+	defer store.Close()
+
+	day := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, store.Push(NewSnapshot("alice", "laptop", teamEntries(day, 1), day)))
+	require.NoError(t, store.Push(NewSnapshot("alice", "laptop", teamEntries(day, 2), day.Add(time.Hour))))
+
+	snapshots, problems, err := store.Snapshots()
+	require.NoError(t, err)
+	assert.Empty(t, problems)
+	require.Len(t, snapshots, 1, "pushing again replaces the machine's snapshot")
+	assert.InDelta(t, 6, Aggregate(snapshots, "", "").CostUSD, 1e-9)
+}
@@ -0,0 +1,141 @@
+// Package telemetry exports OpenTelemetry traces and metrics over OTLP/HTTP.
+//
+// Instrumented packages create their tracers and instruments from the global providers,
+// which are no-ops until Setup installs exporting ones.
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScopePrefix is the instrumentation scope prefix of claudecat's packages
+const ScopePrefix = "github.com/penwyp/claudecat/"
+
+// Options configures the exporters
+type Options struct {
+	Endpoint       string            // OTLP/HTTP collector URL; empty disables telemetry
+	Headers        map[string]string // Extra headers sent with every export
+	ServiceName    string            // service.name resource attribute
+	ServiceVersion string            // service.version resource attribute
+	SampleRatio    float64           // Fraction of traces recorded, 0-1
+	MetricInterval time.Duration     // How often metrics are exported; 0 uses the SDK default
+}
+
+// ShutdownFunc flushes and stops the exporters
+type ShutdownFunc func(ctx context.Context) error
+
+// Setup installs global trace and meter providers exporting to opts.Endpoint. Without an
+// endpoint it does nothing and the returned ShutdownFunc is a no-op.
+func Setup(ctx context.Context, opts Options) (ShutdownFunc, error) {
+	if opts.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+	endpoint := strings.TrimSuffix(opts.Endpoint, "/")
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		attribute.String("service.name", opts.ServiceName),
+		attribute.String("service.version", opts.ServiceVersion),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create telemetry resource: %w", err)
+	}
+
+	traceExporter, err := otlptracehttp.New(ctx,
+		otlptracehttp.WithEndpointURL(endpoint+"/v1/traces"),
+		otlptracehttp.WithHeaders(opts.Headers),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace exporter: %w", err)
+	}
+
+	metricExporter, err := otlpmetrichttp.New(ctx,
+		otlpmetrichttp.WithEndpointURL(endpoint+"/v1/metrics"),
+		otlpmetrichttp.WithHeaders(opts.Headers),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create metric exporter: %w", err)
+	}
+
+	var readerOpts []sdkmetric.PeriodicReaderOption
+	if opts.MetricInterval > 0 {
+		readerOpts = append(readerOpts, sdkmetric.WithInterval(opts.MetricInterval))
+	}
+
+	tracerProvider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(traceExporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SampleRatio))),
+	)
+	meterProvider := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(metricExporter, readerOpts...)),
+		sdkmetric.WithResource(res),
+	)
+
+	otel.SetTracerProvider(tracerProvider)
+	otel.SetMeterProvider(meterProvider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	return func(ctx context.Context) error {
+		return errors.Join(tracerProvider.Shutdown(ctx), meterProvider.Shutdown(ctx))
+	}, nil
+}
+
+// Tracer returns the tracer of a claudecat package, e.g. Tracer("fileio")
+func Tracer(pkg string) trace.Tracer {
+	return otel.Tracer(ScopePrefix + pkg)
+}
+
+// Float64Histogram creates a histogram for a claudecat package on the global meter
+// provider. Creation only fails for invalid names, in which case a no-op is returned.
+func Float64Histogram(pkg, name, description, unit string) metric.Float64Histogram {
+	histogram, err := otel.Meter(ScopePrefix+pkg).Float64Histogram(name,
+		metric.WithDescription(description), metric.WithUnit(unit))
+	if err != nil {
+		otel.Handle(err)
+		return noop.Float64Histogram{}
+	}
+	return histogram
+}
+
+// Int64Counter creates a counter for a claudecat package on the global meter provider.
+// Creation only fails for invalid names, in which case a no-op is returned.
+func Int64Counter(pkg, name, description, unit string) metric.Int64Counter {
+	counter, err := otel.Meter(ScopePrefix+pkg).Int64Counter(name,
+		metric.WithDescription(description), metric.WithUnit(unit))
+	if err != nil {
+		otel.Handle(err)
+		return noop.Int64Counter{}
+	}
+	return counter
+}
+
+// End ends span, marking it failed when err is set
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Since returns the seconds elapsed since start, the unit of the duration histograms
+func Since(start time.Time) float64 {
+	return time.Since(start).Seconds()
+}
@@ -0,0 +1,82 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestSetup_WithoutEndpoint(t *testing.T) {
+	before := otel.GetTracerProvider()
+
+	shutdown, err := Setup(context.Background(), Options{})
+	require.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+	assert.Equal(t, before, otel.GetTracerProvider(), "global provider must stay untouched")
+}
+
+func TestSetup_WithEndpoint(t *testing.T) {
+	beforeTracer, beforeMeter := otel.GetTracerProvider(), otel.GetMeterProvider()
+	t.Cleanup(func() {
+		otel.SetTracerProvider(beforeTracer)
+		otel.SetMeterProvider(beforeMeter)
+	})
+
+	var mu sync.Mutex
+	paths := map[string]string{}
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths[r.URL.Path] = r.Header.Get("X-Token")
+		mu.Unlock()
+	}))
+	defer collector.Close()
+
+	shutdown, err := Setup(context.Background(), Options{
+		Endpoint:       collector.URL + "/",
+		Headers:        map[string]string{"X-Token": "secret"},
+		ServiceName:    "claudecat",
+		SampleRatio:    1,
+		MetricInterval: time.Hour,
+	})
+	require.NoError(t, err)
+
+	_, span := Tracer("test").Start(context.Background(), "span")
+	span.End()
+	Int64Counter("test", "claudecat.test.count", "test counter", "{call}").Add(context.Background(), 1)
+
+	// Shutting down flushes both signals to the collector
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, shutdown(ctx))
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, map[string]string{"/v1/traces": "secret", "/v1/metrics": "secret"}, paths)
+}
+
+func TestEnd(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+
+	_, ok := tracer.Start(context.Background(), "ok")
+	End(ok, nil)
+	_, failed := tracer.Start(context.Background(), "failed")
+	End(failed, errors.New("boom"))
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+	assert.Equal(t, "boom", spans[1].Status().Description)
+}
@@ -4,8 +4,8 @@
 echo "Testing claudecat run command..."
 
 # Use test data path
-export CLAWCAT_DEBUG=true
-export CLAWCAT_LOG_LEVEL=debug
+export CLAUDECAT_DEBUG_ENABLED=true
+export CLAUDECAT_LOG_LEVEL=debug
 
 # Run claudecat with the test data path
 ./claudecat run --paths /Users/penwyp/Dat/worktree/claude_data_snapshot/projects --debug
\ No newline at end of file
@@ -0,0 +1,107 @@
+// Package timeutil computes calendar boundaries and reset times in a configured timezone.
+//
+// Boundaries are computed on the wall clock with time.Date, never by adding fixed durations,
+// so they stay aligned to local midnight across DST transitions. Weeks start on Monday.
+package timeutil
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DateLayout is the layout of day keys such as "2025-06-01"
+const DateLayout = "2006-01-02"
+
+// LoadLocation resolves a configured timezone name. Empty, "Local" and "auto" mean the
+// system zone; invalid names return the system zone together with an error.
+func LoadLocation(name string) (*time.Location, error) {
+	if name == "" || name == "Local" || strings.EqualFold(name, "auto") {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.Local, fmt.Errorf("invalid timezone %q: %w", name, err)
+	}
+	return loc, nil
+}
+
+// StartOfDay returns midnight of the day containing t in loc
+func StartOfDay(t time.Time, loc *time.Location) time.Time {
+	local := t.In(orLocal(loc))
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, local.Location())
+}
+
+// StartOfWeek returns midnight of the Monday that starts the week containing t in loc
+func StartOfWeek(t time.Time, loc *time.Location) time.Time {
+	local := t.In(orLocal(loc))
+	offset := (int(local.Weekday()) + 6) % 7
+	return time.Date(local.Year(), local.Month(), local.Day()-offset, 0, 0, 0, 0, local.Location())
+}
+
+// StartOfMonth returns midnight of the first day of the month containing t in loc
+func StartOfMonth(t time.Time, loc *time.Location) time.Time {
+	local := t.In(orLocal(loc))
+	return time.Date(local.Year(), local.Month(), 1, 0, 0, 0, 0, local.Location())
+}
+
+// DayBounds returns the start and exclusive end of the day containing t in loc.
+// The day is 23 or 25 hours long when it contains a DST transition.
+func DayBounds(t time.Time, loc *time.Location) (time.Time, time.Time) {
+	start := StartOfDay(t, loc)
+	return start, start.AddDate(0, 0, 1)
+}
+
+// WeekBounds returns the start and exclusive end of the week containing t in loc
+func WeekBounds(t time.Time, loc *time.Location) (time.Time, time.Time) {
+	start := StartOfWeek(t, loc)
+	return start, start.AddDate(0, 0, 7)
+}
+
+// MonthBounds returns the start and exclusive end of the month containing t in loc
+func MonthBounds(t time.Time, loc *time.Location) (time.Time, time.Time) {
+	start := StartOfMonth(t, loc)
+	return start, start.AddDate(0, 1, 0)
+}
+
+// BillingCycleBounds returns the start and exclusive end of the billing cycle containing t in
+// loc, for a subscription renewing on the given day of each month. Cycles start at midnight of
+// that day, or of the last day of months too short to have it. Days of 1 or less give
+// calendar months.
+func BillingCycleBounds(t time.Time, loc *time.Location, day int) (time.Time, time.Time) {
+	if day <= 1 {
+		return MonthBounds(t, loc)
+	}
+	local := t.In(orLocal(loc))
+	start := cycleStart(local.Year(), local.Month(), day, local.Location())
+	if local.Before(start) {
+		start = cycleStart(local.Year(), local.Month()-1, day, local.Location())
+	}
+	return start, cycleStart(start.Year(), start.Month()+1, day, local.Location())
+}
+
+// cycleStart returns midnight of the given day of a month, clamped to the month's last day.
+// Months outside 1-12 roll over into the neighbouring years.
+func cycleStart(year int, month time.Month, day int, loc *time.Location) time.Time {
+	first := time.Date(year, month, 1, 0, 0, 0, 0, loc)
+	last := first.AddDate(0, 1, -1).Day()
+	return time.Date(first.Year(), first.Month(), min(day, last), 0, 0, 0, 0, loc)
+}
+
+// DateKey returns the date of t in loc, formatted with DateLayout
+func DateKey(t time.Time, loc *time.Location) string {
+	return t.In(orLocal(loc)).Format(DateLayout)
+}
+
+// ResetTime returns when a session window that started at sessionStart ends and the plan
+// limit resets, expressed in loc so it formats as the configured wall clock
+func ResetTime(sessionStart time.Time, window time.Duration, loc *time.Location) time.Time {
+	return sessionStart.Add(window).In(orLocal(loc))
+}
+
+func orLocal(loc *time.Location) *time.Location {
+	if loc == nil {
+		return time.Local
+	}
+	return loc
+}
@@ -0,0 +1,106 @@
+package timeutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadLocation(t *testing.T) {
+	for _, name := range []string{"", "Local", "auto"} {
+		loc, err := LoadLocation(name)
+		require.NoError(t, err)
+		assert.Equal(t, time.Local, loc)
+	}
+
+	loc, err := LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+	assert.Equal(t, "Asia/Tokyo", loc.String())
+
+	loc, err = LoadLocation("Mars/Olympus")
+	assert.Error(t, err)
+	assert.Equal(t, time.Local, loc)
+}
+
+func TestBoundsInConfiguredZone(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+
+	// Sunday 2025-06-01 20:00 UTC is already Monday morning in Tokyo
+	ts := time.Date(2025, 6, 1, 20, 0, 0, 0, time.UTC)
+
+	start, end := DayBounds(ts, tokyo)
+	assert.Equal(t, time.Date(2025, 6, 2, 0, 0, 0, 0, tokyo), start)
+	assert.Equal(t, time.Date(2025, 6, 3, 0, 0, 0, 0, tokyo), end)
+	assert.Equal(t, "2025-06-02", DateKey(ts, tokyo))
+	assert.Equal(t, "2025-06-01", DateKey(ts, time.UTC))
+
+	start, end = WeekBounds(ts, tokyo)
+	assert.Equal(t, time.Date(2025, 6, 2, 0, 0, 0, 0, tokyo), start)
+	assert.Equal(t, time.Date(2025, 6, 9, 0, 0, 0, 0, tokyo), end)
+	assert.Equal(t, time.Date(2025, 5, 26, 0, 0, 0, 0, time.UTC), StartOfWeek(ts, time.UTC))
+
+	start, end = MonthBounds(time.Date(2025, 5, 31, 16, 0, 0, 0, time.UTC), tokyo)
+	assert.Equal(t, time.Date(2025, 6, 1, 0, 0, 0, 0, tokyo), start)
+	assert.Equal(t, time.Date(2025, 7, 1, 0, 0, 0, 0, tokyo), end)
+}
+
+func TestDayBoundsAcrossDST(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	// Clocks spring forward on 2025-03-09, so the day is 23 hours long
+	start, end := DayBounds(time.Date(2025, 3, 9, 12, 0, 0, 0, newYork), newYork)
+	assert.Equal(t, 23*time.Hour, end.Sub(start))
+	assert.Equal(t, 0, end.In(newYork).Hour())
+
+	// Clocks fall back on 2025-11-02, so the day is 25 hours long
+	start, end = DayBounds(time.Date(2025, 11, 2, 12, 0, 0, 0, newYork), newYork)
+	assert.Equal(t, 25*time.Hour, end.Sub(start))
+}
+
+func TestBillingCycleBounds(t *testing.T) {
+	day := func(year int, month time.Month, d int) time.Time {
+		return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name       string
+		t          time.Time
+		anchor     int
+		start, end time.Time
+	}{
+		{"calendar months", day(2025, 3, 10), 1, day(2025, 3, 1), day(2025, 4, 1)},
+		{"on the anchor", day(2025, 3, 17), 17, day(2025, 3, 17), day(2025, 4, 17)},
+		{"before the anchor", day(2025, 3, 16).Add(23 * time.Hour), 17, day(2025, 2, 17), day(2025, 3, 17)},
+		{"across the year", day(2025, 1, 5), 17, day(2024, 12, 17), day(2025, 1, 17)},
+		{"short months", day(2025, 2, 28), 31, day(2025, 2, 28), day(2025, 3, 31)},
+		{"after a short month", day(2025, 3, 30), 31, day(2025, 2, 28), day(2025, 3, 31)},
+		{"leap year", day(2024, 2, 29), 30, day(2024, 2, 29), day(2024, 3, 30)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := BillingCycleBounds(tt.t, time.UTC, tt.anchor)
+			assert.Equal(t, tt.start, start)
+			assert.Equal(t, tt.end, end)
+		})
+	}
+
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+	// 20:00 UTC on the 16th is already the 17th in Tokyo
+	start, _ := BillingCycleBounds(time.Date(2025, 3, 16, 20, 0, 0, 0, time.UTC), tokyo, 17)
+	assert.Equal(t, time.Date(2025, 3, 17, 0, 0, 0, 0, tokyo), start)
+}
+
+func TestResetTime(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+
+	start := time.Date(2025, 6, 1, 22, 0, 0, 0, time.UTC)
+	reset := ResetTime(start, 5*time.Hour, tokyo)
+	assert.True(t, reset.Equal(start.Add(5*time.Hour)))
+	assert.Equal(t, "12:00", reset.Format("15:04"))
+	assert.Equal(t, tokyo, reset.Location())
+}